@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+var benchCompareRealPath string
+
+// benchMetric is one comparable column of a real-benchmark CSV: a column name
+// (the MetricsOutput JSON name, as in `blis diff`) and the simulated value.
+type benchMetric struct {
+	Name string
+	get  func(m sim.MetricsOutput) float64
+}
+
+// benchMetrics lists the columns bench-compare understands, in report order.
+var benchMetrics = []benchMetric{
+	{"ttft_p50_ms", func(m sim.MetricsOutput) float64 { return m.TTFTP50Ms }},
+	{"ttft_p99_ms", func(m sim.MetricsOutput) float64 { return m.TTFTP99Ms }},
+	{"e2e_p50_ms", func(m sim.MetricsOutput) float64 { return m.E2EP50Ms }},
+	{"e2e_p99_ms", func(m sim.MetricsOutput) float64 { return m.E2EP99Ms }},
+	{"ttft_mean_ms", func(m sim.MetricsOutput) float64 { return m.TTFTMeanMs }},
+	{"e2e_mean_ms", func(m sim.MetricsOutput) float64 { return m.E2EMeanMs }},
+	{"itl_mean_ms", func(m sim.MetricsOutput) float64 { return m.ITLMeanMs }},
+	{"itl_p99_ms", func(m sim.MetricsOutput) float64 { return m.ITLP99Ms }},
+	{"responses_per_sec", func(m sim.MetricsOutput) float64 { return m.ResponsesPerSec }},
+	{"tokens_per_sec", func(m sim.MetricsOutput) float64 { return m.TokensPerSec }},
+}
+
+// benchRow is one measured load point of a real benchmark: the request rate
+// and the measured value of each metric column present in the CSV.
+type benchRow struct {
+	Rate float64
+	Real map[string]float64
+}
+
+// benchMetricError is the relative error of one simulated metric at one rate.
+type benchMetricError struct {
+	Rate   float64
+	Name   string
+	Real   float64
+	Sim    float64
+	RelErr float64 // |sim - real| / real
+}
+
+// benchComparison is the full sim-vs-real report. Fidelity is
+// max(0, 1 - MeanRelErr): 1 is a perfect match, 0 means the simulator is off
+// by 100% or more on average.
+type benchComparison struct {
+	Errors     []benchMetricError
+	MeanRelErr float64
+	MaxRelErr  float64
+	Fidelity   float64
+}
+
+// loadBenchCSV reads a real-benchmark CSV. The header must contain a "rate"
+// column; every other column must be a benchMetrics name. Empty cells mean
+// "not measured" and are skipped. Rates and measured values must be finite
+// and > 0 so relative error is defined.
+func loadBenchCSV(path string) ([]benchRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s: need a header and at least one data row", path)
+	}
+	known := make(map[string]bool, len(benchMetrics))
+	for _, m := range benchMetrics {
+		known[m.Name] = true
+	}
+	header := records[0]
+	rateCol := -1
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		header[i] = name
+		switch {
+		case name == "rate":
+			rateCol = i
+		case !known[name]:
+			return nil, fmt.Errorf("%s: unknown column %q", path, name)
+		}
+	}
+	if rateCol < 0 {
+		return nil, fmt.Errorf("%s: missing \"rate\" column", path)
+	}
+
+	rows := make([]benchRow, 0, len(records)-1)
+	for r, rec := range records[1:] {
+		row := benchRow{Real: make(map[string]float64, len(rec)-1)}
+		for i, cell := range rec {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				if i == rateCol {
+					return nil, fmt.Errorf("%s row %d: empty rate", path, r+2)
+				}
+				continue
+			}
+			v, err := strconv.ParseFloat(cell, 64)
+			if err != nil || v <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("%s row %d: %s must be a finite value > 0, got %q", path, r+2, header[i], cell)
+			}
+			if i == rateCol {
+				row.Rate = v
+			} else {
+				row.Real[header[i]] = v
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// runBenchCompare simulates each row's rate with runAt (in CSV order) and
+// compares the result against the row's measurements.
+func runBenchCompare(rows []benchRow, runAt func(rate float64) sim.MetricsOutput) benchComparison {
+	simOut := make([]sim.MetricsOutput, len(rows))
+	for i, row := range rows {
+		simOut[i] = runAt(row.Rate)
+	}
+	return compareBench(rows, simOut)
+}
+
+// compareBench computes the relative error of every measured metric, rows in
+// order and metrics in benchMetrics order, and the aggregate fidelity score.
+// simOut[i] is the simulation of rows[i].
+func compareBench(rows []benchRow, simOut []sim.MetricsOutput) benchComparison {
+	var c benchComparison
+	sum := 0.0
+	for i, row := range rows {
+		for _, m := range benchMetrics {
+			measured, ok := row.Real[m.Name]
+			if !ok {
+				continue
+			}
+			got := m.get(simOut[i])
+			e := benchMetricError{Rate: row.Rate, Name: m.Name, Real: measured, Sim: got, RelErr: math.Abs(got-measured) / measured}
+			c.Errors = append(c.Errors, e)
+			sum += e.RelErr
+			c.MaxRelErr = max(c.MaxRelErr, e.RelErr)
+		}
+	}
+	if len(c.Errors) > 0 {
+		c.MeanRelErr = sum / float64(len(c.Errors))
+		c.Fidelity = max(0, 1-c.MeanRelErr)
+	}
+	return c
+}
+
+// printBenchComparison writes the per-metric error table and the summary.
+func printBenchComparison(w io.Writer, c benchComparison) {
+	_, _ = fmt.Fprintln(w, "=== Bench Compare ===")
+	_, _ = fmt.Fprintf(w, "%10s %-18s %14s %14s %10s\n", "rate", "metric", "real", "sim", "rel_err%")
+	for _, e := range c.Errors {
+		_, _ = fmt.Fprintf(w, "%10.4g %-18s %14.4f %14.4f %9.2f%%\n", e.Rate, e.Name, e.Real, e.Sim, e.RelErr*100)
+	}
+	_, _ = fmt.Fprintf(w, "Mean relative error: %.2f%%\n", c.MeanRelErr*100)
+	_, _ = fmt.Fprintf(w, "Max relative error:  %.2f%%\n", c.MaxRelErr*100)
+	_, _ = fmt.Fprintf(w, "Fidelity score:      %.4f\n", c.Fidelity)
+}
+
+var benchCompareCmd = &cobra.Command{
+	Use:   "bench-compare",
+	Short: "Simulate each rate of a real benchmark CSV and report per-metric error",
+	Long: "Run the simulation once per row of --real-csv with --rate set to the row's rate and " +
+		"otherwise identical configuration, then print the relative error of each measured metric " +
+		"(TTFT/E2E P50/P99, throughput, ...) and an overall fidelity score (1 - mean relative error).",
+	Run: func(cmd *cobra.Command, args []string) {
+		if benchCompareRealPath == "" {
+			logrus.Fatalf("--real-csv is required")
+		}
+		if concurrency > 0 {
+			logrus.Fatalf("--concurrency cannot be used with bench-compare; each CSV row sets --rate")
+		}
+		rows, err := loadBenchCSV(benchCompareRealPath)
+		if err != nil {
+			logrus.Fatalf("Failed to load real benchmark: %v", err)
+		}
+		c := runBenchCompare(rows, func(r float64) sim.MetricsOutput {
+			_, _ = fmt.Fprintf(os.Stdout, "=== Bench Compare Run (rate=%g) ===\n", r)
+			return runSimulation(cmd, runConfig{seed: seed, seedExplicit: cmd.Flags().Changed("seed"), rate: r})
+		})
+		printBenchComparison(os.Stdout, c)
+	},
+}
+
+func init() {
+	registerSimConfigFlags(benchCompareCmd)
+	registerWorkloadGenFlags(benchCompareCmd)
+	benchCompareCmd.Flags().StringVar(&benchCompareRealPath, "real-csv", "", "CSV of real measurements: a rate column plus any of "+benchMetricNames())
+	rootCmd.AddCommand(benchCompareCmd)
+}
+
+// benchMetricNames returns the accepted metric column names, comma-separated.
+func benchMetricNames() string {
+	names := make([]string, len(benchMetrics))
+	for i, m := range benchMetrics {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
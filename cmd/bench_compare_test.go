@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+const benchCompareRealFixture = "testdata/bench_compare_real.csv"
+
+func TestLoadBenchCSV_Fixture(t *testing.T) {
+	rows, err := loadBenchCSV(benchCompareRealFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Rate != 2 || rows[1].Rate != 8 {
+		t.Fatalf("rows = %+v, want rates [2 8]", rows)
+	}
+	// An empty cell means "not measured", not zero.
+	if _, ok := rows[1].Real["e2e_p99_ms"]; ok {
+		t.Errorf("row 2 has e2e_p99_ms, want it skipped (empty cell)")
+	}
+	if got := len(rows[0].Real); got != 5 {
+		t.Errorf("row 1 has %d metrics, want 5", got)
+	}
+}
+
+func TestLoadBenchCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name, csv, wantErr string
+	}{
+		{"no rate column", "ttft_p99_ms\n10\n", "missing \"rate\""},
+		{"unknown column", "rate,p42\n1,10\n", "unknown column"},
+		{"zero measurement", "rate,ttft_p99_ms\n1,0\n", "must be a finite value > 0"},
+		{"NaN rate", "rate,ttft_p99_ms\nNaN,10\n", "must be a finite value > 0"},
+		{"header only", "rate,ttft_p99_ms\n", "at least one data row"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "real.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadBenchCSV(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCompareBench_RelativeErrorAndFidelity pins the error arithmetic: 10% and
+// 30% errors give mean 20%, max 30%, fidelity 0.8; unmeasured metrics are skipped.
+func TestCompareBench_RelativeErrorAndFidelity(t *testing.T) {
+	rows := []benchRow{{Rate: 1, Real: map[string]float64{"ttft_p99_ms": 100, "responses_per_sec": 10}}}
+	out := []sim.MetricsOutput{{TTFTP99Ms: 110, ResponsesPerSec: 7, E2EP99Ms: 999}}
+
+	c := compareBench(rows, out)
+
+	if len(c.Errors) != 2 {
+		t.Fatalf("got %d errors, want 2: %+v", len(c.Errors), c.Errors)
+	}
+	if c.Errors[0].Name != "ttft_p99_ms" || math.Abs(c.Errors[0].RelErr-0.1) > 1e-9 {
+		t.Errorf("errors[0] = %+v, want ttft_p99_ms rel_err 0.1", c.Errors[0])
+	}
+	if c.Errors[1].Name != "responses_per_sec" || math.Abs(c.Errors[1].RelErr-0.3) > 1e-9 {
+		t.Errorf("errors[1] = %+v, want responses_per_sec rel_err 0.3", c.Errors[1])
+	}
+	if math.Abs(c.MeanRelErr-0.2) > 1e-9 || math.Abs(c.MaxRelErr-0.3) > 1e-9 || math.Abs(c.Fidelity-0.8) > 1e-9 {
+		t.Errorf("mean=%v max=%v fidelity=%v, want 0.2 0.3 0.8", c.MeanRelErr, c.MaxRelErr, c.Fidelity)
+	}
+
+	// A simulator off by more than 100% on average scores 0, not negative.
+	if c := compareBench(rows, []sim.MetricsOutput{{TTFTP99Ms: 400, ResponsesPerSec: 40}}); c.Fidelity != 0 {
+		t.Errorf("Fidelity = %v for 300%% errors, want 0", c.Fidelity)
+	}
+}
+
+// TestRunBenchCompare_SimAgainstFixture runs the simulator at each fixture rate
+// through the single-run path and checks every measured metric gets an error
+// entry within the fixture's bounds (its values sit within ~20% of this
+// configuration's output).
+// NOTE: Do NOT use t.Parallel() — mutates package-level vars.
+func TestRunBenchCompare_SimAgainstFixture(t *testing.T) {
+	mcFolder, hwPath, defaultsPath := setupTrainedPhysicsTestFixturesWithDefaults(t)
+	orig := captureCmdLevelVars()
+	defer orig.restore()
+
+	testCmd := &cobra.Command{}
+	registerSimConfigFlags(testCmd)
+	registerWorkloadGenFlags(testCmd)
+	if err := testCmd.ParseFlags([]string{
+		"--model", "qwen/qwen3-14b",
+		"--latency-model", "trained-physics",
+		"--defaults-filepath", defaultsPath,
+		"--model-config-folder", mcFolder,
+		"--hardware-config", hwPath,
+		"--hardware", "H100",
+		"--tp", "1",
+		"--total-kv-blocks", "1000",
+		"--num-requests", "50",
+		"--seed", "7",
+	}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	rows, err := loadBenchCSV(benchCompareRealFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = devNull
+	c := runBenchCompare(rows, func(r float64) sim.MetricsOutput {
+		return runSimulation(testCmd, runConfig{seed: 7, seedExplicit: true, rate: r})
+	})
+	os.Stdout = oldStdout
+	_ = devNull.Close()
+
+	// THEN every non-empty fixture cell (5 + 4) has an error entry
+	if len(c.Errors) != 9 {
+		t.Fatalf("got %d errors, want 9: %+v", len(c.Errors), c.Errors)
+	}
+	for _, e := range c.Errors {
+		if e.Sim <= 0 {
+			t.Errorf("rate %v %s: sim = %v, want > 0", e.Rate, e.Name, e.Sim)
+		}
+		if want := math.Abs(e.Sim-e.Real) / e.Real; e.RelErr != want {
+			t.Errorf("rate %v %s: RelErr = %v, want %v", e.Rate, e.Name, e.RelErr, want)
+		}
+		if e.RelErr > 0.25 {
+			t.Errorf("rate %v %s: RelErr = %.3f (real %v, sim %v), want <= 0.25", e.Rate, e.Name, e.RelErr, e.Real, e.Sim)
+		}
+	}
+	if c.Fidelity < 0.8 || c.Fidelity > 1 {
+		t.Errorf("Fidelity = %v, want in [0.8, 1]", c.Fidelity)
+	}
+}
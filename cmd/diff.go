@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+var (
+	diffBaselinePath  string
+	diffCandidatePath string
+	diffThresholdPct  float64
+)
+
+// diffMetric is one row of the `blis diff` table: a MetricsOutput field and
+// the direction in which a change counts as a regression.
+type diffMetric struct {
+	Name           string // JSON field name in MetricsOutput
+	HigherIsBetter bool
+	get            func(m sim.MetricsOutput) float64
+}
+
+var diffMetrics = []diffMetric{
+	{"responses_per_sec", true, func(m sim.MetricsOutput) float64 { return m.ResponsesPerSec }},
+	{"tokens_per_sec", true, func(m sim.MetricsOutput) float64 { return m.TokensPerSec }},
+	{"ttft_mean_ms", false, func(m sim.MetricsOutput) float64 { return m.TTFTMeanMs }},
+	{"ttft_p90_ms", false, func(m sim.MetricsOutput) float64 { return m.TTFTP90Ms }},
+	{"ttft_p95_ms", false, func(m sim.MetricsOutput) float64 { return m.TTFTP95Ms }},
+	{"ttft_p99_ms", false, func(m sim.MetricsOutput) float64 { return m.TTFTP99Ms }},
+	{"e2e_mean_ms", false, func(m sim.MetricsOutput) float64 { return m.E2EMeanMs }},
+	{"e2e_p90_ms", false, func(m sim.MetricsOutput) float64 { return m.E2EP90Ms }},
+	{"e2e_p95_ms", false, func(m sim.MetricsOutput) float64 { return m.E2EP95Ms }},
+	{"e2e_p99_ms", false, func(m sim.MetricsOutput) float64 { return m.E2EP99Ms }},
+	{"itl_mean_ms", false, func(m sim.MetricsOutput) float64 { return m.ITLMeanMs }},
+	{"itl_p90_ms", false, func(m sim.MetricsOutput) float64 { return m.ITLP90Ms }},
+	{"itl_p95_ms", false, func(m sim.MetricsOutput) float64 { return m.ITLP95Ms }},
+	{"itl_p99_ms", false, func(m sim.MetricsOutput) float64 { return m.ITLP99Ms }},
+	{"cache_hit_rate", true, func(m sim.MetricsOutput) float64 { return m.CacheHitRate }},
+	{"preemption_count", false, func(m sim.MetricsOutput) float64 { return float64(m.PreemptionCount) }},
+}
+
+// metricDelta is the change in one metric from baseline to candidate.
+// DeltaPct is relative to the baseline; it is 0 when both values are 0 and
+// ±Inf when only the baseline is 0.
+type metricDelta struct {
+	Name       string
+	Baseline   float64
+	Candidate  float64
+	Delta      float64
+	DeltaPct   float64
+	Regression bool // change in the worse direction beyond the threshold
+}
+
+// computeMetricDeltas compares candidate against baseline for every diffMetric.
+// A metric regresses when it moves in its worse direction by more than
+// thresholdPct percent of the baseline value.
+func computeMetricDeltas(baseline, candidate sim.MetricsOutput, thresholdPct float64) []metricDelta {
+	deltas := make([]metricDelta, 0, len(diffMetrics))
+	for _, m := range diffMetrics {
+		b, c := m.get(baseline), m.get(candidate)
+		d := metricDelta{Name: m.Name, Baseline: b, Candidate: c, Delta: c - b}
+		switch {
+		case b != 0:
+			d.DeltaPct = d.Delta / math.Abs(b) * 100
+		case d.Delta != 0:
+			d.DeltaPct = math.Inf(int(math.Copysign(1, d.Delta)))
+		}
+		worse := d.DeltaPct
+		if m.HigherIsBetter {
+			worse = -worse
+		}
+		d.Regression = worse > thresholdPct
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// countRegressions returns the number of deltas flagged as regressions.
+func countRegressions(deltas []metricDelta) int {
+	n := 0
+	for _, d := range deltas {
+		if d.Regression {
+			n++
+		}
+	}
+	return n
+}
+
+// printMetricDeltas writes the delta table; regressions are marked in the last column.
+func printMetricDeltas(w io.Writer, deltas []metricDelta, thresholdPct float64) {
+	_, _ = fmt.Fprintf(w, "=== Metrics Diff (regression threshold %.2f%%) ===\n", thresholdPct)
+	_, _ = fmt.Fprintf(w, "%-18s %14s %14s %14s %10s\n", "metric", "baseline", "candidate", "delta", "delta%")
+	for _, d := range deltas {
+		flag := ""
+		if d.Regression {
+			flag = "  REGRESSION"
+		}
+		_, _ = fmt.Fprintf(w, "%-18s %14.4f %14.4f %+14.4f %+9.2f%%%s\n",
+			d.Name, d.Baseline, d.Candidate, d.Delta, d.DeltaPct, flag)
+	}
+}
+
+// loadMetricsOutput reads a MetricsOutput JSON file written by --metrics-path.
+func loadMetricsOutput(path string) (sim.MetricsOutput, error) {
+	var out sim.MetricsOutput
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return out, nil
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two MetricsOutput JSON files and flag regressions",
+	Long: "Print absolute and percent deltas of throughput, TTFT/E2E/ITL latencies, cache hit rate, " +
+		"and preemption count between two --metrics-path files. Exits non-zero when any metric " +
+		"regresses by more than --threshold percent, for CI gating.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffThresholdPct < 0 || math.IsNaN(diffThresholdPct) || math.IsInf(diffThresholdPct, 0) {
+			logrus.Fatalf("--threshold must be a finite value >= 0, got %v", diffThresholdPct)
+		}
+		baseline, err := loadMetricsOutput(diffBaselinePath)
+		if err != nil {
+			logrus.Fatalf("Failed to load baseline: %v", err)
+		}
+		candidate, err := loadMetricsOutput(diffCandidatePath)
+		if err != nil {
+			logrus.Fatalf("Failed to load candidate: %v", err)
+		}
+
+		deltas := computeMetricDeltas(baseline, candidate, diffThresholdPct)
+		printMetricDeltas(os.Stdout, deltas, diffThresholdPct)
+		if n := countRegressions(deltas); n > 0 {
+			logrus.Fatalf("%d metric(s) regressed by more than %.2f%%", n, diffThresholdPct)
+		}
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffBaselinePath, "baseline", "", "Path to the baseline MetricsOutput JSON file")
+	diffCmd.Flags().StringVar(&diffCandidatePath, "candidate", "", "Path to the candidate MetricsOutput JSON file")
+	diffCmd.Flags().Float64Var(&diffThresholdPct, "threshold", 5.0, "Regression threshold in percent of the baseline value")
+	_ = diffCmd.MarkFlagRequired("baseline")
+	_ = diffCmd.MarkFlagRequired("candidate")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+const (
+	diffBaselineFixture  = "testdata/diff_baseline.json"
+	diffCandidateFixture = "testdata/diff_candidate.json"
+)
+
+// TestComputeMetricDeltas_Fixtures verifies absolute and percent deltas and the
+// direction-aware regression flags for the two fixture files at a 5% threshold.
+func TestComputeMetricDeltas_Fixtures(t *testing.T) {
+	baseline, err := loadMetricsOutput(diffBaselineFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidate, err := loadMetricsOutput(diffCandidateFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deltas := computeMetricDeltas(baseline, candidate, 5)
+	if len(deltas) != len(diffMetrics) {
+		t.Fatalf("got %d deltas, want %d", len(deltas), len(diffMetrics))
+	}
+	byName := make(map[string]metricDelta, len(deltas))
+	for _, d := range deltas {
+		byName[d.Name] = d
+	}
+
+	tests := []struct {
+		name       string
+		delta      float64
+		pct        float64
+		regression bool
+	}{
+		{"responses_per_sec", 0.2, 2, false}, // higher is better: improvement
+		{"ttft_mean_ms", 2, 4, false},        // worse, but within threshold
+		{"ttft_p90_ms", 3, 3.75, false},
+		{"ttft_p95_ms", 9, 10, true},
+		{"ttft_p99_ms", 20, 20, true},
+		{"e2e_mean_ms", -20, -2.5, false},
+		{"itl_p99_ms", -1, -12.5, false}, // large improvement is not a regression
+		{"cache_hit_rate", -0.05, -10, true},
+		{"preemption_count", 2, 50, true},
+	}
+	for _, tc := range tests {
+		d, ok := byName[tc.name]
+		if !ok {
+			t.Errorf("%s: missing from deltas", tc.name)
+			continue
+		}
+		if math.Abs(d.Delta-tc.delta) > 1e-9 || math.Abs(d.DeltaPct-tc.pct) > 1e-9 {
+			t.Errorf("%s: delta=%v (%v%%), want %v (%v%%)", tc.name, d.Delta, d.DeltaPct, tc.delta, tc.pct)
+		}
+		if d.Regression != tc.regression {
+			t.Errorf("%s: Regression=%v, want %v", tc.name, d.Regression, tc.regression)
+		}
+	}
+	if got := countRegressions(deltas); got != 4 {
+		t.Errorf("countRegressions = %d, want 4", got)
+	}
+	// A looser threshold clears every regression except the preemption jump.
+	if got := countRegressions(computeMetricDeltas(baseline, candidate, 25)); got != 1 {
+		t.Errorf("countRegressions at 25%% = %d, want 1", got)
+	}
+}
+
+func TestComputeMetricDeltas_ZeroBaseline(t *testing.T) {
+	deltas := computeMetricDeltas(sim.MetricsOutput{}, sim.MetricsOutput{PreemptionCount: 3}, 5)
+	for _, d := range deltas {
+		switch d.Name {
+		case "preemption_count":
+			if !math.IsInf(d.DeltaPct, 1) || !d.Regression {
+				t.Errorf("preemption 0 -> 3: pct=%v regression=%v, want +Inf and true", d.DeltaPct, d.Regression)
+			}
+		default:
+			if d.DeltaPct != 0 || d.Regression {
+				t.Errorf("%s: pct=%v regression=%v, want 0 and false for 0 -> 0", d.Name, d.DeltaPct, d.Regression)
+			}
+		}
+	}
+}
+
+// TestDiffCmd_ExitCode drives `blis diff` in a subprocess and asserts the CI
+// gating contract: exit 1 with a regression summary when any metric regresses
+// beyond --threshold, exit 0 otherwise.
+func TestDiffCmd_ExitCode(t *testing.T) {
+	if os.Getenv("BLIS_TEST_SUBPROCESS") == "1" {
+		rootCmd.SetArgs(strings.Fields(os.Getenv("BLIS_DIFF_ARGS")))
+		_ = rootCmd.Execute()
+		os.Exit(0)
+	}
+
+	tests := []struct {
+		name     string
+		args     string
+		wantExit int
+		wantOut  string
+		notOut   string
+	}{
+		{"regression", "diff --baseline " + diffBaselineFixture + " --candidate " + diffCandidateFixture, 1, "4 metric(s) regressed", ""},
+		{"identical", "diff --baseline " + diffBaselineFixture + " --candidate " + diffBaselineFixture, 0, "ttft_p99_ms", "REGRESSION"},
+		{"loose threshold", "diff --baseline " + diffBaselineFixture + " --candidate " + diffCandidateFixture + " --threshold 60", 0, "preemption_count", "REGRESSION"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=TestDiffCmd_ExitCode")
+			cmd.Env = append(os.Environ(), "BLIS_TEST_SUBPROCESS=1", "BLIS_DIFF_ARGS="+tc.args)
+			out, err := cmd.CombinedOutput()
+			exit := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exit = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("subprocess failed to run: %v", err)
+			}
+			if exit != tc.wantExit {
+				t.Fatalf("exit code = %d, want %d; output:\n%s", exit, tc.wantExit, out)
+			}
+			if !strings.Contains(string(out), tc.wantOut) {
+				t.Errorf("output missing %q:\n%s", tc.wantOut, out)
+			}
+			if tc.notOut != "" && strings.Contains(string(out), tc.notOut) {
+				t.Errorf("output unexpectedly contains %q:\n%s", tc.notOut, out)
+			}
+		})
+	}
+}
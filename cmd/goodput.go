@@ -129,6 +129,7 @@ func mergeGoodputTargets(
 // emitGoodput populates MetricsOutput goodput fields from aggregated metrics,
 // per-class injection counts, and the resolved targets. No-op when targets is
 // empty (BC-3: byte-identical output when goodput is not configured).
+// Returns the computed goodput so callers can also surface it on RawMetrics.
 //
 // The per_class payload is built as map[string]map[string]any so it serializes
 // cleanly through MetricsOutput.PerClass (typed as interface{} to avoid
@@ -139,14 +140,15 @@ func emitGoodput(
 	injectedByClass map[string]int64,
 	runtimeSec float64,
 	targets map[string]workload.SLODimTargets,
-) {
+) cluster.Goodput {
 	if output == nil || aggregated == nil || len(targets) == 0 {
-		return
+		return cluster.Goodput{}
 	}
 	results := cluster.BuildLatencyResults(aggregated)
 	overall, perClass := cluster.SLOAttainmentMultiDim(results, injectedByClass, targets)
+	goodput := cluster.ComputeGoodput(results, targets, runtimeSec)
+	softDeadlines := cluster.ComputeSoftDeadlineReport(results, targets)
 
-	totalGood := 0
 	classKeys := make([]string, 0, len(perClass))
 	for k := range perClass {
 		classKeys = append(classKeys, k)
@@ -156,16 +158,11 @@ func emitGoodput(
 	per := make(map[string]map[string]any, len(perClass))
 	for _, cls := range classKeys {
 		sca := perClass[cls]
-		totalGood += sca.Good
 
 		entry := map[string]any{
 			"slo_attainment": safeRatio(int64(sca.Good), sca.Injected),
 			"count":          sca.Injected,
-		}
-		if runtimeSec > 0 {
-			entry["goodput_rps"] = float64(sca.Good) / runtimeSec
-		} else {
-			entry["goodput_rps"] = 0.0
+			"goodput_rps":    goodput.PerClassRPS[cls], // 0 when runtimeSec <= 0
 		}
 
 		// slo_attainment_by_dim: deterministic iteration via sorted keys.
@@ -193,15 +190,18 @@ func emitGoodput(
 		if e2eP99 > 0 {
 			entry["e2e_p99_ms"] = e2eP99
 		}
+		// soft_deadlines: present only when the class gates TTFT or E2E.
+		if sd, ok := softDeadlines[cls]; ok && (sd.TTFT != nil || sd.E2E != nil) {
+			entry["soft_deadlines"] = sd
+		}
 
 		per[cls] = entry
 	}
 
 	output.SLOAttainment = overall
 	output.PerClass = per
-	if runtimeSec > 0 {
-		output.GoodputRPS = float64(totalGood) / runtimeSec
-	}
+	output.GoodputRPS = goodput.RPS
+	return goodput
 }
 
 // perClassLatencyStats computes per-class TTFT P99, mean ITL, and E2E P99
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	sim "github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/cluster"
 	"github.com/inference-sim/inference-sim/sim/workload"
 )
 
@@ -184,6 +185,17 @@ func TestEmitGoodput_OneClassE2EOnly(t *testing.T) {
 	if v, hasE2E := byDim["e2e"]; !hasE2E || v != 0.5 {
 		t.Errorf("byDim e2e: got %v (present=%v), want 0.5", v, hasE2E)
 	}
+	sd, ok := def["soft_deadlines"].(cluster.SoftDeadlineClass)
+	if !ok {
+		t.Fatalf("soft_deadlines type: got %T, want cluster.SoftDeadlineClass", def["soft_deadlines"])
+	}
+	if sd.TTFT != nil || sd.E2E == nil {
+		t.Fatalf("soft_deadlines: got %+v, want E2E only", sd)
+	}
+	// E2E 1s and 10s against a 5s budget: half met, interpolated P50 = 5.5s misses.
+	if sd.E2E.Attainment != 0.5 || sd.E2E.P50Ms != 5500 || sd.E2E.P50Met {
+		t.Errorf("soft_deadlines e2e: got %+v, want attainment 0.5, P50 5500ms unmet", *sd.E2E)
+	}
 }
 
 // TestEmitObserveGoodput_OkErrorTimeoutDenominator verifies BC-2 on observe path:
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -113,6 +114,27 @@ func resolveHardwareConfig(explicitPath, defaultsFile string) (string, error) {
 	)
 }
 
+// loadHardwareCalib returns the calibration for gpu from the hardware config
+// at hwPath. When inventoryPath names a fleet inventory (--fleet-inventory),
+// its peak TFLOPs and bandwidth override the hardware config entry, which
+// then only needs to supply what the inventory leaves unset (e.g. MFUs); a
+// GPU absent from the inventory is an error rather than a silent fallback.
+func loadHardwareCalib(hwPath, inventoryPath, gpu string) (sim.HardwareCalib, error) {
+	if inventoryPath == "" {
+		return latency.GetHWConfig(hwPath, gpu)
+	}
+	inv, err := latency.LoadFleetInventory(inventoryPath)
+	if err != nil {
+		return sim.HardwareCalib{}, err
+	}
+	base, baseErr := latency.GetHWConfig(hwPath, gpu)
+	hc, err := inv.HardwareCalib(gpu, base)
+	if err != nil && baseErr != nil {
+		return sim.HardwareCalib{}, fmt.Errorf("%w (hardware config: %v)", err, baseErr)
+	}
+	return hc, err
+}
+
 // fetchHFConfigFunc is the function used to fetch HF configs. Package-level
 // variable allows tests to inject a mock without hitting real HuggingFace.
 // Second parameter is the target directory to write config.json into.
@@ -276,6 +298,32 @@ func applyWeightPrecisionFallback(mc *sim.ModelConfig, model string, hfRaw map[s
 	}
 }
 
+// kvCacheDtypeBytes maps --kv-cache-dtype values to bytes per KV element.
+// 0 (auto) keeps the model's compute dtype (BytesPerParam).
+var kvCacheDtypeBytes = map[string]float64{"auto": 0, "bf16": 2, "fp16": 2, "fp8": 1, "int8": 1}
+
+// validKVCacheDtypeNames returns sorted --kv-cache-dtype values.
+func validKVCacheDtypeNames() []string {
+	names := make([]string, 0, len(kvCacheDtypeBytes))
+	for name := range kvCacheDtypeBytes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyDtypeOverrides applies --compute-dtype and --kv-cache-dtype to mc.
+// Defaults leave mc unchanged: the compute dtype is inferred from weight
+// precision and the KV cache uses BytesPerParam.
+func applyDtypeOverrides(mc *sim.ModelConfig) {
+	if computeDtype != "" {
+		mc.ComputeDtype = computeDtype
+	}
+	if b := kvCacheDtypeBytes[kvCacheDtype]; b > 0 {
+		mc.KVBytesPerParam = b
+	}
+}
+
 // bundledModelConfigDir returns the expected path for bundled model configs.
 // Model names like "meta-llama/llama-3.1-8b-instruct" map to "<baseDir>/model_configs/llama-3.1-8b-instruct/".
 // When baseDir is empty, returns a relative path (resolved relative to CWD).
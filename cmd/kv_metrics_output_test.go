@@ -34,6 +34,41 @@ func TestPrintKVCacheMetrics_AllZero_NoOutput(t *testing.T) {
 	assert.Empty(t, buf.String())
 }
 
+func TestPrintCacheHitBreakdown_TwoTenants_PrintsSorted(t *testing.T) {
+	// GIVEN hit rates for two tenants and untagged traffic
+	var buf bytes.Buffer
+
+	// WHEN we print the breakdown
+	printCacheHitBreakdown(&buf, "Tenant", map[string]float64{"bob": 0, "alice": 0.9, "": 0.5})
+
+	// THEN the section lists every group in sorted order, untagged as "(none)"
+	output := buf.String()
+	assert.Contains(t, output, "=== Cache Hit Rate by Tenant ===")
+	assert.Contains(t, output, "  (none): 0.5000\n  alice: 0.9000\n  bob: 0.0000\n")
+}
+
+func TestPrintCacheHitBreakdown_SingleGroup_NoOutput(t *testing.T) {
+	// GIVEN a single group (no breakdown to show)
+	var buf bytes.Buffer
+
+	// WHEN we print the breakdown
+	printCacheHitBreakdown(&buf, "SLO Class", map[string]float64{"": 0.4})
+
+	// THEN no output (INV-6: untagged workloads unchanged)
+	assert.Empty(t, buf.String())
+}
+
+func TestPrintUnservableByReason_TwoReasons_PrintsSorted(t *testing.T) {
+	// GIVEN unservable drops under two reasons
+	var buf bytes.Buffer
+
+	// WHEN we print the per-reason counts
+	printUnservableByReason(&buf, map[string]int{"prompt_exceeds_kv": 1, "max_model_len": 3})
+
+	// THEN each reason is listed in sorted order
+	assert.Equal(t, "  Unservable (max_model_len): 3\n  Unservable (prompt_exceeds_kv): 1\n", buf.String())
+}
+
 func TestPrintPerSLOMetrics_MultipleClasses_PrintsSorted(t *testing.T) {
 	// GIVEN per-SLO distributions with multiple classes
 	var buf bytes.Buffer
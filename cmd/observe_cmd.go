@@ -1230,16 +1230,30 @@ func adaptForSessionManager(original *sim.Request, record *RequestRecord) *sim.R
 }
 
 // tokensToPrompt converts token IDs into a diverse prompt string using
-// prefixVocabulary. Each token ID selects a vocabulary word via modular
-// indexing, ensuring different token arrays produce different prompts.
+// prefixVocabulary. The tokens are split into wordCount contiguous spans and
+// each span selects one vocabulary word from its folded token IDs via modular
+// indexing, so every token influences the prompt: different token arrays
+// produce different prompts even when wordCount < len(tokens) (tokensPerWord > 1).
+// With one token per word, word i is selected by tokens[i] directly. Words
+// beyond len(tokens) fall back to the word index.
 func tokensToPrompt(tokens []sim.TokenID, wordCount int) string {
 	vocabLen := len(prefixVocabulary)
+	n := len(tokens)
 	var b strings.Builder
 	b.Grow(wordCount * 8) // average word ~7 chars + space
 	for i := 0; i < wordCount; i++ {
 		var idx int
-		if i < len(tokens) {
-			idx = int(tokens[i])
+		if i < n {
+			// Span [lo, hi) is non-empty for every i < min(n, wordCount).
+			lo, hi := i*n/wordCount, (i+1)*n/wordCount
+			if wordCount > n {
+				lo, hi = i, i+1
+			}
+			var folded int64
+			for _, tok := range tokens[lo:hi] {
+				folded = folded*31 + int64(tok)
+			}
+			idx = int(folded % int64(vocabLen))
 		} else {
 			idx = i
 		}
@@ -1346,6 +1346,61 @@ func TestRequestToPending_NoPrefixDiversePrompt(t *testing.T) {
 	}
 }
 
+func TestRequestToPending_EveryTokenInfluencesPrompt(t *testing.T) {
+	// With tokensPerWord=2.0 each word covers two tokens. Requests that differ
+	// only in the second token of each pair must still produce different prompts.
+	tokens1 := make([]sim.TokenID, 40)
+	tokens2 := make([]sim.TokenID, 40)
+	for i := range tokens1 {
+		tokens1[i] = sim.TokenID(i * 13)
+		tokens2[i] = tokens1[i]
+		if i%2 == 1 {
+			tokens2[i] += 7
+		}
+	}
+	p1 := requestToPending(&sim.Request{ID: "a", InputTokens: tokens1}, 0, false, false, nil, nil, 2.0)
+	p2 := requestToPending(&sim.Request{ID: "b", InputTokens: tokens2}, 1, false, false, nil, nil, 2.0)
+	if p1.Prompt == p2.Prompt {
+		t.Error("requests differing only in odd-position tokens should produce different prompts")
+	}
+	if got := len(strings.Fields(p1.Prompt)); got != 20 {
+		t.Errorf("word count = %d, want 20 (40 tokens / 2.0 tokensPerWord)", got)
+	}
+}
+
+func TestRequestToPending_SamePrefixGroupSharesPrefixOnly(t *testing.T) {
+	groups := map[string]int{"sys": 16}
+	prefixes, prefixLengths := buildPrefixStrings(groups, 42, 1.0)
+
+	makeReq := func(id string, offset int) *sim.Request {
+		tokens := make([]sim.TokenID, 24)
+		for i := range tokens {
+			tokens[i] = sim.TokenID(i)
+			if i >= 16 {
+				tokens[i] += sim.TokenID(offset)
+			}
+		}
+		return &sim.Request{ID: id, InputTokens: tokens, PrefixGroup: "sys"}
+	}
+	p1 := requestToPending(makeReq("a", 1), 0, false, false, prefixes, prefixLengths, 1.0)
+	p2 := requestToPending(makeReq("b", 2), 1, false, false, prefixes, prefixLengths, 1.0)
+
+	// Both start with exactly the group's prefix string...
+	for _, p := range []*PendingRequest{p1, p2} {
+		if !strings.HasPrefix(p.Prompt, prefixes["sys"]) {
+			t.Fatalf("prompt %q does not start with the group prefix", p.Prompt)
+		}
+		// ...and keep the configured input length (16 prefix + 8 suffix words at ratio 1.0).
+		if got := len(strings.Fields(p.Prompt)); got != 24 {
+			t.Errorf("word count = %d, want 24", got)
+		}
+	}
+	// ...while their distinct suffix tokens yield distinct suffixes.
+	if strings.TrimPrefix(p1.Prompt, prefixes["sys"]) == strings.TrimPrefix(p2.Prompt, prefixes["sys"]) {
+		t.Error("same-group requests with different suffix tokens should differ after the prefix")
+	}
+}
+
 func TestRequestToPending_WordCountScaledByTokensPerWord(t *testing.T) {
 	// BC-5: with tokensPerWord=2.0, 100 tokens should produce 50 words
 	tokens := make([]sim.TokenID, 100)
@@ -2265,16 +2320,17 @@ func TestObserveCmd_RecordITLNoStreaming_FatalNoTrace(t *testing.T) {
 // TestObserveCmd_LazySamplerError_FatalNoTrace verifies BC-9: a terminal lazy
 // sampler error (surfaced by lazySource.Err() after dispatch) aborts runObserve
 // with exit 1 and writes no trace — the invariant IMPORTANT-2 asks to pin. The
-// trigger is a reasoning client whose ReasonRatioDist passes spec.Validate() but
-// fails the sampler (missing "mu"), reachable only in the lazy path.
+// trigger is a reasoning client whose file-backed empirical ReasonRatioDist passes
+// spec.ValidateFields() and spec.Validate() (files are not opened during
+// validation) but fails the sampler, reachable only in the lazy path.
 func TestObserveCmd_LazySamplerError_FatalNoTrace(t *testing.T) {
 	header, data := observeSubprocessPaths(t)
 	if os.Getenv("BLIS_TEST_SUBPROCESS") == "1" {
 		dir := filepath.Dir(header)
 		_ = os.MkdirAll(dir, 0o755)
 		specPath := filepath.Join(dir, "spec.yaml")
-		// Single-session reasoning (lazy-supported) with an incomplete
-		// ReasonRatioDist: Validate() passes, the streaming sampler fails.
+		// Single-session reasoning (lazy-supported) with a file-backed
+		// ReasonRatioDist: validation passes, the streaming sampler fails.
 		specYAML := "version: \"2\"\n" +
 			"seed: 42\n" +
 			"aggregate_rate: 10.0\n" +
@@ -2286,7 +2342,7 @@ func TestObserveCmd_LazySamplerError_FatalNoTrace(t *testing.T) {
 			"    input_distribution: {type: constant, params: {value: 50}}\n" +
 			"    output_distribution: {type: constant, params: {value: 25}}\n" +
 			"    reasoning:\n" +
-			"      reason_ratio_distribution: {type: lognormal, params: {sigma: 0.5}}\n" +
+			"      reason_ratio_distribution: {type: empirical, file: ratios.csv}\n" +
 			"      multi_turn: {max_rounds: 2, think_time_us: 1000, context_growth: accumulate, single_session: true}\n"
 		if err := os.WriteFile(specPath, []byte(specYAML), 0o644); err != nil {
 			fmt.Fprintf(os.Stderr, "write spec failed: %v\n", err)
@@ -1794,7 +1794,7 @@ func TestObserveRecorder_VLLMPriority_EndToEndFlow(t *testing.T) {
 	}
 
 	// Verify simulation isolation: LoadTraceV2Requests must NOT read VLLMPriority (BC-5)
-	requests, err := workload.LoadTraceV2Requests(loaded, 42)
+	requests, err := workload.LoadTraceV2Requests(loaded, 42, "")
 	if err != nil {
 		t.Fatalf("LoadTraceV2Requests error: %v", err)
 	}
@@ -28,7 +28,11 @@ var (
 	replayTraceOutput   string // File prefix for TraceV2 re-export (<prefix>.yaml + <prefix>.csv)
 	replaySessionMode   string
 	replayThinkTimeMs   int
-	replayThinkTimeDist string // distribution spec for think time (e.g. "lognormal:mu=2.0,sigma=0.6,min=3s,max=30s")
+	replayThinkTimeDist string  // distribution spec for think time (e.g. "lognormal:mu=2.0,sigma=0.6,min=3s,max=30s")
+	replayRateScale     float64 // offered-load multiplier: inter-arrival gaps are divided by this (1.0 = as recorded)
+	replayBurstRequests int     // synthetic requests overlaid on the trace (0 = no burst)
+	replayBurstAt       int64   // burst start tick in µs, on the (rate-scaled) trace clock
+	replayBurstWindow   int64   // µs over which the burst's arrivals are spread evenly
 	// saturationReport is declared in root.go and shared across run, replay, observe
 )
 
@@ -76,6 +80,10 @@ Example:
 		if model == "" {
 			logrus.Fatalf("LLM name not provided. Exiting simulation.")
 		}
+		// Checked before the trace's token IDs are drawn from it.
+		if !sim.IsValidRandSource(randSource) {
+			logrus.Fatalf("--rand-source must be one of %v, got %q", sim.ValidRandSourceNames(), randSource)
+		}
 
 		// Load trace (BC-1)
 		traceData, err := workload.LoadTraceV2(traceHeaderPath, traceDataPath)
@@ -84,6 +92,22 @@ Example:
 		}
 		logrus.Infof("Loaded trace: %d records (mode=%s)", len(traceData.Records), traceData.Header.Mode)
 
+		// Rescale offered load before any arrival-derived value (horizon, think
+		// times) is computed from the trace.
+		if err := workload.ScaleTraceV2ArrivalRate(traceData, replayRateScale); err != nil {
+			logrus.Fatalf("--rate-scale: %v", err)
+		}
+		if replayRateScale != 1.0 {
+			logrus.Infof("Rate scale %.3g: inter-arrival gaps divided by %.3g", replayRateScale, replayRateScale)
+		}
+		burst := workload.TraceBurst{Requests: replayBurstRequests, StartUs: replayBurstAt, WindowUs: replayBurstWindow}
+		if err := workload.OverlayTraceV2Burst(traceData, burst, seed, randSource); err != nil {
+			logrus.Fatalf("--burst-requests: %v", err)
+		}
+		if replayBurstRequests > 0 {
+			logrus.Infof("Burst overlay: %d requests over [%d, %d) µs", replayBurstRequests, replayBurstAt, replayBurstAt+replayBurstWindow)
+		}
+
 		// Validate session mode flags (BC-11)
 		if replaySessionMode != "fixed" && replaySessionMode != "closed-loop" {
 			logrus.Fatalf("--session-mode must be \"fixed\" or \"closed-loop\", got %q", replaySessionMode)
@@ -91,6 +115,12 @@ Example:
 		if replayThinkTimeMs < 0 {
 			logrus.Fatalf("--think-time-ms must be non-negative, got %d", replayThinkTimeMs)
 		}
+		if bootstrapResamples < 0 {
+			logrus.Fatalf("--bootstrap-resamples must be >= 0, got %d", bootstrapResamples)
+		}
+		if !sim.IsValidPercentileMethod(percentileMethod) {
+			logrus.Fatalf("--percentile-method must be %s or %s, got %q", sim.PercentileLinear, sim.PercentileNearestRank, percentileMethod)
+		}
 		if replayThinkTimeMs > 0 && replaySessionMode != "closed-loop" {
 			logrus.Fatalf("--think-time-ms requires --session-mode closed-loop")
 		}
@@ -130,7 +160,7 @@ Example:
 			if cmd.Flags().Changed("horizon") {
 				replayHorizonPrelim = simulationHorizon
 			}
-			r0Requests, blueprints, bErr := workload.LoadTraceV2SessionBlueprints(traceData, seed, thinkTimeSampler, replayHorizonPrelim)
+			r0Requests, blueprints, bErr := workload.LoadTraceV2SessionBlueprints(traceData, seed, randSource, thinkTimeSampler, replayHorizonPrelim)
 			if bErr != nil {
 				logrus.Fatalf("Failed to build session blueprints from trace: %v", bErr)
 			}
@@ -145,7 +175,7 @@ Example:
 		} else {
 			// Fixed mode (default): pre-baked arrivals, existing behavior (BC-8)
 			var bErr error
-			requests, bErr = workload.LoadTraceV2Requests(traceData, seed)
+			requests, bErr = workload.LoadTraceV2Requests(traceData, seed, randSource)
 			if bErr != nil {
 				logrus.Fatalf("Failed to build requests from trace: %v", bErr)
 			}
@@ -230,6 +260,7 @@ Example:
 				WarmStartInitialInstances: bundle.InstanceLifecycle.WarmStartInitialInstances,
 			}
 		}
+		bundleInstanceOverrides, bundleHWConfigByGPU := buildInstanceOverrides(bundle, numInstances, lr.Backend)
 
 		// PD disaggregation validation (same as runCmd, R3) — INV-13 Track A.
 		if prefillInstances < 0 {
@@ -284,6 +315,7 @@ Example:
 				logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing, but failed to extract ModelConfig: %v", mcErr)
 			}
 			applyWeightPrecisionFallback(mc, model, hfConfig.Raw)
+			applyDtypeOverrides(mc)
 			if mc.BytesPerParam <= 0 {
 				logrus.Fatalf("PD disaggregation: could not determine model precision (BytesPerParam=%v) from %s — ensure torch_dtype or dtype is present in config.json", mc.BytesPerParam, hfPath)
 			}
@@ -318,7 +350,7 @@ Example:
 						poolPrefillGPU = prefillHardware
 					}
 					if poolPrefillTP != tensorParallelism || poolPrefillGPU != gpu {
-						poolHC, hcErr := latency.GetHWConfig(hwConfigPath, poolPrefillGPU)
+						poolHC, hcErr := loadHardwareCalib(hwConfigPath, fleetInventoryPath, poolPrefillGPU)
 						if hcErr != nil {
 							logrus.Warnf("--prefill-hardware: failed to load hardware config for GPU %q: %v; prefill pool will use global total-kv-blocks=%d", poolPrefillGPU, hcErr, totalKVBlocks)
 						} else if poolHC.MemoryGiB <= 0 {
@@ -355,7 +387,7 @@ Example:
 						poolDecodeGPU = decodeHardware
 					}
 					if poolDecodeTP != tensorParallelism || poolDecodeGPU != gpu {
-						poolHC, hcErr := latency.GetHWConfig(hwConfigPath, poolDecodeGPU)
+						poolHC, hcErr := loadHardwareCalib(hwConfigPath, fleetInventoryPath, poolDecodeGPU)
 						if hcErr != nil {
 							logrus.Warnf("--decode-hardware: failed to load hardware config for GPU %q: %v; decode pool will use global total-kv-blocks=%d", poolDecodeGPU, hcErr, totalKVBlocks)
 						} else if poolHC.MemoryGiB <= 0 {
@@ -469,25 +501,70 @@ Example:
 		// DeploymentConfig literal). See docs/contributing/standards/invariants.md INV-13.
 		config := cluster.DeploymentConfig{
 			SimConfig: sim.SimConfig{
-				Horizon: replayHorizon,
-				Seed:    seed,
+				Horizon:    replayHorizon,
+				Seed:       seed,
+				RandSource: randSource,
 				KVCacheConfig: sim.NewKVCacheConfig(totalKVBlocks, blockSizeTokens, kvCPUBlocks,
 					kvOffloadThreshold, kvTransferBandwidth, kvTransferBaseLatency),
-				BatchConfig:          sim.NewBatchConfig(maxRunningReqs, maxScheduledTokens, longPrefillTokenThreshold),
-				LatencyCoeffs:        sim.NewLatencyCoeffs(lr.BetaCoeffs, lr.AlphaCoeffs),
-				ModelHardwareConfig:  sim.NewModelHardwareConfig(lr.ModelConfig, lr.HWConfig, model, gpu, tensorParallelism, dataParallelism, enableExpertParallel, moeCommBackend, lr.Backend, maxModelLen),
-				PolicyConfig:         sim.NewPolicyConfig(scheduler, preemptionPolicy),
-				LoRAConfig:           loraCfg,
-				SLOPriorityOverrides: sloPriorityOverrides,
+				BatchConfig:                    sim.NewBatchConfig(maxRunningReqs, maxScheduledTokens, longPrefillTokenThreshold),
+				LatencyCoeffs:                  sim.NewLatencyCoeffs(lr.BetaCoeffs, lr.AlphaCoeffs),
+				ModelHardwareConfig:            sim.NewModelHardwareConfig(lr.ModelConfig, lr.HWConfig, model, gpu, tensorParallelism, dataParallelism, enableExpertParallel, moeCommBackend, lr.Backend, maxModelLen, stepTimeTablePath),
+				PolicyConfig:                   sim.NewPolicyConfig(scheduler, preemptionPolicy, priorityPolicy),
+				LoRAConfig:                     loraCfg,
+				SLOPriorityOverrides:           sloPriorityOverrides,
+				WarmupSteps:                    warmupSteps,
+				WarmupFactor:                   warmupFactor,
+				MaxQueueDepth:                  maxQueueDepth,
+				MaxQueueWaitTicks:              maxQueueWait,
+				QueueOverflowPolicy:            queueOverflowPolicy,
+				KVPressureThreshold:            kvPressureThreshold,
+				DetokenizationUsPerToken:       detokenizationUsPerToken,
+				MaxOutputTokens:                maxOutputTokens,
+				TokensPerDecodeStep:            tokensPerDecodeStep,
+				DecodeLengthBuckets:            decodeLengthBuckets,
+				DecodeQuantumSteps:             decodeQuantumSteps,
+				PreemptionMode:                 preemptionMode,
+				SwapSpaceBlocks:                swapSpaceBlocks,
+				CriticalReserveFraction:        criticalReserveFraction,
+				AdaptivePrefillChunkMin:        adaptivePrefillChunkMin,
+				StepOrdering:                   stepOrdering,
+				MinBatchFill:                   minBatchFill,
+				BatchFillMaxWaitTicks:          batchFillMaxWait,
+				PowerIdleWatts:                 powerIdleWatts,
+				PowerPeakWatts:                 powerPeakWatts,
+				PowerCapWatts:                  powerCapWatts,
+				KVAllocationMode:               kvAllocationMode,
+				KVFairShareMaxBlocks:           kvFairShareMaxBlocks,
+				RemotePrefixFetchUsPerBlock:    remotePrefixFetchUsPerBlock,
+				SchedulingOverheadUsPerSeq:     schedulingOverheadUs,
+				SchedulingContentionUsPerSeqSq: schedulingContentionUs,
+				MinStepTimeTicks:               minStepTimeUs,
+				StepNoiseMagnitude:             stepNoise,
+				StepNoiseCorrelation:           stepNoiseCorrelation,
+				RooflineBlockTable:             rooflineBlockTable,
+				RooflineAccounting:             rooflineAccounting,
+				StopAfterCompleted:             stopAfterCompleted,
+				ThroughputSampleIntervalUs:     throughputSampleInterval,
+				KVSampleIntervalUs:             kvSampleInterval,
 			},
 			NumInstances:                    numInstances,
 			AdmissionPolicy:                 admissionPolicy,
 			AdmissionLatency:                admissionLatency,
+			AdmissionLatencyDist:            admissionLatencyDist,
+			AdmissionLatencyStdDevUs:        admissionLatencyStd,
 			RoutingLatency:                  routingLatency,
 			TokenBucketCapacity:             tokenBucketCapacity,
 			TokenBucketRefillRate:           tokenBucketRefillRate,
+			RetryMaxAttempts:                retryMaxAttempts,
+			RetryBackoffUs:                  retryBackoff,
+			MaxInFlightTokens:               maxInFlightTokens,
+			FaultInjection:                  cluster.FaultInjectionConfig{InstanceID: faultInstance, AtUs: faultAt, Mode: faultMode},
+			SharedPrefixCache:               sharedPrefixCache,
 			RoutingPolicy:                   routingPolicy,
 			RoutingScorerConfigs:            parsedScorerConfigs,
+			RoutingTieBreak:                 routingTieBreak,
+			RoutingInstanceWeights:          routingInstanceWeights,
+			PrefixHashSkipTokens:            prefixHashSkipTokens,
 			TraceLevel:                      traceLevel,
 			CounterfactualK:                 counterfactualK,
 			SnapshotRefreshInterval:         snapshotRefreshInterval,
@@ -502,8 +579,11 @@ Example:
 			PDTransferBandwidthGBps:         pdTransferBandwidth,
 			PDTransferBaseLatencyMs:         pdTransferBaseLatency,
 			PDTransferContention:            pdTransferContention,
+			KVOffloadHostContention:         kvOffloadHostContention,
 			PrefillScorerConfigs:            prefillScorerCfgs,
 			DecodeScorerConfigs:             decodeScorerCfgs,
+			PrefillRoutingPolicy:            prefillRoutingPolicy,
+			DecodeRoutingPolicy:             decodeRoutingPolicy,
 			PrefillOverrides:                prefillOverrides,
 			DecodeOverrides:                 decodeOverrides,
 			FlowControlEnabled:              flowControlEnabled,
@@ -525,9 +605,14 @@ Example:
 			TierShedMinPriority:             tierShedMinPriority,
 			GAIEQDThreshold:                 gaieQDThreshold,
 			GAIEKVThreshold:                 gaieKVThreshold,
+			SLOTokenBuckets:                 sloTokenBuckets,
+			TenantShares:                    tenantShares,
 			TenantBudgets:                   tenantBudgets,
 			InstanceLifecycle:               bundleInstanceLifecycle,
+			InstanceOverrides:               bundleInstanceOverrides,
+			HWConfigByGPU:                   bundleHWConfigByGPU,
 		}
+		config.KVCacheConfig.CheckRefCounts = kvRefCountChecks
 
 		// Run simulation — wire SessionManager for closed-loop, nil for fixed mode
 		// Collect follow-ups for saturation analysis in closed-loop mode (BC-12, issue #1298)
@@ -591,6 +676,7 @@ Example:
 		// Save aggregate metrics to stdout (same as runCmd)
 		if numInstances > 1 {
 			for _, inst := range cs.Instances() {
+				inst.Metrics().PercentileMethod = sim.PercentileMethod(percentileMethod)
 				if err := inst.Metrics().SaveResults(string(inst.ID()), config.Horizon, totalKVBlocks, "", saturationDetector); err != nil {
 					logrus.Fatalf("SaveResults for instance %s: %v", inst.ID(), err)
 				}
@@ -599,8 +685,10 @@ Example:
 		// Save aggregate (always print to stdout; SimResult output uses separate file)
 		// goodputTargets resolved above for trace re-export; reused here (#1413, BC-1, BC-4).
 		aggregated := cs.AggregatedMetrics()
+		aggregated.PercentileMethod = sim.PercentileMethod(percentileMethod)
 		clusterOutput := aggregated.BuildOutput("cluster", saturationDetector)
-		emitGoodput(&clusterOutput, aggregated, cs.InjectedByClass(),
+		clusterOutput.LatencyCI = aggregated.BootstrapLatencyCIs(bootstrapResamples, seed)
+		goodput := emitGoodput(&clusterOutput, aggregated, cs.InjectedByClass(),
 			float64(aggregated.SimEndedTime)/1e6, goodputTargets)
 		if err := aggregated.EmitOutput(clusterOutput, ""); err != nil {
 			logrus.Fatalf("SaveResults: %v", err)
@@ -628,6 +716,9 @@ Example:
 		rawMetrics.GatewayQueueRejected = cs.GatewayQueueRejected() // Issue #1190: gateway queue rejected count
 		rawMetrics.GatewayEvicted = cs.GatewayEvicted()             // Phase 4: in-flight eviction count (#1228)
 		rawMetrics.GatewayExpired = cs.GatewayExpired()             // Phase 6: TTL expiration count (#1193)
+		rawMetrics.AdmissionRetries = cs.RetriedRequests()          // admission retry model: re-attempts after rejection
+		rawMetrics.FailedRequests = cs.FailedRequests()             // fault injection: requests lost with a failed instance
+		rawMetrics.Goodput = goodput                                // SLO-meeting throughput; zero when no goodput targets
 
 		if rawMetrics.PD != nil && config.PDTransferContention {
 			rawMetrics.PD.PeakConcurrentTransfers = cs.PeakConcurrentTransfers()
@@ -635,11 +726,14 @@ Example:
 		}
 
 		// Print anomaly counters if any detected
-		if rawMetrics.PriorityInversions > 0 || rawMetrics.HOLBlockingEvents > 0 || rawMetrics.RejectedRequests > 0 || rawMetrics.RoutingRejections > 0 || rawMetrics.DroppedUnservable > 0 || rawMetrics.LengthCappedRequests > 0 || rawMetrics.GatewayQueueDepth > 0 || rawMetrics.GatewayQueueShed > 0 || rawMetrics.GatewayQueueRejected > 0 || rawMetrics.GatewayEvicted > 0 || rawMetrics.GatewayExpired > 0 || rawMetrics.EncodeRoutingRejections > 0 || rawMetrics.TimedOutRequests > 0 {
+		if rawMetrics.PriorityInversions > 0 || rawMetrics.HOLBlockingEvents > 0 || rawMetrics.RejectedRequests > 0 || rawMetrics.AdmissionRetries > 0 || rawMetrics.RoutingRejections > 0 || rawMetrics.DroppedUnservable > 0 || rawMetrics.LengthCappedRequests > 0 || rawMetrics.GatewayQueueDepth > 0 || rawMetrics.GatewayQueueShed > 0 || rawMetrics.GatewayQueueRejected > 0 || rawMetrics.GatewayEvicted > 0 || rawMetrics.GatewayExpired > 0 || rawMetrics.EncodeRoutingRejections > 0 || rawMetrics.TimedOutRequests > 0 || rawMetrics.FailedRequests > 0 {
 			fmt.Println("=== Anomaly Counters ===")
 			fmt.Printf("Priority Inversions: %d\n", rawMetrics.PriorityInversions)
 			fmt.Printf("HOL Blocking Events: %d\n", rawMetrics.HOLBlockingEvents)
 			fmt.Printf("Rejected Requests (Admission): %d\n", rawMetrics.RejectedRequests)
+			if rawMetrics.AdmissionRetries > 0 {
+				fmt.Printf("Admission Retries: %d\n", rawMetrics.AdmissionRetries)
+			}
 			if len(rawMetrics.ShedByTier) > 0 {
 				tierKeys := make([]string, 0, len(rawMetrics.ShedByTier))
 				for k := range rawMetrics.ShedByTier {
@@ -652,7 +746,11 @@ Example:
 			}
 			fmt.Printf("Rejected Requests (Routing): %d\n", rawMetrics.RoutingRejections)
 			fmt.Printf("Dropped Unservable: %d\n", rawMetrics.DroppedUnservable)
+			printUnservableByReason(os.Stdout, rawMetrics.DroppedUnservableByReason)
 			fmt.Printf("Timed Out Requests: %d\n", rawMetrics.TimedOutRequests)
+			if rawMetrics.FailedRequests > 0 {
+				fmt.Printf("Failed Requests (Instance Fault): %d\n", rawMetrics.FailedRequests)
+			}
 			fmt.Printf("Length-Capped Requests: %d\n", rawMetrics.LengthCappedRequests)
 			if rawMetrics.GatewayQueueDepth > 0 {
 				fmt.Printf("Gateway Queue Depth (horizon): %d\n", rawMetrics.GatewayQueueDepth)
@@ -675,6 +773,12 @@ Example:
 		}
 
 		printKVCacheMetrics(os.Stdout, rawMetrics.PreemptionRate, rawMetrics.CacheHitRate, rawMetrics.KVThrashingRate)
+		printCacheHitBreakdown(os.Stdout, "Tenant", rawMetrics.CacheHitRateByTenant)
+		printCacheHitBreakdown(os.Stdout, "SLO Class", rawMetrics.CacheHitRateBySLOClass)
+		if printDiagnostics {
+			printBottleneck(os.Stdout, cs.AggregatedMetrics())
+			printBatchSize(os.Stdout, rawMetrics.BatchSize)
+		}
 
 		sloDistributions := cluster.ComputePerSLODistributions(cs.AggregatedMetrics())
 		printPerSLOMetrics(os.Stdout, sloDistributions, len(goodputTargets) > 0)
@@ -791,10 +895,16 @@ func init() {
 
 	replayCmd.Flags().StringVar(&replaySessionMode, "session-mode", "fixed", `Session replay mode: "fixed" (pre-baked arrivals from trace) or "closed-loop" (load-adaptive follow-ups via SessionManager)`)
 	replayCmd.Flags().IntVar(&replayThinkTimeMs, "think-time-ms", 0, "Override think time between session rounds in milliseconds (0 = derive from trace inter-round arrival gaps; mutually exclusive with --think-time-dist; requires --session-mode closed-loop)")
+	replayCmd.Flags().Float64Var(&replayRateScale, "rate-scale", 1.0, "Scale the trace's offered load by dividing every inter-arrival gap by this factor (2.0 = double the rate, 0.5 = half); token counts and client timeouts are preserved. Must be > 0")
+	replayCmd.Flags().IntVar(&replayBurstRequests, "burst-requests", 0, "Overlay this many synthetic requests on the trace, each copying the token counts and metadata of a random trace record, merged into arrival order (0 = disabled)")
+	replayCmd.Flags().Int64Var(&replayBurstAt, "burst-at", 0, "Start of the --burst-requests window in microseconds on the trace clock (after --rate-scale)")
+	replayCmd.Flags().Int64Var(&replayBurstWindow, "burst-window", 0, "Width of the --burst-requests window in microseconds; burst arrivals are spaced evenly across it (0 = all at --burst-at)")
 	replayCmd.Flags().StringVar(&replayThinkTimeDist, "think-time-dist", "", `Think-time distribution spec for closed-loop replay (e.g. "lognormal:mu=2.0,sigma=0.6,min=3s,max=30s" or "constant:value=500ms"). Mutually exclusive with --think-time-ms. Requires --session-mode closed-loop.`)
 	replayCmd.Flags().StringVar(&goodputSLOTTFT, "slo-ttft", "", "Per-class TTFT goodput thresholds (e.g. \"critical=100ms,standard=500ms\"). Precedence: CLI > trace header > workload spec.")
 	replayCmd.Flags().StringVar(&goodputSLOITL, "slo-itl", "", "Per-class mean ITL goodput thresholds (e.g. \"critical=50ms,standard=150ms\").")
 	replayCmd.Flags().StringVar(&goodputSLOE2E, "slo-e2e", "", "Per-class E2E goodput thresholds (e.g. \"critical=5s,standard=30s\").")
+	replayCmd.Flags().StringVar(&percentileMethod, "percentile-method", string(sim.PercentileLinear), "Latency percentile method: linear (interpolate between ranks, vLLM benchmark parity) or nearest-rank")
+	replayCmd.Flags().IntVar(&bootstrapResamples, "bootstrap-resamples", 0, "Number of bootstrap resamples for 95% confidence intervals on TTFT/E2E/ITL P50/P90/P99 (0 = disabled). Seeded from --seed.")
 	// --lazy-generation: accepted for CLI symmetry with `blis run` (#1441),
 	// but ignored — replay reads requests from a captured trace and never
 	// invokes the workload generator. The flag binds to a throwaway local
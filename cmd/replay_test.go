@@ -125,6 +125,7 @@ func TestReplayCmd_SimConfigFlags_Registered(t *testing.T) {
 		// registerSimConfigFlags: tiered KV cache
 		"kv-cpu-blocks", "kv-offload-threshold",
 		"kv-transfer-bandwidth", "kv-transfer-base-latency",
+		"kv-offload-host-contention",
 		"snapshot-refresh-interval",
 
 		// registerSimConfigFlags: cache signal delay
@@ -830,7 +831,7 @@ warm_up_requests: 0
 		t.Errorf("want 3 records, got %d", len(trace.Records))
 	}
 
-	reqs, err := workload.LoadTraceV2Requests(trace, 42)
+	reqs, err := workload.LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatalf("LoadTraceV2Requests failed: %v", err)
 	}
@@ -1940,8 +1941,8 @@ func TestINV13_RunReplayParity_PD(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(1000, 16, 0, 0.9, 100.0, 0),
 			BatchConfig:         sim.NewBatchConfig(64, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs(betaCfg, alphaCfg),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(*mc, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 4096),
-			PolicyConfig:        sim.NewPolicyConfig("fcfs", ""),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(*mc, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 4096, ""),
+			PolicyConfig:        sim.NewPolicyConfig("fcfs", "", ""),
 		},
 		NumInstances:            2,
 		AdmissionPolicy:         "always-admit",
@@ -1977,7 +1978,7 @@ func TestINV13_RunReplayParity_PD(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadTraceV2: %v", err)
 	}
-	replayReqs, err := workload.LoadTraceV2Requests(traceData, fixedSeed)
+	replayReqs, err := workload.LoadTraceV2Requests(traceData, fixedSeed, "")
 	if err != nil {
 		t.Fatalf("LoadTraceV2Requests: %v", err)
 	}
@@ -2078,8 +2079,8 @@ func TestINV13_RunReplayParity_PD_CLI(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(1000, 16, 0, 0.9, 100.0, 0),
 			BatchConfig:         sim.NewBatchConfig(64, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs(betaCfg, alphaCfg),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(*mc, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 4096),
-			PolicyConfig:        sim.NewPolicyConfig("fcfs", ""),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(*mc, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 4096, ""),
+			PolicyConfig:        sim.NewPolicyConfig("fcfs", "", ""),
 		},
 		NumInstances:            2,
 		AdmissionPolicy:         "always-admit",
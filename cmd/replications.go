@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// replicationResult is the per-replication subset of MetricsOutput summarized
+// by --replications.
+type replicationResult struct {
+	Seed            int64
+	ResponsesPerSec float64
+	TokensPerSec    float64
+	TTFTP99Ms       float64
+	E2EP99Ms        float64
+	ITLP99Ms        float64
+}
+
+// meanStd is a sample mean and sample standard deviation (n-1 denominator;
+// 0 for a single sample).
+type meanStd struct {
+	Mean   float64
+	Stddev float64
+}
+
+// replicationSummary aggregates throughput and P99 latency across replications.
+type replicationSummary struct {
+	Runs            []replicationResult
+	ResponsesPerSec meanStd
+	TokensPerSec    meanStd
+	TTFTP99Ms       meanStd
+	E2EP99Ms        meanStd
+	ITLP99Ms        meanStd
+}
+
+// runReplications calls runOnce n times with seeds baseSeed, baseSeed+1, …,
+// baseSeed+n-1 in order and summarizes the returned cluster outputs. runOnce is
+// the unmodified single-run path, so each replication is exactly the run a user
+// would get from `blis run --seed <s>`.
+func runReplications(n int, baseSeed int64, runOnce func(seed int64) sim.MetricsOutput) replicationSummary {
+	runs := make([]replicationResult, 0, n)
+	for i := 0; i < n; i++ {
+		s := baseSeed + int64(i)
+		out := runOnce(s)
+		runs = append(runs, replicationResult{
+			Seed:            s,
+			ResponsesPerSec: out.ResponsesPerSec,
+			TokensPerSec:    out.TokensPerSec,
+			TTFTP99Ms:       out.TTFTP99Ms,
+			E2EP99Ms:        out.E2EP99Ms,
+			ITLP99Ms:        out.ITLP99Ms,
+		})
+	}
+	return summarizeReplications(runs)
+}
+
+// summarizeReplications computes mean ± stddev of each metric across runs.
+func summarizeReplications(runs []replicationResult) replicationSummary {
+	field := func(get func(r replicationResult) float64) meanStd {
+		values := make([]float64, len(runs))
+		for i, r := range runs {
+			values[i] = get(r)
+		}
+		return computeMeanStd(values)
+	}
+	return replicationSummary{
+		Runs:            runs,
+		ResponsesPerSec: field(func(r replicationResult) float64 { return r.ResponsesPerSec }),
+		TokensPerSec:    field(func(r replicationResult) float64 { return r.TokensPerSec }),
+		TTFTP99Ms:       field(func(r replicationResult) float64 { return r.TTFTP99Ms }),
+		E2EP99Ms:        field(func(r replicationResult) float64 { return r.E2EP99Ms }),
+		ITLP99Ms:        field(func(r replicationResult) float64 { return r.ITLP99Ms }),
+	}
+}
+
+func computeMeanStd(values []float64) meanStd {
+	if len(values) == 0 {
+		return meanStd{}
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return meanStd{Mean: mean}
+	}
+	sq := 0.0
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return meanStd{Mean: mean, Stddev: math.Sqrt(sq / float64(len(values)-1))}
+}
+
+// printReplicationSummary prints the cross-replication summary after all runs.
+func printReplicationSummary(w io.Writer, s replicationSummary) {
+	_, _ = fmt.Fprintln(w, "=== Replication Summary ===")
+	_, _ = fmt.Fprintf(w, "Replications: %d\n", len(s.Runs))
+	_, _ = fmt.Fprint(w, "Seeds:")
+	for _, r := range s.Runs {
+		_, _ = fmt.Fprintf(w, " %d", r.Seed)
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "Responses/sec: mean=%.4f stddev=%.4f\n", s.ResponsesPerSec.Mean, s.ResponsesPerSec.Stddev)
+	_, _ = fmt.Fprintf(w, "Tokens/sec:    mean=%.4f stddev=%.4f\n", s.TokensPerSec.Mean, s.TokensPerSec.Stddev)
+	_, _ = fmt.Fprintf(w, "TTFT P99 (ms): mean=%.4f stddev=%.4f\n", s.TTFTP99Ms.Mean, s.TTFTP99Ms.Stddev)
+	_, _ = fmt.Fprintf(w, "E2E P99 (ms):  mean=%.4f stddev=%.4f\n", s.E2EP99Ms.Mean, s.E2EP99Ms.Stddev)
+	_, _ = fmt.Fprintf(w, "ITL P99 (ms):  mean=%.4f stddev=%.4f\n", s.ITLP99Ms.Mean, s.ITLP99Ms.Stddev)
+}
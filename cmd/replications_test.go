@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// TestRunReplications_DistinctSeedsAndMeanStddev verifies the seed sequence and
+// the mean/stddev arithmetic independently of a full simulation.
+func TestRunReplications_DistinctSeedsAndMeanStddev(t *testing.T) {
+	var seen []int64
+	summary := runReplications(3, 100, func(s int64) sim.MetricsOutput {
+		seen = append(seen, s)
+		// Throughput 10, 20, 30 → mean 20, sample stddev 10.
+		return sim.MetricsOutput{ResponsesPerSec: float64(s-99) * 10, TTFTP99Ms: 5}
+	})
+
+	if want := []int64{100, 101, 102}; len(seen) != 3 || seen[0] != want[0] || seen[1] != want[1] || seen[2] != want[2] {
+		t.Fatalf("seeds = %v, want %v", seen, want)
+	}
+	if summary.ResponsesPerSec.Mean != 20 || math.Abs(summary.ResponsesPerSec.Stddev-10) > 1e-9 {
+		t.Errorf("ResponsesPerSec = %+v, want mean=20 stddev=10", summary.ResponsesPerSec)
+	}
+	if summary.TTFTP99Ms.Mean != 5 || summary.TTFTP99Ms.Stddev != 0 {
+		t.Errorf("TTFTP99Ms = %+v, want mean=5 stddev=0", summary.TTFTP99Ms)
+	}
+}
+
+func TestComputeMeanStd_SingleSample_ZeroStddev(t *testing.T) {
+	got := computeMeanStd([]float64{3})
+	if got.Mean != 3 || got.Stddev != 0 {
+		t.Errorf("computeMeanStd([3]) = %+v, want {3 0}", got)
+	}
+	if got := computeMeanStd(nil); got != (meanStd{}) {
+		t.Errorf("computeMeanStd(nil) = %+v, want zero", got)
+	}
+}
+
+// TestRunCmd_Replications_PrintsSummary drives `blis run --replications 3`
+// through runCmd.Run and asserts that each replication ran on its own seed and
+// that the summary reports mean and stddev for throughput and every P99 metric.
+// NOTE: Do NOT use t.Parallel() — mutates package-level vars.
+func TestRunCmd_Replications_PrintsSummary(t *testing.T) {
+	origReplications := replications
+	defer func() { replications = origReplications }()
+	replications = 3
+
+	shape := paritySpecShapes()[0] // chatbot
+	out := string(runSpecAndCaptureStdout(t, shape.yaml, 7, shape.horizon, false))
+
+	// THEN each replication announces a distinct, consecutive seed
+	seedRe := regexp.MustCompile(`=== Replication \(seed=(\d+)\) ===`)
+	matches := seedRe.FindAllStringSubmatch(out, -1)
+	if len(matches) != 3 {
+		t.Fatalf("got %d replication headers, want 3\nstdout:\n%s", len(matches), out)
+	}
+	for i, want := range []string{"7", "8", "9"} {
+		if matches[i][1] != want {
+			t.Errorf("replication %d seed = %s, want %s", i, matches[i][1], want)
+		}
+	}
+
+	// AND the summary reports mean and stddev per metric
+	idx := strings.Index(out, "=== Replication Summary ===")
+	if idx < 0 {
+		t.Fatalf("missing replication summary\nstdout:\n%s", out)
+	}
+	summary := out[idx:]
+	if !strings.Contains(summary, "Seeds: 7 8 9") {
+		t.Errorf("summary seeds line missing or wrong:\n%s", summary)
+	}
+	for _, metric := range []string{"Responses/sec:", "Tokens/sec:", "TTFT P99 (ms):", "E2E P99 (ms):", "ITL P99 (ms):"} {
+		line := lineWithPrefix(summary, metric)
+		if !strings.Contains(line, "mean=") || !strings.Contains(line, "stddev=") {
+			t.Errorf("summary line for %q lacks mean/stddev: %q", metric, line)
+		}
+	}
+
+	// AND the seeds actually changed the workload: per-run throughput differs
+	if strings.Contains(lineWithPrefix(summary, "Tokens/sec:"), "stddev=0.0000") {
+		t.Errorf("tokens/sec stddev is zero across distinct seeds — replications did not vary:\n%s", summary)
+	}
+}
+
+// lineWithPrefix returns the first line of s beginning with prefix, or "".
+func lineWithPrefix(s, prefix string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+	return ""
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -54,8 +56,10 @@ const (
 var (
 	// CLI flags for vllm server configs
 	seed                      int64     // Seed for random token generation
+	randSource                string    // Bit generator for all RNG streams (stdlib or xoshiro256ss)
 	simulationHorizon         int64     // Total simulation time (in ticks)
 	logLevel                  string    // Log verbosity level
+	printDiagnostics          bool      // Print the bottleneck diagnosis and batch-size distribution after the run
 	totalKVBlocks             int64     // Total number of KV blocks available on GPU
 	maxRunningReqs            int64     // Maximum number of requests in the Running batch
 	maxScheduledTokens        int64     // Maximum total number of tokens across requests in the Running batch
@@ -65,8 +69,42 @@ var (
 	defaultsFilePath          string    // Path to default constants - trained coefficients, default specs and workloads
 	modelConfigFolder         string    // Path to folder containing config.json and model.json
 	hwConfigPath              string    // Path to constants specific to hardware type (GPU)
+	fleetInventoryPath        string    // Path to a JSON fleet inventory overriding hardware config peaks ("" = off)
+	computeDtype              string    // Roofline GEMM precision override: bf16, fp16, fp8, int8 ("" = infer from weights)
+	kvCacheDtype              string    // KV cache precision: auto (compute dtype), bf16, fp16, fp8, int8
 	workloadType              string    // Workload type (chatbot, summarization, contentgen, multidoc, distribution)
 	longPrefillTokenThreshold int64     // Max length of prefill beyond which chunked prefill is triggered
+	kvPressureThreshold       float64   // KV utilization above which new admissions are throttled (0 = disabled)
+	detokenizationUsPerToken  float64   // CPU detokenization cost per output token added to E2E (0 = disabled)
+	maxOutputTokens           int       // Server-side output length cap; longer outputs finish by length (0 = disabled)
+	tokensPerDecodeStep       int       // Output tokens per decode step per request (0/1 = one)
+	decodeLengthBuckets       int       // Padded decode passes per step, split by context length (0 = unpadded)
+	decodeQuantumSteps        int       // Steps between fair-decode rotations of the running batch (0 = disabled)
+	criticalReserveFraction   float64   // Fraction of running slots and token budget held for critical-class requests (0 = disabled)
+	adaptivePrefillChunkMin   int64     // Floor of the decode-load-adaptive prefill chunk size (0 = fixed --long-prefill-token-threshold)
+	stepOrdering              string    // Which work a tight step token budget is charged to first (decode-first or prefill-first)
+	powerIdleWatts            float64   // Per-instance power draw of an idle step (power model)
+	powerPeakWatts            float64   // Per-instance power draw at a full token budget (0 = power model off)
+	powerCapWatts             float64   // Per-instance power cap; steps over it are clock-throttled (0 = unlimited)
+	schedulingOverheadUs      float64   // Per-sequence scheduling cost added to every step time (0 = disabled)
+	schedulingContentionUs    float64   // Per-sequence² scheduling contention cost added to every step time (0 = disabled)
+	minStepTimeUs             int64     // Floor on every step's forward-pass time (0 = no floor)
+	stepNoise                 float64   // Std dev of the AR(1) step-time noise factor (0 = deterministic step times)
+	stepNoiseCorrelation      float64   // Lag-1 correlation of the step-time noise
+	rooflineBlockTable        bool      // Charge paged-attention block-table reads in roofline step time
+	rooflineAccounting        bool      // Record per-step roofline FLOPs/bytes and export a bound-step summary
+	stopAfterCompleted        int64     // Halt once this many requests complete; arrivals stream until then (0 = disabled)
+	throughputSampleInterval  int64     // Tick interval for the cumulative completed-request series (0 = disabled)
+	kvSampleInterval          int64     // Tick interval for the KV blocks-used series (0 = disabled)
+	kvAllocationMode          string    // Per-request KV allocation: greedy, fair-share
+	kvFairShareMaxBlocks      int64     // Fixed fair-share cap in KV blocks (0 = total blocks / running requests)
+	warmupSteps               int       // Cold-start steps with inflated step time per fresh instance (0 = disabled)
+	warmupFactor              float64   // Step-time multiplier on an instance's first step; decays linearly to 1.0
+	maxQueueDepth             int       // Per-instance wait-queue bound (0 = unbounded)
+	queueOverflowPolicy       string    // Who is turned away when the wait queue is full: reject-new, drop-oldest
+	maxQueueWait              int64     // Anti-starvation deadline: queued requests this old move to the queue front (0 = disabled)
+	minBatchFill              int       // Queued requests an idle instance waits for before launching a step (0 = disabled)
+	batchFillMaxWait          int64     // Longest an idle instance holds its oldest queued request for --min-batch-fill (µs)
 	rate                      float64   // Requests arrival per second
 	numRequests               int       // Number of requests
 	concurrency               int       // Number of concurrent virtual users (closed-loop)
@@ -81,6 +119,7 @@ var (
 	outputTokensMin           int       // Min Output Token Count
 	outputTokensMax           int       // Max Output Token Count
 	latencyModelBackend       string    // CLI --latency-model flag: selects latency model backend (Cobra-bound, NEVER mutated inside Run)
+	stepTimeTablePath         string    // CLI --step-time-table: measured step-time CSV for --latency-model table
 	maxModelLen               int64     // CLI --max-model-len: max total sequence length (input + output); 0 = unlimited
 	// CLI flags for model, GPU, TP
 	model                string // LLM name
@@ -91,14 +130,26 @@ var (
 	moeCommBackend       string // MoE all-to-all comm backend (MoE only; trained-physics backend only)
 
 	// cluster config
-	numInstances int // Number of instances in the cluster
+	numInstances  int    // Number of instances in the cluster
+	faultInstance string // Instance ID to fail mid-run (empty = no fault injection)
+	faultAt       int64  // Simulated time of the injected failure in microseconds
+	faultMode     string // Fate of the failed instance's in-flight requests: lose, requeue
+
+	// shared prefix cache config
+	sharedPrefixCache           bool    // Cluster-wide prefix index over a shared distributed KV store
+	remotePrefixFetchUsPerBlock float64 // Step-time cost per KV block fetched from another instance's cache
 
 	// online routing pipeline config
 	admissionPolicy       string             // Admission policy name
 	admissionLatency      int64              // Admission latency in microseconds
+	admissionLatencyDist  string             // Admission latency jitter distribution: "" (fixed), gaussian, lognormal, exponential
+	admissionLatencyStd   float64            // Admission latency stddev in microseconds (gaussian only)
 	routingLatency        int64              // Routing latency in microseconds
 	tokenBucketCapacity   float64            // Token bucket capacity
 	tokenBucketRefillRate float64            // Token bucket refill rate (tokens/second)
+	retryMaxAttempts      int                // Max admission retries per rejected request (0 = disabled)
+	maxInFlightTokens     int64              // Cluster-wide in-flight token cap at admission (0 = disabled)
+	retryBackoff          int64              // Base admission retry backoff in microseconds
 	tierShedThreshold     int                // Tier-shed overload threshold (0 = any load)
 	tierShedMinPriority   int                // Tier-shed minimum admitted priority under overload
 	tenantBudgets         map[string]float64 // Per-tenant fraction of total capacity (nil = no enforcement)
@@ -107,14 +158,30 @@ var (
 	gaieQDThreshold       float64            // GAIE-legacy queue depth threshold per instance (default 5)
 	gaieKVThreshold       float64            // GAIE-legacy KV cache utilization threshold (default 0.8)
 
+	// Per-SLO-class bucket sizes for slo-token-bucket (policy bundle only; nil = all classes use the default bucket size)
+	sloTokenBuckets map[string]sim.SLOTokenBucketConfig
+
+	// Per-tenant guaranteed capacity shares for tenant-borrow (policy bundle only; nil = no guarantees)
+	tenantShares map[string]float64
+
 	// routing policy config (PR 6, evolved in PR17)
 	routingPolicy    string  // Routing policy name
 	routingScorers   string  // Comma-separated name:weight pairs for weighted routing
 	loraScorerWeight float64 // Weight of the lora-affinity scorer; 0 (default) ⇒ off (#1469)
+	routingTieBreak  string  // Tie-break among equally scored instances: random (default), lowest-index
+
+	prefixHashSkipTokens int64 // Leading input tokens the prefix-affinity scorer leaves out of its hashes (0 = hash all)
+
+	// Static per-instance routing weights for --routing-policy static-weighted.
+	routingWeights         string    // Comma-separated weights from --routing-weights
+	routingInstanceWeights []float64 // Resolved from --routing-weights or bundle routing.weights (nil = unset)
 
 	// Scheduler and preemption config
 	scheduler        string // Scheduler name
 	preemptionPolicy string // Preemption victim selection policy
+	preemptionMode   string // What a preemption does to the victim's KV: recompute (default) or swap
+	swapSpaceBlocks  int64  // Host KV blocks swapped-out requests may hold under swap preemption (0 = unbounded)
+	priorityPolicy   string // Source of instance-level request priority: slo-class or explicit
 
 	// Policy bundle config
 	policyConfigPath string // Path to YAML policy configuration file
@@ -144,12 +211,15 @@ var (
 	// Workload spec config (PR10)
 	workloadSpecPath string // Path to YAML workload specification file
 	lazyGeneration   bool   // --lazy-generation: stream requests from generator (alpha, #1441)
+	kvPrefixSeed     bool   // --kv-prefix-seed: seed instance KV caches with the workload's prefix groups
 
 	// Tiered KV cache config (PR12)
 	kvCPUBlocks             int64
 	kvOffloadThreshold      float64
 	kvTransferBandwidth     float64
 	kvTransferBaseLatency   int64
+	kvOffloadHostContention bool // --kv-offload-host-contention: instances on one node share host-memory bandwidth
+	kvRefCountChecks        bool // --kv-refcount-checks: panic on KV block refcount violations (debug)
 	snapshotRefreshInterval int64
 	cacheSignalDelay        int64
 	gpuMemoryUtilization    float64
@@ -165,6 +235,8 @@ var (
 	pdPrefixThreshold      int     // Non-cached token threshold for prefix-threshold decider
 	prefillRoutingScorers  string  // Scorer weights for prefill pool routing
 	decodeRoutingScorers   string  // Scorer weights for decode pool routing
+	prefillRoutingPolicy   string  // Routing policy for prefill pool ("" = weighted when scorers set, else cluster policy)
+	decodeRoutingPolicy    string  // Routing policy for decode pool ("" = weighted when scorers set, else cluster policy)
 
 	// E/P/D disaggregation config (GAP-4, issue #1264)
 	encodeInstances int    // Number of instances dedicated to encoding multimodal input (0 = disabled)
@@ -210,6 +282,16 @@ var (
 	goodputSLOITL  string
 	goodputSLOE2E  string
 
+	// Number of independent `blis run` replications with seeds seed..seed+N-1.
+	replications int
+
+	// Bootstrap confidence intervals for latency percentiles (0 = disabled).
+	// Resampling is seeded from --seed so intervals are reproducible.
+	bootstrapResamples int
+
+	// Percentile method for latency output: "linear" (default) or "nearest-rank".
+	percentileMethod string
+
 	// output file paths
 	metricsPath      string // File to write MetricsOutput JSON for blis run (--metrics-path)
 	resultsPath      string // File to write []SimResult JSON for blis replay (--results-path)
@@ -235,8 +317,101 @@ var (
 
 	// trace export
 	traceOutput string // File prefix for TraceV2 export (<prefix>.yaml + <prefix>.csv)
+
+	// debugging
+	eventLogPath  string // JSONL file recording every executed event (--event-log)
+	kvStatePath   string // JSON file receiving each instance's final KV prefix index (--dump-kv-state)
+	kvExportPath  string // JSON file receiving each instance's cached prefixes for a later warm start (--kv-export-state)
+	kvImportPath  string // JSON file from --kv-export-state whose prefixes warm each instance's KV cache (--kv-import-state)
+	otlpTracePath string // OTLP/JSON file receiving per-request lifecycle spans (--otlp-trace)
+
+	// routing replay
+	routingLogPath       string // JSON file receiving the run's routing-decision log (--routing-log-output)
+	routingReplayLogPath string // JSON routing-decision log obeyed by --routing-policy replay (--routing-replay-log)
+
+	// multi-region ingress
+	ingressClockOffsets map[string]int64 // Ingress point → arrival clock offset in µs (--ingress-clock-offsets)
 )
 
+// writeKVStateDump writes the cluster's final per-instance KV prefix index
+// (ClusterSimulator.KVStateDump) to path as indented JSON.
+func writeKVStateDump(path string, cs *cluster.ClusterSimulator) error {
+	data, err := json.MarshalIndent(struct {
+		Instances []cluster.InstanceKVState `json:"instances"`
+	}{cs.KVStateDump()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// kvPrefixFile is the --kv-export-state / --kv-import-state file format.
+type kvPrefixFile struct {
+	Instances []cluster.InstanceKVPrefixes `json:"instances"`
+}
+
+// writeKVPrefixExport writes the cluster's final per-instance cached prefixes
+// (ClusterSimulator.ExportKVPrefixes) to path as JSON.
+func writeKVPrefixExport(path string, cs *cluster.ClusterSimulator) error {
+	data, err := json.Marshal(kvPrefixFile{Instances: cs.ExportKVPrefixes()})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// readKVPrefixImport reads a --kv-export-state file for DeploymentConfig.KVWarmState.
+func readKVPrefixImport(path string) ([]cluster.InstanceKVPrefixes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f kvPrefixFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parsing KV prefix state: %w", err)
+	}
+	return f.Instances, nil
+}
+
+// writeRoutingLog writes the run's routing-decision log (request ID ->
+// instance ID, from the decision trace) to path as JSON (--routing-log-output).
+func writeRoutingLog(path string, cs *cluster.ClusterSimulator) error {
+	data, err := json.MarshalIndent(cs.Trace().RoutingLog(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// readRoutingReplayLog reads a --routing-log-output file for
+// DeploymentConfig.RoutingReplayLog.
+func readRoutingReplayLog(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var log map[string]string
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("parsing routing-decision log: %w", err)
+	}
+	if len(log) == 0 {
+		return nil, fmt.Errorf("routing-decision log %s is empty", path)
+	}
+	return log, nil
+}
+
+// writeOTLPTrace writes the per-request lifecycle spans of m to path as
+// OTLP/JSON (--otlp-trace).
+func writeOTLPTrace(path string, m *sim.Metrics) error {
+	var buf bytes.Buffer
+	if err := sim.WriteOTLPTrace(&buf, m); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
 // registerSaturationFlags registers backlog-drift analysis flags on the given command.
 // These flags control post-hoc saturation classification and are shared across run, replay, and observe.
 //
@@ -414,6 +589,7 @@ type latencyResolution struct {
 //   - Validates gpuMemoryUtilization and blockSizeTokens (used in KV auto-calc)
 //   - Applies defaults.yaml for GPU and TP when not set via CLI
 //   - Validates alpha/beta coefficients and auto-detects trained-physics mode when coefficients are provided
+//   - For table: validates --step-time-table
 //   - For roofline/trained-physics: resolves model config folder and
 //     hardware config, loads coefficients from defaults.yaml, auto-calculates
 //     total-kv-blocks and max-model-len from the HF config
@@ -467,6 +643,23 @@ func resolveLatencyConfig(cmd *cobra.Command) latencyResolution {
 		logrus.Infof("--alpha-coeffs and --beta-coeffs provided; using trained-physics mode")
 	}
 
+	// --latency-model table: step times interpolated from a measured CSV grid.
+	// No model or hardware config is resolved, so --total-kv-blocks and
+	// --max-model-len keep their flag values.
+	if backend == "table" {
+		if stepTimeTablePath == "" {
+			logrus.Fatalf("--latency-model table requires --step-time-table")
+		}
+		if _, err := latency.LoadStepTimeTable(stepTimeTablePath); err != nil {
+			logrus.Fatalf("--step-time-table: %v", err)
+		}
+		if betaChanged {
+			logrus.Warnf("--latency-model table: --beta-coeffs are ignored; step times come from --step-time-table (--alpha-coeffs still apply)")
+		}
+	} else if stepTimeTablePath != "" {
+		logrus.Fatalf("--step-time-table requires --latency-model table (got --latency-model %s)", backend)
+	}
+
 	// Validate flags consumed inside this function before any KV auto-calc.
 	// gpuMemoryUtilization and blockSizeTokens are used in CalculateKVBlocks;
 	// validate here so errors are caught before computation rather than silently
@@ -606,13 +799,14 @@ func resolveLatencyConfig(cmd *cobra.Command) latencyResolution {
 			logrus.Fatalf("Failed to load model config: %v", err)
 		}
 		modelConfig = *mc
-		hc, err := latency.GetHWConfig(hwConfigPath, gpu)
+		hc, err := loadHardwareCalib(hwConfigPath, fleetInventoryPath, gpu)
 		if err != nil {
 			logrus.Fatalf("Failed to load hardware config: %v", err)
 		}
 		hwConfig = hc
 
 		applyWeightPrecisionFallback(&modelConfig, model, hfConfig.Raw)
+		applyDtypeOverrides(&modelConfig)
 
 		if backend == "roofline" && modelConfig.IsMoE() {
 			logrus.Infof("--latency-model: MoE model detected (%d experts, top_%d). "+
@@ -805,6 +999,47 @@ func composeLoRAScorer(base []sim.ScorerConfig, weight float64) ([]sim.ScorerCon
 	return composed, nil
 }
 
+// buildInstanceOverrides converts the policy bundle's instance_overrides section into
+// cluster.InstanceOverride entries for heterogeneous clusters. For analytical backends
+// (roofline, trained-physics), the hardware calibration of every overridden GPU type is
+// loaded from hwConfigPath so each instance is modeled on its own hardware.
+// Returns (nil, nil) when the bundle is nil or has no overrides (homogeneous cluster).
+func buildInstanceOverrides(bundle *sim.PolicyBundle, numInstances int, backend string) ([]cluster.InstanceOverride, map[string]sim.HardwareCalib) {
+	if bundle == nil || len(bundle.InstanceOverrides) == 0 {
+		return nil, nil
+	}
+	if len(bundle.InstanceOverrides) > numInstances {
+		logrus.Fatalf("policy bundle instance_overrides has %d entries but --num-instances=%d", len(bundle.InstanceOverrides), numInstances)
+	}
+	overrides := make([]cluster.InstanceOverride, len(bundle.InstanceOverrides))
+	var hwByGPU map[string]sim.HardwareCalib
+	for i, o := range bundle.InstanceOverrides {
+		overrides[i].GPU = o.GPU
+		overrides[i].TP = o.TP
+		overrides[i].TotalKVBlocks = o.TotalKVBlocks
+		if len(o.BetaCoeffs) > 0 {
+			coeffs := sim.NewLatencyCoeffs(o.BetaCoeffs, o.AlphaCoeffs)
+			overrides[i].LatencyCoeffs = &coeffs
+		}
+		if o.GPU == "" || (backend != "roofline" && backend != "trained-physics") {
+			continue
+		}
+		if _, loaded := hwByGPU[o.GPU]; loaded {
+			continue
+		}
+		hc, err := loadHardwareCalib(hwConfigPath, fleetInventoryPath, o.GPU)
+		if err != nil {
+			logrus.Fatalf("instance_overrides[%d]: failed to load hardware config for GPU %q: %v", i, o.GPU, err)
+		}
+		if hwByGPU == nil {
+			hwByGPU = make(map[string]sim.HardwareCalib)
+		}
+		hwByGPU[o.GPU] = hc
+	}
+	logrus.Infof("[cluster] heterogeneous deployment: %d of %d instances have per-instance overrides", len(overrides), numInstances)
+	return overrides, hwByGPU
+}
+
 // resolvePolicies resolves admission/routing/priority/scheduler policy configuration
 // from CLI flags and an optional policy bundle YAML file. It is called by both runCmd
 // and replayCmd to ensure a single validation code path (R23: code path parity).
@@ -816,7 +1051,7 @@ func composeLoRAScorer(base []sim.ScorerConfig, weight float64) ([]sim.ScorerCon
 //
 // Side effects: may write admissionPolicy, routingPolicy, scheduler,
 // tokenBucketCapacity, tokenBucketRefillRate, tierShedThreshold, tierShedMinPriority,
-// tenantBudgets package-level vars (from policy bundle).
+// tenantBudgets, sloTokenBuckets, tenantShares package-level vars (from policy bundle).
 //
 // Returns the parsed scorer configs for weighted routing (caller uses these in
 // DeploymentConfig.RoutingScorerConfigs) and the loaded policy bundle (nil if none).
@@ -875,14 +1110,27 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 		if bundle.Admission.GAIEKVThreshold != nil {
 			gaieKVThreshold = *bundle.Admission.GAIEKVThreshold
 		}
+		if bundle.Admission.SLOTokenBuckets != nil {
+			sloTokenBuckets = bundle.Admission.SLOTokenBuckets
+		}
+		if bundle.Admission.TenantShares != nil {
+			tenantShares = bundle.Admission.TenantShares
+		}
 		if bundle.Routing.Policy != "" && !cmd.Flags().Changed("routing-policy") {
 			routingPolicy = bundle.Routing.Policy
 		}
 		bundleScorerConfigs = bundle.Routing.Scorers
-		if bundle.Priority.Policy != "" && bundle.Priority.Policy != "constant" {
-			logrus.Warnf("bundle priority.policy=%q has no effect: --priority-policy was removed in PR #1216. "+
-				"Request priority is now static (set at enqueue via SLOPriorityMap.InvertForVLLM). "+
-				"Use SLOClass in your workload spec for tier-based priority.", bundle.Priority.Policy)
+		if bundle.Routing.Weights != nil && !cmd.Flags().Changed("routing-weights") {
+			routingInstanceWeights = bundle.Routing.Weights
+		}
+		if sim.IsValidPriorityPolicy(bundle.Priority.Policy) {
+			if bundle.Priority.Policy != "" && !cmd.Flags().Changed("priority-policy") {
+				priorityPolicy = bundle.Priority.Policy
+			}
+		} else if bundle.Priority.Policy != "constant" {
+			logrus.Warnf("bundle priority.policy=%q has no effect: the per-step priority policies were removed in PR #1216. "+
+				"Request priority is static, set at enqueue from the SLO class (slo-class) or the workload's explicit priority (explicit). "+
+				"Valid priority.policy values: %s", bundle.Priority.Policy, strings.Join(sim.ValidPriorityPolicyNames(), ", "))
 		}
 		if bundle.Scheduler != "" && !cmd.Flags().Changed("scheduler") {
 			scheduler = bundle.Scheduler
@@ -901,7 +1149,7 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 	}
 
 	// Policy name validation (R3: validate at CLI boundary before passing to library)
-	if admissionPolicy == "token-bucket" {
+	if admissionPolicy == "token-bucket" || admissionPolicy == "slo-token-bucket" {
 		if tokenBucketCapacity <= 0 || math.IsNaN(tokenBucketCapacity) || math.IsInf(tokenBucketCapacity, 0) {
 			logrus.Fatalf("--token-bucket-capacity must be a finite value > 0, got %v", tokenBucketCapacity)
 		}
@@ -923,18 +1171,72 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 	if !sim.IsValidRoutingPolicy(routingPolicy) {
 		logrus.Fatalf("Unknown routing policy %q. Valid: %s", routingPolicy, strings.Join(sim.ValidRoutingPolicyNames(), ", "))
 	}
+	if prefillRoutingPolicy != "" && !sim.IsValidRoutingPolicy(prefillRoutingPolicy) {
+		logrus.Fatalf("Unknown --prefill-routing-policy %q. Valid: %s", prefillRoutingPolicy, strings.Join(sim.ValidRoutingPolicyNames(), ", "))
+	}
+	if decodeRoutingPolicy != "" && !sim.IsValidRoutingPolicy(decodeRoutingPolicy) {
+		logrus.Fatalf("Unknown --decode-routing-policy %q. Valid: %s", decodeRoutingPolicy, strings.Join(sim.ValidRoutingPolicyNames(), ", "))
+	}
+	if cmd.Flags().Changed("routing-weights") {
+		routingInstanceWeights = nil
+		for _, field := range strings.Split(routingWeights, ",") {
+			w, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				logrus.Fatalf("Invalid --routing-weights %q: %v", routingWeights, err)
+			}
+			routingInstanceWeights = append(routingInstanceWeights, w)
+		}
+	}
+	staticWeighted := routingPolicy == "static-weighted" || prefillRoutingPolicy == "static-weighted" || decodeRoutingPolicy == "static-weighted"
+	if staticWeighted {
+		if len(routingInstanceWeights) != numInstances {
+			logrus.Fatalf("static-weighted routing needs one weight per instance (--routing-weights or bundle routing.weights): got %d weights for --num-instances=%d",
+				len(routingInstanceWeights), numInstances)
+		}
+		if err := sim.ValidateStaticRoutingWeights(routingInstanceWeights); err != nil {
+			logrus.Fatalf("Invalid routing weights: %v", err)
+		}
+	} else if routingInstanceWeights != nil {
+		logrus.Warnf("routing weights have no effect when routing policy is %q (only applies to 'static-weighted')", routingPolicy)
+	}
 	if !sim.IsValidScheduler(scheduler) {
 		logrus.Fatalf("Unknown scheduler %q. Valid: %s", scheduler, strings.Join(sim.ValidSchedulerNames(), ", "))
 	}
 	if !sim.IsValidPreemptionPolicy(preemptionPolicy) {
 		logrus.Fatalf("Unknown preemption policy %q. Valid: %s", preemptionPolicy, strings.Join(sim.ValidPreemptionPolicyNames(), ", "))
 	}
+	if !sim.IsValidPreemptionMode(preemptionMode) {
+		logrus.Fatalf("Unknown --preemption-mode %q. Valid: %s, %s", preemptionMode, sim.PreemptionModeRecompute, sim.PreemptionModeSwap)
+	}
+	if preemptionMode == sim.PreemptionModeSwap && (kvTransferBandwidth <= 0 || math.IsNaN(kvTransferBandwidth) || math.IsInf(kvTransferBandwidth, 0)) {
+		logrus.Fatalf("--kv-transfer-bandwidth must be a finite value > 0 with --preemption-mode swap, got %f", kvTransferBandwidth)
+	}
+	if swapSpaceBlocks < 0 {
+		logrus.Fatalf("--swap-space-blocks must be >= 0, got %d", swapSpaceBlocks)
+	}
+	if !sim.IsValidPriorityPolicy(priorityPolicy) {
+		logrus.Fatalf("Unknown priority policy %q. Valid: %s", priorityPolicy, strings.Join(sim.ValidPriorityPolicyNames(), ", "))
+	}
+	if priorityPolicy == sim.PriorityPolicyExplicit && scheduler != "priority-fcfs" && scheduler != "reverse-priority" &&
+		preemptionPolicy != "priority" && preemptionPolicy != "priority-admission" && preemptionPolicy != "priority-inheritance" {
+		logrus.Warnf("--priority-policy explicit has no effect with --scheduler %s and --preemption-policy %s; "+
+			"use --scheduler priority-fcfs to order the wait queue by explicit priority", scheduler, preemptionPolicy)
+	}
 	if !trace.IsValidTraceLevel(traceLevel) {
 		logrus.Fatalf("Unknown trace level %q. Valid: none, decisions", traceLevel)
 	}
 	if counterfactualK < 0 {
 		logrus.Fatalf("--counterfactual-k must be >= 0, got %d", counterfactualK)
 	}
+	if routingLogPath != "" && traceLevel != string(trace.TraceLevelDecisions) {
+		logrus.Fatalf("--routing-log-output requires --trace-level decisions")
+	}
+	if prefillRoutingPolicy == "replay" || decodeRoutingPolicy == "replay" {
+		logrus.Fatalf("replay routing is supported for --routing-policy only, not the PD pool routing policies")
+	}
+	if (routingPolicy == "replay") != (routingReplayLogPath != "") {
+		logrus.Fatalf("--routing-policy replay and --routing-replay-log must be set together")
+	}
 	if traceLevel == "none" && counterfactualK > 0 {
 		logrus.Warnf("--counterfactual-k=%d has no effect without --trace-level decisions", counterfactualK)
 	}
@@ -967,9 +1269,134 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 	if admissionLatency < 0 {
 		logrus.Fatalf("--admission-latency must be >= 0, got %d", admissionLatency)
 	}
+	if !cluster.IsValidAdmissionLatencyDist(admissionLatencyDist) {
+		logrus.Fatalf("Unknown --admission-latency-dist %q. Valid: %s, %s, %s (empty = fixed latency)",
+			admissionLatencyDist, cluster.AdmissionLatencyDistGaussian, cluster.AdmissionLatencyDistLognormal, cluster.AdmissionLatencyDistExponential)
+	}
+	if admissionLatencyStd < 0 || math.IsNaN(admissionLatencyStd) || math.IsInf(admissionLatencyStd, 0) {
+		logrus.Fatalf("--admission-latency-stddev must be a finite value >= 0, got %v", admissionLatencyStd)
+	}
 	if routingLatency < 0 {
 		logrus.Fatalf("--routing-latency must be >= 0, got %d", routingLatency)
 	}
+	if faultInstance != "" {
+		if err := (cluster.FaultInjectionConfig{InstanceID: faultInstance, AtUs: faultAt, Mode: faultMode}).Validate(); err != nil {
+			logrus.Fatalf("--fault-*: %v", err)
+		}
+	}
+	if remotePrefixFetchUsPerBlock < 0 || math.IsNaN(remotePrefixFetchUsPerBlock) || math.IsInf(remotePrefixFetchUsPerBlock, 0) {
+		logrus.Fatalf("--remote-prefix-fetch-us-per-block must be a finite value >= 0, got %v", remotePrefixFetchUsPerBlock)
+	}
+	if retryMaxAttempts < 0 {
+		logrus.Fatalf("--retry-max-attempts must be >= 0, got %d", retryMaxAttempts)
+	}
+	if retryMaxAttempts > 0 && retryBackoff <= 0 {
+		logrus.Fatalf("--retry-backoff must be > 0 when --retry-max-attempts is set, got %d", retryBackoff)
+	}
+	if maxInFlightTokens < 0 {
+		logrus.Fatalf("--max-inflight-tokens must be >= 0, got %d", maxInFlightTokens)
+	}
+	if kvPressureThreshold < 0 || kvPressureThreshold >= 1 || math.IsNaN(kvPressureThreshold) {
+		logrus.Fatalf("--kv-pressure-threshold must be in [0, 1), got %v", kvPressureThreshold)
+	}
+	if detokenizationUsPerToken < 0 || math.IsNaN(detokenizationUsPerToken) || math.IsInf(detokenizationUsPerToken, 0) {
+		logrus.Fatalf("--detokenization-us-per-token must be a finite value >= 0, got %v", detokenizationUsPerToken)
+	}
+	if maxOutputTokens < 0 {
+		logrus.Fatalf("--max-output-tokens must be >= 0, got %d", maxOutputTokens)
+	}
+	if tokensPerDecodeStep < 0 {
+		logrus.Fatalf("--tokens-per-decode-step must be >= 0, got %d", tokensPerDecodeStep)
+	}
+	if decodeLengthBuckets < 0 {
+		logrus.Fatalf("--decode-length-buckets must be >= 0, got %d", decodeLengthBuckets)
+	}
+	if decodeQuantumSteps < 0 {
+		logrus.Fatalf("--decode-quantum-steps must be >= 0, got %d", decodeQuantumSteps)
+	}
+	if !(criticalReserveFraction >= 0 && criticalReserveFraction < 1) {
+		logrus.Fatalf("--critical-reserve-fraction must be in [0, 1), got %v", criticalReserveFraction)
+	}
+	if adaptivePrefillChunkMin < 0 {
+		logrus.Fatalf("--adaptive-prefill-chunk-min must be >= 0, got %d", adaptivePrefillChunkMin)
+	}
+	if !sim.IsValidStepOrdering(stepOrdering) {
+		logrus.Fatalf("--step-ordering must be one of %v, got %q", sim.ValidStepOrderingNames(), stepOrdering)
+	}
+	if !sim.IsValidRandSource(randSource) {
+		logrus.Fatalf("--rand-source must be one of %v, got %q", sim.ValidRandSourceNames(), randSource)
+	}
+	for _, w := range []struct {
+		flag  string
+		watts float64
+	}{{"--power-idle-watts", powerIdleWatts}, {"--power-peak-watts", powerPeakWatts}, {"--power-cap-watts", powerCapWatts}} {
+		if w.watts < 0 || math.IsNaN(w.watts) || math.IsInf(w.watts, 0) {
+			logrus.Fatalf("%s must be a finite value >= 0, got %v", w.flag, w.watts)
+		}
+	}
+	if powerPeakWatts > 0 && powerPeakWatts <= powerIdleWatts {
+		logrus.Fatalf("--power-peak-watts (%v) must exceed --power-idle-watts (%v)", powerPeakWatts, powerIdleWatts)
+	}
+	if powerCapWatts > 0 && (powerPeakWatts <= 0 || powerCapWatts <= powerIdleWatts) {
+		logrus.Fatalf("--power-cap-watts requires --power-peak-watts > 0 and a cap above --power-idle-watts (%v), got %v", powerIdleWatts, powerCapWatts)
+	}
+	if schedulingOverheadUs < 0 || math.IsNaN(schedulingOverheadUs) || math.IsInf(schedulingOverheadUs, 0) {
+		logrus.Fatalf("--scheduling-overhead-us-per-seq must be a finite value >= 0, got %v", schedulingOverheadUs)
+	}
+	if schedulingContentionUs < 0 || math.IsNaN(schedulingContentionUs) || math.IsInf(schedulingContentionUs, 0) {
+		logrus.Fatalf("--scheduling-contention-us-per-seq-sq must be a finite value >= 0, got %v", schedulingContentionUs)
+	}
+	if minStepTimeUs < 0 {
+		logrus.Fatalf("--min-step-time-us must be >= 0, got %d", minStepTimeUs)
+	}
+	if math.IsNaN(stepNoise) || stepNoise < 0 || stepNoise > 0.5 {
+		logrus.Fatalf("--step-noise must be in [0, 0.5], got %v", stepNoise)
+	}
+	if math.IsNaN(stepNoiseCorrelation) || stepNoiseCorrelation < 0 || stepNoiseCorrelation >= 1 {
+		logrus.Fatalf("--step-noise-correlation must be in [0, 1), got %v", stepNoiseCorrelation)
+	}
+	if stopAfterCompleted < 0 {
+		logrus.Fatalf("--stop-after-completed must be >= 0, got %d", stopAfterCompleted)
+	}
+	if throughputSampleInterval < 0 {
+		logrus.Fatalf("--throughput-sample-interval must be >= 0, got %d", throughputSampleInterval)
+	}
+	if kvSampleInterval < 0 {
+		logrus.Fatalf("--kv-sample-interval must be >= 0, got %d", kvSampleInterval)
+	}
+	if !sim.IsValidKVAllocationMode(kvAllocationMode) {
+		logrus.Fatalf("Unknown KV allocation mode %q. Valid: %s", kvAllocationMode, strings.Join(sim.ValidKVAllocationModeNames(), ", "))
+	}
+	if kvFairShareMaxBlocks < 0 {
+		logrus.Fatalf("--kv-fair-share-max-blocks must be >= 0, got %d", kvFairShareMaxBlocks)
+	}
+	if !sim.IsValidComputeDtype(computeDtype) {
+		logrus.Fatalf("Unknown --compute-dtype %q. Valid: %s", computeDtype, strings.Join(sim.ValidComputeDtypeNames(), ", "))
+	}
+	if _, ok := kvCacheDtypeBytes[kvCacheDtype]; !ok {
+		logrus.Fatalf("Unknown --kv-cache-dtype %q. Valid: %s", kvCacheDtype, strings.Join(validKVCacheDtypeNames(), ", "))
+	}
+	if maxQueueDepth < 0 {
+		logrus.Fatalf("--max-queue-depth must be >= 0, got %d", maxQueueDepth)
+	}
+	if maxQueueWait < 0 {
+		logrus.Fatalf("--max-queue-wait must be >= 0, got %d", maxQueueWait)
+	}
+	if minBatchFill < 0 {
+		logrus.Fatalf("--min-batch-fill must be >= 0, got %d", minBatchFill)
+	}
+	if batchFillMaxWait < 0 {
+		logrus.Fatalf("--batch-fill-max-wait must be >= 0, got %d", batchFillMaxWait)
+	}
+	if !sim.IsValidQueueOverflowPolicy(queueOverflowPolicy) {
+		logrus.Fatalf("Unknown queue overflow policy %q. Valid: %s", queueOverflowPolicy, strings.Join(sim.ValidQueueOverflowPolicyNames(), ", "))
+	}
+	if warmupSteps < 0 {
+		logrus.Fatalf("--warmup-steps must be >= 0, got %d", warmupSteps)
+	}
+	if warmupFactor < 1 || math.IsNaN(warmupFactor) || math.IsInf(warmupFactor, 0) {
+		logrus.Fatalf("--warmup-factor must be a finite value >= 1, got %f", warmupFactor)
+	}
 	// Flow control validation (R3: validate at CLI boundary before passing to library)
 	if flowControlEnabled {
 		if !sim.IsValidSaturationDetector(flowControlDetector) {
@@ -1089,6 +1516,16 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 	if routingPolicy != "weighted" && routingScorers != "" {
 		logrus.Warnf("--routing-scorers has no effect when routing policy is %q (only applies to 'weighted')", routingPolicy)
 	}
+	if !cluster.IsValidRoutingTieBreak(routingTieBreak) {
+		logrus.Fatalf("Unknown --routing-tie-break %q. Valid: %s, %s",
+			routingTieBreak, cluster.RoutingTieBreakRandom, cluster.RoutingTieBreakLowestIndex)
+	}
+	if prefixHashSkipTokens < 0 {
+		logrus.Fatalf("--prefix-hash-skip-tokens must be >= 0, got %d", prefixHashSkipTokens)
+	}
+	if routingPolicy != "weighted" && prefixHashSkipTokens > 0 {
+		logrus.Warnf("--prefix-hash-skip-tokens has no effect when routing policy is %q (only applies to the prefix-affinity scorer of 'weighted')", routingPolicy)
+	}
 	if routingPolicy != "weighted" && cmd.Flags().Changed("lora-scorer-weight") {
 		logrus.Warnf("--lora-scorer-weight has no effect when routing policy is %q (only applies to 'weighted')", routingPolicy)
 	}
@@ -1104,11 +1541,16 @@ func resolvePolicies(cmd *cobra.Command) ([]sim.ScorerConfig, *sim.PolicyBundle)
 // duplicating ~50 flag registrations.
 func registerSimConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().Int64Var(&seed, "seed", 42, "Seed for random request generation")
+	cmd.Flags().StringVar(&randSource, "rand-source", sim.RandSourceStdlib, "Bit generator behind every RNG stream: stdlib (Go's math/rand, default) or xoshiro256ss (vendored, bit-identical across Go versions and platforms)")
 	cmd.Flags().Int64Var(&simulationHorizon, "horizon", math.MaxInt64, "Total simulation horizon (in ticks)")
 	cmd.Flags().StringVar(&logLevel, "log", "warn", "Log level for diagnostic messages (trace, debug, info, warn, error, fatal, panic). Simulation results always print to stdout regardless of this setting.")
+	cmd.Flags().BoolVar(&printDiagnostics, "print-diagnostics", false, "Print the run's primary bottleneck (also in the --metrics-path JSON) and running-batch size distribution after the results")
 	cmd.Flags().StringVar(&defaultsFilePath, "defaults-filepath", "defaults.yaml", "Path to default constants - trained coefficients, default specs and workloads")
 	cmd.Flags().StringVar(&modelConfigFolder, "model-config-folder", "", "Path to folder containing config.json")
 	cmd.Flags().StringVar(&hwConfigPath, "hardware-config", "", "Path to file containing hardware config")
+	cmd.Flags().StringVar(&fleetInventoryPath, "fleet-inventory", "", "Path to a JSON fleet inventory whose per-GPU peak TFLOPs, bandwidth, and memory override --hardware-config entries")
+	cmd.Flags().StringVar(&computeDtype, "compute-dtype", "", "GEMM precision selecting the roofline peak FLOPs: "+strings.Join(sim.ValidComputeDtypeNames(), ", ")+" (empty = infer from weight precision)")
+	cmd.Flags().StringVar(&kvCacheDtype, "kv-cache-dtype", "auto", "KV cache precision for KV memory traffic, capacity, and PD transfer sizing: "+strings.Join(validKVCacheDtypeNames(), ", ")+" (auto = model compute dtype)")
 
 	// vLLM server configs
 	cmd.Flags().Int64Var(&totalKVBlocks, "total-kv-blocks", 1000000, "Total number of KV cache blocks")
@@ -1118,6 +1560,35 @@ func registerSimConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().Float64SliceVar(&alphaCoeffs, "alpha-coeffs", []float64{0.0, 0.0, 0.0}, "Comma-separated alpha coefficients (alpha0,alpha1) for processing delays")
 	cmd.Flags().Int64Var(&blockSizeTokens, "block-size-in-tokens", 16, "Number of tokens contained in a KV cache block")
 	cmd.Flags().Int64Var(&longPrefillTokenThreshold, "long-prefill-token-threshold", 0, "Max length of prefill beyond which chunked prefill is triggered")
+	cmd.Flags().Float64Var(&kvPressureThreshold, "kv-pressure-threshold", 0, "KV utilization fraction above which new admissions are throttled proportionally to the remaining headroom (0 = disabled)")
+	cmd.Flags().StringVar(&kvAllocationMode, "kv-allocation-mode", sim.KVAllocationGreedy, "Per-request KV block allocation: "+strings.Join(sim.ValidKVAllocationModeNames(), ", ")+". fair-share caps each request at a fair share of the cache under contention")
+	cmd.Flags().Int64Var(&kvFairShareMaxBlocks, "kv-fair-share-max-blocks", 0, "Fixed per-request KV block cap for --kv-allocation-mode=fair-share (0 = total blocks / running requests)")
+	cmd.Flags().Float64Var(&detokenizationUsPerToken, "detokenization-us-per-token", 0, "CPU detokenization cost in microseconds per output token, added to E2E but not to GPU step time (0 = disabled)")
+	cmd.Flags().IntVar(&maxOutputTokens, "max-output-tokens", 0, "Server-side output length cap: requests sampling more output tokens are truncated to it and finish with completion_reason \"length\" (0 = disabled)")
+	cmd.Flags().Float64Var(&powerIdleWatts, "power-idle-watts", 0, "Per-instance power draw in watts of a step with no scheduled tokens (power model; see --power-peak-watts)")
+	cmd.Flags().Float64Var(&powerPeakWatts, "power-peak-watts", 0, "Per-instance power draw in watts of a step using the full token budget; enables the power model and energy_joules output (0 = disabled)")
+	cmd.Flags().Float64Var(&powerCapWatts, "power-cap-watts", 0, "Per-instance power cap in watts: steps whose modeled draw exceeds it are clock-throttled, stretching compute time (0 = unlimited)")
+	cmd.Flags().IntVar(&tokensPerDecodeStep, "tokens-per-decode-step", 0, "Output tokens each decoding request generates per forward pass (deterministic multi-token decode, not speculative; 0 or 1 = one token per step)")
+	cmd.Flags().IntVar(&decodeLengthBuckets, "decode-length-buckets", 0, "Model padded decode attention, splitting each step's decode requests into up to this many context-length buckets charged as separate padded passes (1 = one fully padded pass; 0 = unpadded)")
+	cmd.Flags().IntVar(&decodeQuantumSteps, "decode-quantum-steps", 0, "Time-slice decode slots: every this many steps, reorder the running batch by least output tokens decoded and suspend the most-served decode requests (keeping their KV) so waiting requests get their slots (0 = disabled)")
+	cmd.Flags().Float64Var(&criticalReserveFraction, "critical-reserve-fraction", 0, "Fraction of --max-num-running-reqs and --max-num-scheduled-tokens held back for critical-class requests; others are admitted only within the rest, and queued critical requests go first (0 = disabled)")
+	cmd.Flags().Int64Var(&adaptivePrefillChunkMin, "adaptive-prefill-chunk-min", 0, "Adaptive chunked prefill: size each step's prefill chunks as --max-num-scheduled-tokens scaled by the non-decoding share of the running batch, never below this many tokens; replaces --long-prefill-token-threshold (0 = disabled)")
+	cmd.Flags().StringVar(&stepOrdering, "step-ordering", sim.StepOrderingDecodeFirst, "Which work each step's token budget goes to first when it is tight: decode-first (running requests, then new prefills; vLLM's order, favors ITL) or prefill-first (prefill chunks and new admissions, then decodes with what is left; favors TTFT)")
+	cmd.Flags().Float64Var(&schedulingOverheadUs, "scheduling-overhead-us-per-seq", 0, "CPU scheduling cost in microseconds per sequence in the batch, added to every step time (0 = disabled)")
+	cmd.Flags().Float64Var(&schedulingContentionUs, "scheduling-contention-us-per-seq-sq", 0, "Scheduling contention cost in microseconds per sequence squared, added to every step time; makes throughput peak at an interior batch size (0 = disabled)")
+	cmd.Flags().Int64Var(&minStepTimeUs, "min-step-time-us", 0, "Minimum forward-pass time of every step in microseconds, modeling fixed kernel launch overhead that tiny batches still pay; applied before --scheduling-overhead-us-per-seq (0 = no floor)")
+	cmd.Flags().Float64Var(&stepNoise, "step-noise", 0, "Multiply every step time by a seeded AR(1) noise factor with mean 1 and this standard deviation, in [0, 0.5] (0 = deterministic step times)")
+	cmd.Flags().Float64Var(&stepNoiseCorrelation, "step-noise-correlation", 0, "Lag-1 correlation of the --step-noise process, in [0, 1): 0 = independent per step, near 1 = slowly drifting jitter")
+	cmd.Flags().Int64Var(&stopAfterCompleted, "stop-after-completed", 0, "Halt once this many requests have completed, leaving queued and running requests unfinished (0 = disabled). In blis run, arrivals are generated without bound unless --num-requests, num_requests, or --horizon is set")
+	cmd.Flags().Int64Var(&throughputSampleInterval, "throughput-sample-interval", 0, "Record the cumulative completed-request count every this many microseconds, per instance and cluster-wide, as completed_series in the metrics output (0 = disabled)")
+	cmd.Flags().Int64Var(&kvSampleInterval, "kv-sample-interval", 0, "Record the most KV blocks in use during each interval of this many microseconds, per instance, as kv_used_series in the metrics output (0 = disabled)")
+	cmd.Flags().IntVar(&warmupSteps, "warmup-steps", 0, "Number of initial steps on each fresh instance whose step time is inflated by a decaying cold-start penalty (0 = disabled)")
+	cmd.Flags().Float64Var(&warmupFactor, "warmup-factor", 1.0, "Step-time multiplier applied to an instance's first step; decays linearly to 1.0 over --warmup-steps (must be >= 1)")
+	cmd.Flags().IntVar(&maxQueueDepth, "max-queue-depth", 0, "Per-instance wait queue bound (0 = unbounded). Arrivals at a full queue are handled by --queue-overflow-policy")
+	cmd.Flags().Int64Var(&maxQueueWait, "max-queue-wait", 0, "Anti-starvation deadline in microseconds: a queued request that has waited this long since arrival is moved to the front of the wait queue, ahead of the --scheduler order (0 = disabled)")
+	cmd.Flags().IntVar(&minBatchFill, "min-batch-fill", 0, "Idle-instance batch fill target: with fewer than this many requests queued, an idle instance waits up to --batch-fill-max-wait for more before launching a step (0 = disabled)")
+	cmd.Flags().Int64Var(&batchFillMaxWait, "batch-fill-max-wait", 0, "Longest, in microseconds since arrival, the oldest queued request is held waiting for --min-batch-fill requests")
+	cmd.Flags().StringVar(&queueOverflowPolicy, "queue-overflow-policy", sim.QueueOverflowRejectNew, "Policy when the wait queue is at --max-queue-depth: "+strings.Join(sim.ValidQueueOverflowPolicyNames(), ", "))
 
 	// BLIS model configs
 	cmd.Flags().StringVar(&model, "model", "", "LLM name")
@@ -1126,27 +1597,46 @@ func registerSimConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVar(&dataParallelism, "dp", 1, "Data parallelism degree (MoE models only; --latency-model trained-physics only)")
 	cmd.Flags().BoolVar(&enableExpertParallel, "enable-expert-parallel", false, "Enable expert parallelism for MoE models (mirrors vLLM --enable-expert-parallel; --latency-model trained-physics only)")
 	cmd.Flags().StringVar(&moeCommBackend, "moe-comm-backend", "", "MoE all-to-all comm backend for dispatch/combine cost (mirrors vLLM VLLM_ALL2ALL_BACKEND: naive, allgather_reducescatter [default], pplx, deepep_high_throughput, deepep_low_latency, mori, flashinfer_all2allv; MoE + --latency-model trained-physics + --dp > 1)")
-	cmd.Flags().StringVar(&latencyModelBackend, "latency-model", "trained-physics", "Latency model backend: trained-physics (default), roofline")
+	cmd.Flags().StringVar(&latencyModelBackend, "latency-model", "trained-physics", "Latency model backend: trained-physics (default), roofline, table")
+	cmd.Flags().BoolVar(&rooflineBlockTable, "roofline-block-table", false, "Charge paged-attention block-table reads (one entry per KV block of context, per layer) in roofline step time; grows with context length (--latency-model roofline only)")
+	cmd.Flags().BoolVar(&rooflineAccounting, "roofline-accounting", false, "Record each roofline step's FLOPs and memory bytes and report compute-bound/memory-bound step fractions, mean arithmetic intensity and effective MFU in the metrics output (--latency-model roofline only)")
+	cmd.Flags().StringVar(&stepTimeTablePath, "step-time-table", "", "CSV of measured step times (columns batch_tokens, context_len, step_time_us) interpolated by --latency-model table")
 	cmd.Flags().Int64Var(&maxModelLen, "max-model-len", 0, "Max total sequence length (input + output); 0 = unlimited. Auto-derived from HF config for analytical backends when not set.")
 
 	// Cluster config
 	cmd.Flags().IntVar(&numInstances, "num-instances", 1, "Number of instances in the cluster")
+	cmd.Flags().StringVar(&faultInstance, "fault-instance", "", "Instance ID to fail mid-run for resilience studies (e.g. instance_1); empty = no fault injection")
+	cmd.Flags().Int64Var(&faultAt, "fault-at", 0, "Simulated time of the injected instance failure in microseconds")
+	cmd.Flags().StringVar(&faultMode, "fault-mode", cluster.FaultModeLose, "Fate of the failed instance's in-flight requests: lose (counted as failed), requeue (re-routed to surviving instances)")
+	cmd.Flags().BoolVar(&sharedPrefixCache, "shared-prefix-cache", false, "Share prefix cache hits across instances via a cluster-wide prefix index (models a distributed KV store)")
+	cmd.Flags().Float64Var(&remotePrefixFetchUsPerBlock, "remote-prefix-fetch-us-per-block", 0, "Step-time cost in microseconds per KV block fetched from another instance's cache with --shared-prefix-cache")
 
 	// Online routing pipeline config
 	cmd.Flags().StringVar(&admissionPolicy, "admission-policy", "always-admit", "Admission policy: "+strings.Join(sim.ValidAdmissionPolicyNames(), ", "))
 	cmd.Flags().Int64Var(&admissionLatency, "admission-latency", 0, "Admission latency in microseconds")
+	cmd.Flags().StringVar(&admissionLatencyDist, "admission-latency-dist", "", "Sample per-request admission latency with mean --admission-latency: gaussian, lognormal, exponential (empty = fixed latency)")
+	cmd.Flags().Float64Var(&admissionLatencyStd, "admission-latency-stddev", 0, "Admission latency standard deviation in microseconds for --admission-latency-dist gaussian or lognormal")
 	cmd.Flags().Int64Var(&routingLatency, "routing-latency", 0, "Routing latency in microseconds")
 	cmd.Flags().Float64Var(&tokenBucketCapacity, "token-bucket-capacity", 10000, "Token bucket capacity")
 	cmd.Flags().Float64Var(&tokenBucketRefillRate, "token-bucket-refill-rate", 1000, "Token bucket refill rate (tokens/second)")
+	cmd.Flags().IntVar(&retryMaxAttempts, "retry-max-attempts", 0, "Max times an admission-rejected request retries after exponential backoff (0 = rejection is final)")
+	cmd.Flags().Int64Var(&retryBackoff, "retry-backoff", 100_000, "Base admission retry backoff in microseconds; retry k waits backoff*2^(k-1) with ±50% jitter")
+	cmd.Flags().Int64Var(&maxInFlightTokens, "max-inflight-tokens", 0, "Cluster-wide cap on in-flight tokens (input + declared output budget over admitted, unfinished requests); arrivals that would exceed it are rejected at admission, or delayed with --retry-max-attempts (0 = no cap)")
 
 	// Routing policy config
-	cmd.Flags().StringVar(&routingPolicy, "routing-policy", "round-robin", "Routing policy: round-robin, least-loaded, weighted, always-busiest")
+	cmd.Flags().StringVar(&routingPolicy, "routing-policy", "round-robin", "Routing policy: round-robin, least-loaded, decode-load, weighted, always-busiest, static-weighted, session-affinity, replay")
 	cmd.Flags().StringVar(&routingScorers, "routing-scorers", "", "Scorer weights for weighted routing (e.g., queue-depth:2,kv-utilization:2,load-balance:1). Default: precise-prefix-cache:2,queue-depth:1,kv-utilization:1")
 	cmd.Flags().Float64Var(&loraScorerWeight, "lora-scorer-weight", 0, "Weight of the lora-affinity routing scorer, composed into the weighted profile. Leave unset to keep routing unchanged; must be a finite positive number when set. Requires --routing-policy weighted (#1469)")
+	cmd.Flags().StringVar(&routingTieBreak, "routing-tie-break", cluster.RoutingTieBreakRandom, "How least-loaded, decode-load, weighted and session-affinity routing pick among instances tied on load or score: random (seeded router RNG), lowest-index (first tied instance, independent of the RNG)")
+	cmd.Flags().Int64Var(&prefixHashSkipTokens, "prefix-hash-skip-tokens", 0, "Leading input tokens (e.g. a shared system prompt) the prefix-affinity routing scorer leaves out of its block hashes, so affinity keys on the user-specific part of the prompt. Router-side only; the KV cache still hashes the full prompt (0 = hash all)")
+	cmd.Flags().StringVar(&routingWeights, "routing-weights", "", "Per-instance weights for static-weighted routing, one per instance in index order (e.g., 3,1 sends ~75% to instance_0). 0 = never route")
 
 	// Scheduler and preemption config
-	cmd.Flags().StringVar(&scheduler, "scheduler", "fcfs", "Instance scheduler: fcfs, priority-fcfs, sjf, reverse-priority")
-	cmd.Flags().StringVar(&preemptionPolicy, "preemption-policy", "fcfs", "Preemption victim selection: fcfs (tail-of-batch), priority (least-urgent SLO tier)")
+	cmd.Flags().StringVar(&scheduler, "scheduler", "fcfs", "Instance scheduler: fcfs, priority-fcfs, sjf, reverse-priority, prefix-pack, wfq")
+	cmd.Flags().StringVar(&preemptionPolicy, "preemption-policy", "fcfs", "Preemption victim selection: fcfs (tail-of-batch), priority (least-urgent SLO tier), priority-admission (priority, plus waiting requests may evict less-urgent running requests when KV is full), priority-inheritance (priority, but the running request blocking the most urgent waiter is ranked at its priority)")
+	cmd.Flags().StringVar(&preemptionMode, "preemption-mode", sim.PreemptionModeRecompute, "What preemption does to the victim's KV cache: recompute (discard it and re-prefill from scratch), swap (copy it to host memory and back at --kv-transfer-bandwidth blocks/tick plus --kv-transfer-base-latency, keeping progress)")
+	cmd.Flags().Int64Var(&swapSpaceBlocks, "swap-space-blocks", 0, "Host memory for --preemption-mode swap, in KV blocks: a victim that does not fit beside the requests already swapped out is recomputed instead (0 = unbounded)")
+	cmd.Flags().StringVar(&priorityPolicy, "priority-policy", "slo-class", "Source of instance-level request priority: slo-class (from the request's SLO class), explicit (the workload's numeric priority, higher first)")
 
 	// Policy bundle config
 	cmd.Flags().StringVar(&policyConfigPath, "policy-config", "", "Path to YAML policy configuration file")
@@ -1164,6 +1654,8 @@ func registerSimConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().Float64Var(&kvOffloadThreshold, "kv-offload-threshold", 0.9, "GPU utilization (0-1) above which blocks are offloaded to CPU. Default: offload when GPU >90% full")
 	cmd.Flags().Float64Var(&kvTransferBandwidth, "kv-transfer-bandwidth", 100.0, "CPU↔GPU transfer rate in blocks per tick. Higher = faster transfers")
 	cmd.Flags().Int64Var(&kvTransferBaseLatency, "kv-transfer-base-latency", 0, "Fixed per-transfer latency in ticks for CPU↔GPU KV transfers (0 = no fixed cost)")
+	cmd.Flags().BoolVar(&kvRefCountChecks, "kv-refcount-checks", false, "Debug: verify KV block reference counts on every release and panic with the request ID on a double free or a freed block still referenced by another request (slow)")
+	cmd.Flags().BoolVar(&kvOffloadHostContention, "kv-offload-host-contention", false, "Share host-memory bandwidth among instances on the same node: concurrent CPU↔GPU KV transfers take proportionally longer")
 	cmd.Flags().Int64Var(&snapshotRefreshInterval, "snapshot-refresh-interval", 50000, "Prometheus snapshot refresh interval for all instance metrics in microseconds (0 = immediate/oracle mode, default 50ms = llm-d parity)")
 	cmd.Flags().Int64Var(&cacheSignalDelay, "cache-signal-delay", cluster.DefaultCacheSignalDelay, "Propagation delay for prefix cache signals in microseconds. Only affects precise-prefix-cache and no-hit-lru scorers; no effect on other routing policies. Default 50ms. Set to 0 for oracle mode (live cache state).")
 	cmd.Flags().Float64Var(&modelAutoscalerIntervalUs, "model-autoscaler-interval-us", 0, "Autoscaler tick interval in microseconds (0 = disabled). Overrides policy-config autoscaler.interval_us when non-zero.")
@@ -1180,6 +1672,8 @@ func registerSimConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVar(&pdPrefixThreshold, "pd-prefix-threshold", 16, "Non-cached token threshold for prefix-threshold decider (>= 0); disaggregate when non-cached tokens exceed this value. Default 16 matches llm-d's shipped P/D configs (deploy/config/pd-epp-config.yaml).")
 	cmd.Flags().StringVar(&prefillRoutingScorers, "prefill-routing-scorers", "", "Scorer weights for prefill pool routing (e.g., queue-depth:2,kv-utilization:2)")
 	cmd.Flags().StringVar(&decodeRoutingScorers, "decode-routing-scorers", "", "Scorer weights for decode pool routing (e.g., queue-depth:2,kv-utilization:2)")
+	cmd.Flags().StringVar(&prefillRoutingPolicy, "prefill-routing-policy", "", "Routing policy for prefill pool (empty = weighted if --prefill-routing-scorers is set, else --routing-policy)")
+	cmd.Flags().StringVar(&decodeRoutingPolicy, "decode-routing-policy", "", "Routing policy for decode pool (empty = weighted if --decode-routing-scorers is set, else --routing-policy)")
 
 	// E/P/D disaggregation (GAP-4, issue #1264). Registered on both run and replay.
 	cmd.Flags().IntVar(&encodeInstances, "encode-instances", 0, "Number of instances dedicated to encoding multimodal input (0 = encode pool disabled, default)")
@@ -1434,142 +1928,176 @@ var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run the inference simulation",
 	Run: func(cmd *cobra.Command, args []string) {
-		// Set up logging
-		level, err := logrus.ParseLevel(logLevel)
+		if replications < 1 {
+			logrus.Fatalf("--replications must be >= 1, got %d", replications)
+		}
+		rc := runConfig{seed: seed, seedExplicit: cmd.Flags().Changed("seed"), rate: rate}
+		if replications == 1 {
+			runSimulation(cmd, rc)
+			return
+		}
+		// Each replication re-enters the single-run path and would overwrite
+		// the same output files; refuse rather than silently keep only the last.
+		if metricsPath != "" || traceOutput != "" || saturationReport != "" || eventLogPath != "" || kvStatePath != "" || kvExportPath != "" || otlpTracePath != "" || routingLogPath != "" {
+			logrus.Fatalf("--replications > 1 cannot be combined with --metrics-path, --trace-output, --saturation-report, --event-log, --dump-kv-state, --kv-export-state, --otlp-trace, or --routing-log-output")
+		}
+		summary := runReplications(replications, seed, func(s int64) sim.MetricsOutput {
+			_, _ = fmt.Fprintf(os.Stdout, "=== Replication (seed=%d) ===\n", s)
+			// Every replication's seed overrides a workload-spec seed, not just the first.
+			return runSimulation(cmd, runConfig{seed: s, seedExplicit: true, rate: rate})
+		})
+		printReplicationSummary(os.Stdout, summary)
+	},
+}
+
+// runConfig is the per-run configuration that varies across --replications.
+// Everything else a run reads comes from the flag globals.
+type runConfig struct {
+	seed         int64   // workload and simulation RNG seed
+	seedExplicit bool    // seed overrides a workload-spec seed (CLI --seed given)
+	rate         float64 // arrival rate (req/s) of synthesized workloads
+}
+
+// runSimulation executes a single `blis run` end to end — config resolution,
+// workload generation, cluster simulation, and all stdout/file reporting — and
+// returns the aggregate cluster MetricsOutput. Called once per replication.
+func runSimulation(cmd *cobra.Command, rc runConfig) sim.MetricsOutput {
+	// Set up logging
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		logrus.Fatalf("Invalid log level: %s", logLevel)
+	}
+	logrus.SetLevel(level)
+
+	if model == "" { // model not provided, exit
+		logrus.Fatalf("LLM name not provided. Exiting simulation.")
+	}
+
+	// LoRA control-plane (#1464): resolve the config ONCE here (R4 single site) so
+	// both the KV auto-capacity path — resolveLatencyConfig and the per-pool calc
+	// below read the resulting static HBM reservation (PR5) — and the SimConfig
+	// literal further down share one resolution. The reservation is 0 (KV
+	// unaffected) when the subsystem is inert (INV-6). Set before resolveLatencyConfig.
+	loraCfg := resolveLoRAConfig(cmd)
+	loraReservedBytesForKV = adapterReservedBytesFor(loraCfg)
+
+	// Resolve latency backend configuration (single code path shared with replayCmd).
+	lr := resolveLatencyConfig(cmd)
+
+	// PD disaggregation requires ModelConfig for KV transfer duration derivation.
+	// Analytical backends populate ModelConfig from HF config.json.
+	// When PD is enabled and ModelConfig is zero-valued, resolve and load it using the
+	// same resolution as analytical backends (--model-config-folder → local bundled → HuggingFace fetch → error).
+	if prefillInstances > 0 && lr.ModelConfig.NumHeads == 0 {
+		resolved, err := resolveModelConfig(model, modelConfigFolder, defaultsFilePath)
 		if err != nil {
-			logrus.Fatalf("Invalid log level: %s", logLevel)
+			logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing: %v", err)
 		}
-		logrus.SetLevel(level)
-
-		if model == "" { // model not provided, exit
-			logrus.Fatalf("LLM name not provided. Exiting simulation.")
+		hfPath := filepath.Join(resolved, "config.json")
+		hfConfig, parseErr := latency.ParseHFConfig(hfPath)
+		if parseErr != nil {
+			logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing, but failed to parse %s: %v", hfPath, parseErr)
 		}
+		mc, mcErr := latency.GetModelConfigFromHF(hfConfig)
+		if mcErr != nil {
+			logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing, but failed to extract ModelConfig: %v", mcErr)
+		}
+		applyWeightPrecisionFallback(mc, model, hfConfig.Raw)
+		applyDtypeOverrides(mc)
+		if mc.BytesPerParam <= 0 {
+			logrus.Fatalf("PD disaggregation: could not determine model precision (BytesPerParam=%v) from %s — ensure torch_dtype or dtype is present in config.json", mc.BytesPerParam, hfPath)
+		}
+		lr.ModelConfig = *mc
+		logrus.Infof("PD disaggregation: loaded ModelConfig from %s for KV transfer derivation", hfPath)
+	}
 
-		// LoRA control-plane (#1464): resolve the config ONCE here (R4 single site) so
-		// both the KV auto-capacity path — resolveLatencyConfig and the per-pool calc
-		// below read the resulting static HBM reservation (PR5) — and the SimConfig
-		// literal further down share one resolution. The reservation is 0 (KV
-		// unaffected) when the subsystem is inert (INV-6). Set before resolveLatencyConfig.
-		loraCfg := resolveLoRAConfig(cmd)
-		loraReservedBytesForKV = adapterReservedBytesFor(loraCfg)
-
-		// Resolve latency backend configuration (single code path shared with replayCmd).
-		lr := resolveLatencyConfig(cmd)
+	// Per-pool hardware override vars. TotalKVBlocks is populated from per-pool KV
+	// auto-calc in the analytical backend block below (when applicable). TP/GPU/Backend/MaxModelLen
+	// are populated from CLI flags after PD validation. Both paths are no-ops when disaggregation
+	// is disabled (prefillInstances == 0).
+	var prefillOverrides, decodeOverrides cluster.PoolOverrides
 
-		// PD disaggregation requires ModelConfig for KV transfer duration derivation.
-		// Analytical backends populate ModelConfig from HF config.json.
-		// When PD is enabled and ModelConfig is zero-valued, resolve and load it using the
-		// same resolution as analytical backends (--model-config-folder → local bundled → HuggingFace fetch → error).
-		if prefillInstances > 0 && lr.ModelConfig.NumHeads == 0 {
-			resolved, err := resolveModelConfig(model, modelConfigFolder, defaultsFilePath)
+	// Per-pool KV auto-calculation: when PD disaggregation is active and a pool
+	// uses different TP or GPU hardware, compute per-pool KV blocks from model + hardware.
+	// Only runs for analytical backends where hardware configs are available.
+	if lr.Backend == "roofline" || lr.Backend == "trained-physics" {
+		if prefillInstances > 0 {
+			hfPath := filepath.Join(modelConfigFolder, "config.json")
+			hfConfig, err := latency.ParseHFConfig(hfPath)
 			if err != nil {
-				logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing: %v", err)
-			}
-			hfPath := filepath.Join(resolved, "config.json")
-			hfConfig, parseErr := latency.ParseHFConfig(hfPath)
-			if parseErr != nil {
-				logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing, but failed to parse %s: %v", hfPath, parseErr)
-			}
-			mc, mcErr := latency.GetModelConfigFromHF(hfConfig)
-			if mcErr != nil {
-				logrus.Fatalf("PD disaggregation requires model architecture for KV transfer sizing, but failed to extract ModelConfig: %v", mcErr)
-			}
-			applyWeightPrecisionFallback(mc, model, hfConfig.Raw)
-			if mc.BytesPerParam <= 0 {
-				logrus.Fatalf("PD disaggregation: could not determine model precision (BytesPerParam=%v) from %s — ensure torch_dtype or dtype is present in config.json", mc.BytesPerParam, hfPath)
+				logrus.Fatalf("Failed to parse HuggingFace config for per-pool KV calc: %v", err)
 			}
-			lr.ModelConfig = *mc
-			logrus.Infof("PD disaggregation: loaded ModelConfig from %s for KV transfer derivation", hfPath)
-		}
-
-		// Per-pool hardware override vars. TotalKVBlocks is populated from per-pool KV
-		// auto-calc in the analytical backend block below (when applicable). TP/GPU/Backend/MaxModelLen
-		// are populated from CLI flags after PD validation. Both paths are no-ops when disaggregation
-		// is disabled (prefillInstances == 0).
-		var prefillOverrides, decodeOverrides cluster.PoolOverrides
-
-		// Per-pool KV auto-calculation: when PD disaggregation is active and a pool
-		// uses different TP or GPU hardware, compute per-pool KV blocks from model + hardware.
-		// Only runs for analytical backends where hardware configs are available.
-		if lr.Backend == "roofline" || lr.Backend == "trained-physics" {
-			if prefillInstances > 0 {
-				hfPath := filepath.Join(modelConfigFolder, "config.json")
-				hfConfig, err := latency.ParseHFConfig(hfPath)
-				if err != nil {
-					logrus.Fatalf("Failed to parse HuggingFace config for per-pool KV calc: %v", err)
+			kvParamsPool, kvErrPool := latency.ExtractKVCapacityParams(hfConfig)
+			if kvErrPool != nil {
+				logrus.Warnf("per-pool KV auto-calculation skipped (could not extract model KV params: %v); both pools will use global total-kv-blocks=%d", kvErrPool, totalKVBlocks)
+			} else {
+				// Prefill pool auto-calc
+				poolPrefillTP := tensorParallelism
+				if cmd.Flags().Changed("prefill-tp") {
+					poolPrefillTP = prefillTP
 				}
-				kvParamsPool, kvErrPool := latency.ExtractKVCapacityParams(hfConfig)
-				if kvErrPool != nil {
-					logrus.Warnf("per-pool KV auto-calculation skipped (could not extract model KV params: %v); both pools will use global total-kv-blocks=%d", kvErrPool, totalKVBlocks)
-				} else {
-					// Prefill pool auto-calc
-					poolPrefillTP := tensorParallelism
-					if cmd.Flags().Changed("prefill-tp") {
-						poolPrefillTP = prefillTP
-					}
-					poolPrefillGPU := gpu
-					if cmd.Flags().Changed("prefill-hardware") {
-						poolPrefillGPU = prefillHardware
-					}
-					if poolPrefillTP != tensorParallelism || poolPrefillGPU != gpu {
-						poolHC, hcErr := latency.GetHWConfig(hwConfigPath, poolPrefillGPU)
-						if hcErr != nil {
-							logrus.Warnf("--prefill-hardware: failed to load hardware config for GPU %q: %v; prefill pool will use global total-kv-blocks=%d", poolPrefillGPU, hcErr, totalKVBlocks)
-						} else if poolHC.MemoryGiB <= 0 {
-							logrus.Warnf("--prefill-hardware: GPU memory capacity not available for %q in hardware config; prefill pool will use global total-kv-blocks=%d", poolPrefillGPU, totalKVBlocks)
+				poolPrefillGPU := gpu
+				if cmd.Flags().Changed("prefill-hardware") {
+					poolPrefillGPU = prefillHardware
+				}
+				if poolPrefillTP != tensorParallelism || poolPrefillGPU != gpu {
+					poolHC, hcErr := loadHardwareCalib(hwConfigPath, fleetInventoryPath, poolPrefillGPU)
+					if hcErr != nil {
+						logrus.Warnf("--prefill-hardware: failed to load hardware config for GPU %q: %v; prefill pool will use global total-kv-blocks=%d", poolPrefillGPU, hcErr, totalKVBlocks)
+					} else if poolHC.MemoryGiB <= 0 {
+						logrus.Warnf("--prefill-hardware: GPU memory capacity not available for %q in hardware config; prefill pool will use global total-kv-blocks=%d", poolPrefillGPU, totalKVBlocks)
+					} else {
+						// Per-pool TP but GLOBAL dp: per-pool DP is out of scope (#1420);
+						// --dp applies uniformly to all pools. Not a bug — see issue #1420.
+						poolBlocks, calcErr := latency.CalculateKVBlocks(lr.ModelConfig, poolHC, poolPrefillTP, dataParallelism, blockSizeTokens, gpuMemoryUtilization, kvParamsPool,
+							latency.WithAdapterReservedBytes(loraReservedBytesForKV))
+						if calcErr != nil {
+							logrus.Fatalf("--prefill-tp/--prefill-hardware: KV capacity auto-calculation failed for prefill pool: %v", calcErr)
 						} else {
-							// Per-pool TP but GLOBAL dp: per-pool DP is out of scope (#1420);
-							// --dp applies uniformly to all pools. Not a bug — see issue #1420.
-							poolBlocks, calcErr := latency.CalculateKVBlocks(lr.ModelConfig, poolHC, poolPrefillTP, dataParallelism, blockSizeTokens, gpuMemoryUtilization, kvParamsPool,
-								latency.WithAdapterReservedBytes(loraReservedBytesForKV))
-							if calcErr != nil {
-								logrus.Fatalf("--prefill-tp/--prefill-hardware: KV capacity auto-calculation failed for prefill pool: %v", calcErr)
-							} else {
-								prefillOverrides.TotalKVBlocks = &poolBlocks
-								logrus.Infof("--prefill-tp/--prefill-hardware: auto-calculated prefill pool total-kv-blocks=%d (GPU=%.0f GiB, TP=%d, DP=%d)",
-									poolBlocks, poolHC.MemoryGiB, poolPrefillTP, dataParallelism)
-								if !cmd.Flags().Changed("prefill-max-model-len") {
-									kvFeasibleMax := poolBlocks * int64(blockSizeTokens)
-									if kvFeasibleMax < maxModelLen {
-										prefillOverrides.MaxModelLen = &kvFeasibleMax
-										logrus.Infof("--prefill-tp/--prefill-hardware: auto-capped prefill pool max-model-len=%d (pool KV capacity smaller than global)", kvFeasibleMax)
-									}
+							prefillOverrides.TotalKVBlocks = &poolBlocks
+							logrus.Infof("--prefill-tp/--prefill-hardware: auto-calculated prefill pool total-kv-blocks=%d (GPU=%.0f GiB, TP=%d, DP=%d)",
+								poolBlocks, poolHC.MemoryGiB, poolPrefillTP, dataParallelism)
+							if !cmd.Flags().Changed("prefill-max-model-len") {
+								kvFeasibleMax := poolBlocks * int64(blockSizeTokens)
+								if kvFeasibleMax < maxModelLen {
+									prefillOverrides.MaxModelLen = &kvFeasibleMax
+									logrus.Infof("--prefill-tp/--prefill-hardware: auto-capped prefill pool max-model-len=%d (pool KV capacity smaller than global)", kvFeasibleMax)
 								}
 							}
 						}
 					}
+				}
 
-					// Decode pool auto-calc
-					poolDecodeTP := tensorParallelism
-					if cmd.Flags().Changed("decode-tp") {
-						poolDecodeTP = decodeTP
-					}
-					poolDecodeGPU := gpu
-					if cmd.Flags().Changed("decode-hardware") {
-						poolDecodeGPU = decodeHardware
-					}
-					if poolDecodeTP != tensorParallelism || poolDecodeGPU != gpu {
-						poolHC, hcErr := latency.GetHWConfig(hwConfigPath, poolDecodeGPU)
-						if hcErr != nil {
-							logrus.Warnf("--decode-hardware: failed to load hardware config for GPU %q: %v; decode pool will use global total-kv-blocks=%d", poolDecodeGPU, hcErr, totalKVBlocks)
-						} else if poolHC.MemoryGiB <= 0 {
-							logrus.Warnf("--decode-hardware: GPU memory capacity not available for %q in hardware config; decode pool will use global total-kv-blocks=%d", poolDecodeGPU, totalKVBlocks)
+				// Decode pool auto-calc
+				poolDecodeTP := tensorParallelism
+				if cmd.Flags().Changed("decode-tp") {
+					poolDecodeTP = decodeTP
+				}
+				poolDecodeGPU := gpu
+				if cmd.Flags().Changed("decode-hardware") {
+					poolDecodeGPU = decodeHardware
+				}
+				if poolDecodeTP != tensorParallelism || poolDecodeGPU != gpu {
+					poolHC, hcErr := loadHardwareCalib(hwConfigPath, fleetInventoryPath, poolDecodeGPU)
+					if hcErr != nil {
+						logrus.Warnf("--decode-hardware: failed to load hardware config for GPU %q: %v; decode pool will use global total-kv-blocks=%d", poolDecodeGPU, hcErr, totalKVBlocks)
+					} else if poolHC.MemoryGiB <= 0 {
+						logrus.Warnf("--decode-hardware: GPU memory capacity not available for %q in hardware config; decode pool will use global total-kv-blocks=%d", poolDecodeGPU, totalKVBlocks)
+					} else {
+						// Per-pool TP, global dp (see prefill-pool note above; #1420).
+						poolBlocks, calcErr := latency.CalculateKVBlocks(lr.ModelConfig, poolHC, poolDecodeTP, dataParallelism, blockSizeTokens, gpuMemoryUtilization, kvParamsPool,
+							latency.WithAdapterReservedBytes(loraReservedBytesForKV))
+						if calcErr != nil {
+							logrus.Fatalf("--decode-tp/--decode-hardware: KV capacity auto-calculation failed for decode pool: %v", calcErr)
 						} else {
-							// Per-pool TP, global dp (see prefill-pool note above; #1420).
-							poolBlocks, calcErr := latency.CalculateKVBlocks(lr.ModelConfig, poolHC, poolDecodeTP, dataParallelism, blockSizeTokens, gpuMemoryUtilization, kvParamsPool,
-								latency.WithAdapterReservedBytes(loraReservedBytesForKV))
-							if calcErr != nil {
-								logrus.Fatalf("--decode-tp/--decode-hardware: KV capacity auto-calculation failed for decode pool: %v", calcErr)
-							} else {
-								decodeOverrides.TotalKVBlocks = &poolBlocks
-								logrus.Infof("--decode-tp/--decode-hardware: auto-calculated decode pool total-kv-blocks=%d (GPU=%.0f GiB, TP=%d, DP=%d)",
-									poolBlocks, poolHC.MemoryGiB, poolDecodeTP, dataParallelism)
-								if !cmd.Flags().Changed("decode-max-model-len") {
-									kvFeasibleMax := poolBlocks * int64(blockSizeTokens)
-									if kvFeasibleMax < maxModelLen {
-										decodeOverrides.MaxModelLen = &kvFeasibleMax
-										logrus.Infof("--decode-tp/--decode-hardware: auto-capped decode pool max-model-len=%d (pool KV capacity smaller than global)", kvFeasibleMax)
-									}
+							decodeOverrides.TotalKVBlocks = &poolBlocks
+							logrus.Infof("--decode-tp/--decode-hardware: auto-calculated decode pool total-kv-blocks=%d (GPU=%.0f GiB, TP=%d, DP=%d)",
+								poolBlocks, poolHC.MemoryGiB, poolDecodeTP, dataParallelism)
+							if !cmd.Flags().Changed("decode-max-model-len") {
+								kvFeasibleMax := poolBlocks * int64(blockSizeTokens)
+								if kvFeasibleMax < maxModelLen {
+									decodeOverrides.MaxModelLen = &kvFeasibleMax
+									logrus.Infof("--decode-tp/--decode-hardware: auto-capped decode pool max-model-len=%d (pool KV capacity smaller than global)", kvFeasibleMax)
 								}
 							}
 						}
@@ -1577,883 +2105,1084 @@ var runCmd = &cobra.Command{
 				}
 			}
 		}
+	}
 
-		// R3: Validate workload generation flags (before any synthesis path consumes them)
-		if numRequests < 0 {
-			logrus.Fatalf("--num-requests must be >= 0, got %d", numRequests)
-		}
-		if prefixTokens < 0 {
-			logrus.Fatalf("--prefix-tokens must be >= 0, got %d", prefixTokens)
-		}
+	// R3: Validate workload generation flags (before any synthesis path consumes them)
+	if numRequests < 0 {
+		logrus.Fatalf("--num-requests must be >= 0, got %d", numRequests)
+	}
+	// --stop-after-completed bounds the run by completions, so the default
+	// --num-requests cap only applies when given explicitly.
+	if stopAfterCompleted > 0 && !cmd.Flags().Changed("num-requests") {
+		numRequests = 0
+	}
+	if len(ingressClockOffsets) > 0 && stopAfterCompleted > 0 {
+		logrus.Fatalf("--ingress-clock-offsets cannot be combined with --stop-after-completed")
+	}
+	if prefixTokens < 0 {
+		logrus.Fatalf("--prefix-tokens must be >= 0, got %d", prefixTokens)
+	}
 
-		// R3: Validate concurrency flags
-		if concurrency < 0 {
-			logrus.Fatalf("--concurrency must be >= 0, got %d", concurrency)
-		}
-		if thinkTimeMs < 0 {
-			logrus.Fatalf("--think-time-ms must be >= 0, got %d", thinkTimeMs)
-		}
-		// BC-1: --concurrency and --rate are mutually exclusive
-		if concurrency > 0 && cmd.Flags().Changed("rate") {
-			logrus.Fatalf("--concurrency and --rate are mutually exclusive; use one or the other")
-		}
+	// R3: Validate concurrency flags
+	if concurrency < 0 {
+		logrus.Fatalf("--concurrency must be >= 0, got %d", concurrency)
+	}
+	if thinkTimeMs < 0 {
+		logrus.Fatalf("--think-time-ms must be >= 0, got %d", thinkTimeMs)
+	}
+	// BC-1: --concurrency and --rate are mutually exclusive
+	if concurrency > 0 && cmd.Flags().Changed("rate") {
+		logrus.Fatalf("--concurrency and --rate are mutually exclusive; use one or the other")
+	}
 
-		// Workload configuration — all paths synthesize a v2 WorkloadSpec
-		// and generate requests via workload.GenerateRequests (BC-10).
-		var spec *workload.WorkloadSpec
-		var preGeneratedRequests []*sim.Request
-		var sessionMgr *workload.SessionManager
+	// Workload configuration — all paths synthesize a v2 WorkloadSpec
+	// and generate requests via workload.GenerateRequests (BC-10).
+	var spec *workload.WorkloadSpec
+	var preGeneratedRequests []*sim.Request
+	var sessionMgr *workload.SessionManager
 
-		if workloadSpecPath != "" {
-			if concurrency > 0 {
-				logrus.Fatalf("--concurrency cannot be used with --workload-spec; " +
-					"define concurrency in the spec file using clients[].concurrency instead")
-			}
-			// --workload-spec takes precedence over --workload
-			var err error
-			spec, err = workload.LoadWorkloadSpec(workloadSpecPath)
-			if err != nil {
-				logrus.Fatalf("Failed to load workload spec: %v", err)
-			}
-			// Apply CLI --seed override (R18: CLI flag precedence)
-			if cmd.Flags().Changed("seed") {
-				logrus.Infof("CLI --seed %d overrides workload-spec seed %d", seed, spec.Seed)
-				spec.Seed = seed
-			} else {
-				logrus.Infof("Using workload-spec seed %d (CLI --seed not specified)", spec.Seed)
-			}
-			if spec.Horizon > 0 && !cmd.Flags().Changed("horizon") {
-				simulationHorizon = spec.Horizon
-			}
-		} else if concurrency > 0 {
-			// Concurrency mode → synthesize v2 spec with closed-loop client.
-			// In concurrency mode, --num-requests has no meaningful default.
-			// If the user did not explicitly set it, leave it at 0 (unbounded) and
-			// require --horizon to bound the run. The existing unbounded-generation
-			// guard will fire with a clear message if neither is provided.
-			// R3: Validate distribution token bounds (shared with distribution mode).
-			if msg := validateDistributionParams(promptTokensMin, promptTokensMax, outputTokensMin, outputTokensMax,
-				promptTokensStdev, outputTokensStdev, promptTokensMean, outputTokensMean); msg != "" {
-				logrus.Fatalf("%s", msg)
-			}
-			concurrencyNumRequests := 0
-			if cmd.Flags().Changed("num-requests") {
-				concurrencyNumRequests = numRequests
-			}
-			spec = workload.SynthesizeFromDistribution(workload.DistributionParams{
-				Concurrency: concurrency, ThinkTimeMs: thinkTimeMs,
-				NumRequests: concurrencyNumRequests, PrefixTokens: prefixTokens,
-				PromptTokensMean: promptTokensMean, PromptTokensStdDev: promptTokensStdev,
-				PromptTokensMin: promptTokensMin, PromptTokensMax: promptTokensMax,
-				OutputTokensMean: outputTokensMean, OutputTokensStdDev: outputTokensStdev,
-				OutputTokensMin: outputTokensMin, OutputTokensMax: outputTokensMax,
-			})
-			spec.Seed = seed
-		} else if workloadType == "distribution" {
-			// Distribution mode → synthesize v2 spec from CLI flags
-			if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
-				logrus.Fatalf("--rate must be a finite value > 0, got %v", rate)
-			}
-			// R3: Validate distribution token bounds (shared with concurrency mode).
-			if msg := validateDistributionParams(promptTokensMin, promptTokensMax, outputTokensMin, outputTokensMax,
-				promptTokensStdev, outputTokensStdev, promptTokensMean, outputTokensMean); msg != "" {
-				logrus.Fatalf("%s", msg)
-			}
-			spec = workload.SynthesizeFromDistribution(workload.DistributionParams{
-				Rate: rate, NumRequests: numRequests, PrefixTokens: prefixTokens,
-				PromptTokensMean: promptTokensMean, PromptTokensStdDev: promptTokensStdev,
-				PromptTokensMin: promptTokensMin, PromptTokensMax: promptTokensMax,
-				OutputTokensMean: outputTokensMean, OutputTokensStdDev: outputTokensStdev,
-				OutputTokensMin: outputTokensMin, OutputTokensMax: outputTokensMax,
-			})
-			spec.Seed = seed
-		} else {
-			// Preset name (chatbot, summarization, etc.) → synthesize v2 spec
-			if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
-				logrus.Fatalf("--rate must be a finite value > 0, got %v", rate)
-			}
-			wl := loadPresetWorkload(defaultsFilePath, workloadType)
-			if wl == nil {
-				logrus.Fatalf("Undefined workload %q. Use one among (chatbot, summarization, contentgen, multidoc) or --workload-spec", workloadType)
-			}
-			spec = workload.SynthesizeFromPreset(workloadType, workload.PresetConfig{
-				PrefixTokens:     wl.PrefixTokens,
-				PromptTokensMean: wl.PromptTokensMean, PromptTokensStdev: wl.PromptTokensStdev,
-				PromptTokensMin: wl.PromptTokensMin, PromptTokensMax: wl.PromptTokensMax,
-				OutputTokensMean: wl.OutputTokensMean, OutputTokensStdev: wl.OutputTokensStdev,
-				OutputTokensMin: wl.OutputTokensMin, OutputTokensMax: wl.OutputTokensMax,
-			}, rate, numRequests)
-			spec.Seed = seed
-		}
-
-		// Apply per-request timeout to all clients.
-		// For synthesized specs, always apply (default 300s matches the session-client default).
-		// For file-loaded specs, only apply when the flag is explicitly set.
-		if requestTimeoutSecs == 0 {
-			logrus.Fatalf("--timeout must be positive (seconds) or negative to disable; got 0")
-		}
-		// Pre-expand inference-perf / ServeGen specs (LAZY MODE ONLY) so
-		// the timeout-application step below sees every client — including
-		// those populated by expansion. Without this, --lazy-generation +
-		// --workload-spec=inference_perf.yaml + an explicit --timeout
-		// would build streaming states from clients whose Timeout is nil
-		// (because expansion inside GenerateWorkloadLazy happens AFTER
-		// applyTimeoutToSpec runs), producing the default 300 s deadline
-		// instead of the user-requested value (PR #1453 self-review).
-		//
-		// Scoped to `lazyGeneration` so it does not run for eager-only
-		// invocations. Running it unconditionally would clear the
-		// spec.InferencePerf marker before GenerateWorkload's Validate,
-		// which suppresses the mixed-slo_class check via
-		// `s.InferencePerf == nil && s.ServeGenData == nil` in spec.go.
-		// Today all inference-perf-expanded clients carry SLOClass="standard"
-		// (uniform → check can't fire), so eager was accidentally safe.
-		// But scoping here defends against a future ExpandInferencePerfSpec
-		// change that emits mixed/empty slo_class from silently failing
-		// eager runs that previously validated (PR #1453 review round 3).
-		//
-		// REMOVING THIS CALL re-introduces the lazy timeout bug silently.
-		// The regression is covered by
-		// TestGenerateWorkloadLazy_InferencePerf_TimeoutAppliedAfterPreExpand
-		// at the library layer; the cmd-level smoke is covered by the
-		// inference-perf byte-identity check verified during PR review.
-		//
-		// ExpandClientsAndCohorts is idempotent — the generators'
-		// validateAndExpandSpec runs it again with no effect since both
-		// branches guard on len(spec.Clients) == 0. (#1441)
-		if lazyGeneration {
-			if err := workload.ExpandClientsAndCohorts(spec); err != nil {
-				logrus.Fatalf("Failed to expand workload spec: %v", err)
-			}
+	if workloadSpecPath != "" {
+		if concurrency > 0 {
+			logrus.Fatalf("--concurrency cannot be used with --workload-spec; " +
+				"define concurrency in the spec file using clients[].concurrency instead")
 		}
-		if workloadSpecPath == "" || cmd.Flags().Changed("timeout") {
-			applyTimeoutToSpec(spec, requestTimeoutSecs)
+		// --workload-spec takes precedence over --workload
+		var err error
+		spec, err = workload.LoadWorkloadSpec(workloadSpecPath)
+		if err != nil {
+			logrus.Fatalf("Failed to load workload spec: %v", err)
 		}
-
-		// Resolve maxRequests: spec.NumRequests as default, CLI --num-requests overrides
-		maxRequests := spec.NumRequests
+		// Apply CLI --seed override (R18: CLI flag precedence)
+		if rc.seedExplicit {
+			logrus.Infof("CLI --seed %d overrides workload-spec seed %d", rc.seed, spec.Seed)
+			spec.Seed = rc.seed
+		} else {
+			logrus.Infof("Using workload-spec seed %d (CLI --seed not specified)", spec.Seed)
+		}
+		if spec.Horizon > 0 && !cmd.Flags().Changed("horizon") {
+			simulationHorizon = spec.Horizon
+		}
+	} else if concurrency > 0 {
+		// Concurrency mode → synthesize v2 spec with closed-loop client.
+		// In concurrency mode, --num-requests has no meaningful default.
+		// If the user did not explicitly set it, leave it at 0 (unbounded) and
+		// require --horizon to bound the run. The existing unbounded-generation
+		// guard will fire with a clear message if neither is provided.
+		// R3: Validate distribution token bounds (shared with distribution mode).
+		if msg := validateDistributionParams(promptTokensMin, promptTokensMax, outputTokensMin, outputTokensMax,
+			promptTokensStdev, outputTokensStdev, promptTokensMean, outputTokensMean); msg != "" {
+			logrus.Fatalf("%s", msg)
+		}
+		concurrencyNumRequests := 0
 		if cmd.Flags().Changed("num-requests") {
-			maxRequests = int64(numRequests)
-		}
-
-		// Guard against unbounded generation
-		if maxRequests <= 0 && simulationHorizon == math.MaxInt64 {
-			logrus.Fatalf("Workload requires either num_requests or --horizon to bound generation")
-		}
-
-		// Lazy generation path (#1441, alpha). Default off. When set, build
-		// a streaming workload source instead of materializing the full
-		// request slice. As of #1460 there is NO eager-fallback class — every
-		// spec the eager generator accepts is streamed: multi-session reasoning
-		// (#1458), concurrency clients (#1459), and time-varying / per-window
-		// workloads (#1460).
-		var wl *workload.GeneratedWorkload
-		// lazyRequestSource is typed as the interface satisfied by
-		// *workload.lazyRequestSource: Next() delivers requests to the
-		// cluster; Err() surfaces any terminal sampler/generator error
-		// recorded on a per-client state after the run completes, so
-		// cmd can Fatalf and match the eager path's abort-on-invalid-spec
-		// behavior (PR #1453 review round 3).
-		var lazyRequestSource interface {
-			Next() (*sim.Request, bool)
-			Err() error
-		}
-		if lazyGeneration {
-			src, sessions, followUpBudget, lazyErr := workload.GenerateWorkloadLazy(spec, simulationHorizon, maxRequests)
-			// As of #1460 there is no ErrLazyUnsupported* fallback class — every
-			// spec the eager generator accepts is streamed. Any error is a real
-			// spec/validation failure → abort (matches eager's error handling).
-			if lazyErr != nil {
-				logrus.Fatalf("Failed to build lazy workload: %v", lazyErr)
-			}
-			lazyRequestSource = src
-			wl = &workload.GeneratedWorkload{Sessions: sessions, FollowUpBudget: followUpBudget}
+			concurrencyNumRequests = numRequests
+		}
+		spec = workload.SynthesizeFromDistribution(workload.DistributionParams{
+			Concurrency: concurrency, ThinkTimeMs: thinkTimeMs,
+			NumRequests: concurrencyNumRequests, PrefixTokens: prefixTokens,
+			PromptTokensMean: promptTokensMean, PromptTokensStdDev: promptTokensStdev,
+			PromptTokensMin: promptTokensMin, PromptTokensMax: promptTokensMax,
+			OutputTokensMean: outputTokensMean, OutputTokensStdDev: outputTokensStdev,
+			OutputTokensMin: outputTokensMin, OutputTokensMax: outputTokensMax,
+		})
+		spec.Seed = rc.seed
+	} else if workloadType == "distribution" {
+		// Distribution mode → synthesize v2 spec from CLI flags
+		if rc.rate <= 0 || math.IsNaN(rc.rate) || math.IsInf(rc.rate, 0) {
+			logrus.Fatalf("--rate must be a finite value > 0, got %v", rc.rate)
+		}
+		// R3: Validate distribution token bounds (shared with concurrency mode).
+		if msg := validateDistributionParams(promptTokensMin, promptTokensMax, outputTokensMin, outputTokensMax,
+			promptTokensStdev, outputTokensStdev, promptTokensMean, outputTokensMean); msg != "" {
+			logrus.Fatalf("%s", msg)
+		}
+		spec = workload.SynthesizeFromDistribution(workload.DistributionParams{
+			Rate: rc.rate, NumRequests: numRequests, PrefixTokens: prefixTokens,
+			PromptTokensMean: promptTokensMean, PromptTokensStdDev: promptTokensStdev,
+			PromptTokensMin: promptTokensMin, PromptTokensMax: promptTokensMax,
+			OutputTokensMean: outputTokensMean, OutputTokensStdDev: outputTokensStdev,
+			OutputTokensMin: outputTokensMin, OutputTokensMax: outputTokensMax,
+		})
+		spec.Seed = rc.seed
+	} else {
+		// Preset name (chatbot, summarization, etc.) → synthesize v2 spec
+		if rc.rate <= 0 || math.IsNaN(rc.rate) || math.IsInf(rc.rate, 0) {
+			logrus.Fatalf("--rate must be a finite value > 0, got %v", rc.rate)
 		}
+		wl := loadPresetWorkload(defaultsFilePath, workloadType)
 		if wl == nil {
-			var err error
-			wl, err = workload.GenerateWorkload(spec, simulationHorizon, maxRequests)
-			if err != nil {
-				logrus.Fatalf("Failed to generate workload: %v", err)
-			}
+			logrus.Fatalf("Undefined workload %q. Use one among (chatbot, summarization, contentgen, multidoc) or --workload-spec", workloadType)
+		}
+		spec = workload.SynthesizeFromPreset(workloadType, workload.PresetConfig{
+			PrefixTokens:     wl.PrefixTokens,
+			PromptTokensMean: wl.PromptTokensMean, PromptTokensStdev: wl.PromptTokensStdev,
+			PromptTokensMin: wl.PromptTokensMin, PromptTokensMax: wl.PromptTokensMax,
+			OutputTokensMean: wl.OutputTokensMean, OutputTokensStdev: wl.OutputTokensStdev,
+			OutputTokensMin: wl.OutputTokensMin, OutputTokensMax: wl.OutputTokensMax,
+		}, rc.rate, numRequests)
+		spec.Seed = rc.seed
+	}
+	// CLI --rand-source overrides the spec's rand_source (R18); otherwise the
+	// spec's choice drives the simulation streams too, so one source runs end to end.
+	if cmd.Flags().Changed("rand-source") || spec.RandSource == "" {
+		spec.RandSource = randSource
+	} else {
+		randSource = spec.RandSource
+	}
+
+	if bootstrapResamples < 0 {
+		logrus.Fatalf("--bootstrap-resamples must be >= 0, got %d", bootstrapResamples)
+	}
+	if !sim.IsValidPercentileMethod(percentileMethod) {
+		logrus.Fatalf("--percentile-method must be %s or %s, got %q", sim.PercentileLinear, sim.PercentileNearestRank, percentileMethod)
+	}
+
+	// Apply per-request timeout to all clients.
+	// For synthesized specs, always apply (default 300s matches the session-client default).
+	// For file-loaded specs, only apply when the flag is explicitly set.
+	if requestTimeoutSecs == 0 {
+		logrus.Fatalf("--timeout must be positive (seconds) or negative to disable; got 0")
+	}
+	// Pre-expand inference-perf / ServeGen specs (LAZY MODE ONLY) so
+	// the timeout-application step below sees every client — including
+	// those populated by expansion. Without this, --lazy-generation +
+	// --workload-spec=inference_perf.yaml + an explicit --timeout
+	// would build streaming states from clients whose Timeout is nil
+	// (because expansion inside GenerateWorkloadLazy happens AFTER
+	// applyTimeoutToSpec runs), producing the default 300 s deadline
+	// instead of the user-requested value (PR #1453 self-review).
+	//
+	// Scoped to `lazyGeneration` so it does not run for eager-only
+	// invocations. Running it unconditionally would clear the
+	// spec.InferencePerf marker before GenerateWorkload's Validate,
+	// which suppresses the mixed-slo_class check via
+	// `s.InferencePerf == nil && s.ServeGenData == nil` in spec.go.
+	// Today all inference-perf-expanded clients carry SLOClass="standard"
+	// (uniform → check can't fire), so eager was accidentally safe.
+	// But scoping here defends against a future ExpandInferencePerfSpec
+	// change that emits mixed/empty slo_class from silently failing
+	// eager runs that previously validated (PR #1453 review round 3).
+	//
+	// REMOVING THIS CALL re-introduces the lazy timeout bug silently.
+	// The regression is covered by
+	// TestGenerateWorkloadLazy_InferencePerf_TimeoutAppliedAfterPreExpand
+	// at the library layer; the cmd-level smoke is covered by the
+	// inference-perf byte-identity check verified during PR review.
+	//
+	// ExpandClientsAndCohorts is idempotent — the generators'
+	// validateAndExpandSpec runs it again with no effect since both
+	// branches guard on len(spec.Clients) == 0. (#1441)
+	// Unbounded generation under --stop-after-completed needs the streaming
+	// source: the cluster pulls arrivals until the completion target is met.
+	if stopAfterCompleted > 0 && simulationHorizon == math.MaxInt64 && spec.NumRequests <= 0 &&
+		(!cmd.Flags().Changed("num-requests") || numRequests <= 0) && !lazyGeneration {
+		lazyGeneration = true
+	}
+	if lazyGeneration {
+		if err := workload.ExpandClientsAndCohorts(spec); err != nil {
+			logrus.Fatalf("Failed to expand workload spec: %v", err)
+		}
+	}
+	if workloadSpecPath == "" || cmd.Flags().Changed("timeout") {
+		applyTimeoutToSpec(spec, requestTimeoutSecs)
+	}
+
+	// Resolve maxRequests: spec.NumRequests as default, CLI --num-requests overrides
+	maxRequests := spec.NumRequests
+	if cmd.Flags().Changed("num-requests") {
+		maxRequests = int64(numRequests)
+	}
+
+	// Guard against unbounded generation
+	if maxRequests <= 0 && simulationHorizon == math.MaxInt64 && stopAfterCompleted == 0 {
+		logrus.Fatalf("Workload requires either num_requests, --horizon, or --stop-after-completed to bound generation")
+	}
+
+	// Lazy generation path (#1441, alpha). Default off. When set, build
+	// a streaming workload source instead of materializing the full
+	// request slice. As of #1460 there is NO eager-fallback class — every
+	// spec the eager generator accepts is streamed: multi-session reasoning
+	// (#1458), concurrency clients (#1459), and time-varying / per-window
+	// workloads (#1460).
+	var wl *workload.GeneratedWorkload
+	// lazyRequestSource is typed as the interface satisfied by
+	// *workload.lazyRequestSource: Next() delivers requests to the
+	// cluster; Err() surfaces any terminal sampler/generator error
+	// recorded on a per-client state after the run completes, so
+	// cmd can Fatalf and match the eager path's abort-on-invalid-spec
+	// behavior (PR #1453 review round 3).
+	var lazyRequestSource interface {
+		Next() (*sim.Request, bool)
+		Err() error
+	}
+	if lazyGeneration {
+		src, sessions, followUpBudget, lazyErr := workload.GenerateWorkloadLazy(spec, simulationHorizon, maxRequests)
+		// As of #1460 there is no ErrLazyUnsupported* fallback class — every
+		// spec the eager generator accepts is streamed. Any error is a real
+		// spec/validation failure → abort (matches eager's error handling).
+		if lazyErr != nil {
+			logrus.Fatalf("Failed to build lazy workload: %v", lazyErr)
+		}
+		lazyRequestSource = src
+		wl = &workload.GeneratedWorkload{Sessions: sessions, FollowUpBudget: followUpBudget}
+	}
+	if wl == nil {
+		var err error
+		wl, err = workload.GenerateWorkload(spec, simulationHorizon, maxRequests)
+		if err != nil {
+			logrus.Fatalf("Failed to generate workload: %v", err)
 		}
-		// Re-apply timeout to generated requests and session blueprints.
-		// For inference_perf specs, spec.Clients was empty at applyTimeoutToSpec time
-		// and populated inside GenerateWorkload — deadlines need correction here.
-		// In lazy mode wl.Requests is nil (no-op for the request loop); session
-		// blueprint Timeout pointers still need refresh.
-		if workloadSpecPath == "" || cmd.Flags().Changed("timeout") {
-			applyTimeoutToRequests(wl, requestTimeoutSecs)
-		}
-		preGeneratedRequests = wl.Requests
-		if len(wl.Sessions) > 0 {
-			sessionMgr = workload.NewSessionManager(wl.Sessions)
-			if wl.FollowUpBudget >= 0 {
-				sessionMgr.SetFollowUpBudget(wl.FollowUpBudget)
-			}
-			if lazyRequestSource != nil {
-				logrus.Infof("Generated streaming source + %d session blueprints (closed-loop, lazy)", len(wl.Sessions))
-			} else {
-				logrus.Infof("Generated %d requests + %d session blueprints (closed-loop)", len(wl.Requests), len(wl.Sessions))
-			}
-		} else if lazyRequestSource != nil {
-			logrus.Infof("Generated streaming workload source (lazy, #1441)")
+	}
+	// Re-apply timeout to generated requests and session blueprints.
+	// For inference_perf specs, spec.Clients was empty at applyTimeoutToSpec time
+	// and populated inside GenerateWorkload — deadlines need correction here.
+	// In lazy mode wl.Requests is nil (no-op for the request loop); session
+	// blueprint Timeout pointers still need refresh.
+	if workloadSpecPath == "" || cmd.Flags().Changed("timeout") {
+		applyTimeoutToRequests(wl, requestTimeoutSecs)
+	}
+	preGeneratedRequests = wl.Requests
+	if len(wl.Sessions) > 0 {
+		sessionMgr = workload.NewSessionManager(wl.Sessions)
+		if wl.FollowUpBudget >= 0 {
+			sessionMgr.SetFollowUpBudget(wl.FollowUpBudget)
+		}
+		if lazyRequestSource != nil {
+			logrus.Infof("Generated streaming source + %d session blueprints (closed-loop, lazy)", len(wl.Sessions))
 		} else {
-			logrus.Infof("Generated %d requests via unified workload pipeline", len(wl.Requests))
+			logrus.Infof("Generated %d requests + %d session blueprints (closed-loop)", len(wl.Requests), len(wl.Sessions))
 		}
+	} else if lazyRequestSource != nil {
+		logrus.Infof("Generated streaming workload source (lazy, #1441)")
+	} else {
+		logrus.Infof("Generated %d requests via unified workload pipeline", len(wl.Requests))
+	}
 
-		if numInstances < 1 {
-			logrus.Fatalf("num-instances must be >= 1")
-		}
-		if totalKVBlocks <= 0 {
-			logrus.Fatalf("--total-kv-blocks must be > 0, got %d", totalKVBlocks)
+	var kvPrefixSeeds [][]sim.TokenID
+	if kvPrefixSeed {
+		seeds, err := workload.PrefixGroupTokens(spec)
+		if err != nil {
+			logrus.Fatalf("--kv-prefix-seed: %v", err)
 		}
-		if maxRunningReqs <= 0 {
-			logrus.Fatalf("--max-num-running-reqs must be > 0, got %d", maxRunningReqs)
+		if len(seeds) == 0 {
+			logrus.Warnf("--kv-prefix-seed: the workload has no prefix groups; nothing to seed")
+		} else {
+			logrus.Infof("Seeding each instance's KV cache with %d prefix group(s)", len(seeds))
+		}
+		kvPrefixSeeds = seeds
+	}
+
+	if numInstances < 1 {
+		logrus.Fatalf("num-instances must be >= 1")
+	}
+	if totalKVBlocks <= 0 {
+		logrus.Fatalf("--total-kv-blocks must be > 0, got %d", totalKVBlocks)
+	}
+	if maxRunningReqs <= 0 {
+		logrus.Fatalf("--max-num-running-reqs must be > 0, got %d", maxRunningReqs)
+	}
+	if maxScheduledTokens <= 0 {
+		logrus.Fatalf("--max-num-scheduled-tokens must be > 0, got %d", maxScheduledTokens)
+	}
+	if longPrefillTokenThreshold < 0 {
+		logrus.Fatalf("--long-prefill-token-threshold must be >= 0, got %d", longPrefillTokenThreshold)
+	}
+	// Changed() guard: unlike peer flags (default always positive), --horizon defaults
+	// to math.MaxInt64 which would fail <= 0. Only validate when user explicitly sets it.
+	if cmd.Flags().Changed("horizon") && simulationHorizon <= 0 {
+		logrus.Fatalf("--horizon must be > 0, got %d", simulationHorizon)
+	}
+
+	// Resolve policy configuration (single code path shared with replayCmd).
+	// Per-pool scorer configs (PD disaggregation) remain inline below.
+	parsedScorerConfigs, bundle := resolvePolicies(cmd)
+
+	// Resolve autoscaler and node pool config from policy bundle, then apply CLI overrides.
+	var (
+		bundleAutoscalerIntervalUs           float64
+		bundleScaleUpStabilizationWindowUs   float64
+		bundleScaleDownStabilizationWindowUs float64
+		bundleHPAScrapeDelayMean             float64
+		bundleHPAScrapeDelayStddev           float64
+		bundleAnalyzerCfg                    cluster.V2SaturationAnalyzerConfig
+		bundleNodePools                      []cluster.NodePoolConfig
+		bundleInstanceLifecycle              cluster.InstanceLifecycleConfig
+	)
+	bundleInstanceOverrides, bundleHWConfigByGPU := buildInstanceOverrides(bundle, numInstances, lr.Backend)
+	if bundle != nil {
+		if bundle.Autoscaler.IntervalUs > 0 {
+			bundleAutoscalerIntervalUs = bundle.Autoscaler.IntervalUs
+			bundleScaleUpStabilizationWindowUs = bundle.Autoscaler.ScaleUpStabilizationWindowUs
+			bundleScaleDownStabilizationWindowUs = bundle.Autoscaler.ScaleDownStabilizationWindowUs
+			bundleHPAScrapeDelayMean = bundle.Autoscaler.HPAScrapeDelay.Mean
+			bundleHPAScrapeDelayStddev = bundle.Autoscaler.HPAScrapeDelay.Stddev
+			bundleAnalyzerCfg = cluster.V2SaturationAnalyzerConfig{
+				KvCacheThreshold:  bundle.Autoscaler.Analyzer.KVCacheThreshold,
+				ScaleUpThreshold:  bundle.Autoscaler.Analyzer.ScaleUpThreshold,
+				ScaleDownBoundary: bundle.Autoscaler.Analyzer.ScaleDownBoundary,
+				AvgInputTokens:    bundle.Autoscaler.Analyzer.AvgInputTokens,
+			}
+		}
+		for _, np := range bundle.NodePools {
+			bundleNodePools = append(bundleNodePools, cluster.NodePoolConfig{
+				Name:         np.Name,
+				GPUType:      np.GPUType,
+				GPUsPerNode:  np.GPUsPerNode,
+				GPUMemoryGiB: np.GPUMemoryGiB,
+				InitialNodes: np.InitialNodes,
+				MinNodes:     np.MinNodes,
+				MaxNodes:     np.MaxNodes,
+				ProvisioningDelay: cluster.DelaySpec{
+					Mean:   np.ProvisioningDelay.Mean,
+					Stddev: np.ProvisioningDelay.Stddev,
+				},
+				CostPerHour: np.CostPerHour,
+			})
 		}
-		if maxScheduledTokens <= 0 {
-			logrus.Fatalf("--max-num-scheduled-tokens must be > 0, got %d", maxScheduledTokens)
+		bundleInstanceLifecycle = cluster.InstanceLifecycleConfig{
+			LoadingDelay: cluster.DelaySpec{
+				Mean:   bundle.InstanceLifecycle.LoadingDelay.Mean,
+				Stddev: bundle.InstanceLifecycle.LoadingDelay.Stddev,
+			},
+			WarmStartInitialInstances: bundle.InstanceLifecycle.WarmStartInitialInstances,
 		}
-		if longPrefillTokenThreshold < 0 {
-			logrus.Fatalf("--long-prefill-token-threshold must be >= 0, got %d", longPrefillTokenThreshold)
+	}
+	// CLI flag overrides bundle value when explicitly set.
+	if cmd.Flags().Changed("model-autoscaler-interval-us") {
+		bundleAutoscalerIntervalUs = modelAutoscalerIntervalUs
+	}
+
+	// PD disaggregation validation (R3: validate at CLI boundary)
+	if prefillInstances < 0 {
+		logrus.Fatalf("--prefill-instances must be >= 0, got %d", prefillInstances)
+	}
+	if decodeInstances < 0 {
+		logrus.Fatalf("--decode-instances must be >= 0, got %d", decodeInstances)
+	}
+	if prefillDecodeInstances < 0 {
+		logrus.Fatalf("--prefill-decode-instances must be >= 0, got %d", prefillDecodeInstances)
+	}
+	if !sim.IsValidDisaggregationDecider(pdDecider) {
+		logrus.Fatalf("Unknown PD decider %q. Valid: %s", pdDecider, strings.Join(sim.ValidDisaggregationDeciderNames(), ", "))
+	}
+	if err := cluster.ValidatePoolTopology(prefillInstances, decodeInstances, prefillDecodeInstances, encodeInstances, numInstances); err != nil {
+		logrus.Fatalf("Invalid PD pool topology: %v", err)
+	}
+	// PD transfer parameter validation (R3, R11)
+	if prefillInstances > 0 {
+		if pdTransferBandwidth <= 0 || math.IsInf(pdTransferBandwidth, 0) || math.IsNaN(pdTransferBandwidth) {
+			logrus.Fatalf("--pd-transfer-bandwidth must be a finite positive number, got %f", pdTransferBandwidth)
 		}
-		// Changed() guard: unlike peer flags (default always positive), --horizon defaults
-		// to math.MaxInt64 which would fail <= 0. Only validate when user explicitly sets it.
-		if cmd.Flags().Changed("horizon") && simulationHorizon <= 0 {
-			logrus.Fatalf("--horizon must be > 0, got %d", simulationHorizon)
+		if pdTransferBaseLatency < 0 || math.IsInf(pdTransferBaseLatency, 0) || math.IsNaN(pdTransferBaseLatency) {
+			logrus.Fatalf("--pd-transfer-base-latency must be a finite non-negative number, got %f", pdTransferBaseLatency)
 		}
+	}
+	if pdDecider == "prefix-threshold" && pdPrefixThreshold < 0 {
+		logrus.Fatalf("--pd-prefix-threshold must be >= 0, got %d", pdPrefixThreshold)
+	}
+	if pdDecider != "prefix-threshold" && cmd.Flags().Changed("pd-prefix-threshold") {
+		logrus.Warnf("--pd-prefix-threshold=%d is ignored when --pd-decider=%q (only applies to the prefix-threshold decider)", pdPrefixThreshold, pdDecider)
+	}
+	if pdDecider != "" && pdDecider != "never" && prefillInstances == 0 {
+		logrus.Warnf("--pd-decider=%q has no effect because --prefill-instances=0 (disaggregation is disabled); set --prefill-instances and --decode-instances to enable", pdDecider)
+	}
 
-		// Resolve policy configuration (single code path shared with replayCmd).
-		// Per-pool scorer configs (PD disaggregation) remain inline below.
-		parsedScorerConfigs, bundle := resolvePolicies(cmd)
+	// E/P/D disaggregation validation (GAP-4, issue #1264).
+	if encodeInstances < 0 {
+		logrus.Fatalf("--encode-instances must be >= 0, got %d", encodeInstances)
+	}
+	if !sim.IsValidEncodeDecider(encodeDecider) {
+		logrus.Fatalf("Unknown encode decider %q. Valid: %s", encodeDecider, strings.Join(sim.ValidEncodeDeciderNames(), ", "))
+	}
+	if encodeDecider != "" && encodeDecider != "never" && encodeInstances == 0 {
+		logrus.Fatalf("--encode-decider=%q requires --encode-instances > 0 (the encode pool is disabled)", encodeDecider)
+	}
+	if encodeInstances > 0 && (encodeDecider == "" || encodeDecider == "never") {
+		logrus.Warnf("--encode-decider=%q has no effect because --encode-instances=%d but the decider never encodes; set --encode-decider=multimodal or always to activate the encode pool", encodeDecider, encodeInstances)
+	}
 
-		// Resolve autoscaler and node pool config from policy bundle, then apply CLI overrides.
-		var (
-			bundleAutoscalerIntervalUs           float64
-			bundleScaleUpStabilizationWindowUs   float64
-			bundleScaleDownStabilizationWindowUs float64
-			bundleHPAScrapeDelayMean             float64
-			bundleHPAScrapeDelayStddev           float64
-			bundleAnalyzerCfg                    cluster.V2SaturationAnalyzerConfig
-			bundleNodePools                      []cluster.NodePoolConfig
-			bundleInstanceLifecycle              cluster.InstanceLifecycleConfig
-		)
-		if bundle != nil {
-			if bundle.Autoscaler.IntervalUs > 0 {
-				bundleAutoscalerIntervalUs = bundle.Autoscaler.IntervalUs
-				bundleScaleUpStabilizationWindowUs = bundle.Autoscaler.ScaleUpStabilizationWindowUs
-				bundleScaleDownStabilizationWindowUs = bundle.Autoscaler.ScaleDownStabilizationWindowUs
-				bundleHPAScrapeDelayMean = bundle.Autoscaler.HPAScrapeDelay.Mean
-				bundleHPAScrapeDelayStddev = bundle.Autoscaler.HPAScrapeDelay.Stddev
-				bundleAnalyzerCfg = cluster.V2SaturationAnalyzerConfig{
-					KvCacheThreshold:  bundle.Autoscaler.Analyzer.KVCacheThreshold,
-					ScaleUpThreshold:  bundle.Autoscaler.Analyzer.ScaleUpThreshold,
-					ScaleDownBoundary: bundle.Autoscaler.Analyzer.ScaleDownBoundary,
-					AvgInputTokens:    bundle.Autoscaler.Analyzer.AvgInputTokens,
-				}
-			}
-			for _, np := range bundle.NodePools {
-				bundleNodePools = append(bundleNodePools, cluster.NodePoolConfig{
-					Name:         np.Name,
-					GPUType:      np.GPUType,
-					GPUsPerNode:  np.GPUsPerNode,
-					GPUMemoryGiB: np.GPUMemoryGiB,
-					InitialNodes: np.InitialNodes,
-					MinNodes:     np.MinNodes,
-					MaxNodes:     np.MaxNodes,
-					ProvisioningDelay: cluster.DelaySpec{
-						Mean:   np.ProvisioningDelay.Mean,
-						Stddev: np.ProvisioningDelay.Stddev,
-					},
-					CostPerHour: np.CostPerHour,
-				})
-			}
-			bundleInstanceLifecycle = cluster.InstanceLifecycleConfig{
-				LoadingDelay: cluster.DelaySpec{
-					Mean:   bundle.InstanceLifecycle.LoadingDelay.Mean,
-					Stddev: bundle.InstanceLifecycle.LoadingDelay.Stddev,
-				},
-				WarmStartInitialInstances: bundle.InstanceLifecycle.WarmStartInitialInstances,
+	// Per-pool hardware override construction (R3): build PoolOverrides from CLI flags.
+	// Pointer fields use cmd.Flags().Changed() to distinguish "not set" from "set to value".
+	// Warns if per-pool flags are set but disaggregation is disabled.
+	perPoolFlagsChanged := cmd.Flags().Changed("prefill-tp") || cmd.Flags().Changed("decode-tp") ||
+		cmd.Flags().Changed("prefill-hardware") || cmd.Flags().Changed("decode-hardware") ||
+		cmd.Flags().Changed("prefill-latency-model") || cmd.Flags().Changed("decode-latency-model") ||
+		cmd.Flags().Changed("prefill-max-model-len") || cmd.Flags().Changed("decode-max-model-len")
+	if perPoolFlagsChanged && prefillInstances == 0 {
+		logrus.Warnf("per-pool hardware flags (--prefill-tp, --decode-tp, etc.) have no effect when --prefill-instances=0 (disaggregation is disabled)")
+	}
+	if prefillInstances > 0 {
+		// Prefill pool overrides
+		if cmd.Flags().Changed("prefill-tp") {
+			if prefillTP <= 0 {
+				logrus.Fatalf("--prefill-tp must be > 0, got %d", prefillTP)
 			}
+			tp := prefillTP
+			prefillOverrides.TP = &tp
 		}
-		// CLI flag overrides bundle value when explicitly set.
-		if cmd.Flags().Changed("model-autoscaler-interval-us") {
-			bundleAutoscalerIntervalUs = modelAutoscalerIntervalUs
-		}
-
-		// PD disaggregation validation (R3: validate at CLI boundary)
-		if prefillInstances < 0 {
-			logrus.Fatalf("--prefill-instances must be >= 0, got %d", prefillInstances)
+		if cmd.Flags().Changed("prefill-hardware") {
+			prefillOverrides.GPU = prefillHardware
 		}
-		if decodeInstances < 0 {
-			logrus.Fatalf("--decode-instances must be >= 0, got %d", decodeInstances)
+		if cmd.Flags().Changed("prefill-latency-model") {
+			if !sim.IsValidLatencyBackend(prefillLatencyModel) {
+				logrus.Fatalf("--prefill-latency-model %q is not a recognized backend; valid: %s",
+					prefillLatencyModel, strings.Join(sim.ValidLatencyBackendNames(), ", "))
+			}
+			prefillOverrides.LatencyBackend = prefillLatencyModel
 		}
-		if prefillDecodeInstances < 0 {
-			logrus.Fatalf("--prefill-decode-instances must be >= 0, got %d", prefillDecodeInstances)
+		if cmd.Flags().Changed("prefill-max-model-len") {
+			if prefillMaxModelLen <= 0 {
+				logrus.Fatalf("--prefill-max-model-len must be > 0 when set, got %d", prefillMaxModelLen)
+			}
+			ml := prefillMaxModelLen
+			prefillOverrides.MaxModelLen = &ml
 		}
-		if !sim.IsValidDisaggregationDecider(pdDecider) {
-			logrus.Fatalf("Unknown PD decider %q. Valid: %s", pdDecider, strings.Join(sim.ValidDisaggregationDeciderNames(), ", "))
+		// Decode pool overrides
+		if cmd.Flags().Changed("decode-tp") {
+			if decodeTP <= 0 {
+				logrus.Fatalf("--decode-tp must be > 0, got %d", decodeTP)
+			}
+			tp := decodeTP
+			decodeOverrides.TP = &tp
 		}
-		if err := cluster.ValidatePoolTopology(prefillInstances, decodeInstances, prefillDecodeInstances, encodeInstances, numInstances); err != nil {
-			logrus.Fatalf("Invalid PD pool topology: %v", err)
+		if cmd.Flags().Changed("decode-hardware") {
+			decodeOverrides.GPU = decodeHardware
 		}
-		// PD transfer parameter validation (R3, R11)
-		if prefillInstances > 0 {
-			if pdTransferBandwidth <= 0 || math.IsInf(pdTransferBandwidth, 0) || math.IsNaN(pdTransferBandwidth) {
-				logrus.Fatalf("--pd-transfer-bandwidth must be a finite positive number, got %f", pdTransferBandwidth)
-			}
-			if pdTransferBaseLatency < 0 || math.IsInf(pdTransferBaseLatency, 0) || math.IsNaN(pdTransferBaseLatency) {
-				logrus.Fatalf("--pd-transfer-base-latency must be a finite non-negative number, got %f", pdTransferBaseLatency)
+		if cmd.Flags().Changed("decode-latency-model") {
+			if !sim.IsValidLatencyBackend(decodeLatencyModel) {
+				logrus.Fatalf("--decode-latency-model %q is not a recognized backend; valid: %s",
+					decodeLatencyModel, strings.Join(sim.ValidLatencyBackendNames(), ", "))
 			}
+			decodeOverrides.LatencyBackend = decodeLatencyModel
 		}
-		if pdDecider == "prefix-threshold" && pdPrefixThreshold < 0 {
-			logrus.Fatalf("--pd-prefix-threshold must be >= 0, got %d", pdPrefixThreshold)
+		if cmd.Flags().Changed("decode-max-model-len") {
+			if decodeMaxModelLen <= 0 {
+				logrus.Fatalf("--decode-max-model-len must be > 0 when set, got %d", decodeMaxModelLen)
+			}
+			ml := decodeMaxModelLen
+			decodeOverrides.MaxModelLen = &ml
 		}
-		if pdDecider != "prefix-threshold" && cmd.Flags().Changed("pd-prefix-threshold") {
-			logrus.Warnf("--pd-prefix-threshold=%d is ignored when --pd-decider=%q (only applies to the prefix-threshold decider)", pdPrefixThreshold, pdDecider)
+	}
+
+	// Parse per-pool scorer configs (PD disaggregation — not in resolvePolicies)
+	var prefillScorerCfgs, decodeScorerCfgs []sim.ScorerConfig
+	if prefillRoutingScorers != "" {
+		var err error
+		prefillScorerCfgs, err = sim.ParseScorerConfigs(prefillRoutingScorers)
+		if err != nil {
+			logrus.Fatalf("Invalid --prefill-routing-scorers: %v", err)
 		}
-		if pdDecider != "" && pdDecider != "never" && prefillInstances == 0 {
-			logrus.Warnf("--pd-decider=%q has no effect because --prefill-instances=0 (disaggregation is disabled); set --prefill-instances and --decode-instances to enable", pdDecider)
+	}
+	if decodeRoutingScorers != "" {
+		var err error
+		decodeScorerCfgs, err = sim.ParseScorerConfigs(decodeRoutingScorers)
+		if err != nil {
+			logrus.Fatalf("Invalid --decode-routing-scorers: %v", err)
+		}
+	}
+	// Log configuration after all config sources (CLI, workload spec, policy bundle) are resolved
+	logrus.Infof("Starting simulation with %d KV blocks, horizon=%dticks, alphaCoeffs=%v, betaCoeffs=%v",
+		totalKVBlocks, simulationHorizon, lr.AlphaCoeffs, lr.BetaCoeffs)
+
+	// LoRA control-plane (#1464). loraCfg was resolved once at the top of RunE (for
+	// the KV HBM reservation); here we cross-validate every workload adapter
+	// reference against the declared registry (unknown id / base-model mismatch =>
+	// Fatalf, never a silent no-op). With no adapters and no workload adapter
+	// references this is inert (INV-6).
+	var loraRegistry sim.AdapterRegistry
+	if loraCfg.HasAdapters() {
+		r, regErr := sim.NewAdapterRegistryFunc(loraCfg.Adapters)
+		if regErr != nil {
+			logrus.Fatalf("Invalid LoRA adapter registry: %v", regErr)
+		}
+		loraRegistry = r
+	}
+	if err := workload.ValidateAdapterReferences(spec, loraRegistry); err != nil {
+		logrus.Fatalf("LoRA workload validation: %v", err)
+	}
+
+	startTime := time.Now() // Get current time (start)
+
+	// Unified cluster path (used for all values of numInstances).
+	// INV-13 SYNC POINT: PD fields below must stay in sync with cmd/replay.go (replayCmd
+	// DeploymentConfig literal). See docs/contributing/standards/invariants.md INV-13.
+	config := cluster.DeploymentConfig{
+		SimConfig: sim.SimConfig{
+			Horizon:    simulationHorizon,
+			Seed:       rc.seed,
+			RandSource: randSource,
+			KVCacheConfig: sim.NewKVCacheConfig(totalKVBlocks, blockSizeTokens, kvCPUBlocks,
+				kvOffloadThreshold, kvTransferBandwidth, kvTransferBaseLatency),
+			BatchConfig:                    sim.NewBatchConfig(maxRunningReqs, maxScheduledTokens, longPrefillTokenThreshold),
+			LatencyCoeffs:                  sim.NewLatencyCoeffs(lr.BetaCoeffs, lr.AlphaCoeffs),
+			ModelHardwareConfig:            sim.NewModelHardwareConfig(lr.ModelConfig, lr.HWConfig, model, gpu, tensorParallelism, dataParallelism, enableExpertParallel, moeCommBackend, lr.Backend, maxModelLen, stepTimeTablePath),
+			PolicyConfig:                   sim.NewPolicyConfig(scheduler, preemptionPolicy, priorityPolicy),
+			LoRAConfig:                     loraCfg,
+			SLOPriorityOverrides:           sloPriorityOverrides,
+			WarmupSteps:                    warmupSteps,
+			WarmupFactor:                   warmupFactor,
+			MaxQueueDepth:                  maxQueueDepth,
+			MaxQueueWaitTicks:              maxQueueWait,
+			QueueOverflowPolicy:            queueOverflowPolicy,
+			KVPressureThreshold:            kvPressureThreshold,
+			DetokenizationUsPerToken:       detokenizationUsPerToken,
+			MaxOutputTokens:                maxOutputTokens,
+			TokensPerDecodeStep:            tokensPerDecodeStep,
+			DecodeLengthBuckets:            decodeLengthBuckets,
+			DecodeQuantumSteps:             decodeQuantumSteps,
+			PreemptionMode:                 preemptionMode,
+			SwapSpaceBlocks:                swapSpaceBlocks,
+			CriticalReserveFraction:        criticalReserveFraction,
+			AdaptivePrefillChunkMin:        adaptivePrefillChunkMin,
+			StepOrdering:                   stepOrdering,
+			MinBatchFill:                   minBatchFill,
+			BatchFillMaxWaitTicks:          batchFillMaxWait,
+			PowerIdleWatts:                 powerIdleWatts,
+			PowerPeakWatts:                 powerPeakWatts,
+			PowerCapWatts:                  powerCapWatts,
+			KVAllocationMode:               kvAllocationMode,
+			KVFairShareMaxBlocks:           kvFairShareMaxBlocks,
+			RemotePrefixFetchUsPerBlock:    remotePrefixFetchUsPerBlock,
+			SchedulingOverheadUsPerSeq:     schedulingOverheadUs,
+			SchedulingContentionUsPerSeqSq: schedulingContentionUs,
+			MinStepTimeTicks:               minStepTimeUs,
+			StepNoiseMagnitude:             stepNoise,
+			StepNoiseCorrelation:           stepNoiseCorrelation,
+			RooflineBlockTable:             rooflineBlockTable,
+			RooflineAccounting:             rooflineAccounting,
+			StopAfterCompleted:             stopAfterCompleted,
+			ThroughputSampleIntervalUs:     throughputSampleInterval,
+			KVSampleIntervalUs:             kvSampleInterval,
+			KVPrefixSeeds:                  kvPrefixSeeds,
+		},
+		NumInstances:                    numInstances,
+		AdmissionPolicy:                 admissionPolicy,
+		AdmissionLatency:                admissionLatency,
+		AdmissionLatencyDist:            admissionLatencyDist,
+		AdmissionLatencyStdDevUs:        admissionLatencyStd,
+		RoutingLatency:                  routingLatency,
+		TokenBucketCapacity:             tokenBucketCapacity,
+		TokenBucketRefillRate:           tokenBucketRefillRate,
+		RetryMaxAttempts:                retryMaxAttempts,
+		RetryBackoffUs:                  retryBackoff,
+		MaxInFlightTokens:               maxInFlightTokens,
+		FaultInjection:                  cluster.FaultInjectionConfig{InstanceID: faultInstance, AtUs: faultAt, Mode: faultMode},
+		SharedPrefixCache:               sharedPrefixCache,
+		RoutingPolicy:                   routingPolicy,
+		RoutingScorerConfigs:            parsedScorerConfigs,
+		RoutingTieBreak:                 routingTieBreak,
+		RoutingInstanceWeights:          routingInstanceWeights,
+		PrefixHashSkipTokens:            prefixHashSkipTokens,
+		IngressClockOffsetsUs:           ingressClockOffsets,
+		TraceLevel:                      traceLevel,
+		CounterfactualK:                 counterfactualK,
+		SnapshotRefreshInterval:         snapshotRefreshInterval,
+		CacheSignalDelay:                cacheSignalDelay,
+		PrefillInstances:                prefillInstances,
+		DecodeInstances:                 decodeInstances,
+		SharedInstances:                 prefillDecodeInstances,
+		EncodeInstances:                 encodeInstances,
+		EncodeDecider:                   encodeDecider,
+		PDDecider:                       pdDecider,
+		PDPrefixThreshold:               pdPrefixThreshold,
+		PDTransferBandwidthGBps:         pdTransferBandwidth,
+		PDTransferBaseLatencyMs:         pdTransferBaseLatency,
+		PDTransferContention:            pdTransferContention,
+		KVOffloadHostContention:         kvOffloadHostContention,
+		PrefillScorerConfigs:            prefillScorerCfgs,
+		DecodeScorerConfigs:             decodeScorerCfgs,
+		PrefillRoutingPolicy:            prefillRoutingPolicy,
+		DecodeRoutingPolicy:             decodeRoutingPolicy,
+		PrefillOverrides:                prefillOverrides,
+		DecodeOverrides:                 decodeOverrides,
+		TierShedThreshold:               tierShedThreshold,
+		TierShedMinPriority:             tierShedMinPriority,
+		GAIEQDThreshold:                 gaieQDThreshold,
+		GAIEKVThreshold:                 gaieKVThreshold,
+		SLOTokenBuckets:                 sloTokenBuckets,
+		TenantShares:                    tenantShares,
+		TenantBudgets:                   tenantBudgets,
+		FlowControlEnabled:              flowControlEnabled,
+		FlowControlDetector:             flowControlDetector,
+		FlowControlDispatchOrder:        flowControlDispatchOrder,
+		FlowControlSLOTargets:           sloTargetsMap,
+		FlowControlMaxQueueDepth:        flowControlMaxQueueDepth,
+		FlowControlQueueDepthThreshold:  flowControlQueueDepthThreshold,
+		FlowControlKVCacheUtilThreshold: flowControlKVCacheUtilThreshold,
+		FlowControlMaxConcurrency:       flowControlMaxConcurrency,
+		FlowControlPerBandCapacity:      flowControlPerBandCapacity,
+		FlowControlUsageLimitThreshold:  flowControlUsageLimitThreshold,
+		FlowControlFairnessPolicy:       flowControlFairnessPolicy,
+		FlowControlRequestTTL:           flowControlRequestTTL,
+		FlowControlQueueShedding:        flowControlQueueShedding,
+		FlowControlDispatchTickInterval: flowControlDispatchTickInterval,
+		FlowControlInFlightEviction:     flowControlInFlightEviction,
+		ModelAutoscalerIntervalUs:       bundleAutoscalerIntervalUs,
+		ScaleUpStabilizationWindowUs:    bundleScaleUpStabilizationWindowUs,
+		ScaleDownStabilizationWindowUs:  bundleScaleDownStabilizationWindowUs,
+		HPAScrapeDelay:                  cluster.DelaySpec{Mean: bundleHPAScrapeDelayMean, Stddev: bundleHPAScrapeDelayStddev},
+		AutoscalerAnalyzerConfig:        bundleAnalyzerCfg,
+		NodePools:                       bundleNodePools,
+		InstanceLifecycle:               bundleInstanceLifecycle,
+		InstanceOverrides:               bundleInstanceOverrides,
+		HWConfigByGPU:                   bundleHWConfigByGPU,
+	}
+	config.KVCacheConfig.CheckRefCounts = kvRefCountChecks
+	if routingReplayLogPath != "" {
+		log, err := readRoutingReplayLog(routingReplayLogPath)
+		if err != nil {
+			logrus.Fatalf("--routing-replay-log: %v", err)
 		}
+		config.RoutingReplayLog = log
+	}
+	if kvImportPath != "" {
+		warm, err := readKVPrefixImport(kvImportPath)
+		if err != nil {
+			logrus.Fatalf("--kv-import-state: %v", err)
+		}
+		if len(warm) != numInstances {
+			logrus.Warnf("--kv-import-state: file has %d instance(s), run has %d; matching by position, extra instances start cold", len(warm), numInstances)
+		}
+		config.KVWarmState = warm
+	}
+	// Session callback installation (Constraint 3 fix):
+	// Follow-up collection must be UNCONDITIONAL for saturation analysis correctness.
+	// The TraceV2 export (lines 1582-1601) remains gated on --trace-output, but the
+	// follow-up accumulation happens regardless so saturation analysis sees complete workloads.
+	var followUpRequests []*sim.Request
+	var onRequestDone func(*sim.Request, int64) []*sim.Request
+	if sessionMgr != nil {
+		// Always install callback to accumulate follow-ups (for saturation analysis + optional trace export)
+		baseCb := sessionMgr.OnComplete
+		onRequestDone = func(req *sim.Request, clock int64) []*sim.Request {
+			followUps := baseCb(req, clock)
+			followUpRequests = append(followUpRequests, followUps...)
+			return followUps
+		}
+	}
+	// RequestSource: streaming in lazy mode, eager-slice otherwise.
+	// The workload package's lazy source satisfies cluster.RequestSource
+	// via structural typing — both define the same Next() method.
+	var clusterRequestSource cluster.RequestSource
+	if lazyRequestSource != nil {
+		clusterRequestSource = lazyRequestSource
+	} else {
+		clusterRequestSource = cluster.NewSliceRequestSource(preGeneratedRequests)
+	}
+	cs := cluster.NewClusterSimulator(config, clusterRequestSource, onRequestDone)
 
-		// E/P/D disaggregation validation (GAP-4, issue #1264).
-		if encodeInstances < 0 {
-			logrus.Fatalf("--encode-instances must be >= 0, got %d", encodeInstances)
+	// Arrival hook: capture trace-emission references at the cluster's
+	// single arrival boundary so the trace exporter no longer relies on
+	// the eager preGeneratedRequests + followUpRequests list assembly
+	// (issue #1440). The hook fires once per fresh arrival in
+	// clock-monotonic order — see ClusterArrivalEvent.Execute. We hold
+	// pointers (not copies) so the request's final state (set by the
+	// event loop) is visible at export time.
+	//
+	// Only install when --trace-output is set (BC-1: zero overhead when
+	// trace is disabled). Saturation analysis continues to use the
+	// preGeneratedRequests + followUpRequests path below — those slices
+	// remain populated for that purpose only.
+	//
+	// Install/export coupling: traceArrivals is declared nil here and
+	// assigned a non-nil empty slice ONLY inside the install branch.
+	// A nil traceArrivals at the export site below with traceOutput
+	// non-empty means the install branch was dropped — we fail loudly
+	// rather than write a silent empty trace (R1).
+	// The arrival hook captures fresh-arrival references at the single
+	// cluster boundary. It powers trace export (#1440) and, in lazy mode
+	// where preGeneratedRequests is nil, also feeds saturation analysis.
+	// In eager mode without --trace-output, the hook stays uninstalled
+	// (BC-1 zero overhead) and saturation falls back to the
+	// preGeneratedRequests + followUpRequests path.
+	var traceArrivals []*sim.Request
+	arrivalHookNeeded := traceOutput != "" || (lazyRequestSource != nil && saturationReport != "")
+	if arrivalHookNeeded {
+		traceArrivals = make([]*sim.Request, 0)
+		cs.SetArrivalHook(func(req *sim.Request) {
+			traceArrivals = append(traceArrivals, req)
+		})
+	}
+	// Event log (debugging): the cluster holds a nil log unless --event-log
+	// is set, so the event loop pays only a nil check.
+	var eventLog *sim.EventLog
+	var eventLogFile *os.File
+	var eventLogBuf *bufio.Writer
+	if eventLogPath != "" {
+		f, err := os.Create(eventLogPath)
+		if err != nil {
+			logrus.Fatalf("Failed to create event log: %v", err)
 		}
-		if !sim.IsValidEncodeDecider(encodeDecider) {
-			logrus.Fatalf("Unknown encode decider %q. Valid: %s", encodeDecider, strings.Join(sim.ValidEncodeDeciderNames(), ", "))
+		eventLogFile = f
+		eventLogBuf = bufio.NewWriter(f)
+		eventLog = sim.NewEventLog(eventLogBuf)
+		cs.SetEventLog(eventLog)
+	}
+	if err := cs.Run(); err != nil {
+		logrus.Fatalf("Simulation failed: %v", err)
+	}
+	if eventLog != nil {
+		err := eventLog.Err()
+		if err == nil {
+			err = eventLogBuf.Flush()
 		}
-		if encodeDecider != "" && encodeDecider != "never" && encodeInstances == 0 {
-			logrus.Fatalf("--encode-decider=%q requires --encode-instances > 0 (the encode pool is disabled)", encodeDecider)
+		if closeErr := eventLogFile.Close(); err == nil {
+			err = closeErr
 		}
-		if encodeInstances > 0 && (encodeDecider == "" || encodeDecider == "never") {
-			logrus.Warnf("--encode-decider=%q has no effect because --encode-instances=%d but the decider never encodes; set --encode-decider=multimodal or always to activate the encode pool", encodeDecider, encodeInstances)
+		if err != nil {
+			logrus.Fatalf("Failed to write event log %s: %v", eventLogPath, err)
 		}
-
-		// Per-pool hardware override construction (R3): build PoolOverrides from CLI flags.
-		// Pointer fields use cmd.Flags().Changed() to distinguish "not set" from "set to value".
-		// Warns if per-pool flags are set but disaggregation is disabled.
-		perPoolFlagsChanged := cmd.Flags().Changed("prefill-tp") || cmd.Flags().Changed("decode-tp") ||
-			cmd.Flags().Changed("prefill-hardware") || cmd.Flags().Changed("decode-hardware") ||
-			cmd.Flags().Changed("prefill-latency-model") || cmd.Flags().Changed("decode-latency-model") ||
-			cmd.Flags().Changed("prefill-max-model-len") || cmd.Flags().Changed("decode-max-model-len")
-		if perPoolFlagsChanged && prefillInstances == 0 {
-			logrus.Warnf("per-pool hardware flags (--prefill-tp, --decode-tp, etc.) have no effect when --prefill-instances=0 (disaggregation is disabled)")
+		logrus.Infof("Event log written to %s", eventLogPath)
+	}
+	if kvStatePath != "" {
+		if err := writeKVStateDump(kvStatePath, cs); err != nil {
+			logrus.Fatalf("Failed to write KV state dump %s: %v", kvStatePath, err)
 		}
-		if prefillInstances > 0 {
-			// Prefill pool overrides
-			if cmd.Flags().Changed("prefill-tp") {
-				if prefillTP <= 0 {
-					logrus.Fatalf("--prefill-tp must be > 0, got %d", prefillTP)
-				}
-				tp := prefillTP
-				prefillOverrides.TP = &tp
-			}
-			if cmd.Flags().Changed("prefill-hardware") {
-				prefillOverrides.GPU = prefillHardware
-			}
-			if cmd.Flags().Changed("prefill-latency-model") {
-				if !sim.IsValidLatencyBackend(prefillLatencyModel) {
-					logrus.Fatalf("--prefill-latency-model %q is not a recognized backend; valid: %s",
-						prefillLatencyModel, strings.Join(sim.ValidLatencyBackendNames(), ", "))
-				}
-				prefillOverrides.LatencyBackend = prefillLatencyModel
-			}
-			if cmd.Flags().Changed("prefill-max-model-len") {
-				if prefillMaxModelLen <= 0 {
-					logrus.Fatalf("--prefill-max-model-len must be > 0 when set, got %d", prefillMaxModelLen)
-				}
-				ml := prefillMaxModelLen
-				prefillOverrides.MaxModelLen = &ml
-			}
-			// Decode pool overrides
-			if cmd.Flags().Changed("decode-tp") {
-				if decodeTP <= 0 {
-					logrus.Fatalf("--decode-tp must be > 0, got %d", decodeTP)
-				}
-				tp := decodeTP
-				decodeOverrides.TP = &tp
-			}
-			if cmd.Flags().Changed("decode-hardware") {
-				decodeOverrides.GPU = decodeHardware
-			}
-			if cmd.Flags().Changed("decode-latency-model") {
-				if !sim.IsValidLatencyBackend(decodeLatencyModel) {
-					logrus.Fatalf("--decode-latency-model %q is not a recognized backend; valid: %s",
-						decodeLatencyModel, strings.Join(sim.ValidLatencyBackendNames(), ", "))
-				}
-				decodeOverrides.LatencyBackend = decodeLatencyModel
-			}
-			if cmd.Flags().Changed("decode-max-model-len") {
-				if decodeMaxModelLen <= 0 {
-					logrus.Fatalf("--decode-max-model-len must be > 0 when set, got %d", decodeMaxModelLen)
-				}
-				ml := decodeMaxModelLen
-				decodeOverrides.MaxModelLen = &ml
-			}
+		logrus.Infof("KV state dump written to %s", kvStatePath)
+	}
+	if kvExportPath != "" {
+		if err := writeKVPrefixExport(kvExportPath, cs); err != nil {
+			logrus.Fatalf("Failed to write KV prefix state %s: %v", kvExportPath, err)
 		}
-
-		// Parse per-pool scorer configs (PD disaggregation — not in resolvePolicies)
-		var prefillScorerCfgs, decodeScorerCfgs []sim.ScorerConfig
-		if prefillRoutingScorers != "" {
-			var err error
-			prefillScorerCfgs, err = sim.ParseScorerConfigs(prefillRoutingScorers)
-			if err != nil {
-				logrus.Fatalf("Invalid --prefill-routing-scorers: %v", err)
-			}
+		logrus.Infof("KV prefix state written to %s", kvExportPath)
+	}
+	if routingLogPath != "" {
+		if err := writeRoutingLog(routingLogPath, cs); err != nil {
+			logrus.Fatalf("Failed to write routing-decision log %s: %v", routingLogPath, err)
 		}
-		if decodeRoutingScorers != "" {
-			var err error
-			decodeScorerCfgs, err = sim.ParseScorerConfigs(decodeRoutingScorers)
-			if err != nil {
-				logrus.Fatalf("Invalid --decode-routing-scorers: %v", err)
-			}
+		logrus.Infof("Routing-decision log written to %s", routingLogPath)
+	}
+	if otlpTracePath != "" {
+		if err := writeOTLPTrace(otlpTracePath, cs.AggregatedMetrics()); err != nil {
+			logrus.Fatalf("Failed to write OTLP trace %s: %v", otlpTracePath, err)
 		}
-		// Log configuration after all config sources (CLI, workload spec, policy bundle) are resolved
-		logrus.Infof("Starting simulation with %d KV blocks, horizon=%dticks, alphaCoeffs=%v, betaCoeffs=%v",
-			totalKVBlocks, simulationHorizon, lr.AlphaCoeffs, lr.BetaCoeffs)
-
-		// LoRA control-plane (#1464). loraCfg was resolved once at the top of RunE (for
-		// the KV HBM reservation); here we cross-validate every workload adapter
-		// reference against the declared registry (unknown id / base-model mismatch =>
-		// Fatalf, never a silent no-op). With no adapters and no workload adapter
-		// references this is inert (INV-6).
-		var loraRegistry sim.AdapterRegistry
-		if loraCfg.HasAdapters() {
-			r, regErr := sim.NewAdapterRegistryFunc(loraCfg.Adapters)
-			if regErr != nil {
-				logrus.Fatalf("Invalid LoRA adapter registry: %v", regErr)
-			}
-			loraRegistry = r
-		}
-		if err := workload.ValidateAdapterReferences(spec, loraRegistry); err != nil {
-			logrus.Fatalf("LoRA workload validation: %v", err)
-		}
-
-		startTime := time.Now() // Get current time (start)
-
-		// Unified cluster path (used for all values of numInstances).
-		// INV-13 SYNC POINT: PD fields below must stay in sync with cmd/replay.go (replayCmd
-		// DeploymentConfig literal). See docs/contributing/standards/invariants.md INV-13.
-		config := cluster.DeploymentConfig{
-			SimConfig: sim.SimConfig{
-				Horizon: simulationHorizon,
-				Seed:    seed,
-				KVCacheConfig: sim.NewKVCacheConfig(totalKVBlocks, blockSizeTokens, kvCPUBlocks,
-					kvOffloadThreshold, kvTransferBandwidth, kvTransferBaseLatency),
-				BatchConfig:          sim.NewBatchConfig(maxRunningReqs, maxScheduledTokens, longPrefillTokenThreshold),
-				LatencyCoeffs:        sim.NewLatencyCoeffs(lr.BetaCoeffs, lr.AlphaCoeffs),
-				ModelHardwareConfig:  sim.NewModelHardwareConfig(lr.ModelConfig, lr.HWConfig, model, gpu, tensorParallelism, dataParallelism, enableExpertParallel, moeCommBackend, lr.Backend, maxModelLen),
-				PolicyConfig:         sim.NewPolicyConfig(scheduler, preemptionPolicy),
-				LoRAConfig:           loraCfg,
-				SLOPriorityOverrides: sloPriorityOverrides,
-			},
-			NumInstances:                    numInstances,
-			AdmissionPolicy:                 admissionPolicy,
-			AdmissionLatency:                admissionLatency,
-			RoutingLatency:                  routingLatency,
-			TokenBucketCapacity:             tokenBucketCapacity,
-			TokenBucketRefillRate:           tokenBucketRefillRate,
-			RoutingPolicy:                   routingPolicy,
-			RoutingScorerConfigs:            parsedScorerConfigs,
-			TraceLevel:                      traceLevel,
-			CounterfactualK:                 counterfactualK,
-			SnapshotRefreshInterval:         snapshotRefreshInterval,
-			CacheSignalDelay:                cacheSignalDelay,
-			PrefillInstances:                prefillInstances,
-			DecodeInstances:                 decodeInstances,
-			SharedInstances:                 prefillDecodeInstances,
-			EncodeInstances:                 encodeInstances,
-			EncodeDecider:                   encodeDecider,
-			PDDecider:                       pdDecider,
-			PDPrefixThreshold:               pdPrefixThreshold,
-			PDTransferBandwidthGBps:         pdTransferBandwidth,
-			PDTransferBaseLatencyMs:         pdTransferBaseLatency,
-			PDTransferContention:            pdTransferContention,
-			PrefillScorerConfigs:            prefillScorerCfgs,
-			DecodeScorerConfigs:             decodeScorerCfgs,
-			PrefillOverrides:                prefillOverrides,
-			DecodeOverrides:                 decodeOverrides,
-			TierShedThreshold:               tierShedThreshold,
-			TierShedMinPriority:             tierShedMinPriority,
-			GAIEQDThreshold:                 gaieQDThreshold,
-			GAIEKVThreshold:                 gaieKVThreshold,
-			TenantBudgets:                   tenantBudgets,
-			FlowControlEnabled:              flowControlEnabled,
-			FlowControlDetector:             flowControlDetector,
-			FlowControlDispatchOrder:        flowControlDispatchOrder,
-			FlowControlSLOTargets:           sloTargetsMap,
-			FlowControlMaxQueueDepth:        flowControlMaxQueueDepth,
-			FlowControlQueueDepthThreshold:  flowControlQueueDepthThreshold,
-			FlowControlKVCacheUtilThreshold: flowControlKVCacheUtilThreshold,
-			FlowControlMaxConcurrency:       flowControlMaxConcurrency,
-			FlowControlPerBandCapacity:      flowControlPerBandCapacity,
-			FlowControlUsageLimitThreshold:  flowControlUsageLimitThreshold,
-			FlowControlFairnessPolicy:       flowControlFairnessPolicy,
-			FlowControlRequestTTL:           flowControlRequestTTL,
-			FlowControlQueueShedding:        flowControlQueueShedding,
-			FlowControlDispatchTickInterval: flowControlDispatchTickInterval,
-			FlowControlInFlightEviction:     flowControlInFlightEviction,
-			ModelAutoscalerIntervalUs:       bundleAutoscalerIntervalUs,
-			ScaleUpStabilizationWindowUs:    bundleScaleUpStabilizationWindowUs,
-			ScaleDownStabilizationWindowUs:  bundleScaleDownStabilizationWindowUs,
-			HPAScrapeDelay:                  cluster.DelaySpec{Mean: bundleHPAScrapeDelayMean, Stddev: bundleHPAScrapeDelayStddev},
-			AutoscalerAnalyzerConfig:        bundleAnalyzerCfg,
-			NodePools:                       bundleNodePools,
-			InstanceLifecycle:               bundleInstanceLifecycle,
-		}
-		// Session callback installation (Constraint 3 fix):
-		// Follow-up collection must be UNCONDITIONAL for saturation analysis correctness.
-		// The TraceV2 export (lines 1582-1601) remains gated on --trace-output, but the
-		// follow-up accumulation happens regardless so saturation analysis sees complete workloads.
-		var followUpRequests []*sim.Request
-		var onRequestDone func(*sim.Request, int64) []*sim.Request
-		if sessionMgr != nil {
-			// Always install callback to accumulate follow-ups (for saturation analysis + optional trace export)
-			baseCb := sessionMgr.OnComplete
-			onRequestDone = func(req *sim.Request, clock int64) []*sim.Request {
-				followUps := baseCb(req, clock)
-				followUpRequests = append(followUpRequests, followUps...)
-				return followUps
-			}
+		logrus.Infof("OTLP trace written to %s", otlpTracePath)
+	}
+
+	// Surface any terminal sampler / generator error the lazy source
+	// recorded on a per-client state during the run. Eager mode would
+	// have hit logrus.Fatalf inside cmd on the same invalid spec;
+	// without this check, lazy mode would exit 0 with reduced traffic
+	// and misleading capacity numbers (PR #1453 review round 3).
+	if lazyRequestSource != nil {
+		if err := lazyRequestSource.Err(); err != nil {
+			logrus.Fatalf("Lazy workload sampler failure: %v", err)
 		}
-		// RequestSource: streaming in lazy mode, eager-slice otherwise.
-		// The workload package's lazy source satisfies cluster.RequestSource
-		// via structural typing — both define the same Next() method.
-		var clusterRequestSource cluster.RequestSource
+	}
+
+	// Wall-clock timing on stderr (BC-6); stdout remains deterministic (BC-7)
+	logrus.Infof("Simulation wall-clock time: %.3fs", time.Since(startTime).Seconds())
+
+	// Resolve goodput SLO targets early so the trace export and aggregate metrics
+	// see the same merged map (#1413, BC-1). Run has no trace header; precedence
+	// here is CLI > workload spec.
+	cliTTFT, cliITL, cliE2E, gpErr := resolveGoodputCLIFlags(goodputSLOTTFT, goodputSLOITL, goodputSLOE2E)
+	if gpErr != nil {
+		logrus.Fatalf("%v", gpErr)
+	}
+	var specTargets map[string]workload.SLODimTargets
+	if spec != nil {
+		specTargets = spec.GoodputSLOTargets
+	}
+	goodputTargets := mergeGoodputTargets(cliTTFT, cliITL, cliE2E, nil, specTargets)
+
+	// Assemble allRequests for saturation analysis (BC-12, issue #1298).
+	// Trace export is now driven by the arrival hook above and no longer
+	// shares this slice (issue #1440). allRequests is nil when
+	// --saturation-report is not set.
+	//
+	// In lazy mode (#1441), preGeneratedRequests is nil — the arrival hook
+	// captures every fresh arrival in clock-monotonic order (already sorted
+	// by INV-3), so we use traceArrivals directly. In eager mode we keep
+	// the existing append+sort path for backward compatibility.
+	var allRequests []*sim.Request
+	if saturationReport != "" {
 		if lazyRequestSource != nil {
-			clusterRequestSource = lazyRequestSource
+			// traceArrivals already contains every fresh arrival in
+			// clock-monotonic order — no separate followUpRequests merge
+			// or post-sort required (the cluster delivers them in arrival
+			// order via the hook).
+			//
+			// SAFETY: allRequests aliases the same backing array as
+			// traceArrivals. Both downstream consumers (trace export
+			// below + saturation analysis) MUST be read-only of this
+			// slice — neither appends, reorders, nor mutates element
+			// contents. If a future consumer needs to mutate, copy
+			// first: `allRequests = append([]*sim.Request(nil), traceArrivals...)`.
+			allRequests = traceArrivals
 		} else {
-			clusterRequestSource = cluster.NewSliceRequestSource(preGeneratedRequests)
-		}
-		cs := cluster.NewClusterSimulator(config, clusterRequestSource, onRequestDone)
-
-		// Arrival hook: capture trace-emission references at the cluster's
-		// single arrival boundary so the trace exporter no longer relies on
-		// the eager preGeneratedRequests + followUpRequests list assembly
-		// (issue #1440). The hook fires once per fresh arrival in
-		// clock-monotonic order — see ClusterArrivalEvent.Execute. We hold
-		// pointers (not copies) so the request's final state (set by the
-		// event loop) is visible at export time.
-		//
-		// Only install when --trace-output is set (BC-1: zero overhead when
-		// trace is disabled). Saturation analysis continues to use the
-		// preGeneratedRequests + followUpRequests path below — those slices
-		// remain populated for that purpose only.
-		//
-		// Install/export coupling: traceArrivals is declared nil here and
-		// assigned a non-nil empty slice ONLY inside the install branch.
-		// A nil traceArrivals at the export site below with traceOutput
-		// non-empty means the install branch was dropped — we fail loudly
-		// rather than write a silent empty trace (R1).
-		// The arrival hook captures fresh-arrival references at the single
-		// cluster boundary. It powers trace export (#1440) and, in lazy mode
-		// where preGeneratedRequests is nil, also feeds saturation analysis.
-		// In eager mode without --trace-output, the hook stays uninstalled
-		// (BC-1 zero overhead) and saturation falls back to the
-		// preGeneratedRequests + followUpRequests path.
-		var traceArrivals []*sim.Request
-		arrivalHookNeeded := traceOutput != "" || (lazyRequestSource != nil && saturationReport != "")
-		if arrivalHookNeeded {
-			traceArrivals = make([]*sim.Request, 0)
-			cs.SetArrivalHook(func(req *sim.Request) {
-				traceArrivals = append(traceArrivals, req)
+			allRequests = make([]*sim.Request, 0, len(preGeneratedRequests)+len(followUpRequests))
+			allRequests = append(allRequests, preGeneratedRequests...)
+			allRequests = append(allRequests, followUpRequests...)
+			// Sort by arrival time so RequestIDs (array indices) are arrival-ordered
+			sort.SliceStable(allRequests, func(i, j int) bool {
+				return allRequests[i].ArrivalTime < allRequests[j].ArrivalTime
 			})
 		}
-		if err := cs.Run(); err != nil {
-			logrus.Fatalf("Simulation failed: %v", err)
-		}
+	}
 
-		// Surface any terminal sampler / generator error the lazy source
-		// recorded on a per-client state during the run. Eager mode would
-		// have hit logrus.Fatalf inside cmd on the same invalid spec;
-		// without this check, lazy mode would exit 0 with reduced traffic
-		// and misleading capacity numbers (PR #1453 review round 3).
-		if lazyRequestSource != nil {
-			if err := lazyRequestSource.Err(); err != nil {
-				logrus.Fatalf("Lazy workload sampler failure: %v", err)
-			}
+	// Export trace if requested (BC-1, BC-7). Records are sourced from
+	// the arrival hook (issue #1440) — already in clock-monotonic order
+	// per INV-3, so no sort is required.
+	if traceOutput != "" {
+		// Install/export coupling guard (R1): traceArrivals is a non-nil
+		// empty slice when SetArrivalHook ran above. A nil here means
+		// the install branch was dropped or moved without updating this
+		// site — refuse to write a silent empty trace.
+		if traceArrivals == nil {
+			logrus.Fatalf("Trace export: arrival hook was not installed but --trace-output=%q is set — install/export branches diverged (issue #1440)", traceOutput)
+		}
+		records := workload.RequestsToTraceRecords(traceArrivals)
+		header := &workload.TraceHeader{
+			Version:           3,
+			TimeUnit:          "microseconds",
+			Mode:              "generated",
+			WorkloadSeed:      &spec.Seed,
+			GoodputSLOTargets: goodputTargets, // #1413, BC-7: persist resolved targets for downstream replay/calibrate
+		}
+		if err := workload.ExportTraceV2(header, records, traceOutput+".yaml", traceOutput+".csv"); err != nil {
+			logrus.Fatalf("Trace export failed: %v", err)
+		}
+		logrus.Infof("Trace exported: %s.yaml, %s.csv (%d records)", traceOutput, traceOutput, len(records))
+	}
+
+	// Saturation analysis if requested (issue #1298, #1391, #1392)
+	if saturationReport != "" {
+		simEndUs := workload.ComputeSimEndUs(allRequests, config.Horizon)
+
+		// Validate classifier name (CLI gate; library factory panics on unknown).
+		if !sim.IsValidBacklogClassifier(saturationClassifier) {
+			logrus.Fatalf("Unknown --saturation-classifier %q. Valid: %s",
+				saturationClassifier, strings.Join(sim.ValidBacklogClassifierNames(), ", "))
+		}
+		classifier := workload.NewBacklogClassifier(saturationClassifier)
+
+		// Build saturation analysis config from flags (or defaults if not set)
+		cfg := workload.NewBacklogDriftConfig(
+			time.Duration(saturationWindowSec)*time.Second,
+			saturationMinWindows,
+			saturationPeakRatio,
+			saturationPeakBand,
+			saturationConfidence,
+			saturationWarmupWindows,
+			saturationTailWindows,
+			saturationSaturatedRatio,
+			saturationTransientRatio,
+		)
+		report := workload.AnalyzeBacklogDriftWithClassifier(allRequests, simEndUs, cfg, classifier)
+		if err := workload.WriteBacklogDriftReportJSON(saturationReport, report); err != nil {
+			logrus.Fatalf("Failed to write saturation report: %v", err)
+		}
+		logrus.Infof("Saturation report written to %s (classification: %s)", saturationReport, report.Classification)
+	}
+
+	// Validate and instantiate post-hoc saturation detector from CLI flags (#1369, C3)
+	if !saturation.ValidDetectorNames()[postHocDetector] {
+		logrus.Fatalf("--post-hoc-detector %q not recognized. Valid: composite, threshold, none", postHocDetector)
+	}
+
+	// Validate saturation threshold for negative values (I4)
+	if saturationThreshold < 0 {
+		logrus.Fatalf("--saturation-threshold-ms must be non-negative, got %.2f", saturationThreshold)
+	}
+
+	var saturationDetector sim.BatchClassifier
+	if postHocDetector != "none" {
+		saturationDetector = saturation.NewDetector(postHocDetector, saturation.DetectorOpts{
+			ThresholdMs: saturationThreshold,
+		})
+	}
+
+	if numInstances > 1 {
+		// Print per-instance metrics to stdout (multi-instance only)
+		for _, inst := range cs.Instances() {
+			inst.Metrics().PercentileMethod = sim.PercentileMethod(percentileMethod)
+			if err := inst.Metrics().SaveResults(string(inst.ID()), config.Horizon, totalKVBlocks, "", saturationDetector); err != nil {
+				logrus.Fatalf("SaveResults for instance %s: %v", inst.ID(), err)
+			}
+		}
+	}
+	// Build aggregate output, inject goodput, then emit (#1413).
+	aggregated := cs.AggregatedMetrics()
+	aggregated.PercentileMethod = sim.PercentileMethod(percentileMethod)
+	clusterOutput := aggregated.BuildOutput("cluster", saturationDetector)
+	clusterOutput.LatencyCI = aggregated.BootstrapLatencyCIs(bootstrapResamples, rc.seed)
+	clusterOutput.ClusterConservation = &sim.ClusterConservation{
+		Arrived:                 cs.ArrivedRequests(),
+		AdmissionRejected:       cs.RejectedRequests(),
+		RoutingRejections:       cs.RoutingRejections(),
+		EncodeRoutingRejections: cs.EncodeRoutingRejections(),
+		GatewayQueueDepth:       cs.GatewayQueueDepth(),
+		GatewayQueueShed:        cs.GatewayQueueShed(),
+		GatewayQueueRejected:    cs.GatewayQueueRejected(),
+		GatewayEvicted:          cs.GatewayEvicted(),
+		GatewayExpired:          cs.GatewayExpired(),
+		Failed:                  cs.FailedRequests(),
+		InPipeline:              cs.PipelineRequests(),
+	}
+	clusterOutput.AdmissionRetries = cs.RetriedRequests()
+	goodput := emitGoodput(&clusterOutput, aggregated, cs.InjectedByClass(),
+		float64(aggregated.SimEndedTime)/1e6, goodputTargets)
+	if err := aggregated.EmitOutput(clusterOutput, metricsPath); err != nil {
+		logrus.Fatalf("SaveResults: %v", err)
+	}
+
+	// Collect RawMetrics and compute fitness (PR9)
+	rawMetrics := cluster.CollectRawMetrics(
+		cs.AggregatedMetrics(),
+		cs.PerInstanceMetrics(),
+		cs.RejectedRequests(),
+		scheduler,
+		cs.RoutingRejections(),
+		cs.EncodeRoutingRejections(),
+		cs.InjectedByClass(),
+	)
+
+	rawMetrics.PD = cluster.CollectPDMetrics(
+		cs.ParentRequests(),
+		cs.AggregatedMetrics(),
+		cs.PoolMembership(),
+		cs.PerInstanceMetricsByID(),
+	)
+	rawMetrics.ShedByTier = cs.ShedByTier()                     // Phase 1B-1a: tier-shed per-tier breakdown (SC-004)
+	rawMetrics.GatewayQueueDepth = cs.GatewayQueueDepth()       // Issue #882: gateway queue depth at horizon
+	rawMetrics.GatewayQueueShed = cs.GatewayQueueShed()         // Issue #882: gateway queue shed count
+	rawMetrics.GatewayQueueRejected = cs.GatewayQueueRejected() // Issue #1190: gateway queue rejected count
+	rawMetrics.GatewayEvicted = cs.GatewayEvicted()             // Phase 4: in-flight eviction count (#1228)
+	rawMetrics.GatewayExpired = cs.GatewayExpired()             // Phase 6: TTL expiration count (#1193)
+	rawMetrics.AdmissionRetries = cs.RetriedRequests()          // admission retry model: re-attempts after rejection
+	rawMetrics.FailedRequests = cs.FailedRequests()             // fault injection: requests lost with a failed instance
+	rawMetrics.Goodput = goodput                                // SLO-meeting throughput; zero when no goodput targets
+
+	if rawMetrics.PD != nil && config.PDTransferContention {
+		rawMetrics.PD.PeakConcurrentTransfers = cs.PeakConcurrentTransfers()
+		rawMetrics.PD.MeanTransferQueueDepth = cs.MeanTransferQueueDepth()
+	}
+
+	if fitnessWeights != "" {
+		weights, err := cluster.ParseFitnessWeights(fitnessWeights)
+		if err != nil {
+			logrus.Fatalf("Invalid fitness weights: %v", err)
 		}
-
-		// Wall-clock timing on stderr (BC-6); stdout remains deterministic (BC-7)
-		logrus.Infof("Simulation wall-clock time: %.3fs", time.Since(startTime).Seconds())
-
-		// Resolve goodput SLO targets early so the trace export and aggregate metrics
-		// see the same merged map (#1413, BC-1). Run has no trace header; precedence
-		// here is CLI > workload spec.
-		cliTTFT, cliITL, cliE2E, gpErr := resolveGoodputCLIFlags(goodputSLOTTFT, goodputSLOITL, goodputSLOE2E)
-		if gpErr != nil {
-			logrus.Fatalf("%v", gpErr)
-		}
-		var specTargets map[string]workload.SLODimTargets
-		if spec != nil {
-			specTargets = spec.GoodputSLOTargets
-		}
-		goodputTargets := mergeGoodputTargets(cliTTFT, cliITL, cliE2E, nil, specTargets)
-
-		// Assemble allRequests for saturation analysis (BC-12, issue #1298).
-		// Trace export is now driven by the arrival hook above and no longer
-		// shares this slice (issue #1440). allRequests is nil when
-		// --saturation-report is not set.
-		//
-		// In lazy mode (#1441), preGeneratedRequests is nil — the arrival hook
-		// captures every fresh arrival in clock-monotonic order (already sorted
-		// by INV-3), so we use traceArrivals directly. In eager mode we keep
-		// the existing append+sort path for backward compatibility.
-		var allRequests []*sim.Request
-		if saturationReport != "" {
-			if lazyRequestSource != nil {
-				// traceArrivals already contains every fresh arrival in
-				// clock-monotonic order — no separate followUpRequests merge
-				// or post-sort required (the cluster delivers them in arrival
-				// order via the hook).
-				//
-				// SAFETY: allRequests aliases the same backing array as
-				// traceArrivals. Both downstream consumers (trace export
-				// below + saturation analysis) MUST be read-only of this
-				// slice — neither appends, reorders, nor mutates element
-				// contents. If a future consumer needs to mutate, copy
-				// first: `allRequests = append([]*sim.Request(nil), traceArrivals...)`.
-				allRequests = traceArrivals
-			} else {
-				allRequests = make([]*sim.Request, 0, len(preGeneratedRequests)+len(followUpRequests))
-				allRequests = append(allRequests, preGeneratedRequests...)
-				allRequests = append(allRequests, followUpRequests...)
-				// Sort by arrival time so RequestIDs (array indices) are arrival-ordered
-				sort.SliceStable(allRequests, func(i, j int) bool {
-					return allRequests[i].ArrivalTime < allRequests[j].ArrivalTime
-				})
-			}
+		fitness, fitErr := cluster.ComputeFitness(rawMetrics, weights)
+		if fitErr != nil {
+			logrus.Fatalf("Fitness evaluation failed: %v", fitErr)
 		}
-
-		// Export trace if requested (BC-1, BC-7). Records are sourced from
-		// the arrival hook (issue #1440) — already in clock-monotonic order
-		// per INV-3, so no sort is required.
-		if traceOutput != "" {
-			// Install/export coupling guard (R1): traceArrivals is a non-nil
-			// empty slice when SetArrivalHook ran above. A nil here means
-			// the install branch was dropped or moved without updating this
-			// site — refuse to write a silent empty trace.
-			if traceArrivals == nil {
-				logrus.Fatalf("Trace export: arrival hook was not installed but --trace-output=%q is set — install/export branches diverged (issue #1440)", traceOutput)
-			}
-			records := workload.RequestsToTraceRecords(traceArrivals)
-			header := &workload.TraceHeader{
-				Version:           3,
-				TimeUnit:          "microseconds",
-				Mode:              "generated",
-				WorkloadSeed:      &spec.Seed,
-				GoodputSLOTargets: goodputTargets, // #1413, BC-7: persist resolved targets for downstream replay/calibrate
-			}
-			if err := workload.ExportTraceV2(header, records, traceOutput+".yaml", traceOutput+".csv"); err != nil {
-				logrus.Fatalf("Trace export failed: %v", err)
-			}
-			logrus.Infof("Trace exported: %s.yaml, %s.csv (%d records)", traceOutput, traceOutput, len(records))
+		fmt.Println("=== Fitness Evaluation ===")
+		fmt.Printf("Score: %.6f\n", fitness.Score)
+		// Sort keys for deterministic output order
+		componentKeys := make([]string, 0, len(fitness.Components))
+		for k := range fitness.Components {
+			componentKeys = append(componentKeys, k)
+		}
+		sort.Strings(componentKeys)
+		for _, k := range componentKeys {
+			fmt.Printf("  %s: %.6f\n", k, fitness.Components[k])
 		}
+	}
 
-		// Saturation analysis if requested (issue #1298, #1391, #1392)
-		if saturationReport != "" {
-			simEndUs := workload.ComputeSimEndUs(allRequests, config.Horizon)
-
-			// Validate classifier name (CLI gate; library factory panics on unknown).
-			if !sim.IsValidBacklogClassifier(saturationClassifier) {
-				logrus.Fatalf("Unknown --saturation-classifier %q. Valid: %s",
-					saturationClassifier, strings.Join(sim.ValidBacklogClassifierNames(), ", "))
+	// Print anomaly counters if any detected
+	if rawMetrics.PriorityInversions > 0 || rawMetrics.HOLBlockingEvents > 0 || rawMetrics.RejectedRequests > 0 || rawMetrics.AdmissionRetries > 0 || rawMetrics.RoutingRejections > 0 || rawMetrics.DroppedUnservable > 0 || rawMetrics.LengthCappedRequests > 0 || rawMetrics.GatewayQueueDepth > 0 || rawMetrics.GatewayQueueShed > 0 || rawMetrics.GatewayQueueRejected > 0 || rawMetrics.GatewayEvicted > 0 || rawMetrics.GatewayExpired > 0 || rawMetrics.EncodeRoutingRejections > 0 || rawMetrics.TimedOutRequests > 0 || rawMetrics.FailedRequests > 0 {
+		fmt.Println("=== Anomaly Counters ===")
+		fmt.Printf("Priority Inversions: %d\n", rawMetrics.PriorityInversions)
+		fmt.Printf("HOL Blocking Events: %d\n", rawMetrics.HOLBlockingEvents)
+		fmt.Printf("Rejected Requests (Admission): %d\n", rawMetrics.RejectedRequests)
+		if rawMetrics.AdmissionRetries > 0 {
+			fmt.Printf("Admission Retries: %d\n", rawMetrics.AdmissionRetries)
+		}
+		if len(rawMetrics.ShedByTier) > 0 {
+			tierKeys := make([]string, 0, len(rawMetrics.ShedByTier))
+			for k := range rawMetrics.ShedByTier {
+				tierKeys = append(tierKeys, k)
 			}
-			classifier := workload.NewBacklogClassifier(saturationClassifier)
-
-			// Build saturation analysis config from flags (or defaults if not set)
-			cfg := workload.NewBacklogDriftConfig(
-				time.Duration(saturationWindowSec)*time.Second,
-				saturationMinWindows,
-				saturationPeakRatio,
-				saturationPeakBand,
-				saturationConfidence,
-				saturationWarmupWindows,
-				saturationTailWindows,
-				saturationSaturatedRatio,
-				saturationTransientRatio,
-			)
-			report := workload.AnalyzeBacklogDriftWithClassifier(allRequests, simEndUs, cfg, classifier)
-			if err := workload.WriteBacklogDriftReportJSON(saturationReport, report); err != nil {
-				logrus.Fatalf("Failed to write saturation report: %v", err)
+			sort.Strings(tierKeys) // R2/INV-6: deterministic output order
+			for _, tier := range tierKeys {
+				fmt.Printf("  Shed (%s): %d\n", tier, rawMetrics.ShedByTier[tier])
 			}
-			logrus.Infof("Saturation report written to %s (classification: %s)", saturationReport, report.Classification)
 		}
-
-		// Validate and instantiate post-hoc saturation detector from CLI flags (#1369, C3)
-		if !saturation.ValidDetectorNames()[postHocDetector] {
-			logrus.Fatalf("--post-hoc-detector %q not recognized. Valid: composite, threshold, none", postHocDetector)
+		fmt.Printf("Rejected Requests (Routing): %d\n", rawMetrics.RoutingRejections)
+		fmt.Printf("Dropped Unservable: %d\n", rawMetrics.DroppedUnservable)
+		printUnservableByReason(os.Stdout, rawMetrics.DroppedUnservableByReason)
+		fmt.Printf("Timed Out Requests: %d\n", rawMetrics.TimedOutRequests)
+		if rawMetrics.FailedRequests > 0 {
+			fmt.Printf("Failed Requests (Instance Fault): %d\n", rawMetrics.FailedRequests)
 		}
-
-		// Validate saturation threshold for negative values (I4)
-		if saturationThreshold < 0 {
-			logrus.Fatalf("--saturation-threshold-ms must be non-negative, got %.2f", saturationThreshold)
+		fmt.Printf("Length-Capped Requests: %d\n", rawMetrics.LengthCappedRequests)
+		if rawMetrics.GatewayQueueDepth > 0 {
+			fmt.Printf("Gateway Queue Depth (horizon): %d\n", rawMetrics.GatewayQueueDepth)
 		}
-
-		var saturationDetector sim.BatchClassifier
-		if postHocDetector != "none" {
-			saturationDetector = saturation.NewDetector(postHocDetector, saturation.DetectorOpts{
-				ThresholdMs: saturationThreshold,
-			})
+		if rawMetrics.GatewayQueueShed > 0 {
+			fmt.Printf("Gateway Queue Shed: %d\n", rawMetrics.GatewayQueueShed)
 		}
-
-		if numInstances > 1 {
-			// Print per-instance metrics to stdout (multi-instance only)
-			for _, inst := range cs.Instances() {
-				if err := inst.Metrics().SaveResults(string(inst.ID()), config.Horizon, totalKVBlocks, "", saturationDetector); err != nil {
-					logrus.Fatalf("SaveResults for instance %s: %v", inst.ID(), err)
-				}
-			}
+		if rawMetrics.GatewayQueueRejected > 0 {
+			fmt.Printf("Gateway Queue Rejected: %d\n", rawMetrics.GatewayQueueRejected)
 		}
-		// Build aggregate output, inject goodput, then emit (#1413).
-		aggregated := cs.AggregatedMetrics()
-		clusterOutput := aggregated.BuildOutput("cluster", saturationDetector)
-		emitGoodput(&clusterOutput, aggregated, cs.InjectedByClass(),
-			float64(aggregated.SimEndedTime)/1e6, goodputTargets)
-		if err := aggregated.EmitOutput(clusterOutput, metricsPath); err != nil {
-			logrus.Fatalf("SaveResults: %v", err)
-		}
-
-		// Collect RawMetrics and compute fitness (PR9)
-		rawMetrics := cluster.CollectRawMetrics(
-			cs.AggregatedMetrics(),
-			cs.PerInstanceMetrics(),
-			cs.RejectedRequests(),
-			scheduler,
-			cs.RoutingRejections(),
-			cs.EncodeRoutingRejections(),
-			cs.InjectedByClass(),
-		)
+		if rawMetrics.GatewayEvicted > 0 {
+			fmt.Printf("Gateway Evicted (in-flight): %d\n", rawMetrics.GatewayEvicted)
+		}
+		if rawMetrics.GatewayExpired > 0 {
+			fmt.Printf("Gateway Expired (TTL): %d\n", rawMetrics.GatewayExpired)
+		}
+		if rawMetrics.EncodeRoutingRejections > 0 {
+			fmt.Printf("Encode Routing Rejections: %d\n", rawMetrics.EncodeRoutingRejections)
+		}
+	}
 
-		rawMetrics.PD = cluster.CollectPDMetrics(
-			cs.ParentRequests(),
-			cs.AggregatedMetrics(),
-			cs.PoolMembership(),
-			cs.PerInstanceMetricsByID(),
-		)
-		rawMetrics.ShedByTier = cs.ShedByTier()                     // Phase 1B-1a: tier-shed per-tier breakdown (SC-004)
-		rawMetrics.GatewayQueueDepth = cs.GatewayQueueDepth()       // Issue #882: gateway queue depth at horizon
-		rawMetrics.GatewayQueueShed = cs.GatewayQueueShed()         // Issue #882: gateway queue shed count
-		rawMetrics.GatewayQueueRejected = cs.GatewayQueueRejected() // Issue #1190: gateway queue rejected count
-		rawMetrics.GatewayEvicted = cs.GatewayEvicted()             // Phase 4: in-flight eviction count (#1228)
-		rawMetrics.GatewayExpired = cs.GatewayExpired()             // Phase 6: TTL expiration count (#1193)
+	// Print KV cache metrics if any nonzero (BC-1, BC-2)
+	printKVCacheMetrics(os.Stdout, rawMetrics.PreemptionRate, rawMetrics.CacheHitRate, rawMetrics.KVThrashingRate)
+	printCacheHitBreakdown(os.Stdout, "Tenant", rawMetrics.CacheHitRateByTenant)
+	printCacheHitBreakdown(os.Stdout, "SLO Class", rawMetrics.CacheHitRateBySLOClass)
+	if printDiagnostics {
+		printBottleneck(os.Stdout, cs.AggregatedMetrics())
+		printBatchSize(os.Stdout, rawMetrics.BatchSize)
+	}
 
-		if rawMetrics.PD != nil && config.PDTransferContention {
-			rawMetrics.PD.PeakConcurrentTransfers = cs.PeakConcurrentTransfers()
-			rawMetrics.PD.MeanTransferQueueDepth = cs.MeanTransferQueueDepth()
-		}
+	// Print per-SLO metrics. With goodput targets configured, the section prints
+	// even for a single class (#1413, BC-5). Without goodput, the legacy
+	// suppression for ≤1 class is preserved.
+	sloDistributions := cluster.ComputePerSLODistributions(cs.AggregatedMetrics())
+	printPerSLOMetrics(os.Stdout, sloDistributions, len(goodputTargets) > 0)
 
-		if fitnessWeights != "" {
-			weights, err := cluster.ParseFitnessWeights(fitnessWeights)
-			if err != nil {
-				logrus.Fatalf("Invalid fitness weights: %v", err)
-			}
-			fitness, fitErr := cluster.ComputeFitness(rawMetrics, weights)
-			if fitErr != nil {
-				logrus.Fatalf("Fitness evaluation failed: %v", fitErr)
-			}
-			fmt.Println("=== Fitness Evaluation ===")
-			fmt.Printf("Score: %.6f\n", fitness.Score)
-			// Sort keys for deterministic output order
-			componentKeys := make([]string, 0, len(fitness.Components))
-			for k := range fitness.Components {
-				componentKeys = append(componentKeys, k)
-			}
-			sort.Strings(componentKeys)
-			for _, k := range componentKeys {
-				fmt.Printf("  %s: %.6f\n", k, fitness.Components[k])
-			}
-		}
+	// Print per-model metrics if requests carry model tags (Phase 1A, FR-011)
+	perModelMetrics := cluster.ComputePerModelMetrics(cs.AggregatedMetrics())
+	printPerModelMetrics(os.Stdout, perModelMetrics)
 
-		// Print anomaly counters if any detected
-		if rawMetrics.PriorityInversions > 0 || rawMetrics.HOLBlockingEvents > 0 || rawMetrics.RejectedRequests > 0 || rawMetrics.RoutingRejections > 0 || rawMetrics.DroppedUnservable > 0 || rawMetrics.LengthCappedRequests > 0 || rawMetrics.GatewayQueueDepth > 0 || rawMetrics.GatewayQueueShed > 0 || rawMetrics.GatewayQueueRejected > 0 || rawMetrics.GatewayEvicted > 0 || rawMetrics.GatewayExpired > 0 || rawMetrics.EncodeRoutingRejections > 0 || rawMetrics.TimedOutRequests > 0 {
-			fmt.Println("=== Anomaly Counters ===")
-			fmt.Printf("Priority Inversions: %d\n", rawMetrics.PriorityInversions)
-			fmt.Printf("HOL Blocking Events: %d\n", rawMetrics.HOLBlockingEvents)
-			fmt.Printf("Rejected Requests (Admission): %d\n", rawMetrics.RejectedRequests)
-			if len(rawMetrics.ShedByTier) > 0 {
-				tierKeys := make([]string, 0, len(rawMetrics.ShedByTier))
-				for k := range rawMetrics.ShedByTier {
-					tierKeys = append(tierKeys, k)
-				}
-				sort.Strings(tierKeys) // R2/INV-6: deterministic output order
-				for _, tier := range tierKeys {
-					fmt.Printf("  Shed (%s): %d\n", tier, rawMetrics.ShedByTier[tier])
-				}
-			}
-			fmt.Printf("Rejected Requests (Routing): %d\n", rawMetrics.RoutingRejections)
-			fmt.Printf("Dropped Unservable: %d\n", rawMetrics.DroppedUnservable)
-			fmt.Printf("Timed Out Requests: %d\n", rawMetrics.TimedOutRequests)
-			fmt.Printf("Length-Capped Requests: %d\n", rawMetrics.LengthCappedRequests)
-			if rawMetrics.GatewayQueueDepth > 0 {
-				fmt.Printf("Gateway Queue Depth (horizon): %d\n", rawMetrics.GatewayQueueDepth)
-			}
-			if rawMetrics.GatewayQueueShed > 0 {
-				fmt.Printf("Gateway Queue Shed: %d\n", rawMetrics.GatewayQueueShed)
-			}
-			if rawMetrics.GatewayQueueRejected > 0 {
-				fmt.Printf("Gateway Queue Rejected: %d\n", rawMetrics.GatewayQueueRejected)
-			}
-			if rawMetrics.GatewayEvicted > 0 {
-				fmt.Printf("Gateway Evicted (in-flight): %d\n", rawMetrics.GatewayEvicted)
-			}
-			if rawMetrics.GatewayExpired > 0 {
-				fmt.Printf("Gateway Expired (TTL): %d\n", rawMetrics.GatewayExpired)
-			}
-			if rawMetrics.EncodeRoutingRejections > 0 {
-				fmt.Printf("Encode Routing Rejections: %d\n", rawMetrics.EncodeRoutingRejections)
-			}
-		}
+	// Print per-tenant fairness metrics if any request carries a tenant label (Phase 1B-2b, FR-010)
+	perTenantMetrics := cluster.ComputePerTenantMetrics(cs.AggregatedMetrics())
+	printPerTenantMetrics(os.Stdout, perTenantMetrics)
 
-		// Print KV cache metrics if any nonzero (BC-1, BC-2)
-		printKVCacheMetrics(os.Stdout, rawMetrics.PreemptionRate, rawMetrics.CacheHitRate, rawMetrics.KVThrashingRate)
-
-		// Print per-SLO metrics. With goodput targets configured, the section prints
-		// even for a single class (#1413, BC-5). Without goodput, the legacy
-		// suppression for ≤1 class is preserved.
-		sloDistributions := cluster.ComputePerSLODistributions(cs.AggregatedMetrics())
-		printPerSLOMetrics(os.Stdout, sloDistributions, len(goodputTargets) > 0)
-
-		// Print per-model metrics if requests carry model tags (Phase 1A, FR-011)
-		perModelMetrics := cluster.ComputePerModelMetrics(cs.AggregatedMetrics())
-		printPerModelMetrics(os.Stdout, perModelMetrics)
-
-		// Print per-tenant fairness metrics if any request carries a tenant label (Phase 1B-2b, FR-010)
-		perTenantMetrics := cluster.ComputePerTenantMetrics(cs.AggregatedMetrics())
-		printPerTenantMetrics(os.Stdout, perTenantMetrics)
-
-		// Print session metrics if any request carries a session label (#1058)
-		sessionMetrics := cluster.ComputeSessionMetrics(cs.AggregatedMetrics())
-		printSessionMetrics(os.Stdout, sessionMetrics)
-
-		// Print PD disaggregation metrics if disaggregation was active (PR4)
-		printPDMetrics(os.Stdout, rawMetrics.PD, config.PDTransferContention)
-
-		// Build and print trace summary if requested (BC-9)
-		if cs.Trace() != nil && summarizeTrace {
-			traceSummary := trace.Summarize(cs.Trace())
-			fmt.Println("=== Trace Summary ===")
-			fmt.Printf("Total Decisions: %d\n", traceSummary.TotalDecisions)
-			fmt.Printf("  Admitted: %d\n", traceSummary.AdmittedCount)
-			fmt.Printf("  Rejected: %d\n", traceSummary.RejectedCount)
-			fmt.Printf("Unique Targets: %d\n", traceSummary.UniqueTargets)
-			if len(traceSummary.TargetDistribution) > 0 {
-				fmt.Println("Target Distribution:")
-				targetKeys := make([]string, 0, len(traceSummary.TargetDistribution))
-				for k := range traceSummary.TargetDistribution {
-					targetKeys = append(targetKeys, k)
-				}
-				sort.Strings(targetKeys)
-				for _, k := range targetKeys {
-					fmt.Printf("  %s: %d\n", k, traceSummary.TargetDistribution[k])
-				}
+	// Print session metrics if any request carries a session label (#1058)
+	sessionMetrics := cluster.ComputeSessionMetrics(cs.AggregatedMetrics())
+	printSessionMetrics(os.Stdout, sessionMetrics)
+
+	// Print PD disaggregation metrics if disaggregation was active (PR4)
+	printPDMetrics(os.Stdout, rawMetrics.PD, config.PDTransferContention)
+
+	// Build and print trace summary if requested (BC-9)
+	if cs.Trace() != nil && summarizeTrace {
+		traceSummary := trace.Summarize(cs.Trace())
+		fmt.Println("=== Trace Summary ===")
+		fmt.Printf("Total Decisions: %d\n", traceSummary.TotalDecisions)
+		fmt.Printf("  Admitted: %d\n", traceSummary.AdmittedCount)
+		fmt.Printf("  Rejected: %d\n", traceSummary.RejectedCount)
+		fmt.Printf("Unique Targets: %d\n", traceSummary.UniqueTargets)
+		if len(traceSummary.TargetDistribution) > 0 {
+			fmt.Println("Target Distribution:")
+			targetKeys := make([]string, 0, len(traceSummary.TargetDistribution))
+			for k := range traceSummary.TargetDistribution {
+				targetKeys = append(targetKeys, k)
+			}
+			sort.Strings(targetKeys)
+			for _, k := range targetKeys {
+				fmt.Printf("  %s: %d\n", k, traceSummary.TargetDistribution[k])
 			}
-			fmt.Printf("Mean Regret: %.6f\n", traceSummary.MeanRegret)
-			fmt.Printf("Max Regret: %.6f\n", traceSummary.MaxRegret)
 		}
+		fmt.Printf("Mean Regret: %.6f\n", traceSummary.MeanRegret)
+		fmt.Printf("Max Regret: %.6f\n", traceSummary.MaxRegret)
+	}
 
-		logrus.Info("Simulation complete.")
-	},
+	logrus.Info("Simulation complete.")
+	return clusterOutput
 }
 
 // printKVCacheMetrics prints KV cache metrics to w when any value is nonzero.
@@ -2467,6 +3196,66 @@ func printKVCacheMetrics(w io.Writer, preemptionRate, cacheHitRate, kvThrashingR
 	_, _ = fmt.Fprintf(w, "KV Thrashing Rate: %.4f\n", kvThrashingRate)
 }
 
+// printUnservableByReason prints the unservable-drop count for each reason,
+// sorted by reason name (R2/INV-6: deterministic output order).
+func printUnservableByReason(w io.Writer, byReason map[string]int) {
+	reasons := make([]string, 0, len(byReason))
+	for k := range byReason {
+		reasons = append(reasons, k)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		_, _ = fmt.Fprintf(w, "  Unservable (%s): %d\n", reason, byReason[reason])
+	}
+}
+
+// printBottleneck prints the run's primary bottleneck (sim.ClassifyBottleneck)
+// on one line with the numbers behind it. No-op when no batch was formed.
+func printBottleneck(w io.Writer, m *sim.Metrics) {
+	b, ok := sim.ClassifyBottleneck(m)
+	if !ok {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "=== Bottleneck ===")
+	_, _ = fmt.Fprintf(w, "%s: steps bound by arrivals %.2f, KV %.2f, batch slots %.2f, token budget %.2f, other %.2f; %.3f preemptions/request, GPU idle %.2f, KV utilization %.2f\n",
+		b.Label, b.ArrivalStepFraction, b.KVStepFraction, b.BatchSlotStepFraction, b.TokenBudgetStepFraction,
+		b.OtherStepFraction, b.PreemptionsPerRequest, b.GPUIdleFraction, b.KVUtilization)
+}
+
+// printBatchSize prints the distribution of running-batch sizes over steps.
+// No-op when no step ran.
+func printBatchSize(w io.Writer, d cluster.Distribution) {
+	if d.Count == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "=== Batch Size ===")
+	_, _ = fmt.Fprintf(w, "Mean: %.2f, P50: %.1f, P90: %.1f, P99: %.1f, Max: %.0f over %d steps\n",
+		d.Mean, d.P50, d.P90, d.P99, d.Max, d.Count)
+}
+
+// printCacheHitBreakdown prints prefix-cache hit rates grouped by dimension
+// (R2: sorted keys). No-op for fewer than two groups, so untagged or
+// single-tenant runs print nothing new (INV-6). Untagged requests are listed
+// as "(none)".
+func printCacheHitBreakdown(w io.Writer, dimension string, rates map[string]float64) {
+	if len(rates) < 2 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "=== Cache Hit Rate by %s ===\n", dimension)
+	keys := make([]string, 0, len(rates))
+	for k := range rates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		label := k
+		if label == "" {
+			label = "(none)"
+		}
+		_, _ = fmt.Fprintf(w, "  %s: %.4f\n", label, rates[k])
+	}
+}
+
 // printPerSLOMetrics prints per-SLO-class latency distributions. Without
 // goodput targets configured, the section is suppressed for ≤1 class (the
 // legacy no-spurious-section behavior). With goodput targets configured, the
@@ -2522,7 +3311,8 @@ func printPerModelMetrics(w io.Writer, perModelMetrics map[string]*cluster.Model
 	}
 }
 
-// printPerTenantMetrics prints per-tenant request counts, token totals, and Jain fairness index.
+// printPerTenantMetrics prints per-tenant request counts, token totals, scheduling delays,
+// and the Jain fairness indices over tokens and mean scheduling delay.
 // Follows the same pattern as printPerModelMetrics (R2: sorted keys).
 // No-op when perTenantMetrics is nil or empty.
 func printPerTenantMetrics(w io.Writer, perTenantMetrics map[string]*cluster.TenantMetrics) {
@@ -2538,11 +3328,13 @@ func printPerTenantMetrics(w io.Writer, perTenantMetrics map[string]*cluster.Ten
 	tokenMap := make(map[string]float64, len(perTenantMetrics))
 	for _, tid := range keys {
 		tm := perTenantMetrics[tid]
-		_, _ = fmt.Fprintf(w, "  %s: requests=%d, tokens=%d\n", tid, tm.CompletedRequests, tm.TotalTokensServed)
+		_, _ = fmt.Fprintf(w, "  %s: requests=%d, tokens=%d, sched_delay_mean=%.2fms, sched_delay_p99=%.2fms\n",
+			tid, tm.CompletedRequests, tm.TotalTokensServed, tm.SchedulingDelayMeanMs, tm.SchedulingDelayP99Ms)
 		tokenMap[tid] = float64(tm.TotalTokensServed)
 	}
 	jain := cluster.JainFairnessIndex(tokenMap)
 	_, _ = fmt.Fprintf(w, "  Jain Fairness Index: %.4f\n", jain)
+	_, _ = fmt.Fprintf(w, "  Scheduling Delay Fairness Index: %.4f\n", cluster.SchedulingDelayFairnessIndex(perTenantMetrics))
 }
 
 // printSessionMetrics writes the session metrics section to w.
@@ -2605,32 +3397,42 @@ func Execute() {
 	}
 }
 
+// registerWorkloadGenFlags registers the rate and token-distribution flags that
+// synthesize a workload without a spec file. Shared by run and bench-compare.
+func registerWorkloadGenFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&workloadType, "workload", "distribution", "Workload type (chatbot, summarization, contentgen, multidoc, distribution)")
+
+	cmd.Flags().Float64Var(&rate, "rate", 1.0, "Requests arrival per second")
+	cmd.Flags().IntVar(&numRequests, "num-requests", 100, "Number of requests to generate")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "Number of concurrent virtual users (closed-loop, mutually exclusive with --rate)")
+	cmd.Flags().IntVar(&thinkTimeMs, "think-time-ms", 0, "Think time in ms between response and next request (concurrency mode)")
+	cmd.Flags().IntVar(&prefixTokens, "prefix-tokens", 0, "Prefix Token Count")
+	cmd.Flags().IntVar(&promptTokensMean, "prompt-tokens", defaultPromptMean, "Average Prompt Token Count")
+	cmd.Flags().IntVar(&promptTokensStdev, "prompt-tokens-stdev", defaultPromptStdev, "Stddev Prompt Token Count")
+	cmd.Flags().IntVar(&promptTokensMin, "prompt-tokens-min", defaultPromptMin, "Min Prompt Token Count")
+	cmd.Flags().IntVar(&promptTokensMax, "prompt-tokens-max", defaultPromptMax, "Max Prompt Token Count")
+	cmd.Flags().IntVar(&outputTokensMean, "output-tokens", defaultOutputMean, "Average Output Token Count")
+	cmd.Flags().IntVar(&outputTokensStdev, "output-tokens-stdev", defaultOutputStdev, "Stddev Output Token Count")
+	cmd.Flags().IntVar(&outputTokensMin, "output-tokens-min", defaultOutputMin, "Min Output Token Count")
+	cmd.Flags().IntVar(&outputTokensMax, "output-tokens-max", defaultOutputMax, "Max Output Token Count")
+}
+
 // init sets up CLI flags and subcommands
 func init() {
 	registerSimConfigFlags(runCmd)
 
-	// Workload generation flags (run-only)
-	runCmd.Flags().StringVar(&workloadType, "workload", "distribution", "Workload type (chatbot, summarization, contentgen, multidoc, distribution)")
-
-	runCmd.Flags().Float64Var(&rate, "rate", 1.0, "Requests arrival per second")
-	runCmd.Flags().IntVar(&numRequests, "num-requests", 100, "Number of requests to generate")
-	runCmd.Flags().IntVar(&concurrency, "concurrency", 0, "Number of concurrent virtual users (closed-loop, mutually exclusive with --rate)")
-	runCmd.Flags().IntVar(&thinkTimeMs, "think-time-ms", 0, "Think time in ms between response and next request (concurrency mode)")
-	runCmd.Flags().IntVar(&prefixTokens, "prefix-tokens", 0, "Prefix Token Count")
-	runCmd.Flags().IntVar(&promptTokensMean, "prompt-tokens", defaultPromptMean, "Average Prompt Token Count")
-	runCmd.Flags().IntVar(&promptTokensStdev, "prompt-tokens-stdev", defaultPromptStdev, "Stddev Prompt Token Count")
-	runCmd.Flags().IntVar(&promptTokensMin, "prompt-tokens-min", defaultPromptMin, "Min Prompt Token Count")
-	runCmd.Flags().IntVar(&promptTokensMax, "prompt-tokens-max", defaultPromptMax, "Max Prompt Token Count")
-	runCmd.Flags().IntVar(&outputTokensMean, "output-tokens", defaultOutputMean, "Average Output Token Count")
-	runCmd.Flags().IntVar(&outputTokensStdev, "output-tokens-stdev", defaultOutputStdev, "Stddev Output Token Count")
-	runCmd.Flags().IntVar(&outputTokensMin, "output-tokens-min", defaultOutputMin, "Min Output Token Count")
-	runCmd.Flags().IntVar(&outputTokensMax, "output-tokens-max", defaultOutputMax, "Max Output Token Count")
+	// Workload generation flags
+	registerWorkloadGenFlags(runCmd)
 	runCmd.Flags().StringVar(&workloadSpecPath, "workload-spec", "", "Path to YAML workload specification file (overrides --workload)")
+	runCmd.Flags().BoolVar(&kvPrefixSeed, "kv-prefix-seed", false, "Seed every instance's KV cache with the workload's prefix-group prefixes before arrivals begin, charging their prefill time once to the instance's first step")
 	runCmd.Flags().BoolVar(&lazyGeneration, "lazy-generation", false, "Alpha (#1441): stream requests from the workload generator instead of pre-generating the full slice. Default off. Supports every workload class — single-shot, single- and multi-session reasoning (#1458), concurrency clients (#1459), and time-varying / per-window workloads (#1460); no eager fallback.")
 	runCmd.Flags().IntVar(&requestTimeoutSecs, "timeout", 300, "Per-request deadline in seconds (default 300s matches the session-client default in computeDeadline). Negative = disabled; 0 is rejected. Consistent with blis observe: both commands reject 0.")
 	runCmd.Flags().StringVar(&goodputSLOTTFT, "slo-ttft", "", "Per-class TTFT goodput thresholds (e.g. \"critical=100ms,standard=500ms\"). Precedence: CLI > trace header > workload spec.")
 	runCmd.Flags().StringVar(&goodputSLOITL, "slo-itl", "", "Per-class mean ITL goodput thresholds (e.g. \"critical=50ms,standard=150ms\").")
 	runCmd.Flags().StringVar(&goodputSLOE2E, "slo-e2e", "", "Per-class E2E goodput thresholds (e.g. \"critical=5s,standard=30s\").")
+	runCmd.Flags().IntVar(&replications, "replications", 1, "Run the simulation N times with seeds --seed, --seed+1, ... and print mean ± stddev of throughput and P99 latency across runs. The seed overrides any workload-spec seed.")
+	runCmd.Flags().StringVar(&percentileMethod, "percentile-method", string(sim.PercentileLinear), "Latency percentile method: linear (interpolate between ranks, vLLM benchmark parity) or nearest-rank")
+	runCmd.Flags().IntVar(&bootstrapResamples, "bootstrap-resamples", 0, "Number of bootstrap resamples for 95% confidence intervals on TTFT/E2E/ITL P50/P90/P99 (0 = disabled). Seeded from --seed.")
 
 	// Run-specific export
 	runCmd.Flags().StringVar(&traceOutput, "trace-output", "", "Export workload as TraceV2 files (<prefix>.yaml + <prefix>.csv)")
@@ -2642,6 +3444,14 @@ func init() {
 	runCmd.Flags().Float64Var(&saturationThreshold, "saturation-threshold-ms", 5000.0, "Threshold in ms for threshold detector (default 5000ms)")
 
 	registerSaturationFlags(runCmd)
+	runCmd.Flags().StringVar(&eventLogPath, "event-log", "", "Write every executed event (tick, type, instance, request ID) to this JSONL file for debugging")
+	runCmd.Flags().StringVar(&otlpTracePath, "otlp-trace", "", "Write each completed request's lifecycle (queued, prefill, decode) as OTLP/JSON spans to this file, importable into Jaeger")
+	runCmd.Flags().StringToInt64Var(&ingressClockOffsets, "ingress-clock-offsets", nil, "Per-ingress-point arrival clock offsets in µs (e.g. \"us-east=0,eu-west=-2000\"): arrivals of clients with a matching ingress_point are shifted by the offset before routing")
+	runCmd.Flags().StringVar(&kvExportPath, "kv-export-state", "", "Write each instance's cached prefixes (token sequences) at the end of the run to this JSON file, for --kv-import-state in a later run")
+	runCmd.Flags().StringVar(&routingLogPath, "routing-log-output", "", "Write the run's routing-decision log (request ID -> instance ID) to this JSON file, for --routing-replay-log in a later run. Requires --trace-level decisions")
+	runCmd.Flags().StringVar(&routingReplayLogPath, "routing-replay-log", "", "Routing-decision log from --routing-log-output for --routing-policy replay: each request is routed to its logged instance; a request missing from the log fails the run")
+	runCmd.Flags().StringVar(&kvImportPath, "kv-import-state", "", "Warm-start each instance's KV cache from a --kv-export-state file (matched by instance position) before arrivals begin; no prefill time is charged")
+	runCmd.Flags().StringVar(&kvStatePath, "dump-kv-state", "", "Write each instance's final KV prefix index (cached prefix hashes, prefix block counts, last-access ticks) to this JSON file for debugging")
 
 	// Attach `run` as a subcommand to `root`
 	rootCmd.AddCommand(runCmd)
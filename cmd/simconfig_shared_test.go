@@ -73,10 +73,11 @@ func TestRunCmd_SimConfigFlagsParity(t *testing.T) {
 	// WHEN we check for latency-model related flags
 	// THEN both commands must have the exact same set (registered via registerSimConfigFlags)
 	latencyFlags := []string{
-		"latency-model", "hardware", "tp", "dp", "enable-expert-parallel",
+		"latency-model", "step-time-table", "hardware", "tp", "dp", "enable-expert-parallel",
 		"alpha-coeffs", "beta-coeffs",
 		"total-kv-blocks", "block-size-in-tokens", "max-model-len",
-		"gpu-memory-utilization", "model-config-folder", "hardware-config",
+		"gpu-memory-utilization", "model-config-folder", "hardware-config", "fleet-inventory",
+		"compute-dtype", "kv-cache-dtype",
 	}
 	for _, name := range latencyFlags {
 		runFlag := runCmd.Flags().Lookup(name)
@@ -104,11 +105,13 @@ func TestResolvePolicies_InvalidAdmissionPolicy_Fatal(t *testing.T) {
 // consumed by resolvePolicies are registered in both runCmd and replayCmd (BC-2).
 func TestResolvePolicies_PolicyFlagsRegisteredInBothCommands(t *testing.T) {
 	policyFlags := []string{
-		"admission-policy", "routing-policy", "scheduler", "preemption-policy",
-		"routing-scorers", "lora-scorer-weight", "token-bucket-capacity", "token-bucket-refill-rate",
+		"admission-policy", "routing-policy", "scheduler", "preemption-policy", "preemption-mode", "swap-space-blocks", "priority-policy",
+		"routing-scorers", "routing-weights", "lora-scorer-weight", "prefix-hash-skip-tokens", "token-bucket-capacity", "token-bucket-refill-rate",
 		"kv-cpu-blocks", "kv-offload-threshold", "kv-transfer-bandwidth",
-		"kv-transfer-base-latency", "snapshot-refresh-interval",
-		"admission-latency", "routing-latency", "trace-level",
+		"kv-transfer-base-latency", "kv-refcount-checks", "snapshot-refresh-interval",
+		"admission-latency", "admission-latency-dist", "admission-latency-stddev",
+		"routing-latency", "trace-level",
+		"retry-max-attempts", "retry-backoff",
 		"counterfactual-k", "summarize-trace", "policy-config",
 		"cache-signal-delay",
 	}
@@ -152,18 +155,31 @@ func TestReplayCmd_SourceContainsNoPolicyInlineBlocks(t *testing.T) {
 // and resolvePolicies have identical default values in runCmd and replayCmd.
 func TestBothCommands_SimConfigFlagsHaveIdenticalDefaults(t *testing.T) {
 	sharedFlags := []string{
-		"latency-model", "hardware", "tp", "dp", "enable-expert-parallel",
+		"latency-model", "step-time-table", "hardware", "tp", "dp", "enable-expert-parallel",
 		"alpha-coeffs", "beta-coeffs",
 		"total-kv-blocks", "block-size-in-tokens", "max-model-len",
-		"gpu-memory-utilization", "model-config-folder", "hardware-config",
-		"admission-policy", "routing-policy", "scheduler", "preemption-policy",
-		"routing-scorers", "lora-scorer-weight", "token-bucket-capacity", "token-bucket-refill-rate",
+		"gpu-memory-utilization", "model-config-folder", "hardware-config", "fleet-inventory",
+		"compute-dtype", "kv-cache-dtype",
+		"admission-policy", "routing-policy", "scheduler", "preemption-policy", "preemption-mode", "swap-space-blocks", "priority-policy",
+		"routing-scorers", "routing-weights", "routing-tie-break", "prefix-hash-skip-tokens", "prefill-routing-policy", "decode-routing-policy",
+		"lora-scorer-weight", "token-bucket-capacity", "token-bucket-refill-rate",
 		"kv-cpu-blocks", "kv-offload-threshold", "kv-transfer-bandwidth",
-		"kv-transfer-base-latency", "snapshot-refresh-interval",
-		"admission-latency", "routing-latency", "trace-level",
+		"kv-transfer-base-latency", "kv-refcount-checks", "snapshot-refresh-interval",
+		"admission-latency", "admission-latency-dist", "admission-latency-stddev",
+		"routing-latency", "trace-level",
+		"retry-max-attempts", "retry-backoff", "max-inflight-tokens",
 		"counterfactual-k", "summarize-trace", "policy-config",
 		"num-instances", "max-num-running-reqs", "max-num-scheduled-tokens",
+		"fault-instance", "fault-at", "fault-mode",
+		"shared-prefix-cache", "remote-prefix-fetch-us-per-block",
 		"long-prefill-token-threshold", "cache-signal-delay",
+		"warmup-steps", "warmup-factor", "max-queue-depth", "queue-overflow-policy", "max-queue-wait",
+		"min-batch-fill", "batch-fill-max-wait",
+		"rand-source", "kv-pressure-threshold", "detokenization-us-per-token", "max-output-tokens", "tokens-per-decode-step", "decode-length-buckets", "decode-quantum-steps", "critical-reserve-fraction", "adaptive-prefill-chunk-min", "step-ordering",
+		"power-idle-watts", "power-peak-watts", "power-cap-watts", "scheduling-overhead-us-per-seq", "scheduling-contention-us-per-seq-sq", "min-step-time-us", "step-noise", "step-noise-correlation",
+		"roofline-block-table", "roofline-accounting",
+		"stop-after-completed", "throughput-sample-interval", "kv-sample-interval", "print-diagnostics",
+		"kv-allocation-mode", "kv-fair-share-max-blocks",
 		"flow-control", "saturation-detector", "dispatch-order",
 		"max-gateway-queue-depth", "queue-depth-threshold",
 		"kv-cache-util-threshold", "max-concurrency",
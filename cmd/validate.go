@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+var validateResultsPath string
+
+// invariantViolation is one failed check reported by `blis validate`.
+// RequestID is empty for file-level checks.
+type invariantViolation struct {
+	Invariant string
+	RequestID string
+	Detail    string
+}
+
+func (v invariantViolation) String() string {
+	if v.RequestID != "" {
+		return fmt.Sprintf("[%s] request %s: %s", v.Invariant, v.RequestID, v.Detail)
+	}
+	return fmt.Sprintf("[%s] %s", v.Invariant, v.Detail)
+}
+
+// checkResultsInvariants verifies the invariants a MetricsOutput written by
+// --metrics-path must satisfy and returns every violation found, file-level
+// checks first, then per-request checks in file order:
+//
+//   - conservation (INV-1): cluster_conservation.arrived, counted at the
+//     cluster before any decision, equals the instance terminal and in-flight
+//     buckets plus the cluster-level ones (admission and routing rejections,
+//     gateway queue, failures, requests not yet queued at the horizon). Files without cluster_conservation (written
+//     before it existed) fall back to injected_requests, which BuildOutput
+//     derives from the instance buckets and so only catches hand edits.
+//   - percentile-monotonicity: p90 <= p95 <= p99 for TTFT, E2E and ITL
+//   - cache-hit-rate: every cache hit rate lies in [0, 1]
+//   - e2e>=ttft: per completed request (incomplete requests have e2e_ms 0)
+//   - itl>=0 and ttft>=0: per request
+//
+// Non-finite values are never valid and are reported by whichever check reads them.
+func checkResultsInvariants(out sim.MetricsOutput) []invariantViolation {
+	var vs []invariantViolation
+	add := func(inv, reqID, format string, args ...any) {
+		vs = append(vs, invariantViolation{Invariant: inv, RequestID: reqID, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	accounted := out.CompletedRequests + out.StillQueued + out.StillRunning + out.DroppedUnservable +
+		out.TimedOutRequests + out.QueueOverflowRejected + out.QueueOverflowDropped
+	if cc := out.ClusterConservation; cc != nil {
+		clusterAccounted := accounted + cc.AdmissionRejected + cc.RoutingRejections + cc.EncodeRoutingRejections +
+			cc.GatewayQueueDepth + cc.GatewayQueueShed + cc.GatewayQueueRejected + cc.GatewayEvicted +
+			cc.GatewayExpired + cc.Failed + cc.InPipeline
+		if clusterAccounted != cc.Arrived {
+			add("conservation", "", "arrived %d != completed %d + still_queued %d + still_running %d + dropped_unservable %d + timed_out %d + queue_overflow_rejected %d + queue_overflow_dropped %d + admission_rejected %d + routing_rejections %d + encode_routing_rejections %d + gateway_queue_depth %d + gateway_queue_shed %d + gateway_queue_rejected %d + gateway_evicted %d + gateway_expired %d + failed %d + in_pipeline %d (= %d)",
+				cc.Arrived, out.CompletedRequests, out.StillQueued, out.StillRunning, out.DroppedUnservable,
+				out.TimedOutRequests, out.QueueOverflowRejected, out.QueueOverflowDropped,
+				cc.AdmissionRejected, cc.RoutingRejections, cc.EncodeRoutingRejections, cc.GatewayQueueDepth,
+				cc.GatewayQueueShed, cc.GatewayQueueRejected, cc.GatewayEvicted, cc.GatewayExpired, cc.Failed, cc.InPipeline, clusterAccounted)
+		}
+	} else if accounted != out.InjectedRequests {
+		add("conservation", "", "injected_requests %d != completed %d + still_queued %d + still_running %d + dropped_unservable %d + timed_out %d + queue_overflow_rejected %d + queue_overflow_dropped %d (= %d)",
+			out.InjectedRequests, out.CompletedRequests, out.StillQueued, out.StillRunning, out.DroppedUnservable,
+			out.TimedOutRequests, out.QueueOverflowRejected, out.QueueOverflowDropped, accounted)
+	}
+
+	for _, p := range []struct {
+		name          string
+		p90, p95, p99 float64
+	}{
+		{"ttft", out.TTFTP90Ms, out.TTFTP95Ms, out.TTFTP99Ms},
+		{"e2e", out.E2EP90Ms, out.E2EP95Ms, out.E2EP99Ms},
+		{"itl", out.ITLP90Ms, out.ITLP95Ms, out.ITLP99Ms},
+	} {
+		// Negated comparisons so NaN fails too.
+		if !(p.p90 <= p.p95 && p.p95 <= p.p99) {
+			add("percentile-monotonicity", "", "%s_p90_ms %v <= %s_p95_ms %v <= %s_p99_ms %v does not hold",
+				p.name, p.p90, p.name, p.p95, p.name, p.p99)
+		}
+	}
+
+	checkRate := func(field string, rate float64) {
+		if !(rate >= 0 && rate <= 1) {
+			add("cache-hit-rate", "", "%s = %v, want a value in [0, 1]", field, rate)
+		}
+	}
+	checkRate("cache_hit_rate", out.CacheHitRate)
+	for _, k := range sortedRateKeys(out.CacheHitRateByTenant) {
+		checkRate(fmt.Sprintf("cache_hit_rate_by_tenant[%q]", k), out.CacheHitRateByTenant[k])
+	}
+	for _, k := range sortedRateKeys(out.CacheHitRateBySLOClass) {
+		checkRate(fmt.Sprintf("cache_hit_rate_by_slo_class[%q]", k), out.CacheHitRateBySLOClass[k])
+	}
+
+	for _, r := range out.Requests {
+		if !(r.TTFT >= 0) {
+			add("ttft>=0", r.ID, "ttft_ms = %v", r.TTFT)
+		}
+		if !(r.ITL >= 0) {
+			add("itl>=0", r.ID, "itl_ms = %v", r.ITL)
+		}
+		if r.E2E != 0 && !(r.E2E >= r.TTFT) {
+			add("e2e>=ttft", r.ID, "e2e_ms %v < ttft_ms %v", r.E2E, r.TTFT)
+		}
+	}
+	return vs
+}
+
+// sortedRateKeys returns the keys of rates in sorted order (R2).
+func sortedRateKeys(rates map[string]float64) []string {
+	keys := make([]string, 0, len(rates))
+	for k := range rates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printInvariantViolations writes one line per violation, or a pass summary.
+func printInvariantViolations(w io.Writer, out sim.MetricsOutput, vs []invariantViolation) {
+	if len(vs) == 0 {
+		_, _ = fmt.Fprintf(w, "OK: all invariants hold (%d requests checked)\n", len(out.Requests))
+		return
+	}
+	for _, v := range vs {
+		_, _ = fmt.Fprintf(w, "VIOLATION %s\n", v)
+	}
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check a MetricsOutput JSON file for invariant violations",
+	Long: "Load a --metrics-path results file and verify request conservation, p90 <= p95 <= p99 for " +
+		"TTFT/E2E/ITL, cache hit rates in [0, 1], and per request E2E >= TTFT and non-negative " +
+		"TTFT/ITL. Prints each violation with the offending request and exits non-zero on any, for CI gating.",
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := loadMetricsOutput(validateResultsPath)
+		if err != nil {
+			logrus.Fatalf("Failed to load results: %v", err)
+		}
+		vs := checkResultsInvariants(out)
+		printInvariantViolations(os.Stdout, out, vs)
+		if len(vs) > 0 {
+			logrus.Fatalf("%d invariant violation(s) in %s", len(vs), validateResultsPath)
+		}
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&validateResultsPath, "results", "", "Path to the MetricsOutput JSON file written by --metrics-path")
+	_ = validateCmd.MarkFlagRequired("results")
+
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim/workload"
+)
+
+var workloadGenTarget workload.TargetLoad
+
+var workloadGenCmd = &cobra.Command{
+	Use:   "workload-gen",
+	Short: "Generate a v2 workload spec from target load characteristics",
+	Long: "Build a v2 WorkloadSpec from a target request rate, token-length statistics, tenant count, " +
+		"and prefix-sharing fraction. Output is written to stdout.",
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := workload.GenerateSpecFromTarget(workloadGenTarget)
+		if err != nil {
+			logrus.Fatalf("workload-gen failed: %v", err)
+		}
+		writeSpecToStdout(spec)
+	},
+}
+
+func init() {
+	f := workloadGenCmd.Flags()
+	f.Float64Var(&workloadGenTarget.RPS, "rps", 1.0, "Target aggregate request rate in req/s")
+	f.Float64Var(&workloadGenTarget.InputMean, "input-mean", 512, "Mean input tokens per request (shared prefix included)")
+	f.Float64Var(&workloadGenTarget.InputStdDev, "input-std", 128, "Input token standard deviation")
+	f.Float64Var(&workloadGenTarget.OutputMean, "output-mean", 256, "Mean output tokens per request")
+	f.Float64Var(&workloadGenTarget.OutputStdDev, "output-std", 64, "Output token standard deviation")
+	f.IntVar(&workloadGenTarget.Tenants, "tenants", 1, "Number of tenants sharing the load equally")
+	f.Float64Var(&workloadGenTarget.PrefixFraction, "prefix-fraction", 0, "Fraction of requests starting with their tenant's shared prefix, in [0, 1]")
+	f.IntVar(&workloadGenTarget.PrefixLength, "prefix-length", 256, "Shared prefix length in tokens (used when --prefix-fraction > 0)")
+	f.Int64Var(&workloadGenTarget.NumRequests, "num-requests", 0, "Request cap written to the spec (0 = bounded by the horizon only)")
+	f.Int64Var(&workloadGenTarget.Seed, "seed", 42, "Seed written to the spec")
+
+	rootCmd.AddCommand(workloadGenCmd)
+}
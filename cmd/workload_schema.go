@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/inference-sim/inference-sim/sim/workload"
+)
+
+var workloadSchemaCmd = &cobra.Command{
+	Use:   "workload-schema",
+	Short: "Print the JSON Schema for v2 workload spec files",
+	Long: "Write a JSON Schema (draft 2020-12) describing the --workload-spec YAML format to stdout, " +
+		"for editor completion and pre-flight linting of hand-written specs. Unknown keys are rejected " +
+		"and arrival processes, distribution types, categories, and slo_class values are enumerated; " +
+		"cross-field rules are still checked only by blis itself.",
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := json.MarshalIndent(workload.WorkloadSpecJSONSchema(), "", "  ")
+		if err != nil {
+			logrus.Fatalf("JSON marshal failed: %v", err)
+		}
+		fmt.Println(string(data))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workloadSchemaCmd)
+}
@@ -0,0 +1,24 @@
+package sim
+
+// adaptivePrefillThreshold returns the prefill chunk size for this step under
+// adaptive chunking (SimConfig.AdaptivePrefillChunkMin > 0): the token budget
+// scaled by the share of the running batch that is not decoding, never below
+// AdaptivePrefillChunkMin. A decode-heavy batch gets small chunks, so a long
+// prompt cannot stretch the step its decode requests are waiting on; a
+// prefill-dominated batch gets chunks up to the whole budget.
+func adaptivePrefillThreshold(ctx BatchContext) int64 {
+	running := ctx.RunningBatch.Requests
+	var decoding int64
+	for _, req := range running {
+		if req.ProgressIndex >= req.InputLen() && len(req.OutputTokens) > 0 {
+			decoding++
+		}
+	}
+	chunk := ctx.MaxScheduledTokens
+	if decoding > 0 {
+		// Count one prospective prefill so a batch of pure decodes still
+		// leaves room for a chunk.
+		chunk = chunk * (int64(len(running)) + 1 - decoding) / (int64(len(running)) + 1)
+	}
+	return max(chunk, ctx.AdaptivePrefillChunkMin)
+}
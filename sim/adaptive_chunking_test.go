@@ -0,0 +1,119 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// runDecodesWithLongPrompts runs 8 long-output decode requests at t=0 while
+// 6 long-prompt (8192-token) requests arrive every 40ms, and returns the
+// standard deviation of the decode requests' ITLs (ticks) and the prefill
+// throughput of the long prompts (tokens per tick, from the first arrival to
+// the last first token).
+func runDecodesWithLongPrompts(t *testing.T, threshold, adaptiveMin int64) (itlStdDev, prefillRate float64) {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(64, 4096, threshold)
+	cfg.AdaptivePrefillChunkMin = adaptiveMin
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	decodes := make([]*Request, 8)
+	for i := range decodes {
+		decodes[i] = &Request{
+			ID:           fmt.Sprintf("decode_%d", i),
+			InputTokens:  make([]TokenID, 64),
+			OutputTokens: make([]TokenID, 400),
+			MaxOutputLen: 400,
+			State:        StateQueued,
+		}
+		s.InjectArrival(decodes[i])
+	}
+	const longPrompts, promptLen, gap = 6, 8192, 40000
+	for i := 0; i < longPrompts; i++ {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("long_%d", i),
+			ArrivalTime:  int64(gap * (i + 1)),
+			InputTokens:  make([]TokenID, promptLen),
+			OutputTokens: make([]TokenID, 4),
+			MaxOutputLen: 4,
+			State:        StateQueued,
+		})
+	}
+	s.Run()
+	if s.Metrics.CompletedRequests != len(decodes)+longPrompts {
+		t.Fatalf("CompletedRequests = %d, want %d", s.Metrics.CompletedRequests, len(decodes)+longPrompts)
+	}
+
+	var sum, sumSq, n float64
+	for _, req := range decodes {
+		for _, itl := range req.ITL {
+			sum += float64(itl)
+			sumSq += float64(itl) * float64(itl)
+			n++
+		}
+	}
+	mean := sum / n
+	itlStdDev = math.Sqrt(sumSq/n - mean*mean)
+
+	var lastFirstToken float64
+	for i := 0; i < longPrompts; i++ {
+		ttft := s.Metrics.RequestTTFTs[fmt.Sprintf("long_%d", i)]
+		lastFirstToken = max(lastFirstToken, float64(gap*(i+1))+ttft)
+	}
+	prefillRate = float64(longPrompts*promptLen) / (lastFirstToken - gap)
+	return itlStdDev, prefillRate
+}
+
+// TestAdaptivePrefillChunk_LowerITLVarianceAtComparablePrefillThroughput
+// verifies that shrinking prefill chunks under decode load steadies the
+// concurrent decodes' ITL without giving up prefill throughput.
+func TestAdaptivePrefillChunk_LowerITLVarianceAtComparablePrefillThroughput(t *testing.T) {
+	// GIVEN decodes interleaved with long prompts chunked at the full budget
+	fixedStdDev, fixedRate := runDecodesWithLongPrompts(t, 4096, 0)
+
+	// WHEN chunks adapt to the decode load instead
+	adaptiveStdDev, adaptiveRate := runDecodesWithLongPrompts(t, 4096, 512)
+
+	// THEN the decodes' ITL varies less
+	if adaptiveStdDev >= fixedStdDev {
+		t.Errorf("ITL stddev: adaptive %.0f, fixed %.0f; want adaptive lower", adaptiveStdDev, fixedStdDev)
+	}
+	// AND prefill throughput stays comparable
+	if adaptiveRate < 0.8*fixedRate {
+		t.Errorf("prefill throughput: adaptive %.4f tokens/tick, fixed %.4f; want at least 80%% of fixed", adaptiveRate, fixedRate)
+	}
+	t.Logf("ITL stddev fixed=%.0f adaptive=%.0f; prefill tokens/tick fixed=%.4f adaptive=%.4f", fixedStdDev, adaptiveStdDev, fixedRate, adaptiveRate)
+}
+
+// TestAdaptivePrefillThreshold_ScalesWithDecodeShare verifies the chunk size
+// formula: the whole budget with no decodes, shrinking as the decoding share
+// of the running batch grows, and never below the floor.
+func TestAdaptivePrefillThreshold_ScalesWithDecodeShare(t *testing.T) {
+	decoding := func() *Request {
+		return &Request{InputTokens: make([]TokenID, 4), OutputTokens: make([]TokenID, 4), ProgressIndex: 5}
+	}
+	prefilling := func() *Request {
+		return &Request{InputTokens: make([]TokenID, 4), OutputTokens: make([]TokenID, 4)}
+	}
+	tests := []struct {
+		name    string
+		running []*Request
+		want    int64
+	}{
+		{"empty", nil, 4096},
+		{"prefill only", []*Request{prefilling(), prefilling()}, 4096},
+		{"one decode", []*Request{decoding()}, 2048},
+		{"three decodes one prefill", []*Request{decoding(), decoding(), decoding(), prefilling()}, 1638},
+		{"many decodes hits floor", []*Request{decoding(), decoding(), decoding(), decoding(), decoding(), decoding(), decoding()}, 1000},
+	}
+	for _, tc := range tests {
+		ctx := BatchContext{
+			RunningBatch:            &Batch{Requests: tc.running},
+			MaxScheduledTokens:      4096,
+			AdaptivePrefillChunkMin: 1000,
+		}
+		if got := adaptivePrefillThreshold(ctx); got != tc.want {
+			t.Errorf("%s: adaptivePrefillThreshold = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
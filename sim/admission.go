@@ -3,6 +3,7 @@ package sim
 import (
 	"fmt"
 	"math"
+	"sort"
 )
 
 // AdmissionPolicy decides whether a request is admitted for processing.
@@ -60,6 +61,131 @@ func (tb *TokenBucket) Admit(req *Request, state *RouterState) (bool, string) {
 	return false, "insufficient tokens"
 }
 
+// SLOTokenBucketConfig sizes one SLO class's bucket for the slo-token-bucket
+// admission policy.
+type SLOTokenBucketConfig struct {
+	Capacity   float64 `yaml:"capacity"`
+	RefillRate float64 `yaml:"refill_rate"` // tokens per second
+}
+
+// SLOTokenBucketAdmission rate-limits each SLO class with its own TokenBucket,
+// so a flood from one class drains only that class's bucket and cannot starve
+// the others. Classes without a configured bucket each get a private bucket
+// sized by the default capacity and refill rate. An empty SLOClass is treated
+// as "standard" (matches SLOPriorityMap default).
+type SLOTokenBucketAdmission struct {
+	buckets           map[string]*TokenBucket
+	defaultCapacity   float64
+	defaultRefillRate float64
+}
+
+// NewSLOTokenBucketAdmission creates an SLOTokenBucketAdmission.
+// Panics if any per-class or default capacity/refill rate is <= 0, NaN, or Inf (R3).
+func NewSLOTokenBucketAdmission(classes map[string]SLOTokenBucketConfig, defaultCapacity, defaultRefillRate float64) *SLOTokenBucketAdmission {
+	// Validate the defaults eagerly even if every class is configured.
+	NewTokenBucket(defaultCapacity, defaultRefillRate)
+	buckets := make(map[string]*TokenBucket, len(classes))
+	for class, cfg := range classes {
+		buckets[class] = NewTokenBucket(cfg.Capacity, cfg.RefillRate)
+	}
+	return &SLOTokenBucketAdmission{
+		buckets:           buckets,
+		defaultCapacity:   defaultCapacity,
+		defaultRefillRate: defaultRefillRate,
+	}
+}
+
+// Admit charges the request's input tokens to its SLO class's bucket.
+func (s *SLOTokenBucketAdmission) Admit(req *Request, state *RouterState) (bool, string) {
+	class := req.SLOClass
+	if class == "" {
+		class = "standard"
+	}
+	tb, ok := s.buckets[class]
+	if !ok {
+		tb = NewTokenBucket(s.defaultCapacity, s.defaultRefillRate)
+		s.buckets[class] = tb
+	}
+	if admitted, reason := tb.Admit(req, state); !admitted {
+		return false, fmt.Sprintf("%s for slo class %q", reason, class)
+	}
+	return true, ""
+}
+
+// TenantInFlightCounter reports per-tenant in-flight request counts and the
+// cluster's in-flight capacity. Implemented by cluster.TenantTracker.
+// Defined here (in sim/) to avoid an import cycle with sim/cluster/.
+type TenantInFlightCounter interface {
+	InFlight(tenantID string) int
+	Capacity() int
+}
+
+// TenantBorrowAdmission is a work-conserving per-tenant rate limiter. Each
+// tenant is guaranteed shares[tenant] × Capacity() in-flight requests and is
+// always admitted below its guarantee. Above it, a tenant borrows: it is
+// admitted only while cluster-wide in-flight requests (summed over
+// RouterState snapshots), plus the unused guarantees of the other active
+// tenants (in-flight > 0), stay below capacity. An idle tenant's share is thus
+// lent out, and reclaimed once it becomes active: borrowers are throttled
+// until their in-flight count drains back toward their guarantee. Tenants
+// without a share (including the empty TenantID) can only borrow.
+type TenantBorrowAdmission struct {
+	shares  map[string]float64
+	tenants []string // sorted keys of shares, for deterministic iteration
+	counter TenantInFlightCounter
+}
+
+// NewTenantBorrowAdmission creates a TenantBorrowAdmission.
+// Panics if counter is nil, any share is outside [0, 1] or NaN, or shares sum above 1 (R3).
+func NewTenantBorrowAdmission(shares map[string]float64, counter TenantInFlightCounter) *TenantBorrowAdmission {
+	if counter == nil {
+		panic("NewTenantBorrowAdmission: counter must not be nil")
+	}
+	var total float64
+	sharesCopy := make(map[string]float64, len(shares))
+	tenants := make([]string, 0, len(shares))
+	for tenantID, v := range shares {
+		if math.IsNaN(v) || v < 0 || v > 1 {
+			panic(fmt.Sprintf("NewTenantBorrowAdmission: share for tenant %q is %v; must be in [0, 1]", tenantID, v))
+		}
+		total += v
+		sharesCopy[tenantID] = v
+		tenants = append(tenants, tenantID)
+	}
+	if total > 1+1e-9 {
+		panic(fmt.Sprintf("NewTenantBorrowAdmission: shares sum to %v; must be <= 1", total))
+	}
+	sort.Strings(tenants)
+	return &TenantBorrowAdmission{shares: sharesCopy, tenants: tenants, counter: counter}
+}
+
+// Admit admits requests within their tenant's guarantee, and borrowing
+// requests only while the capacity not reserved for other active tenants'
+// guarantees has room.
+func (a *TenantBorrowAdmission) Admit(req *Request, state *RouterState) (bool, string) {
+	capacity := float64(a.counter.Capacity())
+	if float64(a.counter.InFlight(req.TenantID)) < a.shares[req.TenantID]*capacity {
+		return true, ""
+	}
+	var inFlight int
+	for _, snap := range state.Snapshots {
+		inFlight += snap.InFlightRequests
+	}
+	var reserved float64
+	for _, tenantID := range a.tenants {
+		if tenantID == req.TenantID {
+			continue
+		}
+		if n := a.counter.InFlight(tenantID); n > 0 {
+			reserved += max(0, a.shares[tenantID]*capacity-float64(n))
+		}
+	}
+	if float64(inFlight)+reserved < capacity {
+		return true, ""
+	}
+	return false, "tenant-borrow-throttled"
+}
+
 // RejectAll rejects all requests unconditionally (pathological template for testing).
 type RejectAll struct{}
 
@@ -207,7 +333,9 @@ func (g *GAIELegacyAdmission) saturation(snapshots []RoutingSnapshot) float64 {
 // NewAdmissionPolicy creates an admission policy by name.
 // Valid names are defined in ValidAdmissionPolicies (bundle.go).
 // An empty string defaults to AlwaysAdmit (for CLI flag default compatibility).
-// For token-bucket, capacity and refillRate configure the bucket.
+// For token-bucket, capacity and refillRate configure the bucket. For
+// slo-token-bucket, they size every class's bucket (per-class sizes require
+// NewSLOTokenBucketAdmission).
 // Panics on unrecognized names.
 func NewAdmissionPolicy(name string, capacity, refillRate float64) AdmissionPolicy {
 	if !IsValidAdmissionPolicy(name) {
@@ -218,12 +346,16 @@ func NewAdmissionPolicy(name string, capacity, refillRate float64) AdmissionPoli
 		return &AlwaysAdmit{}
 	case "token-bucket":
 		return NewTokenBucket(capacity, refillRate)
+	case "slo-token-bucket":
+		return NewSLOTokenBucketAdmission(nil, capacity, refillRate)
 	case "reject-all":
 		return &RejectAll{}
 	case "tier-shed":
 		panic("tier-shed requires NewTierShedAdmission; cannot use generic factory")
 	case "gaie-legacy":
 		panic("gaie-legacy requires NewGAIELegacyAdmission; cannot use generic factory")
+	case "tenant-borrow":
+		panic("tenant-borrow requires NewTenantBorrowAdmission; cannot use generic factory")
 	default:
 		panic(fmt.Sprintf("unhandled admission policy %q", name))
 	}
@@ -270,3 +402,61 @@ func (t *TenantBudgetAdmission) Admit(req *Request, state *RouterState) (bool, s
 	}
 	return t.inner.Admit(req, state)
 }
+
+// InFlightTokenLedger is the interface needed by InFlightTokenCapAdmission to
+// account cluster-wide in-flight tokens. Implemented by cluster.InFlightTokenTracker.
+type InFlightTokenLedger interface {
+	// InFlightTokens returns InFlightTokenReservation summed over admitted
+	// requests that have not reached a terminal state.
+	InFlightTokens() int64
+	// Reserve counts req as in flight from its admission onward.
+	Reserve(req *Request)
+}
+
+// InFlightTokenReservation is the share of the in-flight token cap req holds
+// from admission until it finishes: its input tokens plus its whole declared
+// output budget (MaxOutputLen; never the oracle output length, INV-9). Decode
+// progress is not credited back, because a recompute preemption makes the
+// request generate its output again.
+func InFlightTokenReservation(req *Request) int64 {
+	return req.InputLen() + int64(max(req.MaxOutputLen, 0))
+}
+
+// InFlightTokenCapAdmission wraps an inner AdmissionPolicy with a cluster-wide
+// cap on in-flight tokens, modeling a shared resource such as a KV-transfer
+// fabric. A request is rejected when its reservation (InFlightTokenReservation)
+// would push the ledger's total above the cap; with the admission retry model enabled the
+// rejected client comes back later, so the cap delays rather than drops.
+type InFlightTokenCapAdmission struct {
+	inner  AdmissionPolicy
+	cap    int64
+	ledger InFlightTokenLedger
+}
+
+// NewInFlightTokenCapAdmission creates an InFlightTokenCapAdmission decorator.
+// Panics if inner or ledger is nil or maxTokens is not positive.
+func NewInFlightTokenCapAdmission(inner AdmissionPolicy, maxTokens int64, ledger InFlightTokenLedger) *InFlightTokenCapAdmission {
+	if inner == nil {
+		panic("InFlightTokenCapAdmission: inner policy must not be nil")
+	}
+	if ledger == nil {
+		panic("InFlightTokenCapAdmission: ledger must not be nil")
+	}
+	if maxTokens <= 0 {
+		panic(fmt.Sprintf("InFlightTokenCapAdmission: maxTokens must be > 0, got %d", maxTokens))
+	}
+	return &InFlightTokenCapAdmission{inner: inner, cap: maxTokens, ledger: ledger}
+}
+
+func (a *InFlightTokenCapAdmission) Admit(req *Request, state *RouterState) (bool, string) {
+	// Cap check BEFORE the inner policy so a capped request never consumes
+	// inner state (e.g. token-bucket tokens).
+	if a.ledger.InFlightTokens()+InFlightTokenReservation(req) > a.cap {
+		return false, "inflight-token-cap"
+	}
+	admitted, reason := a.inner.Admit(req, state)
+	if admitted {
+		a.ledger.Reserve(req)
+	}
+	return admitted, reason
+}
@@ -1,6 +1,7 @@
 package sim
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -143,6 +144,77 @@ func TestTokenBucket_AdmitAndReject(t *testing.T) {
 	})
 }
 
+// TestSLOTokenBucketAdmission_BatchFloodDoesNotStarveCritical verifies that each
+// SLO class draws from its own bucket: a batch flood far exceeding the batch
+// bucket is rate-limited while critical traffic within its bucket is admitted.
+func TestSLOTokenBucketAdmission_BatchFloodDoesNotStarveCritical(t *testing.T) {
+	// GIVEN critical and batch buckets of 100 tokens each with negligible refill
+	p := NewSLOTokenBucketAdmission(map[string]SLOTokenBucketConfig{
+		"critical": {Capacity: 100, RefillRate: 0.001},
+		"batch":    {Capacity: 100, RefillRate: 0.001},
+	}, 1000, 1000)
+
+	// WHEN 50 batch requests of 10 tokens (500 tokens, 5× the batch bucket)
+	// interleave with 5 critical requests of 10 tokens (50 tokens, within budget)
+	var batchAdmitted, batchRejected, criticalAdmitted int
+	for i := 0; i < 50; i++ {
+		state := &RouterState{Clock: int64(i)}
+		batch := &Request{ID: fmt.Sprintf("b%d", i), SLOClass: "batch", InputTokens: make([]TokenID, 10)}
+		if admitted, reason := p.Admit(batch, state); admitted {
+			batchAdmitted++
+		} else {
+			batchRejected++
+			if reason != `insufficient tokens for slo class "batch"` {
+				t.Errorf("unexpected rejection reason %q", reason)
+			}
+		}
+		if i%10 == 0 {
+			critical := &Request{ID: fmt.Sprintf("c%d", i), SLOClass: "critical", InputTokens: make([]TokenID, 10)}
+			if admitted, _ := p.Admit(critical, state); admitted {
+				criticalAdmitted++
+			}
+		}
+	}
+
+	// THEN every critical request is admitted and batch is capped at its bucket
+	if criticalAdmitted != 5 {
+		t.Errorf("critical admitted = %d, want 5 (batch flood must not drain the critical bucket)", criticalAdmitted)
+	}
+	if batchAdmitted != 10 || batchRejected != 40 {
+		t.Errorf("batch admitted/rejected = %d/%d, want 10/40", batchAdmitted, batchRejected)
+	}
+}
+
+// TestSLOTokenBucketAdmission_UnlistedClassesGetPrivateDefaultBuckets verifies
+// unconfigured classes (including empty → standard) do not share a bucket.
+func TestSLOTokenBucketAdmission_UnlistedClassesGetPrivateDefaultBuckets(t *testing.T) {
+	p := NewSLOTokenBucketAdmission(nil, 10, 0.001)
+	state := &RouterState{Clock: 0}
+	req := func(class string) *Request {
+		return &Request{ID: "r-" + class, SLOClass: class, InputTokens: make([]TokenID, 10)}
+	}
+
+	if admitted, _ := p.Admit(req(""), state); !admitted {
+		t.Fatal("first standard request should be admitted")
+	}
+	if admitted, _ := p.Admit(req("standard"), state); admitted {
+		t.Error("empty SLOClass must share the standard bucket")
+	}
+	if admitted, _ := p.Admit(req("sheddable"), state); !admitted {
+		t.Error("sheddable must get its own default-sized bucket")
+	}
+}
+
+// TestNewSLOTokenBucketAdmission_InvalidClassConfig_Panics verifies R3 validation.
+func TestNewSLOTokenBucketAdmission_InvalidClassConfig_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for zero per-class capacity")
+		}
+	}()
+	NewSLOTokenBucketAdmission(map[string]SLOTokenBucketConfig{"batch": {Capacity: 0, RefillRate: 1}}, 10, 10)
+}
+
 // TestNewAdmissionPolicy_ValidNames verifies the factory produces correct behavioral policies.
 func TestNewAdmissionPolicy_ValidNames(t *testing.T) {
 	req := &Request{ID: "r0", InputTokens: make([]TokenID, 10)}
@@ -365,3 +437,100 @@ func TestTenantBudgetAdmission_PassesThroughInnerAdmissionReason(t *testing.T) {
 		t.Errorf("expected reason %q from inner policy, got %q", "inner-policy-reason", reason)
 	}
 }
+
+// fakeTenantCounter is a TenantInFlightCounter whose counts the test drives.
+type fakeTenantCounter struct {
+	inFlight map[string]int
+	capacity int
+}
+
+func (f *fakeTenantCounter) InFlight(tenantID string) int { return f.inFlight[tenantID] }
+func (f *fakeTenantCounter) Capacity() int                { return f.capacity }
+
+// TestTenantBorrowAdmission_IdleShareLentThenReclaimed verifies work-conserving
+// borrowing: an active tenant bursts above its guarantee while the other tenant
+// is idle, and is throttled back to its guarantee once the other activates.
+func TestTenantBorrowAdmission_IdleShareLentThenReclaimed(t *testing.T) {
+	// GIVEN capacity 10 split evenly between tenants a and b
+	counter := &fakeTenantCounter{inFlight: map[string]int{}, capacity: 10}
+	policy := NewTenantBorrowAdmission(map[string]float64{"a": 0.5, "b": 0.5}, counter)
+	submit := func(tenant string) bool {
+		total := counter.inFlight["a"] + counter.inFlight["b"]
+		state := &RouterState{Snapshots: []RoutingSnapshot{{ID: "i0", InFlightRequests: total}}}
+		admitted, _ := policy.Admit(&Request{TenantID: tenant}, state)
+		if admitted {
+			counter.inFlight[tenant]++
+		}
+		return admitted
+	}
+
+	// WHEN tenant a bursts 12 requests while b is idle
+	for i := 0; i < 12; i++ {
+		submit("a")
+	}
+	// THEN a borrows b's idle share up to full capacity, but not beyond
+	if got := counter.inFlight["a"]; got != 10 {
+		t.Fatalf("a in-flight after burst = %d, want 10 (guarantee 5 + borrowed 5)", got)
+	}
+	if admitted, reason := policy.Admit(&Request{TenantID: "a"}, &RouterState{Snapshots: []RoutingSnapshot{{InFlightRequests: 10}}}); admitted || reason != "tenant-borrow-throttled" {
+		t.Errorf("a at capacity: admitted=%v reason=%q, want throttled", admitted, reason)
+	}
+
+	// WHEN b becomes active and, each round, one of a's requests completes and
+	// both tenants submit one more
+	for round := 0; round < 10; round++ {
+		counter.inFlight["a"]--
+		if !submit("b") && counter.inFlight["b"] < 5 {
+			t.Fatalf("round %d: b rejected below its guarantee (b=%d)", round, counter.inFlight["b"])
+		}
+		submit("a")
+		// THEN a never regains borrowed slots while b is below its guarantee
+		if counter.inFlight["b"] < 5 && counter.inFlight["a"] > 10-round-1 {
+			t.Fatalf("round %d: a in-flight = %d, want throttled to <= %d", round, counter.inFlight["a"], 10-round-1)
+		}
+	}
+	// AND both tenants settle at their guarantees
+	if counter.inFlight["a"] != 5 || counter.inFlight["b"] != 5 {
+		t.Errorf("settled in-flight a=%d b=%d, want 5 and 5", counter.inFlight["a"], counter.inFlight["b"])
+	}
+}
+
+type fakeTokenLedger struct {
+	total    int64
+	reserved []string
+}
+
+func (f *fakeTokenLedger) InFlightTokens() int64 { return f.total }
+func (f *fakeTokenLedger) Reserve(req *Request) {
+	f.total += InFlightTokenReservation(req)
+	f.reserved = append(f.reserved, req.ID)
+}
+
+// TestInFlightTokenCapAdmission_RejectsAboveCap verifies the cap is checked
+// before the inner policy and that only admitted requests are reserved.
+func TestInFlightTokenCapAdmission_RejectsAboveCap(t *testing.T) {
+	// GIVEN a 500-token cap over an always-admit inner policy
+	ledger := &fakeTokenLedger{}
+	policy := NewInFlightTokenCapAdmission(&AlwaysAdmit{}, 500, ledger)
+	req := func(id string) *Request {
+		return &Request{ID: id, InputTokens: make([]TokenID, 100), MaxOutputLen: 100}
+	}
+
+	// WHEN 200-token requests arrive with nothing completing
+	var admitted []bool
+	for i := 0; i < 3; i++ {
+		ok, _ := policy.Admit(req(fmt.Sprintf("r%d", i)), &RouterState{})
+		admitted = append(admitted, ok)
+	}
+
+	// THEN two fit (400 <= 500) and the third, which would reach 600, is rejected
+	if !admitted[0] || !admitted[1] || admitted[2] {
+		t.Errorf("admitted = %v, want [true true false]", admitted)
+	}
+	if _, reason := policy.Admit(req("r3"), &RouterState{}); reason != "inflight-token-cap" {
+		t.Errorf("reason = %q, want inflight-token-cap", reason)
+	}
+	if len(ledger.reserved) != 2 || ledger.total != 400 {
+		t.Errorf("reserved %v totaling %d, want 2 requests totaling 400", ledger.reserved, ledger.total)
+	}
+}
@@ -0,0 +1,140 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// runTwoAffinityGroups queues 16 requests each of affinity keys "a" and "b",
+// interleaved, at t=0 on 8 running slots with a 2ms switch cost between groups.
+func runTwoAffinityGroups(t *testing.T, grouping bool) *Simulator {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(8, 2048, 0)
+	cfg.BatchAffinityGrouping = grouping
+	cfg.BatchAffinitySwitchCostUs = 2000
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	requests := uniformRequests(32, 64, 20, 0)
+	for i, req := range requests {
+		req.BatchAffinityKey = []string{"a", "b"}[i%2]
+	}
+	runToCompletion(t, s, requests)
+	return s
+}
+
+// TestAffinityBatchFormation_CoBatchesKeysAndAvoidsSwitches verifies that
+// affinity grouping runs each step with a single key, so no step pays the
+// switch cost that interleaved batching pays on every step, and the workload
+// finishes sooner.
+func TestAffinityBatchFormation_CoBatchesKeysAndAvoidsSwitches(t *testing.T) {
+	// GIVEN default FCFS batching of interleaved keys
+	interleaved := runTwoAffinityGroups(t, false)
+	if interleaved.Metrics.BatchAffinitySwitchSteps == 0 {
+		t.Fatal("interleaved batching paid no switch cost; test would not exercise grouping")
+	}
+
+	// WHEN the same workload runs with affinity grouping
+	grouped := runTwoAffinityGroups(t, true)
+
+	// THEN every step batches one key only
+	if got := grouped.Metrics.BatchAffinitySwitchSteps; got != 0 {
+		t.Errorf("grouped: %d steps mixed affinity keys, want 0", got)
+	}
+	// AND the avoided switch costs shorten the run
+	if grouped.Metrics.SimEndedTime >= interleaved.Metrics.SimEndedTime {
+		t.Errorf("grouped run ended at %d, want before interleaved %d", grouped.Metrics.SimEndedTime, interleaved.Metrics.SimEndedTime)
+	}
+}
+
+// TestAffinityBatchFormation_AdmitsOnlyActiveKey verifies that with an empty
+// batch the queue head's key is admitted ahead of earlier-queued other keys.
+func TestAffinityBatchFormation_AdmitsOnlyActiveKey(t *testing.T) {
+	cfg := newTestSimConfig()
+	kvStore := MustNewKVStoreFromConfig(cfg.KVCacheConfig)
+	wq := &WaitQueue{}
+	for i, key := range []string{"a", "b", "a", "b", "a"} {
+		wq.Enqueue(&Request{
+			ID:               fmt.Sprintf("req_%d", i),
+			InputTokens:      make([]TokenID, 16),
+			OutputTokens:     make([]TokenID, 4),
+			BatchAffinityKey: key,
+			State:            StateQueued,
+		})
+	}
+	result := NewAffinityBatchFormation("").FormBatch(BatchContext{
+		RunningBatch:       &Batch{},
+		WaitQ:              wq,
+		KVCache:            kvStore,
+		MaxScheduledTokens: 2048,
+		MaxRunningReqs:     8,
+		ComputedTokens:     map[string]int64{},
+	})
+
+	if len(result.RunningBatch.Requests) != 3 {
+		t.Fatalf("admitted %d requests, want the 3 with key \"a\"", len(result.RunningBatch.Requests))
+	}
+	for _, r := range result.RunningBatch.Requests {
+		if r.BatchAffinityKey != "a" {
+			t.Errorf("%s with key %q admitted alongside key \"a\"", r.ID, r.BatchAffinityKey)
+		}
+	}
+	if wq.Len() != 2 {
+		t.Errorf("wait queue holds %d requests, want the 2 with key \"b\"", wq.Len())
+	}
+}
+
+// runManyAdapters cycles 48 requests over 8 LoRA adapters, one arrival every
+// 200µs, on an instance holding capacity resident adapters, and returns the
+// total adapter loads and the run's end time.
+func runManyAdapters(t *testing.T, capacity int, grouping bool) (loads int64, ended int64) {
+	t.Helper()
+	adapters := make([]AdapterSpec, 8)
+	for i := range adapters {
+		adapters[i] = AdapterSpec{ID: fmt.Sprintf("adapter_%d", i), Rank: 8}
+	}
+	cfg := gateTestConfig(capacity, adapters...)
+	cfg.BatchAffinityGrouping = grouping
+	s := mustNewSimulator(t, cfg)
+	for i := 0; i < 48; i++ {
+		req := newTestRequest(fmt.Sprintf("req_%d", i), int64(i)*200, 32, 16)
+		req.Adapter = adapters[i%len(adapters)].ID
+		s.InjectArrival(req)
+	}
+	s.Run()
+	if s.Metrics.CompletedRequests != 48 {
+		t.Fatalf("capacity=%d grouping=%v: CompletedRequests = %d, want 48", capacity, grouping, s.Metrics.CompletedRequests)
+	}
+	for _, n := range s.Metrics.AdapterLoadCounts {
+		loads += n
+	}
+	return loads, s.Metrics.SimEndedTime
+}
+
+// TestAdapterSwitching_SmallCacheInflatesLoadsAndGroupingRecoversThem verifies
+// that cycling many adapters through a small resident set pays repeated load
+// latency that a larger set avoids, and that grouping same-adapter requests
+// (Request.AffinityKey falls back to Adapter) recovers most of it.
+func TestAdapterSwitching_SmallCacheInflatesLoadsAndGroupingRecoversThem(t *testing.T) {
+	smallLoads, smallEnded := runManyAdapters(t, 2, false)
+	largeLoads, largeEnded := runManyAdapters(t, 8, false)
+	groupedLoads, groupedEnded := runManyAdapters(t, 2, true)
+
+	// GIVEN every adapter must load at least once
+	if largeLoads != 8 {
+		t.Errorf("capacity 8: %d adapter loads, want 8 (one cold load each)", largeLoads)
+	}
+	// THEN the small cache reloads evicted adapters and finishes later
+	if smallLoads <= largeLoads {
+		t.Errorf("capacity 2: %d adapter loads, want more than capacity 8's %d", smallLoads, largeLoads)
+	}
+	if smallEnded <= largeEnded {
+		t.Errorf("capacity 2 ended at %d, want after capacity 8 at %d", smallEnded, largeEnded)
+	}
+	// AND grouping on the small cache loads less and finishes sooner than FCFS
+	if groupedLoads >= smallLoads {
+		t.Errorf("grouped capacity 2: %d adapter loads, want fewer than FCFS %d", groupedLoads, smallLoads)
+	}
+	if groupedEnded >= smallEnded {
+		t.Errorf("grouped capacity 2 ended at %d, want before FCFS %d", groupedEnded, smallEnded)
+	}
+}
@@ -0,0 +1,36 @@
+package sim
+
+// recordBatchEfficiency counts a step that ran a non-empty batch: its
+// scheduled tokens and its duration as GPU busy time. Steps that scheduled
+// nothing leave the GPU idle and are not counted.
+func (sim *Simulator) recordBatchEfficiency(scheduled []*Request, stepTicks int64) {
+	if len(scheduled) == 0 {
+		return
+	}
+	sim.Metrics.BatchSteps++
+	for _, req := range scheduled {
+		sim.Metrics.BatchScheduledTokens += int64(req.NumNewTokens)
+	}
+	sim.Metrics.GPUBusyTicks += stepTicks
+}
+
+// MeanTokensPerStep returns the mean number of tokens (prefill and decode)
+// scheduled per non-empty step, or 0 when no step ran. Low values at high
+// load indicate under-batching.
+func (m *Metrics) MeanTokensPerStep() float64 {
+	if m.BatchSteps == 0 {
+		return 0
+	}
+	return float64(m.BatchScheduledTokens) / float64(m.BatchSteps)
+}
+
+// GPUIdleFraction returns the fraction of simulated time with an empty running
+// batch: GPUIdleTicks over GPUIdleTicks + GPUBusyTicks, or 0 before Finalize
+// (or cluster aggregation) has set the idle time of a run that did nothing.
+func (m *Metrics) GPUIdleFraction() float64 {
+	total := m.GPUIdleTicks + m.GPUBusyTicks
+	if total <= 0 {
+		return 0
+	}
+	return float64(m.GPUIdleTicks) / float64(total)
+}
@@ -0,0 +1,44 @@
+package sim
+
+import "testing"
+
+// runBatchEfficiency runs n requests (64-token prompts, 20 output tokens)
+// arriving gap ticks apart and returns the finalized metrics.
+func runBatchEfficiency(t *testing.T, n int, gap int64) *Metrics {
+	t.Helper()
+	cfg := newTestSimConfig()
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	runToCompletion(t, s, distinctPrompts(uniformRequests(n, 64, 20, gap)))
+	return s.Metrics
+}
+
+// TestBatchEfficiency_LowVersusHighLoad verifies that sparse arrivals leave
+// the GPU mostly idle running near-singleton batches, while a burst keeps it
+// busy with large batches.
+func TestBatchEfficiency_LowVersusHighLoad(t *testing.T) {
+	// Low load: one request every 100ms, each served alone in ~11ms.
+	low := runBatchEfficiency(t, 5, 100_000)
+	// High load: 40 requests at once, batched together from the first step.
+	high := runBatchEfficiency(t, 40, 0)
+	t.Logf("low: %.1f tokens/step, idle %.3f; high: %.1f tokens/step, idle %.3f",
+		low.MeanTokensPerStep(), low.GPUIdleFraction(), high.MeanTokensPerStep(), high.GPUIdleFraction())
+
+	if got := low.GPUIdleFraction(); got < 0.8 {
+		t.Errorf("low load GPUIdleFraction = %.3f, want >= 0.8", got)
+	}
+	if got := low.MeanTokensPerStep(); got > 10 {
+		t.Errorf("low load MeanTokensPerStep = %.1f, want <= 10 (one request per step)", got)
+	}
+	if got := high.GPUIdleFraction(); got > 0.05 {
+		t.Errorf("high load GPUIdleFraction = %.3f, want <= 0.05", got)
+	}
+	if got := high.MeanTokensPerStep(); got < 100 {
+		t.Errorf("high load MeanTokensPerStep = %.1f, want >= 100", got)
+	}
+	// Busy and idle time partition the run.
+	for _, m := range []*Metrics{low, high} {
+		if m.GPUBusyTicks+m.GPUIdleTicks != m.SimEndedTime {
+			t.Errorf("busy %d + idle %d != SimEndedTime %d", m.GPUBusyTicks, m.GPUIdleTicks, m.SimEndedTime)
+		}
+	}
+}
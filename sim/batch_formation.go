@@ -1,6 +1,9 @@
 package sim
 
 import (
+	"math"
+	"slices"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/inference-sim/inference-sim/sim/internal/util"
@@ -21,16 +24,33 @@ type BatchFormation interface {
 // increment ComputedTokens[req.ID] to the total computed tokens (including
 // cached). Phase 2 of Step() reads this map to advance ProgressIndex.
 type BatchContext struct {
-	RunningBatch          *Batch
-	WaitQ                 *WaitQueue
-	KVCache               KVStore
-	MaxScheduledTokens    int64
-	MaxRunningReqs        int64
-	PrefillTokenThreshold int64
-	MaxModelLen           int64 // 0 = unlimited (proactive cap disabled)
-	Now                   int64
-	StepCount             int
-	ComputedTokens        map[string]int64
+	RunningBatch            *Batch
+	WaitQ                   *WaitQueue
+	KVCache                 KVStore
+	MaxScheduledTokens      int64
+	MaxRunningReqs          int64
+	PrefillTokenThreshold   int64
+	MaxModelLen             int64   // 0 = unlimited (proactive cap disabled)
+	KVPressureThreshold     float64 // KV utilization above which admissions are throttled; 0 = disabled
+	KVFairShare             bool    // cap per-request KV blocks at a fair share under contention
+	KVFairShareMaxBlocks    int64   // fixed fair-share cap in blocks; 0 = TotalCapacity / running requests
+	TokensPerDecodeStep     int64   // output tokens a decoding request advances per step; <= 1 = one
+	MinBatchFill            int64   // queued requests an idle instance waits for before launching a step; 0 = disabled
+	BatchFillMaxWait        int64   // longest the oldest queued request is held for MinBatchFill (ticks since arrival)
+	DecodeQuantumSteps      int     // steps between fair-decode rotations of the running batch; 0 = disabled
+	CriticalReserveSlots    int64   // running slots held for critical-class requests (set by CriticalReserveBatchFormation); 0 = none
+	CriticalReserveTokens   int64   // per-step token budget held for critical-class prefill; 0 = none
+	AdaptivePrefillChunkMin int64   // > 0 replaces PrefillTokenThreshold with a decode-load-adaptive chunk size of at least this many tokens
+	PrefillFirst            bool    // charge prefill chunks and new admissions before running decodes (StepOrderingPrefillFirst)
+	SwapPreemption          bool    // preemption victims swap their KV out and keep their progress (PreemptionModeSwap)
+	SwapSpaceBlocks         int64   // host blocks swapped-out requests may hold; a victim that does not fit is recomputed; 0 = unbounded
+	AffinityGrouping        bool    // admit only requests whose BatchAffinityKey is AffinityKey (set by AffinityBatchFormation)
+	AffinityKey             string
+	PriorityInheritor       *Request // running request ranked at InheritedPriority for victim selection (priority-inheritance); nil = none
+	InheritedPriority       float64
+	Now                     int64
+	StepCount               int
+	ComputedTokens          map[string]int64
 
 	// AdapterResident is the cold-load pre-admission gate predicate (#1466): it
 	// reports whether a request's LoRA adapter is currently resident on the
@@ -39,16 +59,32 @@ type BatchContext struct {
 	// kernel-scheduled adapter load completes. nil ⇒ no LoRA gating; admission is
 	// byte-identical to a pre-feature build (INV-6).
 	AdapterResident func(id string) bool
+
+	// RemotePrefixCached re-checks a shared prefix cache hit when its fetch is
+	// charged: it returns how many of the request's leading input blocks its
+	// RemotePrefixOwner still holds. nil ⇒ Request.RemotePrefixBlocks is fetched
+	// as annotated at routing.
+	RemotePrefixCached func(req *Request) int
 }
 
 // ScheduledRequest carries metadata about a newly scheduled request.
 type ScheduledRequest struct {
-	Request *Request
+	Request         *Request
+	SwappedInBlocks int64 // KV blocks copied back in for a swapped-out request, less prompt blocks still in the prefix cache (PreemptionModeSwap); 0 otherwise
 }
 
 // PreemptedRequest carries metadata about a preempted request.
 type PreemptedRequest struct {
-	Request *Request
+	Request      *Request
+	ProgressLost int64 // ProgressIndex discarded by the reset; recomputed on re-prefill
+	// SwappedBlocks is the KV blocks copied out to host memory when the
+	// victim was swapped out (PreemptionModeSwap) instead of reset; its
+	// ProgressLost is then 0.
+	SwappedBlocks int64
+	// HOLBlocking marks the eviction of the running request nearest completion
+	// among those less urgent than a waiting request (priorityHOLHolder): the
+	// waiter stays blocked on KV that request would have released.
+	HOLBlocking bool
 }
 
 // BatchResult describes the outcome of batch formation.
@@ -57,6 +93,20 @@ type BatchResult struct {
 	NewlyScheduled     []ScheduledRequest
 	Preempted          []PreemptedRequest
 	PreemptionHappened bool
+
+	// RemotePrefixFetchedBlocks counts KV blocks newly admitted requests pulled
+	// from a remote prefix cache (Request.RemotePrefixBlocks) instead of computing.
+	RemotePrefixFetchedBlocks int64
+
+	// HoldUntil, when > 0, means the instance was idle with fewer than
+	// MinBatchFill queued requests: nothing was admitted, and the kernel should
+	// re-form the batch at this tick (or on an earlier arrival).
+	HoldUntil int64
+
+	// ForwardPasses, when non-nil, splits the step's scheduled requests into
+	// separate forward passes (DecodeBucketBatchFormation), each charged by the
+	// latency model on its own and summed. Nil runs them as one pass.
+	ForwardPasses [][]*Request
 }
 
 // PreemptionPolicy controls how preemption selects a victim from the running batch.
@@ -71,6 +121,19 @@ const (
 	// Selects max(Priority) with max(ArrivalTime) tiebreak — direct parity with
 	// vLLM scheduler.py:1086: max(self.running, key=lambda r: (r.priority, r.arrival_time)).
 	PreemptionPriority PreemptionPolicy = "priority"
+
+	// PreemptionPriorityAdmission extends PreemptionPriority to the wait queue:
+	// when the queue head cannot get KV blocks, running requests strictly less
+	// urgent than it are evicted (least urgent first) to make room, instead of
+	// the head waiting for blocks to free up. BLIS extension; vLLM preempts
+	// only to grow running requests.
+	PreemptionPriorityAdmission PreemptionPolicy = "priority-admission"
+
+	// PreemptionPriorityInheritance is PreemptionPriority with priority
+	// inheritance: the running request blocking the most urgent waiting
+	// request (priorityHOLHolder) is ranked at the waiter's priority, so it is
+	// not evicted in favor of work less urgent than the waiter. BLIS extension.
+	PreemptionPriorityInheritance PreemptionPolicy = "priority-inheritance"
 )
 
 // VLLMBatchFormation implements the vLLM FCFS + chunked-prefill + preemption strategy.
@@ -87,8 +150,37 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 		RunningBatch: ctx.RunningBatch,
 	}
 
+	// Min-batch-fill wait: an idle instance below the fill threshold launches
+	// nothing until more requests arrive or the oldest has waited long enough.
+	if holdUntil := batchFillHoldUntil(ctx); holdUntil > ctx.Now {
+		result.HoldUntil = holdUntil
+		return result
+	}
+
 	tokenBudget := ctx.MaxScheduledTokens
 
+	// Fair-share KV: when no running request can grow within its share, lift
+	// the cap for this step so a batch of capped requests cannot deadlock.
+	if ctx.KVFairShare && !anyRequestFitsFairShare(result.RunningBatch.Requests, kvFairShareTokenCap(ctx, len(result.RunningBatch.Requests))) {
+		ctx.KVFairShare = false
+	}
+
+	// Fair decode: on a quantum boundary, reorder by least service and
+	// suspend the most-served decode requests so waiting requests get slots.
+	rotateDecodeQuantum(ctx, result.RunningBatch)
+
+	// Priority inheritance: the request blocking the most urgent waiter is
+	// ranked at its priority for this step's victim selection.
+	if v.preemptionPolicy == PreemptionPriorityInheritance {
+		ctx.PriorityInheritor, ctx.InheritedPriority = priorityHOLHolder(ctx.WaitQ.Items(), result.RunningBatch.Requests)
+	}
+
+	// Adaptive chunked prefill: size this step's prefill chunks from the
+	// decode load of the running batch.
+	if ctx.AdaptivePrefillChunkMin > 0 {
+		ctx.PrefillTokenThreshold = adaptivePrefillThreshold(ctx)
+	}
+
 	// Zero NumNewTokens for all running requests at the start of each scheduling pass.
 	// This prevents stale values from the prior step from causing phantom budget
 	// restoration when a request is preempted before being visited in this pass.
@@ -97,12 +189,14 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 	}
 
 	// Phase 1: Process continuing requests (chunked prefill + decode).
+	// Under prefill-first ordering decodes are deferred until after Phase 2.
 	// Index-based loop: re-evaluates len() each iteration so evicted requests
 	// are never visited. In priority mode, non-tail eviction shifts elements
 	// left; the reqAdjustment returned by preemptForTokens compensates by
 	// decrementing reqIndex, preventing element skipping.
 	// Analog of vLLM v1 req_index -= 1 (scheduler.py:853).
 	reqIndex := 0
+	var deferredDecodes []*Request
 	for reqIndex < len(result.RunningBatch.Requests) {
 		if tokenBudget <= 0 {
 			logrus.Warnf("[tick %07d] token budget exhausted, deferring remaining requests to next step", ctx.Now)
@@ -110,6 +204,10 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 		}
 		req := result.RunningBatch.Requests[reqIndex]
 
+		// Fair-share KV cap: a request at its share waits (keeping its blocks)
+		// until other requests finish and the share grows.
+		fairShareCap := kvFairShareTokenCap(ctx, len(result.RunningBatch.Requests))
+
 		numNewTokens := req.InputLen() - req.ProgressIndex
 		// Chunked prefill for running requests
 		if numNewTokens > 0 {
@@ -117,6 +215,20 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 				numNewTokens = ctx.PrefillTokenThreshold
 			}
 			numNewTokens = min(numNewTokens, tokenBudget)
+			// Critical headroom: a non-critical prefill chunk leaves the
+			// reserved token budget untouched.
+			if reserved := criticalReserveTokenBudget(ctx, req, tokenBudget); numNewTokens > reserved {
+				if reserved <= 0 {
+					reqIndex++
+					continue
+				}
+				numNewTokens = reserved
+			}
+			if req.ProgressIndex >= fairShareCap {
+				reqIndex++
+				continue
+			}
+			numNewTokens = min(numNewTokens, fairShareCap-req.ProgressIndex)
 			// Proactive MaxModelLen cap (BC-1): match vLLM scheduler.py:773-774.
 			// Note: the enqueue guard (len(InputTokens) < maxModelLen) guarantees
 			// maxAllowed >= 1 during prefill, so this clamp only reduces chunk size,
@@ -136,34 +248,24 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 			req.NumNewTokens = int(numNewTokens)
 			ctx.ComputedTokens[req.ID] += numNewTokens
 		}
-		// Decode phase: allocate 1 token
 		if req.ProgressIndex >= req.InputLen() && len(req.OutputTokens) > 0 {
-			decodeTokens := int64(1)
-			// Proactive MaxModelLen cap (BC-1): skip decode at boundary.
-			// Equivalent to max(0, maxModelLen-1-PI) < 1, specialized for single-token decode.
-			// Note: when decodeTokens=0, the request stays in RunningBatch with its
-			// previously-allocated KV blocks for one zero-work step until processCompletions
-			// releases them via ReleaseKVBlocks. Under tight KV pressure this transiently
-			// reduces available blocks by the request's allocation.
-			if ctx.MaxModelLen > 0 && req.ProgressIndex+decodeTokens > ctx.MaxModelLen-1 {
-				decodeTokens = 0
-			}
-			if decodeTokens > 0 {
-				canSchedule, adj := v.preemptForTokens(req, decodeTokens, &result, ctx, &tokenBudget, reqIndex)
+			if ctx.PrefillFirst {
+				deferredDecodes = append(deferredDecodes, req)
+			} else {
+				canSchedule, adj := v.scheduleDecode(req, fairShareCap, true, &result, ctx, &tokenBudget, reqIndex)
 				reqIndex -= adj
 				if !canSchedule {
 					break
 				}
-				tokenBudget--
-				req.NumNewTokens = 1
-				ctx.ComputedTokens[req.ID] += 1
 			}
 		}
 		reqIndex++
 	}
 
-	// Phase 2: Dequeue new requests from wait queue
-	for len(result.RunningBatch.Requests) < int(ctx.MaxRunningReqs) && ctx.WaitQ.Len() > 0 && tokenBudget > 0 && !result.PreemptionHappened {
+	// Phase 2: Dequeue new requests from wait queue.
+	// The running-request cap is re-evaluated per admission because each new
+	// prefill raises KV utilization (memory-pressure throttle).
+	for len(result.RunningBatch.Requests) < kvPressureRunningCap(ctx) && ctx.WaitQ.Len() > 0 && tokenBudget > 0 && !result.PreemptionHappened {
 		next := ctx.WaitQ.Peek()
 
 		// Cold-load pre-admission gate (LoRA, #1466): a new prefill request whose
@@ -171,25 +273,51 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 		// until its adapter load completes (FR-007, §7). Because Phase 2 inspects
 		// only the queue head and breaks on the first non-admittable request, this
 		// realizes the DT-faithful blocking model — a gated head stalls the warm
-		// requests behind it for the load duration. Decode sub-requests (PD) are
-		// already past the gate (their prefill ran with the adapter resident).
-		if ctx.AdapterResident != nil && !next.IsDecodeSubRequest && next.Adapter != "" && !ctx.AdapterResident(next.Adapter) {
+		// requests behind it for the load duration. Decode sub-requests (PD) and
+		// suspended or swapped-out requests are already past the gate (their
+		// prefill ran with the adapter resident).
+		if ctx.AdapterResident != nil && !next.IsDecodeSubRequest && !next.Suspended && !next.SwappedOut && next.Adapter != "" && !ctx.AdapterResident(next.Adapter) {
+			break
+		}
+		// Critical headroom: a non-critical head may not take the reserved
+		// slots. Critical requests are queued first, so none wait behind it.
+		if !criticalReserveAdmits(ctx, next, result.RunningBatch.Requests) {
+			break
+		}
+		// Affinity grouping: the active group is queued first, so the first
+		// request of another group ends admission for this step.
+		if !affinityAdmits(ctx, next) {
 			break
 		}
 
+		// A swapped-out request gets its KV blocks back and resumes where it
+		// left off: decoding, or prefilling its next chunk.
+		if next.SwappedOut {
+			if !swapIn(next, &result, ctx, &tokenBudget) {
+				break
+			}
+			continue
+		}
+
 		// Handle decode-only requests (PD disaggregation: KV pre-allocated by transfer).
 		// IsDecodeSubRequest is set exclusively by KVTransferStartedEvent when it
 		// reserves KV on the decode pod (issue #1343), so this path fires only for
 		// requests that genuinely arrived via PD KV transfer.
 		// ProgressIndex has already been set to len(InputTokens) by ReserveTransferredKV.
-		if next.IsDecodeSubRequest {
+		// A decode request suspended by the fair-decode rotation resumes the
+		// same way: its KV and progress were kept while it waited.
+		if next.IsDecodeSubRequest || next.Suspended {
 			decodeTokens := int64(1)
 			if ok := ctx.KVCache.AllocateKVBlocks(next, next.ProgressIndex, next.ProgressIndex+decodeTokens, nil); !ok {
 				break
 			}
 			ctx.WaitQ.DequeueBatch()
 			result.RunningBatch.Requests = append(result.RunningBatch.Requests, next)
-			next.ScheduledStepIdx = ctx.StepCount
+			if next.Suspended {
+				next.Suspended = false // keeps its first ScheduledStepIdx
+			} else {
+				next.ScheduledStepIdx = ctx.StepCount
+			}
 			result.NewlyScheduled = append(result.NewlyScheduled, ScheduledRequest{Request: next})
 			tokenBudget -= decodeTokens
 			next.State = StateRunning
@@ -198,27 +326,58 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 			continue
 		}
 
-		cachedBlocks := ctx.KVCache.GetCachedBlocks(next.FullInputTokens())
-		numNewTokens := next.InputLen() - util.Len64(cachedBlocks)*ctx.KVCache.BlockSize()
+		kvc := kvStoreFor(ctx.KVCache, next)
+		var cachedBlocks []int64
+		if !next.NoCache {
+			cachedBlocks = kvc.GetCachedBlocks(next.FullInputTokens())
+		}
+		startIndex := util.Len64(cachedBlocks) * kvc.BlockSize()
+		// Shared prefix cache: blocks held by another instance beyond the local
+		// hit are fetched rather than recomputed. At least one input token is
+		// always computed so the request produces its first output token.
+		computeStart := startIndex
+		if next.RemotePrefixBlocks > 0 {
+			remoteBlocks := int64(next.RemotePrefixBlocks)
+			if ctx.RemotePrefixCached != nil {
+				remoteBlocks = min(remoteBlocks, int64(ctx.RemotePrefixCached(next))) // the owner may have evicted since routing
+			}
+			maxFetchBlocks := (next.InputLen() - 1) / kvc.BlockSize()
+			computeStart = max(startIndex, min(remoteBlocks, maxFetchBlocks)*kvc.BlockSize())
+		}
+		numNewTokens := next.InputLen() - computeStart
 
 		if 0 < ctx.PrefillTokenThreshold && ctx.PrefillTokenThreshold < numNewTokens {
 			numNewTokens = ctx.PrefillTokenThreshold
 		}
 		numNewTokens = min(numNewTokens, tokenBudget)
-		startIndex := util.Len64(cachedBlocks) * ctx.KVCache.BlockSize()
+		if reserved := criticalReserveTokenBudget(ctx, next, tokenBudget); numNewTokens > reserved {
+			if reserved <= 0 {
+				break
+			}
+			numNewTokens = reserved
+		}
 		// Proactive MaxModelLen cap (BC-2): BLIS safety extension (vLLM only caps running requests).
 		// For valid enqueued requests (input < maxModelLen), this is a no-op.
 		if ctx.MaxModelLen > 0 {
-			maxAllowed := max(ctx.MaxModelLen-1-startIndex, 0)
+			maxAllowed := max(ctx.MaxModelLen-1-computeStart, 0)
 			numNewTokens = min(numNewTokens, maxAllowed)
 		}
-		endIndex := startIndex + numNewTokens
+		// Fair-share KV cap: a new prefill is chunked down to its share of the
+		// cache, counting itself among the running requests.
+		if fairShareCap := kvFairShareTokenCap(ctx, len(result.RunningBatch.Requests)+1); computeStart+numNewTokens > fairShareCap {
+			if computeStart >= fairShareCap {
+				break
+			}
+			numNewTokens = fairShareCap - computeStart
+		}
+		endIndex := computeStart + numNewTokens
 
-		if ok := ctx.KVCache.AllocateKVBlocks(next, startIndex, endIndex, cachedBlocks); !ok {
+		if ok := ctx.KVCache.AllocateKVBlocks(next, startIndex, endIndex, cachedBlocks); ok {
+			ctx.WaitQ.DequeueBatch()
+		} else if !v.preemptForAdmission(next, startIndex, endIndex, cachedBlocks, &result, ctx, &tokenBudget) {
 			break
 		}
 
-		ctx.WaitQ.DequeueBatch()
 		result.RunningBatch.Requests = append(result.RunningBatch.Requests, next)
 		next.ScheduledStepIdx = ctx.StepCount
 
@@ -229,12 +388,137 @@ func (v *VLLMBatchFormation) FormBatch(ctx BatchContext) BatchResult {
 		tokenBudget -= numNewTokens
 		next.State = StateRunning
 		next.NumNewTokens = int(numNewTokens)
-		ctx.ComputedTokens[next.ID] = numNewTokens + util.Len64(cachedBlocks)*ctx.KVCache.BlockSize()
+		ctx.ComputedTokens[next.ID] = endIndex
+		result.RemotePrefixFetchedBlocks += (computeStart - startIndex) / kvc.BlockSize()
+	}
+
+	// Phase 3 (prefill-first only): deferred decodes take the budget the
+	// prefills left, in running-batch order. They preempt only when nothing
+	// was admitted this step, so a newly scheduled request is never evicted
+	// in the step that admitted it; otherwise a decode that cannot get KV
+	// waits, and the remaining decodes with it.
+	for _, req := range deferredDecodes {
+		if tokenBudget <= 0 {
+			break
+		}
+		if req.State != StateRunning {
+			continue // preempted earlier in this step
+		}
+		fairShareCap := kvFairShareTokenCap(ctx, len(result.RunningBatch.Requests))
+		if canSchedule, _ := v.scheduleDecode(req, fairShareCap, len(result.NewlyScheduled) == 0, &result, ctx, &tokenBudget, 0); !canSchedule {
+			break
+		}
 	}
 
 	return result
 }
 
+// scheduleDecode gives a decoding request its tokens for this step: 1, or up
+// to TokensPerDecodeStep (never past the request's last decode step or the
+// token budget), allocating KV through preemptForTokens when preempt is set
+// and without evicting anyone otherwise. Returns (canSchedule, reqAdjustment)
+// as preemptForTokens does; a request capped to zero tokens is skipped.
+func (v *VLLMBatchFormation) scheduleDecode(req *Request, fairShareCap int64, preempt bool, result *BatchResult, ctx BatchContext, tokenBudget *int64, reqIndex int) (bool, int) {
+	decodeTokens := int64(1)
+	if ctx.TokensPerDecodeStep > 1 {
+		remaining := req.InputLen() + util.Len64(req.OutputTokens) - 1 - req.ProgressIndex
+		decodeTokens = max(min(ctx.TokensPerDecodeStep, remaining, *tokenBudget), 1)
+	}
+	// Proactive MaxModelLen cap (BC-1): clamp decode at the boundary, to 0 once
+	// PI reaches maxModelLen-1.
+	// Note: when decodeTokens=0, the request stays in RunningBatch with its
+	// previously-allocated KV blocks for one zero-work step until processCompletions
+	// releases them via ReleaseKVBlocks. Under tight KV pressure this transiently
+	// reduces available blocks by the request's allocation.
+	if ctx.MaxModelLen > 0 {
+		decodeTokens = min(decodeTokens, max(ctx.MaxModelLen-1-req.ProgressIndex, 0))
+	}
+	decodeTokens = min(decodeTokens, max(fairShareCap-req.ProgressIndex, 0))
+	if decodeTokens <= 0 {
+		return true, 0
+	}
+	adj := 0
+	if preempt {
+		var canSchedule bool
+		canSchedule, adj = v.preemptForTokens(req, decodeTokens, result, ctx, tokenBudget, reqIndex)
+		if !canSchedule {
+			return false, adj
+		}
+	} else if !ctx.KVCache.AllocateKVBlocks(req, req.ProgressIndex, req.ProgressIndex+decodeTokens, nil) {
+		return false, 0
+	}
+	*tokenBudget -= decodeTokens
+	req.NumNewTokens = int(decodeTokens)
+	ctx.ComputedTokens[req.ID] += decodeTokens
+	return true, adj
+}
+
+// kvPressureRunningCap returns the effective MaxRunningReqs for new admissions.
+// At or below ctx.KVPressureThreshold utilization (or when the throttle is
+// disabled) it is MaxRunningReqs; above it the cap shrinks in proportion to the
+// remaining headroom, (1-util)/(1-threshold), never below 1 so an empty batch
+// can always make progress.
+func kvPressureRunningCap(ctx BatchContext) int {
+	maxRunning := int(ctx.MaxRunningReqs)
+	if ctx.KVPressureThreshold <= 0 {
+		return maxRunning
+	}
+	total := ctx.KVCache.TotalCapacity()
+	if total <= 0 {
+		return maxRunning
+	}
+	util := float64(ctx.KVCache.UsedBlocks()) / float64(total)
+	if util <= ctx.KVPressureThreshold {
+		return maxRunning
+	}
+	scaled := int(float64(maxRunning) * (1 - util) / (1 - ctx.KVPressureThreshold))
+	return max(scaled, 1)
+}
+
+// kvFairShareTokenCap returns the most tokens of KV one request may hold when
+// active requests share the cache: KVFairShareMaxBlocks when set, otherwise
+// TotalCapacity/active, rounded to whole blocks and never below one block.
+// Without contention (fair share disabled or active <= 1) there is no cap.
+func kvFairShareTokenCap(ctx BatchContext, active int) int64 {
+	if !ctx.KVFairShare || active <= 1 {
+		return math.MaxInt64
+	}
+	blocks := ctx.KVFairShareMaxBlocks
+	if blocks <= 0 {
+		blocks = ctx.KVCache.TotalCapacity() / int64(active)
+	}
+	return max(blocks, 1) * ctx.KVCache.BlockSize()
+}
+
+// anyRequestFitsFairShare reports whether at least one running request can
+// take its next prefill chunk or decode token without exceeding capTokens.
+func anyRequestFitsFairShare(requests []*Request, capTokens int64) bool {
+	for _, req := range requests {
+		if req.ProgressIndex < req.InputLen() {
+			if req.ProgressIndex < capTokens {
+				return true
+			}
+		} else if req.ProgressIndex+1 <= capTokens {
+			return true
+		}
+	}
+	return len(requests) == 0
+}
+
+// selectFairShareVictim returns the index of the running request holding the
+// most KV above the fair share, or -1 when no request exceeds it. Under
+// fair-share allocation such requests are preempted ahead of the policy's
+// usual victim.
+func selectFairShareVictim(requests []*Request, capTokens int64) int {
+	victimIdx := -1
+	for i, req := range requests {
+		if req.ProgressIndex > capTokens && (victimIdx < 0 || req.ProgressIndex > requests[victimIdx].ProgressIndex) {
+			victimIdx = i
+		}
+	}
+	return victimIdx
+}
+
 // preemptForTokens tries to allocate numNewTokens of KV blocks for req,
 // evicting victims if needed. Returns (canSchedule, reqAdjustment) where
 // reqAdjustment counts evictions at indices below reqIndex.
@@ -254,22 +538,20 @@ func (v *VLLMBatchFormation) preemptForTokens(req *Request, numNewTokens int64,
 
 			result.PreemptionHappened = true
 
-			var victimIdx int
-			switch v.preemptionPolicy {
-			case PreemptionPriority:
-				victimIdx = v.selectPriorityVictim(result.RunningBatch.Requests)
-			default:
-				victimIdx = len(result.RunningBatch.Requests) - 1
+			victimIdx := selectFairShareVictim(result.RunningBatch.Requests, kvFairShareTokenCap(ctx, len(result.RunningBatch.Requests)))
+			if victimIdx < 0 {
+				switch v.preemptionPolicy {
+				case PreemptionPriority, PreemptionPriorityAdmission:
+					victimIdx = v.selectPriorityVictim(result.RunningBatch.Requests)
+				case PreemptionPriorityInheritance:
+					victimIdx = selectInheritanceVictim(ctx, result.RunningBatch.Requests)
+				default:
+					victimIdx = len(result.RunningBatch.Requests) - 1
+				}
 			}
 
 			preemptedRequest := result.RunningBatch.Requests[victimIdx]
-			logrus.Warnf("[tick %07d] preemption: evicting %s to make room", ctx.Now, preemptedRequest.ID)
-
-			// Remove by index (supports non-tail eviction in priority mode).
-			result.RunningBatch.Requests = append(
-				result.RunningBatch.Requests[:victimIdx],
-				result.RunningBatch.Requests[victimIdx+1:]...,
-			)
+			preemptRunningRequest(victimIdx, result, ctx, tokenBudget)
 
 			// Track Phase 1 index adjustment: if the victim was before the
 			// caller's current position, elements shifted left under the cursor.
@@ -290,29 +572,6 @@ func (v *VLLMBatchFormation) preemptForTokens(req *Request, numNewTokens int64,
 				adjustment++
 			}
 
-			result.Preempted = append(result.Preempted, PreemptedRequest{
-				Request: preemptedRequest,
-			})
-
-			// Restore token budget if preempted request was already scheduled
-			// in this step (visited earlier in Phase 1, NumNewTokens > 0).
-			// Reachable in priority mode when victim was at index < reqIndex
-			// (already visited and allocated tokens this step).
-			// With FCFS (tail-only eviction), unreachable because evicted
-			// requests are always unvisited (beyond reqIndex).
-			if preemptedRequest.NumNewTokens > 0 {
-				*tokenBudget += int64(preemptedRequest.NumNewTokens)
-				preemptedRequest.NumNewTokens = 0
-			}
-
-			preemptedRequest.State = StateQueued
-			preemptedRequest.ProgressIndex = 0
-			preemptedRequest.ITL = nil
-			preemptedRequest.TTFTSet = false // lets the !TTFTSet guard in executeBatchStep fire on re-prefill, updating FirstTokenTime (#1122)
-			ctx.KVCache.ReleaseKVBlocks(preemptedRequest)
-			delete(ctx.ComputedTokens, preemptedRequest.ID)
-			ctx.WaitQ.PrependFront(preemptedRequest)
-
 			if preemptedRequest == req {
 				return false, adjustment
 			}
@@ -322,6 +581,168 @@ func (v *VLLMBatchFormation) preemptForTokens(req *Request, numNewTokens int64,
 	}
 }
 
+// batchFillHoldUntil returns the tick until which an idle instance holds its
+// wait queue for ctx.MinBatchFill requests: the oldest queued request's
+// arrival plus ctx.BatchFillMaxWait. Returns 0 when the wait is disabled, the
+// running batch is non-empty, or the queue is empty or already full enough.
+func batchFillHoldUntil(ctx BatchContext) int64 {
+	queued := int64(ctx.WaitQ.Len())
+	if ctx.MinBatchFill <= 0 || len(ctx.RunningBatch.Requests) > 0 || queued == 0 || queued >= ctx.MinBatchFill {
+		return 0
+	}
+	oldest := int64(math.MaxInt64)
+	for _, req := range ctx.WaitQ.Items() {
+		oldest = min(oldest, req.ArrivalTime)
+	}
+	return oldest + ctx.BatchFillMaxWait
+}
+
+// preemptRunningRequest evicts RunningBatch.Requests[victimIdx]: it is reset
+// to StateQueued with no progress, its KV blocks are released, and it is put
+// back at the front of the wait queue. With ctx.SwapPreemption a victim with
+// progress is swapped out instead (swapOutRequest) and keeps it, unless host
+// swap space is full (swapSpaceFits).
+func preemptRunningRequest(victimIdx int, result *BatchResult, ctx BatchContext, tokenBudget *int64) {
+	preemptedRequest := result.RunningBatch.Requests[victimIdx]
+	swap := ctx.SwapPreemption && canSwap(preemptedRequest) && swapSpaceFits(preemptedRequest, ctx)
+	if swap {
+		logrus.Warnf("[tick %07d] preemption: swapping out %s to make room (%d tokens of progress kept)", ctx.Now, preemptedRequest.ID, preemptedRequest.ProgressIndex)
+	} else {
+		logrus.Warnf("[tick %07d] preemption: evicting %s to make room (%d tokens of progress lost)", ctx.Now, preemptedRequest.ID, preemptedRequest.ProgressIndex)
+	}
+	holder, _ := priorityHOLHolder(ctx.WaitQ.Items(), result.RunningBatch.Requests)
+
+	// Remove by index (supports non-tail eviction in priority mode).
+	result.RunningBatch.Requests = append(
+		result.RunningBatch.Requests[:victimIdx],
+		result.RunningBatch.Requests[victimIdx+1:]...,
+	)
+
+	preempted := PreemptedRequest{
+		Request:      preemptedRequest,
+		ProgressLost: preemptedRequest.ProgressIndex,
+		HOLBlocking:  preemptedRequest == holder,
+	}
+	if swap {
+		preempted.ProgressLost = 0
+		preempted.SwappedBlocks = swappedBlocks(preemptedRequest, kvStoreFor(ctx.KVCache, preemptedRequest).BlockSize())
+	}
+	result.Preempted = append(result.Preempted, preempted)
+
+	// Restore token budget if preempted request was already scheduled
+	// in this step (visited earlier in Phase 1, NumNewTokens > 0).
+	// Reachable in priority mode when victim was at index < reqIndex
+	// (already visited and allocated tokens this step).
+	// With FCFS (tail-only eviction), unreachable because evicted
+	// requests are always unvisited (beyond reqIndex).
+	if preemptedRequest.NumNewTokens > 0 {
+		*tokenBudget += int64(preemptedRequest.NumNewTokens)
+		preemptedRequest.NumNewTokens = 0
+	}
+
+	if swap {
+		swapOutRequest(preemptedRequest, ctx.KVCache)
+	} else {
+		preemptedRequest.State = StateQueued
+		preemptedRequest.ProgressIndex = 0
+		preemptedRequest.ITL = nil
+		preemptedRequest.TTFTSet = false // lets the !TTFTSet guard in executeBatchStep fire on re-prefill, updating FirstTokenTime (#1122)
+		ctx.KVCache.ReleaseKVBlocks(preemptedRequest)
+	}
+	delete(ctx.ComputedTokens, preemptedRequest.ID)
+	ctx.WaitQ.PrependFront(preemptedRequest)
+}
+
+// swapIn re-admits the swapped-out wait-queue head next: it re-allocates the
+// blocks of its kept progress plus this step's tokens (one decode token, or
+// its next prefill chunk within the budget) and schedules it. Prompt blocks
+// still in the prefix cache are reused rather than copied in. Returns false,
+// leaving it queued, when free blocks cannot hold them all; a swap-in never
+// preempts.
+func swapIn(next *Request, result *BatchResult, ctx BatchContext, tokenBudget *int64) bool {
+	numNewTokens := int64(1)
+	if next.ProgressIndex < next.InputLen() {
+		numNewTokens = next.InputLen() - next.ProgressIndex
+		if 0 < ctx.PrefillTokenThreshold && ctx.PrefillTokenThreshold < numNewTokens {
+			numNewTokens = ctx.PrefillTokenThreshold
+		}
+		numNewTokens = min(numNewTokens, *tokenBudget)
+	}
+	kvc := kvStoreFor(ctx.KVCache, next)
+	blockSize := kvc.BlockSize()
+	need := (next.ProgressIndex + numNewTokens + blockSize - 1) / blockSize
+	if need > kvc.TotalCapacity()-kvc.UsedBlocks() {
+		return false
+	}
+	cachedBlocks, ok := swapInKVBlocks(kvc, next)
+	if !ok {
+		return false
+	}
+	if !ctx.KVCache.AllocateKVBlocks(next, next.ProgressIndex, next.ProgressIndex+numNewTokens, nil) {
+		ctx.KVCache.ReleaseKVBlocks(next)
+		return false
+	}
+	ctx.WaitQ.DequeueBatch()
+	next.SwappedOut = false // keeps its first ScheduledStepIdx
+	result.RunningBatch.Requests = append(result.RunningBatch.Requests, next)
+	result.NewlyScheduled = append(result.NewlyScheduled, ScheduledRequest{
+		Request:         next,
+		SwappedInBlocks: swappedBlocks(next, blockSize) - cachedBlocks,
+	})
+	*tokenBudget -= numNewTokens
+	next.State = StateRunning
+	next.NumNewTokens = int(numNewTokens)
+	ctx.ComputedTokens[next.ID] = next.ProgressIndex + numNewTokens
+	return true
+}
+
+// preemptForAdmission makes KV room for the wait-queue head next, whose
+// allocation just failed, by evicting running requests strictly less urgent
+// than it (higher Request.Priority), least urgent first. Requests scheduled
+// earlier in this step are never victims. Only active under
+// PreemptionPriorityAdmission; other policies never preempt for a waiting
+// request (vLLM parity).
+//
+// The victims are chosen before any eviction: the shortest run of eligible
+// requests, in victim order, whose release lets next's allocation fit. If even
+// all of them would not, nothing is evicted. next is dequeued before the
+// evictions so the victims, prepended to the wait queue, line up behind
+// nothing. Returns true with next dequeued and its blocks allocated; on false
+// next is left at the queue head.
+func (v *VLLMBatchFormation) preemptForAdmission(next *Request, startIndex, endIndex int64, cachedBlocks []int64, result *BatchResult, ctx BatchContext, tokenBudget *int64) bool {
+	if v.preemptionPolicy != PreemptionPriorityAdmission {
+		return false
+	}
+	candidates := make([]*Request, 0, len(result.RunningBatch.Requests))
+	for _, req := range result.RunningBatch.Requests {
+		if req.Priority > next.Priority && !slices.ContainsFunc(result.NewlyScheduled, func(s ScheduledRequest) bool { return s.Request == req }) {
+			candidates = append(candidates, req)
+		}
+	}
+	victims := make([]*Request, 0, len(candidates))
+	fits := false
+	for len(candidates) > 0 && !fits {
+		i := v.selectPriorityVictim(candidates)
+		victims = append(victims, candidates[i])
+		candidates = slices.Delete(candidates, i, i+1)
+		fits = ctx.KVCache.CanAllocateAfterRelease(victims, next, startIndex, endIndex, cachedBlocks)
+	}
+	if !fits {
+		return false
+	}
+
+	ctx.WaitQ.DequeueBatch()
+	result.PreemptionHappened = true
+	for _, victim := range victims {
+		preemptRunningRequest(slices.Index(result.RunningBatch.Requests, victim), result, ctx, tokenBudget)
+	}
+	if ctx.KVCache.AllocateKVBlocks(next, startIndex, endIndex, cachedBlocks) {
+		return true
+	}
+	ctx.WaitQ.PrependFront(next)
+	return false
+}
+
 // selectPriorityVictim returns the index of the least-urgent running request.
 // Least urgent = highest Request.Priority value (vLLM convention: lower = more urgent).
 // Ties broken by latest ArrivalTime (most recently arrived evicted first, least KV investment).
@@ -349,8 +770,10 @@ func (v *VLLMBatchFormation) selectPriorityVictim(requests []*Request) int {
 }
 
 // NewBatchFormation creates the default BatchFormation.
-// preemptionPolicy selects victim strategy: "fcfs" (tail-of-batch) or "priority" (least-urgent SLO tier).
-// In "priority" mode, victim selection reads Request.Priority directly (set by the pre-processor
+// preemptionPolicy selects victim strategy: "fcfs" (tail-of-batch), "priority" (least-urgent SLO tier),
+// "priority-admission" (priority, plus eviction on behalf of a more urgent waiting request),
+// or "priority-inheritance" (priority, with the holder blocking the most urgent waiter ranked at its priority).
+// In the priority modes, victim selection reads Request.Priority directly (set by the pre-processor
 // in Simulator.EnqueueRequest via SLOPriorityMap.InvertForVLLM — no sloMap needed here).
 func NewBatchFormation(preemptionPolicy string) BatchFormation {
 	policy := PreemptionPolicy(preemptionPolicy)
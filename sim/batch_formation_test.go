@@ -2,6 +2,7 @@ package sim
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -14,7 +15,7 @@ func TestVLLMBatchFormation_ImplementsInterface(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	if bf == nil {
@@ -50,7 +51,7 @@ func TestVLLMBatchFormation_TokenBudgetEnforced(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 50, 0), // tight token budget
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -104,7 +105,7 @@ func TestVLLMBatchFormation_BatchSizeEnforced(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(200, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(2, 10000, 0), // tight batch size limit
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -160,7 +161,7 @@ func TestVLLMBatchFormation_PreemptionReleasesKV(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(3, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -227,7 +228,7 @@ func TestVLLMBatchFormation_PreemptionStopsDequeue(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(3, 16, 0, 0, 0, 0), // very tight
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -283,7 +284,7 @@ func TestVLLMBatchFormation_CircuitBreaker(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(2, 16, 0, 0, 0, 0), // very small
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -329,7 +330,7 @@ func TestVLLMBatchFormation_KVAllocationFailure_StopsDequeue(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(3, 16, 0, 0, 0, 0), // limited KV blocks
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -439,7 +440,7 @@ func TestVLLMBatchFormation_Phase1_EvictedNotRevisited(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(6, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{0, 0, 0}, []float64{100, 1, 0}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
@@ -557,8 +558,8 @@ func TestVLLMBatchFormation_LivelockResolution(t *testing.T) {
 			[]float64{5752.705191348184, 17.25086436834028, 5.999143920128404},   // beta
 			[]float64{232.46191091038054, 1.752360364195244, 3357.4400353290152}, // alpha
 		),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
-		PolicyConfig:        NewPolicyConfig("fcfs", ""),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		PolicyConfig:        NewPolicyConfig("fcfs", "", ""),
 	}
 
 	sim := mustNewSimulator(t, cfg)
@@ -1218,3 +1219,205 @@ func TestSelectPriorityVictim_ArrivalTimeTiebreak(t *testing.T) {
 		t.Errorf("BC-4: victim = %s, want newer (max ArrivalTime tiebreak)", reqs[idx].ID)
 	}
 }
+
+// TestPreemption_PriorityAdmission_WaitingRequestEvictsLessUrgent verifies that
+// under priority-admission a waiting request blocked on a full KV cache evicts a
+// strictly less urgent running request, while plain priority mode (vLLM parity)
+// and an equally urgent waiting request leave the running batch alone.
+func TestPreemption_PriorityAdmission_WaitingRequestEvictsLessUrgent(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		waitingSLO  string
+		wantPreempt bool
+	}{
+		{"critical evicts batch", "priority-admission", "critical", true},
+		{"priority mode waits", "priority", "critical", false},
+		{"equal urgency waits", "priority-admission", "batch", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN 9 blocks × 16 tokens, full with three batch-class requests of
+			// 40 tokens (3 blocks each; their next decode token fits in place)
+			kvCache := MustNewKVCacheState(9, 16)
+			running := []*Request{
+				makeRunningRequest("batch-0", "batch", 100, 40, kvCache),
+				makeRunningRequest("batch-1", "batch", 200, 40, kvCache),
+				makeRunningRequest("batch-2", "batch", 300, 40, kvCache),
+			}
+			if kvCache.UsedBlocks() != 9 {
+				t.Fatalf("setup: used blocks = %d, want 9", kvCache.UsedBlocks())
+			}
+
+			// AND a waiting request needing 2 blocks, with distinct tokens so it
+			// gets no prefix hit from the running requests
+			tokens := make([]TokenID, 32)
+			for i := range tokens {
+				tokens[i] = TokenID(i + 1)
+			}
+			waiting := &Request{
+				ID: "waiting", SLOClass: tt.waitingSLO, ArrivalTime: 400, State: StateQueued,
+				InputTokens: tokens, OutputTokens: make([]TokenID, 4),
+				Priority: float64(DefaultSLOPriorityMap().InvertForVLLM(tt.waitingSLO)),
+			}
+			wq := &WaitQueue{}
+			wq.Enqueue(waiting)
+
+			// WHEN a batch is formed
+			result := NewBatchFormation(tt.policy).FormBatch(BatchContext{
+				RunningBatch:       &Batch{Requests: running},
+				WaitQ:              wq,
+				KVCache:            kvCache,
+				MaxScheduledTokens: 10000,
+				MaxRunningReqs:     10,
+				Now:                1000,
+				ComputedTokens:     make(map[string]int64),
+			})
+
+			admitted := false
+			for _, r := range result.RunningBatch.Requests {
+				if r == waiting {
+					admitted = true
+				}
+			}
+			if !tt.wantPreempt {
+				if len(result.Preempted) != 0 || admitted {
+					t.Fatalf("preempted = %d, admitted = %v; want no preemption and the request left waiting", len(result.Preempted), admitted)
+				}
+				if wq.Len() != 1 || wq.Peek() != waiting {
+					t.Errorf("waiting request is not at the queue head")
+				}
+				return
+			}
+
+			// THEN exactly one batch request is evicted: the latest arrival
+			if len(result.Preempted) != 1 || result.Preempted[0].Request.ID != "batch-2" {
+				t.Fatalf("preempted = %v, want [batch-2]", result.Preempted)
+			}
+			if !result.PreemptionHappened {
+				t.Error("PreemptionHappened = false, want true")
+			}
+			// AND the critical request runs with its prefill scheduled
+			if !admitted || waiting.State != StateRunning || waiting.NumNewTokens != 32 {
+				t.Errorf("waiting request: admitted=%v state=%v NumNewTokens=%d, want running with 32 tokens",
+					admitted, waiting.State, waiting.NumNewTokens)
+			}
+			// AND the victim is requeued at the head with no progress
+			victim := result.Preempted[0].Request
+			if wq.Len() != 1 || wq.Peek() != victim || victim.State != StateQueued || victim.ProgressIndex != 0 {
+				t.Errorf("victim not requeued cleanly: queue len=%d state=%v progress=%d", wq.Len(), victim.State, victim.ProgressIndex)
+			}
+			// AND KV blocks are conserved: 2 survivors × 3 + 2 for the new prefill
+			if got := kvCache.UsedBlocks(); got != 8 {
+				t.Errorf("used blocks = %d, want 8", got)
+			}
+		})
+	}
+}
+
+// TestPreemption_PriorityAdmission_InsufficientVictims_EvictsNothing verifies
+// that when evicting every less urgent running request would still not free
+// enough KV for the waiting request, no request is evicted: the running batch
+// keeps its state and blocks, and the waiting request stays at the queue head.
+func TestPreemption_PriorityAdmission_InsufficientVictims_EvictsNothing(t *testing.T) {
+	// GIVEN 9 blocks × 16 tokens, full with two batch-class requests and one
+	// critical request of 40 tokens (3 blocks each)
+	kvCache := MustNewKVCacheState(9, 16)
+	running := []*Request{
+		makeRunningRequest("batch-0", "batch", 100, 40, kvCache),
+		makeRunningRequest("crit-0", "critical", 200, 40, kvCache),
+		makeRunningRequest("batch-1", "batch", 300, 40, kvCache),
+	}
+
+	// AND a waiting critical request needing 7 blocks: the batch requests free 6
+	tokens := make([]TokenID, 112)
+	for i := range tokens {
+		tokens[i] = TokenID(i + 1)
+	}
+	waiting := &Request{
+		ID: "waiting", SLOClass: "critical", ArrivalTime: 400, State: StateQueued,
+		InputTokens: tokens, OutputTokens: make([]TokenID, 4),
+		Priority: float64(DefaultSLOPriorityMap().InvertForVLLM("critical")),
+	}
+	wq := &WaitQueue{}
+	wq.Enqueue(waiting)
+
+	// WHEN a batch is formed under priority-admission
+	result := NewBatchFormation("priority-admission").FormBatch(BatchContext{
+		RunningBatch:       &Batch{Requests: slices.Clone(running)},
+		WaitQ:              wq,
+		KVCache:            kvCache,
+		MaxScheduledTokens: 10000,
+		MaxRunningReqs:     10,
+		Now:                1000,
+		ComputedTokens:     make(map[string]int64),
+	})
+
+	// THEN nothing is preempted and the waiting request stays queued
+	if len(result.Preempted) != 0 || result.PreemptionHappened {
+		t.Fatalf("preempted = %v, want none", result.Preempted)
+	}
+	if wq.Len() != 1 || wq.Peek() != waiting || waiting.State != StateQueued {
+		t.Errorf("queue len=%d, waiting state=%v; want the waiting request alone at the head", wq.Len(), waiting.State)
+	}
+	// AND every running request keeps its state, progress and blocks
+	if !slices.Equal(result.RunningBatch.Requests, running) {
+		t.Errorf("running batch changed: got %d requests", len(result.RunningBatch.Requests))
+	}
+	for _, req := range running {
+		if req.State != StateRunning || req.ProgressIndex != 40 {
+			t.Errorf("%s: state=%v progress=%d, want running with 40", req.ID, req.State, req.ProgressIndex)
+		}
+	}
+	if got := kvCache.UsedBlocks(); got != 9 {
+		t.Errorf("used blocks = %d, want 9", got)
+	}
+}
+
+// TestVLLMBatchFormation_RemotePrefix_RecheckedAtFetch verifies a remote
+// prefix hit is re-checked when its fetch is charged: only the blocks the
+// owner still holds are fetched, and the rest are prefilled.
+func TestVLLMBatchFormation_RemotePrefix_RecheckedAtFetch(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		lookup    func(*Request) int
+		wantFetch int64
+		wantNew   int // prefill tokens computed: 129 less the fetched blocks
+	}{
+		{"no lookup trusts routing", nil, 8, 1},
+		{"owner evicted half", func(*Request) int { return 4 }, 4, 65},
+		{"owner evicted all", func(*Request) int { return 0 }, 0, 129},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// GIVEN a 129-token request annotated with an 8-block remote prefix hit
+			wq := &WaitQueue{}
+			req := &Request{
+				ID: "r", InputTokens: make([]TokenID, 129), OutputTokens: make([]TokenID, 4),
+				State: StateQueued, RemotePrefixBlocks: 8, RemotePrefixOwner: "instance_0",
+			}
+			for i := range req.InputTokens {
+				req.InputTokens[i] = TokenID(i + 1)
+			}
+			wq.Enqueue(req)
+
+			// WHEN the batch is formed with the owner's live count
+			result := NewBatchFormation("").FormBatch(BatchContext{
+				RunningBatch:       &Batch{},
+				WaitQ:              wq,
+				KVCache:            MustNewKVCacheState(100, 16),
+				MaxScheduledTokens: 10000,
+				MaxRunningReqs:     10,
+				ComputedTokens:     make(map[string]int64),
+				RemotePrefixCached: tc.lookup,
+			})
+
+			// THEN only the blocks still held are fetched
+			if result.RemotePrefixFetchedBlocks != tc.wantFetch {
+				t.Errorf("fetched %d blocks, want %d", result.RemotePrefixFetchedBlocks, tc.wantFetch)
+			}
+			if got := req.NumNewTokens; got != tc.wantNew {
+				t.Errorf("NumNewTokens = %d, want %d", got, tc.wantNew)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package sim
+
+// Bottleneck labels reported by ClassifyBottleneck.
+const (
+	BottleneckKV          = "kv-bound"           // waiting requests could not get KV blocks, or were preempted for them
+	BottleneckBatchSlots  = "batch-slot-bound"   // the running batch was full (MaxRunningReqs)
+	BottleneckTokenBudget = "token-budget-bound" // steps used the whole MaxScheduledTokens budget
+	BottleneckArrivals    = "arrival-limited"    // the wait queue drained: load, not capacity, set throughput
+	BottleneckOther       = "other"              // admission held by another gate (adapter load, critical reserve, batch affinity, KV fair share)
+)
+
+// StepBoundCounts counts formed batches by what ended admission
+// (recordStepBound): the wait queue draining, or the limit that left
+// requests waiting.
+type StepBoundCounts struct {
+	Arrivals    int64 `json:"arrivals"`
+	BatchSlots  int64 `json:"batch_slots"`
+	TokenBudget int64 `json:"token_budget"`
+	KV          int64 `json:"kv"`
+	Other       int64 `json:"other"`
+}
+
+// Total returns the number of classified steps.
+func (c StepBoundCounts) Total() int64 {
+	return c.Arrivals + c.BatchSlots + c.TokenBudget + c.KV + c.Other
+}
+
+// Merge adds other's counts into c (cluster aggregation).
+func (c *StepBoundCounts) Merge(other StepBoundCounts) {
+	c.Arrivals += other.Arrivals
+	c.BatchSlots += other.BatchSlots
+	c.TokenBudget += other.TokenBudget
+	c.KV += other.KV
+	c.Other += other.Other
+}
+
+// recordStepBound classifies the batch just formed by why it stopped
+// admitting. With requests still waiting, the first limit that holds wins:
+// batch slots, then the token budget, then KV (a preemption this step, the
+// KV-pressure running cap, or too few free blocks for the queue head's next
+// prefill chunk); anything else is another admission gate.
+func (sim *Simulator) recordStepBound(ctx BatchContext, result BatchResult) {
+	bounds := &sim.Metrics.StepBounds
+	if sim.WaitQ.Len() == 0 {
+		bounds.Arrivals++
+		return
+	}
+	running := result.RunningBatch.Requests
+	if int64(len(running)) >= sim.maxRunningReqs {
+		bounds.BatchSlots++
+		return
+	}
+	var scheduled int64
+	for _, req := range running {
+		scheduled += int64(req.NumNewTokens)
+	}
+	if scheduled >= sim.maxScheduledTokens {
+		bounds.TokenBudget++
+		return
+	}
+	if result.PreemptionHappened || len(running) >= kvPressureRunningCap(ctx) || !sim.queueHeadFitsKV(sim.maxScheduledTokens-scheduled) {
+		bounds.KV++
+		return
+	}
+	bounds.Other++
+}
+
+// queueHeadFitsKV reports whether the free KV blocks could hold the queue
+// head's next prefill chunk of at most budget tokens, ignoring prefix-cache
+// hits. Decode-only and suspended requests need one token; a swapped-out
+// request also needs its kept progress back.
+func (sim *Simulator) queueHeadFitsKV(budget int64) bool {
+	head := sim.WaitQ.Peek()
+	need := int64(1)
+	if !head.IsDecodeSubRequest && !head.Suspended {
+		need = max(min(head.InputLen()-head.ProgressIndex, budget), 1)
+	}
+	if head.SwappedOut {
+		need += head.ProgressIndex
+	}
+	blockSize := sim.KVCache.BlockSize()
+	free := sim.KVCache.TotalCapacity() - sim.KVCache.UsedBlocks()
+	return (need+blockSize-1)/blockSize <= free
+}
+
+// Bottleneck is a one-line diagnosis of what limited a run, with the numbers
+// behind it.
+type Bottleneck struct {
+	Label string `json:"label"`
+	// Share of formed batches per StepBoundCounts bucket.
+	ArrivalStepFraction     float64 `json:"arrival_step_fraction"`
+	BatchSlotStepFraction   float64 `json:"batch_slot_step_fraction"`
+	TokenBudgetStepFraction float64 `json:"token_budget_step_fraction"`
+	KVStepFraction          float64 `json:"kv_step_fraction"`
+	OtherStepFraction       float64 `json:"other_step_fraction"`
+	PreemptionsPerRequest   float64 `json:"preemptions_per_request"` // PreemptionCount / CompletedRequests
+	GPUIdleFraction         float64 `json:"gpu_idle_fraction"`
+	KVUtilization           float64 `json:"kv_utilization"` // time-weighted mean of used / total KV blocks
+}
+
+// Classification thresholds: a GPU idle at least half the time is waiting for
+// work, and one preemption per ten completions means the cache is thrashing.
+const (
+	bottleneckIdleFraction          = 0.5
+	bottleneckPreemptionsPerRequest = 0.1
+)
+
+// ClassifyBottleneck labels the primary bottleneck of a finalized run. A
+// mostly idle GPU is arrival-limited and frequent preemption is KV-bound;
+// otherwise the label is the most common step bound, ties going to the
+// earlier of arrivals, KV, batch slots, token budget and other. Returns ok
+// false when no batch was formed.
+func ClassifyBottleneck(m *Metrics) (b Bottleneck, ok bool) {
+	steps := m.StepBounds.Total()
+	if steps == 0 {
+		return Bottleneck{}, false
+	}
+	frac := func(n int64) float64 { return float64(n) / float64(steps) }
+	b = Bottleneck{
+		ArrivalStepFraction:     frac(m.StepBounds.Arrivals),
+		BatchSlotStepFraction:   frac(m.StepBounds.BatchSlots),
+		TokenBudgetStepFraction: frac(m.StepBounds.TokenBudget),
+		KVStepFraction:          frac(m.StepBounds.KV),
+		OtherStepFraction:       frac(m.StepBounds.Other),
+		GPUIdleFraction:         m.GPUIdleFraction(),
+		KVUtilization:           m.MeanKVUtilization(),
+	}
+	if m.CompletedRequests > 0 {
+		b.PreemptionsPerRequest = float64(m.PreemptionCount) / float64(m.CompletedRequests)
+	}
+	switch {
+	case b.GPUIdleFraction >= bottleneckIdleFraction:
+		b.Label = BottleneckArrivals
+	case b.PreemptionsPerRequest >= bottleneckPreemptionsPerRequest:
+		b.Label = BottleneckKV
+	default:
+		b.Label = BottleneckArrivals
+		best := m.StepBounds.Arrivals
+		for _, c := range []struct {
+			label string
+			n     int64
+		}{
+			{BottleneckKV, m.StepBounds.KV},
+			{BottleneckBatchSlots, m.StepBounds.BatchSlots},
+			{BottleneckTokenBudget, m.StepBounds.TokenBudget},
+			{BottleneckOther, m.StepBounds.Other},
+		} {
+			if c.n > best {
+				b.Label, best = c.label, c.n
+			}
+		}
+	}
+	return b, true
+}
+
+// MeanKVUtilization returns the time-weighted mean fraction of KV blocks in
+// use: KVBlocksUsed over KVTotalBlocks × SimEndedTime, or 0 before Finalize.
+func (m *Metrics) MeanKVUtilization() float64 {
+	if m.KVTotalBlocks <= 0 || m.SimEndedTime <= 0 {
+		return 0
+	}
+	return m.KVBlocksUsed / (float64(m.KVTotalBlocks) * float64(m.SimEndedTime))
+}
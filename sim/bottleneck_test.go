@@ -0,0 +1,58 @@
+package sim
+
+import "testing"
+
+// runBottleneck runs n requests (256-token prompts, 64 output tokens)
+// arriving gap ticks apart on a KV cache of kvBlocks 16-token blocks and
+// returns the finalized metrics.
+func runBottleneck(t *testing.T, n int, gap, kvBlocks int64) *Metrics {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(kvBlocks, 16, 0, 0, 0, 0)
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	runToCompletion(t, s, distinctPrompts(uniformRequests(n, 256, 64, gap)))
+	return s.Metrics
+}
+
+// TestClassifyBottleneck_KVBoundVersusArrivalLimited verifies the label on a
+// burst into a cache that holds about two requests, and on sparse arrivals
+// into an ample one.
+func TestClassifyBottleneck_KVBoundVersusArrivalLimited(t *testing.T) {
+	// Each request peaks at 20 blocks; 48 blocks fit two, so a burst of 30 waits on KV.
+	kvBound, ok := ClassifyBottleneck(runBottleneck(t, 30, 0, 48))
+	if !ok {
+		t.Fatal("KV-bound run: no steps classified")
+	}
+	t.Logf("KV-bound run: %+v", kvBound)
+	if kvBound.Label != BottleneckKV {
+		t.Errorf("burst into a small cache: label = %q, want %q", kvBound.Label, BottleneckKV)
+	}
+	if kvBound.KVStepFraction < 0.5 {
+		t.Errorf("KVStepFraction = %.2f, want >= 0.5", kvBound.KVStepFraction)
+	}
+	if kvBound.KVUtilization < 0.5 {
+		t.Errorf("KVUtilization = %.2f, want >= 0.5", kvBound.KVUtilization)
+	}
+
+	// One request every 100ms, each served alone in ~40ms.
+	idle, ok := ClassifyBottleneck(runBottleneck(t, 10, 100_000, 10000))
+	if !ok {
+		t.Fatal("arrival-limited run: no steps classified")
+	}
+	t.Logf("arrival-limited run: %+v", idle)
+	if idle.Label != BottleneckArrivals {
+		t.Errorf("sparse arrivals: label = %q, want %q", idle.Label, BottleneckArrivals)
+	}
+	if idle.ArrivalStepFraction != 1 || idle.PreemptionsPerRequest != 0 {
+		t.Errorf("ArrivalStepFraction = %.2f, PreemptionsPerRequest = %.3f, want 1 and 0",
+			idle.ArrivalStepFraction, idle.PreemptionsPerRequest)
+	}
+	if idle.GPUIdleFraction < 0.5 {
+		t.Errorf("GPUIdleFraction = %.2f, want >= 0.5", idle.GPUIdleFraction)
+	}
+
+	// No steps: nothing to classify.
+	if _, ok := ClassifyBottleneck(&Metrics{}); ok {
+		t.Error("empty metrics: ok = true, want false")
+	}
+}
@@ -24,6 +24,7 @@ type PolicyBundle struct {
 	NodePools         []NodePoolBundleConfig        `yaml:"node_pools"`         // nil = no node pools
 	Autoscaler        AutoscalerBundleConfig        `yaml:"autoscaler"`         // IntervalUs=0 = disabled
 	InstanceLifecycle InstanceLifecycleBundleConfig `yaml:"instance_lifecycle"` // zero = instant loading
+	InstanceOverrides []InstanceOverrideBundleConfig `yaml:"instance_overrides"` // nil = homogeneous cluster
 }
 
 // AdmissionConfig holds admission policy configuration.
@@ -37,6 +38,12 @@ type AdmissionConfig struct {
 	// GAIE-legacy options: only used when policy = "gaie-legacy".
 	GAIEQDThreshold *float64 `yaml:"gaie_qd_threshold"` // nil = use default (5)
 	GAIEKVThreshold *float64 `yaml:"gaie_kv_threshold"` // nil = use default (0.8)
+	// SLO-token-bucket options: only used when policy = "slo-token-bucket".
+	// Per-class bucket sizes; unlisted classes use token_bucket_capacity/refill_rate.
+	SLOTokenBuckets map[string]SLOTokenBucketConfig `yaml:"slo_token_buckets,omitempty"`
+	// Tenant-borrow options: only used when policy = "tenant-borrow".
+	// Per-tenant guaranteed fraction of cluster in-flight capacity; must sum to <= 1.
+	TenantShares map[string]float64 `yaml:"tenant_shares,omitempty"`
 	// SLOPriorities overrides default SLO class → priority mappings.
 	// nil = use GAIE defaults (critical=4, standard=3, batch=-1, sheddable=-2, background=-3).
 	SLOPriorities map[string]int   `yaml:"slo_priorities,omitempty"`
@@ -47,6 +54,9 @@ type AdmissionConfig struct {
 type RoutingConfig struct {
 	Policy  string         `yaml:"policy"`
 	Scorers []ScorerConfig `yaml:"scorers"`
+	// Weights are per-instance routing weights for policy "static-weighted",
+	// indexed by instance (weights[i] applies to instance_i). nil = not set.
+	Weights []float64 `yaml:"weights,omitempty"`
 }
 
 // PriorityConfig holds priority policy configuration.
@@ -95,6 +105,17 @@ type InstanceLifecycleBundleConfig struct {
 	WarmStartInitialInstances  bool            `yaml:"warm_start_initial_instances"`
 }
 
+// InstanceOverrideBundleConfig mirrors cluster.InstanceOverride for YAML loading.
+// Entry i applies to instance_i; omitted fields inherit the global configuration.
+// Converted to cluster.InstanceOverride in cmd/ to avoid a sim→sim/cluster circular import.
+type InstanceOverrideBundleConfig struct {
+	GPU           string    `yaml:"gpu"`
+	TP            *int      `yaml:"tp"`
+	TotalKVBlocks *int64    `yaml:"total_kv_blocks"`
+	BetaCoeffs    []float64 `yaml:"beta_coeffs"`
+	AlphaCoeffs   []float64 `yaml:"alpha_coeffs"`
+}
+
 // AutoscalerBundleConfig holds autoscaler pipeline configuration.
 // IntervalUs == 0 disables the autoscaler (default).
 // Note: ScaleDownStabilizationWindowUs = 0 (the Go zero value, used when the field is
@@ -127,11 +148,14 @@ func LoadPolicyBundle(path string) (*PolicyBundle, error) {
 // Valid policy name registries. Unexported to prevent external mutation.
 // Used by Validate(), factory functions, and ValidatePolicyName().
 var (
-	validAdmissionPolicies = map[string]bool{"": true, "always-admit": true, "token-bucket": true, "reject-all": true, "tier-shed": true, "gaie-legacy": true}
-	validRoutingPolicies   = map[string]bool{"": true, "round-robin": true, "least-loaded": true, "weighted": true, "always-busiest": true}
-	validSchedulers        = map[string]bool{"": true, "fcfs": true, "priority-fcfs": true, "sjf": true, "reverse-priority": true}
-	validPreemptionPolicies  = map[string]bool{"": true, "fcfs": true, "priority": true}
-	validLatencyBackends          = map[string]bool{"": true, "roofline": true, "trained-physics": true}
+	validAdmissionPolicies = map[string]bool{"": true, "always-admit": true, "token-bucket": true, "slo-token-bucket": true, "reject-all": true, "tier-shed": true, "gaie-legacy": true, "tenant-borrow": true}
+	validRoutingPolicies   = map[string]bool{"": true, "round-robin": true, "least-loaded": true, "decode-load": true, "weighted": true, "always-busiest": true, "static-weighted": true, "session-affinity": true, "replay": true}
+	validSchedulers        = map[string]bool{"": true, "fcfs": true, "priority-fcfs": true, "sjf": true, "reverse-priority": true, "prefix-pack": true, "wfq": true}
+	validPreemptionPolicies  = map[string]bool{"": true, "fcfs": true, "priority": true, "priority-admission": true, "priority-inheritance": true}
+	validPriorityPolicies    = map[string]bool{"": true, PriorityPolicySLOClass: true, PriorityPolicyExplicit: true}
+	validQueueOverflowPolicies = map[string]bool{"": true, QueueOverflowRejectNew: true, QueueOverflowDropOldest: true}
+	validKVAllocationModes     = map[string]bool{"": true, KVAllocationGreedy: true, KVAllocationFairShare: true}
+	validLatencyBackends          = map[string]bool{"": true, "roofline": true, "trained-physics": true, "table": true}
 	validDisaggregationDeciders   = map[string]bool{"": true, "never": true, "always": true, "prefix-threshold": true}
 	validEncodeDeciders           = map[string]bool{"": true, "never": true, "always": true, "multimodal": true}
 	validSaturationDetectors      = map[string]bool{"": true, "never": true, "utilization": true, "concurrency": true}
@@ -164,6 +188,24 @@ func IsValidPreemptionPolicy(name string) bool { return validPreemptionPolicies[
 // ValidPreemptionPolicyNames returns sorted valid preemption policy names (excluding empty).
 func ValidPreemptionPolicyNames() []string { return validNamesList(validPreemptionPolicies) }
 
+// IsValidPriorityPolicy returns true if name is a recognized priority policy.
+func IsValidPriorityPolicy(name string) bool { return validPriorityPolicies[name] }
+
+// ValidPriorityPolicyNames returns sorted valid priority policy names (excluding empty).
+func ValidPriorityPolicyNames() []string { return validNamesList(validPriorityPolicies) }
+
+// IsValidQueueOverflowPolicy returns true if name is a recognized wait-queue overflow policy.
+func IsValidQueueOverflowPolicy(name string) bool { return validQueueOverflowPolicies[name] }
+
+// ValidQueueOverflowPolicyNames returns sorted valid queue overflow policy names (excluding empty).
+func ValidQueueOverflowPolicyNames() []string { return validNamesList(validQueueOverflowPolicies) }
+
+// IsValidKVAllocationMode returns true if name is a recognized KV allocation mode.
+func IsValidKVAllocationMode(name string) bool { return validKVAllocationModes[name] }
+
+// ValidKVAllocationModeNames returns sorted valid KV allocation mode names (excluding empty).
+func ValidKVAllocationModeNames() []string { return validNamesList(validKVAllocationModes) }
+
 // IsValidLatencyBackend returns true if name is a recognized latency model backend.
 func IsValidLatencyBackend(name string) bool { return validLatencyBackends[name] }
 
@@ -254,12 +296,35 @@ func (b *PolicyBundle) Validate() error {
 			return fmt.Errorf("gaie_kv_threshold must be a finite value in (0, 1.0], got %v", v)
 		}
 	}
+	for class, cfg := range b.Admission.SLOTokenBuckets {
+		if cfg.Capacity <= 0 || math.IsNaN(cfg.Capacity) || math.IsInf(cfg.Capacity, 0) {
+			return fmt.Errorf("slo_token_buckets[%q].capacity must be a finite value > 0, got %v", class, cfg.Capacity)
+		}
+		if cfg.RefillRate <= 0 || math.IsNaN(cfg.RefillRate) || math.IsInf(cfg.RefillRate, 0) {
+			return fmt.Errorf("slo_token_buckets[%q].refill_rate must be a finite value > 0, got %v", class, cfg.RefillRate)
+		}
+	}
+	var shareSum float64
+	for tenantID, v := range b.Admission.TenantShares {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || v > 1 {
+			return fmt.Errorf("tenant_shares[%q] must be in [0, 1], got %v", tenantID, v)
+		}
+		shareSum += v
+	}
+	if shareSum > 1+1e-9 {
+		return fmt.Errorf("tenant_shares must sum to <= 1, got %v", shareSum)
+	}
 	// Validate tenant budgets: each value must be in [0, 1].
 	for tenantID, v := range b.TenantBudgets {
 		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || v > 1 {
 			return fmt.Errorf("tenant_budgets[%q] must be in [0, 1], got %v", tenantID, v)
 		}
 	}
+	if b.Routing.Weights != nil {
+		if err := ValidateStaticRoutingWeights(b.Routing.Weights); err != nil {
+			return fmt.Errorf("routing weights: %w", err)
+		}
+	}
 	// Validate scorer configs if present
 	scorerSeen := make(map[string]bool, len(b.Routing.Scorers))
 	for i, sc := range b.Routing.Scorers {
@@ -369,6 +434,23 @@ func (b *PolicyBundle) Validate() error {
 			return fmt.Errorf("node_pools[%d] %q: cost_per_hour must be >= 0, got %v", i, np.Name, np.CostPerHour)
 		}
 	}
+	// Validate instance overrides — mirrors cluster.InstanceOverride.Validate().
+	for i, o := range b.InstanceOverrides {
+		if o.TP != nil && *o.TP <= 0 {
+			return fmt.Errorf("instance_overrides[%d]: tp must be > 0 when set, got %d", i, *o.TP)
+		}
+		if o.TotalKVBlocks != nil && *o.TotalKVBlocks <= 0 {
+			return fmt.Errorf("instance_overrides[%d]: total_kv_blocks must be > 0 when set, got %d", i, *o.TotalKVBlocks)
+		}
+		if (len(o.BetaCoeffs) == 0) != (len(o.AlphaCoeffs) == 0) {
+			return fmt.Errorf("instance_overrides[%d]: beta_coeffs and alpha_coeffs must be set together", i)
+		}
+		for _, c := range append(append([]float64{}, o.BetaCoeffs...), o.AlphaCoeffs...) {
+			if math.IsNaN(c) || math.IsInf(c, 0) {
+				return fmt.Errorf("instance_overrides[%d]: latency coefficients must be finite, got %v", i, c)
+			}
+		}
+	}
 	// Validate instance lifecycle config.
 	lm := b.InstanceLifecycle.LoadingDelay.Mean
 	if math.IsNaN(lm) || math.IsInf(lm, 0) {
@@ -320,6 +320,57 @@ func TestPolicyBundle_Validate_TenantBudgets(t *testing.T) {
 	}
 }
 
+func TestPolicyBundle_Validate_SLOTokenBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets map[string]SLOTokenBucketConfig
+		wantErr bool
+	}{
+		{"valid", map[string]SLOTokenBucketConfig{"critical": {Capacity: 100, RefillRate: 10}}, false},
+		{"invalid: zero capacity", map[string]SLOTokenBucketConfig{"batch": {Capacity: 0, RefillRate: 10}}, true},
+		{"invalid: negative refill", map[string]SLOTokenBucketConfig{"batch": {Capacity: 100, RefillRate: -1}}, true},
+		{"invalid: NaN capacity", map[string]SLOTokenBucketConfig{"batch": {Capacity: math.NaN(), RefillRate: 10}}, true},
+		{"nil map: default buckets", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &PolicyBundle{Admission: AdmissionConfig{Policy: "slo-token-bucket", SLOTokenBuckets: tt.buckets}}
+			err := bundle.Validate()
+			if tt.wantErr {
+				assert.Error(t, err, "expected validation error for buckets=%v", tt.buckets)
+			} else {
+				assert.NoError(t, err, "unexpected validation error for buckets=%v", tt.buckets)
+			}
+		})
+	}
+}
+
+func TestPolicyBundle_Validate_TenantShares(t *testing.T) {
+	tests := []struct {
+		name    string
+		shares  map[string]float64
+		wantErr bool
+	}{
+		{"valid", map[string]float64{"a": 0.6, "b": 0.4}, false},
+		{"valid: partial", map[string]float64{"a": 0.3}, false},
+		{"invalid: sum above 1", map[string]float64{"a": 0.7, "b": 0.4}, true},
+		{"invalid: negative", map[string]float64{"a": -0.1}, true},
+		{"invalid: NaN", map[string]float64{"a": math.NaN()}, true},
+		{"nil map: no guarantees", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &PolicyBundle{Admission: AdmissionConfig{Policy: "tenant-borrow", TenantShares: tt.shares}}
+			err := bundle.Validate()
+			if tt.wantErr {
+				assert.Error(t, err, "expected validation error for shares=%v", tt.shares)
+			} else {
+				assert.NoError(t, err, "unexpected validation error for shares=%v", tt.shares)
+			}
+		})
+	}
+}
+
 // TestPolicyBundle_Validate_EmptyScorersIsValid verifies nil/empty scorers list is acceptable.
 func TestPolicyBundle_Validate_EmptyScorersIsValid(t *testing.T) {
 	bundle := &PolicyBundle{
@@ -603,8 +654,8 @@ func TestValidLatencyBackendNames_ExcludesRemoved(t *testing.T) {
 		}
 	}
 
-	// AND the list must contain exactly 2 backends
-	expected := []string{"roofline", "trained-physics"}
+	// AND the list must contain exactly 3 backends
+	expected := []string{"roofline", "table", "trained-physics"}
 	if len(names) != len(expected) {
 		t.Errorf("ValidLatencyBackendNames() returned %d backends; want %d: %v", len(names), len(expected), expected)
 	}
@@ -719,3 +770,60 @@ func TestPolicyBundle_Validate_InstanceLifecycle(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyBundle_Validate_InstanceOverrides(t *testing.T) {
+	cases := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name: "valid mixed fleet",
+			yaml: `instance_overrides:
+  - {}
+  - gpu: A100
+    tp: 2
+    total_kv_blocks: 4000
+    beta_coeffs: [2000, 20, 10]
+    alpha_coeffs: [200, 2, 200]`,
+			wantErr: "",
+		},
+		{
+			name: "zero tp",
+			yaml: `instance_overrides:
+  - tp: 0`,
+			wantErr: "tp must be > 0",
+		},
+		{
+			name: "negative kv blocks",
+			yaml: `instance_overrides:
+  - total_kv_blocks: -5`,
+			wantErr: "total_kv_blocks must be > 0",
+		},
+		{
+			name: "beta without alpha",
+			yaml: `instance_overrides:
+  - beta_coeffs: [1, 2, 3]`,
+			wantErr: "must be set together",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempYAML(t, tc.yaml)
+			bundle, err := LoadPolicyBundle(path)
+			if err != nil {
+				t.Fatalf("LoadPolicyBundle: %v", err)
+			}
+			err = bundle.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+const (
+	jitterTestRequests = 200
+	jitterTestMeanUs   = 50_000
+	jitterTestStdDevUs = 10_000
+)
+
+// runJitterCluster sends identical requests one second apart to a single
+// instance, so each request runs alone and its TTFT differs from a zero-latency
+// run by exactly its admission latency.
+func runJitterCluster(t *testing.T, dist string, meanUs int64, stddevUs float64, seed int64) *sim.Metrics {
+	t.Helper()
+	config := newTestDeploymentConfig(1)
+	config.Seed = seed
+	config.AdmissionLatency = meanUs
+	config.AdmissionLatencyDist = dist
+	config.AdmissionLatencyStdDevUs = stddevUs
+	requests := make([]*sim.Request, jitterTestRequests)
+	for i := range requests {
+		input := make([]sim.TokenID, 64)
+		for j := range input {
+			input[j] = sim.TokenID(1_000*i + j)
+		}
+		requests[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 1_000_000,
+			InputTokens:  input,
+			OutputTokens: make([]sim.TokenID, 4),
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	return cs.AggregatedMetrics()
+}
+
+// admissionOffsets returns each request's TTFT minus its TTFT in base, in µs.
+func admissionOffsets(t *testing.T, m, base *sim.Metrics) []float64 {
+	t.Helper()
+	offsets := make([]float64, 0, jitterTestRequests)
+	for i := 0; i < jitterTestRequests; i++ {
+		id := fmt.Sprintf("request_%d", i)
+		got, ok1 := m.RequestTTFTs[id]
+		want, ok2 := base.RequestTTFTs[id]
+		if !ok1 || !ok2 {
+			t.Fatalf("%s missing TTFT (jitter=%v, base=%v)", id, ok1, ok2)
+		}
+		offsets = append(offsets, got-want)
+	}
+	return offsets
+}
+
+func TestAdmissionJitter_MeanOffsetAndReproducibility(t *testing.T) {
+	base := runJitterCluster(t, "", 0, 0, 42)
+
+	for _, dist := range []string{AdmissionLatencyDistGaussian, AdmissionLatencyDistLognormal, AdmissionLatencyDistExponential} {
+		t.Run(dist, func(t *testing.T) {
+			// GIVEN per-request admission latency sampled with mean 50ms
+			offsets := admissionOffsets(t, runJitterCluster(t, dist, jitterTestMeanUs, jitterTestStdDevUs, 42), base)
+
+			// THEN the mean TTFT offset matches the configured mean
+			var sum float64
+			for _, o := range offsets {
+				if o < 0 {
+					t.Fatalf("negative admission offset %v", o)
+				}
+				sum += o
+			}
+			mean := sum / float64(len(offsets))
+			// Std error of the mean is 0.7ms (gaussian, lognormal) / 3.5ms (exponential) at n=200.
+			if tol := 0.15 * jitterTestMeanUs; math.Abs(mean-jitterTestMeanUs) > tol {
+				t.Errorf("mean offset = %.0f µs, want %d ± %.0f", mean, jitterTestMeanUs, tol)
+			}
+
+			// AND the offsets vary per request
+			var sq float64
+			for _, o := range offsets {
+				sq += (o - mean) * (o - mean)
+			}
+			if variance := sq / float64(len(offsets)); variance == 0 {
+				t.Error("admission offsets have zero variance, want per-request jitter")
+			}
+
+			// AND the same seed reproduces them exactly
+			again := admissionOffsets(t, runJitterCluster(t, dist, jitterTestMeanUs, jitterTestStdDevUs, 42), base)
+			for i := range offsets {
+				if offsets[i] != again[i] {
+					t.Fatalf("request_%d offset %v != %v on rerun with the same seed", i, offsets[i], again[i])
+				}
+			}
+		})
+	}
+}
+
+// TestAdmissionJitter_Disabled_IsFixedLatency verifies the default adds exactly
+// AdmissionLatency to every request (INV-6).
+func TestAdmissionJitter_Disabled_IsFixedLatency(t *testing.T) {
+	base := runJitterCluster(t, "", 0, 0, 42)
+	for i, o := range admissionOffsets(t, runJitterCluster(t, "", jitterTestMeanUs, 0, 42), base) {
+		if o != jitterTestMeanUs {
+			t.Fatalf("request_%d offset = %v, want %d", i, o, jitterTestMeanUs)
+		}
+	}
+}
+
+// TestAdmissionJitter_WideStdDev_LognormalKeepsMean verifies that with a stddev
+// twice the mean, clamping gaussian draws at 0 inflates the realized mean while
+// lognormal draws keep the configured mean.
+func TestAdmissionJitter_WideStdDev_LognormalKeepsMean(t *testing.T) {
+	const n = 200_000
+	sampleMean := func(dist string) float64 {
+		j := newAdmissionJitter(dist, jitterTestMeanUs, 2*jitterTestMeanUs, rand.New(rand.NewSource(7)))
+		var sum float64
+		for i := 0; i < n; i++ {
+			sum += float64(j.sample())
+		}
+		return sum / n
+	}
+
+	// Clamped N(μ, (2μ)²) has mean μ + 2μ·φ(0.5) − μ·Φ(−0.5) ≈ 1.40μ.
+	if got := sampleMean(AdmissionLatencyDistGaussian); got < 1.35*jitterTestMeanUs {
+		t.Errorf("gaussian mean = %.0f µs, want the clamp bias to lift it above %.0f", got, 1.35*jitterTestMeanUs)
+	}
+	// Std error of the lognormal mean is 0.45% of μ at n=200k.
+	if got := sampleMean(AdmissionLatencyDistLognormal); math.Abs(got-jitterTestMeanUs) > 0.05*jitterTestMeanUs {
+		t.Errorf("lognormal mean = %.0f µs, want %d ± 5%%", got, jitterTestMeanUs)
+	}
+}
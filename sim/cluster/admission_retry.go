@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/sirupsen/logrus"
+)
+
+// subsystemAdmissionRetry names the PartitionedRNG partition used for retry
+// backoff jitter, so enabling retries never perturbs routing or workload draws.
+const subsystemAdmissionRetry = "admission-retry"
+
+// admissionRetry holds the client retry model for admission-rejected requests.
+// Nil on ClusterSimulator when RetryMaxAttempts == 0 (BC-1: zero overhead).
+type admissionRetry struct {
+	maxAttempts int
+	baseBackoff int64
+	rng         *rand.Rand
+	attempts    map[string]int // request ID → retries already scheduled
+}
+
+func newAdmissionRetry(maxAttempts int, baseBackoff int64, rng *rand.Rand) *admissionRetry {
+	if maxAttempts < 0 {
+		panic(fmt.Sprintf("ClusterSimulator: RetryMaxAttempts must be >= 0, got %d", maxAttempts))
+	}
+	if baseBackoff <= 0 {
+		panic(fmt.Sprintf("ClusterSimulator: RetryBackoffUs must be > 0 when RetryMaxAttempts > 0, got %d", baseBackoff))
+	}
+	return &admissionRetry{
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+		rng:         rng,
+		attempts:    make(map[string]int),
+	}
+}
+
+// backoff returns the delay before retry number attempt (1-based):
+// baseBackoff * 2^(attempt-1), scaled by a uniform jitter factor in [0.5, 1.5).
+// The exponential term saturates instead of overflowing int64.
+func (r *admissionRetry) backoff(attempt int) int64 {
+	mean := float64(r.baseBackoff) * math.Pow(2, float64(attempt-1))
+	delay := mean * (0.5 + r.rng.Float64())
+	if delay >= math.MaxInt64/2 {
+		return math.MaxInt64 / 2
+	}
+	return max(int64(delay), 1)
+}
+
+// scheduleAdmissionRetry re-submits an admission-rejected request after a
+// backoff. Returns false when the rejection is final: the request has used all
+// its retries, or the retry would land past the horizon (INV-1: such requests
+// are counted as rejected rather than silently lost in the event queue).
+// The retry re-enters at AdmissionDecisionEvent, not ClusterArrivalEvent, so it
+// is not double-counted in injectedByClass or seen again by the arrival hook.
+func (cs *ClusterSimulator) scheduleAdmissionRetry(req *sim.Request) bool {
+	r := cs.admissionRetry
+	if r == nil {
+		return false
+	}
+	attempt := r.attempts[req.ID] + 1
+	if attempt > r.maxAttempts {
+		return false
+	}
+	delay := r.backoff(attempt) + cs.admissionDelay()
+	if cs.clock > cs.config.Horizon-delay {
+		return false
+	}
+	r.attempts[req.ID] = attempt
+	cs.retriedRequests++
+	logrus.Debugf("[cluster] req %s: admission retry %d/%d in %d µs", req.ID, attempt, r.maxAttempts, delay)
+	heap.Push(&cs.clusterEvents, clusterEventEntry{
+		event: &AdmissionDecisionEvent{
+			time:    cs.clock + delay,
+			request: req,
+		},
+		seqID: cs.nextSeqID(),
+	})
+	return true
+}
@@ -0,0 +1,133 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// runRetryOverload drives a token-bucket-admitted cluster at 4× the bucket's
+// sustainable rate (~200 req/s offered vs ~50 req/s of 100-token requests refilled).
+func runRetryOverload(t *testing.T, maxAttempts int, backoffUs int64) *ClusterSimulator {
+	t.Helper()
+	config := newTestDeploymentConfig(2)
+	config.Horizon = 60_000_000
+	config.AdmissionPolicy = "token-bucket"
+	config.TokenBucketCapacity = 500
+	config.TokenBucketRefillRate = 5000
+	config.RetryMaxAttempts = maxAttempts
+	config.RetryBackoffUs = backoffUs
+	requests := testGenerateRequests(42, math.MaxInt64, 200.0/1e6, 400,
+		0, 100, 0, 100, 100, 20, 0, 20, 20)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	return cs
+}
+
+// TestAdmissionRetry_Overload_AmplifiesOfferedLoad verifies that under overload
+// with retries enabled, admission sees more attempts than base arrivals, and
+// that retries outnumber the rejections the same workload produces without
+// retries (retry amplification): every rejected client comes back, and coming
+// back competes with fresh arrivals for the same token budget.
+func TestAdmissionRetry_Overload_AmplifiesOfferedLoad(t *testing.T) {
+	const numRequests, maxAttempts = 400, 5
+
+	base := runRetryOverload(t, 0, 0)
+	if base.RetriedRequests() != 0 {
+		t.Fatalf("retries disabled: RetriedRequests = %d, want 0", base.RetriedRequests())
+	}
+	baseRejected := base.RejectedRequests()
+	if baseRejected == 0 {
+		t.Fatal("baseline produced no rejections; workload is not overloaded")
+	}
+
+	cs := runRetryOverload(t, maxAttempts, 50_000)
+	retries := cs.RetriedRequests()
+	injected := int(cs.InjectedByClass()[""])
+	offered := injected + retries
+
+	if injected != numRequests {
+		t.Fatalf("injected = %d, want %d (retries must not count as fresh arrivals)", injected, numRequests)
+	}
+	if offered <= injected {
+		t.Errorf("offered load %d (injected %d + retries %d) does not exceed base arrivals", offered, injected, retries)
+	}
+	if retries <= baseRejected {
+		t.Errorf("retries = %d, want > %d baseline rejections (retry amplification)", retries, baseRejected)
+	}
+	if retries > maxAttempts*numRequests {
+		t.Errorf("retries = %d exceeds cap %d × %d requests", retries, maxAttempts, numRequests)
+	}
+	for id, n := range cs.admissionRetry.attempts {
+		if n > maxAttempts {
+			t.Errorf("request %s retried %d times, want <= %d", id, n, maxAttempts)
+		}
+	}
+
+	// INV-1: each request is admitted once or rejected once, however many times it retried.
+	agg := cs.AggregatedMetrics()
+	admitted := agg.CompletedRequests + agg.StillQueued + agg.StillRunning
+	if admitted+cs.RejectedRequests() != numRequests {
+		t.Errorf("conservation: admitted(%d) + rejected(%d) = %d, want %d",
+			admitted, cs.RejectedRequests(), admitted+cs.RejectedRequests(), numRequests)
+	}
+	t.Logf("offered=%d (%.2f× base) retries=%d rejected: %d→%d",
+		offered, float64(offered)/float64(injected), retries, baseRejected, cs.RejectedRequests())
+}
+
+// TestAdmissionRetry_Disabled_IsInert verifies RetryMaxAttempts=0 leaves the
+// admission pipeline unchanged even when a backoff is configured (INV-6).
+func TestAdmissionRetry_Disabled_IsInert(t *testing.T) {
+	cs := runRetryOverload(t, 0, 50_000)
+	if cs.admissionRetry != nil {
+		t.Error("admissionRetry constructed with RetryMaxAttempts = 0")
+	}
+	plain := runRetryOverload(t, 0, 0)
+	if cs.RejectedRequests() != plain.RejectedRequests() ||
+		cs.AggregatedMetrics().CompletedRequests != plain.AggregatedMetrics().CompletedRequests {
+		t.Errorf("backoff without retries changed results: rejected %d vs %d, completed %d vs %d",
+			cs.RejectedRequests(), plain.RejectedRequests(),
+			cs.AggregatedMetrics().CompletedRequests, plain.AggregatedMetrics().CompletedRequests)
+	}
+}
+
+func TestAdmissionRetry_Backoff_ExponentialWithJitter(t *testing.T) {
+	r := newAdmissionRetry(4, 1000, rand.New(rand.NewSource(7)))
+	for attempt := 1; attempt <= 4; attempt++ {
+		mean := 1000 * math.Pow(2, float64(attempt-1))
+		for i := 0; i < 50; i++ {
+			d := float64(r.backoff(attempt))
+			if d < 0.5*mean-1 || d >= 1.5*mean {
+				t.Fatalf("attempt %d: backoff %v outside [%v, %v)", attempt, d, 0.5*mean, 1.5*mean)
+			}
+		}
+	}
+}
+
+func TestNewClusterSimulator_InvalidRetryConfig_Panics(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		backoff     int64
+	}{
+		{"negative attempts", -1, 1000},
+		{"zero backoff", 3, 0},
+		{"negative backoff", 3, -5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(fmt.Sprint(r), "Retry") {
+					t.Errorf("recovered %v, want a Retry* validation panic", r)
+				}
+			}()
+			config := newTestDeploymentConfig(1)
+			config.RetryMaxAttempts = tc.maxAttempts
+			config.RetryBackoffUs = tc.backoff
+			NewClusterSimulator(config, NewSliceRequestSource(nil), nil)
+		})
+	}
+}
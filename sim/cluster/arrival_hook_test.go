@@ -257,7 +257,7 @@ func TestINV13_ArrivalHookRunReplayParity(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadTraceV2: %v", err)
 	}
-	replayReqs, err := workload.LoadTraceV2Requests(loaded, fixedSeed)
+	replayReqs, err := workload.LoadTraceV2Requests(loaded, fixedSeed, "")
 	if err != nil {
 		t.Fatalf("LoadTraceV2Requests: %v", err)
 	}
@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// TestCollectRawMetrics_CacheHitRateByTenant_SeparatesRepeatedFromUniquePrompts
+// verifies that prefix-cache hits are attributed to the requesting tenant and
+// SLO class: a tenant replaying one prompt sees a high hit rate while a tenant
+// sending unique prompts sees none, and the pooled per-group counts agree with
+// the instance's aggregate CacheHitRate.
+func TestCollectRawMetrics_CacheHitRateByTenant_SeparatesRepeatedFromUniquePrompts(t *testing.T) {
+	const (
+		numPerTenant = 10
+		inputLen     = 250 // 15 full blocks at block size 16 plus a partial tail
+	)
+	shared := make([]sim.TokenID, inputLen)
+	for i := range shared {
+		shared[i] = sim.TokenID(i + 1)
+	}
+	var requests []*sim.Request
+	for i := 0; i < numPerTenant; i++ {
+		unique := make([]sim.TokenID, inputLen)
+		for j := range unique {
+			unique[j] = sim.TokenID(100_000 + i*inputLen + j)
+		}
+		arrival := int64(i) * 50_000
+		requests = append(requests,
+			&sim.Request{
+				ID: fmt.Sprintf("repeat_%d", i), ArrivalTime: arrival,
+				InputTokens: shared, OutputTokens: make([]sim.TokenID, 4), MaxOutputLen: 4,
+				State: sim.StateQueued, TenantID: "repeat", SLOClass: "critical",
+			},
+			&sim.Request{
+				ID: fmt.Sprintf("unique_%d", i), ArrivalTime: arrival + 1,
+				InputTokens: unique, OutputTokens: make([]sim.TokenID, 4), MaxOutputLen: 4,
+				State: sim.StateQueued, TenantID: "unique", SLOClass: "batch",
+			})
+	}
+
+	cs := NewClusterSimulator(newTestDeploymentConfig(1), NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	raw := CollectRawMetrics(cs.AggregatedMetrics(), cs.PerInstanceMetrics(), 0, "", 0, 0, nil)
+
+	if got := raw.CacheHitRateByTenant["repeat"]; got < 0.8 {
+		t.Errorf("tenant repeat hit rate = %.4f, want >= 0.8 (identical prompts)", got)
+	}
+	if got, ok := raw.CacheHitRateByTenant["unique"]; !ok || got != 0 {
+		t.Errorf("tenant unique hit rate = %.4f (present=%v), want 0", got, ok)
+	}
+	if raw.CacheHitRateBySLOClass["critical"] != raw.CacheHitRateByTenant["repeat"] ||
+		raw.CacheHitRateBySLOClass["batch"] != raw.CacheHitRateByTenant["unique"] {
+		t.Errorf("per-class rates %v should mirror per-tenant rates %v (one class per tenant)",
+			raw.CacheHitRateBySLOClass, raw.CacheHitRateByTenant)
+	}
+
+	// Per-tenant counts partition the instance's aggregate hit/miss counts.
+	var total sim.CacheHitCounts
+	for _, c := range cs.AggregatedMetrics().CacheHitCountsByTenant {
+		total.Hits += c.Hits
+		total.Misses += c.Misses
+	}
+	if math.Abs(total.Rate()-raw.CacheHitRate) > 1e-12 {
+		t.Errorf("pooled per-tenant hit rate %.6f != aggregate CacheHitRate %.6f", total.Rate(), raw.CacheHitRate)
+	}
+}
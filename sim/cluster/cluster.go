@@ -4,10 +4,12 @@ import (
 	"container/heap"
 	"fmt"
 	"math"
+	"math/rand"
 	"reflect"
 	"sort"
 
 	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/kv"
 	"github.com/inference-sim/inference-sim/sim/latency"
 	"github.com/inference-sim/inference-sim/sim/trace"
 	"github.com/sirupsen/logrus"
@@ -34,11 +36,20 @@ type ClusterSimulator struct {
 	snapshotProvider      *CachedSnapshotProvider
 	routingPolicy         sim.RoutingPolicy
 	rejectedRequests      int                       // EC-2: count of requests rejected by admission policy
+	retriedRequests       int                       // admission retries scheduled by the retry model (0 when disabled)
+	admissionRetry        *admissionRetry           // nil when RetryMaxAttempts == 0
+	admissionJitter       *admissionJitter          // nil when AdmissionLatencyDist == ""
+	failedRequests        int                       // requests lost to an injected instance failure (FaultModeLose)
+	requeuedRequests      int                       // requests re-routed after an injected instance failure (FaultModeRequeue)
+	sharedPrefixIndex     *sim.PrefixCacheIndex     // cluster-wide prefix index; nil unless SharedPrefixCache
+	remotePrefixHits      int                       // routed requests with a longer prefix cached on another instance
 	routingRejections     int                       // I13: count of requests rejected at routing (no routable instances)
 	shedByTier            map[string]int            // per-SLOClass shedding: admission rejections + gateway queue shed + in-flight evictions
 	// injectedByClass: per-SLOClass arrival counter. Incremented in ClusterArrivalEvent.Execute
 	// before any drop/route/admission decision. Goodput denominator (issue #1409, BC-5).
 	injectedByClass map[string]int64
+	arrivedRequests       int                       // fresh arrivals (REDIRECT re-injections excluded); INV-1 left-hand side
+	pipelineRequests      int                       // arrived but not yet in a wait queue when Run stopped; set after the event loop
 	trace                 *trace.SimulationTrace    // nil when trace-level is "none" (BC-1: zero overhead)
 	requestSource         RequestSource             // Source of requests to inject as arrival events. Drained once by Run().
 	inFlightRequests      map[string]int            // instance ID → dispatched-but-not-completed count (#463)
@@ -72,6 +83,8 @@ type ClusterSimulator struct {
 	pdDecodeCompletedCount  int               // decode sub-requests that completed (for INV-1 in-flight tracking)
 	pdDecodeTimedOutCount   int               // decode sub-requests that timed out (for INV-1 in-flight tracking)
 	droppedAtDecodeKV       int               // requests dropped due to insufficient KV at decode
+	droppedTooLarge         int               // requests dropped at admission: input exceeds every instance's KV capacity
+	completedSeries         []int             // cluster-wide cumulative completions per sample tick (ThroughputSampleIntervalUs > 0)
 	prefillRoutingPolicy    sim.RoutingPolicy // nil = use main routingPolicy
 	decodeRoutingPolicy     sim.RoutingPolicy // nil = use main routingPolicy
 
@@ -87,12 +100,24 @@ type ClusterSimulator struct {
 	transferStartCount             int64
 	contentionBookkeepingCorrupted bool
 
+	// replayMissing lists requests "replay" routing rejected because the
+	// routing-decision log has no entry for them; Run returns an error when non-empty.
+	replayMissing []string
+
+	// Host-memory bandwidth contention for CPU-tier KV transfers
+	// (--kv-offload-host-contention). One resource per node; unplaced instances
+	// share the "" host. Nil when the flag is off.
+	hostBandwidth map[string]*kv.HostBandwidth
+
 	// Phase 1A: node/GPU placement manager. Nil when NodePools is empty (backward-compat).
 	placement *PlacementManager
 
 	// Phase 1B-2a: per-tenant fair-share tracker. Nil when TenantBudgets is nil (backward-compat).
 	tenantTracker *TenantTracker
 
+	// Cluster-wide in-flight token ledger behind MaxInFlightTokens. Nil when the cap is 0 (INV-6).
+	inFlightTokens *InFlightTokenTracker
+
 	// Phase 1C: model autoscaler pipeline. Nil when ModelAutoscalerIntervalUs == 0 (backward-compat, INV-6).
 	autoscaler      *autoscalerPipeline
 	pendingArrivals int // count of ClusterArrivalEvents not yet executed; used by scheduleNextTick to stop ticking when all work is done
@@ -142,6 +167,19 @@ type ClusterSimulator struct {
 	// fireArrivalHook() panics on a regression.
 	arrivalHook         func(*sim.Request)
 	lastArrivalHookTime int64 // monotonicity guard for arrivalHook (us)
+
+	// eventLog records every executed cluster and instance event. Nil unless
+	// SetEventLog was called, so the event loop pays only a nil check.
+	eventLog *sim.EventLog
+
+	// nextArrival is the request pulled from requestSource but not yet
+	// scheduled. Used only when StopAfterCompleted > 0, where arrivals are
+	// pulled on demand instead of drained up front.
+	nextArrival *sim.Request
+
+	// stopCompletions counts the completions admitted by allowCompletion
+	// toward StopAfterCompleted (PD prefill sub-requests excluded).
+	stopCompletions int64
 }
 
 // effectiveAnalyzerConfig applies WVA reference defaults to zero-valued fields.
@@ -193,6 +231,24 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 			panic(fmt.Sprintf("ClusterSimulator: %v", err))
 		}
 	}
+	if len(config.InstanceOverrides) > config.NumInstances {
+		panic(fmt.Sprintf("ClusterSimulator: %d InstanceOverrides exceed NumInstances=%d", len(config.InstanceOverrides), config.NumInstances))
+	}
+	for i, o := range config.InstanceOverrides {
+		if err := o.Validate(fmt.Sprintf("instance_%d", i)); err != nil {
+			panic(fmt.Sprintf("ClusterSimulator: %v", err))
+		}
+	}
+	if config.PrefixHashSkipTokens < 0 {
+		panic(fmt.Sprintf("ClusterSimulator: PrefixHashSkipTokens must be >= 0, got %d", config.PrefixHashSkipTokens))
+	}
+	if len(config.RoutingInstanceWeights) > config.NumInstances {
+		panic(fmt.Sprintf("ClusterSimulator: %d RoutingInstanceWeights exceed NumInstances=%d", len(config.RoutingInstanceWeights), config.NumInstances))
+	}
+	if len(config.IngressClockOffsetsUs) > 0 && config.StopAfterCompleted > 0 {
+		// On-demand pulls rely on the source's arrival order, which skew breaks.
+		panic("ClusterSimulator: IngressClockOffsetsUs cannot be combined with StopAfterCompleted")
+	}
 
 	// Validate KV bytes per token derivation early so KVTransferStartedEvent never
 	// encounters a configuration error at runtime (the panic there is now unreachable).
@@ -238,11 +294,18 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 	// Extract PartitionedRNG before struct literal so routing policy can use SubsystemRouter.
 	// The routing policy exclusively owns the SubsystemRouter partition — do not reuse
 	// cs.rng.ForSubsystem(SubsystemRouter) elsewhere to avoid interleaving RNG draws.
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(config.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(config.Seed), config.RandSource)
 
 	// Construct SLO priority map from config overrides (nil-safe: defaults used when empty).
 	priorityMap := sim.NewSLOPriorityMap(config.SLOPriorityOverrides)
 
+	// tenant-borrow admission reads per-tenant in-flight counts; the same tracker
+	// serves TenantBudgets below when both are configured.
+	var tenantTracker *TenantTracker
+	if config.AdmissionPolicy == "tenant-borrow" {
+		tenantTracker = NewTenantTracker(config.TenantBudgets, config.NumInstances*int(config.MaxRunningReqs))
+	}
+
 	// Bypass generic factory for policies needing custom params (research.md D-2).
 	var admissionPolicy sim.AdmissionPolicy
 	switch config.AdmissionPolicy {
@@ -261,6 +324,10 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 			kvThreshold = 0.8 // GAIE DefaultKVCacheUtilThreshold (config.go:33)
 		}
 		admissionPolicy = sim.NewGAIELegacyAdmission(qdThreshold, kvThreshold, priorityMap)
+	case "slo-token-bucket":
+		admissionPolicy = sim.NewSLOTokenBucketAdmission(config.SLOTokenBuckets, config.TokenBucketCapacity, config.TokenBucketRefillRate)
+	case "tenant-borrow":
+		admissionPolicy = sim.NewTenantBorrowAdmission(config.TenantShares, tenantTracker)
 	default:
 		admissionPolicy = sim.NewAdmissionPolicy(config.AdmissionPolicy, config.TokenBucketCapacity, config.TokenBucketRefillRate)
 	}
@@ -274,6 +341,7 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 		admissionLatency:     config.AdmissionLatency,
 		routingLatency:       config.RoutingLatency,
 		admissionPolicy:      admissionPolicy,
+		tenantTracker:        tenantTracker,
 		priorityMap:          priorityMap,
 		snapshotProvider:     nil, // set after unified construction loop below
 		routingPolicy:        nil, // set after instance construction (needs cacheQueryFn from instances)
@@ -283,6 +351,19 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 		injectedByClass:      make(map[string]int64),
 	}
 
+	if err := config.FaultInjection.Validate(); err != nil {
+		panic(fmt.Sprintf("ClusterSimulator: %v", err))
+	}
+	if config.FaultInjection.IsEnabled() && (config.PrefillInstances > 0 || config.DecodeInstances > 0 || config.SharedInstances > 0) {
+		panic("ClusterSimulator: FaultInjection is not supported with PD disaggregation")
+	}
+	if config.SharedPrefixCache {
+		if config.PrefillInstances > 0 || config.DecodeInstances > 0 || config.SharedInstances > 0 {
+			panic("ClusterSimulator: SharedPrefixCache is not supported with PD disaggregation")
+		}
+		cs.sharedPrefixIndex = sim.NewPrefixCacheIndex(int(config.BlockSizeTokens), int(config.TotalKVBlocks))
+	}
+
 	// PD disaggregation: set pool membership (topology already validated above).
 	// Decider construction is deferred until after cs.cacheQueryFn is built
 	// (PrefixThresholdDecider consumes the map).
@@ -326,17 +407,22 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 		if prePoolMembership != nil {
 			role = prePoolMembership[string(id)]
 		}
-		simCfg := config.resolveConfigForRole(role)
+		simCfg := config.resolveConfigForInstance(idx, role)
+		override, hasOverride := config.instanceOverride(idx)
+		instTP := tpDegree
+		if override.TP != nil {
+			instTP = *override.TP
+		}
 
 		if cs.placement != nil {
 			// NodePools path: placement determines GPU type (authoritative).
 			// Pass "" as gpuType so PlacementManager selects any available pool
 			// (the pool's gpu_type is the authoritative source, not the CLI --gpu flag).
-			nodeID, gpuIDs, matchedGPUType, err := cs.placement.PlaceInstance(id, config.Model, "", tpDegree)
+			nodeID, gpuIDs, matchedGPUType, err := cs.placement.PlaceInstance(id, config.Model, "", instTP)
 			if err != nil {
 				// No capacity — defer construction until NodeReadyEvent.
 				// Pass "" as gpuType (any pool) to match AddPending's placement semantics.
-				cs.placement.AddPending(id, config.Model, "", tpDegree, simCfg)
+				cs.placement.AddPending(id, config.Model, "", instTP, simCfg)
 				continue
 			}
 			// Placement succeeded: use pool's GPU type (SC-004: pool-authoritative, not CLI flag).
@@ -362,7 +448,7 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 			inst.Model = config.Model
 			inst.nodeID = nodeID
 			inst.allocatedGPUIDs = gpuIDs
-			inst.TPDegree = tpDegree
+			inst.TPDegree = instTP
 			inst.CostPerHour = poolCostPerHour
 			inst.warmUpRemaining = config.InstanceLifecycle.WarmUpRequestCount
 			if config.InstanceLifecycle.WarmStartInitialInstances {
@@ -387,6 +473,12 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 			// for the default role, preserving ModelHardwareConfig.GPU from the CLI flag.
 			inst := NewInstanceSimulator(id, simCfg)
 			inst.Model = config.Model
+			if hasOverride {
+				// Heterogeneous clusters: expose the per-instance TP degree to routing
+				// snapshots so capacity-aware scorers can tell instances apart.
+				// Homogeneous clusters leave TPDegree at 0 (unplaced) as before (INV-6).
+				inst.TPDegree = instTP
+			}
 			inst.warmUpRemaining = config.InstanceLifecycle.WarmUpRequestCount
 			if inst.warmUpRemaining > 0 {
 				inst.TransitionTo(sim.InstanceStateWarmingUp)
@@ -399,6 +491,17 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 		}
 	}
 
+	if config.KVOffloadHostContention {
+		cs.hostBandwidth = make(map[string]*kv.HostBandwidth)
+		for _, inst := range cs.instances {
+			cs.attachHostBandwidth(inst)
+		}
+	}
+	for _, inst := range cs.instances {
+		cs.attachSharedPrefixCache(inst)
+		cs.attachCompletionGate(inst)
+	}
+
 	// Initialize snapshot provider with exactly the placed instances.
 	// Deferred instances are registered via CachedSnapshotProvider.AddInstance
 	// when NodeReadyEvent.Execute constructs them (Phase 4, T017).
@@ -410,13 +513,13 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 	cs.cacheQueryFn = cs.snapshotProvider.BuildCacheQueryFn()
 
 	// Create routing policies now that cacheQueryFn is available.
-	cs.routingPolicy = sim.NewRoutingPolicyWithCache(config.RoutingPolicy, config.RoutingScorerConfigs, config.BlockSizeTokens, rng.ForSubsystem(sim.SubsystemRouter), cs.cacheQueryFn)
-	if len(config.PrefillScorerConfigs) > 0 {
-		cs.prefillRoutingPolicy = sim.NewRoutingPolicyWithCache("weighted", config.PrefillScorerConfigs, config.BlockSizeTokens, rng.ForSubsystem("prefill-router"), cs.cacheQueryFn)
-	}
-	if len(config.DecodeScorerConfigs) > 0 {
-		cs.decodeRoutingPolicy = sim.NewRoutingPolicyWithCache("weighted", config.DecodeScorerConfigs, config.BlockSizeTokens, rng.ForSubsystem("decode-router"), cs.cacheQueryFn)
+	if !IsValidRoutingTieBreak(config.RoutingTieBreak) {
+		panic(fmt.Sprintf("ClusterSimulator: unknown RoutingTieBreak %q (valid: %s, %s)",
+			config.RoutingTieBreak, RoutingTieBreakRandom, RoutingTieBreakLowestIndex))
 	}
+	cs.routingPolicy = cs.newRoutingPolicy(config.RoutingPolicy, config.RoutingScorerConfigs, rng.ForSubsystem(sim.SubsystemRouter))
+	cs.prefillRoutingPolicy = cs.newPoolRoutingPolicy(config.PrefillRoutingPolicy, config.PrefillScorerConfigs, rng.ForSubsystem("prefill-router"))
+	cs.decodeRoutingPolicy = cs.newPoolRoutingPolicy(config.DecodeRoutingPolicy, config.DecodeScorerConfigs, rng.ForSubsystem("decode-router"))
 
 	// PD disaggregation: construct the decider now that cacheQueryFn is available.
 	// PrefixThresholdDecider consumes the per-pod cache-query map; other deciders
@@ -477,6 +580,18 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 		)
 	}
 
+	// Admission retry model: rejected requests re-enter admission after backoff.
+	// Disabled by default (RetryMaxAttempts == 0), in which case the pipeline is unchanged.
+	if config.RetryMaxAttempts != 0 {
+		cs.admissionRetry = newAdmissionRetry(config.RetryMaxAttempts, config.RetryBackoffUs, rng.ForSubsystem(subsystemAdmissionRetry))
+	}
+
+	// Admission latency jitter: per-request latency sampled around AdmissionLatency.
+	// Disabled by default (AdmissionLatencyDist == ""), in which case every request waits exactly AdmissionLatency.
+	if config.AdmissionLatencyDist != "" {
+		cs.admissionJitter = newAdmissionJitter(config.AdmissionLatencyDist, config.AdmissionLatency, config.AdmissionLatencyStdDevUs, rng.ForSubsystem(subsystemAdmissionJitter))
+	}
+
 	// Flow control: per-band gateway queue with FlowControlAdmission policy (issue #882, #1191).
 	// When disabled (default), the pipeline is unchanged — requests flow directly
 	// from admission to routing (BC-1 pass-through equivalence).
@@ -549,10 +664,31 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 			logrus.Warnf("[cluster] tenant_budgets configured but totalCapacity=0 (NumInstances=%d, MaxRunningReqs=%d); all budgeted tenants will be immediately over-budget — set max_running_reqs > 0",
 				config.NumInstances, config.MaxRunningReqs)
 		}
-		cs.tenantTracker = NewTenantTracker(config.TenantBudgets, totalCapacity)
+		if cs.tenantTracker == nil {
+			cs.tenantTracker = NewTenantTracker(config.TenantBudgets, totalCapacity)
+		}
 		cs.admissionPolicy = sim.NewTenantBudgetAdmission(cs.admissionPolicy, cs.tenantTracker, cs.priorityMap)
 	}
 
+	// Cluster-wide in-flight token cap. Outermost decorator, so a capped request
+	// is rejected before any inner policy sees it. Admission reserves the tokens;
+	// the OnRequestDone hook below releases them on every terminal state.
+	if config.MaxInFlightTokens < 0 {
+		panic(fmt.Sprintf("ClusterSimulator: MaxInFlightTokens must be >= 0, got %d", config.MaxInFlightTokens))
+	}
+	if config.MaxInFlightTokens > 0 {
+		// Flow control admits into the gateway queue and PD splits requests into
+		// sub-requests, so neither has a single terminal signal to release on.
+		if config.FlowControlEnabled {
+			panic("ClusterSimulator: MaxInFlightTokens cannot be combined with flow control")
+		}
+		if config.PrefillInstances > 0 || config.SharedInstances > 0 {
+			panic("ClusterSimulator: MaxInFlightTokens cannot be combined with PD disaggregation")
+		}
+		cs.inFlightTokens = NewInFlightTokenTracker()
+		cs.admissionPolicy = sim.NewInFlightTokenCapAdmission(cs.admissionPolicy, config.MaxInFlightTokens, cs.inFlightTokens)
+	}
+
 	// Startup warning: horizon too small for pipeline (BC-1)
 	pipelineLatency := cs.admissionLatency + cs.routingLatency
 	if cs.config.Horizon > 0 && cs.config.Horizon < pipelineLatency {
@@ -568,13 +704,16 @@ func NewClusterSimulator(config DeploymentConfig, requestSource RequestSource, o
 	// admission → routing → instance injection. The callback returns nil so the per-instance
 	// simulator does not inject locally.
 	// Phase 1B-2a: also notify tenantTracker on completion when budgets are configured.
-	if onRequestDone != nil || cs.tenantTracker != nil || cs.evictionTracker != nil {
+	if onRequestDone != nil || cs.tenantTracker != nil || cs.evictionTracker != nil || cs.inFlightTokens != nil {
 		for _, inst := range cs.instances {
 			inst.sim.OnRequestDone = func(req *sim.Request, tick int64) []*sim.Request {
 				// Phase 1B-2a: release tenant in-flight slot on every terminal state.
 				if cs.tenantTracker != nil {
 					cs.tenantTracker.OnComplete(req.TenantID)
 				}
+				if cs.inFlightTokens != nil {
+					cs.inFlightTokens.Release(req.ID)
+				}
 				// Remove from eviction tracker on normal completion (BC-3).
 				if cs.evictionTracker != nil {
 					cs.evictionTracker.Untrack(req.ID)
@@ -710,21 +849,31 @@ func (c *ClusterSimulator) Run() error {
 		})
 	}
 
+	// Fault injection: schedule the configured instance failure (disabled by default).
+	if fi := c.config.FaultInjection; fi.IsEnabled() {
+		heap.Push(&c.clusterEvents, clusterEventEntry{
+			event: &InstanceFailureEvent{timestamp: fi.AtUs, instanceID: InstanceID(fi.InstanceID), mode: fi.Mode},
+			seqID: c.nextSeqID(),
+		})
+	}
+
 	// 2. Drain the request source to schedule arrival events. The source is
 	// required to yield in non-decreasing ArrivalTime order (RequestSource
 	// contract — caller obligation, not verified here); we count emissions to
 	// preserve today's "no requests" warning.
+	// StopAfterCompleted pulls only the first request here; the event loop
+	// pulls the rest on demand so an unbounded source is never drained.
 	arrivalCount := 0
-	for {
-		req, ok := c.requestSource.Next()
-		if !ok {
-			break
+	if c.config.StopAfterCompleted > 0 {
+		c.nextArrival = c.pullArrival()
+		if c.nextArrival != nil {
+			arrivalCount++
 		}
-		if req == nil {
-			panic("ClusterSimulator: RequestSource.Next() returned (nil, true) — implementation contract violation (Next must never return ok=true with a nil request)")
+	} else {
+		for req := c.pullArrival(); req != nil; req = c.pullArrival() {
+			c.pushArrival(req, req.ArrivalTime)
+			arrivalCount++
 		}
-		c.pushArrival(req, req.ArrivalTime)
-		arrivalCount++
 	}
 	if arrivalCount == 0 {
 		logrus.Warn("[cluster] no requests provided — simulation will produce zero results")
@@ -751,18 +900,34 @@ func (c *ClusterSimulator) Run() error {
 			}
 		}
 
+		// On-demand arrivals (StopAfterCompleted): schedule the next request
+		// once no earlier event is pending, then re-scan.
+		if c.nextArrival != nil && c.nextArrival.ArrivalTime <= min(clusterTime, instanceTime) {
+			c.pushArrival(c.nextArrival, c.nextArrival.ArrivalTime)
+			c.nextArrival = c.pullArrival()
+			continue
+		}
+
 		// Both queues empty: done
 		if clusterTime == math.MaxInt64 && instanceIdx == -1 {
 			break
 		}
 
+		c.recordThroughputSamplesBefore(min(clusterTime, instanceTime))
+
 		// BC-4: Cluster events at time T processed before instance events at time T
 		// Using <= ensures cluster events drain first when timestamps are equal
 		if clusterTime <= instanceTime {
+			// Checked before popping so a request event past the horizon
+			// stays queued and is counted by PipelineRequests.
+			if clusterTime > c.config.Horizon {
+				c.clock = clusterTime
+				break
+			}
 			entry := heap.Pop(&c.clusterEvents).(clusterEventEntry)
 			c.clock = entry.event.Timestamp()
-			if c.clock > c.config.Horizon {
-				break
+			if c.eventLog != nil {
+				c.eventLog.Record(sim.EventLogRecord{Tick: c.clock, Type: sim.EventTypeName(entry.event), RequestID: clusterEventRequestID(entry.event)})
 			}
 			entry.event.Execute(c)
 		} else {
@@ -778,23 +943,27 @@ func (c *ClusterSimulator) Run() error {
 			completedBefore := inst.Metrics().CompletedRequests
 			droppedBefore := inst.Metrics().DroppedUnservable
 			timedOutBefore := inst.Metrics().TimedOutRequests
+			overflowBefore := inst.Metrics().QueueOverflowRejected + inst.Metrics().QueueOverflowDropped
 
 			ev := inst.ProcessNextEvent()
 
 			// If ProcessNextEvent() skipped a cancelled TimeoutEvent (lazy
 			// cancellation — inst.Clock was not advanced), restore c.clock.
 			// A no-op orphaned timeout must not advance the cluster clock.
-			if te, ok := ev.(*sim.TimeoutEvent); ok && te.Request.State == sim.StateCompleted {
+			if te, ok := ev.(*sim.TimeoutEvent); ok && (te.Request.State == sim.StateCompleted || te.Request.QueueDropped) {
 				c.clock = prevClusterClock
+			} else if c.eventLog != nil {
+				c.eventLog.Record(sim.EventLogRecord{Tick: c.clock, Type: sim.EventTypeName(ev), InstanceID: instID, RequestID: sim.EventRequestID(ev)})
 			}
 
 			// Completion-based decrement (#463, BC-3, BC-7): InFlightRequests tracks the full
 			// dispatch-to-completion window. Decrement by the number of newly completed,
-			// dropped-unservable, or timed-out requests.
+			// dropped-unservable, timed-out, or queue-overflow (rejected/dropped) requests.
 			completedAfter := inst.Metrics().CompletedRequests
 			droppedAfter := inst.Metrics().DroppedUnservable
 			timedOutAfter := inst.Metrics().TimedOutRequests
-			delta := (completedAfter - completedBefore) + (droppedAfter - droppedBefore) + (timedOutAfter - timedOutBefore)
+			overflowAfter := inst.Metrics().QueueOverflowRejected + inst.Metrics().QueueOverflowDropped
+			delta := (completedAfter - completedBefore) + (droppedAfter - droppedBefore) + (timedOutAfter - timedOutBefore) + (overflowAfter - overflowBefore)
 			if delta > 0 {
 				c.inFlightRequests[instID] -= delta
 				if c.inFlightRequests[instID] < 0 {
@@ -846,6 +1015,10 @@ func (c *ClusterSimulator) Run() error {
 					c.detectDecodeCompletions(inst)
 				}
 			}
+
+			if n := c.config.StopAfterCompleted; n > 0 && int64(c.completedRequestsTotal()-c.pdPrefillCompletedCount) >= n {
+				break
+			}
 		}
 
 		c.maybeDeliverProgressSnapshot(false)
@@ -858,6 +1031,19 @@ func (c *ClusterSimulator) Run() error {
 		inst.Finalize()
 	}
 
+	// Requests that arrived but had not reached an instance's wait queue when
+	// the run stopped: a pending admission, disaggregation or routing decision,
+	// or an instance arrival not yet queued (INV-1).
+	for _, entry := range c.clusterEvents {
+		switch entry.event.(type) {
+		case *AdmissionDecisionEvent, *DisaggregationDecisionEvent, *RoutingDecisionEvent, *PrefillRoutingEvent:
+			c.pipelineRequests++
+		}
+	}
+	for _, inst := range c.instances {
+		c.pipelineRequests += inst.sim.InTransitRequests()
+	}
+
 	// 5. Post-simulation invariant: inFlightRequests should match StillQueued + StillRunning
 	// MUST be after Finalize() — StillQueued/StillRunning are zero until Finalize populates them.
 	// NOTE: A mismatch can occur legitimately if requests were routed near the horizon but their
@@ -888,7 +1074,12 @@ func (c *ClusterSimulator) Run() error {
 	// completed (counted above and subtracted), but the original request is lost.
 	// Count as DroppedUnservable for INV-1 conservation.
 	if c.droppedAtDecodeKV > 0 {
-		c.aggregatedMetrics.DroppedUnservable += c.droppedAtDecodeKV
+		c.aggregatedMetrics.AddUnservable(sim.UnservableDecodeKV, c.droppedAtDecodeKV)
+	}
+	// Requests dropped at admission as too large for any instance's KV cache
+	// never reached an instance; count them here (INV-1).
+	if c.droppedTooLarge > 0 {
+		c.aggregatedMetrics.AddUnservable(sim.UnservableTooLarge, c.droppedTooLarge)
 	}
 	// In-flight PD transfers: requests whose prefill completed but decode hasn't
 	// finished or been dropped yet (e.g., simulation ended at bounded horizon while
@@ -920,6 +1111,14 @@ func (c *ClusterSimulator) Run() error {
 	// user-facing distributions reflect the full request lifecycle.
 	c.projectPDMetrics()
 
+	c.aggregatedMetrics.ThroughputSampleIntervalUs = c.config.ThroughputSampleIntervalUs
+	c.aggregatedMetrics.CompletedSeries = sim.FinishCompletedSeries(c.completedSeries,
+		c.config.ThroughputSampleIntervalUs, c.aggregatedMetrics.SimEndedTime, c.aggregatedMetrics.CompletedRequests)
+
+	if len(c.replayMissing) > 0 {
+		return fmt.Errorf("replay routing: %d requests missing from the routing-decision log (first: %q)", len(c.replayMissing), c.replayMissing[0])
+	}
+
 	// Post-simulation contention bookkeeping checks (INV-P2-2)
 	if c.contentionBookkeepingCorrupted {
 		return fmt.Errorf("contention bookkeeping corrupted: activeTransfers went negative during simulation — contention metrics are invalid")
@@ -951,6 +1150,70 @@ func (c *ClusterSimulator) Run() error {
 	return nil
 }
 
+// newPoolRoutingPolicy builds the routing policy for one PD pool, or returns nil
+// so the pool falls back to the main routing policy. An empty policy name keeps
+// the pre-existing behavior: "weighted" over the pool's scorers when configured.
+// A named "weighted" policy without pool scorers uses the main scorer configs.
+func (cs *ClusterSimulator) newPoolRoutingPolicy(policy string, scorers []sim.ScorerConfig, rng *rand.Rand) sim.RoutingPolicy {
+	if policy == "" {
+		if len(scorers) == 0 {
+			return nil
+		}
+		policy = "weighted"
+	}
+	if !sim.IsValidRoutingPolicy(policy) {
+		panic(fmt.Sprintf("ClusterSimulator: unknown pool routing policy %q", policy))
+	}
+	if policy == "replay" {
+		panic("ClusterSimulator: replay routing is supported for the main router only")
+	}
+	if len(scorers) == 0 {
+		scorers = cs.config.RoutingScorerConfigs
+	}
+	return cs.newRoutingPolicy(policy, scorers, rng)
+}
+
+// Routing tie-break modes for DeploymentConfig.RoutingTieBreak.
+const (
+	RoutingTieBreakRandom      = "random"       // seeded router RNG picks among tied instances
+	RoutingTieBreakLowestIndex = "lowest-index" // first tied instance in snapshot (instance) order
+)
+
+var validRoutingTieBreaks = map[string]bool{"": true, RoutingTieBreakRandom: true, RoutingTieBreakLowestIndex: true}
+
+// IsValidRoutingTieBreak returns true if name is a recognized routing
+// tie-break mode ("" = random).
+func IsValidRoutingTieBreak(name string) bool { return validRoutingTieBreaks[name] }
+
+// newRoutingPolicy builds a routing policy by name. "static-weighted" takes
+// its weights from RoutingInstanceWeights keyed by instance ID, "replay" its
+// log from RoutingReplayLog; every other policy goes through
+// sim.NewRoutingPolicyWithCache.
+func (cs *ClusterSimulator) newRoutingPolicy(policy string, scorers []sim.ScorerConfig, rng *rand.Rand) sim.RoutingPolicy {
+	if policy == "replay" {
+		if len(cs.config.RoutingReplayLog) == 0 {
+			panic("ClusterSimulator: replay routing requires a non-empty RoutingReplayLog")
+		}
+		return sim.NewReplayRouting(cs.config.RoutingReplayLog)
+	}
+	if policy != "static-weighted" {
+		// A nil rng makes the policies break ties by lowest snapshot index.
+		// static-weighted keeps its rng: it samples, it does not break ties.
+		if cs.config.RoutingTieBreak == RoutingTieBreakLowestIndex {
+			rng = nil
+		}
+		return sim.NewRoutingPolicyWithCache(policy, scorers, cs.config.BlockSizeTokens, cs.config.PrefixHashSkipTokens, rng, cs.cacheQueryFn)
+	}
+	if err := sim.ValidateStaticRoutingWeights(cs.config.RoutingInstanceWeights); err != nil {
+		panic(fmt.Sprintf("ClusterSimulator: static-weighted routing: %v", err))
+	}
+	weights := make(map[string]float64, len(cs.config.RoutingInstanceWeights))
+	for i, w := range cs.config.RoutingInstanceWeights {
+		weights[fmt.Sprintf("instance_%d", i)] = w
+	}
+	return sim.NewStaticWeighted(weights, rng)
+}
+
 // nextSeqID returns the next monotonically increasing sequence ID for event ordering.
 func (c *ClusterSimulator) nextSeqID() int64 {
 	id := c.seqCounter
@@ -1049,6 +1312,47 @@ func (c *ClusterSimulator) maybeDeliverProgressSnapshot(isFinal bool) {
 	}
 }
 
+// pullArrival returns the next request from the source, or nil once it is
+// exhausted.
+func (c *ClusterSimulator) pullArrival() *sim.Request {
+	req, ok := c.requestSource.Next()
+	if !ok {
+		return nil
+	}
+	if req == nil {
+		panic("ClusterSimulator: RequestSource.Next() returned (nil, true) — implementation contract violation (Next must never return ok=true with a nil request)")
+	}
+	if offset, ok := c.config.IngressClockOffsetsUs[req.IngressPoint]; ok && req.IngressPoint != "" {
+		req.ArrivalTime = max(req.ArrivalTime+offset, 0)
+	}
+	return req
+}
+
+// attachCompletionGate caps inst's completions at StopAfterCompleted. No-op
+// when StopAfterCompleted is disabled.
+func (c *ClusterSimulator) attachCompletionGate(inst *InstanceSimulator) {
+	if c.config.StopAfterCompleted <= 0 {
+		return
+	}
+	inst.attachCompletionGate(c.allowCompletion)
+}
+
+// allowCompletion admits a finished request's completion while fewer than
+// StopAfterCompleted requests have completed. Once the Nth is admitted, requests
+// finishing in the same step stay running, so the run stops with exactly N
+// completions. PD prefill sub-requests always complete and are not counted:
+// their parent completes on the decode instance.
+func (c *ClusterSimulator) allowCompletion(req *sim.Request) bool {
+	if _, prefill := c.pendingPrefillCompletions[req.ID]; prefill {
+		return true
+	}
+	if c.stopCompletions >= c.config.StopAfterCompleted {
+		return false
+	}
+	c.stopCompletions++
+	return true
+}
+
 func (c *ClusterSimulator) completedRequestsTotal() int {
 	total := 0
 	for _, inst := range c.instances {
@@ -1057,6 +1361,25 @@ func (c *ClusterSimulator) completedRequestsTotal() int {
 	return total
 }
 
+// recordThroughputSamplesBefore appends the cluster-wide completed-request
+// count (prefill sub-requests excluded, as in the aggregate) for every pending
+// sample tick earlier than nextEventTime and within the horizon. Samples past
+// the run's end are trimmed by sim.FinishCompletedSeries.
+func (c *ClusterSimulator) recordThroughputSamplesBefore(nextEventTime int64) {
+	interval := c.config.ThroughputSampleIntervalUs
+	if interval <= 0 {
+		return
+	}
+	next := interval * int64(len(c.completedSeries)+1)
+	if next >= nextEventTime || next > c.config.Horizon {
+		return
+	}
+	completed := c.completedRequestsTotal() - c.pdPrefillCompletedCount
+	for ; next < nextEventTime && next <= c.config.Horizon; next += interval {
+		c.completedSeries = append(c.completedSeries, completed)
+	}
+}
+
 func (c *ClusterSimulator) timedOutRequestsTotal() int {
 	total := 0
 	for _, inst := range c.instances {
@@ -1066,7 +1389,7 @@ func (c *ClusterSimulator) timedOutRequestsTotal() int {
 }
 
 func (c *ClusterSimulator) droppedRequestsTotal() int {
-	total := 0
+	total := c.droppedTooLarge
 	for _, inst := range c.instances {
 		total += inst.Metrics().DroppedUnservable
 	}
@@ -1097,6 +1420,21 @@ func (c *ClusterSimulator) preemptionsTotal() int64 {
 	return total
 }
 
+// attachHostBandwidth connects inst to the shared host-memory bandwidth of its
+// node, creating the node's resource on first use. No-op when
+// KVOffloadHostContention is off.
+func (cs *ClusterSimulator) attachHostBandwidth(inst *InstanceSimulator) {
+	if cs.hostBandwidth == nil {
+		return
+	}
+	h, ok := cs.hostBandwidth[inst.nodeID]
+	if !ok {
+		h = kv.NewHostBandwidth()
+		cs.hostBandwidth[inst.nodeID] = h
+	}
+	inst.attachHostBandwidth(h)
+}
+
 // addLiveInstance constructs, registers, and activates an InstanceSimulator for a
 // placement that succeeded while the cluster is already running.
 // Called from NodeReadyEvent.Execute (deferred placement) and DirectActuator.scaleUp
@@ -1140,6 +1478,9 @@ func (cs *ClusterSimulator) addLiveInstance(
 	cs.scheduleInstanceLoadedEvent(inst)
 	cs.instances = append(cs.instances, inst)
 	cs.inFlightRequests[string(id)] = 0
+	cs.attachHostBandwidth(inst)
+	cs.attachSharedPrefixCache(inst)
+	cs.attachCompletionGate(inst)
 
 	// Register with cacheQueryFn for precise prefix scoring.
 	// registerInstanceCacheQueryFn handles both oracle and stale modes (R23).
@@ -1149,11 +1490,14 @@ func (cs *ClusterSimulator) addLiveInstance(
 
 	// Wire OnRequestDone callback — mirrors startup path in NewClusterSimulator (R4).
 	onRequestDone := cs.sessionCallback
-	if onRequestDone != nil || cs.tenantTracker != nil || cs.evictionTracker != nil {
+	if onRequestDone != nil || cs.tenantTracker != nil || cs.evictionTracker != nil || cs.inFlightTokens != nil {
 		inst.sim.OnRequestDone = func(req *sim.Request, tick int64) []*sim.Request {
 			if cs.tenantTracker != nil {
 				cs.tenantTracker.OnComplete(req.TenantID)
 			}
+			if cs.inFlightTokens != nil {
+				cs.inFlightTokens.Release(req.ID)
+			}
 			if cs.evictionTracker != nil {
 				cs.evictionTracker.Untrack(req.ID)
 			}
@@ -1300,7 +1644,9 @@ func (c *ClusterSimulator) detectDecodeCompletions(inst *InstanceSimulator) {
 		// For roofline (overhead=0), value is byte-identical to before.
 		// No zero-output guard needed: decode sub-requests always carry the full
 		// output token list from the original request (set in KVTransferCompletedEvent.Execute).
-		parent.CompletionTime = c.clock + inst.PostDecodeFixedOverhead()
+		// DetokenizationTime is likewise added so PD E2E matches the decode
+		// sub-request's own E2E (0 when the term is disabled).
+		parent.CompletionTime = c.clock + inst.PostDecodeFixedOverhead() + inst.DetokenizationTime(parent.DecodeSubReq)
 		delete(c.pendingDecodeCompletions, subReqID)
 		c.pdDecodeCompletedCount++
 
@@ -1359,6 +1705,26 @@ func (c *ClusterSimulator) Instances() []*InstanceSimulator {
 	return c.instances
 }
 
+// DrainInstance stops routing new requests to the instance with the given ID
+// while it finishes its queued and running requests, which complete normally
+// (the WAIT drain policy). The instance turns Terminated once idle. May be
+// called before Run or mid-run from a hook (e.g. SetArrivalHook). Returns an
+// error if no instance has the ID or it is not currently routable.
+func (c *ClusterSimulator) DrainInstance(id string) error {
+	for _, inst := range c.instances {
+		if string(inst.ID()) != id {
+			continue
+		}
+		if !inst.IsRoutable() {
+			return fmt.Errorf("DrainInstance: instance %q is not routable (state %q)", id, inst.State)
+		}
+		NewDrainPolicy(DrainPolicyWait).Drain(inst, c)
+		logrus.Infof("[cluster] instance %s draining at tick %d", id, c.clock)
+		return nil
+	}
+	return fmt.Errorf("DrainInstance: unknown instance %q", id)
+}
+
 // AggregatedMetrics returns the merged metrics across all instances.
 // Panics if called before Run() has completed.
 func (c *ClusterSimulator) AggregatedMetrics() *sim.Metrics {
@@ -1374,6 +1740,27 @@ func (c *ClusterSimulator) RejectedRequests() int {
 	return c.rejectedRequests
 }
 
+// RetriedRequests returns the number of admission retries scheduled by the
+// retry model. Each retry is an extra admission attempt by an already-injected
+// request, so offered load at admission is injected arrivals + RetriedRequests.
+// Always 0 when RetryMaxAttempts is 0.
+func (c *ClusterSimulator) RetriedRequests() int {
+	return c.retriedRequests
+}
+
+// FailedRequests returns the number of in-flight requests lost when an injected
+// instance failure fired in FaultModeLose. Always 0 when fault injection is
+// disabled or in FaultModeRequeue.
+func (c *ClusterSimulator) FailedRequests() int {
+	return c.failedRequests
+}
+
+// RequeuedRequests returns the number of in-flight requests sent back through
+// routing when an injected instance failure fired in FaultModeRequeue.
+func (c *ClusterSimulator) RequeuedRequests() int {
+	return c.requeuedRequests
+}
+
 // RoutingRejections returns the count of requests rejected at routing because no
 // routable instances were available (I13). Distinct from admission rejections.
 func (c *ClusterSimulator) RoutingRejections() int {
@@ -1402,6 +1789,23 @@ func (c *ClusterSimulator) ShedByTier() map[string]int {
 	return result
 }
 
+// ArrivedRequests returns the number of requests that arrived at the cluster,
+// counted once each before any admission or routing decision. Requests
+// re-injected by the REDIRECT drain policy are not counted again. Every one
+// of them ends in exactly one INV-1 bucket.
+func (c *ClusterSimulator) ArrivedRequests() int {
+	return c.arrivedRequests
+}
+
+// PipelineRequests returns the number of requests that had arrived but not
+// yet reached an instance's wait queue when Run stopped at the horizon or
+// StopAfterCompleted: their admission, disaggregation or routing decision was
+// still pending, or the instance had not yet queued them. They sit in no
+// other INV-1 bucket. Zero for a run that drains.
+func (c *ClusterSimulator) PipelineRequests() int {
+	return c.pipelineRequests
+}
+
 // InjectedByClass returns a defensive copy of the per-SLOClass arrival counter.
 // Incremented in ClusterArrivalEvent.Execute before any drop/route/admission
 // decision; used as the goodput denominator (issue #1409, BC-5/BC-6).
@@ -1630,6 +2034,51 @@ func (c *ClusterSimulator) PerInstanceMetricsByID() map[string]*sim.Metrics {
 	return result
 }
 
+// InstanceKVState is one instance's final KV prefix index (--dump-kv-state).
+type InstanceKVState struct {
+	InstanceID string `json:"instance_id"`
+	kv.PrefixIndexState
+}
+
+// KVStateDump returns every instance's KV prefix index as left at the end of
+// the run (horizon or drain), in instance order. Instances whose KV store
+// cannot dump a prefix index are omitted.
+// Panics if called before Run() completes (R1).
+func (c *ClusterSimulator) KVStateDump() []InstanceKVState {
+	if !c.hasRun {
+		panic("ClusterSimulator.KVStateDump() called before Run()")
+	}
+	out := make([]InstanceKVState, 0, len(c.instances))
+	for _, inst := range c.instances {
+		if st, ok := inst.KVPrefixIndexState(); ok {
+			out = append(out, InstanceKVState{InstanceID: string(inst.ID()), PrefixIndexState: st})
+		}
+	}
+	return out
+}
+
+// InstanceKVPrefixes is one instance's exported prefix cache: the token
+// sequences of its fully cached prefixes, least recently used first
+// (sim.KVStore.ExportPrefixes). Fed back through DeploymentConfig.KVWarmState.
+type InstanceKVPrefixes struct {
+	InstanceID string          `json:"instance_id"`
+	Prefixes   [][]sim.TokenID `json:"prefixes"`
+}
+
+// ExportKVPrefixes returns every instance's cached prefixes as left at the
+// end of the run, in instance order, for warm-starting another run.
+// Panics if called before Run() completes (R1).
+func (c *ClusterSimulator) ExportKVPrefixes() []InstanceKVPrefixes {
+	if !c.hasRun {
+		panic("ClusterSimulator.ExportKVPrefixes() called before Run()")
+	}
+	out := make([]InstanceKVPrefixes, 0, len(c.instances))
+	for _, inst := range c.instances {
+		out = append(out, InstanceKVPrefixes{InstanceID: string(inst.ID()), Prefixes: inst.KVExportPrefixes()})
+	}
+	return out
+}
+
 // PeakConcurrentTransfers returns the maximum number of KV transfers in flight simultaneously.
 // Returns 0 when --pd-transfer-contention is disabled (backward-compat).
 func (c *ClusterSimulator) PeakConcurrentTransfers() int {
@@ -1699,6 +2148,8 @@ func (c *ClusterSimulator) aggregateMetrics() *sim.Metrics {
 		mergeFloat64Map(merged.RequestE2Es, m.RequestE2Es, "RequestE2Es")
 		mergeFloat64Map(merged.RequestITLs, m.RequestITLs, "RequestITLs")
 		mergeInt64Map(merged.RequestSchedulingDelays, m.RequestSchedulingDelays, "RequestSchedulingDelays")
+		mergeInt64Map(merged.RequestQueueWaits, m.RequestQueueWaits, "RequestQueueWaits")
+		mergeInt64Map(merged.RequestKVResidencies, m.RequestKVResidencies, "RequestKVResidencies")
 		mergeFloat64Map(merged.RequestCompletionTimes, m.RequestCompletionTimes, "RequestCompletionTimes")
 
 		for k, v := range m.Requests {
@@ -1721,11 +2172,48 @@ func (c *ClusterSimulator) aggregateMetrics() *sim.Metrics {
 			merged.AdapterEvictionCounts[k] += v
 		}
 		merged.PreemptionCount += m.PreemptionCount
+		merged.DecodePreemptionCount += m.DecodePreemptionCount
+		merged.SwapOutCount += m.SwapOutCount
+		merged.SwapTransferTicks += m.SwapTransferTicks
+		merged.WastedPrefillTokens += m.WastedPrefillTokens
+		merged.PriorityHOLBlockingEvents += m.PriorityHOLBlockingEvents
+		merged.BatchAffinitySwitchSteps += m.BatchAffinitySwitchSteps
+		merged.KVBlocksSavedBySharing += m.KVBlocksSavedBySharing
+		merged.KVRoundingWasteTokens += m.KVRoundingWasteTokens
+		merged.KVAllocations += m.KVAllocations
 		merged.KVAllocationFailures += m.KVAllocationFailures
+		merged.RemotePrefixFetchedBlocks += m.RemotePrefixFetchedBlocks
 		merged.DroppedUnservable += m.DroppedUnservable
+		for reason, n := range m.DroppedUnservableByReason {
+			if merged.DroppedUnservableByReason == nil {
+				merged.DroppedUnservableByReason = make(map[string]int)
+			}
+			merged.DroppedUnservableByReason[reason] += n
+		}
 		merged.LengthCappedRequests += m.LengthCappedRequests
 		merged.TimedOutRequests += m.TimedOutRequests
+		merged.QueueOverflowRejected += m.QueueOverflowRejected
+		merged.QueueOverflowDropped += m.QueueOverflowDropped
+		merged.StarvationPromotions += m.StarvationPromotions
+		merged.EnergyJoules += m.EnergyJoules
+		merged.PowerThrottledSteps += m.PowerThrottledSteps
+		merged.BatchSteps += m.BatchSteps
+		merged.BatchScheduledTokens += m.BatchScheduledTokens
+		merged.GPUBusyTicks += m.GPUBusyTicks
+		merged.StepBounds.Merge(m.StepBounds)
+		if m.KVSampleIntervalUs > 0 {
+			if merged.KVUsedSeriesByInstance == nil {
+				merged.KVUsedSeriesByInstance = make(map[string][]int64, len(c.instances))
+			}
+			merged.KVSampleIntervalUs = m.KVSampleIntervalUs
+			merged.KVUsedSeriesByInstance[string(inst.ID())] = m.KVUsedSeries
+		}
+		merged.KVTotalBlocks += m.KVTotalBlocks
+		merged.Roofline.Merge(m.Roofline)
+		merged.TimeBudget.Merge(m.TimeBudget)
 		merged.CacheHitRate += m.CacheHitRate
+		merged.CacheHitCountsByTenant = sim.MergeCacheHitCounts(merged.CacheHitCountsByTenant, m.CacheHitCountsByTenant)
+		merged.CacheHitCountsBySLOClass = sim.MergeCacheHitCounts(merged.CacheHitCountsBySLOClass, m.CacheHitCountsBySLOClass)
 		merged.KVThrashingRate += m.KVThrashingRate
 		merged.StillQueued += m.StillQueued
 		merged.StillRunning += m.StillRunning
@@ -1733,6 +2221,9 @@ func (c *ClusterSimulator) aggregateMetrics() *sim.Metrics {
 	if n := len(c.instances); n > 0 {
 		merged.CacheHitRate /= float64(n)
 		merged.KVThrashingRate /= float64(n)
+		// Idle time over the cluster's span, not each instance's own clock:
+		// an instance whose last event came early was idle until the end.
+		merged.GPUIdleTicks = max(0, int64(n)*merged.SimEndedTime-merged.GPUBusyTicks)
 	}
 
 	// T042: apply warm-up TTFT factor to requests served during warm-up (Phase 1A, R23).
@@ -1841,8 +2332,19 @@ func (c *ClusterSimulator) projectPDMetrics() {
 		if completed && hasPrefillDelay {
 			m.RequestSchedulingDelays[pid] = prefillDelay
 		}
+		// Queue wait likewise: the parent waited in the prefill pool's queue.
+		prefillWait, hasPrefillWait := m.RequestQueueWaits[pfx]
+		delete(m.RequestQueueWaits, pfx)
+		delete(m.RequestQueueWaits, dec)
+		if completed && hasPrefillWait {
+			m.RequestQueueWaits[pid] = prefillWait
+		}
 
 		// Requests metadata keyed by parent ID, HandledBy set to decode instance.
+		// The decode sub-request's entry carries the realized output length and
+		// completion reason (it is the one a server-side output cap truncates).
+		pfxRM := m.Requests[pfx]
+		decRM, hasDecRM := m.Requests[dec]
 		delete(m.Requests, pfx)
 		delete(m.Requests, dec)
 		if completed {
@@ -1851,6 +2353,13 @@ func (c *ClusterSimulator) projectPDMetrics() {
 			}
 			rm := sim.NewRequestMetrics(parent.OriginalRequest, float64(parent.ArrivalTime)/1e6)
 			rm.HandledBy = string(parent.DecodeInstanceID)
+			if hasDecRM {
+				rm.NumDecodeTokens = decRM.NumDecodeTokens
+				rm.CompletionReason = decRM.CompletionReason
+			}
+			// Preemptions on either side delayed the parent request.
+			rm.PreemptionCount = pfxRM.PreemptionCount + decRM.PreemptionCount
+			rm.TimeLostToPreemption = pfxRM.TimeLostToPreemption + decRM.TimeLostToPreemption
 			m.Requests[pid] = rm
 		}
 
@@ -1888,10 +2397,35 @@ func (cs *ClusterSimulator) executeStandardRouting(req *sim.Request, time int64)
 	if len(state.Snapshots) == 0 {
 		logrus.Warnf("[cluster] req %s: no routable instances for model %q — request rejected at routing (all instances may be Loading or Draining)", req.ID, req.Model)
 		cs.routingRejections++
+		if cs.inFlightTokens != nil {
+			cs.inFlightTokens.Release(req.ID)
+		}
 		return
 	}
 
+	// Replay routing: a request the log does not cover cannot be replayed.
+	// Reject it like an unroutable request and fail the run at the end.
+	if replay, ok := cs.routingPolicy.(*sim.ReplayRouting); ok {
+		if _, logged := replay.Lookup(req.ID); !logged {
+			logrus.Warnf("[cluster] req %s: not in the replay routing-decision log — request rejected at routing", req.ID)
+			cs.replayMissing = append(cs.replayMissing, req.ID)
+			cs.routingRejections++
+			if cs.inFlightTokens != nil {
+				cs.inFlightTokens.Release(req.ID)
+			}
+			return
+		}
+	}
+
 	decision := cs.routingPolicy.Route(req, state)
+	if decision.TargetInstance == "" {
+		logrus.Warnf("[cluster] req %s: routing policy chose no instance (%s) — request rejected at routing", req.ID, decision.Reason)
+		cs.routingRejections++
+		if cs.inFlightTokens != nil {
+			cs.inFlightTokens.Release(req.ID)
+		}
+		return
+	}
 	logrus.Debugf("[cluster] req %s → instance %s (reason=%s)", req.ID, decision.TargetInstance, decision.Reason)
 
 	// #181: Stamp request with assigned instance for per-request metrics
@@ -1932,6 +2466,7 @@ func (cs *ClusterSimulator) executeStandardRouting(req *sim.Request, time int64)
 				inst.RecordWarmUpRequest(req.ID)
 			}
 
+			cs.annotateRemotePrefixHit(req, inst)
 			inst.InjectRequestOnline(req, time)
 			// Track routed sheddable requests for in-flight eviction (BC-3).
 			if cs.evictionTracker != nil {
@@ -1967,6 +2502,11 @@ func (cs *ClusterSimulator) executeDisaggregatedRouting(req *sim.Request, time i
 		policy = cs.routingPolicy
 	}
 	decodeDecision := policy.Route(req, state)
+	if decodeDecision.TargetInstance == "" {
+		logrus.Warnf("[cluster] req %s: decode routing chose no instance (%s) — request rejected at routing", req.ID, decodeDecision.Reason)
+		cs.routingRejections++
+		return
+	}
 	logrus.Debugf("[cluster] req %s: decode pod pre-selected → %s", req.ID, decodeDecision.TargetInstance)
 
 	// Step 2: disaggregation decision with decode pod known. Pass the full decode-pool
@@ -2030,6 +2570,11 @@ func (cs *ClusterSimulator) executeDisaggregatedRouting(req *sim.Request, time i
 		// Encode routing uses the main routingPolicy in this PR; per-pool scorer
 		// config is a follow-up (design doc D6).
 		encodeDecision := cs.routingPolicy.Route(req, encodeState)
+		if encodeDecision.TargetInstance == "" {
+			logrus.Warnf("[cluster] req %s: encode routing chose no instance (%s) — request rejected at encode routing", req.ID, encodeDecision.Reason)
+			cs.encodeRoutingRejections++
+			return
+		}
 		encodeInstanceID = encodeDecision.TargetInstance
 		logrus.Debugf("[cluster] req %s: encode pod selected → %s", req.ID, encodeInstanceID)
 
@@ -2103,16 +2648,18 @@ func (cs *ClusterSimulator) executeDisaggregatedRouting(req *sim.Request, time i
 	// sub-request views the same underlying token buffer, no flatten. If
 	// Request.InputTokens ever becomes lazy/chained, this site must update.
 	prefillSubReq := &sim.Request{
-		ID:           parent.PrefillSubReqID,
-		InputTokens:  req.InputTokens,
-		MaxOutputLen: req.MaxOutputLen,
-		Deadline:     req.Deadline,
-		PrefixGroup:  req.PrefixGroup,
-		State:        sim.StateQueued,
-		ArrivalTime:  req.ArrivalTime,
-		TenantID:     req.TenantID,
-		SLOClass:     req.SLOClass,
-		Model:        req.Model,
+		ID:               parent.PrefillSubReqID,
+		InputTokens:      req.InputTokens,
+		MaxOutputLen:     req.MaxOutputLen,
+		Deadline:         req.Deadline,
+		PrefixGroup:      req.PrefixGroup,
+		State:            sim.StateQueued,
+		ArrivalTime:      req.ArrivalTime,
+		TenantID:         req.TenantID,
+		SLOClass:         req.SLOClass,
+		ExplicitPriority: req.ExplicitPriority,
+		Model:            req.Model,
+		NoCache:          req.NoCache,
 	}
 
 	heap.Push(&cs.clusterEvents, clusterEventEntry{
@@ -82,6 +82,7 @@ func buildRouterState(cs *ClusterSimulator, req *sim.Request) *sim.RouterState {
 		snap.Model = inst.Model
 		snap.GPUType = inst.GPU()
 		snap.TPDegree = inst.TPDegree
+		snap.StepTimeEstimate = inst.StepTimeEstimate()
 		snap.CostPerHour = inst.CostPerHour
 		snap.MaxBatchSize = float64(inst.MaxBatchSize()) // float64: QueueingModelAnalyzer uses it in float arithmetic
 		snapshots = append(snapshots, snap)
@@ -125,12 +126,15 @@ type ClusterArrivalEvent struct {
 func (e *ClusterArrivalEvent) Timestamp() int64 { return e.time }
 func (e *ClusterArrivalEvent) Priority() int     { return 0 }
 
-// Execute schedules an AdmissionDecisionEvent with the configured admission latency.
+// Execute schedules an AdmissionDecisionEvent after the admission latency (fixed, or sampled when jitter is enabled).
 // Records the request as injected on its SLO class BEFORE any admission/routing
 // decision so that drops and timeouts count against goodput (issue #1409, BC-5).
 func (e *ClusterArrivalEvent) Execute(cs *ClusterSimulator) {
 	cs.pendingArrivals--
 	cs.injectedByClass[e.request.SLOClass]++
+	if !e.request.Redirected {
+		cs.arrivedRequests++
+	}
 	logrus.Debugf("[cluster] req %s arrived at tick %d", e.request.ID, e.time)
 	// Fire the arrival hook (issue #1440): trace exporters see each fresh
 	// arrival exactly once, in clock-monotonic order (INV-3). REDIRECT
@@ -138,7 +142,7 @@ func (e *ClusterArrivalEvent) Execute(cs *ClusterSimulator) {
 	cs.fireArrivalHook(e.request, e.time)
 	heap.Push(&cs.clusterEvents, clusterEventEntry{
 		event: &AdmissionDecisionEvent{
-			time:    e.time + cs.admissionLatency,
+			time:    e.time + cs.admissionDelay(),
 			request: e.request,
 		},
 		seqID: cs.nextSeqID(),
@@ -158,8 +162,18 @@ func (e *AdmissionDecisionEvent) Priority() int     { return 1 }
 // Execute processes the admission decision for an incoming request.
 // Checks admission policy with full RouterState (BC-8: includes snapshots).
 // If admitted, schedules a RoutingDecisionEvent.
-// If rejected, increments cs.rejectedRequests counter (EC-2).
+// If rejected, either schedules an admission retry (when the retry model is
+// enabled and retries remain) or increments cs.rejectedRequests counter (EC-2).
 func (e *AdmissionDecisionEvent) Execute(cs *ClusterSimulator) {
+	// An input needing more KV blocks than any instance has can never be
+	// allocated: drop it as unservable now rather than route it to an instance
+	// that would drop it at enqueue (R19). It bypasses the admission policy, so
+	// no tracker reserves it and no retry is scheduled.
+	if !cs.inputFitsSomeInstance(e.request) {
+		cs.dropTooLarge(e.request)
+		return
+	}
+
 	state := buildRouterState(cs, e.request)
 	admitted, reason := cs.admissionPolicy.Admit(e.request, state)
 	logrus.Debugf("[cluster] req %s: admitted=%v reason=%q", e.request.ID, admitted, reason)
@@ -174,6 +188,11 @@ func (e *AdmissionDecisionEvent) Execute(cs *ClusterSimulator) {
 				Reason:    reason,
 			})
 		}
+		// Retry model: a rejected client may come back after a backoff.
+		// Only the final rejection counts toward rejectedRequests and shedByTier.
+		if cs.scheduleAdmissionRetry(e.request) {
+			return
+		}
 		cs.rejectedRequests++
 		// Populate per-tier shed counter for every admission rejection, regardless of policy.
 		tier := e.request.SLOClass
@@ -414,6 +433,11 @@ func (e *DisaggregationDecisionEvent) Execute(cs *ClusterSimulator) {
 		policy = cs.routingPolicy
 	}
 	decodeDecision := policy.Route(e.request, state)
+	if decodeDecision.TargetInstance == "" {
+		logrus.Warnf("[cluster] req %s: decode routing chose no instance (%s) — request rejected at routing", e.request.ID, decodeDecision.Reason)
+		cs.routingRejections++
+		return
+	}
 	logrus.Debugf("[cluster] req %s: decode pod pre-selected → %s", e.request.ID, decodeDecision.TargetInstance)
 
 	// Step 2: disaggregation decision with decode pod known.
@@ -504,6 +528,7 @@ func (e *DisaggregationDecisionEvent) Execute(cs *ClusterSimulator) {
 		TenantID:     e.request.TenantID,
 		SLOClass:     e.request.SLOClass,
 		Model:        e.request.Model,
+		NoCache:      e.request.NoCache,
 	}
 
 	heap.Push(&cs.clusterEvents, clusterEventEntry{
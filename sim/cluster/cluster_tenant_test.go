@@ -309,3 +309,40 @@ func TestTenantAdmission_PDMode_BudgetEnforced(t *testing.T) {
 		t.Errorf("PD mode: expected some requests to complete, got 0 completions out of %d", n)
 	}
 }
+
+// TestTenantBorrowAdmission_ClusterWiring verifies that tenant-borrow lets a
+// tenant fill idle capacity, then reserves the idle tenant's guarantee once it
+// becomes active, in a real cluster run with routing-time in-flight tracking.
+func TestTenantBorrowAdmission_ClusterWiring(t *testing.T) {
+	// GIVEN capacity 1×4 in-flight slots shared evenly by alice and bob
+	cfg := newTestDeploymentConfig(1)
+	cfg.BatchConfig = sim.NewBatchConfig(4, 2048, 0)
+	cfg.AdmissionPolicy = "tenant-borrow"
+	cfg.TenantShares = map[string]float64{"alice": 0.5, "bob": 0.5}
+
+	// WHEN alice bursts 6 long requests, then bob sends 2, then alice 1 more,
+	// all before anything completes
+	var reqs []*sim.Request
+	long := func(id string, at int64, tenant, class string) *sim.Request {
+		r := newTenantRequest(id, at, tenant, class)
+		r.OutputTokens = make([]sim.TokenID, 500)
+		return r
+	}
+	for i := 0; i < 6; i++ {
+		reqs = append(reqs, long(fmt.Sprintf("alice_%d", i), int64(i), "alice", "standard"))
+	}
+	reqs = append(reqs, long("bob_0", 10, "bob", "critical"), long("bob_1", 11, "bob", "critical"))
+	reqs = append(reqs, long("alice_6", 12, "alice", "standard"))
+	cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+	mustRun(t, cs)
+
+	// THEN alice borrows bob's idle share (4 admitted, 2 throttled), bob is
+	// admitted within its guarantee, and alice's late request is throttled
+	shed := cs.ShedByTier()
+	if shed["standard"] != 3 {
+		t.Errorf("alice rejections = %d, want 3 (2 beyond capacity + 1 after bob activated)", shed["standard"])
+	}
+	if shed["critical"] != 0 {
+		t.Errorf("bob rejections = %d, want 0 (within guarantee)", shed["critical"])
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/internal/hash"
 	"github.com/inference-sim/inference-sim/sim/internal/testutil"
 	"github.com/inference-sim/inference-sim/sim/workload"
 )
@@ -27,7 +28,7 @@ func newTestDeploymentConfig(numInstances int) DeploymentConfig {
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances:     numInstances,
 		CacheSignalDelay: DefaultCacheSignalDelay,
@@ -64,8 +65,8 @@ func TestDeploymentConfig_ToSimConfig_ReturnsEmbeddedSimConfig(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(500, 32, 0, 0, 0, 42),
 			BatchConfig:         sim.NewBatchConfig(128, 4096, 512),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1, 2, 3}, []float64{4, 5, 6}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 2, 1, false, "", "roofline", 0),
-			PolicyConfig:        sim.NewPolicyConfig("priority-fcfs", ""),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 2, 1, false, "", "roofline", 0, ""),
+			PolicyConfig:        sim.NewPolicyConfig("priority-fcfs", "", ""),
 		},
 		NumInstances:    3,
 		AdmissionPolicy: "token-bucket",
@@ -142,7 +143,7 @@ func TestClusterSimulator_SingleInstance_GoldenEquivalence(t *testing.T) {
 					KVCacheConfig:       sim.NewKVCacheConfig(tc.TotalKVBlocks, tc.BlockSizeInTokens, 0, 0, 0, 0),
 					BatchConfig:         sim.NewBatchConfig(tc.MaxNumRunningReqs, tc.MaxNumScheduledTokens, tc.LongPrefillTokenThreshold),
 					LatencyCoeffs:       sim.NewLatencyCoeffs(tc.BetaCoeffs, tc.AlphaCoeffs),
-					ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), tc.Model, tc.Hardware, tc.TP, 1, false, "", "roofline", 0),
+					ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), tc.Model, tc.Hardware, tc.TP, 1, false, "", "roofline", 0, ""),
 				},
 				NumInstances: 1,
 			}
@@ -192,7 +193,7 @@ func TestClusterSimulator_SingleInstance_GoldenInvariants(t *testing.T) {
 					KVCacheConfig:       sim.NewKVCacheConfig(tc.TotalKVBlocks, tc.BlockSizeInTokens, 0, 0, 0, 0),
 					BatchConfig:         sim.NewBatchConfig(tc.MaxNumRunningReqs, tc.MaxNumScheduledTokens, tc.LongPrefillTokenThreshold),
 					LatencyCoeffs:       sim.NewLatencyCoeffs(tc.BetaCoeffs, tc.AlphaCoeffs),
-					ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), tc.Model, tc.Hardware, tc.TP, 1, false, "", "roofline", 0),
+					ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), tc.Model, tc.Hardware, tc.TP, 1, false, "", "roofline", 0, ""),
 				},
 				NumInstances: 1,
 			}
@@ -1569,7 +1570,7 @@ func TestClusterSimulator_MaxModelLen_DroppedUnservable(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{0, 0, 0}), // zero alpha
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", maxModelLen),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", maxModelLen, ""),
 		},
 		NumInstances: 2,
 	}
@@ -1807,6 +1808,35 @@ func TestClusterSimulator_FlowControl_Conservation(t *testing.T) {
 	// that this configuration does not guarantee.
 }
 
+// TestClusterSimulator_ArrivedRequests_ConservedAtHorizon verifies INV-1
+// against the cluster's own arrival count when the horizon cuts requests off
+// between arrival and an instance's wait queue: PipelineRequests holds them.
+func TestClusterSimulator_ArrivedRequests_ConservedAtHorizon(t *testing.T) {
+	sawPipeline := false
+	for horizon := int64(100_000); horizon < 1_000_000; horizon += 37_003 {
+		config := newTestDeploymentConfig(2)
+		config.Horizon = horizon
+		config.AdmissionLatency = 3000
+		config.RoutingLatency = 2000
+		requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, 60,
+			0, 200, 20, 100, 300, 200, 20, 100, 300)
+		cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+		mustRun(t, cs)
+
+		m := cs.AggregatedMetrics()
+		accounted := m.CompletedRequests + m.StillQueued + m.StillRunning + m.DroppedUnservable + m.TimedOutRequests +
+			cs.RejectedRequests() + cs.RoutingRejections() + cs.PipelineRequests()
+		if cs.ArrivedRequests() != accounted {
+			t.Errorf("horizon %d: INV-1: arrived=%d != accounted=%d (completed=%d queued=%d running=%d pipeline=%d)",
+				horizon, cs.ArrivedRequests(), accounted, m.CompletedRequests, m.StillQueued, m.StillRunning, cs.PipelineRequests())
+		}
+		sawPipeline = sawPipeline || cs.PipelineRequests() > 0
+	}
+	if !sawPipeline {
+		t.Error("no horizon stopped a request in the pipeline; the test does not exercise PipelineRequests")
+	}
+}
+
 // TestClusterSimulator_FlowControl_Accessors_BeforeRun verifies zero values
 // when flow control is disabled or before run.
 func TestClusterSimulator_FlowControl_Accessors_Disabled(t *testing.T) {
@@ -1840,7 +1870,7 @@ func TestNewClusterSimulator_UsesPoolGPUType(t *testing.T) {
 	sharedConfig := sim.SimConfig{
 		Horizon:             1_000_000,
 		Seed:                42,
-		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 		KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(4, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
@@ -1970,7 +2000,7 @@ func TestNewClusterSimulator_RooflineUsesPoolHWConfig(t *testing.T) {
 	baseSimCfg := sim.SimConfig{
 		Horizon:             1_000_000, // 1 second
 		Seed:                42,
-		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastH100, "test-model", "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastH100, "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 		KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(8, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs(nil, []float64{0, 0, 0}),
@@ -2039,7 +2069,7 @@ func TestNodeReadyEvent_RooflineUsesPoolHWConfig(t *testing.T) {
 	baseSimCfg := sim.SimConfig{
 		Horizon:             1_000_000,
 		Seed:                42,
-		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastH100, "test-model", "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastH100, "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 		KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(8, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs(nil, []float64{0, 0, 0}),
@@ -2131,7 +2161,7 @@ func TestClusterRun_E2E_NodePoolsHWConfig_TTFTReflectsPoolHardware(t *testing.T)
 	baseSimCfg := sim.SimConfig{
 		Horizon:             horizon,
 		Seed:                42,
-		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastGPU, "test-model", "fast-gpu", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(mc, fastGPU, "test-model", "fast-gpu", 1, 1, false, "", "roofline", 0, ""),
 		KVCacheConfig:       sim.NewKVCacheConfig(200, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(8, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs(nil, []float64{0, 0, 0}),
@@ -2196,7 +2226,7 @@ func TestNodeReadyEvent_DeferredConstruction_UsesPoolGPUType(t *testing.T) {
 		SimConfig: sim.SimConfig{
 			Horizon:             1_000_000,
 			Seed:                42,
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(4, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
@@ -2754,7 +2784,7 @@ func TestClusterSimulator_OrphanedTimeout_DoesNotInflateSimEndedTime(t *testing.
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{0, 0, 0}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances: 2,
 	}
@@ -2820,7 +2850,7 @@ func TestClusterSimulator_MixedOrphanedAndGenuineTimeout_CorrectMetrics(t *testi
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(1, 2048, 0), // max 1 running — forces queuing
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{0, 0, 0}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances: 1,
 	}
@@ -3213,3 +3243,180 @@ func TestClusterSimulator_FlowControl_Eviction_PD(t *testing.T) {
 
 	t.Logf("PD+FC results: completed=%d gwEvicted=%d", m.CompletedRequests, gwEvicted)
 }
+
+// TestClusterSimulator_StaticWeightedRouting_SplitsByInstanceWeights verifies
+// RoutingInstanceWeights reach the router keyed by instance index: with weights
+// [3, 1, 0], instance_0 serves ~75% of requests and instance_2 none.
+func TestClusterSimulator_StaticWeightedRouting_SplitsByInstanceWeights(t *testing.T) {
+	config := newTestDeploymentConfig(3)
+	config.RoutingPolicy = "static-weighted"
+	config.RoutingInstanceWeights = []float64{3, 1, 0}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(400)), nil)
+	mustRun(t, cs)
+
+	completed := make(map[string]int)
+	for id, m := range cs.PerInstanceMetricsByID() {
+		completed[id] = m.CompletedRequests
+	}
+	if completed["instance_2"] != 0 {
+		t.Errorf("zero-weight instance_2 completed %d requests, want 0", completed["instance_2"])
+	}
+	if total := completed["instance_0"] + completed["instance_1"]; total != 400 {
+		t.Fatalf("completed %v, want 400 in total", completed)
+	}
+	if share := float64(completed["instance_0"]) / 400; share < 0.68 || share > 0.82 {
+		t.Errorf("instance_0 share = %.3f, want ~0.75 (completed %v)", share, completed)
+	}
+}
+
+// TestClusterSimulator_StaticWeightedRouting_WeightedInstanceDown_RejectsAtRouting
+// verifies that once the only positively weighted instance fails, arrivals are
+// rejected at routing rather than sent to the zero-weight instance.
+func TestClusterSimulator_StaticWeightedRouting_WeightedInstanceDown_RejectsAtRouting(t *testing.T) {
+	config := newTestDeploymentConfig(2)
+	config.RoutingPolicy = "static-weighted"
+	config.RoutingInstanceWeights = []float64{1, 0}
+	config.FaultInjection = FaultInjectionConfig{InstanceID: "instance_0", AtUs: 10_000_000}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(200)), nil)
+	mustRun(t, cs)
+
+	if got := cs.PerInstanceMetricsByID()["instance_1"].CompletedRequests; got != 0 {
+		t.Errorf("zero-weight instance_1 completed %d requests, want 0", got)
+	}
+	if cs.RoutingRejections() == 0 {
+		t.Fatal("no routing rejections after the weighted instance failed")
+	}
+	m := cs.AggregatedMetrics()
+	if accounted := m.CompletedRequests + cs.FailedRequests() + cs.RoutingRejections(); accounted != 200 {
+		t.Errorf("INV-1: completed %d + failed %d + routing rejections %d = %d, want 200",
+			m.CompletedRequests, cs.FailedRequests(), cs.RoutingRejections(), accounted)
+	}
+}
+
+// TestClusterSimulator_ThroughputSampleInterval_PerInstanceAndClusterSeries verifies
+// that with sampling enabled every instance and the cluster aggregate carry a
+// cumulative completed-request series that never decreases and ends at that
+// level's CompletedRequests.
+func TestClusterSimulator_ThroughputSampleInterval_PerInstanceAndClusterSeries(t *testing.T) {
+	// GIVEN a 3-instance cluster with a steady workload sampled every 500ms
+	config := newTestDeploymentConfig(3)
+	config.ThroughputSampleIntervalUs = 500_000
+	cs := NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(60)), nil)
+
+	// WHEN the simulation runs
+	mustRun(t, cs)
+
+	// THEN every level's series is cumulative and ends at its CompletedRequests
+	check := func(name string, m *sim.Metrics) {
+		t.Helper()
+		interval := m.ThroughputSampleIntervalUs
+		if interval != 500_000 {
+			t.Fatalf("%s: ThroughputSampleIntervalUs = %d, want 500000", name, interval)
+		}
+		if want := int((m.SimEndedTime + interval - 1) / interval); len(m.CompletedSeries) != want {
+			t.Fatalf("%s: len(CompletedSeries) = %d, want %d", name, len(m.CompletedSeries), want)
+		}
+		for k := 1; k < len(m.CompletedSeries); k++ {
+			if m.CompletedSeries[k] < m.CompletedSeries[k-1] {
+				t.Errorf("%s: CompletedSeries decreases at sample %d: %d -> %d", name, k, m.CompletedSeries[k-1], m.CompletedSeries[k])
+			}
+		}
+		if last := m.CompletedSeries[len(m.CompletedSeries)-1]; last != m.CompletedRequests {
+			t.Errorf("%s: final CompletedSeries = %d, want CompletedRequests %d", name, last, m.CompletedRequests)
+		}
+	}
+	for i, m := range cs.PerInstanceMetrics() {
+		check(fmt.Sprintf("instance_%d", i), m)
+	}
+	agg := cs.AggregatedMetrics()
+	if agg.CompletedRequests != 60 {
+		t.Fatalf("aggregate CompletedRequests = %d, want 60", agg.CompletedRequests)
+	}
+	check("cluster", agg)
+}
+
+// TestClusterSimulator_KVStateDump_ContainsRepeatedPrefix verifies that the
+// final KV dump lists every block of a prefix shared by repeated requests, with
+// block counts matching its position in the hash chain.
+func TestClusterSimulator_KVStateDump_ContainsRepeatedPrefix(t *testing.T) {
+	const (
+		prefixTokens = 64 // 4 blocks at BlockSizeTokens=16
+		suffixTokens = 20 // 1 full + 1 partial block per request
+		numRequests  = 4
+	)
+	// GIVEN one instance serving 4 requests that share a 64-token prefix, 1s apart
+	config := newTestDeploymentConfig(1)
+	prefix := make([]sim.TokenID, prefixTokens)
+	for i := range prefix {
+		prefix[i] = sim.TokenID(i + 1)
+	}
+	requests := make([]*sim.Request, numRequests)
+	for i := range requests {
+		input := append(append([]sim.TokenID{}, prefix...), make([]sim.TokenID, suffixTokens)...)
+		for j := prefixTokens; j < len(input); j++ {
+			input[j] = sim.TokenID(10_000*(i+1) + j)
+		}
+		requests[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 1_000_000,
+			InputTokens:  input,
+			OutputTokens: make([]sim.TokenID, 8),
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	// WHEN the final KV state is dumped
+	dump := cs.KVStateDump()
+	if len(dump) != 1 {
+		t.Fatalf("KVStateDump returned %d instances, want 1", len(dump))
+	}
+	st := dump[0]
+	if st.InstanceID != "instance_0" {
+		t.Errorf("InstanceID = %q, want instance_0", st.InstanceID)
+	}
+
+	// THEN every prefix block is indexed once, at its depth in the chain
+	byHash := make(map[string]int, len(st.Entries))
+	for i, e := range st.Entries {
+		byHash[e.Hash] = i
+	}
+	prevHash := ""
+	lastArrival := requests[numRequests-1].ArrivalTime
+	for d := int64(1); d <= prefixTokens/16; d++ {
+		h := hash.HashBlock(prevHash, prefix[(d-1)*16:d*16])
+		prevHash = h
+		i, ok := byHash[h]
+		if !ok {
+			t.Fatalf("prefix block %d (hash %s) missing from dump", d, h)
+		}
+		e := st.Entries[i]
+		if e.PrefixBlocks != d {
+			t.Errorf("prefix block %d: PrefixBlocks = %d, want %d", d, e.PrefixBlocks, d)
+		}
+		if e.LastAccessTick < lastArrival {
+			t.Errorf("prefix block %d: LastAccessTick = %d, want >= %d (hit by the last request)", d, e.LastAccessTick, lastArrival)
+		}
+		if e.InUse || e.RefCount != 0 {
+			t.Errorf("prefix block %d: InUse=%v RefCount=%d after drain, want free", d, e.InUse, e.RefCount)
+		}
+	}
+
+	// AND each request adds exactly one unique full block after the prefix
+	var suffixEntries int
+	for _, e := range st.Entries {
+		if e.PrefixBlocks == prefixTokens/16+1 {
+			suffixEntries++
+		}
+	}
+	if suffixEntries != numRequests {
+		t.Errorf("depth-5 entries = %d, want %d (one per request's unique suffix)", suffixEntries, numRequests)
+	}
+	if want := int64(prefixTokens/16 + numRequests); st.CachedBlocks != want {
+		t.Errorf("CachedBlocks = %d, want %d", st.CachedBlocks, want)
+	}
+	if st.UsedBlocks != 0 {
+		t.Errorf("UsedBlocks = %d after drain, want 0", st.UsedBlocks)
+	}
+}
@@ -16,7 +16,7 @@ func TestClusterSimulator_TraceLevelNone_NilTrace(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances: 2,
 		TraceLevel:   "none",
@@ -43,7 +43,7 @@ func TestClusterSimulator_TraceLevelDecisions_RecordsAllEvents(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances:    2,
 		TraceLevel:      "decisions",
@@ -85,7 +85,7 @@ func TestClusterSimulator_TraceLevelDecisions_WithCounterfactual(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances:         2,
 		RoutingPolicy:        "weighted",
@@ -115,6 +115,56 @@ func TestClusterSimulator_TraceLevelDecisions_WithCounterfactual(t *testing.T) {
 	}
 }
 
+func TestClusterSimulator_TraceLevelDecisions_RecordsAllCandidateScores(t *testing.T) {
+	// GIVEN weighted routing over 3 instances with counterfactual top-k disabled
+	config := DeploymentConfig{
+		SimConfig: sim.SimConfig{
+			Horizon:             10000000,
+			Seed:                42,
+			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
+			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
+			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		},
+		NumInstances:         3,
+		RoutingPolicy:        "weighted",
+		RoutingScorerConfigs: sim.DefaultScorerConfigs(),
+		TraceLevel:           "decisions",
+		CounterfactualK:      0,
+	}
+	requests := testGenerateRequests(42, 10000000, 1.0/1e6, 6,
+		0, 10, 0, 10, 10, 5, 0, 5, 5)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+
+	// WHEN run
+	mustRun(t, cs)
+
+	// THEN every routing record scores all 3 candidates and the chosen one has the max
+	tr := cs.Trace()
+	if tr == nil {
+		t.Fatal("expected non-nil trace")
+	}
+	if len(tr.Routings) != 6 {
+		t.Fatalf("expected 6 routing records, got %d", len(tr.Routings))
+	}
+	for i, r := range tr.Routings {
+		if len(r.Scores) != 3 {
+			t.Errorf("routing[%d]: expected 3 candidate scores, got %d", i, len(r.Scores))
+			continue
+		}
+		chosen, ok := r.Scores[r.ChosenInstance]
+		if !ok {
+			t.Errorf("routing[%d]: chosen instance %q has no score", i, r.ChosenInstance)
+			continue
+		}
+		for id, score := range r.Scores {
+			if score > chosen {
+				t.Errorf("routing[%d]: %s scored %.3f above chosen %s (%.3f)", i, id, score, r.ChosenInstance, chosen)
+			}
+		}
+	}
+}
+
 func TestClusterSimulator_TraceWithTokenBucket_RecordsRejections(t *testing.T) {
 	// GIVEN token bucket admission that rejects some requests
 	config := DeploymentConfig{
@@ -124,7 +174,7 @@ func TestClusterSimulator_TraceWithTokenBucket_RecordsRejections(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(100, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances:          1,
 		AdmissionPolicy:       "token-bucket",
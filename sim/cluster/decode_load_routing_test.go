@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// runVariableOutputRouting runs 200 requests whose output lengths vary 10×–100×
+// across 4 instances under the given routing policy and returns the standard
+// deviation of per-instance TotalOutputTokens.
+func runVariableOutputRouting(t *testing.T, policy string) float64 {
+	t.Helper()
+	config := newTestDeploymentConfig(4)
+	config.RoutingPolicy = policy
+	outputs := []int{1000, 20, 300, 10, 20, 600, 10, 40}
+	requests := make([]*sim.Request, 200)
+	for i := range requests {
+		requests[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 20_000,
+			InputTokens:  make([]sim.TokenID, 64),
+			OutputTokens: make([]sim.TokenID, outputs[i%len(outputs)]),
+			MaxOutputLen: outputs[i%len(outputs)],
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	perInstance := cs.PerInstanceMetricsByID()
+	tokens := make([]float64, 0, len(perInstance))
+	for _, m := range perInstance {
+		tokens = append(tokens, float64(m.TotalOutputTokens))
+	}
+	var mean, sq float64
+	for _, v := range tokens {
+		mean += v / float64(len(tokens))
+	}
+	for _, v := range tokens {
+		sq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sq / float64(len(tokens)))
+}
+
+// TestDecodeLoadRouting_BalancesOutputTokensBetterThanLeastLoaded verifies that
+// routing by remaining decode tokens spreads decode work more evenly than
+// routing by request count when output lengths vary widely.
+func TestDecodeLoadRouting_BalancesOutputTokensBetterThanLeastLoaded(t *testing.T) {
+	// GIVEN a repeating mix of very long and very short outputs
+	// WHEN routed by request count vs by remaining decode tokens
+	countStd := runVariableOutputRouting(t, "least-loaded")
+	decodeStd := runVariableOutputRouting(t, "decode-load")
+
+	// THEN decode-load yields a markedly lower spread of per-instance output tokens
+	if decodeStd*2 > countStd {
+		t.Errorf("stddev of per-instance TotalOutputTokens: decode-load=%.1f least-loaded=%.1f, want decode-load at most half", decodeStd, countStd)
+	}
+}
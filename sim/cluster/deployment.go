@@ -1,6 +1,11 @@
 package cluster
 
-import "github.com/inference-sim/inference-sim/sim"
+import (
+	"fmt"
+	"math"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
 
 // DefaultCacheSignalDelay is the default propagation delay for prefix cache
 // signals in microseconds (50ms). Only affects precise-prefix-cache and
@@ -9,8 +14,9 @@ import "github.com/inference-sim/inference-sim/sim"
 // Set to 0 for oracle mode (live cache state).
 const DefaultCacheSignalDelay int64 = 50_000
 
-// DeploymentConfig describes a cluster where all instances share identical
-// hardware and model configuration. NumInstances must be >= 1.
+// DeploymentConfig describes a cluster of instances serving one model.
+// All instances share the embedded SimConfig unless pool overrides (PD
+// disaggregation) or InstanceOverrides differentiate them. NumInstances must be >= 1.
 type DeploymentConfig struct {
 	sim.SimConfig // Embeds all instance-level config (horizon, seed, KV, batch, latency, policy)
 
@@ -23,9 +29,63 @@ type DeploymentConfig struct {
 	TokenBucketCapacity   float64 // max tokens, default 10000
 	TokenBucketRefillRate float64 // tokens/second, default 1000
 
+	// Admission retry model. When RetryMaxAttempts is 0 (default), admission
+	// rejection is final and the pipeline is unchanged (INV-6). When > 0, a
+	// rejected request re-enters admission after a jittered exponential backoff
+	// (RetryBackoffUs * 2^(attempt-1), scaled by a uniform factor in [0.5, 1.5))
+	// until it is admitted or exhausts its retries.
+	RetryMaxAttempts int   // max re-submissions per rejected request (0 = no retries)
+	RetryBackoffUs   int64 // base backoff in microseconds for the first retry
+
+	// Cluster-wide cap on in-flight tokens (input + output budget, summed over
+	// admitted, unfinished requests; see InFlightTokenTracker for why decode
+	// progress is not credited back). Arrivals that would exceed it are
+	// rejected at admission ("inflight-token-cap"), or delayed when the retry
+	// model is on. 0 = no cap (INV-6). Not supported with flow control or PD.
+	MaxInFlightTokens int64
+
+	// Admission latency jitter. When AdmissionLatencyDist is "" (default), every
+	// request waits exactly AdmissionLatency before its admission decision (INV-6).
+	// Otherwise each arrival (and admission retry) samples its own latency with
+	// mean AdmissionLatency: "gaussian" uses AdmissionLatencyStdDevUs and clamps
+	// negative samples to 0, which raises the realized mean once the stddev nears
+	// the mean; "lognormal" matches both the mean and AdmissionLatencyStdDevUs
+	// without clamping; "exponential" ignores the stddev (it equals the mean).
+	// Samples come from a dedicated RNG partition, so runs stay reproducible.
+	AdmissionLatencyDist     string
+	AdmissionLatencyStdDevUs float64
+
 	// Routing policy configuration (PR6, evolved in PR17)
-	RoutingPolicy        string             // "round-robin" (default), "least-loaded", "weighted", "always-busiest"
+	RoutingPolicy        string             // "round-robin" (default), "least-loaded", "decode-load", "weighted", "always-busiest", "static-weighted", "session-affinity", "replay"
 	RoutingScorerConfigs []sim.ScorerConfig // for weighted routing scorer pipeline (nil = use defaults)
+	// RoutingInstanceWeights are the fixed weights of "static-weighted" routing,
+	// indexed by instance (weights[i] applies to instance_i); instances beyond
+	// the slice get weight 0. Used by the main and per-pool routers alike.
+	RoutingInstanceWeights []float64
+	// RoutingReplayLog is the routing-decision log of "replay" routing: request
+	// ID -> instance ID, e.g. trace.SimulationTrace.RoutingLog from an earlier
+	// run. Requests missing from it are rejected at routing and make Run return
+	// an error. Main router only; nil unless the policy is "replay".
+	RoutingReplayLog map[string]string
+	// RoutingTieBreak selects how routing policies pick among instances with an
+	// equal best score or load (least-loaded, decode-load, weighted and the
+	// session-affinity fallback): "" or "random" draws from the seeded router
+	// RNG (default, INV-6); "lowest-index" takes the first tied instance in
+	// snapshot order, which is instance order, so the choice does not depend
+	// on the RNG stream. Applies to the main and per-pool routers.
+	RoutingTieBreak string
+	// PrefixHashSkipTokens is the number of leading input tokens the
+	// prefix-affinity scorer leaves out of its block hashes, so requests that
+	// share a long system prompt are keyed on their user-specific suffix.
+	// Router-side only: the KV cache still hashes the full prompt. 0 = disabled.
+	PrefixHashSkipTokens int64
+
+	// IngressClockOffsetsUs maps an ingress point (Request.IngressPoint) to
+	// the skew of its clock in µs. Each source arrival tagged with a listed
+	// point has its ArrivalTime shifted by the offset (clamped at 0) before it
+	// enters the cluster, modeling multi-region ingress whose timestamps
+	// disagree. Untagged and unlisted requests are unshifted; nil = disabled.
+	IngressClockOffsetsUs map[string]int64
 
 	// Decision trace configuration (PR13)
 	TraceLevel      string // "none" (default), "decisions"
@@ -55,6 +115,19 @@ type DeploymentConfig struct {
 	// Zero value is safe: no loading delay, no warm-up, WAIT drain policy.
 	InstanceLifecycle InstanceLifecycleConfig
 
+	// Deterministic instance failure for resilience studies.
+	// Zero value is safe: no failure is injected. Not supported with PD disaggregation.
+	FaultInjection FaultInjectionConfig
+
+	// Shared prefix cache: instances share a distributed KV store. A cluster-wide
+	// prefix index tracks which blocks each instance has been routed; a request
+	// routed to an instance with a shorter local hit fetches the remaining prefix
+	// blocks from the remote store at SimConfig.RemotePrefixFetchUsPerBlock each
+	// instead of recomputing them. The owner's cache is re-checked when the fetch
+	// is charged, so blocks it has evicted are recomputed, not fetched.
+	// false = disabled. Not supported with PD disaggregation.
+	SharedPrefixCache bool
+
 	// PD disaggregation configuration (PR1)
 	// When both PrefillInstances and DecodeInstances are 0, disaggregation is disabled
 	// and the pipeline is unchanged (BC-PD-1).
@@ -81,11 +154,23 @@ type DeploymentConfig struct {
 	PDTransferBaseLatencyMs float64 // Inter-instance KV transfer base latency in ms (default 0.05)
 	PDTransferContention    bool    // Enable fair-share bandwidth contention model (--pd-transfer-contention, INV-P2-2)
 
+	// KVOffloadHostContention makes CPU↔GPU KV transfers of instances on the same
+	// node share host-memory bandwidth (--kv-offload-host-contention). Only affects
+	// tiered KV caches (--kv-cpu-blocks > 0). False = un-contended (default).
+	KVOffloadHostContention bool
+
 	// Per-pool routing scorer configuration (PR2)
 	// When nil, both pools use the main RoutingScorerConfigs.
 	PrefillScorerConfigs []sim.ScorerConfig // Scorer configs for prefill pool routing
 	DecodeScorerConfigs  []sim.ScorerConfig // Scorer configs for decode pool routing
 
+	// Per-pool routing policies. When empty, a pool uses "weighted" if its scorer
+	// configs are set and the main routing policy otherwise (pre-existing behavior).
+	// When set, the pool routes with the named policy; a "weighted" pool policy
+	// without pool scorer configs falls back to RoutingScorerConfigs.
+	PrefillRoutingPolicy string // Routing policy for the prefill pool (e.g. "weighted" with prefix-affinity)
+	DecodeRoutingPolicy  string // Routing policy for the decode pool (e.g. "least-loaded")
+
 	// Per-pool hardware overrides
 	// When empty (all nil/zero), all instances use the global SimConfig (BC-P2-1).
 	PrefillOverrides PoolOverrides // Hardware overrides for prefill pool instances
@@ -112,6 +197,15 @@ type DeploymentConfig struct {
 	GAIEQDThreshold float64 // queue depth threshold per instance (default 5)
 	GAIEKVThreshold float64 // KV cache utilization threshold (default 0.8)
 
+	// Per-SLO-class token buckets. Only used when AdmissionPolicy = "slo-token-bucket";
+	// classes without an entry get their own bucket sized by TokenBucketCapacity/RefillRate.
+	SLOTokenBuckets map[string]sim.SLOTokenBucketConfig
+
+	// Per-tenant guaranteed shares of cluster in-flight capacity
+	// (NumInstances × MaxRunningReqs). Only used when AdmissionPolicy = "tenant-borrow";
+	// tenants without an entry have no guarantee and can only borrow idle capacity.
+	TenantShares map[string]float64
+
 	// Phase 1B-2a: per-tenant fair-share budgets (issue #811).
 	// Key: TenantID string. Value: fraction of total cluster capacity (0.0–1.0).
 	// Zero value is safe: nil = no enforcement (all tenants unlimited).
@@ -144,6 +238,56 @@ type DeploymentConfig struct {
 	// (TFlopsPeak, BwPeakTBs) rather than the CLI --gpu calibration.
 	// Zero value (nil) is safe: no override, backward-compatible with all existing callers.
 	HWConfigByGPU map[string]sim.HardwareCalib `yaml:"hw_config_by_gpu,omitempty"`
+
+	// Per-instance hardware overrides for heterogeneous clusters (mixed GPU types / TP).
+	// InstanceOverrides[i] applies to instance_i on top of its pool-resolved SimConfig;
+	// instances beyond len(InstanceOverrides) use the pool/global config unchanged.
+	// Zero value (nil) is safe: all instances share one SimConfig (backward-compatible).
+	// Under NodePools, the placed pool's gpu_type remains authoritative for GPU (SC-004).
+	InstanceOverrides []InstanceOverride `yaml:"instance_overrides,omitempty"`
+
+	// KV warm start from a prior run's ClusterSimulator.ExportKVPrefixes.
+	// KVWarmState[i] becomes instance_i's SimConfig.KVWarmPrefixes; instances
+	// beyond len(KVWarmState) start cold. Matched by position, not instance
+	// ID. Zero value (nil) is safe: every instance starts cold.
+	KVWarmState []InstanceKVPrefixes `yaml:"-"`
+}
+
+// InstanceOverride holds optional hardware overrides for a single instance.
+// Embeds PoolOverrides (TP, GPU, LatencyBackend, MaxModelLen, TotalKVBlocks) and adds
+// per-instance latency coefficients, which pools share with the global config.
+// When GPU is set and HWConfigByGPU has an entry for it, that HardwareCalib replaces
+// HWConfig so roofline and trained-physics backends see the instance's own hardware.
+type InstanceOverride struct {
+	PoolOverrides
+	LatencyCoeffs *sim.LatencyCoeffs // alpha/beta coefficients (nil = use global)
+}
+
+// Validate checks that non-nil fields satisfy their constraints (R3).
+// name is used in error messages (e.g., "instance_1").
+func (o InstanceOverride) Validate(name string) error {
+	if err := o.PoolOverrides.Validate(name); err != nil {
+		return err
+	}
+	if o.LatencyCoeffs == nil {
+		return nil
+	}
+	for _, c := range o.LatencyCoeffs.BetaCoeffs {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return fmt.Errorf("%s: InstanceOverride.LatencyCoeffs.BetaCoeffs must be finite, got %v", name, c)
+		}
+	}
+	for _, c := range o.LatencyCoeffs.AlphaCoeffs {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return fmt.Errorf("%s: InstanceOverride.LatencyCoeffs.AlphaCoeffs must be finite, got %v", name, c)
+		}
+	}
+	return nil
+}
+
+// IsEmpty returns true when no overrides are set.
+func (o InstanceOverride) IsEmpty() bool {
+	return o.PoolOverrides.IsEmpty() && o.LatencyCoeffs == nil
 }
 
 // ToSimConfig returns the embedded SimConfig for per-instance construction.
@@ -189,3 +333,36 @@ func (d DeploymentConfig) resolveConfigForRole(role PoolRole) sim.SimConfig {
 		return d.SimConfig
 	}
 }
+
+// instanceOverride returns the override for instance index idx and whether one is set.
+func (d DeploymentConfig) instanceOverride(idx int) (InstanceOverride, bool) {
+	if idx < 0 || idx >= len(d.InstanceOverrides) || d.InstanceOverrides[idx].IsEmpty() {
+		return InstanceOverride{}, false
+	}
+	return d.InstanceOverrides[idx], true
+}
+
+// resolveConfigForInstance returns the SimConfig for instance index idx: the
+// pool-resolved config for role with KVWarmState[idx] and InstanceOverrides[idx]
+// applied on top.
+// The global SimConfig is never mutated.
+func (d DeploymentConfig) resolveConfigForInstance(idx int, role PoolRole) sim.SimConfig {
+	cfg := d.resolveConfigForRole(role)
+	if idx >= 0 && idx < len(d.KVWarmState) {
+		cfg.KVWarmPrefixes = d.KVWarmState[idx].Prefixes
+	}
+	o, ok := d.instanceOverride(idx)
+	if !ok {
+		return cfg
+	}
+	cfg = ResolvePoolConfig(cfg, o.PoolOverrides)
+	if o.LatencyCoeffs != nil {
+		cfg.LatencyCoeffs = *o.LatencyCoeffs
+	}
+	if o.GPU != "" {
+		if hc, found := d.HWConfigByGPU[o.GPU]; found {
+			cfg.HWConfig = hc
+		}
+	}
+	return cfg
+}
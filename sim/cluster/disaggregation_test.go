@@ -75,7 +75,7 @@ func newTestDisaggDeploymentConfigWithOverhead(overhead float64) DeploymentConfi
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs(betas, alphas),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(modelCfg, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(modelCfg, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 0, ""),
 		},
 		NumInstances:            4,
 		PrefillInstances:        2,
@@ -113,7 +113,7 @@ func newTestDisaggDeploymentConfig(numInstances, prefill, decode int) Deployment
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs(betas, alphas),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(modelCfg, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(modelCfg, hwCfg, "test-model", "H100", 1, 1, false, "", "trained-physics", 0, ""),
 		},
 		NumInstances:            numInstances,
 		PrefillInstances:        prefill,
@@ -129,7 +129,7 @@ func TestNewClusterSimulator_PDEnabled_InvalidModelConfig_Panics(t *testing.T) {
 	cfg := newTestDisaggDeploymentConfig(2, 1, 1)
 	// Replace the valid ModelConfig with a zero-value one to trigger the PD guard.
 	// PD mode requires valid ModelConfig for KV transfer size calculation.
-	cfg.ModelHardwareConfig = sim.NewModelHardwareConfig(sim.ModelConfig{}, testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0)
+	cfg.ModelHardwareConfig = sim.NewModelHardwareConfig(sim.ModelConfig{}, testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0, "")
 	defer func() {
 		if r := recover(); r == nil {
 			t.Error("expected panic for PD with zero ModelConfig, got none")
@@ -438,7 +438,7 @@ func TestDisaggregation_BackwardCompatibility(t *testing.T) {
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "H100", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances:  4,
 		RoutingPolicy: "round-robin",
@@ -486,6 +486,78 @@ func TestDisaggregation_PerPoolScorerConfigs(t *testing.T) {
 	}
 }
 
+// TestDisaggregation_PerPoolRoutingPolicies verifies that prefill and decode pools
+// route with independent policies: prefill honors prefix affinity (each prefix group
+// sticks to one prefill instance) while decode balances load across the decode pool.
+func TestDisaggregation_PerPoolRoutingPolicies(t *testing.T) {
+	const numRequests = 42
+	config := newTestDisaggDeploymentConfig(4, 2, 2)
+	config.TraceLevel = "decisions"
+	config.PrefillRoutingPolicy = "weighted"
+	config.PrefillScorerConfigs = []sim.ScorerConfig{{Name: "prefix-affinity", Weight: 1.0}}
+	config.DecodeRoutingPolicy = "least-loaded"
+
+	// Three prefix groups interleaved over two prefill instances, so a
+	// load-oblivious rotation would split every group. Suffixes are unique.
+	rng := rand.New(rand.NewSource(7))
+	prefixes := [][]sim.TokenID{
+		sim.GenerateRandomTokenIDs(rng, 256),
+		sim.GenerateRandomTokenIDs(rng, 256),
+		sim.GenerateRandomTokenIDs(rng, 256),
+	}
+	group := make(map[string]int, numRequests)
+	requests := make([]*sim.Request, numRequests)
+	for i := range requests {
+		g := i % len(prefixes)
+		id := fmt.Sprintf("request_%d", i)
+		group[id] = g
+		input := append(append([]sim.TokenID{}, prefixes[g]...), sim.GenerateRandomTokenIDs(rng, 64)...)
+		requests[i] = &sim.Request{
+			ID:           id,
+			ArrivalTime:  int64(i) * 500,
+			InputTokens:  input,
+			OutputTokens: sim.GenerateRandomTokenIDs(rng, 50),
+			State:        sim.StateQueued,
+		}
+	}
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	tr := cs.Trace()
+	if len(tr.PrefillRoutings) != numRequests || len(tr.KVTransfers) != numRequests {
+		t.Fatalf("PrefillRoutings=%d KVTransfers=%d, want %d each", len(tr.PrefillRoutings), len(tr.KVTransfers), numRequests)
+	}
+
+	// Prefill: prefix affinity pins each group to a single prefill instance.
+	prefillByGroup := make([]map[string]bool, len(prefixes))
+	for g := range prefillByGroup {
+		prefillByGroup[g] = make(map[string]bool)
+	}
+	for _, r := range tr.PrefillRoutings {
+		prefillByGroup[group[r.ParentRequestID]][r.ChosenInstance] = true
+	}
+	for g, insts := range prefillByGroup {
+		if len(insts) != 1 {
+			t.Errorf("prefix group %d prefilled on %d instances %v, want 1 (prefix affinity)", g, len(insts), insts)
+		}
+	}
+
+	// Decode: least-loaded spreads requests over both decode instances.
+	decodeCounts := make(map[string]int)
+	for _, kv := range tr.KVTransfers {
+		decodeCounts[kv.DecodeInstanceID]++
+	}
+	if len(decodeCounts) != config.DecodeInstances {
+		t.Fatalf("decode instances used = %v, want all %d", decodeCounts, config.DecodeInstances)
+	}
+	for inst, n := range decodeCounts {
+		if n < numRequests/4 {
+			t.Errorf("decode instance %s received %d of %d requests, want balanced load", inst, n, numRequests)
+		}
+	}
+}
+
 func TestReserveTransferredKV_Success(t *testing.T) {
 	cfg := sim.SimConfig{
 		Horizon:             1000000,
@@ -493,7 +565,7 @@ func TestReserveTransferredKV_Success(t *testing.T) {
 		KVCacheConfig:       sim.NewKVCacheConfig(1000, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0, ""),
 	}
 	inst := NewInstanceSimulator("decode_0", cfg)
 
@@ -522,7 +594,7 @@ func TestReserveTransferredKV_InsufficientCapacity(t *testing.T) {
 		KVCacheConfig:       sim.NewKVCacheConfig(2, 16, 0, 0, 0, 0), // Only 2 blocks
 		BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test", "H100", 1, 1, false, "", "roofline", 0, ""),
 	}
 	inst := NewInstanceSimulator("decode_0", cfg)
 
@@ -0,0 +1,51 @@
+package cluster
+
+import (
+	"testing"
+)
+
+// TestEffectiveMFU_ComputeBoundHighDecodeLow verifies the roofline effective
+// MFU metric: long single-token-output prompts keep the GPUs near the
+// calibrated prefill MFU, while lone short-prompt decodes leave them mostly idle
+// against peak. Checked per instance and on the cluster aggregate.
+func TestEffectiveMFU_ComputeBoundHighDecodeLow(t *testing.T) {
+	run := func(inMean, outMean int, rate float64) (perInstance []float64, aggregate float64) {
+		cfg := newTestDeploymentConfig(2)
+		cfg.RooflineAccounting = true
+		reqs := testGenerateRequests(42, 1_000_000_000, rate/1e6, 40, 0,
+			inMean, 0, inMean, inMean, outMean, 0, outMean, outMean)
+		cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+		mustRun(t, cs)
+		for _, m := range cs.PerInstanceMetrics() {
+			sum := m.Roofline.Summary()
+			if sum == nil {
+				t.Fatal("instance recorded no roofline steps")
+			}
+			perInstance = append(perInstance, sum.EffectiveMFU)
+		}
+		agg := cs.AggregatedMetrics().Roofline.Summary()
+		if agg == nil || agg.BusySeconds <= 0 {
+			t.Fatalf("aggregate roofline summary = %+v, want positive busy time", agg)
+		}
+		return perInstance, agg.EffectiveMFU
+	}
+
+	computeInst, computeAgg := run(4096, 1, 50)
+	decodeInst, decodeAgg := run(16, 256, 0.5)
+	t.Logf("effective MFU: compute-bound %v (agg %.3f), decode %v (agg %.3f)", computeInst, computeAgg, decodeInst, decodeAgg)
+
+	hw := testRooflineHWCalib()
+	for i, mfu := range computeInst {
+		if mfu < 0.8*hw.MfuPrefill || mfu > hw.MfuPrefill+1e-9 {
+			t.Errorf("compute-bound instance %d: effective MFU = %.3f, want within [%.3f, %.3f]", i, mfu, 0.8*hw.MfuPrefill, hw.MfuPrefill)
+		}
+	}
+	for i, mfu := range decodeInst {
+		if mfu > 0.05 {
+			t.Errorf("decode instance %d: effective MFU = %.3f, want <= 0.05", i, mfu)
+		}
+	}
+	if computeAgg < 0.8*hw.MfuPrefill || decodeAgg > 0.05 {
+		t.Errorf("aggregate effective MFU: compute-bound %.3f, decode %.3f", computeAgg, decodeAgg)
+	}
+}
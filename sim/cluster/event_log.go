@@ -0,0 +1,45 @@
+package cluster
+
+import "github.com/inference-sim/inference-sim/sim"
+
+// SetEventLog installs a log that records every event the cluster executes:
+// cluster events (arrival, admission, routing, ...) and instance events
+// (ArrivalEvent, StepEvent, RequestLeftEvent, ...), in execution order.
+// Orphaned TimeoutEvents skipped by lazy cancellation and events past the
+// horizon are not executed and not logged. Pass nil to disable (default).
+//
+// Must be called before Run(); panics otherwise.
+func (c *ClusterSimulator) SetEventLog(log *sim.EventLog) {
+	if c.hasRun {
+		panic("ClusterSimulator: SetEventLog must be called before Run()")
+	}
+	c.eventLog = log
+}
+
+// clusterEventRequestID returns the ID of the request a cluster event refers
+// to, or "" for control-plane events that carry none (scaling, lifecycle).
+func clusterEventRequestID(ev ClusterEvent) string {
+	var req *sim.Request
+	switch e := ev.(type) {
+	case *ClusterArrivalEvent:
+		req = e.request
+	case *AdmissionDecisionEvent:
+		req = e.request
+	case *RoutingDecisionEvent:
+		req = e.request
+	case *GatewayEvictionEvent:
+		req = e.request
+	case *DisaggregationDecisionEvent:
+		req = e.request
+	case *PrefillRoutingEvent:
+		req = e.request
+	case *KVTransferStartedEvent:
+		return e.parentReq.ID
+	case *KVTransferCompletedEvent:
+		return e.parentReq.ID
+	}
+	if req == nil {
+		return ""
+	}
+	return req.ID
+}
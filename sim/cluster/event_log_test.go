@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// TestEventLog_RecordsExecutedEventsInTickOrder runs a tiny workload with the
+// event log enabled and checks that instance arrivals, steps, and departures
+// are all recorded, in non-decreasing tick order.
+func TestEventLog_RecordsExecutedEventsInTickOrder(t *testing.T) {
+	var buf bytes.Buffer
+	log := sim.NewEventLog(&buf)
+	config := newTestDeploymentConfig(2)
+	requests := testGenerateRequests(42, 10_000_000, 10.0/1e6, 5,
+		0, 64, 8, 16, 128, 16, 4, 8, 32)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	cs.SetEventLog(log)
+	mustRun(t, cs)
+	if err := log.Err(); err != nil {
+		t.Fatalf("event log write error: %v", err)
+	}
+
+	seen := map[string]int{}
+	left := map[string]bool{}
+	lastTick := int64(-1)
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec sim.EventLogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		if rec.Tick < lastTick {
+			t.Fatalf("tick went backwards: %d after %d (%s)", rec.Tick, lastTick, rec.Type)
+		}
+		lastTick = rec.Tick
+		seen[rec.Type]++
+		if rec.Type == "RequestLeftEvent" {
+			if rec.RequestID == "" || rec.InstanceID == "" {
+				t.Errorf("RequestLeftEvent missing IDs: %+v", rec)
+			}
+			left[rec.RequestID] = true
+		}
+	}
+	for _, typ := range []string{"ClusterArrivalEvent", "ArrivalEvent", "StepEvent", "RequestLeftEvent"} {
+		if seen[typ] == 0 {
+			t.Errorf("no %s in event log (seen: %v)", typ, seen)
+		}
+	}
+	if len(left) != len(requests) {
+		t.Errorf("RequestLeftEvent for %d distinct requests, want %d", len(left), len(requests))
+	}
+}
+
+// TestEventLog_Disabled_IsInert verifies an unset event log changes nothing (INV-6).
+func TestEventLog_Disabled_IsInert(t *testing.T) {
+	run := func(withLog bool) *sim.Metrics {
+		requests := testGenerateRequests(42, 10_000_000, 10.0/1e6, 5,
+			0, 64, 8, 16, 128, 16, 4, 8, 32)
+		cs := NewClusterSimulator(newTestDeploymentConfig(2), NewSliceRequestSource(requests), nil)
+		if withLog {
+			cs.SetEventLog(sim.NewEventLog(&bytes.Buffer{}))
+		}
+		mustRun(t, cs)
+		return cs.AggregatedMetrics()
+	}
+	off, on := run(false), run(true)
+	if off.CompletedRequests != on.CompletedRequests || off.SimEndedTime != on.SimEndedTime || off.TTFTSum != on.TTFTSum {
+		t.Errorf("event log changed results: off=(%d, %d, %d) on=(%d, %d, %d)",
+			off.CompletedRequests, off.SimEndedTime, off.TTFTSum, on.CompletedRequests, on.SimEndedTime, on.TTFTSum)
+	}
+}
@@ -0,0 +1,115 @@
+package cluster
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/sirupsen/logrus"
+)
+
+// Fault modes: what happens to an instance's in-flight requests when it fails.
+const (
+	FaultModeLose    = "lose"    // in-flight requests are lost and counted as failed
+	FaultModeRequeue = "requeue" // in-flight requests are re-routed to surviving instances
+)
+
+var validFaultModes = map[string]bool{"": true, FaultModeLose: true, FaultModeRequeue: true}
+
+// IsValidFaultMode returns true if name is a recognized fault mode ("" = lose).
+func IsValidFaultMode(name string) bool { return validFaultModes[name] }
+
+// FaultInjectionConfig schedules a deterministic instance failure.
+// Zero value is safe: an empty InstanceID disables fault injection (INV-6).
+type FaultInjectionConfig struct {
+	InstanceID string // instance to fail (e.g. "instance_1"); empty = disabled
+	AtUs       int64  // simulated time of the failure in microseconds
+	Mode       string // FaultModeLose (default when empty) or FaultModeRequeue
+}
+
+// IsEnabled returns true when a failure is configured.
+func (c FaultInjectionConfig) IsEnabled() bool { return c.InstanceID != "" }
+
+// Validate checks the fault injection config. Returns nil when disabled.
+func (c FaultInjectionConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+	if c.AtUs < 0 {
+		return fmt.Errorf("fault injection time must be >= 0, got %d", c.AtUs)
+	}
+	if !IsValidFaultMode(c.Mode) {
+		return fmt.Errorf("unknown fault mode %q (valid: %s, %s)", c.Mode, FaultModeLose, FaultModeRequeue)
+	}
+	return nil
+}
+
+// InstanceFailureEvent abruptly kills an instance. Unlike a drain, the instance
+// does no further work: everything it holds (running, queued, and routed but not
+// yet enqueued) is either counted as failed or sent back through routing.
+// Priority -1: lifecycle events run before request events at the same tick (I6).
+type InstanceFailureEvent struct {
+	timestamp  int64
+	instanceID InstanceID
+	mode       string
+}
+
+func (e *InstanceFailureEvent) Timestamp() int64 { return e.timestamp }
+func (e *InstanceFailureEvent) Priority() int    { return priorityInstanceLifecycle }
+
+// Execute fails the instance, marks it Terminated so routing excludes it, and
+// disposes of its in-flight requests according to the fault mode.
+func (e *InstanceFailureEvent) Execute(cs *ClusterSimulator) {
+	var inst *InstanceSimulator
+	for _, candidate := range cs.instances {
+		if candidate.ID() == e.instanceID {
+			inst = candidate
+			break
+		}
+	}
+	if inst == nil || !inst.HasSim() || inst.State == sim.InstanceStateTerminated {
+		logrus.Warnf("[cluster] InstanceFailureEvent: instance %s not found or not running — fault ignored", e.instanceID)
+		return
+	}
+
+	failed := inst.sim.Fail()
+	instID := string(inst.ID())
+	if cs.inFlightRequests[instID] != len(failed) {
+		logrus.Warnf("[cluster] instance %s failed with inFlightRequests=%d but %d requests recovered — bookkeeping bug",
+			instID, cs.inFlightRequests[instID], len(failed))
+	}
+	cs.inFlightRequests[instID] = 0
+
+	inst.TransitionTo(sim.InstanceStateTerminated)
+	cs.releaseInstanceGPUs(inst)
+	if cs.snapshotProvider != nil {
+		cs.snapshotProvider.RemoveCacheInstance(inst.ID())
+	}
+	delete(cs.cacheQueryFn, instID)
+	logrus.Infof("[cluster] instance %s failed at tick %d with %d in-flight requests (mode=%s)",
+		instID, cs.clock, len(failed), e.mode)
+
+	for _, req := range failed {
+		if cs.evictionTracker != nil {
+			cs.evictionTracker.Untrack(req.ID)
+		}
+		if e.mode != FaultModeRequeue {
+			cs.failedRequests++
+			if cs.inFlightTokens != nil {
+				cs.inFlightTokens.Release(req.ID)
+			}
+			continue
+		}
+		// Re-route without re-admission: the request was already admitted once,
+		// and injectedByClass must not count it twice (INV-1).
+		req.AssignedInstance = ""
+		cs.requeuedRequests++
+		heap.Push(&cs.clusterEvents, clusterEventEntry{
+			event: &RoutingDecisionEvent{
+				time:    cs.clock + cs.routingLatency,
+				request: req,
+			},
+			seqID: cs.nextSeqID(),
+		})
+	}
+}
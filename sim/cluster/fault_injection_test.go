@@ -0,0 +1,118 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	faultTestRequests = 100
+	// faultTestAtUs avoids coinciding with any arrival or step tick, so the
+	// probe run truncated at faultTestAtUs-1 sees exactly the pre-failure state.
+	faultTestAtUs = 1_000_001
+)
+
+func newFaultTestCluster(horizon int64, fault FaultInjectionConfig) *ClusterSimulator {
+	config := newTestDeploymentConfig(2)
+	config.Horizon = horizon
+	config.MaxRunningReqs = 4
+	config.RoutingPolicy = "round-robin"
+	config.FaultInjection = fault
+	requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, faultTestRequests,
+		0, 200, 20, 100, 300, 200, 20, 100, 300)
+	return NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+}
+
+// TestFaultInjection_InstanceFailure verifies both fault modes against the
+// in-flight count on the failed instance just before the failure fires.
+func TestFaultInjection_InstanceFailure(t *testing.T) {
+	baseline := newFaultTestCluster(math.MaxInt64, FaultInjectionConfig{})
+	mustRun(t, baseline)
+	probe := newFaultTestCluster(faultTestAtUs-1, FaultInjectionConfig{})
+	mustRun(t, probe)
+	inFlightAtFailure := probe.inFlightRequests["instance_0"]
+	if inFlightAtFailure == 0 {
+		t.Fatal("instance_0 has no in-flight requests at the failure tick; test does not exercise failure")
+	}
+
+	t.Run("requeue", func(t *testing.T) {
+		cs := newFaultTestCluster(math.MaxInt64, FaultInjectionConfig{InstanceID: "instance_0", AtUs: faultTestAtUs, Mode: FaultModeRequeue})
+		mustRun(t, cs)
+
+		if cs.FailedRequests() != 0 {
+			t.Errorf("FailedRequests = %d, want 0 in requeue mode", cs.FailedRequests())
+		}
+		if cs.RequeuedRequests() != inFlightAtFailure {
+			t.Errorf("RequeuedRequests = %d, want %d (in-flight at failure)", cs.RequeuedRequests(), inFlightAtFailure)
+		}
+		// INV-1: every request still completes, and no request is counted twice.
+		agg := cs.AggregatedMetrics()
+		if agg.CompletedRequests != faultTestRequests || len(agg.Requests) != faultTestRequests {
+			t.Errorf("completed = %d, registered = %d, want %d each", agg.CompletedRequests, len(agg.Requests), faultTestRequests)
+		}
+		// Requeued requests' input tokens were counted at their first admission.
+		if want := baseline.AggregatedMetrics().TotalInputTokens; agg.TotalInputTokens != want {
+			t.Errorf("TotalInputTokens = %d, want %d (each request counted once)", agg.TotalInputTokens, want)
+		}
+		// Work after the failure lands on the survivor.
+		perInst := cs.PerInstanceMetricsByID()
+		failedDone := perInst["instance_0"].CompletedRequests
+		survivorDone := perInst["instance_1"].CompletedRequests
+		if failedDone+survivorDone != faultTestRequests || survivorDone <= faultTestRequests/2 {
+			t.Errorf("completions: instance_0=%d instance_1=%d, want survivor to absorb rerouted load", failedDone, survivorDone)
+		}
+	})
+
+	t.Run("lose", func(t *testing.T) {
+		cs := newFaultTestCluster(math.MaxInt64, FaultInjectionConfig{InstanceID: "instance_0", AtUs: faultTestAtUs, Mode: FaultModeLose})
+		mustRun(t, cs)
+
+		if cs.FailedRequests() != inFlightAtFailure {
+			t.Errorf("FailedRequests = %d, want %d (in-flight at failure)", cs.FailedRequests(), inFlightAtFailure)
+		}
+		if cs.RequeuedRequests() != 0 {
+			t.Errorf("RequeuedRequests = %d, want 0 in lose mode", cs.RequeuedRequests())
+		}
+		agg := cs.AggregatedMetrics()
+		if agg.CompletedRequests+cs.FailedRequests() != faultTestRequests {
+			t.Errorf("conservation: completed(%d) + failed(%d) != %d", agg.CompletedRequests, cs.FailedRequests(), faultTestRequests)
+		}
+		if cs.Instances()[0].IsRoutable() {
+			t.Error("failed instance is still routable")
+		}
+	})
+}
+
+// TestFaultInjection_Disabled_IsInert verifies the zero config injects nothing (INV-6).
+func TestFaultInjection_Disabled_IsInert(t *testing.T) {
+	cs := newFaultTestCluster(math.MaxInt64, FaultInjectionConfig{})
+	mustRun(t, cs)
+	if cs.FailedRequests() != 0 || cs.RequeuedRequests() != 0 {
+		t.Errorf("failed=%d requeued=%d, want 0 with fault injection disabled", cs.FailedRequests(), cs.RequeuedRequests())
+	}
+	if got := cs.AggregatedMetrics().CompletedRequests; got != faultTestRequests {
+		t.Errorf("completed = %d, want %d", got, faultTestRequests)
+	}
+}
+
+func TestFaultInjectionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     FaultInjectionConfig
+		wantErr bool
+	}{
+		{"disabled", FaultInjectionConfig{}, false},
+		{"disabled ignores other fields", FaultInjectionConfig{AtUs: -1, Mode: "explode"}, false},
+		{"default mode", FaultInjectionConfig{InstanceID: "instance_0", AtUs: 10}, false},
+		{"requeue", FaultInjectionConfig{InstanceID: "instance_0", Mode: FaultModeRequeue}, false},
+		{"negative time", FaultInjectionConfig{InstanceID: "instance_0", AtUs: -1}, true},
+		{"unknown mode", FaultInjectionConfig{InstanceID: "instance_0", Mode: "explode"}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cfg.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
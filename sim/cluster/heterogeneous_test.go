@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// newHeterogeneousTestConfig returns a 2-instance deployment where instance_0 uses
+// the global H100 calibration and instance_1 is overridden to a slower "A100"
+// calibration (1/10 the compute and bandwidth) and a smaller KV cache.
+func newHeterogeneousTestConfig() DeploymentConfig {
+	config := newTestDeploymentConfig(2)
+	config.RoutingPolicy = "least-loaded"
+	slow := sim.HardwareCalib{TFlopsPeak: 99.0, BwPeakTBs: 0.335, MfuPrefill: 0.55, MfuDecode: 0.30}
+	config.HWConfigByGPU = map[string]sim.HardwareCalib{"A100": slow}
+	tp := 1
+	blocks := int64(5000)
+	config.InstanceOverrides = []InstanceOverride{
+		{}, // instance_0: global config
+		{PoolOverrides: PoolOverrides{GPU: "A100", TP: &tp, TotalKVBlocks: &blocks}},
+	}
+	return config
+}
+
+// TestResolveConfigForInstance_AppliesOverrideOnTopOfGlobal verifies that only the
+// overridden instance differs and the global SimConfig is not mutated.
+func TestResolveConfigForInstance_AppliesOverrideOnTopOfGlobal(t *testing.T) {
+	config := newHeterogeneousTestConfig()
+	coeffs := sim.NewLatencyCoeffs([]float64{2000, 20, 10}, []float64{200, 2, 200})
+	config.InstanceOverrides[1].LatencyCoeffs = &coeffs
+
+	c0 := config.resolveConfigForInstance(0, PoolRole(0))
+	c1 := config.resolveConfigForInstance(1, PoolRole(0))
+
+	if c0.GPU != "H100" || c0.TotalKVBlocks != 10000 || c0.HWConfig.TFlopsPeak != 989.0 {
+		t.Errorf("instance_0 should use global config, got GPU=%q blocks=%d tflops=%v", c0.GPU, c0.TotalKVBlocks, c0.HWConfig.TFlopsPeak)
+	}
+	if c1.GPU != "A100" || c1.TP != 1 || c1.TotalKVBlocks != 5000 {
+		t.Errorf("instance_1 override not applied: GPU=%q TP=%d blocks=%d", c1.GPU, c1.TP, c1.TotalKVBlocks)
+	}
+	if c1.HWConfig.TFlopsPeak != 99.0 {
+		t.Errorf("instance_1 HWConfig should come from HWConfigByGPU[A100], got TFlopsPeak=%v", c1.HWConfig.TFlopsPeak)
+	}
+	if c1.BetaCoeffs[0] != 2000 {
+		t.Errorf("instance_1 LatencyCoeffs not applied, got BetaCoeffs=%v", c1.BetaCoeffs)
+	}
+	if config.GPU != "H100" || config.BetaCoeffs[0] != 1000 {
+		t.Error("global SimConfig was mutated by resolveConfigForInstance")
+	}
+}
+
+// TestHeterogeneousCluster_FasterInstanceCompletesMore verifies that under
+// least-loaded routing the faster instance drains its queue sooner, receives
+// more traffic, and completes proportionally more requests. Aggregated metrics
+// must still equal the per-instance sums.
+func TestHeterogeneousCluster_FasterInstanceCompletesMore(t *testing.T) {
+	config := newHeterogeneousTestConfig()
+	// 200 req/s keeps both instances busy without routing the whole workload before
+	// either instance drains (which would equalize in-flight counts regardless of speed).
+	requests := testGenerateRequests(42, math.MaxInt64, 200.0/1e6, 200,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	perInst := cs.PerInstanceMetricsByID()
+	fast := perInst["instance_0"].CompletedRequests
+	slow := perInst["instance_1"].CompletedRequests
+	if float64(fast) < 2.0*float64(slow) {
+		t.Errorf("fast instance completed %d, slow instance %d; want fast >= 2× slow", fast, slow)
+	}
+
+	agg := cs.AggregatedMetrics()
+	if agg.CompletedRequests != fast+slow {
+		t.Errorf("aggregated CompletedRequests = %d, want %d (sum of instances)", agg.CompletedRequests, fast+slow)
+	}
+	if agg.CompletedRequests != len(requests) {
+		t.Errorf("aggregated CompletedRequests = %d, want all %d requests (INV-1)", agg.CompletedRequests, len(requests))
+	}
+
+	// Routing snapshots see the per-instance TP degree and GPU type.
+	instances := cs.Instances()
+	if instances[1].GPU() != "A100" || instances[1].TPDegree != 1 {
+		t.Errorf("instance_1: GPU=%q TPDegree=%d, want A100/1", instances[1].GPU(), instances[1].TPDegree)
+	}
+	if instances[0].TPDegree != 0 {
+		t.Errorf("instance_0 without override should keep TPDegree=0, got %d", instances[0].TPDegree)
+	}
+}
+
+// TestHeterogeneousCluster_StepTimeScorerRoutesByCapacity verifies that each
+// instance's reference step time reflects its hardware override, and that
+// weighted routing on the step-time scorer sends the faster instance a larger
+// share of the traffic than load-balance, which counts requests only.
+func TestHeterogeneousCluster_StepTimeScorerRoutesByCapacity(t *testing.T) {
+	run := func(scorer string) (*ClusterSimulator, map[string]int) {
+		config := newHeterogeneousTestConfig()
+		config.RoutingPolicy = "weighted"
+		config.RoutingScorerConfigs = []sim.ScorerConfig{{Name: scorer, Weight: 1}}
+		requests := testGenerateRequests(42, math.MaxInt64, 200.0/1e6, 200,
+			0, 100, 20, 10, 200, 50, 10, 10, 100)
+		cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+		mustRun(t, cs)
+		routed := map[string]int{}
+		for _, rm := range cs.AggregatedMetrics().Requests {
+			routed[rm.HandledBy]++
+		}
+		return cs, routed
+	}
+
+	cs, byStepTime := run("step-time")
+	fast, slow := cs.Instances()[0].StepTimeEstimate(), cs.Instances()[1].StepTimeEstimate()
+	if fast <= 0 || slow <= fast {
+		t.Fatalf("StepTimeEstimate: instance_0 = %v, instance_1 = %v; want 0 < fast < slow", fast, slow)
+	}
+	_, byLoad := run("load-balance")
+	t.Logf("step-time estimates fast=%v slow=%v; step-time routed %v, load-balance routed %v", fast, slow, byStepTime, byLoad)
+	if byStepTime["instance_0"] <= byLoad["instance_0"] {
+		t.Errorf("step-time routed %d requests to the fast instance, want more than load-balance (%d)",
+			byStepTime["instance_0"], byLoad["instance_0"])
+	}
+}
+
+// TestNewClusterSimulator_TooManyInstanceOverrides_Panics verifies the length guard.
+func TestNewClusterSimulator_TooManyInstanceOverrides_Panics(t *testing.T) {
+	config := newTestDeploymentConfig(1)
+	config.InstanceOverrides = []InstanceOverride{{}, {}}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic when InstanceOverrides exceed NumInstances")
+		}
+	}()
+	NewClusterSimulator(config, NewSliceRequestSource(nil), nil)
+}
@@ -0,0 +1,54 @@
+package cluster
+
+import "github.com/inference-sim/inference-sim/sim"
+
+// InFlightTokenTracker is the cluster-wide in-flight token ledger behind
+// DeploymentConfig.MaxInFlightTokens. A request is in flight from admission
+// until it reaches a terminal state (completion, drop, timeout, or rejection
+// at routing). It holds its input tokens plus its whole output budget the
+// entire time: its remaining work is not monotone, since a recompute
+// preemption discards the output generated so far and the request decodes it
+// again, so crediting decode progress back would let the remaining work of
+// admitted requests grow past the cap. The full reservation bounds it.
+type InFlightTokenTracker struct {
+	reserved map[string]int64 // tokens held per tracked request ID
+	total    int64            // sum of reserved
+	peak     int64            // largest total seen
+}
+
+// NewInFlightTokenTracker creates an empty tracker.
+func NewInFlightTokenTracker() *InFlightTokenTracker {
+	return &InFlightTokenTracker{reserved: make(map[string]int64)}
+}
+
+// InFlightTokens returns the tokens reserved by tracked requests.
+func (t *InFlightTokenTracker) InFlightTokens() int64 {
+	return t.total
+}
+
+// Reserve starts tracking req, holding its input tokens plus its output budget
+// as declared now: an instance later filling in a missing budget (MaxModelLen
+// auto-fill) cannot grow the total past what admission checked. Re-reserving
+// a tracked request replaces its reservation.
+func (t *InFlightTokenTracker) Reserve(req *sim.Request) {
+	t.Release(req.ID)
+	tokens := sim.InFlightTokenReservation(req)
+	t.reserved[req.ID] = tokens
+	t.total += tokens
+	// The total only changes here and in Release, so it peaks right after a
+	// reservation.
+	t.peak = max(t.peak, t.total)
+}
+
+// Release stops tracking the request with the given ID. No-op if untracked.
+func (t *InFlightTokenTracker) Release(reqID string) {
+	if tokens, ok := t.reserved[reqID]; ok {
+		t.total -= tokens
+		delete(t.reserved, reqID)
+	}
+}
+
+// Peak returns the largest in-flight token total observed.
+func (t *InFlightTokenTracker) Peak() int64 {
+	return t.peak
+}
@@ -0,0 +1,135 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// runInFlightTokenCap runs 200 requests of 100 input + 100 output tokens
+// arriving every 100µs — far faster than 2 instances drain them — under a
+// 2000-token cluster-wide cap.
+func runInFlightTokenCap(t *testing.T, retries int) *ClusterSimulator {
+	t.Helper()
+	cfg := newTestDeploymentConfig(2)
+	cfg.MaxInFlightTokens = 2000
+	cfg.RetryMaxAttempts = retries
+	cfg.RetryBackoffUs = 5000
+	reqs := make([]*sim.Request, 200)
+	for i := range reqs {
+		reqs[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 100,
+			InputTokens:  make([]sim.TokenID, 100),
+			OutputTokens: make([]sim.TokenID, 100),
+			MaxOutputLen: 100,
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+	mustRun(t, cs)
+	return cs
+}
+
+func TestMaxInFlightTokens_HeavyLoad_NeverExceedsCap(t *testing.T) {
+	// GIVEN heavy load against a 2000-token in-flight cap
+	// WHEN the cluster runs without admission retries
+	cs := runInFlightTokenCap(t, 0)
+
+	// THEN the in-flight token sum never exceeds the cap, and the cap binds
+	peak := cs.inFlightTokens.Peak()
+	if peak > 2000 {
+		t.Errorf("peak in-flight tokens = %d, want <= 2000", peak)
+	}
+	if peak <= 2000-200 {
+		t.Errorf("peak in-flight tokens = %d, want > 1800 (cap should bind)", peak)
+	}
+	// AND arrivals beyond the cap are rejected
+	if cs.RejectedRequests() == 0 {
+		t.Error("RejectedRequests = 0, want arrivals rejected by the cap")
+	}
+	// AND every admitted request released its tokens (conservation)
+	if got := cs.inFlightTokens.InFlightTokens(); got != 0 {
+		t.Errorf("in-flight tokens after run = %d, want 0", got)
+	}
+}
+
+func TestMaxInFlightTokens_WithRetries_DelaysInsteadOfDropping(t *testing.T) {
+	// GIVEN the same load, once with rejection final and once with retries
+	final := runInFlightTokenCap(t, 0)
+	retried := runInFlightTokenCap(t, 8)
+
+	// THEN capped clients retry later: fewer final rejections, cap still held
+	if retried.RetriedRequests() == 0 {
+		t.Error("RetriedRequests = 0, want capped arrivals delayed by retries")
+	}
+	if retried.RejectedRequests() >= final.RejectedRequests() {
+		t.Errorf("rejections with retries = %d, without = %d, want fewer with retries",
+			retried.RejectedRequests(), final.RejectedRequests())
+	}
+	if peak := retried.inFlightTokens.Peak(); peak > 2000 {
+		t.Errorf("peak in-flight tokens with retries = %d, want <= 2000", peak)
+	}
+}
+
+func TestMaxInFlightTokens_KVPreemptions_RemainingWorkStaysUnderCap(t *testing.T) {
+	// GIVEN a 2000-token cap over one instance with 50 KV blocks (800 tokens):
+	// a few partly decoded requests fill the cache, so admitted requests are
+	// recompute-preempted and must decode their output again
+	cfg := newTestDeploymentConfig(1)
+	cfg.TotalKVBlocks = 50
+	cfg.MaxInFlightTokens = 2000
+	reqs := make([]*sim.Request, 200)
+	byID := make(map[string]*sim.Request, len(reqs))
+	for i := range reqs {
+		reqs[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 3000,
+			InputTokens:  make([]sim.TokenID, 100),
+			OutputTokens: make([]sim.TokenID, 100),
+			MaxOutputLen: 100,
+			State:        sim.StateQueued,
+		}
+		byID[reqs[i].ID] = reqs[i]
+	}
+	cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+	// Sample the work admitted requests still owe at every arrival
+	var worst int64
+	cs.SetArrivalHook(func(*sim.Request) {
+		var remaining int64
+		for id := range cs.inFlightTokens.reserved {
+			req := byID[id]
+			remaining += req.InputLen() + req.RemainingOutputBudget()
+		}
+		worst = max(worst, remaining)
+	})
+	mustRun(t, cs)
+
+	// THEN preemptions happened
+	if got := cs.AggregatedMetrics().PreemptionCount; got == 0 {
+		t.Fatal("PreemptionCount = 0, want KV pressure to preempt admitted requests")
+	}
+	// AND the remaining input + output work never exceeded the cap
+	if worst > 2000 {
+		t.Errorf("remaining in-flight work peaked at %d tokens, want <= 2000", worst)
+	}
+	if peak := cs.inFlightTokens.Peak(); peak > 2000 {
+		t.Errorf("peak in-flight tokens = %d, want <= 2000", peak)
+	}
+	if got := cs.inFlightTokens.InFlightTokens(); got != 0 {
+		t.Errorf("in-flight tokens after run = %d, want 0", got)
+	}
+}
+
+func TestMaxInFlightTokens_WithFlowControl_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for MaxInFlightTokens with flow control")
+		}
+	}()
+	cfg := newTestDeploymentConfig(1)
+	cfg.MaxInFlightTokens = 1000
+	cfg.FlowControlEnabled = true
+	NewClusterSimulator(cfg, NewSliceRequestSource(nil), nil)
+}
@@ -200,7 +200,17 @@ func (d *drainImmediate) Drain(inst *InstanceSimulator, cs *ClusterSimulator) {
 	if cs != nil && inst.HasSim() {
 		instID := string(inst.ID())
 		abandoned := inst.QueueDepth() + inst.BatchSize()
-		_ = inst.DrainWaitQueue() // discard queued requests; they won't be re-routed
+		if cs.inFlightTokens != nil && inst.sim.RunningBatch != nil {
+			for _, req := range inst.sim.RunningBatch.Requests {
+				cs.inFlightTokens.Release(req.ID)
+			}
+		}
+		queued := inst.DrainWaitQueue() // discard queued requests; they won't be re-routed
+		if cs.inFlightTokens != nil {
+			for _, req := range queued {
+				cs.inFlightTokens.Release(req.ID)
+			}
+		}
 		if abandoned > 0 {
 			cs.inFlightRequests[instID] -= abandoned
 			if cs.inFlightRequests[instID] < 0 {
@@ -269,6 +279,12 @@ func (d *drainRedirect) Drain(inst *InstanceSimulator, cs *ClusterSimulator) {
 			cs.inFlightRequests[instID] = 0
 		}
 	}
+	// Re-injected requests are re-admitted, so they re-reserve their tokens there.
+	if cs.inFlightTokens != nil {
+		for _, req := range queued {
+			cs.inFlightTokens.Release(req.ID)
+		}
+	}
 
 	// I10 (intentional): Re-injected requests go through admission again.
 	// This is deliberate — re-validates capacity after the drain event rather than
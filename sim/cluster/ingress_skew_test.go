@@ -0,0 +1,91 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+)
+
+// TestIngressClockOffsets_ShiftArrivalsAndKeepRoutingBalanced verifies that
+// GIVEN requests alternating between two ingress points with different clock
+// offsets, and a 2-instance round-robin cluster
+// WHEN the simulation runs
+// THEN each request's arrival time is shifted by its ingress point's offset
+// (clamped at 0), AND round-robin still splits the requests evenly.
+func TestIngressClockOffsets_ShiftArrivalsAndKeepRoutingBalanced(t *testing.T) {
+	offsets := map[string]int64{"us-east": 20_000, "eu-west": -150_000}
+	requests := newTestRequests(20)
+	want := make(map[string]int64, len(requests))
+	for i, req := range requests {
+		req.IngressPoint = "us-east"
+		if i%2 == 1 {
+			req.IngressPoint = "eu-west"
+		}
+		want[req.ID] = max(req.ArrivalTime+offsets[req.IngressPoint], 0)
+	}
+
+	config := newTestDeploymentConfig(2)
+	config.RoutingPolicy = "round-robin"
+	config.IngressClockOffsetsUs = offsets
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	agg := cs.AggregatedMetrics()
+	if agg.CompletedRequests != len(requests) {
+		t.Fatalf("completed %d requests, want %d", agg.CompletedRequests, len(requests))
+	}
+	clamped := 0
+	for _, req := range requests {
+		if req.ArrivalTime != want[req.ID] {
+			t.Errorf("%s (%s): ArrivalTime = %d, want %d", req.ID, req.IngressPoint, req.ArrivalTime, want[req.ID])
+		}
+		if got := agg.Requests[req.ID].ArrivedAt; math.Abs(got-float64(want[req.ID])/1e6) > 1e-9 {
+			t.Errorf("%s: ArrivedAt = %v s, want %v s", req.ID, got, float64(want[req.ID])/1e6)
+		}
+		if want[req.ID] == 0 {
+			clamped++
+		}
+	}
+	if clamped == 0 {
+		t.Error("expected the negative eu-west offset to clamp at least one arrival to 0")
+	}
+
+	for _, inst := range cs.Instances() {
+		if n := len(inst.Metrics().Requests); n != len(requests)/2 {
+			t.Errorf("%s handled %d requests, want %d", inst.ID(), n, len(requests)/2)
+		}
+	}
+}
+
+// TestIngressClockOffsets_UnlistedPointUnshifted verifies requests whose
+// ingress point has no configured offset keep their arrival time.
+func TestIngressClockOffsets_UnlistedPointUnshifted(t *testing.T) {
+	requests := newTestRequests(4)
+	want := make(map[string]int64, len(requests))
+	for _, req := range requests {
+		req.IngressPoint = "ap-south"
+		want[req.ID] = req.ArrivalTime
+	}
+	config := newTestDeploymentConfig(1)
+	config.IngressClockOffsetsUs = map[string]int64{"us-east": 5_000}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	for _, req := range requests {
+		if req.ArrivalTime != want[req.ID] {
+			t.Errorf("%s: ArrivalTime = %d, want unshifted %d", req.ID, req.ArrivalTime, want[req.ID])
+		}
+	}
+}
+
+// TestIngressClockOffsets_WithStopAfterCompleted_Panics verifies R3: skewed
+// arrivals break the source order on-demand pulls rely on.
+func TestIngressClockOffsets_WithStopAfterCompleted_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for IngressClockOffsetsUs with StopAfterCompleted")
+		}
+	}()
+	config := newTestDeploymentConfig(1)
+	config.IngressClockOffsetsUs = map[string]int64{"us-east": 5_000}
+	config.StopAfterCompleted = 2
+	NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(4)), nil)
+}
@@ -45,6 +45,14 @@ type InstanceSimulator struct {
 	// maxRunningReqs stores cfg.BatchConfig.MaxRunningReqs at construction time.
 	// Exposed via MaxBatchSize() for the autoscaler pipeline.
 	maxRunningReqs int64
+
+	// stepTimeEstimate is the latency model's time for a reference decode step
+	// (see referenceStepTime), exposed to routing as RoutingSnapshot.StepTimeEstimate.
+	stepTimeEstimate float64
+
+	// rooflineStats is the latency model's per-step FLOPs/bytes accumulator
+	// (nil unless cfg.RooflineAccounting); copied into Metrics at Finalize.
+	rooflineStats *sim.RooflineStepStats
 }
 
 // NewInstanceSimulator creates an InstanceSimulator from a SimConfig struct.
@@ -64,22 +72,73 @@ func NewInstanceSimulator(id InstanceID, cfg sim.SimConfig) *InstanceSimulator {
 	if err != nil {
 		panic(fmt.Sprintf("NewInstanceSimulator(%s): adapter cost model: %v", id, err))
 	}
-	latencyModel, err := latency.NewLatencyModel(cfg.LatencyCoeffs, cfg.ModelHardwareConfig, latency.WithAdapterCost(adapterCost))
+	var blockTableBlockSize int64
+	if cfg.RooflineBlockTable {
+		blockTableBlockSize = cfg.KVCacheConfig.BlockSizeTokens
+	}
+	var rooflineStats *sim.RooflineStepStats
+	if cfg.RooflineAccounting {
+		rooflineStats = &sim.RooflineStepStats{}
+	}
+	latencyModel, err := latency.NewLatencyModel(cfg.LatencyCoeffs, cfg.ModelHardwareConfig,
+		latency.WithAdapterCost(adapterCost), latency.WithSchedulingOverhead(cfg.SchedulingOverheadUsPerSeq),
+		latency.WithSchedulingContention(cfg.SchedulingContentionUsPerSeqSq),
+		latency.WithBlockSize(blockTableBlockSize), latency.WithRooflineStats(rooflineStats),
+		latency.WithMinStepTime(cfg.MinStepTimeTicks))
 	if err != nil {
 		panic(fmt.Sprintf("NewInstanceSimulator(%s): NewLatencyModel: %v", id, err))
 	}
+	if cfg.StepNoiseMagnitude > 0 {
+		noiseRNG := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(cfg.Seed), cfg.RandSource).
+			ForSubsystem(sim.SubsystemStepNoiseFor(string(id)))
+		latencyModel, err = latency.NewNoisyLatencyModel(latencyModel, cfg.StepNoiseMagnitude, cfg.StepNoiseCorrelation, noiseRNG)
+		if err != nil {
+			panic(fmt.Sprintf("NewInstanceSimulator(%s): %v", id, err))
+		}
+	}
 	s, err := sim.NewSimulator(cfg, kvStore, latencyModel)
 	if err != nil {
 		panic(fmt.Sprintf("NewInstanceSimulator(%s): %v", id, err))
 	}
 	return &InstanceSimulator{
-		id:             id,
-		sim:            s,
-		gpu:            cfg.GPU,
-		maxRunningReqs: cfg.MaxRunningReqs,
+		id:               id,
+		sim:              s,
+		gpu:              cfg.GPU,
+		maxRunningReqs:   cfg.MaxRunningReqs,
+		stepTimeEstimate: referenceStepTime(id, cfg),
+		rooflineStats:    rooflineStats,
+	}
+}
+
+// referenceStepContextTokens is the context length of the reference decode
+// step used to compare instance speeds.
+const referenceStepContextTokens = 512
+
+// referenceStepTime returns the time cfg's latency model takes for one decode
+// step of a single request at referenceStepContextTokens of context: a
+// per-instance speed figure that reflects its GPU, TP degree and latency
+// coefficients. It uses a model of its own so the estimate neither advances
+// step-noise state nor counts in roofline accounting.
+func referenceStepTime(id InstanceID, cfg sim.SimConfig) float64 {
+	model, err := latency.NewLatencyModel(cfg.LatencyCoeffs, cfg.ModelHardwareConfig)
+	if err != nil {
+		panic(fmt.Sprintf("NewInstanceSimulator(%s): NewLatencyModel: %v", id, err))
 	}
+	probe := &sim.Request{
+		ID:            "step-time-estimate",
+		InputTokens:   make([]sim.TokenID, referenceStepContextTokens),
+		OutputTokens:  make([]sim.TokenID, 1),
+		ProgressIndex: referenceStepContextTokens,
+		NumNewTokens:  1,
+		State:         sim.StateRunning,
+	}
+	return float64(model.StepTime([]*sim.Request{probe}))
 }
 
+// StepTimeEstimate returns the instance's reference decode step time in μs
+// (see referenceStepTime).
+func (i *InstanceSimulator) StepTimeEstimate() float64 { return i.stepTimeEstimate }
+
 // GPU returns the GPU type this instance was constructed with.
 // When NodePools are configured, this reflects the pool's gpu_type (authoritative).
 // When NodePools are absent, this reflects config.GPU (the CLI flag).
@@ -130,6 +189,13 @@ func (i *InstanceSimulator) PostDecodeFixedOverhead() int64 {
 	return i.sim.PostDecodeFixedOverhead()
 }
 
+// DetokenizationTime returns the CPU detokenization cost (µs) of a request
+// completed on this instance. Used by detectDecodeCompletions alongside
+// PostDecodeFixedOverhead. Returns 0 when the term is disabled.
+func (i *InstanceSimulator) DetokenizationTime(req *sim.Request) int64 {
+	return i.sim.DetokenizationTime(req)
+}
+
 // InjectRequest delegates to sim.InjectArrival. Panics if called after Run().
 func (i *InstanceSimulator) InjectRequest(req *sim.Request) {
 	if i.hasRun {
@@ -149,12 +215,27 @@ func (i *InstanceSimulator) PeekNextEventTime() int64 { return i.sim.PeekNextEve
 // Caller MUST check HasPendingEvents() first; panics on empty queue.
 func (i *InstanceSimulator) ProcessNextEvent() sim.Event { return i.sim.ProcessNextEvent() }
 
+// SetProbeTicks registers ticks at which the instance records a queue and
+// batch snapshot (see sim.Simulator.SetProbeTicks). Must be called before Run().
+func (i *InstanceSimulator) SetProbeTicks(ticks []int64) { i.sim.SetProbeTicks(ticks) }
+
+// ProbeSnapshots returns the instance's recorded probe snapshots, in tick order.
+func (i *InstanceSimulator) ProbeSnapshots() []sim.ProbeSnapshot { return i.sim.ProbeSnapshots() }
+
 // Finalize sets SimEndedTime, captures KV metrics, and logs completion.
 func (i *InstanceSimulator) Finalize() {
 	i.sim.Finalize()
 	// Capture KV metrics at finalization for CollectRawMetrics
 	i.sim.Metrics.CacheHitRate = i.sim.KVCache.CacheHitRate()
+	byGroup := i.sim.KVCache.CacheHitCountsByGroup()
+	i.sim.Metrics.CacheHitCountsByTenant = sim.CacheHitCountsBy(byGroup, func(g sim.CacheGroup) string { return g.TenantID })
+	i.sim.Metrics.CacheHitCountsBySLOClass = sim.CacheHitCountsBy(byGroup, func(g sim.CacheGroup) string { return g.SLOClass })
 	i.sim.Metrics.KVThrashingRate = i.sim.KVCache.KVThrashingRate()
+	i.sim.Metrics.KVBlocksSavedBySharing = i.sim.KVCache.BlocksSavedBySharing()
+	i.sim.Metrics.KVRoundingWasteTokens, i.sim.Metrics.KVAllocations = i.sim.KVCache.RoundingWaste()
+	if i.rooflineStats != nil {
+		i.sim.Metrics.Roofline = *i.rooflineStats
+	}
 }
 
 // QueueDepth returns the number of requests in the wait queue.
@@ -167,6 +248,12 @@ func (i *InstanceSimulator) BatchSize() int {
 	return i.sim.BatchSize()
 }
 
+// RemainingDecodeTokens returns the output tokens this instance has yet to
+// generate for in-transit, queued, and running requests.
+func (i *InstanceSimulator) RemainingDecodeTokens() int64 {
+	return i.sim.RemainingDecodeTokens()
+}
+
 // ResidentAdapterIDs returns the ids of LoRA adapters currently resident on this
 // instance, or nil when the LoRA subsystem is inert. Read by the snapshot provider
 // to populate RoutingSnapshot.ResidentAdapters for the lora-affinity scorer (#1469).
@@ -218,6 +305,57 @@ func (i *InstanceSimulator) KvTokensInUse() int64 {
 	return i.sim.KVCache.UsedBlocks() * i.sim.KVCache.BlockSize()
 }
 
+// attachHostBandwidth makes this instance's CPU↔GPU KV transfers contend on the
+// shared host-memory bandwidth h. No-op for single-tier KV stores.
+func (i *InstanceSimulator) attachHostBandwidth(h *kv.HostBandwidth) {
+	if i.sim == nil {
+		return
+	}
+	if tiered, ok := i.sim.KVCache.(*kv.TieredKVCache); ok {
+		tiered.SetHostBandwidth(h)
+	}
+}
+
+// attachRemotePrefixLookup installs the shared prefix cache check the instance
+// applies when it charges a remote prefix fetch (sim.Simulator.SetRemotePrefixLookup).
+func (i *InstanceSimulator) attachRemotePrefixLookup(fn func(*sim.Request) int) {
+	if i.sim == nil {
+		return
+	}
+	i.sim.SetRemotePrefixLookup(fn)
+}
+
+// attachCompletionGate installs the check the instance asks before completing
+// a finished request (sim.Simulator.SetCompletionGate).
+func (i *InstanceSimulator) attachCompletionGate(fn func(*sim.Request) bool) {
+	if i.sim == nil {
+		return
+	}
+	i.sim.SetCompletionGate(fn)
+}
+
+// KVPrefixIndexState dumps this instance's KV prefix index.
+// Returns false when the KV store cannot dump one.
+func (i *InstanceSimulator) KVPrefixIndexState() (kv.PrefixIndexState, bool) {
+	if i.sim == nil {
+		return kv.PrefixIndexState{}, false
+	}
+	idx, ok := i.sim.KVCache.(kv.PrefixIndexer)
+	if !ok {
+		return kv.PrefixIndexState{}, false
+	}
+	return idx.PrefixIndexState(), true
+}
+
+// KVExportPrefixes returns this instance's fully cached prefixes, least
+// recently used first. Returns nil for an unconstructed instance.
+func (i *InstanceSimulator) KVExportPrefixes() [][]sim.TokenID {
+	if i.sim == nil || i.sim.KVCache == nil {
+		return nil
+	}
+	return i.sim.KVCache.ExportPrefixes()
+}
+
 // TotalKVBlocks returns the total number of KV cache blocks for this instance.
 func (i *InstanceSimulator) TotalKVBlocks() int64 {
 	if i.sim == nil || i.sim.KVCache == nil {
@@ -226,11 +364,41 @@ func (i *InstanceSimulator) TotalKVBlocks() int64 {
 	return i.sim.KVCache.TotalCapacity()
 }
 
+// InputFitsKV reports whether an input of n tokens fits in this instance's
+// empty KV cache: ceil(n / block size) <= total blocks. It mirrors the
+// enqueue-time KV capacity guard (R19), so a request failing it on every
+// instance can never be served.
+func (i *InstanceSimulator) InputFitsKV(n int64) bool {
+	if i.sim == nil || i.sim.KVCache == nil {
+		return true
+	}
+	blockSize := i.sim.KVCache.BlockSize()
+	return (n+blockSize-1)/blockSize <= i.sim.KVCache.TotalCapacity()
+}
+
 // PreemptionCount returns the cumulative number of preemption events on this instance.
 func (i *InstanceSimulator) PreemptionCount() int64 {
 	return i.sim.Metrics.PreemptionCount
 }
 
+// RecentTTFTP99 returns the windowed P99 TTFT (μs) over this instance's most
+// recent completions. Returns 0 when no request has completed.
+func (i *InstanceSimulator) RecentTTFTP99() float64 {
+	if i.sim == nil {
+		return 0
+	}
+	return i.sim.RecentTTFTP99()
+}
+
+// RecentPreemptionRate returns this instance's preemptions per second over the
+// last sim.RecentPreemptionWindowUs. Returns 0 for an unconstructed instance.
+func (i *InstanceSimulator) RecentPreemptionRate() float64 {
+	if i.sim == nil {
+		return 0
+	}
+	return i.sim.RecentPreemptionRate()
+}
+
 // InstanceLatencyStats holds cumulative averages of per-instance latency and throughput from completed requests.
 // Units: TTFT and ITL are in microseconds (ticks = µs in the simulator clock).
 // DispatchRate is in req/s; AvgInTokens and AvgOutTokens are per-request averages.
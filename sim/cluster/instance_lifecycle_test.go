@@ -126,6 +126,69 @@ func TestInstanceLifecycle_ImmediateDrain(t *testing.T) {
 	})
 }
 
+// TestClusterSimulator_DrainInstance_MidRun drains one of three round-robin
+// instances when the 30th request arrives and verifies that no request routed
+// afterward lands on it, while the requests it already held — some still
+// running at the drain — complete normally.
+func TestClusterSimulator_DrainInstance_MidRun(t *testing.T) {
+	const numReqs, drainAfter, drained = 60, 30, "instance_1"
+	cfg := newTestDeploymentConfig(3)
+	reqs := testGenerateRequests(42, 1_000_000_000, 100.0/1e6, numReqs, 0,
+		64, 0, 64, 64, 128, 0, 128, 128)
+	cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+
+	arrivals := 0
+	drainTick := int64(-1)
+	cs.SetArrivalHook(func(req *sim.Request) {
+		arrivals++
+		if arrivals == drainAfter {
+			if err := cs.DrainInstance(drained); err != nil {
+				t.Fatalf("DrainInstance: %v", err)
+			}
+			drainTick = cs.Clock()
+		}
+	})
+	mustRun(t, cs)
+	if drainTick < 0 {
+		t.Fatal("drain never triggered")
+	}
+
+	m := cs.AggregatedMetrics()
+	if m.CompletedRequests != numReqs {
+		t.Errorf("CompletedRequests = %d, want %d (drain must not fail in-flight work)", m.CompletedRequests, numReqs)
+	}
+	held, finishedAfterDrain := 0, 0
+	for _, req := range reqs {
+		if req.AssignedInstance != drained {
+			continue
+		}
+		if req.ArrivalTime > drainTick {
+			t.Errorf("request %s arrived at %d, after the drain at %d, but was routed to %s", req.ID, req.ArrivalTime, drainTick, drained)
+		}
+		held++
+		if _, ok := m.RequestE2Es[req.ID]; !ok {
+			t.Errorf("request %s on the drained instance did not complete", req.ID)
+		}
+		if m.RequestCompletionTimes[req.ID] > float64(drainTick) {
+			finishedAfterDrain++
+		}
+	}
+	if held == 0 || finishedAfterDrain == 0 {
+		t.Fatalf("drained instance held %d requests, %d finishing after the drain; want both > 0", held, finishedAfterDrain)
+	}
+	for _, inst := range cs.Instances() {
+		if string(inst.ID()) == drained && inst.State != sim.InstanceStateTerminated {
+			t.Errorf("drained instance state = %q, want Terminated once idle", inst.State)
+		}
+	}
+	if err := cs.DrainInstance(drained); err == nil {
+		t.Error("draining a terminated instance: want error")
+	}
+	if err := cs.DrainInstance("instance_9"); err == nil {
+		t.Error("draining an unknown instance: want error")
+	}
+}
+
 // TestInstanceLifecycle_RedirectDrainPreservesConservation verifies that DrainRedirect
 // policy preserves INV-1 (request conservation) when requests are actually in the
 // source WaitQ at drain time.
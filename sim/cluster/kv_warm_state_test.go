@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// prefixedRequest builds a request whose 64-token prompt prefix is shared by
+// every request with the same group, followed by a 32-token suffix keyed by seq.
+func prefixedRequest(id string, arrival int64, group, seq int) *sim.Request {
+	input := make([]sim.TokenID, 0, 96)
+	for i := 0; i < 64; i++ {
+		input = append(input, sim.TokenID(group*1000+i))
+	}
+	for i := 0; i < 32; i++ {
+		input = append(input, sim.TokenID(1_000_000+seq*100+i))
+	}
+	return &sim.Request{
+		ID:           id,
+		ArrivalTime:  arrival,
+		InputTokens:  input,
+		OutputTokens: make([]sim.TokenID, 8),
+		State:        sim.StateQueued,
+	}
+}
+
+// GIVEN workload A run on two round-robin instances, each caching one prefix group,
+// and its exported KV prefixes
+// WHEN workload B — new requests sharing A's prefixes — runs cold and warm-started
+// from the export
+// THEN B's first requests get prefix-cache hits only when warm-started, and finish
+// their prefill sooner
+func TestKVWarmState_ExportImport_FirstMatchingRequestsHit(t *testing.T) {
+	newConfig := func() DeploymentConfig {
+		cfg := newTestDeploymentConfig(2)
+		cfg.RoutingPolicy = "round-robin"
+		return cfg
+	}
+
+	var workloadA []*sim.Request
+	for i := 0; i < 4; i++ {
+		workloadA = append(workloadA, prefixedRequest(fmt.Sprintf("a_%d", i), int64(i)*100_000, i%2, i))
+	}
+	csA := NewClusterSimulator(newConfig(), NewSliceRequestSource(workloadA), nil)
+	mustRun(t, csA)
+	exported := csA.ExportKVPrefixes()
+	if len(exported) != 2 {
+		t.Fatalf("exported %d instances, want 2", len(exported))
+	}
+	for _, inst := range exported {
+		if len(inst.Prefixes) == 0 {
+			t.Fatalf("%s exported no prefixes", inst.InstanceID)
+		}
+	}
+
+	runB := func(warm []InstanceKVPrefixes) *ClusterSimulator {
+		workloadB := []*sim.Request{prefixedRequest("b_0", 0, 0, 10), prefixedRequest("b_1", 10, 1, 11)}
+		cfg := newConfig()
+		cfg.KVWarmState = warm
+		cs := NewClusterSimulator(cfg, NewSliceRequestSource(workloadB), nil)
+		mustRun(t, cs)
+		return cs
+	}
+	cold := runB(nil)
+	warm := runB(exported)
+
+	for i, m := range warm.PerInstanceMetrics() {
+		if m.CacheHitRate <= 0 {
+			t.Errorf("warm instance_%d: cache hit rate %.3f, want > 0", i, m.CacheHitRate)
+		}
+	}
+	for i, m := range cold.PerInstanceMetrics() {
+		if m.CacheHitRate != 0 {
+			t.Errorf("cold instance_%d: cache hit rate %.3f, want 0", i, m.CacheHitRate)
+		}
+	}
+	for _, id := range []string{"b_0", "b_1"} {
+		coldTTFT, warmTTFT := cold.AggregatedMetrics().RequestTTFTs[id], warm.AggregatedMetrics().RequestTTFTs[id]
+		if warmTTFT >= coldTTFT {
+			t.Errorf("%s: warm TTFT %.0f not below cold TTFT %.0f", id, warmTTFT, coldTTFT)
+		}
+	}
+}
@@ -27,6 +27,7 @@ func sortedKeys(m map[string]float64) []string {
 type Distribution struct {
 	Mean  float64
 	P50   float64
+	P90   float64
 	P95   float64
 	P99   float64
 	Min   float64
@@ -52,6 +53,7 @@ func NewDistribution(values []float64) Distribution {
 	return Distribution{
 		Mean:  sum / float64(len(sorted)),
 		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
 		P95:   percentile(sorted, 95),
 		P99:   percentile(sorted, 99),
 		Min:   sorted[0],
@@ -95,20 +97,29 @@ type RawMetrics struct {
 	// Per-SLO-class distributions (PR10: keyed by SLOClass string)
 	PerSLOClass map[string]*SLOMetrics
 
+	// Running-batch size at each step, pooled over instances
+	// (Metrics.NumRunningBatchRequests).
+	BatchSize Distribution
+
 	// Throughput
 	RequestsPerSec float64
 	TokensPerSec   float64
 
+	// SLO-meeting throughput. Zero-valued unless the caller populates it via
+	// ComputeGoodput (requires goodput SLO targets).
+	Goodput Goodput
+
 	// Anomaly counters
 	PriorityInversions   int
 	HOLBlockingEvents    int
 	RejectedRequests     int            // admission rejections
+	AdmissionRetries     int            // admission re-attempts by rejected requests (retry model; 0 when disabled)
 	ShedByTier                map[string]int // per-SLOClass breakdown of all shedding events: admission rejections + gateway queue evictions (unconditional)
 	// InjectedByClass: per-SLOClass arrival counter; populated by ClusterArrivalEvent.Execute
 	// before any drop/route decision. Used as the goodput denominator (issue #1409, BC-6).
 	// Empty SLOClass requests appear under the "" key.
 	InjectedByClass map[string]int64
-	// INV-1 extended: injected == completed + running + queued + routing_rejections + dropped + timed_out + gw_depth + gw_shed + gw_rejected + gw_evicted + gw_expired + encode_routing_rejections
+	// INV-1 extended: injected == completed + running + queued + routing_rejections + dropped + timed_out + gw_depth + gw_shed + gw_rejected + gw_evicted + gw_expired + encode_routing_rejections + failed
 	GatewayQueueDepth       int // Requests still in gateway queue at horizon (issue #882)
 	GatewayQueueShed        int // Requests shed (evicted victims) from gateway queue (issue #882)
 	GatewayQueueRejected    int // Requests rejected from gateway queue — incoming could not displace any entry (#1190)
@@ -117,14 +128,25 @@ type RawMetrics struct {
 	RoutingRejections       int // I13: routing rejections (no routable instances)
 	EncodeRoutingRejections int // GAP-4 (#1264): encode pool routing rejections (no routable encode instances)
 	DroppedUnservable       int
+	DroppedUnservableByReason map[string]int // DroppedUnservable split by sim.Unservable* reason
 	LengthCappedRequests    int
 	TimedOutRequests        int
+	FailedRequests          int // requests lost to an injected instance failure
 
 	// KV cache metrics (PR12)
 	CacheHitRate    float64
 	PreemptionRate  float64
 	KVThrashingRate float64
 
+	// Prefix-cache hit rate pooled per tenant and per SLO class (untagged
+	// requests under ""). Nil when no cache lookup occurred.
+	CacheHitRateByTenant   map[string]float64
+	CacheHitRateBySLOClass map[string]float64
+
+	// Instance load imbalance over per-instance completed-request counts.
+	// Zero-valued when perInstance is nil.
+	LoadImbalance LoadImbalance
+
 	// PD disaggregation metrics (PR4). Nil when disaggregation is not active.
 	PD *PDMetrics
 }
@@ -141,6 +163,7 @@ func CollectRawMetrics(aggregated *sim.Metrics, perInstance []*sim.Metrics, reje
 		RoutingRejections:       routingRejections,
 		EncodeRoutingRejections: encodeRoutingRejections,
 		DroppedUnservable:       aggregated.DroppedUnservable,
+		DroppedUnservableByReason: aggregated.DroppedUnservableByReason,
 		LengthCappedRequests:    aggregated.LengthCappedRequests,
 		TimedOutRequests:        aggregated.TimedOutRequests,
 	}
@@ -160,6 +183,12 @@ func CollectRawMetrics(aggregated *sim.Metrics, perInstance []*sim.Metrics, reje
 	e2eValues := mapValues(aggregated.RequestE2Es)
 	raw.E2E = NewDistribution(e2eValues)
 
+	batchSizes := make([]float64, len(aggregated.NumRunningBatchRequests))
+	for i, n := range aggregated.NumRunningBatchRequests {
+		batchSizes[i] = float64(n)
+	}
+	raw.BatchSize = NewDistribution(batchSizes)
+
 	// Throughput
 	if aggregated.SimEndedTime > 0 && aggregated.CompletedRequests > 0 {
 		durationSec := float64(aggregated.SimEndedTime) / 1e6
@@ -167,6 +196,9 @@ func CollectRawMetrics(aggregated *sim.Metrics, perInstance []*sim.Metrics, reje
 		raw.TokensPerSec = float64(aggregated.TotalOutputTokens) / durationSec
 	}
 
+	raw.CacheHitRateByTenant = sim.CacheHitRates(aggregated.CacheHitCountsByTenant)
+	raw.CacheHitRateBySLOClass = sim.CacheHitRates(aggregated.CacheHitCountsBySLOClass)
+
 	// Anomaly detection
 	if perInstance != nil {
 		raw.PriorityInversions = detectPriorityInversions(perInstance, scheduler)
@@ -190,6 +222,8 @@ func CollectRawMetrics(aggregated *sim.Metrics, perInstance []*sim.Metrics, reje
 			raw.CacheHitRate = cacheHitSum / float64(count)
 			raw.KVThrashingRate = thrashingSum / float64(count)
 		}
+
+		raw.LoadImbalance = ComputeLoadImbalance(perInstance)
 	}
 
 	return raw
@@ -574,6 +608,129 @@ func SLOAttainmentMultiDim(
 	return overall, perClass
 }
 
+// Goodput is SLO-meeting throughput: completed requests per second that met
+// every configured SLO dimension of their class.
+type Goodput struct {
+	RPS         float64            // over all configured classes
+	PerClassRPS map[string]float64 // keyed by configured class; nil when no targets
+}
+
+// ComputeGoodput counts the completed requests in results that met every
+// non-zero dimension (TTFT, ITL, E2E) of their class's target and divides by
+// durationSec. Class lookup follows SLOAttainmentMultiDim: an empty SLOClass
+// maps to "default", and requests of unconfigured classes never count.
+// Returns a zero Goodput when targets is empty or durationSec is not positive.
+func ComputeGoodput(results map[string]RequestLatency, targets map[string]workload.SLODimTargets, durationSec float64) Goodput {
+	if len(targets) == 0 || durationSec <= 0 {
+		return Goodput{}
+	}
+	// Goodput needs only the numerators; the injected denominator is irrelevant.
+	_, perClass := SLOAttainmentMultiDim(results, nil, targets)
+	g := Goodput{PerClassRPS: make(map[string]float64, len(perClass))}
+	totalGood := 0
+	for cls, sca := range perClass {
+		g.PerClassRPS[cls] = float64(sca.Good) / durationSec
+		totalGood += sca.Good
+	}
+	g.RPS = float64(totalGood) / durationSec
+	return g
+}
+
+// SoftDeadlineDim is the soft-deadline view of one SLO dimension for one class:
+// the fraction of completed requests within budget, plus the class's P50/P90/P99
+// latency and whether each percentile is itself within budget. Latencies are in ms.
+type SoftDeadlineDim struct {
+	BudgetMs   float64 `json:"budget_ms"`
+	Attainment float64 `json:"attainment"`
+	P50Ms      float64 `json:"p50_ms"`
+	P90Ms      float64 `json:"p90_ms"`
+	P99Ms      float64 `json:"p99_ms"`
+	P50Met     bool    `json:"p50_met"`
+	P90Met     bool    `json:"p90_met"`
+	P99Met     bool    `json:"p99_met"`
+}
+
+// SoftDeadlineClass is the soft-deadline report for one SLO class. TTFT and E2E
+// are nil when the class has no budget for that dimension.
+type SoftDeadlineClass struct {
+	Completed int              `json:"completed"`
+	TTFT      *SoftDeadlineDim `json:"ttft,omitempty"`
+	E2E       *SoftDeadlineDim `json:"e2e,omitempty"`
+}
+
+// ComputeSoftDeadlineReport evaluates each configured class's TTFT and E2E
+// budgets against the completed requests in results. Unlike goodput, a miss is
+// not a failure: the report records how much of the latency distribution fits
+// the budget. Class lookup follows SLOAttainmentMultiDim (empty SLOClass maps to
+// "default"; unconfigured classes are ignored). Requests without a TTFT are
+// excluded from the TTFT dimension only. Percentiles use linear interpolation.
+// Returns an empty map when targets is empty.
+func ComputeSoftDeadlineReport(results map[string]RequestLatency, targets map[string]workload.SLODimTargets) map[string]SoftDeadlineClass {
+	report := make(map[string]SoftDeadlineClass, len(targets))
+	if len(targets) == 0 {
+		return report
+	}
+	ttfts := make(map[string][]float64, len(targets))
+	e2es := make(map[string][]float64, len(targets))
+	completed := make(map[string]int, len(targets))
+	for _, rl := range results {
+		cls := rl.Class
+		if cls == "" {
+			cls = "default"
+		}
+		if _, ok := targets[cls]; !ok {
+			continue
+		}
+		completed[cls]++
+		e2es[cls] = append(e2es[cls], rl.E2EMs)
+		if rl.HasTTFT {
+			ttfts[cls] = append(ttfts[cls], rl.TTFTMs)
+		}
+	}
+	classes := make([]string, 0, len(targets))
+	for cls := range targets {
+		classes = append(classes, cls)
+	}
+	sort.Strings(classes)
+	for _, cls := range classes {
+		t := targets[cls]
+		entry := SoftDeadlineClass{Completed: completed[cls]}
+		if t.TTFTMs > 0 {
+			entry.TTFT = softDeadlineDim(ttfts[cls], t.TTFTMs)
+		}
+		if t.E2EMs > 0 {
+			entry.E2E = softDeadlineDim(e2es[cls], t.E2EMs)
+		}
+		report[cls] = entry
+	}
+	return report
+}
+
+// softDeadlineDim sorts values in place (R2: results map order must not leak
+// into the percentiles) and scores them against budgetMs.
+func softDeadlineDim(values []float64, budgetMs float64) *SoftDeadlineDim {
+	sort.Float64s(values)
+	met := 0
+	for _, v := range values {
+		if v <= budgetMs {
+			met++
+		}
+	}
+	d := &SoftDeadlineDim{
+		BudgetMs:   budgetMs,
+		Attainment: safeFrac(met, len(values)),
+		P50Ms:      percentile(values, 50),
+		P90Ms:      percentile(values, 90),
+		P99Ms:      percentile(values, 99),
+	}
+	if len(values) > 0 {
+		d.P50Met = d.P50Ms <= budgetMs
+		d.P90Met = d.P90Ms <= budgetMs
+		d.P99Met = d.P99Ms <= budgetMs
+	}
+	return d
+}
+
 func safeFrac(num, denom int) float64 {
 	if denom == 0 {
 		return 0
@@ -602,6 +759,61 @@ func JainFairnessIndex(throughputs map[string]float64) float64 {
 	return (sumX * sumX) / (n * sumX2)
 }
 
+// LoadImbalance summarizes how unevenly completed requests are spread across instances.
+type LoadImbalance struct {
+	// Gini is the Gini coefficient of per-instance completed-request counts.
+	// 0 means perfectly balanced; the maximum for N instances is 1 - 1/N
+	// (all completions on a single instance).
+	Gini float64
+	// MaxMeanRatio is max / mean of per-instance completed-request counts.
+	// 1.0 means perfectly balanced; N means all completions on a single instance.
+	MaxMeanRatio float64
+}
+
+// ComputeLoadImbalance computes the Gini coefficient and max/mean ratio of
+// per-instance completed-request counts. Instances with zero completions are
+// included — an idle instance next to a saturated one IS imbalance (same
+// rationale as detectHOLBlocking, #291).
+// Returns a zero-value LoadImbalance when there are no instances or no completions.
+func ComputeLoadImbalance(perInstance []*sim.Metrics) LoadImbalance {
+	n := len(perInstance)
+	if n == 0 {
+		return LoadImbalance{}
+	}
+	counts := make([]int, n)
+	total := 0
+	maxCount := 0
+	for i, m := range perInstance {
+		counts[i] = m.CompletedRequests
+		total += m.CompletedRequests
+		if m.CompletedRequests > maxCount {
+			maxCount = m.CompletedRequests
+		}
+	}
+	if total == 0 {
+		return LoadImbalance{}
+	}
+	sort.Ints(counts)
+
+	// Gini over sorted values (1-indexed rank i):
+	//   G = 2·Σ(i·x_i) / (n·Σx_i) − (n+1)/n
+	weighted := 0.0
+	for i, c := range counts {
+		weighted += float64(i+1) * float64(c)
+	}
+	nf := float64(n)
+	gini := 2.0*weighted/(nf*float64(total)) - (nf+1.0)/nf
+	if gini < 0 {
+		gini = 0 // clamp float rounding on perfectly balanced input
+	}
+
+	mean := float64(total) / nf
+	return LoadImbalance{
+		Gini:         gini,
+		MaxMeanRatio: float64(maxCount) / mean,
+	}
+}
+
 // mapValues extracts values from a map into a slice.
 func mapValues(m map[string]float64) []float64 {
 	vals := make([]float64, 0, len(m))
@@ -615,7 +827,7 @@ func mapValues(m map[string]float64) []float64 {
 // Returns a fresh slice each call to prevent mutation of shared state.
 func validFitnessKeysList() []string {
 	return []string{
-		"throughput", "tokens_per_sec",
+		"throughput", "tokens_per_sec", "goodput",
 		"p99_ttft", "p50_ttft", "mean_ttft",
 		"p99_e2e", "p50_e2e", "mean_e2e",
 	}
@@ -675,6 +887,8 @@ func extractMetric(m *RawMetrics, key string) (float64, bool) {
 		return m.RequestsPerSec / (m.RequestsPerSec + referenceRPS), true
 	case "tokens_per_sec":
 		return m.TokensPerSec / (m.TokensPerSec + referenceTPS), true
+	case "goodput":
+		return m.Goodput.RPS / (m.Goodput.RPS + referenceRPS), true
 	// Lower is better — normalized via 1 / (1 + value/reference)
 	case "p99_ttft":
 		return 1.0 / (1.0 + m.TTFT.P99/referenceTicks), true
@@ -775,14 +989,18 @@ func ComputePerModelMetrics(aggregated *sim.Metrics) map[string]*ModelMetrics {
 }
 
 // TenantMetrics holds post-simulation aggregates for a single tenant.
+// Scheduling delay is arrival to the (latest) schedule of each completed
+// request (sim.Metrics.RequestSchedulingDelays), in milliseconds.
 type TenantMetrics struct {
-	TenantID          string `json:"tenant_id"`
-	CompletedRequests int    `json:"completed_requests"`
-	TotalTokensServed int    `json:"total_tokens_served"`
+	TenantID              string  `json:"tenant_id"`
+	CompletedRequests     int     `json:"completed_requests"`
+	TotalTokensServed     int     `json:"total_tokens_served"`
+	SchedulingDelayMeanMs float64 `json:"scheduling_delay_mean_ms"`
+	SchedulingDelayP99Ms  float64 `json:"scheduling_delay_p99_ms"`
 }
 
 // ComputePerTenantMetrics partitions completed requests by TenantID and accumulates
-// per-tenant request counts and output token totals.
+// per-tenant request counts, output token totals, and scheduling-delay mean/P99.
 // Returns nil when no completed request has a non-empty TenantID (backward-compatible —
 // section absent for legacy/untenanted workloads). A workload with a single named tenant
 // returns a one-entry map; Jain=1.0 is correct and intentional — do not add a len<=1 guard.
@@ -790,6 +1008,7 @@ type TenantMetrics struct {
 // same two-map join used by ComputePerModelMetrics (R2: sorted keys in caller).
 func ComputePerTenantMetrics(aggregated *sim.Metrics) map[string]*TenantMetrics {
 	result := make(map[string]*TenantMetrics)
+	delaysByTenant := make(map[string][]float64)
 
 	for reqID := range aggregated.RequestE2Es {
 		req, ok := aggregated.Requests[reqID]
@@ -803,14 +1022,36 @@ func ComputePerTenantMetrics(aggregated *sim.Metrics) map[string]*TenantMetrics
 		}
 		tm.CompletedRequests++
 		tm.TotalTokensServed += req.NumDecodeTokens
+		if delay, ok := aggregated.RequestSchedulingDelays[reqID]; ok {
+			delaysByTenant[req.TenantID] = append(delaysByTenant[req.TenantID], float64(delay)/1e3)
+		}
 	}
 
 	if len(result) == 0 {
 		return nil
 	}
+	// NewDistribution sorts its input, so map-order accumulation above is harmless (R2).
+	for tenantID, delays := range delaysByTenant {
+		d := NewDistribution(delays)
+		result[tenantID].SchedulingDelayMeanMs = d.Mean
+		result[tenantID].SchedulingDelayP99Ms = d.P99
+	}
 	return result
 }
 
+// SchedulingDelayFairnessIndex is JainFairnessIndex over per-tenant mean
+// scheduling delays: 1.0 when every tenant waits equally long on average,
+// approaching 1/N as one tenant absorbs all the queueing. Unlike the token
+// index printed alongside it, it measures whether tenants experience similar
+// queueing, not similar service. Returns 0 for no tenants.
+func SchedulingDelayFairnessIndex(perTenant map[string]*TenantMetrics) float64 {
+	delays := make(map[string]float64, len(perTenant))
+	for tenantID, tm := range perTenant {
+		delays[tenantID] = tm.SchedulingDelayMeanMs
+	}
+	return JainFairnessIndex(delays)
+}
+
 // ParseFitnessWeights parses a "key:value,key:value" string into a weight map.
 // Returns empty map for empty input (EC-2). Returns error for malformed entries.
 func ParseFitnessWeights(s string) (map[string]float64, error) {
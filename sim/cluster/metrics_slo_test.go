@@ -386,3 +386,132 @@ func TestSLOAttainmentMultiDim_NoConfiguredClasses_ReturnsZero(t *testing.T) {
 		t.Errorf("perClass = %v, want empty", perClass)
 	}
 }
+
+func TestComputeGoodput_CountsOnlyCompliantRequestsOverDuration(t *testing.T) {
+	// GIVEN a 10 s run where critical has 6 compliant requests + 4 missing TTFT,
+	// batch has 3 compliant + 2 missing E2E, and an unconfigured class is all fast
+	m := makeMetricsForSLO(t, []struct {
+		class                string
+		count                int
+		ttftMs, itlMs, e2eMs float64
+	}{
+		{"critical", 6, 50, 30, 2000},
+		{"critical", 4, 500, 30, 2000},
+		{"batch", 3, 800, 30, 8000},
+		{"batch", 2, 800, 30, 20000},
+		{"free", 7, 1, 1, 1},
+	})
+	targets := map[string]workload.SLODimTargets{
+		"critical": {TTFTMs: 100, E2EMs: 5000},
+		"batch":    {TTFTMs: 1000, E2EMs: 10000},
+	}
+
+	// WHEN goodput is computed
+	g := ComputeGoodput(BuildLatencyResults(m), targets, 10)
+
+	// THEN goodput is compliant requests / duration, overall and per class
+	if math.Abs(g.RPS-0.9) > 1e-9 {
+		t.Errorf("RPS = %f, want 0.9 (9 compliant / 10 s)", g.RPS)
+	}
+	if math.Abs(g.PerClassRPS["critical"]-0.6) > 1e-9 {
+		t.Errorf("PerClassRPS[critical] = %f, want 0.6", g.PerClassRPS["critical"])
+	}
+	if math.Abs(g.PerClassRPS["batch"]-0.3) > 1e-9 {
+		t.Errorf("PerClassRPS[batch] = %f, want 0.3", g.PerClassRPS["batch"])
+	}
+	if _, ok := g.PerClassRPS["free"]; ok {
+		t.Error("PerClassRPS must not contain unconfigured class 'free'")
+	}
+	// Goodput never exceeds plain throughput (22 completions / 10 s).
+	if g.RPS > 2.2 {
+		t.Errorf("RPS = %f exceeds throughput 2.2", g.RPS)
+	}
+}
+
+func TestComputeGoodput_NoTargetsOrDuration_ReturnsZero(t *testing.T) {
+	m := makeMetricsForSLO(t, []struct {
+		class                string
+		count                int
+		ttftMs, itlMs, e2eMs float64
+	}{
+		{"A", 10, 50, 30, 2000},
+	})
+	results := BuildLatencyResults(m)
+	if g := ComputeGoodput(results, nil, 10); g.RPS != 0 || g.PerClassRPS != nil {
+		t.Errorf("no targets: got %+v, want zero Goodput", g)
+	}
+	if g := ComputeGoodput(results, map[string]workload.SLODimTargets{"A": {E2EMs: 5000}}, 0); g.RPS != 0 || g.PerClassRPS != nil {
+		t.Errorf("zero duration: got %+v, want zero Goodput", g)
+	}
+}
+
+func TestComputeSoftDeadlineReport_HandComputedFractions(t *testing.T) {
+	// critical: TTFT 10..100ms, E2E 100..1000ms (step 10/100), budgets 55/950.
+	// default: 4 empty-class requests without TTFT, E2E 100..400ms, budget 250.
+	// batch: unconfigured class, must be ignored.
+	results := make(map[string]RequestLatency)
+	for i := 1; i <= 10; i++ {
+		results[fmt.Sprintf("c%d", i)] = RequestLatency{
+			Class: "critical", TTFTMs: float64(10 * i), E2EMs: float64(100 * i), HasTTFT: true,
+		}
+	}
+	for i := 1; i <= 4; i++ {
+		results[fmt.Sprintf("d%d", i)] = RequestLatency{E2EMs: float64(100 * i)}
+	}
+	results["b1"] = RequestLatency{Class: "batch", TTFTMs: 1, E2EMs: 1, HasTTFT: true}
+	targets := map[string]workload.SLODimTargets{
+		"critical": {TTFTMs: 55, E2EMs: 950},
+		"default":  {E2EMs: 250},
+	}
+
+	report := ComputeSoftDeadlineReport(results, targets)
+	if len(report) != 2 {
+		t.Fatalf("report classes = %d, want 2 (batch is unconfigured)", len(report))
+	}
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	crit := report["critical"]
+	if crit.Completed != 10 || crit.TTFT == nil || crit.E2E == nil {
+		t.Fatalf("critical = %+v, want 10 completed with TTFT and E2E", crit)
+	}
+	// TTFT: 5 of 10 within 55ms; P50 = 55 (met), P90 = 91, P99 = 99.1.
+	approx("critical TTFT attainment", crit.TTFT.Attainment, 0.5)
+	approx("critical TTFT P50", crit.TTFT.P50Ms, 55)
+	approx("critical TTFT P90", crit.TTFT.P90Ms, 91)
+	approx("critical TTFT P99", crit.TTFT.P99Ms, 99.1)
+	if !crit.TTFT.P50Met || crit.TTFT.P90Met || crit.TTFT.P99Met {
+		t.Errorf("critical TTFT met = %v/%v/%v, want true/false/false", crit.TTFT.P50Met, crit.TTFT.P90Met, crit.TTFT.P99Met)
+	}
+	// E2E: 9 of 10 within 950ms; P50 = 550, P90 = 910 (met), P99 = 991.
+	approx("critical E2E attainment", crit.E2E.Attainment, 0.9)
+	approx("critical E2E P50", crit.E2E.P50Ms, 550)
+	approx("critical E2E P90", crit.E2E.P90Ms, 910)
+	approx("critical E2E P99", crit.E2E.P99Ms, 991)
+	if !crit.E2E.P50Met || !crit.E2E.P90Met || crit.E2E.P99Met {
+		t.Errorf("critical E2E met = %v/%v/%v, want true/true/false", crit.E2E.P50Met, crit.E2E.P90Met, crit.E2E.P99Met)
+	}
+
+	def := report["default"]
+	if def.Completed != 4 || def.TTFT != nil || def.E2E == nil {
+		t.Fatalf("default = %+v, want 4 completed with E2E only", def)
+	}
+	// E2E: 2 of 4 within 250ms; P50 = 250 (met at the boundary), P99 = 397.
+	approx("default E2E attainment", def.E2E.Attainment, 0.5)
+	approx("default E2E P50", def.E2E.P50Ms, 250)
+	approx("default E2E P99", def.E2E.P99Ms, 397)
+	if !def.E2E.P50Met || def.E2E.P99Met {
+		t.Errorf("default E2E met P50/P99 = %v/%v, want true/false", def.E2E.P50Met, def.E2E.P99Met)
+	}
+}
+
+func TestComputeSoftDeadlineReport_NoTargets_Empty(t *testing.T) {
+	results := map[string]RequestLatency{"r": {E2EMs: 1}}
+	if got := ComputeSoftDeadlineReport(results, nil); len(got) != 0 {
+		t.Errorf("report = %v, want empty", got)
+	}
+}
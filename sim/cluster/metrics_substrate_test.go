@@ -26,8 +26,8 @@ func msClusterConfig(numInstances int) DeploymentConfig {
 			KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(256, 100000, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{5000, 10, 3}, []float64{1000, 2, 500}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "test-gpu", 1, 1, false, "", "roofline", 0),
-			PolicyConfig:        sim.NewPolicyConfig("fcfs", ""),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "test-gpu", 1, 1, false, "", "roofline", 0, ""),
+			PolicyConfig:        sim.NewPolicyConfig("fcfs", "", ""),
 		},
 		NumInstances:    numInstances,
 		RoutingPolicy:   "round-robin",
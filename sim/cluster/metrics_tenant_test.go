@@ -224,3 +224,61 @@ func TestComputePerTenantMetrics_OrphanedE2E_Skipped(t *testing.T) {
 		t.Errorf("expected exactly 1 tenant entry (alice), got %d", len(result))
 	}
 }
+
+// BC-T9: Per-tenant scheduling delays diverge under FCFS and converge under WFQ.
+//
+// GIVEN alice's 40-request burst lands one tick before bob's equal burst on a
+// single instance that runs 4 requests at a time
+// WHEN the workload runs under fcfs and under wfq
+// THEN under fcfs bob's mean scheduling delay far exceeds alice's and the
+// delay fairness index is low, while under wfq the means are close and the
+// index is near 1
+func TestComputePerTenantMetrics_SchedulingDelayFairness_FCFSvsWFQ(t *testing.T) {
+	const perTenant = 40
+	run := func(scheduler string) map[string]*TenantMetrics {
+		reqs := make([]*sim.Request, 0, 2*perTenant)
+		for i := 0; i < perTenant; i++ {
+			reqs = append(reqs, newTenantRequest(fmt.Sprintf("alice_%02d", i), 0, "alice", ""))
+		}
+		for i := 0; i < perTenant; i++ {
+			reqs = append(reqs, newTenantRequest(fmt.Sprintf("bob_%02d", i), 1, "bob", ""))
+		}
+		cfg := newTestDeploymentConfig(1)
+		cfg.BatchConfig = sim.NewBatchConfig(4, 2048, 0)
+		cfg.Scheduler = scheduler
+		cs := NewClusterSimulator(cfg, NewSliceRequestSource(reqs), nil)
+		mustRun(t, cs)
+		if got := cs.AggregatedMetrics().CompletedRequests; got != 2*perTenant {
+			t.Fatalf("%s: completed %d, want %d", scheduler, got, 2*perTenant)
+		}
+		return ComputePerTenantMetrics(cs.AggregatedMetrics())
+	}
+
+	fcfs := run("fcfs")
+	wfq := run("wfq")
+
+	fcfsRatio := fcfs["bob"].SchedulingDelayMeanMs / fcfs["alice"].SchedulingDelayMeanMs
+	if fcfsRatio < 2 {
+		t.Errorf("fcfs: bob/alice mean delay ratio %.2f, want >= 2 (bob queues behind alice's burst)", fcfsRatio)
+	}
+	if fcfs["bob"].SchedulingDelayP99Ms <= fcfs["alice"].SchedulingDelayMeanMs {
+		t.Errorf("fcfs: bob P99 %.2fms not above alice mean %.2fms",
+			fcfs["bob"].SchedulingDelayP99Ms, fcfs["alice"].SchedulingDelayMeanMs)
+	}
+	wfqRatio := wfq["bob"].SchedulingDelayMeanMs / wfq["alice"].SchedulingDelayMeanMs
+	if wfqRatio < 0.8 || wfqRatio > 1.25 {
+		t.Errorf("wfq: bob/alice mean delay ratio %.2f, want within [0.8, 1.25]", wfqRatio)
+	}
+
+	fcfsIdx, wfqIdx := SchedulingDelayFairnessIndex(fcfs), SchedulingDelayFairnessIndex(wfq)
+	if wfqIdx < 0.98 {
+		t.Errorf("wfq delay fairness index %.4f, want >= 0.98", wfqIdx)
+	}
+	if fcfsIdx >= wfqIdx {
+		t.Errorf("delay fairness index: fcfs %.4f, wfq %.4f; want fcfs lower", fcfsIdx, wfqIdx)
+	}
+	// Scheduling order does not change how much each tenant is served.
+	if fcfs["alice"].TotalTokensServed != wfq["alice"].TotalTokensServed {
+		t.Errorf("alice tokens: fcfs %d, wfq %d; want equal", fcfs["alice"].TotalTokensServed, wfq["alice"].TotalTokensServed)
+	}
+}
@@ -150,6 +150,48 @@ func TestCollectRawMetrics_DroppedUnservable(t *testing.T) {
 	}
 }
 
+// runBatchSizeWorkload runs 60 requests on one instance at a mean rate of one
+// per 50ms, arriving in groups of burst, and returns the running-batch size
+// distribution.
+func runBatchSizeWorkload(t *testing.T, burst int) Distribution {
+	t.Helper()
+	requests := newTestRequests(60)
+	for i, req := range requests {
+		req.ArrivalTime = int64(i/burst) * int64(burst) * 50_000
+	}
+	cs := NewClusterSimulator(newTestDeploymentConfig(1), NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	raw := CollectRawMetrics(cs.AggregatedMetrics(), cs.PerInstanceMetrics(), 0, "", 0, 0, nil)
+	if raw.BatchSize.Count != len(cs.AggregatedMetrics().NumRunningBatchRequests) {
+		t.Fatalf("BatchSize.Count = %d, want one sample per step (%d)", raw.BatchSize.Count, len(cs.AggregatedMetrics().NumRunningBatchRequests))
+	}
+	return raw.BatchSize
+}
+
+// TestCollectRawMetrics_BatchSize_BurstyWideSteadyNarrow verifies that at the
+// same mean arrival rate, bursts of 20 requests spread the per-step batch size
+// from 1 up to the burst size (high P99), while evenly spaced arrivals keep it
+// in a narrow band around the mean.
+func TestCollectRawMetrics_BatchSize_BurstyWideSteadyNarrow(t *testing.T) {
+	steady := runBatchSizeWorkload(t, 1)
+	bursty := runBatchSizeWorkload(t, 20)
+	t.Logf("steady: %+v", steady)
+	t.Logf("bursty: %+v", bursty)
+
+	if steady.P99-steady.P50 > 2 {
+		t.Errorf("steady: P50 = %.1f, P99 = %.1f, want a narrow distribution (P99 - P50 <= 2)", steady.P50, steady.P99)
+	}
+	if bursty.P99 < 15 || bursty.Min > 1 {
+		t.Errorf("bursty: Min = %.1f, P99 = %.1f, want batches from 1 up to near the burst size of 20", bursty.Min, bursty.P99)
+	}
+	if bursty.P99 < 3*steady.P99 {
+		t.Errorf("bursty P99 = %.1f, want at least 3x steady P99 (%.1f)", bursty.P99, steady.P99)
+	}
+	if !(bursty.P50 <= bursty.P90 && bursty.P90 <= bursty.P99) {
+		t.Errorf("bursty: P50 = %.1f, P90 = %.1f, P99 = %.1f, want non-decreasing percentiles", bursty.P50, bursty.P90, bursty.P99)
+	}
+}
+
 // TestComputeFitness_WeightedScore verifies BC-3.
 func TestComputeFitness_WeightedScore(t *testing.T) {
 	raw := &RawMetrics{
@@ -616,6 +658,81 @@ func TestPathological_AlwaysBusiest_CausesImbalance(t *testing.T) {
 	}
 }
 
+// TestLoadImbalance_AlwaysBusiest_HighGini verifies that concentrating all
+// traffic on one instance drives the Gini coefficient toward its 1-1/N maximum.
+func TestLoadImbalance_AlwaysBusiest_HighGini(t *testing.T) {
+	config := newTestDeploymentConfig(3)
+	config.RoutingPolicy = "always-busiest"
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(20)), nil)
+	mustRun(t, cs)
+
+	raw := CollectRawMetrics(cs.AggregatedMetrics(), cs.PerInstanceMetrics(), cs.RejectedRequests(), "", 0, 0, nil)
+
+	if raw.LoadImbalance.Gini < 0.5 {
+		t.Errorf("Gini = %.4f, want >= 0.5 for always-busiest routing (max for 3 instances is 0.667)", raw.LoadImbalance.Gini)
+	}
+	if raw.LoadImbalance.MaxMeanRatio < 2.0 {
+		t.Errorf("MaxMeanRatio = %.4f, want >= 2.0 for always-busiest routing", raw.LoadImbalance.MaxMeanRatio)
+	}
+}
+
+// TestLoadImbalance_RoundRobin_NearZeroGini verifies that an evenly divisible
+// round-robin workload produces a Gini near zero and a max/mean ratio near 1.
+func TestLoadImbalance_RoundRobin_NearZeroGini(t *testing.T) {
+	config := newTestDeploymentConfig(4)
+	config.RoutingPolicy = "round-robin"
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(newTestRequests(20)), nil)
+	mustRun(t, cs)
+
+	raw := CollectRawMetrics(cs.AggregatedMetrics(), cs.PerInstanceMetrics(), cs.RejectedRequests(), "", 0, 0, nil)
+
+	if raw.LoadImbalance.Gini > 0.05 {
+		t.Errorf("Gini = %.4f, want <= 0.05 for round-robin routing", raw.LoadImbalance.Gini)
+	}
+	if math.Abs(raw.LoadImbalance.MaxMeanRatio-1.0) > 0.05 {
+		t.Errorf("MaxMeanRatio = %.4f, want ~1.0 for round-robin routing", raw.LoadImbalance.MaxMeanRatio)
+	}
+}
+
+// TestComputeLoadImbalance_EdgeCases verifies the closed-form values and the
+// zero-value return for empty or idle clusters.
+func TestComputeLoadImbalance_EdgeCases(t *testing.T) {
+	withCompleted := func(counts ...int) []*sim.Metrics {
+		out := make([]*sim.Metrics, len(counts))
+		for i, c := range counts {
+			out[i] = sim.NewMetrics()
+			out[i].CompletedRequests = c
+		}
+		return out
+	}
+	tests := []struct {
+		name      string
+		perInst   []*sim.Metrics
+		wantGini  float64
+		wantRatio float64
+	}{
+		{"nil", nil, 0, 0},
+		{"all idle", withCompleted(0, 0, 0), 0, 0},
+		{"single instance", withCompleted(7), 0, 1},
+		{"balanced", withCompleted(5, 5, 5, 5), 0, 1},
+		{"all on one of four", withCompleted(0, 12, 0, 0), 0.75, 4},
+		{"two of four", withCompleted(10, 0, 10, 0), 0.5, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ComputeLoadImbalance(tc.perInst)
+			if math.Abs(got.Gini-tc.wantGini) > 1e-9 {
+				t.Errorf("Gini = %v, want %v", got.Gini, tc.wantGini)
+			}
+			if math.Abs(got.MaxMeanRatio-tc.wantRatio) > 1e-9 {
+				t.Errorf("MaxMeanRatio = %v, want %v", got.MaxMeanRatio, tc.wantRatio)
+			}
+		})
+	}
+}
+
 // TestJainFairnessIndex_AllZeroThroughputs_ReturnsPerfectFairness verifies BC-13:
 // all-zero throughputs means all tenants treated identically → perfectly fair.
 func TestJainFairnessIndex_AllZeroThroughputs_ReturnsPerfectFairness(t *testing.T) {
@@ -2,10 +2,15 @@
 package cluster
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/kv"
 )
 
 // newTestDeploymentConfigWithModel creates a test DeploymentConfig using the specified model.
@@ -17,7 +22,7 @@ func newTestDeploymentConfigWithModel(numInstances int, model string) Deployment
 		KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
 		BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
 		LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), model, "H100", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), model, "H100", 1, 1, false, "", "roofline", 0, ""),
 	}
 	return cfg
 }
@@ -258,3 +263,69 @@ func TestEvaluationResult_WithPerModelMetrics(t *testing.T) {
 		t.Errorf("PerModelMetrics[test-model].TotalRequests = %v, want 10", result.PerModelMetrics["test-model"])
 	}
 }
+
+// TestInstanceSimulator_MultiModelKV_MixedBlockSizes_ByteAccountingNoLeak
+// verifies that two models with different KV block sizes share one
+// instance's byte-denominated KV pool.
+func TestInstanceSimulator_MultiModelKV_MixedBlockSizes_ByteAccountingNoLeak(t *testing.T) {
+	// GIVEN an instance with a 4096-byte KV pool shared by a model with
+	// 16-token, 32-byte blocks and a model with 32-token, 128-byte blocks
+	cfg := newTestInstanceSimConfig()
+	cfg.KVCacheConfig = sim.NewMultiModelKVCacheConfig(4096, map[string]sim.ModelKVLayout{
+		"small": {BlockSizeTokens: 16, BytesPerToken: 2},
+		"large": {BlockSizeTokens: 32, BytesPerToken: 4},
+	})
+	inst := NewInstanceSimulator("mm", cfg)
+	mm, ok := inst.sim.KVCache.(*kv.MultiModelKVCache)
+	require.True(t, ok, "ModelKVLayouts must build a MultiModelKVCache")
+
+	// AND more requests of both models than the pool holds at once, with
+	// distinct tokens so no blocks are shared
+	n := 0
+	for i := 0; i < 8; i++ {
+		for _, model := range []string{"small", "large"} {
+			input := make([]sim.TokenID, 128)
+			for j := range input {
+				input[j] = sim.TokenID(n*1000 + j)
+			}
+			n++
+			inst.InjectRequest(&sim.Request{
+				ID:           fmt.Sprintf("%s-%d", model, i),
+				Model:        model,
+				ArrivalTime:  int64(i) * 10,
+				InputTokens:  input,
+				OutputTokens: make([]sim.TokenID, 64),
+				State:        sim.StateQueued,
+			})
+		}
+	}
+
+	// WHEN the instance runs event by event
+	inst.hasRun = true
+	var peakBytes int64
+	sawBothModels := false
+	for inst.HasPendingEvents() {
+		inst.ProcessNextEvent()
+
+		// THEN every in-use block is charged its own model's block bytes,
+		// and the pool never exceeds its capacity
+		smallBlocks, largeBlocks := mm.ModelUsedBlocks("small"), mm.ModelUsedBlocks("large")
+		require.Equal(t, smallBlocks*32+largeBlocks*128, mm.UsedBytes())
+		require.LessOrEqual(t, mm.UsedBytes(), mm.CapacityBytes())
+		peakBytes = max(peakBytes, mm.UsedBytes())
+		sawBothModels = sawBothModels || (smallBlocks > 0 && largeBlocks > 0)
+	}
+	inst.Finalize()
+
+	// AND both models held blocks at once, under byte pressure
+	assert.True(t, sawBothModels, "both models should hold KV blocks concurrently")
+	assert.Greater(t, peakBytes, mm.CapacityBytes()/2)
+	assert.Greater(t, inst.PreemptionCount(), int64(0))
+
+	// AND every request completes with no bytes or blocks leaked
+	assert.Equal(t, 16, inst.Metrics().CompletedRequests)
+	assert.Equal(t, int64(0), mm.UsedBytes())
+	assert.Equal(t, int64(0), mm.ModelUsedBlocks("small"))
+	assert.Equal(t, int64(0), mm.ModelUsedBlocks("large"))
+	assert.Equal(t, int64(0), inst.KvTokensInUse())
+}
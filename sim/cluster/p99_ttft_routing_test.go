@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// runHeterogeneousWeighted runs the 2-instance heterogeneous deployment
+// (instance_1 is ~10× slower) under weighted routing with the given scorers and
+// returns the cluster-wide P99 TTFT (ms) and the slow instance's completions.
+func runHeterogeneousWeighted(t *testing.T, scorers []sim.ScorerConfig) (ttftP99 float64, slowCompleted int) {
+	t.Helper()
+	config := newHeterogeneousTestConfig()
+	config.RoutingPolicy = "weighted"
+	config.RoutingScorerConfigs = scorers
+	requests := testGenerateRequests(42, math.MaxInt64, 100.0/1e6, 400,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	agg := cs.AggregatedMetrics()
+	if agg.CompletedRequests != len(requests) {
+		t.Fatalf("CompletedRequests = %d, want %d (INV-1)", agg.CompletedRequests, len(requests))
+	}
+	ttfts := make([]float64, 0, len(agg.RequestTTFTs))
+	for _, v := range agg.RequestTTFTs {
+		ttfts = append(ttfts, v)
+	}
+	sort.Float64s(ttfts)
+	return sim.CalculatePercentile(ttfts, 99), cs.PerInstanceMetricsByID()["instance_1"].CompletedRequests
+}
+
+// TestP99TTFTScorer_DivertsTrafficFromSlowInstance verifies that adding the
+// p99-ttft scorer to a queue-depth profile steers traffic away from an
+// artificially slow instance and lowers the cluster's P99 TTFT.
+func TestP99TTFTScorer_DivertsTrafficFromSlowInstance(t *testing.T) {
+	// GIVEN a baseline profile that only sees queue depth
+	baseP99, baseSlow := runHeterogeneousWeighted(t, []sim.ScorerConfig{
+		{Name: "queue-depth", Weight: 1},
+	})
+	// AND the same profile with the tail-latency scorer added
+	tailP99, tailSlow := runHeterogeneousWeighted(t, []sim.ScorerConfig{
+		{Name: "queue-depth", Weight: 1},
+		{Name: "p99-ttft", Weight: 1},
+	})
+
+	// THEN the slow instance receives markedly less traffic
+	if tailSlow*2 > baseSlow {
+		t.Errorf("slow instance completed %d with p99-ttft vs %d without; want at most half", tailSlow, baseSlow)
+	}
+	// AND cluster tail latency improves
+	if tailP99 >= baseP99 {
+		t.Errorf("cluster TTFT P99 = %.2fms with p99-ttft, want < baseline %.2fms", tailP99, baseP99)
+	}
+}
+
+// TestSnapshot_TTFTP99_TracksRecentCompletions verifies the snapshot provider
+// exposes the instance's windowed P99 TTFT once requests have completed.
+func TestSnapshot_TTFTP99_TracksRecentCompletions(t *testing.T) {
+	config := newTestDeploymentConfig(1)
+	requests := testGenerateRequests(42, math.MaxInt64, 10.0/1e6, 20,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	inst := cs.Instances()[0]
+	provider := NewCachedSnapshotProvider(map[InstanceID]*InstanceSimulator{inst.ID(): inst}, DefaultObservabilityConfig())
+	snap := provider.Snapshot(inst.ID(), inst.Clock())
+	if snap.TTFTP99 <= 0 {
+		t.Fatalf("TTFTP99 = %v after %d completions, want > 0", snap.TTFTP99, inst.Metrics().CompletedRequests)
+	}
+	maxTTFT := 0.0
+	for _, v := range inst.Metrics().RequestTTFTs {
+		maxTTFT = math.Max(maxTTFT, v)
+	}
+	if snap.TTFTP99 > maxTTFT {
+		t.Errorf("TTFTP99 = %v exceeds the largest observed TTFT %v", snap.TTFTP99, maxTTFT)
+	}
+}
@@ -38,6 +38,11 @@ func (e *PrefillRoutingEvent) Execute(cs *ClusterSimulator) {
 		policy = cs.routingPolicy
 	}
 	decision := policy.Route(e.request, state)
+	if decision.TargetInstance == "" {
+		logrus.Warnf("[cluster] prefill req %s: prefill routing chose no instance (%s) — request rejected at routing", e.request.ID, decision.Reason)
+		cs.routingRejections++
+		return
+	}
 
 	logrus.Debugf("[cluster] prefill req %s → instance %s", e.request.ID, decision.TargetInstance)
 
@@ -139,7 +144,10 @@ func (e *KVTransferStartedEvent) Execute(cs *ClusterSimulator) {
 		ArrivalTime:        orig.ArrivalTime,
 		TenantID:           orig.TenantID,
 		SLOClass:           orig.SLOClass,
+		ExplicitPriority:   orig.ExplicitPriority,
 		Model:              orig.Model,
+		NoCache:            orig.NoCache,
+		ParallelSamples:    orig.ParallelSamples,
 		IsDecodeSubRequest: true,
 	}
 
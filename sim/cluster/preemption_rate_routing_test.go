@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// runThrashingWeighted runs a 2-instance deployment where instance_1 has a tiny
+// KV cache (so it preempts under load) under weighted routing with the given
+// scorers, and returns the cluster-wide preemption count and instance_1's
+// completions.
+func runThrashingWeighted(t *testing.T, scorers []sim.ScorerConfig) (preemptions int64, thrashCompleted int) {
+	t.Helper()
+	config := newTestDeploymentConfig(2)
+	config.RoutingPolicy = "weighted"
+	config.RoutingScorerConfigs = scorers
+	tinyKV := int64(40)
+	config.InstanceOverrides = []InstanceOverride{
+		{}, // instance_0: global config
+		{PoolOverrides: PoolOverrides{TotalKVBlocks: &tinyKV}},
+	}
+	requests := testGenerateRequests(42, math.MaxInt64, 100.0/1e6, 300,
+		0, 150, 30, 100, 200, 150, 30, 100, 200)
+
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	agg := cs.AggregatedMetrics()
+	if agg.CompletedRequests != len(requests) {
+		t.Fatalf("CompletedRequests = %d, want %d (INV-1)", agg.CompletedRequests, len(requests))
+	}
+	return agg.PreemptionCount, cs.PerInstanceMetricsByID()["instance_1"].CompletedRequests
+}
+
+// TestPreemptionRateScorer_SteersAwayFromThrashingInstance verifies that adding
+// the preemption-rate scorer to a queue-depth profile moves traffic off an
+// instance thrashing on KV pressure and lowers cluster-wide preemptions.
+func TestPreemptionRateScorer_SteersAwayFromThrashingInstance(t *testing.T) {
+	// GIVEN a baseline profile that only sees queue depth
+	basePreempt, baseThrash := runThrashingWeighted(t, []sim.ScorerConfig{
+		{Name: "queue-depth", Weight: 1},
+	})
+	if basePreempt == 0 {
+		t.Fatal("baseline produced no preemptions; the tiny-KV instance is not thrashing")
+	}
+	// AND the same profile with the preemption-rate scorer added
+	avoidPreempt, avoidThrash := runThrashingWeighted(t, []sim.ScorerConfig{
+		{Name: "queue-depth", Weight: 1},
+		{Name: "preemption-rate", Weight: 1},
+	})
+
+	// THEN the thrashing instance receives markedly less traffic
+	if avoidThrash*2 > baseThrash {
+		t.Errorf("thrashing instance completed %d with preemption-rate vs %d without; want at most half", avoidThrash, baseThrash)
+	}
+	// AND the cluster preempts markedly less
+	if avoidPreempt*2 > basePreempt {
+		t.Errorf("cluster preemptions = %d with preemption-rate vs baseline %d; want at most half", avoidPreempt, basePreempt)
+	}
+}
+
+// TestSnapshot_RecentPreemptionRate_WindowedRate verifies the snapshot exposes a
+// preemption rate only while preemptions fall within the rolling window.
+func TestSnapshot_RecentPreemptionRate_WindowedRate(t *testing.T) {
+	config := newTestDeploymentConfig(1)
+	config.KVCacheConfig = sim.NewKVCacheConfig(40, 16, 0, 0, 0, 0)
+	requests := testGenerateRequests(42, math.MaxInt64, 200.0/1e6, 40,
+		0, 150, 30, 100, 200, 150, 30, 100, 200)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	inst := cs.Instances()[0]
+	if inst.PreemptionCount() == 0 {
+		t.Fatal("no preemptions; the tiny-KV instance is not thrashing")
+	}
+	// Run() leaves the clock at the last event, right after the thrash.
+	provider := NewCachedSnapshotProvider(map[InstanceID]*InstanceSimulator{inst.ID(): inst}, DefaultObservabilityConfig())
+	snap := provider.Snapshot(inst.ID(), inst.Clock())
+	if snap.RecentPreemptionRate <= 0 {
+		t.Fatalf("RecentPreemptionRate = %v after %d preemptions, want > 0", snap.RecentPreemptionRate, snap.PreemptionCount)
+	}
+	maxRate := float64(snap.PreemptionCount) * 1e6 / float64(sim.RecentPreemptionWindowUs)
+	if snap.RecentPreemptionRate > maxRate {
+		t.Errorf("RecentPreemptionRate = %v exceeds all %d preemptions in one window (%v)", snap.RecentPreemptionRate, snap.PreemptionCount, maxRate)
+	}
+}
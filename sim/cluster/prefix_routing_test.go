@@ -63,7 +63,7 @@ func baseDeploymentConfig(numInstances int) DeploymentConfig {
 			KVCacheConfig:       sim.NewKVCacheConfig(2000, 16, 0, 0, 0, 0),
 			BatchConfig:         sim.NewBatchConfig(64, 65536, 0),
 			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
-			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0, ""),
 		},
 		NumInstances: numInstances,
 		TraceLevel:   "decisions",
@@ -318,6 +318,59 @@ func TestPrefixAffinityRouting_MultiTurn_SessionAffinity(t *testing.T) {
 		"prefix-affinity should keep sessions together (lower scatter) vs load-only")
 }
 
+// TestSessionAffinityRouting_MultiTurn_RoundsStayOnInstance verifies that the
+// session-affinity policy keeps every round of a session on the instance that
+// served its previous round when the cluster is lightly loaded.
+func TestSessionAffinityRouting_MultiTurn_RoundsStayOnInstance(t *testing.T) {
+	// GIVEN 12 sessions of 4 rounds each, spaced far enough apart that no
+	// instance builds a queue
+	const numSessions, roundsPerSession = 12, 4
+	var requests []*sim.Request
+	for s := 0; s < numSessions; s++ {
+		for r := 0; r < roundsPerSession; r++ {
+			requests = append(requests, &sim.Request{
+				ID:           fmt.Sprintf("request_%d", s*roundsPerSession+r),
+				InputTokens:  make([]sim.TokenID, 64*(r+1)),
+				OutputTokens: make([]sim.TokenID, 16),
+				State:        sim.StateQueued,
+				ArrivalTime:  int64(r)*2000000 + int64(s)*50000,
+				SessionID:    fmt.Sprintf("session_%d", s),
+				RoundIndex:   r,
+			})
+		}
+	}
+	config := baseDeploymentConfig(4)
+	config.Horizon = 50000000
+	config.RoutingPolicy = "session-affinity"
+
+	// WHEN the cluster runs
+	cs := NewClusterSimulator(config, NewSliceRequestSource(copyRequests(requests)), nil)
+	require.NoError(t, cs.Run())
+
+	// THEN (nearly) every round after the first lands where the previous round did
+	reqToInst := make(map[string]string)
+	for _, inst := range cs.Instances() {
+		for reqID := range inst.Metrics().Requests {
+			reqToInst[reqID] = string(inst.ID())
+		}
+	}
+	sticky, followUps := 0, 0
+	for i, req := range requests {
+		if req.RoundIndex == 0 {
+			continue
+		}
+		followUps++
+		if reqToInst[req.ID] == reqToInst[requests[i-1].ID] {
+			sticky++
+		}
+	}
+	affinity := float64(sticky) / float64(followUps)
+	t.Logf("session affinity rate: %.2f (%d/%d)", affinity, sticky, followUps)
+	assert.GreaterOrEqual(t, affinity, 0.95, "light load should keep sessions on one instance")
+	// Sessions still spread across the cluster rather than piling onto one instance.
+	assert.Greater(t, countNonZero(getRoutingDistribution(cs)), 1)
+}
+
 // --- helpers ---
 
 func copyRequests(reqs []*sim.Request) []*sim.Request {
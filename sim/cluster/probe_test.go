@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// TestInstanceProbeSnapshots_ClusterMode verifies probe ticks registered on
+// cluster instances are recorded by the cluster event loop, each matching the
+// instance's queue and batch in a run truncated at that tick.
+func TestInstanceProbeSnapshots_ClusterMode(t *testing.T) {
+	// Off the 20ms arrival grid, so truncating at a tick sees the same state.
+	probes := []int64{150_001, 400_001, 900_001}
+	newCluster := func(horizon int64) *ClusterSimulator {
+		config := newTestDeploymentConfig(2)
+		config.Horizon = horizon
+		config.MaxRunningReqs = 2
+		config.RoutingPolicy = "round-robin"
+		requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, 60,
+			0, 200, 20, 100, 300, 200, 20, 100, 300)
+		return NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	}
+
+	// GIVEN probe ticks on both instances of a running cluster
+	cs := newCluster(math.MaxInt64)
+	for _, inst := range cs.Instances() {
+		inst.SetProbeTicks(probes)
+	}
+	mustRun(t, cs)
+
+	sawQueued := false
+	for i, tick := range probes {
+		ref := newCluster(tick)
+		mustRun(t, ref)
+		for k, inst := range cs.Instances() {
+			// THEN each instance records one snapshot per tick
+			snaps := inst.ProbeSnapshots()
+			if len(snaps) != len(probes) {
+				t.Fatalf("%s: got %d snapshots, want %d", inst.ID(), len(snaps), len(probes))
+			}
+			snap := snaps[i]
+
+			// AND it matches that instance's state when the cluster stops at the tick
+			refSim := ref.Instances()[k].sim
+			var wantQueued, gotQueued, wantRunning, gotRunning []string
+			for _, req := range refSim.WaitQ.Items() {
+				wantQueued = append(wantQueued, req.ID)
+			}
+			if refSim.RunningBatch != nil {
+				for _, req := range refSim.RunningBatch.Requests {
+					wantRunning = append(wantRunning, req.ID)
+				}
+			}
+			for _, q := range snap.Queued {
+				gotQueued = append(gotQueued, q.ID)
+			}
+			for _, r := range snap.Running {
+				gotRunning = append(gotRunning, r.ID)
+			}
+			if snap.Clock != tick || !slices.Equal(gotQueued, wantQueued) || !slices.Equal(gotRunning, wantRunning) {
+				t.Errorf("%s tick %d: snapshot clock %d queued %v running %v, want queued %v running %v",
+					inst.ID(), tick, snap.Clock, gotQueued, gotRunning, wantQueued, wantRunning)
+			}
+			sawQueued = sawQueued || len(gotQueued) > 0
+		}
+	}
+	if !sawQueued {
+		t.Error("no snapshot saw a queued request; the test does not exercise queueing")
+	}
+}
@@ -137,7 +137,7 @@ func TestRoofline_GoldenDataset(t *testing.T) {
 				[]float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, // beta coeffs (unused in roofline)
 				[]float64{0, 0, 0},                      // alpha coeffs (unused in roofline)
 			)
-			hwCfg := sim.NewModelHardwareConfig(*mc, hc, exp.Model, exp.Hardware, exp.TP, 1, false, "", ds.Backend, 0)
+			hwCfg := sim.NewModelHardwareConfig(*mc, hc, exp.Model, exp.Hardware, exp.TP, 1, false, "", ds.Backend, 0, "")
 
 			// Validate that the backend is accepted; fail fast with a clear error.
 			if _, err := latency.NewLatencyModel(coeffs, hwCfg); err != nil {
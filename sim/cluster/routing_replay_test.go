@@ -0,0 +1,77 @@
+package cluster
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// runRoundRobinTraced runs 30 requests on 3 instances under round-robin routing
+// with decision tracing and returns the cluster and its routing-decision log.
+func runRoundRobinTraced(t *testing.T) (*ClusterSimulator, map[string]string) {
+	t.Helper()
+	config := newTestDeploymentConfig(3)
+	config.RoutingPolicy = "round-robin"
+	config.TraceLevel = "decisions"
+	requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, 30,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	return cs, cs.Trace().RoutingLog()
+}
+
+// TestReplayRouting_ReproducesRecordedAssignments verifies that replaying a
+// round-robin run's routing-decision log assigns every request to the instance
+// it was recorded on.
+func TestReplayRouting_ReproducesRecordedAssignments(t *testing.T) {
+	// GIVEN the routing decisions of a round-robin run
+	recorded, log := runRoundRobinTraced(t)
+	if len(log) != 30 {
+		t.Fatalf("routing-decision log has %d entries, want 30", len(log))
+	}
+
+	// WHEN the same workload is replayed from the log
+	config := newTestDeploymentConfig(3)
+	config.RoutingPolicy = "replay"
+	config.RoutingReplayLog = log
+	requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, 30,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+	replayed := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, replayed)
+
+	// THEN every request lands on its recorded instance
+	for id, inst := range log {
+		if got := replayed.AggregatedMetrics().Requests[id].HandledBy; got != inst {
+			t.Errorf("%s handled by %q on replay, recorded %q", id, got, inst)
+		}
+	}
+	// AND each instance serves the same number of requests
+	want := recorded.PerInstanceMetricsByID()
+	for id, m := range replayed.PerInstanceMetricsByID() {
+		if m.CompletedRequests != want[id].CompletedRequests {
+			t.Errorf("%s completed %d on replay, recorded %d", id, m.CompletedRequests, want[id].CompletedRequests)
+		}
+	}
+}
+
+// TestReplayRouting_MissingRequest_RunErrors verifies that a request absent
+// from the routing-decision log fails the run with its ID.
+func TestReplayRouting_MissingRequest_RunErrors(t *testing.T) {
+	_, log := runRoundRobinTraced(t)
+	delete(log, "request_7")
+
+	config := newTestDeploymentConfig(3)
+	config.RoutingPolicy = "replay"
+	config.RoutingReplayLog = log
+	requests := testGenerateRequests(42, math.MaxInt64, 50.0/1e6, 30,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+
+	err := cs.Run()
+	if err == nil {
+		t.Fatal("Run succeeded with a request missing from the routing-decision log, want error")
+	}
+	if !strings.Contains(err.Error(), "request_7") {
+		t.Errorf("error %q does not name the missing request", err)
+	}
+}
@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// runTieBreakTraced runs 40 Poisson requests on 4 instances under the given
+// routing policy with lowest-index tie-breaking and returns the
+// routing-decision log. seed is the cluster seed (router RNG); the workload is
+// fixed.
+func runTieBreakTraced(t *testing.T, policy string, seed int64) map[string]string {
+	t.Helper()
+	config := newTestDeploymentConfig(4)
+	config.Seed = seed
+	config.RoutingPolicy = policy
+	config.RoutingTieBreak = RoutingTieBreakLowestIndex
+	config.TraceLevel = "decisions"
+	requests := testGenerateRequests(42, math.MaxInt64, 200.0/1e6, 40,
+		0, 100, 20, 10, 200, 50, 10, 10, 100)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	return cs.Trace().RoutingLog()
+}
+
+// TestRoutingTieBreak_LowestIndex_ExactTiePicksFirstInstance verifies that
+// with every instance idle (an exact tie on load and score), least-loaded and
+// weighted routing send each request to instance_0 whatever the seed.
+func TestRoutingTieBreak_LowestIndex_ExactTiePicksFirstInstance(t *testing.T) {
+	for _, policy := range []string{"least-loaded", "weighted"} {
+		for _, seed := range []int64{1, 42, 1234} {
+			t.Run(fmt.Sprintf("%s/seed=%d", policy, seed), func(t *testing.T) {
+				// GIVEN 4 idle instances and requests spaced far enough apart
+				// that each finishes before the next arrives
+				config := newTestDeploymentConfig(4)
+				config.Seed = seed
+				config.RoutingPolicy = policy
+				config.RoutingScorerConfigs = []sim.ScorerConfig{{Name: "queue-depth", Weight: 1}, {Name: "load-balance", Weight: 1}}
+				config.RoutingTieBreak = RoutingTieBreakLowestIndex
+				config.SnapshotRefreshInterval = 0
+				requests := newTestRequests(5)
+				for i, req := range requests {
+					req.ArrivalTime = int64(i) * 10_000_000
+				}
+
+				// WHEN the cluster runs
+				cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+				mustRun(t, cs)
+
+				// THEN every tie goes to the lowest-index instance
+				for _, req := range requests {
+					if got := cs.AggregatedMetrics().Requests[req.ID].HandledBy; got != "instance_0" {
+						t.Errorf("%s handled by %q, want instance_0", req.ID, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestRoutingTieBreak_LowestIndex_SameAcrossRunsAndProcesses verifies that
+// lowest-index routing decisions do not depend on the router RNG: runs with
+// different seeds, in this process and in fresh processes, produce the same
+// routing-decision log.
+func TestRoutingTieBreak_LowestIndex_SameAcrossRunsAndProcesses(t *testing.T) {
+	if seed := os.Getenv("BLIS_TIE_BREAK_SEED"); seed != "" {
+		// Subprocess: print the routing-decision log for the given seed.
+		s, err := strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			t.Fatalf("BLIS_TIE_BREAK_SEED: %v", err)
+		}
+		log := runTieBreakTraced(t, "least-loaded", s)
+		data, err := json.Marshal(log) // map keys are sorted
+		if err != nil {
+			t.Fatalf("marshal routing log: %v", err)
+		}
+		fmt.Printf("ROUTING_LOG %s\n", data)
+		return
+	}
+
+	// GIVEN the routing log of an in-process run
+	want := runTieBreakTraced(t, "least-loaded", 42)
+	if len(want) != 40 {
+		t.Fatalf("routing-decision log has %d entries, want 40", len(want))
+	}
+	used := map[string]bool{}
+	for _, inst := range want {
+		used[inst] = true
+	}
+	if len(used) < 2 {
+		t.Fatalf("routing used %d instance(s), want a workload that spreads load", len(used))
+	}
+
+	// WHEN the same workload runs again in-process and in fresh processes with other seeds
+	if again := runTieBreakTraced(t, "least-loaded", 7); !routingLogsEqual(again, want) {
+		t.Errorf("in-process rerun with seed 7 routed differently:\n got %v\nwant %v", again, want)
+	}
+	for _, seed := range []string{"42", "99"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRoutingTieBreak_LowestIndex_SameAcrossRunsAndProcesses$")
+		cmd.Env = append(os.Environ(), "BLIS_TIE_BREAK_SEED="+seed)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("subprocess (seed %s): %v\n%s", seed, err, out)
+		}
+
+		// THEN every process routes each request to the same instance
+		got := parseRoutingLogLine(t, string(out))
+		if !routingLogsEqual(got, want) {
+			t.Errorf("subprocess with seed %s routed differently:\n got %v\nwant %v", seed, got, want)
+		}
+	}
+}
+
+// parseRoutingLogLine extracts the ROUTING_LOG line written by the subprocess.
+func parseRoutingLogLine(t *testing.T, out string) map[string]string {
+	t.Helper()
+	for _, line := range strings.Split(out, "\n") {
+		if data, ok := strings.CutPrefix(line, "ROUTING_LOG "); ok {
+			var log map[string]string
+			if err := json.Unmarshal([]byte(data), &log); err != nil {
+				t.Fatalf("unmarshal routing log: %v", err)
+			}
+			return log
+		}
+	}
+	t.Fatalf("no ROUTING_LOG line in subprocess output:\n%s", out)
+	return nil
+}
+
+func routingLogsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, inst := range a {
+		if b[id] != inst {
+			return false
+		}
+	}
+	return true
+}
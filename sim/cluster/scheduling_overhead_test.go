@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"testing"
+)
+
+// schedulingOverheadThroughput runs 256 concurrent 256/128-token requests on
+// one instance and returns output tokens per second.
+func schedulingOverheadThroughput(t *testing.T, maxRunning int64, usPerSeq, usPerSeqSq float64) float64 {
+	t.Helper()
+	config := newTestDeploymentConfig(1)
+	config.MaxRunningReqs = maxRunning
+	config.SchedulingOverheadUsPerSeq = usPerSeq
+	config.SchedulingContentionUsPerSeqSq = usPerSeqSq
+	requests := testGenerateRequests(42, 1<<62, 1000.0/1e6, 256,
+		0, 256, 0, 256, 256, 128, 0, 128, 128)
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	m := cs.AggregatedMetrics()
+	return float64(m.TotalOutputTokens) / float64(m.SimEndedTime) * 1e6
+}
+
+// TestSchedulingOverhead_DiminishingReturnsInBatchSize verifies that a
+// per-sequence scheduling cost caps throughput: every decode step producing B
+// tokens costs at least usPerSeq*B, so tokens/sec stays below 1e6/usPerSeq and
+// growing MaxRunningReqs stops paying off. Without it, large batches keep
+// amortizing the fixed weight-loading cost.
+func TestSchedulingOverhead_DiminishingReturnsInBatchSize(t *testing.T) {
+	const usPerSeq = 500
+	batchSizes := []int64{16, 64, 256}
+	gain := func(c float64) (float64, []float64) {
+		tput := make([]float64, len(batchSizes))
+		for i, b := range batchSizes {
+			tput[i] = schedulingOverheadThroughput(t, b, c, 0)
+		}
+		return tput[2]/tput[1] - 1, tput
+	}
+
+	baseGain, base := gain(0)
+	overheadGain, withOverhead := gain(usPerSeq)
+
+	for i, tput := range withOverhead {
+		// THEN throughput is bounded by the per-sequence overhead
+		if ceiling := 1e6 / usPerSeq; tput > ceiling {
+			t.Errorf("MaxRunningReqs=%d: throughput %.0f tok/s exceeds 1e6/c = %.0f", batchSizes[i], tput, ceiling)
+		}
+		// AND the overhead only ever slows the run down
+		if tput >= base[i] {
+			t.Errorf("MaxRunningReqs=%d: throughput with overhead %.0f >= without %.0f", batchSizes[i], tput, base[i])
+		}
+	}
+	// AND quadrupling the batch from 64 to 256 gains little with the overhead
+	// compared to without it
+	if overheadGain >= 0.1 || baseGain < 0.2 {
+		t.Errorf("throughput gain 64→256: with overhead %.1f%%, without %.1f%%; want < 10%% and >= 20%%",
+			overheadGain*100, baseGain*100)
+	}
+}
+
+// TestSchedulingOverhead_ContentionPeaksThroughput verifies that a per-sequence²
+// contention cost makes throughput peak at an interior MaxRunningReqs: B²
+// growth eventually outweighs amortizing the fixed per-step cost, so both a
+// smaller and a larger batch than the peak are slower. Without it, throughput
+// rises monotonically with batch size.
+func TestSchedulingOverhead_ContentionPeaksThroughput(t *testing.T) {
+	const usPerSeqSq = 1
+	batchSizes := []int64{16, 64, 256}
+	base := make([]float64, len(batchSizes))
+	tput := make([]float64, len(batchSizes))
+	for i, b := range batchSizes {
+		base[i] = schedulingOverheadThroughput(t, b, 0, 0)
+		tput[i] = schedulingOverheadThroughput(t, b, 0, usPerSeqSq)
+	}
+
+	// THEN without the overhead throughput keeps rising with batch size
+	if base[0] >= base[1] || base[1] >= base[2] {
+		t.Errorf("throughput without overhead = %.0f, want increasing over MaxRunningReqs %v", base, batchSizes)
+	}
+	// AND with contention it peaks at MaxRunningReqs=64
+	if tput[1] <= tput[0] || tput[1] <= tput[2] {
+		t.Errorf("throughput with contention = %.0f over MaxRunningReqs %v, want an interior peak at 64", tput, batchSizes)
+	}
+}
@@ -0,0 +1,75 @@
+package cluster
+
+import "github.com/inference-sim/inference-sim/sim"
+
+// annotateRemotePrefixHit consults the cluster-wide prefix index as req is
+// routed to inst. When another instance holds a longer prefix than inst's local
+// cache, req carries RemotePrefixBlocks and RemotePrefixOwner so inst fetches
+// those blocks from the shared KV store instead of recomputing them (the fetch
+// cost is SimConfig.RemotePrefixFetchUsPerBlock). The index records routing, not
+// eviction, so the best candidate is confirmed against its owner's live cache
+// here and again when the fetch is charged (remotePrefixCached). Both fields are
+// reset on every routing, so a re-routed request never carries a stale hit.
+// No-op when SharedPrefixCache is disabled (INV-6) or req bypasses prefix
+// caching (NoCache).
+//
+// Routing stays prefix-agnostic: the index is consulted only after the target
+// is chosen, so cross-instance reuse does not depend on prefix-affinity scoring.
+func (c *ClusterSimulator) annotateRemotePrefixHit(req *sim.Request, inst *InstanceSimulator) {
+	req.RemotePrefixBlocks, req.RemotePrefixOwner = 0, ""
+	if c.sharedPrefixIndex == nil || req.NoCache {
+		return
+	}
+	tokens := req.FullInputTokens()
+	hashes := c.sharedPrefixIndex.ComputeBlockHashes(tokens)
+	var owner *InstanceSimulator
+	indexed := 0
+	for _, other := range c.instances {
+		if other == inst || other.State == sim.InstanceStateTerminated {
+			continue
+		}
+		if n := c.sharedPrefixIndex.MatchLength(hashes, string(other.ID())); n > indexed {
+			owner, indexed = other, n
+		}
+	}
+	c.sharedPrefixIndex.RecordBlocks(hashes, string(inst.ID()))
+	if owner == nil {
+		return
+	}
+	remote := min(indexed, owner.GetCachedBlockCount(tokens))
+	if remote > inst.GetCachedBlockCount(tokens) {
+		req.RemotePrefixBlocks = remote
+		req.RemotePrefixOwner = string(owner.ID())
+		c.remotePrefixHits++
+	}
+}
+
+// remotePrefixCached returns how many of req's leading input blocks its
+// RemotePrefixOwner still holds, so an instance never fetches blocks the owner
+// evicted between routing and admission. 0 when the owner has terminated.
+func (c *ClusterSimulator) remotePrefixCached(req *sim.Request) int {
+	for _, inst := range c.instances {
+		if string(inst.ID()) == req.RemotePrefixOwner {
+			if inst.State == sim.InstanceStateTerminated {
+				return 0
+			}
+			return inst.GetCachedBlockCount(req.FullInputTokens())
+		}
+	}
+	return 0
+}
+
+// attachSharedPrefixCache wires inst's remote prefix fetches to the owners'
+// live caches. No-op when SharedPrefixCache is disabled.
+func (c *ClusterSimulator) attachSharedPrefixCache(inst *InstanceSimulator) {
+	if c.sharedPrefixIndex == nil {
+		return
+	}
+	inst.attachRemotePrefixLookup(c.remotePrefixCached)
+}
+
+// RemotePrefixHits returns the number of routed requests that found a longer
+// prefix cached on another instance. Always 0 when SharedPrefixCache is disabled.
+func (c *ClusterSimulator) RemotePrefixHits() int {
+	return c.remotePrefixHits
+}
@@ -0,0 +1,179 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+const (
+	sharedPrefixTokens = 256 // 16 blocks at BlockSizeTokens=16
+	sharedPrefixBlocks = sharedPrefixTokens / 16
+)
+
+// runSharedPrefixCluster routes two requests with a common 256-token prefix
+// round-robin across two instances: request_0 to instance_0 at t=0 and
+// request_1 to instance_1 one second later, after request_0 has finished.
+func runSharedPrefixCluster(t *testing.T, shared bool, fetchUsPerBlock float64) *ClusterSimulator {
+	t.Helper()
+	config := newTestDeploymentConfig(2)
+	config.RoutingPolicy = "round-robin"
+	config.SharedPrefixCache = shared
+	config.RemotePrefixFetchUsPerBlock = fetchUsPerBlock
+
+	prefix := make([]sim.TokenID, sharedPrefixTokens)
+	for i := range prefix {
+		prefix[i] = sim.TokenID(i + 1)
+	}
+	requests := make([]*sim.Request, 2)
+	for i := range requests {
+		input := append(append([]sim.TokenID{}, prefix...), make([]sim.TokenID, 32)...)
+		for j := sharedPrefixTokens; j < len(input); j++ {
+			input[j] = sim.TokenID(10_000*(i+1) + j)
+		}
+		requests[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 1_000_000,
+			InputTokens:  input,
+			OutputTokens: make([]sim.TokenID, 8),
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+	return cs
+}
+
+// TestSharedPrefixCache_RemoteHitChargesFetchLatency verifies that the second
+// instance reuses the prefix cached on the first through the shared store, and
+// that the remote fetch cost is added to its TTFT.
+func TestSharedPrefixCache_RemoteHitChargesFetchLatency(t *testing.T) {
+	const fetchUs = 200
+
+	// GIVEN a shared prefix cache with free and with priced remote fetches
+	free := runSharedPrefixCluster(t, true, 0)
+	priced := runSharedPrefixCluster(t, true, fetchUs)
+
+	for name, cs := range map[string]*ClusterSimulator{"free": free, "priced": priced} {
+		// THEN request_1 on instance_1 hits the prefix held by instance_0
+		if got := cs.RemotePrefixHits(); got != 1 {
+			t.Errorf("%s: RemotePrefixHits = %d, want 1", name, got)
+		}
+		perInst := cs.PerInstanceMetricsByID()
+		if got := perInst["instance_1"].RemotePrefixFetchedBlocks; got != sharedPrefixBlocks {
+			t.Errorf("%s: instance_1 fetched %d blocks, want %d", name, got, sharedPrefixBlocks)
+		}
+		if got := perInst["instance_0"].RemotePrefixFetchedBlocks; got != 0 {
+			t.Errorf("%s: instance_0 fetched %d blocks, want 0 (first to see the prefix)", name, got)
+		}
+		if got := cs.AggregatedMetrics().CompletedRequests; got != 2 {
+			t.Errorf("%s: completed = %d, want 2", name, got)
+		}
+	}
+
+	// AND the fetch latency is charged on the admitting step
+	freeTTFT := free.AggregatedMetrics().RequestTTFTs["request_1"]
+	pricedTTFT := priced.AggregatedMetrics().RequestTTFTs["request_1"]
+	if want := float64(sharedPrefixBlocks * fetchUs); pricedTTFT-freeTTFT != want {
+		t.Errorf("request_1 TTFT: priced %.0f - free %.0f = %.0f, want %.0f", pricedTTFT, freeTTFT, pricedTTFT-freeTTFT, want)
+	}
+
+	// AND skipping the prefix prefill beats recomputing it without the shared cache
+	local := runSharedPrefixCluster(t, false, fetchUs)
+	if got := local.AggregatedMetrics().RequestTTFTs["request_1"]; got <= freeTTFT {
+		t.Errorf("request_1 TTFT without shared cache = %.0f, want > %.0f with a free remote hit", got, freeTTFT)
+	}
+}
+
+// TestSharedPrefixCache_Disabled_IsInert verifies no remote hits are recorded
+// when the shared prefix cache is off (INV-6).
+func TestSharedPrefixCache_Disabled_IsInert(t *testing.T) {
+	cs := runSharedPrefixCluster(t, false, 200)
+	if cs.RemotePrefixHits() != 0 {
+		t.Errorf("RemotePrefixHits = %d, want 0", cs.RemotePrefixHits())
+	}
+	if got := cs.AggregatedMetrics().RemotePrefixFetchedBlocks; got != 0 {
+		t.Errorf("RemotePrefixFetchedBlocks = %d, want 0", got)
+	}
+}
+
+// TestSharedPrefixCache_EvictedBlocksAreNotFetched verifies a prefix evicted
+// from its owner's cache stops being shared: a later request routed elsewhere
+// recomputes it instead of fetching blocks that no longer exist.
+func TestSharedPrefixCache_EvictedBlocksAreNotFetched(t *testing.T) {
+	// GIVEN two 40-block instances sharing prefix caches, round-robin routed
+	config := newTestDeploymentConfig(2)
+	config.KVCacheConfig = sim.NewKVCacheConfig(40, 16, 0, 0, 0, 0)
+	config.RoutingPolicy = "round-robin"
+	config.SharedPrefixCache = true
+
+	prefix := make([]sim.TokenID, sharedPrefixTokens)
+	for i := range prefix {
+		prefix[i] = sim.TokenID(i + 1)
+	}
+	unique := func(base, n int) []sim.TokenID {
+		tokens := make([]sim.TokenID, n)
+		for j := range tokens {
+			tokens[j] = sim.TokenID(base + j)
+		}
+		return tokens
+	}
+	inputs := [][]sim.TokenID{
+		append(append([]sim.TokenID{}, prefix...), unique(10_000, 32)...), // instance_0 caches the prefix
+		unique(20_000, 32),  // instance_1
+		unique(30_000, 352), // instance_0: with its decode, needs all 40 blocks
+		append(append([]sim.TokenID{}, prefix...), unique(40_000, 32)...), // instance_1
+	}
+	// request_2's prompt fits beside the cached prefix; its decode blocks evict it.
+	outputs := []int{8, 8, 288, 8}
+	requests := make([]*sim.Request, len(inputs))
+	for i, input := range inputs {
+		requests[i] = &sim.Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 1_000_000,
+			InputTokens:  input,
+			OutputTokens: make([]sim.TokenID, outputs[i]),
+			State:        sim.StateQueued,
+		}
+	}
+	cs := NewClusterSimulator(config, NewSliceRequestSource(requests), nil)
+	mustRun(t, cs)
+
+	// THEN request_3 finds no remote prefix and fetches nothing
+	if got := cs.RemotePrefixHits(); got != 0 {
+		t.Errorf("RemotePrefixHits = %d, want 0 (prefix evicted from instance_0)", got)
+	}
+	if got := cs.PerInstanceMetricsByID()["instance_1"].RemotePrefixFetchedBlocks; got != 0 {
+		t.Errorf("instance_1 fetched %d blocks, want 0", got)
+	}
+	if got := cs.AggregatedMetrics().CompletedRequests; got != len(requests) {
+		t.Errorf("completed = %d, want %d", got, len(requests))
+	}
+}
+
+// TestSharedPrefixCache_RerouteClearsStaleHit verifies routing resets a
+// request's remote prefix annotation, so a request re-routed after a fault does
+// not carry the previous routing's hit.
+func TestSharedPrefixCache_RerouteClearsStaleHit(t *testing.T) {
+	config := newTestDeploymentConfig(2)
+	config.SharedPrefixCache = true
+	cs := NewClusterSimulator(config, NewSliceRequestSource(nil), nil)
+
+	// GIVEN a request still annotated from an earlier routing
+	req := &sim.Request{
+		ID: "r", InputTokens: make([]sim.TokenID, 64), OutputTokens: make([]sim.TokenID, 4),
+		RemotePrefixBlocks: 4, RemotePrefixOwner: "instance_0",
+	}
+
+	// WHEN it is routed to an instance no prefix is indexed for
+	cs.annotateRemotePrefixHit(req, cs.instances[1])
+
+	// THEN the stale hit is cleared and not counted
+	if req.RemotePrefixBlocks != 0 || req.RemotePrefixOwner != "" {
+		t.Errorf("RemotePrefixBlocks=%d owner=%q, want 0 and empty", req.RemotePrefixBlocks, req.RemotePrefixOwner)
+	}
+	if got := cs.RemotePrefixHits(); got != 0 {
+		t.Errorf("RemotePrefixHits = %d, want 0", got)
+	}
+}
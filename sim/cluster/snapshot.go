@@ -29,8 +29,9 @@ type ObservabilityConfig struct {
 	BatchSize        FieldConfig
 	KVUtilization    FieldConfig
 	CacheBlocks      FieldConfig // cache block hash map staleness (precise-prefix-cache, no-hit-lru)
-	PreemptionCount  FieldConfig
+	PreemptionCount  FieldConfig // also governs RecentPreemptionRate (preemption-rate scorer)
 	ResidentAdapters FieldConfig // resident LoRA adapter set staleness (lora-affinity scorer, #1469)
+	TTFTP99          FieldConfig // windowed P99 TTFT staleness (p99-ttft scorer)
 }
 
 // DefaultObservabilityConfig returns a config where all fields use Immediate mode.
@@ -42,6 +43,7 @@ func DefaultObservabilityConfig() ObservabilityConfig {
 		CacheBlocks:      FieldConfig{Mode: Immediate},
 		PreemptionCount:  FieldConfig{Mode: Immediate},
 		ResidentAdapters: FieldConfig{Mode: Immediate},
+		TTFTP99:          FieldConfig{Mode: Immediate},
 	}
 }
 
@@ -57,6 +59,7 @@ func newObservabilityConfig(refreshInterval int64, cacheDelay int64) Observabili
 		config.KVUtilization = periodic
 		config.PreemptionCount = periodic
 		config.ResidentAdapters = periodic
+		config.TTFTP99 = periodic
 	}
 	if cacheDelay > 0 {
 		config.CacheBlocks = FieldConfig{Mode: Periodic, Interval: cacheDelay}
@@ -80,6 +83,7 @@ type fieldTimestamps struct {
 	KVUtilization    int64
 	PreemptionCount  int64
 	ResidentAdapters int64
+	TTFTP99          int64
 }
 
 // cacheEntry holds a live instance reference and its current stale snapshot closure.
@@ -151,6 +155,7 @@ func (p *CachedSnapshotProvider) Snapshot(id InstanceID, clock int64) sim.Routin
 
 	if p.shouldRefresh(p.config.PreemptionCount, lr.PreemptionCount, clock) {
 		snap.PreemptionCount = inst.PreemptionCount()
+		snap.RecentPreemptionRate = inst.RecentPreemptionRate()
 		lr.PreemptionCount = clock
 	}
 	if p.shouldRefresh(p.config.QueueDepth, lr.QueueDepth, clock) {
@@ -159,6 +164,7 @@ func (p *CachedSnapshotProvider) Snapshot(id InstanceID, clock int64) sim.Routin
 	}
 	if p.shouldRefresh(p.config.BatchSize, lr.BatchSize, clock) {
 		snap.BatchSize = inst.BatchSize()
+		snap.RemainingDecodeTokens = inst.RemainingDecodeTokens()
 		lr.BatchSize = clock
 	}
 	if p.shouldRefresh(p.config.KVUtilization, lr.KVUtilization, clock) {
@@ -173,6 +179,10 @@ func (p *CachedSnapshotProvider) Snapshot(id InstanceID, clock int64) sim.Routin
 		snap.ResidentAdapters = residentAdapterSet(inst)
 		lr.ResidentAdapters = clock
 	}
+	if p.shouldRefresh(p.config.TTFTP99, lr.TTFTP99, clock) {
+		snap.TTFTP99 = inst.RecentTTFTP99()
+		lr.TTFTP99 = clock
+	}
 
 	p.cache[id] = snap
 	p.lastRefresh[id] = lr
@@ -201,6 +211,7 @@ func (p *CachedSnapshotProvider) RefreshAll(clock int64) {
 	for id, inst := range p.instances {
 		snap := sim.NewRoutingSnapshot(string(id))
 		snap.PreemptionCount = inst.PreemptionCount()
+		snap.RecentPreemptionRate = inst.RecentPreemptionRate()
 		snap.QueueDepth = inst.QueueDepth()
 		snap.BatchSize = inst.BatchSize()
 		snap.KVUtilization = inst.KVUtilization()
@@ -209,6 +220,7 @@ func (p *CachedSnapshotProvider) RefreshAll(clock int64) {
 		snap.TotalKvCapacityTokens = inst.TotalKvCapacityTokens()
 		snap.KvTokensInUse = inst.KvTokensInUse()
 		snap.ResidentAdapters = residentAdapterSet(inst)
+		snap.TTFTP99 = inst.RecentTTFTP99()
 		p.cache[id] = snap
 		p.lastRefresh[id] = fieldTimestamps{
 			PreemptionCount:  clock,
@@ -216,6 +228,7 @@ func (p *CachedSnapshotProvider) RefreshAll(clock int64) {
 			BatchSize:        clock,
 			KVUtilization:    clock,
 			ResidentAdapters: clock,
+			TTFTP99:          clock,
 		}
 	}
 }
@@ -0,0 +1,135 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// endlessRequestSource serves fixed-size requests at a constant inter-arrival
+// time forever, recording the arrival time of every request pulled.
+type endlessRequestSource struct {
+	iat     int64
+	pulled  []int64
+	nextArr int64
+}
+
+func (s *endlessRequestSource) Next() (*sim.Request, bool) {
+	req := &sim.Request{
+		ID:           fmt.Sprintf("request_%d", len(s.pulled)),
+		ArrivalTime:  s.nextArr,
+		InputTokens:  make([]sim.TokenID, 64),
+		OutputTokens: make([]sim.TokenID, 16),
+		MaxOutputLen: 16,
+		State:        sim.StateQueued,
+	}
+	s.pulled = append(s.pulled, s.nextArr)
+	s.nextArr += s.iat
+	return req, true
+}
+
+// burstRequestSource serves identical requests forever in bursts of size
+// requests sharing one arrival tick, bursts gap ticks apart, so each burst
+// is batched together and finishes in a single step.
+type burstRequestSource struct {
+	size, gap int64
+	n         int64
+}
+
+func (s *burstRequestSource) Next() (*sim.Request, bool) {
+	req := &sim.Request{
+		ID:           fmt.Sprintf("request_%d", s.n),
+		ArrivalTime:  s.n / s.size * s.gap,
+		InputTokens:  make([]sim.TokenID, 64),
+		OutputTokens: make([]sim.TokenID, 16),
+		MaxOutputLen: 16,
+		State:        sim.StateQueued,
+	}
+	s.n++
+	return req, true
+}
+
+// TestClusterSimulator_StopAfterCompleted_TiedCompletionsStayRunning verifies
+// that when several requests finish in the step that reaches N, only enough of
+// them complete to make exactly N; the rest are reported as still running.
+func TestClusterSimulator_StopAfterCompleted_TiedCompletionsStayRunning(t *testing.T) {
+	// GIVEN bursts of 4 identical requests, so completions come 4 per step,
+	// and a target of 6 that falls inside the second burst
+	const target = 6
+	config := newTestDeploymentConfig(1)
+	config.StopAfterCompleted = target
+
+	ticks := map[int64]int{}
+	onDone := func(req *sim.Request, tick int64) []*sim.Request {
+		if req.State == sim.StateCompleted {
+			ticks[tick]++
+		}
+		return nil
+	}
+	cs := NewClusterSimulator(config, &burstRequestSource{size: 4, gap: 1_000_000}, onDone)
+	mustRun(t, cs)
+
+	// THEN exactly N requests complete, in two steps: 4 and then 2 of 4
+	m := cs.AggregatedMetrics()
+	if m.CompletedRequests != target {
+		t.Fatalf("CompletedRequests = %d, want exactly %d", m.CompletedRequests, target)
+	}
+	var perStep []int
+	for _, n := range ticks {
+		perStep = append(perStep, n)
+	}
+	if len(perStep) != 2 || perStep[0]+perStep[1] != target || (perStep[0] != 2 && perStep[1] != 2) {
+		t.Errorf("completions per step = %v, want 4 and 2", perStep)
+	}
+	// AND the two tied requests left over are still running (INV-1)
+	if m.StillRunning != 2 {
+		t.Errorf("StillRunning = %d, want 2 (finished in the Nth completion's step)", m.StillRunning)
+	}
+	if got := m.CompletedRequests + m.StillQueued + m.StillRunning; got != 8 {
+		t.Errorf("completed+queued+running = %d, want the 8 requests of two bursts", got)
+	}
+}
+
+// TestClusterSimulator_StopAfterCompleted_HaltsAtNthCompletion verifies that
+// with StopAfterCompleted set, a run over an unbounded arrival source
+// terminates with exactly N completed requests, and that arrivals are pulled
+// on demand: nothing past the Nth completion is generated beyond the single
+// look-ahead request.
+func TestClusterSimulator_StopAfterCompleted_HaltsAtNthCompletion(t *testing.T) {
+	const target = 50
+	src := &endlessRequestSource{iat: 20_000}
+	config := newTestDeploymentConfig(2)
+	config.StopAfterCompleted = target
+
+	var completed int
+	var stopTick int64
+	onDone := func(req *sim.Request, tick int64) []*sim.Request {
+		if req.State == sim.StateCompleted {
+			completed++
+			if completed == target {
+				stopTick = tick
+			}
+		}
+		return nil
+	}
+	cs := NewClusterSimulator(config, src, onDone)
+	mustRun(t, cs)
+
+	if got := cs.AggregatedMetrics().CompletedRequests; got != target {
+		t.Fatalf("CompletedRequests = %d, want exactly %d", got, target)
+	}
+	if stopTick == 0 {
+		t.Fatal("never observed the target completion")
+	}
+	late := 0
+	for _, arr := range src.pulled {
+		if arr > stopTick {
+			late++
+		}
+	}
+	if late > 1 {
+		t.Errorf("%d arrivals generated after the %dth completion at tick %d, want at most 1 (look-ahead)",
+			late, target, stopTick)
+	}
+}
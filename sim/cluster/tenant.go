@@ -59,6 +59,16 @@ func (t *TenantTracker) IsOverBudget(tenantID string) bool {
 	return float64(t.inFlight[tenantID]) > limit
 }
 
+// InFlight returns tenantID's current in-flight count (0 for the empty TenantID).
+func (t *TenantTracker) InFlight(tenantID string) int {
+	return t.inFlight[tenantID]
+}
+
+// Capacity returns the cluster-wide in-flight capacity the budgets are fractions of.
+func (t *TenantTracker) Capacity() int {
+	return t.totalCapacity
+}
+
 // OnStart increments the in-flight count for tenantID.
 // No-op when tenantID is empty.
 func (t *TenantTracker) OnStart(tenantID string) {
@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/workload"
+)
+
+// TestClusterSimulator_ThinkTimeDist_FollowUpsWaitSampledThinkTime verifies
+// that with multi_turn.think_time_dist set, every round N+1 of a closed-loop
+// session arrives at least the distribution's minimum after round N completes
+// in the DES, and that the gaps are sampled rather than constant.
+func TestClusterSimulator_ThinkTimeDist_FollowUpsWaitSampledThinkTime(t *testing.T) {
+	const (
+		minThinkUs = 200_000 // min=200ms below
+		maxRounds  = 4
+	)
+	spec := &workload.WorkloadSpec{
+		Version:       "2",
+		Seed:          42,
+		AggregateRate: 5,
+		NumRequests:   8,
+		Clients: []workload.ClientSpec{{
+			ID:           "chat",
+			TenantID:     "t",
+			SLOClass:     "standard",
+			RateFraction: 1,
+			Arrival:      workload.ArrivalSpec{Process: "poisson"},
+			InputDist:    workload.DistSpec{Type: "constant", Params: map[string]float64{"value": 32}},
+			OutputDist:   workload.DistSpec{Type: "constant", Params: map[string]float64{"value": 16}},
+			Reasoning: &workload.ReasoningSpec{MultiTurn: &workload.MultiTurnSpec{
+				MaxRounds:     maxRounds,
+				ThinkTimeUs:   1, // overridden by ThinkTimeDist
+				ThinkTimeDist: "lognormal:mu=-0.5,sigma=0.8,min=200ms,max=5s",
+				ContextGrowth: "accumulate",
+			}},
+		}},
+	}
+	wl, err := workload.GenerateWorkload(spec, math.MaxInt64, spec.NumRequests)
+	if err != nil {
+		t.Fatalf("GenerateWorkload: %v", err)
+	}
+	if len(wl.Sessions) == 0 {
+		t.Fatal("expected closed-loop session blueprints")
+	}
+
+	type roundKey struct {
+		sessID string
+		round  int
+	}
+	completion := make(map[roundKey]int64)
+	arrival := make(map[roundKey]int64)
+	sm := workload.NewSessionManager(wl.Sessions)
+	onDone := func(req *sim.Request, tick int64) []*sim.Request {
+		completion[roundKey{req.SessionID, req.RoundIndex}] = tick
+		followUps := sm.OnComplete(req, tick)
+		for _, fu := range followUps {
+			arrival[roundKey{fu.SessionID, fu.RoundIndex}] = fu.ArrivalTime
+		}
+		return followUps
+	}
+
+	cs := NewClusterSimulator(newTestDeploymentConfig(2), NewSliceRequestSource(wl.Requests), onDone)
+	mustRun(t, cs)
+
+	gaps := make(map[int64]bool)
+	checked := 0
+	for k, arr := range arrival {
+		done, ok := completion[roundKey{k.sessID, k.round - 1}]
+		if !ok {
+			t.Fatalf("session %s round %d arrived without a completed round %d", k.sessID, k.round, k.round-1)
+		}
+		if arr-done < minThinkUs {
+			t.Errorf("session %s round %d: arrival %d - round %d completion %d = %d µs, want >= %d",
+				k.sessID, k.round, arr, k.round-1, done, arr-done, minThinkUs)
+		}
+		gaps[arr-done] = true
+		checked++
+	}
+	if want := len(wl.Sessions) * (maxRounds - 1); checked != want {
+		t.Errorf("checked %d follow-up rounds, want %d", checked, want)
+	}
+	if len(gaps) < 2 {
+		t.Errorf("all %d think times identical; want sampled gaps", checked)
+	}
+}
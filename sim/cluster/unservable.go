@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// reasonUnservableTooLarge is the admission trace reason for a request whose
+// input can never fit in any instance's KV cache.
+const reasonUnservableTooLarge = "unservable-too-large"
+
+// inputFitsSomeInstance reports whether req's input fits in at least one
+// instance's empty KV cache. With no instances yet (all deferred) it returns
+// true and leaves the check to the instance's enqueue guard.
+func (cs *ClusterSimulator) inputFitsSomeInstance(req *sim.Request) bool {
+	if len(cs.instances) == 0 {
+		return true
+	}
+	for _, inst := range cs.instances {
+		if inst.InputFitsKV(req.InputLen()) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropTooLarge drops req at admission as unservable (sim.UnservableTooLarge).
+// It never reaches an instance, so it is counted in droppedTooLarge and folded
+// into DroppedUnservable at finalization (INV-1). A session request cancels
+// its session, as an enqueue-time drop does (BC-17).
+func (cs *ClusterSimulator) dropTooLarge(req *sim.Request) {
+	logrus.Warnf("[cluster] dropping request %s: input of %d tokens exceeds the KV capacity of every instance",
+		req.ID, req.InputLen())
+	cs.droppedTooLarge++
+	if cs.trace != nil {
+		cs.trace.RecordAdmission(trace.AdmissionRecord{
+			RequestID: req.ID,
+			Clock:     cs.clock,
+			Admitted:  false,
+			Reason:    reasonUnservableTooLarge,
+		})
+	}
+	if cs.sessionCallback != nil {
+		// Value copy: the session manager reads a dropped request as StateQueued.
+		dropped := *req
+		dropped.State = sim.StateQueued
+		for _, next := range cs.sessionCallback(&dropped, cs.clock) {
+			cs.pushArrival(next, next.ArrivalTime)
+		}
+	}
+}
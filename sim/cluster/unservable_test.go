@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// TestClusterSimulator_InputTooLargeForKV_DroppedAtAdmission verifies that a
+// request whose input can never fit in any instance's KV cache is dropped at
+// admission as unservable-too-large and never reaches an instance.
+func TestClusterSimulator_InputTooLargeForKV_DroppedAtAdmission(t *testing.T) {
+	// GIVEN one instance with a 5-block × 16-token KV cache (80 tokens)
+	config := DeploymentConfig{
+		SimConfig: sim.SimConfig{
+			Horizon:             10000000,
+			Seed:                42,
+			KVCacheConfig:       sim.NewKVCacheConfig(5, 16, 0, 0, 0, 0),
+			BatchConfig:         sim.NewBatchConfig(10, 2048, 0),
+			LatencyCoeffs:       sim.NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 50, 25}),
+			ModelHardwareConfig: sim.NewModelHardwareConfig(testRooflineModelConfig(), testRooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		},
+		NumInstances: 1,
+		TraceLevel:   "decisions",
+	}
+	// AND one 200-token request (13 blocks) among two that fit
+	reqs := []*sim.Request{
+		{ID: "too_large", ArrivalTime: 0, InputTokens: make([]sim.TokenID, 200), OutputTokens: make([]sim.TokenID, 8), MaxOutputLen: 8, State: sim.StateQueued},
+	}
+	for i := 0; i < 2; i++ {
+		reqs = append(reqs, &sim.Request{
+			ID: fmt.Sprintf("fits_%d", i), ArrivalTime: int64(1000 * (i + 1)),
+			InputTokens: make([]sim.TokenID, 16), OutputTokens: make([]sim.TokenID, 8), MaxOutputLen: 8, State: sim.StateQueued,
+		})
+	}
+
+	// WHEN the cluster runs
+	cs := NewClusterSimulator(config, NewSliceRequestSource(reqs), nil)
+	mustRun(t, cs)
+
+	// THEN the oversized request is counted as unservable-too-large
+	m := cs.AggregatedMetrics()
+	if m.DroppedUnservable != 1 || m.DroppedUnservableByReason[sim.UnservableTooLarge] != 1 {
+		t.Errorf("DroppedUnservable = %d, by reason %v; want 1 under %q",
+			m.DroppedUnservable, m.DroppedUnservableByReason, sim.UnservableTooLarge)
+	}
+	if m.CompletedRequests != 2 {
+		t.Errorf("CompletedRequests = %d, want 2", m.CompletedRequests)
+	}
+	// AND it was decided at admission, never routed to or enqueued on the instance
+	inst := cs.Instances()[0]
+	if got := inst.Metrics().DroppedUnservable; got != 0 {
+		t.Errorf("instance DroppedUnservable = %d, want 0 (request must not reach the instance)", got)
+	}
+	if _, ok := inst.Metrics().Requests["too_large"]; ok {
+		t.Error("oversized request registered on the instance")
+	}
+	tr := cs.Trace()
+	var rejectedWith string
+	for _, a := range tr.Admissions {
+		if a.RequestID == "too_large" && !a.Admitted {
+			rejectedWith = a.Reason
+		}
+	}
+	if rejectedWith != reasonUnservableTooLarge {
+		t.Errorf("admission reason for too_large = %q, want %q", rejectedWith, reasonUnservableTooLarge)
+	}
+	for _, r := range tr.Routings {
+		if r.RequestID == "too_large" {
+			t.Error("oversized request was routed")
+		}
+	}
+	// AND it is not an admission-policy rejection
+	if cs.RejectedRequests() != 0 {
+		t.Errorf("RejectedRequests = %d, want 0", cs.RejectedRequests())
+	}
+}
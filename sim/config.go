@@ -3,6 +3,7 @@ package sim
 import (
 	"fmt"
 	"math"
+	"sort"
 )
 
 // KVCacheConfig groups KV cache parameters for KV store construction.
@@ -13,6 +14,45 @@ type KVCacheConfig struct {
 	KVOffloadThreshold    float64 // DEPRECATED: Ignored in vLLM v1 mirror model. Was: GPU utilization threshold for offload. (CLI default: 0.9, zero-value: 0)
 	KVTransferBandwidth   float64 // blocks/tick transfer rate (CLI default: 100.0, zero-value: 0)
 	KVTransferBaseLatency int64   // fixed cost per transfer (ticks, default 0)
+
+	// CheckRefCounts is a debug toggle, set after NewKVCacheConfig: the GPU
+	// cache panics on KV block refcount violations (double frees, blocks
+	// freed while still referenced). Off by default.
+	CheckRefCounts bool
+
+	// ModelKVLayouts, when non-empty, gives each model served by the instance
+	// its own KV block geometry, all drawing on one pool of KVCapacityBytes
+	// (see NewMultiModelKVCacheConfig). Requests are charged by Request.Model.
+	// Nil (the default) is a single-model cache of TotalKVBlocks blocks.
+	ModelKVLayouts  map[string]ModelKVLayout
+	KVCapacityBytes int64
+}
+
+// ModelKVLayout is one model's KV cache geometry.
+type ModelKVLayout struct {
+	BlockSizeTokens int64 // Tokens per KV block
+	BytesPerToken   int64 // KV cache bytes per token (all layers, K and V)
+}
+
+// BlockBytes returns the memory one block of this layout occupies.
+func (l ModelKVLayout) BlockBytes() int64 { return l.BlockSizeTokens * l.BytesPerToken }
+
+// BaseKVLayoutModel returns the model whose layout has the smallest block in
+// bytes, ties broken by name: the finest unit in which a multi-model cache
+// reports its model-agnostic block counts. Returns "" for no layouts.
+func BaseKVLayoutModel(layouts map[string]ModelKVLayout) string {
+	base, found := "", false
+	for name, l := range layouts {
+		if !found {
+			base, found = name, true
+			continue
+		}
+		b := layouts[base]
+		if l.BlockBytes() < b.BlockBytes() || (l.BlockBytes() == b.BlockBytes() && name < base) {
+			base = name
+		}
+	}
+	return base
 }
 
 // NewKVCacheConfig creates a KVCacheConfig with all fields explicitly set.
@@ -51,6 +91,37 @@ func NewKVCacheConfig(totalKVBlocks, blockSizeTokens, kvCPUBlocks int64,
 	}
 }
 
+// NewMultiModelKVCacheConfig creates a single-tier KVCacheConfig for an
+// instance serving several models with different KV block sizes from one
+// pool of capacityBytes. TotalKVBlocks and BlockSizeTokens describe the pool
+// in the base model's blocks (BaseKVLayoutModel), the unit the cache reports
+// model-agnostic occupancy in. Panics on a non-positive capacity, an empty
+// layout set, or a non-positive layout dimension.
+func NewMultiModelKVCacheConfig(capacityBytes int64, layouts map[string]ModelKVLayout) KVCacheConfig {
+	if capacityBytes <= 0 {
+		panic(fmt.Sprintf("NewMultiModelKVCacheConfig: capacityBytes must be > 0, got %d", capacityBytes))
+	}
+	if len(layouts) == 0 {
+		panic("NewMultiModelKVCacheConfig: at least one model layout is required")
+	}
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names) // R2: deterministic panic message
+	for _, name := range names {
+		if l := layouts[name]; l.BlockSizeTokens <= 0 || l.BytesPerToken <= 0 {
+			panic(fmt.Sprintf("NewMultiModelKVCacheConfig: model %q: BlockSizeTokens and BytesPerToken must be > 0, got %d and %d",
+				name, l.BlockSizeTokens, l.BytesPerToken))
+		}
+	}
+	base := layouts[BaseKVLayoutModel(layouts)]
+	cfg := NewKVCacheConfig(max(capacityBytes/base.BlockBytes(), 1), base.BlockSizeTokens, 0, 0, 0, 0)
+	cfg.ModelKVLayouts = layouts
+	cfg.KVCapacityBytes = capacityBytes
+	return cfg
+}
+
 // BatchConfig groups batch formation parameters.
 type BatchConfig struct {
 	MaxRunningReqs            int64 // max requests in RunningBatch
@@ -119,8 +190,12 @@ type ModelHardwareConfig struct {
 	// MoE models on the trained-physics backend.
 	MoECommBackend string
 
-	Backend     string // latency model backend: "" or "roofline" (default), "trained-physics"
+	Backend     string // latency model backend: "" or "roofline" (default), "trained-physics", "table"
 	MaxModelLen int64  // max total sequence length (input + output); 0 = unlimited (mirrors vLLM --max-model-len)
+
+	// StepTimeTable is the path to a CSV of measured step times that the "table"
+	// latency backend interpolates. Empty for every other backend.
+	StepTimeTable string
 }
 
 // NewModelHardwareConfig creates a ModelHardwareConfig with all fields explicitly set.
@@ -139,7 +214,7 @@ type ModelHardwareConfig struct {
 // through NewLatencyModel, so a zero-TP divisor cannot reach latency math.
 func NewModelHardwareConfig(modelConfig ModelConfig, hwConfig HardwareCalib,
 	model, gpu string, tp, dp int, enableExpertParallel bool,
-	moeCommBackend, backend string, maxModelLen int64) ModelHardwareConfig {
+	moeCommBackend, backend string, maxModelLen int64, stepTimeTable string) ModelHardwareConfig {
 	if maxModelLen < 0 {
 		panic(fmt.Sprintf("NewModelHardwareConfig: MaxModelLen must be >= 0, got %d", maxModelLen))
 	}
@@ -162,6 +237,7 @@ func NewModelHardwareConfig(modelConfig ModelConfig, hwConfig HardwareCalib,
 		MoECommBackend:       moeCommBackend,
 		Backend:              backend,
 		MaxModelLen:          maxModelLen,
+		StepTimeTable:        stepTimeTable,
 	}
 }
 
@@ -208,16 +284,18 @@ func (c ModelHardwareConfig) EffectiveEP() int {
 
 // PolicyConfig groups scheduling and preemption policy selection.
 type PolicyConfig struct {
-	Scheduler        string // "fcfs" (default), "priority-fcfs", "sjf", "reverse-priority"
-	PreemptionPolicy string // "fcfs" (default) or "priority"
+	Scheduler        string // "fcfs" (default), "priority-fcfs", "sjf", "reverse-priority", "prefix-pack", "wfq"
+	PreemptionPolicy string // "fcfs" (default), "priority", "priority-admission", or "priority-inheritance"
+	PriorityPolicy   string // source of Request.Priority: "slo-class" (default) or "explicit"
 }
 
 // NewPolicyConfig creates a PolicyConfig with all fields explicitly set.
 // This is the canonical constructor — all construction sites must use it (R4).
-func NewPolicyConfig(scheduler, preemptionPolicy string) PolicyConfig {
+func NewPolicyConfig(scheduler, preemptionPolicy, priorityPolicy string) PolicyConfig {
 	return PolicyConfig{
 		Scheduler:        scheduler,
 		PreemptionPolicy: preemptionPolicy,
+		PriorityPolicy:   priorityPolicy,
 	}
 }
 
@@ -43,7 +43,7 @@ func TestNewLatencyCoeffs_FieldEquivalence(t *testing.T) {
 func TestNewModelHardwareConfig_FieldEquivalence(t *testing.T) {
 	mc := ModelConfig{NumLayers: 32}
 	hw := HardwareCalib{TFlopsPeak: 1000.0, MemoryGiB: 80.0}
-	got := NewModelHardwareConfig(mc, hw, "llama", "H100", 2, 1, false, "", "roofline", 8192)
+	got := NewModelHardwareConfig(mc, hw, "llama", "H100", 2, 1, false, "", "roofline", 8192, "steps.csv")
 	want := ModelHardwareConfig{
 		ModelConfig:          mc,
 		HWConfig:             hw,
@@ -54,6 +54,7 @@ func TestNewModelHardwareConfig_FieldEquivalence(t *testing.T) {
 		EnableExpertParallel: false,
 		Backend:              "roofline",
 		MaxModelLen:          8192,
+		StepTimeTable:        "steps.csv",
 	}
 	assert.Equal(t, want, got)
 }
@@ -99,7 +100,7 @@ func TestModelHardwareConfig_ParallelismHelpers(t *testing.T) {
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			c := NewModelHardwareConfig(tc.mc, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, tc.ep, "", "trained-physics", 0)
+			c := NewModelHardwareConfig(tc.mc, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, tc.ep, "", "trained-physics", 0, "")
 			assert.Equal(t, tc.wantDP, c.EffectiveDP(), "EffectiveDP")
 			assert.Equal(t, tc.wantMoEGroup, c.EffectiveMoEGroupSize(), "EffectiveMoEGroupSize")
 			assert.Equal(t, tc.wantEP, c.EffectiveEP(), "EffectiveEP")
@@ -155,7 +156,7 @@ func TestNewModelHardwareConfig_DPValidation(t *testing.T) {
 					t.Errorf("panic message %q should contain constructor name", msg)
 				}
 			}()
-			NewModelHardwareConfig(tc.mc, HardwareCalib{}, "m", "H100", 2, tc.dp, false, "", "trained-physics", 0)
+			NewModelHardwareConfig(tc.mc, HardwareCalib{}, "m", "H100", 2, tc.dp, false, "", "trained-physics", 0, "")
 		})
 	}
 }
@@ -165,7 +166,7 @@ func TestNewModelHardwareConfig_DPValidation(t *testing.T) {
 func TestNewModelHardwareConfig_MoE_DPAllowed(t *testing.T) {
 	moe := ModelConfig{NumLayers: 32, NumLocalExperts: 8}
 	for _, ep := range []bool{false, true} {
-		c := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", 2, 4, ep, "", "trained-physics", 0)
+		c := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", 2, 4, ep, "", "trained-physics", 0, "")
 		assert.Equal(t, 4, c.DP)
 		assert.Equal(t, ep, c.EnableExpertParallel)
 		assert.Equal(t, 8, c.EffectiveMoEGroupSize()) // TP·DP = 2·4
@@ -181,8 +182,8 @@ func TestNewModelHardwareConfig_MoE_DPAllowed(t *testing.T) {
 func TestEffectiveMoEGroupSize_EPModeIndependent(t *testing.T) {
 	moe := ModelConfig{NumLayers: 32, NumLocalExperts: 8}
 	for _, tc := range []struct{ tp, dp int }{{2, 2}, {4, 2}, {1, 4}, {2, 1}} {
-		off := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, false, "", "trained-physics", 0)
-		on := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, true, "", "trained-physics", 0)
+		off := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, false, "", "trained-physics", 0, "")
+		on := NewModelHardwareConfig(moe, HardwareCalib{}, "m", "H100", tc.tp, tc.dp, true, "", "trained-physics", 0, "")
 		assert.Equalf(t, off.EffectiveMoEGroupSize(), on.EffectiveMoEGroupSize(),
 			"flattened MoE group must be EP-mode-independent at TP=%d,DP=%d", tc.tp, tc.dp)
 		// And when EP is on, EP equals that same group.
@@ -211,13 +212,13 @@ func TestIsMoE_Boundary(t *testing.T) {
 }
 
 func TestNewPolicyConfig_FieldEquivalence(t *testing.T) {
-	got := NewPolicyConfig("priority-fcfs", "")
-	want := PolicyConfig{Scheduler: "priority-fcfs", PreemptionPolicy: ""}
+	got := NewPolicyConfig("priority-fcfs", "", PriorityPolicyExplicit)
+	want := PolicyConfig{Scheduler: "priority-fcfs", PreemptionPolicy: "", PriorityPolicy: PriorityPolicyExplicit}
 	assert.Equal(t, want, got)
 }
 
 func TestNewPolicyConfig_DefaultPreemptionPolicy(t *testing.T) {
-	cfg := NewPolicyConfig("fcfs", "")
+	cfg := NewPolicyConfig("fcfs", "", "")
 	if cfg.PreemptionPolicy != "" {
 		t.Errorf("default PreemptionPolicy: got %q, want empty", cfg.PreemptionPolicy)
 	}
@@ -296,6 +297,30 @@ func TestNewKVCacheConfig_ValidTiered_ReturnsConfig(t *testing.T) {
 	}
 }
 
+func TestNewMultiModelKVCacheConfig_DescribesPoolInBaseModelBlocks(t *testing.T) {
+	layouts := map[string]ModelKVLayout{
+		"large": {BlockSizeTokens: 32, BytesPerToken: 4},
+		"small": {BlockSizeTokens: 16, BytesPerToken: 2},
+		"tied":  {BlockSizeTokens: 8, BytesPerToken: 4},
+	}
+	// "small" and "tied" both have 32-byte blocks; the name breaks the tie.
+	assert.Equal(t, "small", BaseKVLayoutModel(layouts))
+
+	got := NewMultiModelKVCacheConfig(4096, layouts)
+	assert.Equal(t, int64(128), got.TotalKVBlocks)
+	assert.Equal(t, int64(16), got.BlockSizeTokens)
+	assert.Equal(t, int64(0), got.KVCPUBlocks)
+	assert.Equal(t, int64(4096), got.KVCapacityBytes)
+	assert.Equal(t, layouts, got.ModelKVLayouts)
+
+	assert.PanicsWithValue(t, "NewMultiModelKVCacheConfig: capacityBytes must be > 0, got 0", func() {
+		NewMultiModelKVCacheConfig(0, layouts)
+	})
+	assert.PanicsWithValue(t, `NewMultiModelKVCacheConfig: model "a": BlockSizeTokens and BytesPerToken must be > 0, got 16 and 0`, func() {
+		NewMultiModelKVCacheConfig(4096, map[string]ModelKVLayout{"a": {BlockSizeTokens: 16}, "b": {}})
+	})
+}
+
 func TestEffectiveWeightBytesPerParam_WhenSet_ReturnsWeightValue(t *testing.T) {
 	// BC-4: GIVEN WeightBytesPerParam > 0, THEN returns WeightBytesPerParam
 	mc := ModelConfig{BytesPerParam: 2.0, WeightBytesPerParam: 0.5}
@@ -0,0 +1,79 @@
+package sim
+
+import (
+	"math"
+	"sort"
+)
+
+// SLOClassCritical is the SLO class served from the capacity
+// CriticalReserveBatchFormation holds back.
+const SLOClassCritical = "critical"
+
+// CriticalReserveBatchFormation is VLLMBatchFormation with a share of every
+// step's capacity held back for critical-class requests
+// (SimConfig.CriticalReserveFraction), so a flood of lower-class traffic
+// cannot occupy every slot. Queued critical requests are moved ahead of the
+// others (stably, keeping the scheduler's order within each group) so they
+// reach the reserved capacity instead of waiting behind the flood.
+type CriticalReserveBatchFormation struct {
+	inner    *VLLMBatchFormation
+	fraction float64
+}
+
+// NewCriticalReserveBatchFormation creates a CriticalReserveBatchFormation
+// reserving fraction of MaxRunningReqs and MaxScheduledTokens for critical
+// requests, with the default formation's preemption policy.
+// Panics unless 0 < fraction < 1 (R3).
+func NewCriticalReserveBatchFormation(preemptionPolicy string, fraction float64) *CriticalReserveBatchFormation {
+	if !(fraction > 0 && fraction < 1) {
+		panic("NewCriticalReserveBatchFormation: fraction must be in (0, 1)")
+	}
+	return &CriticalReserveBatchFormation{
+		inner:    NewBatchFormation(preemptionPolicy).(*VLLMBatchFormation),
+		fraction: fraction,
+	}
+}
+
+// FormBatch promotes queued critical requests, then forms the batch with the
+// reserve applied to every other request's admission and prefill chunks.
+func (c *CriticalReserveBatchFormation) FormBatch(ctx BatchContext) BatchResult {
+	ctx.CriticalReserveSlots = int64(math.Floor(c.fraction * float64(ctx.MaxRunningReqs)))
+	ctx.CriticalReserveTokens = int64(math.Floor(c.fraction * float64(ctx.MaxScheduledTokens)))
+	ctx.WaitQ.Reorder(func(reqs []*Request) {
+		sort.SliceStable(reqs, func(i, j int) bool {
+			return isCritical(reqs[i]) && !isCritical(reqs[j])
+		})
+	})
+	return c.inner.FormBatch(ctx)
+}
+
+func isCritical(req *Request) bool {
+	return req.SLOClass == SLOClassCritical
+}
+
+// criticalReserveAdmits reports whether next may take a running slot: always
+// for a critical request; otherwise only while the reserved slots not already
+// used by running critical requests stay free.
+func criticalReserveAdmits(ctx BatchContext, next *Request, running []*Request) bool {
+	if ctx.CriticalReserveSlots <= 0 || isCritical(next) {
+		return true
+	}
+	var critical int64
+	for _, r := range running {
+		if isCritical(r) {
+			critical++
+		}
+	}
+	free := max(ctx.CriticalReserveSlots-critical, 0)
+	return int64(len(running)) < ctx.MaxRunningReqs-free
+}
+
+// criticalReserveTokenBudget returns how much of the remaining tokenBudget
+// req's prefill may use: all of it for a critical request, the remainder
+// above CriticalReserveTokens for any other.
+func criticalReserveTokenBudget(ctx BatchContext, req *Request, tokenBudget int64) int64 {
+	if ctx.CriticalReserveTokens <= 0 || isCritical(req) {
+		return tokenBudget
+	}
+	return tokenBudget - ctx.CriticalReserveTokens
+}
@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// runBatchFloodWithCritical floods 4 running slots with 40 long batch-class
+// requests at t=0 and trickles in 5 short critical requests, returning the
+// largest queue wait (ticks) any critical request saw.
+func runBatchFloodWithCritical(t *testing.T, reserve float64) int64 {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(4, 2048, 0)
+	cfg.CriticalReserveFraction = reserve
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	for i := 0; i < 40; i++ {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("batch_%d", i),
+			InputTokens:  make([]TokenID, 256),
+			OutputTokens: make([]TokenID, 200),
+			MaxOutputLen: 200,
+			SLOClass:     "batch",
+			State:        StateQueued,
+		})
+	}
+	for i := 0; i < 5; i++ {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("critical_%d", i),
+			ArrivalTime:  int64(10000 + 20000*i),
+			InputTokens:  make([]TokenID, 64),
+			OutputTokens: make([]TokenID, 8),
+			MaxOutputLen: 8,
+			SLOClass:     SLOClassCritical,
+			State:        StateQueued,
+		})
+	}
+	s.Run()
+	if s.Metrics.CompletedRequests != 45 {
+		t.Fatalf("reserve=%v: CompletedRequests = %d, want 45", reserve, s.Metrics.CompletedRequests)
+	}
+	var worst int64
+	for i := 0; i < 5; i++ {
+		worst = max(worst, s.Metrics.RequestQueueWaits[fmt.Sprintf("critical_%d", i)])
+	}
+	return worst
+}
+
+// TestCriticalReserve_BatchFlood_CriticalScheduledWithinBound verifies that
+// reserving a quarter of the slots keeps critical requests' queue wait within
+// a few steps while batch demand far exceeds capacity.
+func TestCriticalReserve_BatchFlood_CriticalScheduledWithinBound(t *testing.T) {
+	// GIVEN a step time of roughly 1ms, bound critical queue wait at 5 steps
+	const bound = 5000
+
+	// WHEN the flood runs without a reserve, critical requests queue behind it
+	if got := runBatchFloodWithCritical(t, 0); got <= bound {
+		t.Fatalf("without reserve: worst critical queue wait = %d, expected > %d (test would not exercise the reserve)", got, bound)
+	}
+
+	// THEN with one slot (and 512 tokens) reserved they are scheduled within the bound
+	if got := runBatchFloodWithCritical(t, 0.25); got > bound {
+		t.Errorf("with reserve: worst critical queue wait = %d ticks, want <= %d", got, bound)
+	}
+}
@@ -0,0 +1,111 @@
+package sim
+
+import "sort"
+
+// DecodeBucketBatchFormation wraps a BatchFormation with decode length
+// bucketing (SimConfig.DecodeLengthBuckets). Padded decode kernels compute
+// attention for every sequence in a forward pass over the longest context in
+// it, so a batch mixing short and long contexts wastes work on padding. How a
+// step's requests are grouped into forward passes is a batching decision, so
+// it is made here and handed to the kernel as BatchResult.ForwardPasses; the
+// kernel charges each pass with the latency model and sums them.
+type DecodeBucketBatchFormation struct {
+	inner   BatchFormation
+	buckets int
+}
+
+// NewDecodeBucketBatchFormation creates a DecodeBucketBatchFormation splitting
+// each step's decodes into at most buckets padded passes. Panics on a nil
+// inner formation or buckets <= 0 (R3).
+func NewDecodeBucketBatchFormation(inner BatchFormation, buckets int) *DecodeBucketBatchFormation {
+	if inner == nil {
+		panic("NewDecodeBucketBatchFormation: inner must not be nil")
+	}
+	if buckets <= 0 {
+		panic("NewDecodeBucketBatchFormation: buckets must be > 0")
+	}
+	return &DecodeBucketBatchFormation{inner: inner, buckets: buckets}
+}
+
+// FormBatch forms the batch with the inner formation, then groups the
+// scheduled requests (NumNewTokens > 0) into padded forward passes.
+func (d *DecodeBucketBatchFormation) FormBatch(ctx BatchContext) BatchResult {
+	result := d.inner.FormBatch(ctx)
+	if result.HoldUntil > 0 || result.RunningBatch == nil {
+		return result
+	}
+	var scheduled []*Request
+	for _, req := range result.RunningBatch.Requests {
+		if req.NumNewTokens > 0 {
+			scheduled = append(scheduled, req)
+		}
+	}
+	result.ForwardPasses = decodeBucketPasses(scheduled, d.buckets)
+	return result
+}
+
+// decodeBucketPasses sorts the scheduled decode requests by context length and
+// splits them into at most buckets groups at the buckets-1 widest gaps between
+// consecutive lengths; each group is one forward pass with every request
+// padded to the group's longest context. Prefill requests run in the first
+// (shortest-context) pass. buckets = 1 gives one fully padded pass. Padded
+// requests are copies; scheduled is not modified. Returns nil, one unpadded
+// pass, when nothing is decoding.
+func decodeBucketPasses(scheduled []*Request, buckets int) [][]*Request {
+	var prefills, decodes []*Request
+	for _, req := range scheduled {
+		if req.ProgressIndex < req.InputLen() {
+			prefills = append(prefills, req)
+		} else {
+			decodes = append(decodes, req)
+		}
+	}
+	if len(decodes) == 0 {
+		return nil
+	}
+	sort.SliceStable(decodes, func(i, j int) bool { return decodes[i].ProgressIndex < decodes[j].ProgressIndex })
+
+	groups := splitAtWidestGaps(decodes, buckets)
+	passes := make([][]*Request, 0, len(groups))
+	for i, group := range groups {
+		pass := make([]*Request, 0, len(group)+len(prefills))
+		if i == 0 {
+			pass = append(pass, prefills...)
+		}
+		longest := group[len(group)-1].ProgressIndex
+		for _, req := range group {
+			padded := *req
+			padded.ProgressIndex = longest
+			pass = append(pass, &padded)
+		}
+		passes = append(passes, pass)
+	}
+	return passes
+}
+
+// splitAtWidestGaps splits reqs, sorted by ProgressIndex, into at most n
+// contiguous groups by cutting at the n-1 widest gaps between neighbours.
+// Zero-width gaps are never cut, so equal lengths always share a group.
+// Ties between equal gaps go to the earlier position (deterministic).
+func splitAtWidestGaps(reqs []*Request, n int) [][]*Request {
+	gaps := make([]int, 0, len(reqs))
+	for i := 1; i < len(reqs); i++ {
+		if reqs[i].ProgressIndex > reqs[i-1].ProgressIndex {
+			gaps = append(gaps, i)
+		}
+	}
+	sort.SliceStable(gaps, func(a, b int) bool {
+		return reqs[gaps[a]].ProgressIndex-reqs[gaps[a]-1].ProgressIndex >
+			reqs[gaps[b]].ProgressIndex-reqs[gaps[b]-1].ProgressIndex
+	})
+	cuts := gaps[:min(len(gaps), max(n-1, 0))]
+	sort.Ints(cuts)
+
+	groups := make([][]*Request, 0, len(cuts)+1)
+	start := 0
+	for _, c := range cuts {
+		groups = append(groups, reqs[start:c])
+		start = c
+	}
+	return append(groups, reqs[start:])
+}
@@ -0,0 +1,185 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// contextStepModel charges a fixed per-pass cost plus attention work
+// proportional to each decode request's context and each prefill's new tokens.
+type contextStepModel struct {
+	perPass int64
+}
+
+func (m *contextStepModel) StepTime(batch []*Request) int64 {
+	t := m.perPass
+	for _, req := range batch {
+		if req.ProgressIndex < req.InputLen() {
+			t += int64(req.NumNewTokens)
+		} else {
+			t += req.ProgressIndex / 10
+		}
+	}
+	return t
+}
+func (m *contextStepModel) QueueingTime(req *Request) int64  { return 0 }
+func (m *contextStepModel) OutputTokenProcessingTime() int64 { return 0 }
+func (m *contextStepModel) PostDecodeFixedOverhead() int64   { return 0 }
+
+// runBimodalDecode runs 8 short-context (100-token) and 8 long-context
+// (8000-token) requests, all arriving at t=0 with 200 output tokens, and
+// returns the total simulated decode time: the sum of all decode-step times.
+func runBimodalDecode(t *testing.T, buckets int) int64 {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(256, 100000, 0)
+	cfg.DecodeLengthBuckets = buckets
+	model := &stepRecorder{inner: &contextStepModel{perPass: 500}}
+	s := newSimulatorWithModel(t, cfg, model)
+	for i := 0; i < 16; i++ {
+		input := 100
+		if i%2 == 1 {
+			input = 8000
+		}
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			InputTokens:  make([]TokenID, input),
+			OutputTokens: make([]TokenID, 200),
+			State:        StateQueued,
+		})
+	}
+	s.Run()
+	if s.Metrics.CompletedRequests != 16 {
+		t.Fatalf("buckets=%d: CompletedRequests = %d, want 16", buckets, s.Metrics.CompletedRequests)
+	}
+	return s.Clock - model.prefillOnlyTime
+}
+
+// stepRecorder wraps a LatencyModel and accumulates the time of passes that
+// contain only prefill requests, so the caller can isolate decode time.
+type stepRecorder struct {
+	inner           LatencyModel
+	prefillOnlyTime int64
+}
+
+func (m *stepRecorder) StepTime(batch []*Request) int64 {
+	t := m.inner.StepTime(batch)
+	for _, req := range batch {
+		if req.ProgressIndex >= req.InputLen() {
+			return t
+		}
+	}
+	m.prefillOnlyTime += t
+	return t
+}
+func (m *stepRecorder) QueueingTime(req *Request) int64  { return 0 }
+func (m *stepRecorder) OutputTokenProcessingTime() int64 { return 0 }
+func (m *stepRecorder) PostDecodeFixedOverhead() int64   { return 0 }
+
+// TestDecodeLengthBuckets_BimodalContexts_BucketingReducesDecodeTime verifies
+// that splitting a bimodal decode batch into length buckets removes the
+// padding of short contexts up to the long ones, lowering total decode time
+// despite the extra pass per step.
+func TestDecodeLengthBuckets_BimodalContexts_BucketingReducesDecodeTime(t *testing.T) {
+	// GIVEN a batch mixing 100-token and 8000-token contexts
+	// WHEN decode runs as one padded pass vs two length-bucketed passes
+	padded := runBimodalDecode(t, 1)
+	bucketed := runBimodalDecode(t, 2)
+
+	// THEN bucketing reduces total decode time
+	if bucketed >= padded {
+		t.Errorf("decode time: bucketed=%d padded=%d, want bucketed < padded", bucketed, padded)
+	}
+	// AND unpadded charging (the default) is a lower bound for both
+	unpadded := runBimodalDecode(t, 0)
+	if unpadded > bucketed {
+		t.Errorf("decode time: unpadded=%d bucketed=%d, want unpadded <= bucketed", unpadded, bucketed)
+	}
+}
+
+func TestSplitAtWidestGaps(t *testing.T) {
+	reqsAt := func(lens ...int64) []*Request {
+		out := make([]*Request, len(lens))
+		for i, l := range lens {
+			out[i] = &Request{ProgressIndex: l}
+		}
+		return out
+	}
+	sizes := func(groups [][]*Request) []int {
+		out := make([]int, len(groups))
+		for i, g := range groups {
+			out[i] = len(g)
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		lens []int64
+		n    int
+		want []int
+	}{
+		{"one bucket keeps all", []int64{10, 20, 5000}, 1, []int{3}},
+		{"cuts the widest gap", []int64{10, 20, 30, 5000, 5100}, 2, []int{3, 2}},
+		{"three buckets", []int64{10, 20, 1000, 1010, 5000}, 3, []int{2, 2, 1}},
+		{"equal lengths never split", []int64{50, 50, 50}, 3, []int{3}},
+		{"fewer gaps than buckets", []int64{10, 90}, 4, []int{1, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sizes(splitAtWidestGaps(reqsAt(tc.lens...), tc.n))
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("group sizes = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// fixedBatchFormation returns the same result on every call.
+type fixedBatchFormation struct{ result BatchResult }
+
+func (f *fixedBatchFormation) FormBatch(BatchContext) BatchResult { return f.result }
+
+// TestDecodeBucketBatchFormation_GroupsScheduledIntoPaddedPasses verifies that
+// the wrapper hands the kernel padded forward passes over the scheduled
+// requests only, leaving the running requests themselves unpadded.
+func TestDecodeBucketBatchFormation_GroupsScheduledIntoPaddedPasses(t *testing.T) {
+	decode := func(id string, context int64) *Request {
+		return &Request{ID: id, InputTokens: make([]TokenID, 10), ProgressIndex: context, NumNewTokens: 1}
+	}
+	prefill := &Request{ID: "prefill", InputTokens: make([]TokenID, 64), NumNewTokens: 64}
+	idle := decode("idle", 9000)
+	idle.NumNewTokens = 0
+	short1, short2, long := decode("short_1", 100), decode("short_2", 120), decode("long", 8000)
+	inner := &fixedBatchFormation{result: BatchResult{
+		RunningBatch: &Batch{Requests: []*Request{long, prefill, idle, short2, short1}},
+	}}
+
+	// WHEN the batch is formed with two buckets
+	result := NewDecodeBucketBatchFormation(inner, 2).FormBatch(BatchContext{})
+
+	// THEN the prefill joins the short-context pass, each decode is padded to
+	// its pass's longest context, and the unscheduled request is left out
+	if len(result.ForwardPasses) != 2 {
+		t.Fatalf("passes = %d, want 2", len(result.ForwardPasses))
+	}
+	want := [][]string{{"prefill:0", "short_1:120", "short_2:120"}, {"long:8000"}}
+	for i, pass := range result.ForwardPasses {
+		var got []string
+		for _, r := range pass {
+			got = append(got, fmt.Sprintf("%s:%d", r.ID, r.ProgressIndex))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want[i]) {
+			t.Errorf("pass %d = %v, want %v", i, got, want[i])
+		}
+	}
+	// AND the running requests keep their own progress
+	if short1.ProgressIndex != 100 {
+		t.Errorf("short_1 ProgressIndex = %d, want 100 (padding must not leak)", short1.ProgressIndex)
+	}
+
+	// AND a step with nothing decoding runs as one unpadded pass
+	inner.result = BatchResult{RunningBatch: &Batch{Requests: []*Request{prefill}}}
+	if got := NewDecodeBucketBatchFormation(inner, 2).FormBatch(BatchContext{}).ForwardPasses; got != nil {
+		t.Errorf("prefill-only ForwardPasses = %v, want nil", got)
+	}
+}
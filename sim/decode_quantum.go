@@ -0,0 +1,86 @@
+package sim
+
+import "sort"
+
+// decodeService is the service a request has received for fair decode: the
+// output tokens decoded so far (0 while still in prefill).
+func decodeService(req *Request) int64 {
+	return max(0, req.ProgressIndex-req.InputLen())
+}
+
+// rotateDecodeQuantum time-slices decode slots (SimConfig.DecodeQuantumSteps).
+// On every quantum boundary it stably reorders the running batch by least
+// service received, so the least-served requests get the token budget first
+// and the most-served are the preemption victims. It then pairs the
+// most-served decode requests with the wait-queue head in order and suspends
+// each one that has received strictly more service than its waiting partner:
+// it moves to the back of the queue with its KV blocks and progress kept
+// (Request.Suspended), and the freed slot lets the waiting request in.
+//
+// Rotation stops at the first pair whose waiting request could not get its KV
+// blocks from free capacity, since suspended requests keep theirs: a new
+// request needs blocks for its whole input, a suspended one a single block.
+func rotateDecodeQuantum(ctx BatchContext, batch *Batch) {
+	if ctx.DecodeQuantumSteps <= 0 || ctx.StepCount%ctx.DecodeQuantumSteps != 0 || len(batch.Requests) == 0 {
+		return
+	}
+	sort.SliceStable(batch.Requests, func(i, j int) bool {
+		return decodeService(batch.Requests[i]) < decodeService(batch.Requests[j])
+	})
+
+	waiting := ctx.WaitQ.Items()
+	freeBlocks := ctx.KVCache.TotalCapacity() - ctx.KVCache.UsedBlocks()
+	blockSize := ctx.KVCache.BlockSize()
+	var suspended []*Request
+	for i, w := 0, 0; i < len(batch.Requests) && w < len(waiting); i++ {
+		req := batch.Requests[len(batch.Requests)-1-i]
+		if req.ProgressIndex < req.InputLen() {
+			continue // still in prefill
+		}
+		next := waiting[w]
+		if decodeService(req) <= decodeService(next) {
+			break
+		}
+		need := int64(1)
+		switch {
+		case next.SwappedOut:
+			need = swappedBlocks(next, blockSize) + 1
+		case !next.Suspended:
+			need = (next.InputLen() + blockSize - 1) / blockSize
+		}
+		if need > freeBlocks {
+			break
+		}
+		freeBlocks -= need
+		suspended = append(suspended, req)
+		w++
+	}
+	if len(suspended) == 0 {
+		return
+	}
+
+	kept := make([]*Request, 0, len(batch.Requests)-len(suspended))
+	for _, req := range batch.Requests {
+		if !containsRequest(suspended, req) {
+			kept = append(kept, req)
+		}
+	}
+	batch.Requests = kept
+	// Enqueue after the loop above: waiting aliases the queue's storage.
+	for _, req := range suspended {
+		req.State = StateQueued
+		req.Suspended = true
+		req.NumNewTokens = 0
+		ctx.WaitQ.Enqueue(req)
+	}
+}
+
+// containsRequest reports whether reqs holds req (pointer identity).
+func containsRequest(reqs []*Request, req *Request) bool {
+	for _, r := range reqs {
+		if r == req {
+			return true
+		}
+	}
+	return false
+}
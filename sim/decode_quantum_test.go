@@ -0,0 +1,50 @@
+package sim
+
+import (
+	"math"
+	"testing"
+)
+
+// runConcurrentLongDecodes runs 16 long-output requests arriving together on
+// an instance with 4 decode slots and returns the completion time of each.
+func runConcurrentLongDecodes(t *testing.T, quantum int) []float64 {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(4, 2048, 0)
+	cfg.DecodeQuantumSteps = quantum
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	runToCompletion(t, s, uniformRequests(16, 64, 200, 0))
+	if used := s.KVCache.UsedBlocks(); used != 0 {
+		t.Errorf("quantum=%d: %d KV blocks still used after all requests completed", quantum, used)
+	}
+	times := make([]float64, 0, 16)
+	for _, c := range s.Metrics.RequestCompletionTimes {
+		times = append(times, c)
+	}
+	return times
+}
+
+func stddev(xs []float64) float64 {
+	mean := CalculateMean(xs)
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(ss / float64(len(xs)))
+}
+
+// TestDecodeQuantum_ConcurrentLongOutputs_CompletionTimesCloser verifies that
+// rotating decode slots by least service received spreads progress across
+// all requests, so their completion times cluster instead of finishing in
+// FCFS waves.
+func TestDecodeQuantum_ConcurrentLongOutputs_CompletionTimesCloser(t *testing.T) {
+	// GIVEN 16 concurrent 200-token decodes sharing 4 slots
+	// WHEN run FCFS vs with a 20-step decode quantum
+	fcfs := stddev(runConcurrentLongDecodes(t, 0))
+	fair := stddev(runConcurrentLongDecodes(t, 20))
+
+	// THEN completion times are markedly closer together under the quantum
+	if fair >= fcfs/2 {
+		t.Errorf("completion-time stddev: fair=%.0f fcfs=%.0f, want fair < fcfs/2", fair, fcfs)
+	}
+}
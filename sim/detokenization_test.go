@@ -0,0 +1,62 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// runDetokenizationRequest runs one request with outputLen output tokens at
+// the given detokenization cost and returns the simulator and the request.
+func runDetokenizationRequest(t *testing.T, usPerToken float64, outputLen int) (*Simulator, *Request) {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.DetokenizationUsPerToken = usPerToken
+	s := newFixedStepSimulator(t, cfg)
+	req := uniformRequests(1, 10, outputLen, 0)[0]
+	runToCompletion(t, s, []*Request{req})
+	return s, req
+}
+
+// TestDetokenization_E2EGrowsWithOutputLengthBeyondStepTime verifies that the
+// detokenization term adds coeff × output tokens to E2E on top of what the
+// decode steps explain (TTFT + ΣITL), while GPU step time is unchanged.
+func TestDetokenization_E2EGrowsWithOutputLengthBeyondStepTime(t *testing.T) {
+	const usPerToken = 50.0
+	for _, outputLen := range []int{8, 32} {
+		t.Run(fmt.Sprintf("output=%d", outputLen), func(t *testing.T) {
+			s, req := runDetokenizationRequest(t, usPerToken, outputLen)
+			base, baseReq := runDetokenizationRequest(t, 0, outputLen)
+
+			stepExplained := req.FirstTokenTime
+			for _, itl := range req.ITL {
+				stepExplained += itl
+			}
+			e2e := s.Metrics.RequestE2Es[req.ID]
+			if got, want := e2e-float64(stepExplained), usPerToken*float64(outputLen); got != want {
+				t.Errorf("E2E beyond step time = %v, want %v (coeff × output tokens)", got, want)
+			}
+			if baseE2E := base.Metrics.RequestE2Es[baseReq.ID]; e2e-baseE2E != usPerToken*float64(outputLen) {
+				t.Errorf("E2E = %v, baseline %v: delta %v, want %v", e2e, baseE2E, e2e-baseE2E, usPerToken*float64(outputLen))
+			}
+			// CPU-side cost: GPU timeline, TTFT, and ITL are untouched.
+			if s.Clock != base.Clock {
+				t.Errorf("Clock = %d, want %d (detokenization must not lengthen step time)", s.Clock, base.Clock)
+			}
+			if s.Metrics.RequestTTFTs[req.ID] != base.Metrics.RequestTTFTs[baseReq.ID] ||
+				s.Metrics.RequestITLs[req.ID] != base.Metrics.RequestITLs[baseReq.ID] {
+				t.Errorf("TTFT/ITL changed by detokenization overhead")
+			}
+		})
+	}
+}
+
+func TestNewSimulator_DetokenizationValidation(t *testing.T) {
+	for _, v := range []float64{-1, math.NaN(), math.Inf(1)} {
+		cfg := newTestSimConfig()
+		cfg.DetokenizationUsPerToken = v
+		if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+			t.Errorf("DetokenizationUsPerToken=%v: expected error", v)
+		}
+	}
+}
@@ -44,6 +44,7 @@ func (e *ArrivalEvent) Execute(sim *Simulator) {
 
 	// Trigger queued event with processing delay
 	queued_delay := sim.latencyModel.QueueingTime(e.Request) // coming from alpha model
+	sim.Metrics.TimeBudget.QueueingTicks += queued_delay
 	sim.Schedule(&QueuedEvent{
 		time:    e.time + queued_delay,
 		Request: e.Request,
@@ -66,6 +67,7 @@ func (e *QueuedEvent) Priority() int    { return PriorityQueued }
 // dropped-unservable). Sets sim.stepEvent to prevent duplicate scheduling.
 func (e *QueuedEvent) Execute(sim *Simulator) {
 	logrus.Debugf("<< Queued: %s at %d ticks", e.Request.ID, e.time)
+	delete(sim.inTransit, e.Request.ID)
 
 	// Enqueue the arriving request into the waiting queue
 	sim.EnqueueRequest(e.Request)
@@ -147,6 +149,26 @@ func (e *AdapterLoadCompletionEvent) Execute(sim *Simulator) {
 	sim.completeAdapterLoad(e.time, e.Adapter)
 }
 
+// BatchFillWakeEvent ends a min-batch-fill hold (SimConfig.MinBatchFill): at
+// the hold deadline the idle instance re-forms its batch, launching with
+// whatever has queued. A wake superseded by a later hold is a no-op.
+type BatchFillWakeEvent struct {
+	time int64 // Hold deadline (in ticks): oldest queued arrival + BatchFillMaxWaitTicks
+}
+
+func (e *BatchFillWakeEvent) Timestamp() int64 { return e.time }
+func (e *BatchFillWakeEvent) Priority() int    { return PriorityStep }
+
+// Execute re-forms the batch if this is the current hold and no step is pending.
+func (e *BatchFillWakeEvent) Execute(sim *Simulator) {
+	if sim.batchFillWake != e {
+		return
+	}
+	logrus.Debugf("<< BatchFillWake at %d ticks", e.time)
+	sim.batchFillWake = nil
+	sim.ScheduleStepIfIdle(e.time)
+}
+
 // TimeoutEvent models client-side request cancellation at the deadline tick.
 // Classification: mixed exogenous/endogenous (round-0 exogenous, follow-up endogenous).
 // Priority 5: fires after all other event types at equal timestamps (BC-12).
@@ -164,7 +186,7 @@ func (e *TimeoutEvent) Priority() int    { return PriorityTimeout }
 // any container yet (WaitQ.Remove returns false, safe no-op).
 func (e *TimeoutEvent) Execute(sim *Simulator) {
 	// No-op guard: request already completed or timed out (BC-3)
-	if e.Request.State == StateCompleted || e.Request.State == StateTimedOut {
+	if e.Request.State == StateCompleted || e.Request.State == StateTimedOut || e.Request.QueueDropped {
 		return
 	}
 	wasRunning := e.Request.State == StateRunning
@@ -182,6 +204,7 @@ func (e *TimeoutEvent) Execute(sim *Simulator) {
 	// Clean up computed-token tracking for ALL timed-out requests (prevents memory leak).
 	// A preempted-then-queued request still has an entry from its prior running phase.
 	delete(sim.reqNumComputedTokens, e.Request.ID)
+	delete(sim.reqComputeTicks, e.Request.ID)
 
 	if wasRunning {
 		// New-slice construction (R21): build excluding timed-out request.
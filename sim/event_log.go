@@ -0,0 +1,76 @@
+package sim
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// EventLogRecord is one line of an event log: an event popped from an event
+// queue and executed.
+type EventLogRecord struct {
+	Tick       int64  `json:"tick"`
+	Type       string `json:"type"`                  // concrete event type, e.g. "StepEvent"
+	InstanceID string `json:"instance_id,omitempty"` // empty for cluster-level events
+	RequestID  string `json:"request_id,omitempty"`  // empty when the event carries no request
+}
+
+// EventLog writes one JSON object per executed event (JSON Lines). It is a
+// debugging aid: callers hold a nil *EventLog when logging is disabled, so the
+// event loop pays only a nil check. The first write error is retained and
+// later records are dropped; check Err after the run.
+type EventLog struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewEventLog returns an EventLog writing JSON Lines to w.
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{enc: json.NewEncoder(w)}
+}
+
+// Record appends one record to the log.
+func (l *EventLog) Record(rec EventLogRecord) {
+	if l.err != nil {
+		return
+	}
+	l.err = l.enc.Encode(rec)
+}
+
+// Err returns the first write error, or nil.
+func (l *EventLog) Err() error { return l.err }
+
+// EventTypeName returns the unqualified type name of an event (pointer
+// receivers dereferenced), e.g. "ArrivalEvent".
+func EventTypeName(ev any) string {
+	t := reflect.TypeOf(ev)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// EventRequestID returns the ID of the request an instance event refers to,
+// or "" for events that carry none (StepEvent, AdapterLoadCompletionEvent).
+func EventRequestID(ev Event) string {
+	var req *Request
+	switch e := ev.(type) {
+	case *ArrivalEvent:
+		req = e.Request
+	case *QueuedEvent:
+		req = e.Request
+	case *ScheduledEvent:
+		req = e.Request
+	case *RequestLeftEvent:
+		req = e.Request
+	case *TimeoutEvent:
+		req = e.Request
+	}
+	if req == nil {
+		return ""
+	}
+	return req.ID
+}
@@ -6,6 +6,8 @@ package kv
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 
 	"github.com/sirupsen/logrus"
 
@@ -29,6 +31,18 @@ type KVBlock struct {
 	Tokens   []sim.TokenID // Actual tokens stored in this block; full if len(Tokens) == BlockSizeTokens
 	PrevFree *KVBlock // LRU doubly linked list: previous free block
 	NextFree *KVBlock // LRU doubly linked list: next free block
+
+	// Prefix-index diagnostics (reported by PrefixIndexState, never read by
+	// allocation). PrefixDepth is the number of blocks in the hashed prefix
+	// ending at this block; LastAccess is the clock of the most recent
+	// allocation, append or cache hit.
+	PrefixDepth int64
+	LastAccess  int64
+
+	// PrevHash is the Hash of the block preceding this one in its prefix
+	// ("" for a first block), set with Hash. ExportPrefixes follows it to
+	// rebuild each cached prefix's tokens.
+	PrevHash string
 }
 
 // KVCacheState maintains global KV cache status across all requests.
@@ -46,6 +60,43 @@ type KVCacheState struct {
 	FreeBlockCnt    int64              // Direct count of blocks in free list (vLLM parity)
 	CacheHits       int64              // blocks found via prefix cache (PR12)
 	CacheMisses     int64              // blocks not found, allocated fresh (PR12)
+
+	// SampleMap holds the block tables of samples 1..n-1 of a request with
+	// ParallelSamples > 1 (sample 0 is its RequestMap entry), forked at its
+	// first decode allocation. SharedBlocksSaved counts block allocations
+	// avoided by sharing: prompt blocks referenced by the forked samples,
+	// less the partial blocks later copied on write.
+	SampleMap         map[string][][]int64
+	SharedBlocksSaved int64
+
+	// RoundingWasteTokens sums, over successful AllocateKVBlocks calls, the
+	// unfilled slots left in the request's last block: allocation is in whole
+	// blocks, so a table holding n tokens occupies ceil(n/BlockSizeTokens)
+	// blocks. Allocations counts those calls. Parallel-sample decode forks
+	// are not counted.
+	RoundingWasteTokens int64
+	Allocations         int64
+
+	// groupCounts attributes CacheHits/CacheMisses to the requesting tenant
+	// and SLO class. Lazily allocated on first lookup.
+	groupCounts map[sim.CacheGroup]*sim.CacheHitCounts
+
+	// budget, when set, is a byte pool this cache shares with other models'
+	// caches (see MultiModelKVCache); each block draws blockBytes from it.
+	// nil for a standalone cache, which is limited by TotalBlocks alone.
+	budget     *byteBudget
+	blockBytes int64
+
+	// clock is the current tick (SetClock), stamped on blocks as LastAccess.
+	clock int64
+
+	// CheckRefCounts is a debug mode (KVCacheConfig.CheckRefCounts) that
+	// verifies the reference counts on every release: a block whose count
+	// would go negative (a double free), or one returned to the free list
+	// while another request's block table still references it, panics with
+	// the releasing request's ID. Off by default; each freed block costs a
+	// scan of all block tables.
+	CheckRefCounts bool
 }
 
 // NewKVCacheState initializes the KVCacheState and places all blocks in the free list in order.
@@ -62,6 +113,7 @@ func NewKVCacheState(totalBlocks int64, blockSizeTokens int64) *KVCacheState {
 		Blocks:          make([]*KVBlock, totalBlocks),
 		RequestMap:      make(map[string][]int64),
 		HashToBlock:     make(map[string]int64),
+		SampleMap:       make(map[string][][]int64),
 	}
 	for i := int64(0); i < totalBlocks; i++ {
 		blk := &KVBlock{ID: i}
@@ -181,6 +233,12 @@ func (kvc *KVCacheState) SnapshotCachedBlocksFn() func([]sim.TokenID) int {
 // endIndex is non-inclusive
 func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, endIndex int64, cachedBlocks []int64) bool {
 	reqID := req.ID
+	if req.NoCache {
+		cachedBlocks = nil // cache-bypass: never claim prefix hits
+	}
+	if req.ParallelSamples > 1 && req.ProgressIndex >= req.InputLen() {
+		return kvc.allocateSampleDecode(req, startIndex, endIndex)
+	}
 	logrus.Debugf("AllocateBlock for ReqID: %s, Num Inputs: %d, startIndex = %d, endIndex = %d", req.ID, req.InputLen(), startIndex, endIndex)
 
 	var newTokens []sim.TokenID
@@ -193,17 +251,7 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 		// into the request's existing partially-filled last block. Without this,
 		// the pre-check over-estimates by up to 1 block, causing false rejections
 		// when free blocks are tight (#492).
-		effectiveTokens := util.Len64(newTokens)
-		if ids, hasBlocks := kvc.RequestMap[reqID]; hasBlocks && len(ids) > 0 {
-			lastBlk := kvc.Blocks[ids[len(ids)-1]]
-			// spare < BlockSizeTokens excludes empty blocks (0 tokens stored)
-			if spare := kvc.BlockSizeTokens - util.Len64(lastBlk.Tokens); spare > 0 && spare < kvc.BlockSizeTokens {
-				effectiveTokens -= min(spare, effectiveTokens)
-			}
-		}
-		if effectiveTokens > 0 {
-			numNewBlocks = (effectiveTokens + kvc.BlockSizeTokens - 1) / kvc.BlockSizeTokens
-		}
+		numNewBlocks = kvc.prefillBlocksNeeded(reqID, util.Len64(newTokens))
 
 		// Account for cached blocks that will leave the free list when claimed.
 		// Mirrors vLLM's num_evictable_blocks (single_type_kv_cache_manager.py:124-127).
@@ -270,11 +318,13 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 			for _, blockId := range cachedBlocks {
 				blk := kvc.Blocks[blockId]
 				blk.RefCount++
+				blk.LastAccess = kvc.clock
 				if !blk.InUse {
 					blk.InUse = true
 					kvc.removeFromFreeList(blk)
 				}
 				kvc.CacheHits++
+				kvc.countsFor(req).Hits++
 				logrus.Debugf("Hit KV Cache for req: %s of length: %d", req.ID, util.Len64(cachedBlocks)*kvc.BlockSizeTokens)
 				kvc.RequestMap[reqID] = append(kvc.RequestMap[reqID], blockId)
 			}
@@ -290,9 +340,10 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 			remaining := kvc.BlockSizeTokens - util.Len64(latestBlk.Tokens)
 			toksToAppend := newTokens[newTokenProgressIndex:min(newTokenProgressIndex+remaining, util.Len64(newTokens))]
 			latestBlk.Tokens = append(latestBlk.Tokens, toksToAppend...)
+			latestBlk.LastAccess = kvc.clock
 			newTokenProgressIndex += util.Len64(toksToAppend)
 			logrus.Debugf("Appending to latest blk: req: %s, newTokenProgressIndex = %d, appended=%d tokens", req.ID, newTokenProgressIndex, util.Len64(toksToAppend))
-			if util.Len64(latestBlk.Tokens) == kvc.BlockSizeTokens {
+			if util.Len64(latestBlk.Tokens) == kvc.BlockSizeTokens && !req.NoCache {
 				// latestBlk is full — compute its hierarchical hash.
 				// Chain from the previous block's hash (or "" if first block).
 				prevHash := ""
@@ -301,6 +352,8 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 				}
 				h := hash.HashBlock(prevHash, latestBlk.Tokens)
 				latestBlk.Hash = h
+				latestBlk.PrevHash = prevHash
+				latestBlk.PrefixDepth = int64(len(ids))
 				kvc.HashToBlock[h] = latestBlk.ID
 			}
 		} else {
@@ -347,14 +400,19 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 				blk.Tokens = append([]sim.TokenID{}, tok...) // copy tokens
 				blk.RefCount = 1
 				blk.InUse = true
+				blk.LastAccess = kvc.clock
 				kvc.CacheMisses++
+				kvc.countsFor(req).Misses++
 
-				if util.Len64(blk.Tokens) == kvc.BlockSizeTokens && req.ProgressIndex < req.InputLen() {
+				if util.Len64(blk.Tokens) == kvc.BlockSizeTokens && req.ProgressIndex < req.InputLen() && !req.NoCache {
 					// Only compute prefix hash during prefill (not decode).
 					// During decode, blocks hold output tokens that should not
 					// participate in prefix caching (input sequences only).
+					// NoCache blocks stay unhashed so they never enter the index.
 					h := hash.HashBlock(prevHash, blk.Tokens)
 					blk.Hash = h
+					blk.PrevHash = prevHash
+					blk.PrefixDepth = int64(len(kvc.RequestMap[reqID])) + 1
 					kvc.HashToBlock[h] = blk.ID
 					prevHash = h
 				}
@@ -365,9 +423,91 @@ func (kvc *KVCacheState) AllocateKVBlocks(req *sim.Request, startIndex int64, en
 		}
 	}
 
+	kvc.recordRoundingWaste(reqID)
 	return true
 }
 
+// prefillBlocksNeeded returns the fresh blocks reqID needs for numTokens more
+// prompt tokens, after filling the spare slots of its partially-filled last
+// block.
+func (kvc *KVCacheState) prefillBlocksNeeded(reqID string, numTokens int64) int64 {
+	if ids, hasBlocks := kvc.RequestMap[reqID]; hasBlocks && len(ids) > 0 {
+		lastBlk := kvc.Blocks[ids[len(ids)-1]]
+		// spare < BlockSizeTokens excludes empty blocks (0 tokens stored)
+		if spare := kvc.BlockSizeTokens - util.Len64(lastBlk.Tokens); spare > 0 && spare < kvc.BlockSizeTokens {
+			numTokens -= min(spare, numTokens)
+		}
+	}
+	return (numTokens + kvc.BlockSizeTokens - 1) / kvc.BlockSizeTokens
+}
+
+// CanAllocateAfterRelease reports whether the prefill allocation
+// AllocateKVBlocks(req, startIndex, endIndex, cachedBlocks) would pass its
+// free-block pre-check once every victim's blocks (parallel samples included)
+// were released. A victim's block is counted as freed only if the victims
+// hold all of its references. Pure query: nothing is released.
+func (kvc *KVCacheState) CanAllocateAfterRelease(victims []*sim.Request, req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	return kvc.canAllocateAfterRelease(victims, req, startIndex, endIndex, cachedBlocks, 0)
+}
+
+// canAllocateAfterRelease is CanAllocateAfterRelease with extraBudgetBytes
+// also returned to the shared byte budget by the release: the bytes freed by
+// victims in other models' caches.
+func (kvc *KVCacheState) canAllocateAfterRelease(victims []*sim.Request, req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64, extraBudgetBytes int64) bool {
+	refs, freed := kvc.releasableBlocks(victims)
+	if req.NoCache {
+		cachedBlocks = nil
+	}
+	need := kvc.prefillBlocksNeeded(req.ID, endIndex-startIndex)
+	for _, id := range cachedBlocks {
+		// A cached block leaves the free list when claimed, whether it is
+		// there now or is put there by the release.
+		if blk := kvc.Blocks[id]; !blk.InUse || refs[id] == blk.RefCount {
+			need++
+		}
+	}
+	free := kvc.FreeBlockCnt + freed
+	if kvc.budget != nil {
+		free = min(free, (kvc.budget.freeBytes()+extraBudgetBytes)/kvc.blockBytes+freed)
+	}
+	return need <= free
+}
+
+// releasableBlocks counts the victims' references per block (parallel
+// samples included) and returns them with the number of blocks the victims
+// hold every reference to, i.e. that their release would free.
+func (kvc *KVCacheState) releasableBlocks(victims []*sim.Request) (refs map[int64]int, freed int64) {
+	refs = make(map[int64]int)
+	for _, v := range victims {
+		for _, id := range kvc.RequestMap[v.ID] {
+			refs[id]++
+		}
+		for _, ids := range kvc.SampleMap[v.ID] {
+			for _, id := range ids {
+				refs[id]++
+			}
+		}
+	}
+	for id, n := range refs {
+		if kvc.Blocks[id].RefCount == n {
+			freed++
+		}
+	}
+	return refs, freed
+}
+
+// recordRoundingWaste adds the spare slots of reqID's last block to
+// RoundingWasteTokens and counts the allocation. Every earlier block of the
+// table is full, so the spare equals allocated block tokens − tokens held.
+func (kvc *KVCacheState) recordRoundingWaste(reqID string) {
+	kvc.Allocations++
+	ids := kvc.RequestMap[reqID]
+	if len(ids) == 0 {
+		return
+	}
+	kvc.RoundingWasteTokens += kvc.BlockSizeTokens - util.Len64(kvc.Blocks[ids[len(ids)-1]].Tokens)
+}
+
 // popFreeBlock evicts a block from the free list and prepares it for reuse.
 // Hash entries are preserved (lazy deletion) - they will be cleared when the
 // block is filled with new content in AllocateKVBlocks allocation loop.
@@ -385,8 +525,13 @@ func (kvc *KVCacheState) popFreeBlock() *KVBlock {
 
 // countFreeBlocks returns the number of blocks not currently in use.
 // This is a direct read of the free list counter (vLLM parity), not arithmetic derivation.
+// When the cache shares a byte budget, the count is further capped by the
+// number of whole blocks the budget's free bytes can hold.
 func (kvc *KVCacheState) countFreeBlocks() int64 {
-	return kvc.FreeBlockCnt
+	if kvc.budget == nil {
+		return kvc.FreeBlockCnt
+	}
+	return min(kvc.FreeBlockCnt, kvc.budget.freeBytes()/kvc.blockBytes)
 }
 
 // commitCachedBlocks registers a slice of cached blocks into a request's RequestMap.
@@ -404,15 +549,18 @@ func (kvc *KVCacheState) countFreeBlocks() int64 {
 // decremented by removeFromFreeList during commitCachedBlocks), so the pre-check
 // correctly accounts for the committed blocks. In BLIS's single-threaded DES,
 // FreeBlockCnt cannot decrease between the inner pre-check and allocation loop.
-func (kvc *KVCacheState) commitCachedBlocks(reqID string, cachedBlocks []int64) {
+func (kvc *KVCacheState) commitCachedBlocks(req *sim.Request, cachedBlocks []int64) {
+	reqID := req.ID
 	for _, blockID := range cachedBlocks {
 		blk := kvc.Blocks[blockID]
 		blk.RefCount++
+		blk.LastAccess = kvc.clock
 		if !blk.InUse {
 			blk.InUse = true
 			kvc.removeFromFreeList(blk)
 		}
 		kvc.CacheHits++
+		kvc.countsFor(req).Hits++
 		kvc.RequestMap[reqID] = append(kvc.RequestMap[reqID], blockID)
 	}
 }
@@ -422,6 +570,12 @@ func (kvc *KVCacheState) commitCachedBlocks(reqID string, cachedBlocks []int64)
 func (kvc *KVCacheState) ReleaseKVBlocks(req *sim.Request) {
 	ids := kvc.RequestMap[req.ID]
 	delete(kvc.RequestMap, req.ID)
+	kvc.releaseSamples(req.ID)
+	kvc.releaseBlocks(req.ID, ids)
+}
+
+// releaseBlocks drops reqID's reference to each block of a block table.
+func (kvc *KVCacheState) releaseBlocks(reqID string, ids []int64) {
 	// From https://docs.vllm.ai/en/v0.8.5/design/v1/prefix_caching.html
 	// Freed blocks are added to the tail of the free queue in reverse order.
 	// Later blocks can only be reused if all preceding blocks also match
@@ -431,6 +585,9 @@ func (kvc *KVCacheState) ReleaseKVBlocks(req *sim.Request) {
 		blockId := ids[i]
 		blk := kvc.Blocks[blockId]
 		blk.RefCount--
+		if kvc.CheckRefCounts {
+			kvc.checkReleasedBlock(reqID, blk)
+		}
 		if blk.RefCount == 0 {
 			blk.InUse = false
 			kvc.appendToFreeList(blk)
@@ -438,6 +595,143 @@ func (kvc *KVCacheState) ReleaseKVBlocks(req *sim.Request) {
 	}
 }
 
+// checkReleasedBlock panics if reqID's release left blk with a negative
+// reference count, or with none while another block table still holds it.
+func (kvc *KVCacheState) checkReleasedBlock(reqID string, blk *KVBlock) {
+	if blk.RefCount < 0 {
+		panic(fmt.Sprintf("KV refcount invariant: request %s released block %d to refcount %d (double free)",
+			reqID, blk.ID, blk.RefCount))
+	}
+	if blk.RefCount > 0 {
+		return
+	}
+	if holder, ok := kvc.blockHolder(blk.ID); ok {
+		panic(fmt.Sprintf("KV refcount invariant: request %s freed block %d still referenced by request %s",
+			reqID, blk.ID, holder))
+	}
+}
+
+// blockHolder returns the lowest request ID whose block table (or one of
+// its parallel samples' tables) contains id.
+func (kvc *KVCacheState) blockHolder(id int64) (string, bool) {
+	holders := make([]string, 0)
+	for reqID, ids := range kvc.RequestMap {
+		if slices.Contains(ids, id) {
+			holders = append(holders, reqID)
+		}
+	}
+	for reqID, tables := range kvc.SampleMap {
+		for _, ids := range tables {
+			if slices.Contains(ids, id) {
+				holders = append(holders, reqID)
+				break
+			}
+		}
+	}
+	if len(holders) == 0 {
+		return "", false
+	}
+	return slices.Min(holders), true
+}
+
+// SeedPrefix writes each full block of tokens not already cached into the
+// least recently used free block, hashed as a prefill block would be, and
+// returns it to the tail of the free list. Seeded blocks stay free (INV-4 is
+// unaffected) but GetCachedBlocks finds them until they are evicted. No cache
+// hit or miss is counted. Returns the number of blocks written; a partial
+// trailing block is never seeded.
+func (kvc *KVCacheState) SeedPrefix(tokens []sim.TokenID) int64 {
+	n := util.Len64(tokens) / kvc.BlockSizeTokens
+	written := int64(0)
+	prevHash := ""
+	for i := int64(0); i < n; i++ {
+		blockTokens := tokens[i*kvc.BlockSizeTokens : (i+1)*kvc.BlockSizeTokens]
+		h := hash.HashBlock(prevHash, blockTokens)
+		parent := prevHash
+		prevHash = h
+		if _, ok := kvc.HashToBlock[h]; ok {
+			continue
+		}
+		blk := kvc.popFreeBlock()
+		if blk == nil {
+			break
+		}
+		if blk.Hash != "" {
+			delete(kvc.HashToBlock, blk.Hash)
+		}
+		blk.Tokens = append([]sim.TokenID{}, blockTokens...)
+		blk.Hash = h
+		blk.PrevHash = parent
+		blk.PrefixDepth = i + 1
+		blk.LastAccess = kvc.clock
+		kvc.HashToBlock[h] = blk.ID
+		kvc.appendToFreeList(blk)
+		written++
+	}
+	return written
+}
+
+// ExportPrefixes returns the tokens of every prefix GetCachedBlocks can
+// currently find in full, one sequence per leaf of the prefix index (a cached
+// prefix that no longer cached prefix extends), least recently used first. A
+// block whose chain back to the first block is broken by an eviction is
+// unreachable and omitted. Calling SeedPrefix on each sequence in order
+// rebuilds the index in another cache, with the most recently used prefixes
+// last on the free list and so evicted last. Pure query.
+func (kvc *KVCacheState) ExportPrefixes() [][]sim.TokenID {
+	reachable := make(map[string]bool, len(kvc.HashToBlock))
+	var isReachable func(h string) bool
+	isReachable = func(h string) bool {
+		if r, ok := reachable[h]; ok {
+			return r
+		}
+		reachable[h] = false // guards a malformed cycle
+		id, ok := kvc.HashToBlock[h]
+		if !ok {
+			return false
+		}
+		blk := kvc.Blocks[id]
+		r := blk.Hash == h && util.Len64(blk.Tokens) == kvc.BlockSizeTokens &&
+			(blk.PrevHash == "" || isReachable(blk.PrevHash))
+		reachable[h] = r
+		return r
+	}
+	parents := make(map[string]bool)
+	for h := range kvc.HashToBlock {
+		if isReachable(h) {
+			parents[kvc.Blocks[kvc.HashToBlock[h]].PrevHash] = true
+		}
+	}
+	leaves := make([]*KVBlock, 0)
+	for h, id := range kvc.HashToBlock {
+		if reachable[h] && !parents[h] {
+			leaves = append(leaves, kvc.Blocks[id])
+		}
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].LastAccess != leaves[j].LastAccess {
+			return leaves[i].LastAccess < leaves[j].LastAccess
+		}
+		return leaves[i].Hash < leaves[j].Hash
+	})
+	out := make([][]sim.TokenID, 0, len(leaves))
+	for _, leaf := range leaves {
+		var chain []*KVBlock
+		for blk := leaf; ; blk = kvc.Blocks[kvc.HashToBlock[blk.PrevHash]] {
+			chain = append(chain, blk)
+			if blk.PrevHash == "" {
+				break
+			}
+		}
+		tokens := make([]sim.TokenID, 0, int64(len(chain))*kvc.BlockSizeTokens)
+		for i := len(chain) - 1; i >= 0; i-- {
+			tokens = append(tokens, chain[i].Tokens...)
+		}
+		out = append(out, tokens)
+	}
+	return out
+}
+
 // BlockSize returns the number of tokens per block.
 func (kvc *KVCacheState) BlockSize() int64 { return kvc.BlockSizeTokens }
 
@@ -458,18 +752,54 @@ func (kvc *KVCacheState) CacheHitRate() float64 {
 	return float64(kvc.CacheHits) / float64(total)
 }
 
+// CacheHitCountsByGroup returns a copy of the hit/miss counts per tenant and
+// SLO class. Sums over groups equal CacheHits and CacheMisses.
+func (kvc *KVCacheState) CacheHitCountsByGroup() map[sim.CacheGroup]sim.CacheHitCounts {
+	if len(kvc.groupCounts) == 0 {
+		return nil
+	}
+	out := make(map[sim.CacheGroup]sim.CacheHitCounts, len(kvc.groupCounts))
+	for g, c := range kvc.groupCounts {
+		out[g] = *c
+	}
+	return out
+}
+
+// countsFor returns the hit/miss counters for req's tenant and SLO class.
+func (kvc *KVCacheState) countsFor(req *sim.Request) *sim.CacheHitCounts {
+	g := sim.CacheGroup{TenantID: req.TenantID, SLOClass: req.SLOClass}
+	c, ok := kvc.groupCounts[g]
+	if !ok {
+		if kvc.groupCounts == nil {
+			kvc.groupCounts = make(map[sim.CacheGroup]*sim.CacheHitCounts)
+		}
+		c = &sim.CacheHitCounts{}
+		kvc.groupCounts[g] = c
+	}
+	return c
+}
+
 // PendingTransferLatency returns 0 for single-tier cache (no transfers).
 func (kvc *KVCacheState) PendingTransferLatency() int64 { return 0 }
 
 // KVThrashingRate returns 0 for single-tier cache (no offload/reload).
 func (kvc *KVCacheState) KVThrashingRate() float64 { return 0 }
 
-// SetClock is a no-op for single-tier KV cache (no time-dependent behavior).
-func (kvc *KVCacheState) SetClock(_ int64) {}
+// SetClock records the current tick. It affects no allocation decision; the
+// tick is only stamped on blocks as LastAccess for PrefixIndexState.
+func (kvc *KVCacheState) SetClock(now int64) { kvc.clock = now }
 
 // ConsumePendingTransferLatency returns 0 for single-tier cache (no transfers).
 func (kvc *KVCacheState) ConsumePendingTransferLatency() int64 { return 0 }
 
+// BlocksSavedBySharing returns SharedBlocksSaved.
+func (kvc *KVCacheState) BlocksSavedBySharing() int64 { return kvc.SharedBlocksSaved }
+
+// RoundingWaste returns RoundingWasteTokens and Allocations.
+func (kvc *KVCacheState) RoundingWaste() (wasteTokens, allocations int64) {
+	return kvc.RoundingWasteTokens, kvc.Allocations
+}
+
 // MirrorToCPU is a no-op for single-tier KV cache (no CPU tier).
 func (kvc *KVCacheState) MirrorToCPU(_ []*sim.Request) {}
 
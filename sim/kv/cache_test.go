@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -1057,3 +1059,235 @@ func TestLazyHashDeletion_PartialEviction(t *testing.T) {
 	assertBlockConservation(t, kvc)
 }
 
+
+func TestSeedPrefix_CachesFullBlocksWithoutUsingThem(t *testing.T) {
+	// GIVEN an empty cache with BlockSize=4 and a 10-token prefix (2 full blocks + 2 tokens)
+	kvc := NewKVCacheState(8, 4)
+	prefix := []sim.TokenID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	// WHEN the prefix is seeded twice
+	first := kvc.SeedPrefix(prefix)
+	second := kvc.SeedPrefix(prefix)
+
+	// THEN only the full blocks are written, and only once
+	assert.Equal(t, int64(2), first)
+	assert.Equal(t, int64(0), second, "already-cached blocks are not rewritten")
+
+	// AND seeded blocks stay free with no hit/miss accounting (INV-4)
+	assert.Equal(t, int64(0), kvc.UsedBlocks())
+	assert.Equal(t, int64(0), kvc.CacheHits+kvc.CacheMisses)
+	assertBlockConservation(t, kvc)
+
+	// AND a request sharing the prefix finds both blocks and claims them as hits
+	req := &sim.Request{ID: "r1", InputTokens: append(append([]sim.TokenID{}, prefix...), 11, 12)}
+	cached := kvc.GetCachedBlocks(req.InputTokens)
+	require.Len(t, cached, 2)
+	require.True(t, kvc.AllocateKVBlocks(req, 8, 12, cached))
+	assert.Equal(t, int64(2), kvc.CacheHits)
+	assertBlockConservation(t, kvc)
+}
+
+func TestExportPrefixes_SeedPrefixRoundTrip(t *testing.T) {
+	// GIVEN a cache holding two prompts that share their first block, used in order r1, r2
+	kvc := NewKVCacheState(6, 2)
+	r1 := &sim.Request{ID: "r1", InputTokens: []sim.TokenID{1, 2, 3, 4}}
+	r2 := &sim.Request{ID: "r2", InputTokens: []sim.TokenID{1, 2, 5, 6, 7}}
+	require.True(t, kvc.AllocateKVBlocks(r1, 0, 4, nil))
+	kvc.ReleaseKVBlocks(r1)
+	kvc.SetClock(10)
+	cached := kvc.GetCachedBlocks(r2.InputTokens)
+	require.True(t, kvc.AllocateKVBlocks(r2, int64(len(cached))*2, 5, cached))
+	kvc.ReleaseKVBlocks(r2)
+
+	// WHEN the prefixes are exported and seeded, in order, into a fresh cache
+	exported := kvc.ExportPrefixes()
+	fresh := NewKVCacheState(6, 2)
+	for _, p := range exported {
+		fresh.SeedPrefix(p)
+	}
+
+	// THEN one sequence per leaf comes out, least recently used first, without partial blocks
+	assert.Equal(t, [][]sim.TokenID{{1, 2, 3, 4}, {1, 2, 5, 6}}, exported)
+	// AND the fresh cache finds exactly the prefixes the original one does
+	for _, tokens := range [][]sim.TokenID{{1, 2, 3, 4, 9}, {1, 2, 5, 6, 7}, {1, 2, 8, 8}} {
+		assert.Len(t, fresh.GetCachedBlocks(tokens), len(kvc.GetCachedBlocks(tokens)), "tokens %v", tokens)
+	}
+	assert.Equal(t, int64(0), fresh.UsedBlocks())
+	assertBlockConservation(t, fresh)
+}
+
+func TestAllocateKVBlocks_NoCache_NeitherClaimsNorIndexesBlocks(t *testing.T) {
+	// GIVEN a cache holding a 2-block prefix from r1
+	kvc := NewKVCacheState(8, 2)
+	req1 := &sim.Request{ID: "r1", InputTokens: []sim.TokenID{1, 2, 3, 4}}
+	require.True(t, kvc.AllocateKVBlocks(req1, 0, 4, []int64{}))
+	kvc.ReleaseKVBlocks(req1)
+	indexed := len(kvc.HashToBlock)
+
+	// WHEN a NoCache request with the same prompt is handed the cached blocks
+	cached := kvc.GetCachedBlocks([]sim.TokenID{1, 2, 3, 4, 5, 6})
+	require.Len(t, cached, 2)
+	req2 := &sim.Request{ID: "r2", InputTokens: []sim.TokenID{1, 2, 3, 4, 5, 6}, NoCache: true}
+	require.True(t, kvc.AllocateKVBlocks(req2, 0, 6, cached))
+
+	// THEN it claims none of them and its own blocks stay out of the prefix index
+	assert.Equal(t, int64(0), kvc.CacheHits)
+	assert.Len(t, kvc.RequestMap["r2"], 3)
+	for _, id := range kvc.RequestMap["r2"] {
+		assert.Empty(t, kvc.Blocks[id].Hash, "block %d of NoCache request must not be hashed", id)
+		assert.NotContains(t, cached, id, "NoCache request must not share cached block %d", id)
+	}
+	assert.Len(t, kvc.HashToBlock, indexed, "prefix index must not grow")
+	assertBlockConservation(t, kvc)
+}
+
+// TestAllocateKVBlocks_RoundingWaste_CeilBlocksAndSpareSlots verifies that
+// allocation rounds up to whole blocks — ceil(tokens/blockSize) — and that
+// RoundingWaste accumulates allocated block tokens − actual tokens per
+// allocation, including a decode token absorbed by a partial block.
+func TestAllocateKVBlocks_RoundingWaste_CeilBlocksAndSpareSlots(t *testing.T) {
+	const blockSize = 16
+	kvc := NewKVCacheState(100, blockSize)
+
+	var wantWaste int64
+	for i, n := range []int{10, 16, 33, 47} {
+		req := makeRequest(fmt.Sprintf("r%d", i), n, 2, 1000*i)
+		if !kvc.AllocateKVBlocks(req, 0, int64(n), nil) {
+			t.Fatalf("allocation of %d tokens failed", n)
+		}
+		blocks := int64(len(kvc.RequestMap[req.ID]))
+		if want := (int64(n) + blockSize - 1) / blockSize; blocks != want {
+			t.Errorf("%d tokens: %d blocks, want ceil(%d/%d) = %d", n, blocks, n, blockSize, want)
+		}
+		wantWaste += blocks*blockSize - int64(n)
+	}
+	// 10→6, 16→0, 33→15, 47→1 spare slots.
+	if wantWaste != 22 {
+		t.Fatalf("hand-computed waste = %d, want 22", wantWaste)
+	}
+	if waste, allocs := kvc.RoundingWaste(); waste != wantWaste || allocs != 4 {
+		t.Errorf("RoundingWaste = (%d, %d), want (%d, 4)", waste, allocs, wantWaste)
+	}
+
+	// Decode of r2 (33 input tokens): the token fills one of the 15 spare
+	// slots, so this allocation leaves 14.
+	req := makeRequest("r2", 33, 2, 2000)
+	req.ProgressIndex = 33
+	if !kvc.AllocateKVBlocks(req, 33, 34, nil) {
+		t.Fatal("decode allocation failed")
+	}
+	if waste, allocs := kvc.RoundingWaste(); waste != wantWaste+14 || allocs != 5 {
+		t.Errorf("after decode RoundingWaste = (%d, %d), want (%d, 5)", waste, allocs, wantWaste+14)
+	}
+}
+
+// TestReleaseKVBlocks_CheckRefCounts_PanicsOnDoubleFree crafts release
+// sequences that corrupt the reference counts and verifies the debug check
+// names the offending request, while legitimate prefix sharing passes.
+func TestReleaseKVBlocks_CheckRefCounts_PanicsOnDoubleFree(t *testing.T) {
+	// releaseTwice allocates a 32-token request (two blocks), releases it,
+	// restores its stale block table and releases it again.
+	releaseTwice := func(kvc *KVCacheState) {
+		req := makeRequest("a", 32, 1, 0)
+		if !kvc.AllocateKVBlocks(req, 0, 32, nil) {
+			t.Fatal("allocation failed")
+		}
+		stale := slices.Clone(kvc.RequestMap[req.ID])
+		kvc.ReleaseKVBlocks(req)
+		kvc.RequestMap[req.ID] = stale
+		kvc.ReleaseKVBlocks(req)
+	}
+	expectPanic := func(t *testing.T, want ...string) {
+		t.Helper()
+		r := recover()
+		if r == nil {
+			t.Fatal("expected a refcount invariant panic")
+		}
+		msg := fmt.Sprintf("%v", r)
+		for _, w := range want {
+			if !strings.Contains(msg, w) {
+				t.Errorf("panic %q does not mention %q", msg, w)
+			}
+		}
+	}
+
+	t.Run("double free", func(t *testing.T) {
+		kvc := NewKVCacheState(10, 16)
+		kvc.CheckRefCounts = true
+		defer expectPanic(t, "request a", "double free")
+		releaseTwice(kvc)
+	})
+
+	t.Run("freed while referenced", func(t *testing.T) {
+		kvc := NewKVCacheState(10, 16)
+		kvc.CheckRefCounts = true
+		a := makeRequest("a", 32, 1, 0)
+		if !kvc.AllocateKVBlocks(a, 0, 32, nil) {
+			t.Fatal("allocation failed")
+		}
+		// b's table aliases a's blocks without taking references.
+		kvc.RequestMap["b"] = slices.Clone(kvc.RequestMap[a.ID])
+		defer expectPanic(t, "request a", "still referenced by request b")
+		kvc.ReleaseKVBlocks(a)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		kvc := NewKVCacheState(10, 16)
+		releaseTwice(kvc)
+		if rc := kvc.Blocks[0].RefCount; rc != -1 {
+			t.Errorf("unchecked double free left RefCount = %d, want -1", rc)
+		}
+	})
+
+	t.Run("shared prefix", func(t *testing.T) {
+		kvc := NewKVCacheState(10, 16)
+		kvc.CheckRefCounts = true
+		a := makeRequest("a", 32, 1, 0)
+		b := makeRequest("b", 32, 1, 0) // same tokens: both blocks are cache hits
+		if !kvc.AllocateKVBlocks(a, 0, 32, nil) {
+			t.Fatal("allocation of a failed")
+		}
+		if !kvc.AllocateKVBlocks(b, 0, 32, kvc.GetCachedBlocks(b.InputTokens)) {
+			t.Fatal("allocation of b failed")
+		}
+		kvc.ReleaseKVBlocks(a)
+		kvc.ReleaseKVBlocks(b)
+		if kvc.UsedBlocks() != 0 {
+			t.Errorf("UsedBlocks = %d after releasing both, want 0", kvc.UsedBlocks())
+		}
+	})
+}
+
+// TestCanAllocateAfterRelease_CountsOnlyBlocksVictimsFullyRelease verifies a
+// block shared with a non-victim is not counted as freed, and that the answer
+// matches releasing the victims and allocating.
+func TestCanAllocateAfterRelease_CountsOnlyBlocksVictimsFullyRelease(t *testing.T) {
+	// GIVEN 6 blocks × 4 tokens: a holds a 2-block prefix, b shares it and adds one block
+	kvc := NewKVCacheState(6, 4)
+	a := &sim.Request{ID: "a", InputTokens: []sim.TokenID{1, 2, 3, 4, 5, 6, 7, 8}}
+	require.True(t, kvc.AllocateKVBlocks(a, 0, 8, nil))
+	b := &sim.Request{ID: "b", InputTokens: []sim.TokenID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}}
+	cached := kvc.GetCachedBlocks(b.InputTokens)
+	require.Len(t, cached, 2)
+	require.True(t, kvc.AllocateKVBlocks(b, 8, 12, cached))
+	require.Equal(t, int64(3), kvc.UsedBlocks())
+
+	// AND a new request needing 5 blocks with 3 free
+	next := &sim.Request{ID: "next", InputTokens: make([]sim.TokenID, 20)}
+	for i := range next.InputTokens {
+		next.InputTokens[i] = sim.TokenID(100 + i)
+	}
+
+	// THEN releasing a alone frees nothing (b still holds the prefix), b alone frees 1
+	assert.False(t, kvc.CanAllocateAfterRelease([]*sim.Request{a}, next, 0, 20, nil))
+	assert.False(t, kvc.CanAllocateAfterRelease([]*sim.Request{b}, next, 0, 20, nil))
+	// AND releasing both frees all 3
+	assert.True(t, kvc.CanAllocateAfterRelease([]*sim.Request{a, b}, next, 0, 20, nil))
+	assert.Equal(t, int64(3), kvc.UsedBlocks(), "pure query must not release")
+
+	// AND the allocation then succeeds
+	kvc.ReleaseKVBlocks(a)
+	kvc.ReleaseKVBlocks(b)
+	assert.True(t, kvc.AllocateKVBlocks(next, 0, 20, nil))
+	assertBlockConservation(t, kvc)
+}
@@ -0,0 +1,69 @@
+package kv
+
+// HostBandwidth models the host-memory bandwidth shared by every TieredKVCache
+// placed on the same host. CPU↔GPU KV transfers that overlap in time split the
+// bandwidth fairly: a transfer that starts while n-1 transfers from other
+// instances are still in flight takes n times its un-contended duration, and
+// each in-flight transfer is stretched by its share of the newcomer's cost.
+// The stretch is charged to the owning cache's pending transfer latency, so it
+// lands on that instance's next step.
+//
+// A nil *HostBandwidth is never consulted: caches without one keep the
+// un-contended per-block transfer cost (byte-identical to the pre-contention model).
+type HostBandwidth struct {
+	active []*hostTransfer // in-flight transfers, in registration order (deterministic)
+}
+
+// hostTransfer is one in-flight CPU↔GPU transfer on a shared host.
+type hostTransfer struct {
+	owner *TieredKVCache
+	end   int64 // tick at which the transfer completes
+}
+
+// NewHostBandwidth creates an idle shared host-bandwidth resource.
+func NewHostBandwidth() *HostBandwidth {
+	return &HostBandwidth{}
+}
+
+// ActiveTransfers returns the number of transfers still in flight at tick now.
+func (h *HostBandwidth) ActiveTransfers(now int64) int {
+	h.prune(now)
+	return len(h.active)
+}
+
+// transfer registers a transfer of un-contended duration d ticks starting at now
+// on behalf of owner, and returns its contended duration. Transfers owned by the
+// same cache are serialized by that instance's step loop and do not contend
+// with each other.
+func (h *HostBandwidth) transfer(owner *TieredKVCache, now, d int64) int64 {
+	h.prune(now)
+	var others []*hostTransfer
+	for _, tr := range h.active {
+		if tr.owner != owner {
+			others = append(others, tr)
+		}
+	}
+	n := int64(len(others)) + 1
+	for _, tr := range others {
+		extra := (tr.end - now) / (n - 1)
+		tr.end += extra
+		tr.owner.pendingLatency += extra
+	}
+	contended := d * n
+	h.active = append(h.active, &hostTransfer{owner: owner, end: now + contended})
+	return contended
+}
+
+// prune drops transfers that completed at or before now.
+func (h *HostBandwidth) prune(now int64) {
+	kept := h.active[:0]
+	for _, tr := range h.active {
+		if tr.end > now {
+			kept = append(kept, tr)
+		}
+	}
+	for i := len(kept); i < len(h.active); i++ {
+		h.active[i] = nil
+	}
+	h.active = kept
+}
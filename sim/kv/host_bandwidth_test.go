@@ -0,0 +1,92 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReloadReadyCache returns a TieredKVCache whose prefix {1,2,3,4} lives only
+// on CPU and whose GPU has exactly one free block, so requesting the prefix
+// reloads one block. Same setup as TestTieredKVCache_TargetedReload_TransferLatency.
+func newReloadReadyCache(bandwidth float64, baseLat int64) *TieredKVCache {
+	gpu := NewKVCacheState(6, 2)
+	tiered := NewTieredKVCache(gpu, 10, 0.0, bandwidth, baseLat)
+
+	req := &sim.Request{ID: "r1", InputTokens: []sim.TokenID{1, 2, 3, 4}}
+	tiered.AllocateKVBlocks(req, 0, 4, []int64{})
+	h0 := gpu.Blocks[gpu.RequestMap["r1"][0]].Hash
+	h1 := gpu.Blocks[gpu.RequestMap["r1"][1]].Hash
+	tiered.cpu.store(h0, []sim.TokenID{1, 2})
+	tiered.cpu.store(h1, []sim.TokenID{3, 4})
+	tiered.ReleaseKVBlocks(req)
+
+	for i := 0; i < 6; i++ {
+		f := &sim.Request{ID: fmt.Sprintf("f%d", i), InputTokens: []sim.TokenID{sim.TokenID(i*2 + 20), sim.TokenID(i*2 + 21)}}
+		tiered.AllocateKVBlocks(f, 0, 2, []int64{})
+	}
+	tiered.ReleaseKVBlocks(&sim.Request{ID: "f0"})
+	return tiered
+}
+
+// triggerReload requests the CPU-resident prefix at tick now.
+func triggerReload(tiered *TieredKVCache, now int64) {
+	tiered.SetClock(now)
+	tiered.AllocateKVBlocks(&sim.Request{ID: "new", InputTokens: []sim.TokenID{1, 2, 3, 4}}, 0, 4, []int64{})
+}
+
+func TestHostBandwidth_SingleTransfer_MatchesUncontended(t *testing.T) {
+	// GIVEN a cache without a host and one alone on a shared host
+	// (bandwidth 0.02 blocks/tick: ceil(2/0.02) = 100 transfer ticks; baseLat = 10)
+	plain := newReloadReadyCache(0.02, 10)
+	hosted := newReloadReadyCache(0.02, 10)
+	hosted.SetHostBandwidth(NewHostBandwidth())
+
+	// WHEN each reloads one block at the same tick
+	triggerReload(plain, 1000)
+	triggerReload(hosted, 1000)
+
+	// THEN both pay the un-contended cost: 10 + 100
+	assert.Equal(t, int64(110), plain.ConsumePendingTransferLatency())
+	assert.Equal(t, int64(110), hosted.ConsumePendingTransferLatency(),
+		"a lone transfer on a shared host must match the un-contended duration")
+}
+
+func TestHostBandwidth_ConcurrentTransfers_EachDurationDoubles(t *testing.T) {
+	// GIVEN two instances' caches on the same host
+	host := NewHostBandwidth()
+	a := newReloadReadyCache(0.02, 10)
+	b := newReloadReadyCache(0.02, 10)
+	a.SetHostBandwidth(host)
+	b.SetHostBandwidth(host)
+
+	// WHEN both reload at the same tick
+	triggerReload(a, 1000)
+	triggerReload(b, 1000)
+
+	// THEN each transfer takes 2 × 100 ticks; the fixed cost is not bandwidth-bound
+	assert.Equal(t, 2, host.ActiveTransfers(1000))
+	assert.Equal(t, int64(10+200), a.ConsumePendingTransferLatency(),
+		"the in-flight transfer must be stretched by the newcomer's share")
+	assert.Equal(t, int64(10+200), b.ConsumePendingTransferLatency(),
+		"the newcomer must see half the host bandwidth")
+}
+
+func TestHostBandwidth_CompletedTransfer_DoesNotContend(t *testing.T) {
+	// GIVEN two caches on one host, the second reloading after the first finished
+	host := NewHostBandwidth()
+	a := newReloadReadyCache(0.02, 10)
+	b := newReloadReadyCache(0.02, 10)
+	a.SetHostBandwidth(host)
+	b.SetHostBandwidth(host)
+
+	triggerReload(a, 1000)
+	triggerReload(b, 1100) // a's transfer ended at 1000 + 100
+
+	// THEN neither is contended
+	assert.Equal(t, 0, host.ActiveTransfers(1200))
+	assert.Equal(t, int64(110), a.ConsumePendingTransferLatency())
+	assert.Equal(t, int64(110), b.ConsumePendingTransferLatency())
+}
@@ -0,0 +1,264 @@
+package kv
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// ModelKVLayout is one model's KV cache geometry (see sim.ModelKVLayout).
+type ModelKVLayout = sim.ModelKVLayout
+
+// byteBudget is a KV memory pool shared by per-model caches with different
+// block sizes. Usage is derived from the members' in-use blocks on every
+// query rather than tracked incrementally, so it cannot drift from them.
+type byteBudget struct {
+	capacityBytes int64
+	members       []*KVCacheState
+}
+
+func (b *byteBudget) usedBytes() int64 {
+	var used int64
+	for _, m := range b.members {
+		used += m.UsedBlocks() * m.blockBytes
+	}
+	return used
+}
+
+func (b *byteBudget) freeBytes() int64 { return b.capacityBytes - b.usedBytes() }
+
+// MultiModelKVCache holds the KV caches of several models served by one
+// instance. Each model keeps its own block size, prefix cache and LRU free
+// list, while capacity is a single byte pool: a model may allocate a block
+// whenever the pool has blockBytes free, regardless of which model freed them.
+//
+// MultiModelKVCache is a sim.ModelScopedKVStore. Allocation, release and
+// CanAllocateAfterRelease are routed by Request.Model; the empty model name
+// is an ordinary key and must be given a layout if requests without a model
+// tag are served. ForModel returns a model's view, whose block counts are in
+// that model's blocks and cover the whole pool. The model-agnostic methods
+// (BlockSize, TotalCapacity, UsedBlocks, and the request-agnostic prefix
+// operations GetCachedBlocks, SeedPrefix and ExportPrefixes) address the base
+// model (sim.BaseKVLayoutModel), whose blocks are the smallest in bytes.
+//
+// Only in-use blocks are charged to the pool. Free blocks that still hold a
+// prefix-cache entry are reclaimable by any model, so their entries remain
+// findable within their own model until that model reuses the block.
+type MultiModelKVCache struct {
+	capacityBytes int64
+	budget        *byteBudget
+	models        map[string]*KVCacheState
+	base          modelKVView
+}
+
+// NewMultiModelKVCache creates a cache with capacityBytes of KV memory shared
+// by the models in layouts. Panics on a non-positive capacity, an empty layout
+// set, or a layout whose block does not fit in the pool.
+func NewMultiModelKVCache(capacityBytes int64, layouts map[string]ModelKVLayout) *MultiModelKVCache {
+	if capacityBytes <= 0 {
+		panic(fmt.Sprintf("NewMultiModelKVCache: capacityBytes must be > 0, got %d", capacityBytes))
+	}
+	if len(layouts) == 0 {
+		panic("NewMultiModelKVCache: at least one model layout is required")
+	}
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names) // R2: deterministic member order and panic messages
+
+	mm := &MultiModelKVCache{
+		capacityBytes: capacityBytes,
+		budget:        &byteBudget{capacityBytes: capacityBytes},
+		models:        make(map[string]*KVCacheState, len(layouts)),
+	}
+	for _, name := range names {
+		l := layouts[name]
+		if l.BlockSizeTokens <= 0 {
+			panic(fmt.Sprintf("NewMultiModelKVCache: model %q: BlockSizeTokens must be > 0, got %d", name, l.BlockSizeTokens))
+		}
+		if l.BytesPerToken <= 0 {
+			panic(fmt.Sprintf("NewMultiModelKVCache: model %q: BytesPerToken must be > 0, got %d", name, l.BytesPerToken))
+		}
+		blocks := capacityBytes / l.BlockBytes()
+		if blocks <= 0 {
+			panic(fmt.Sprintf("NewMultiModelKVCache: model %q: block of %d bytes exceeds capacity of %d bytes",
+				name, l.BlockBytes(), capacityBytes))
+		}
+		// Each model could fill the whole pool on its own; the shared budget
+		// caps the models jointly.
+		kvc := NewKVCacheState(blocks, l.BlockSizeTokens)
+		kvc.budget = mm.budget
+		kvc.blockBytes = l.BlockBytes()
+		mm.budget.members = append(mm.budget.members, kvc)
+		mm.models[name] = kvc
+	}
+	mm.base = modelKVView{KVCacheState: mm.models[sim.BaseKVLayoutModel(layouts)], pool: mm}
+	return mm
+}
+
+// cacheFor returns model's cache. Panics on a model without a layout.
+func (mm *MultiModelKVCache) cacheFor(model string) *KVCacheState {
+	kvc, ok := mm.models[model]
+	if !ok {
+		panic(fmt.Sprintf("MultiModelKVCache: no KV layout for model %q", model))
+	}
+	return kvc
+}
+
+// ForModel returns model's view of the cache. Panics on a model without a
+// layout.
+func (mm *MultiModelKVCache) ForModel(model string) sim.KVStore {
+	return modelKVView{KVCacheState: mm.cacheFor(model), pool: mm}
+}
+
+// AllocateKVBlocks allocates in req.Model's cache, failing without state
+// change when the model's blocks or the shared pool's bytes run out.
+func (mm *MultiModelKVCache) AllocateKVBlocks(req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	return mm.cacheFor(req.Model).AllocateKVBlocks(req, startIndex, endIndex, cachedBlocks)
+}
+
+// ReleaseKVBlocks releases req's blocks in req.Model's cache.
+func (mm *MultiModelKVCache) ReleaseKVBlocks(req *sim.Request) {
+	mm.cacheFor(req.Model).ReleaseKVBlocks(req)
+}
+
+// CanAllocateAfterRelease reports whether req's prefill allocation would fit
+// in req.Model's cache once the victims were released. Victims of any model
+// count: the bytes their blocks return to the pool are credited to req.
+func (mm *MultiModelKVCache) CanAllocateAfterRelease(victims []*sim.Request, req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	target := mm.cacheFor(req.Model)
+	var otherFreedBytes int64
+	for _, m := range mm.budget.members {
+		if m != target {
+			_, freed := m.releasableBlocks(victims)
+			otherFreedBytes += freed * m.blockBytes
+		}
+	}
+	return target.canAllocateAfterRelease(victims, req, startIndex, endIndex, cachedBlocks, otherFreedBytes)
+}
+
+// GetCachedBlocks returns the base model's cached prefix blocks for tokens.
+// Pure query.
+func (mm *MultiModelKVCache) GetCachedBlocks(tokens []sim.TokenID) []int64 {
+	return mm.base.GetCachedBlocks(tokens)
+}
+
+// SeedPrefix seeds tokens into the base model's prefix cache.
+func (mm *MultiModelKVCache) SeedPrefix(tokens []sim.TokenID) int64 { return mm.base.SeedPrefix(tokens) }
+
+// ExportPrefixes returns the base model's cached prefixes. Pure query.
+func (mm *MultiModelKVCache) ExportPrefixes() [][]sim.TokenID { return mm.base.ExportPrefixes() }
+
+// BlockSize returns the base model's tokens per block.
+func (mm *MultiModelKVCache) BlockSize() int64 { return mm.base.BlockSize() }
+
+// UsedBlocks returns the pool's in-use bytes in base-model blocks, rounded up.
+func (mm *MultiModelKVCache) UsedBlocks() int64 { return mm.base.UsedBlocks() }
+
+// TotalCapacity returns the pool's size in whole base-model blocks.
+func (mm *MultiModelKVCache) TotalCapacity() int64 { return mm.base.TotalCapacity() }
+
+// CacheHitRate returns the cumulative cache hit rate over all models' blocks.
+// Returns 0 if no lookups have been performed.
+func (mm *MultiModelKVCache) CacheHitRate() float64 {
+	var c sim.CacheHitCounts
+	for _, m := range mm.budget.members {
+		c.Hits += m.CacheHits
+		c.Misses += m.CacheMisses
+	}
+	return c.Rate()
+}
+
+// CacheHitCountsByGroup returns the hit/miss counts per tenant and SLO class,
+// summed over models.
+func (mm *MultiModelKVCache) CacheHitCountsByGroup() map[sim.CacheGroup]sim.CacheHitCounts {
+	var out map[sim.CacheGroup]sim.CacheHitCounts
+	for _, m := range mm.budget.members {
+		for g, c := range m.CacheHitCountsByGroup() {
+			if out == nil {
+				out = make(map[sim.CacheGroup]sim.CacheHitCounts)
+			}
+			sum := out[g]
+			sum.Hits += c.Hits
+			sum.Misses += c.Misses
+			out[g] = sum
+		}
+	}
+	return out
+}
+
+// PendingTransferLatency always returns 0: the cache is single-tier.
+func (mm *MultiModelKVCache) PendingTransferLatency() int64 { return 0 }
+
+// ConsumePendingTransferLatency always returns 0: the cache is single-tier.
+func (mm *MultiModelKVCache) ConsumePendingTransferLatency() int64 { return 0 }
+
+// KVThrashingRate always returns 0: the cache is single-tier.
+func (mm *MultiModelKVCache) KVThrashingRate() float64 { return 0 }
+
+// BlocksSavedBySharing returns the block allocations parallel samples avoided,
+// summed over models.
+func (mm *MultiModelKVCache) BlocksSavedBySharing() int64 {
+	var saved int64
+	for _, m := range mm.budget.members {
+		saved += m.BlocksSavedBySharing()
+	}
+	return saved
+}
+
+// RoundingWaste returns the unfilled last-block slots and allocation count,
+// summed over models.
+func (mm *MultiModelKVCache) RoundingWaste() (wasteTokens, allocations int64) {
+	for _, m := range mm.budget.members {
+		w, a := m.RoundingWaste()
+		wasteTokens += w
+		allocations += a
+	}
+	return wasteTokens, allocations
+}
+
+// SetClock sets every model's clock.
+func (mm *MultiModelKVCache) SetClock(clock int64) {
+	for _, m := range mm.budget.members {
+		m.SetClock(clock)
+	}
+}
+
+// MirrorToCPU is a no-op: the cache is single-tier.
+func (mm *MultiModelKVCache) MirrorToCPU(_ []*sim.Request) {}
+
+// BlockBytes returns the memory one of model's blocks occupies.
+func (mm *MultiModelKVCache) BlockBytes(model string) int64 { return mm.cacheFor(model).blockBytes }
+
+// ModelUsedBlocks returns the number of model's own blocks currently in use.
+func (mm *MultiModelKVCache) ModelUsedBlocks(model string) int64 { return mm.cacheFor(model).UsedBlocks() }
+
+// UsedBytes returns the KV memory held by in-use blocks across all models.
+func (mm *MultiModelKVCache) UsedBytes() int64 { return mm.budget.usedBytes() }
+
+// CapacityBytes returns the size of the shared KV memory pool.
+func (mm *MultiModelKVCache) CapacityBytes() int64 { return mm.capacityBytes }
+
+// modelKVView is one model's sim.KVStore view of a MultiModelKVCache. It is
+// the model's own cache except that occupancy covers the whole pool: other
+// models' blocks count as used, in this model's blocks, so free-block
+// arithmetic (TotalCapacity − UsedBlocks) matches what AllocateKVBlocks can
+// actually take.
+type modelKVView struct {
+	*KVCacheState
+	pool *MultiModelKVCache
+}
+
+// UsedBlocks returns the pool's in-use bytes in this model's blocks, rounded
+// up.
+func (v modelKVView) UsedBlocks() int64 {
+	return (v.pool.budget.usedBytes() + v.blockBytes - 1) / v.blockBytes
+}
+
+// CanAllocateAfterRelease credits victims of every model (see
+// MultiModelKVCache.CanAllocateAfterRelease).
+func (v modelKVView) CanAllocateAfterRelease(victims []*sim.Request, req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	return v.pool.CanAllocateAfterRelease(victims, req, startIndex, endIndex, cachedBlocks)
+}
@@ -0,0 +1,159 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+func modelRequest(id, model string, numTokens int, base sim.TokenID) *sim.Request {
+	tokens := make([]sim.TokenID, numTokens)
+	for i := range tokens {
+		tokens[i] = base + sim.TokenID(i)
+	}
+	return &sim.Request{ID: id, Model: model, InputTokens: tokens}
+}
+
+func TestMultiModelKVCache_MixedBlockSizes_ShareByteCapacity(t *testing.T) {
+	// GIVEN a 1024-byte pool shared by a model with 16-token, 32-byte blocks
+	// and a model with 32-token, 128-byte blocks
+	mm := NewMultiModelKVCache(1024, map[string]ModelKVLayout{
+		"small": {BlockSizeTokens: 16, BytesPerToken: 2},
+		"large": {BlockSizeTokens: 32, BytesPerToken: 4},
+	})
+	require.Equal(t, int64(16), mm.ForModel("small").BlockSize())
+	require.Equal(t, int64(32), mm.ForModel("large").BlockSize())
+
+	// WHEN each model admits a 64-token prompt
+	s1 := modelRequest("s1", "small", 64, 1)
+	l1 := modelRequest("l1", "large", 64, 1)
+	require.True(t, mm.AllocateKVBlocks(s1, 0, 64, nil))
+	require.True(t, mm.AllocateKVBlocks(l1, 0, 64, nil))
+
+	// THEN usage is charged in each model's own block bytes: 4×32 + 2×128
+	assert.Equal(t, int64(4), mm.ModelUsedBlocks("small"))
+	assert.Equal(t, int64(2), mm.ModelUsedBlocks("large"))
+	assert.Equal(t, int64(384), mm.UsedBytes())
+
+	// AND a large request needing 768 bytes is refused with 640 free,
+	// leaving state untouched, although the large model's own blocks suffice
+	l2 := modelRequest("l2", "large", 192, 1000)
+	assert.False(t, mm.AllocateKVBlocks(l2, 0, 192, nil))
+	assert.Equal(t, int64(384), mm.UsedBytes())
+
+	// AND the remaining 640 bytes fit exactly 20 small blocks, after which
+	// neither model can allocate another block
+	s2 := modelRequest("s2", "small", 320, 2000)
+	require.True(t, mm.AllocateKVBlocks(s2, 0, 320, nil))
+	assert.Equal(t, mm.CapacityBytes(), mm.UsedBytes())
+	assert.False(t, mm.AllocateKVBlocks(modelRequest("s3", "small", 1, 3000), 0, 1, nil))
+	assert.False(t, mm.AllocateKVBlocks(modelRequest("l3", "large", 1, 3000), 0, 1, nil))
+
+	// WHEN every request completes
+	for _, req := range []*sim.Request{s1, l1, s2} {
+		mm.ReleaseKVBlocks(req)
+	}
+
+	// THEN no bytes or blocks leak and each model's cache is conserved (INV-4)
+	assert.Equal(t, int64(0), mm.UsedBytes())
+	for _, model := range []string{"small", "large"} {
+		assert.Equal(t, int64(0), mm.ModelUsedBlocks(model), model)
+		assertBlockConservation(t, mm.cacheFor(model))
+	}
+
+	// AND freed small-model bytes are reusable by the large model
+	require.True(t, mm.AllocateKVBlocks(l2, 0, 192, nil))
+	assert.Equal(t, int64(768), mm.UsedBytes())
+}
+
+func TestMultiModelKVCache_ModelView_CountsWholePoolInOwnBlocks(t *testing.T) {
+	// GIVEN a 1024-byte pool with 32-byte small blocks and 128-byte large blocks
+	mm := NewMultiModelKVCache(1024, map[string]ModelKVLayout{
+		"small": {BlockSizeTokens: 16, BytesPerToken: 2},
+		"large": {BlockSizeTokens: 32, BytesPerToken: 4},
+	})
+	small, large := mm.ForModel("small"), mm.ForModel("large")
+	require.Equal(t, int64(32), small.TotalCapacity())
+	require.Equal(t, int64(8), large.TotalCapacity())
+
+	// WHEN the large model holds 2 blocks (256 bytes)
+	l1 := modelRequest("l1", "large", 64, 1)
+	require.True(t, mm.AllocateKVBlocks(l1, 0, 64, nil))
+
+	// THEN each view counts the pool's used bytes in its own blocks
+	assert.Equal(t, int64(8), small.UsedBlocks())
+	assert.Equal(t, int64(2), large.UsedBlocks())
+
+	// AND the model-agnostic view is denominated in the base (smallest) blocks
+	assert.Equal(t, int64(16), mm.BlockSize())
+	assert.Equal(t, int64(32), mm.TotalCapacity())
+	assert.Equal(t, int64(8), mm.UsedBlocks())
+
+	// AND a small block that only partly fills a large one rounds the large
+	// view's usage up, so its free count never exceeds what it can allocate
+	s1 := modelRequest("s1", "small", 16, 1)
+	require.True(t, mm.AllocateKVBlocks(s1, 0, 16, nil))
+	assert.Equal(t, int64(3), large.UsedBlocks())
+	assert.Equal(t, int64(5), large.TotalCapacity()-large.UsedBlocks())
+}
+
+func TestMultiModelKVCache_CanAllocateAfterRelease_CreditsOtherModelsVictims(t *testing.T) {
+	// GIVEN a 512-byte pool filled by two large-model requests
+	mm := NewMultiModelKVCache(512, map[string]ModelKVLayout{
+		"small": {BlockSizeTokens: 16, BytesPerToken: 2},
+		"large": {BlockSizeTokens: 32, BytesPerToken: 4},
+	})
+	l1 := modelRequest("l1", "large", 64, 1)
+	l2 := modelRequest("l2", "large", 64, 1000)
+	require.True(t, mm.AllocateKVBlocks(l1, 0, 64, nil))
+	require.True(t, mm.AllocateKVBlocks(l2, 0, 64, nil))
+	s1 := modelRequest("s1", "small", 128, 2000) // 8 small blocks, 256 bytes
+
+	// THEN the small request does not fit without preemption
+	require.False(t, mm.CanAllocateAfterRelease(nil, s1, 0, 128, nil))
+
+	// AND preempting one large request frees exactly the 256 bytes it needs,
+	// through the top-level store and the small model's view alike
+	assert.True(t, mm.CanAllocateAfterRelease([]*sim.Request{l1}, s1, 0, 128, nil))
+	assert.True(t, mm.ForModel("small").CanAllocateAfterRelease([]*sim.Request{l1}, s1, 0, 128, nil))
+
+	// AND the check agrees with the allocation after the release
+	mm.ReleaseKVBlocks(l1)
+	assert.True(t, mm.AllocateKVBlocks(s1, 0, 128, nil))
+	assert.Equal(t, mm.CapacityBytes(), mm.UsedBytes())
+}
+
+func TestMultiModelKVCache_PrefixCacheIsPerModel(t *testing.T) {
+	mm := NewMultiModelKVCache(4096, map[string]ModelKVLayout{
+		"a": {BlockSizeTokens: 4, BytesPerToken: 8},
+		"b": {BlockSizeTokens: 8, BytesPerToken: 8},
+	})
+	req := modelRequest("r1", "a", 16, 1)
+	require.True(t, mm.AllocateKVBlocks(req, 0, 16, nil))
+
+	// The same tokens hit model a's prefix cache but not model b's.
+	assert.Len(t, mm.ForModel("a").GetCachedBlocks(req.InputTokens), 4)
+	assert.Empty(t, mm.ForModel("b").GetCachedBlocks(req.InputTokens))
+}
+
+func TestNewMultiModelKVCache_InvalidConfig_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "NewMultiModelKVCache: capacityBytes must be > 0, got 0", func() {
+		NewMultiModelKVCache(0, map[string]ModelKVLayout{"m": {BlockSizeTokens: 16, BytesPerToken: 1}})
+	})
+	assert.PanicsWithValue(t, "NewMultiModelKVCache: at least one model layout is required", func() {
+		NewMultiModelKVCache(1024, nil)
+	})
+	assert.PanicsWithValue(t, `NewMultiModelKVCache: model "m": BytesPerToken must be > 0, got 0`, func() {
+		NewMultiModelKVCache(1024, map[string]ModelKVLayout{"m": {BlockSizeTokens: 16}})
+	})
+	assert.PanicsWithValue(t, `NewMultiModelKVCache: model "m": block of 2048 bytes exceeds capacity of 1024 bytes`, func() {
+		NewMultiModelKVCache(1024, map[string]ModelKVLayout{"m": {BlockSizeTokens: 16, BytesPerToken: 128}})
+	})
+	mm := NewMultiModelKVCache(1024, map[string]ModelKVLayout{"m": {BlockSizeTokens: 16, BytesPerToken: 1}})
+	assert.PanicsWithValue(t, `MultiModelKVCache: no KV layout for model "other"`, func() {
+		mm.ReleaseKVBlocks(&sim.Request{ID: "x", Model: "other"})
+	})
+}
@@ -0,0 +1,164 @@
+package kv
+
+import (
+	"fmt"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/internal/util"
+)
+
+// Parallel sampling (Request.ParallelSamples > 1): the prompt is prefilled
+// once into the request's own block table, which serves as sample 0. On the
+// first decode allocation the other samples are forked: each gets a block
+// table referencing the same prompt blocks (RefCount +1 per sample), so the
+// prompt costs its blocks once. Decode tokens are then appended to every
+// sample's table separately. A partially filled block still shared with
+// another sample is copied before it is written (copy-on-write); full prompt
+// blocks are never written and stay shared until the request is released.
+
+// sampleDecodeTokens returns the output tokens in [startIndex, endIndex).
+func sampleDecodeTokens(req *sim.Request, startIndex, endIndex int64) []sim.TokenID {
+	lo := startIndex - req.InputLen()
+	hi := min(max(endIndex-req.InputLen(), lo+1), util.Len64(req.OutputTokens))
+	return req.OutputTokens[lo:hi]
+}
+
+// forkSamples creates the block tables of samples 1..n-1 of req from sample
+// 0's, sharing every block. No-op once forked.
+func (kvc *KVCacheState) forkSamples(req *sim.Request) {
+	if _, forked := kvc.SampleMap[req.ID]; forked {
+		return
+	}
+	ids := kvc.RequestMap[req.ID]
+	tables := make([][]int64, req.ParallelSamples-1)
+	for s := range tables {
+		tables[s] = append([]int64(nil), ids...)
+		for _, id := range ids {
+			kvc.Blocks[id].RefCount++
+		}
+	}
+	kvc.SampleMap[req.ID] = tables
+	kvc.SharedBlocksSaved += int64(len(ids)) * int64(len(tables))
+}
+
+// sampleDecodeBlocksNeeded returns the free blocks appending k tokens to
+// every sample of req takes, in append order: a sample whose last block is
+// partial and still shared copies it, which leaves one fewer sharer for the
+// samples after it. Before the fork every sample shares sample 0's table.
+func (kvc *KVCacheState) sampleDecodeBlocksNeeded(req *sim.Request, k int64) int64 {
+	tables := [][]int64{kvc.RequestMap[req.ID]}
+	sharers := make(map[int64]int)
+	if forked, ok := kvc.SampleMap[req.ID]; ok {
+		tables = append(tables, forked...)
+	} else if ids := kvc.RequestMap[req.ID]; len(ids) > 0 {
+		for s := 1; s < req.ParallelSamples; s++ {
+			tables = append(tables, ids)
+		}
+		last := ids[len(ids)-1]
+		sharers[last] = kvc.Blocks[last].RefCount + req.ParallelSamples - 1
+	} else {
+		return int64(req.ParallelSamples) * ((k + kvc.BlockSizeTokens - 1) / kvc.BlockSizeTokens)
+	}
+	var need int64
+	for _, table := range tables {
+		rest := k
+		if len(table) > 0 {
+			lastID := table[len(table)-1]
+			last := kvc.Blocks[lastID]
+			if fill := util.Len64(last.Tokens); fill < kvc.BlockSizeTokens {
+				refs, seen := sharers[lastID]
+				if !seen {
+					refs = last.RefCount
+				}
+				if refs > 1 {
+					sharers[lastID] = refs - 1
+					need += (fill + k + kvc.BlockSizeTokens - 1) / kvc.BlockSizeTokens
+					continue
+				}
+				rest -= min(kvc.BlockSizeTokens-fill, rest)
+			}
+		}
+		need += (rest + kvc.BlockSizeTokens - 1) / kvc.BlockSizeTokens
+	}
+	return need
+}
+
+// allocateSampleDecode appends the decode tokens in [startIndex, endIndex)
+// to every sample of req, forking the samples on first use. All-or-nothing:
+// returns false without mutation when free blocks cannot cover every sample.
+func (kvc *KVCacheState) allocateSampleDecode(req *sim.Request, startIndex, endIndex int64) bool {
+	tokens := sampleDecodeTokens(req, startIndex, endIndex)
+	if kvc.sampleDecodeBlocksNeeded(req, util.Len64(tokens)) > kvc.countFreeBlocks() {
+		return false
+	}
+	kvc.forkSamples(req)
+	kvc.RequestMap[req.ID] = kvc.appendSampleTokens(req, kvc.RequestMap[req.ID], tokens)
+	tables := kvc.SampleMap[req.ID]
+	for s := range tables {
+		tables[s] = kvc.appendSampleTokens(req, tables[s], tokens)
+	}
+	return true
+}
+
+// appendSampleTokens appends tokens to one sample's block table and returns
+// the updated table. The caller has checked free capacity.
+func (kvc *KVCacheState) appendSampleTokens(req *sim.Request, table []int64, tokens []sim.TokenID) []int64 {
+	for len(tokens) > 0 {
+		if n := len(table); n > 0 {
+			last := kvc.Blocks[table[n-1]]
+			if fill := util.Len64(last.Tokens); fill < kvc.BlockSizeTokens {
+				if last.RefCount > 1 {
+					// Copy-on-write: this sample takes a private copy.
+					cp := kvc.takeFreeBlock(req, last.Tokens)
+					last.RefCount--
+					table[n-1] = cp.ID
+					last = cp
+					kvc.SharedBlocksSaved--
+				}
+				take := min(kvc.BlockSizeTokens-fill, util.Len64(tokens))
+				last.Tokens = append(last.Tokens, tokens[:take]...)
+				last.LastAccess = kvc.clock
+				tokens = tokens[take:]
+				continue
+			}
+		}
+		take := min(kvc.BlockSizeTokens, util.Len64(tokens))
+		blk := kvc.takeFreeBlock(req, tokens[:take])
+		table = append(table, blk.ID)
+		tokens = tokens[take:]
+	}
+	return table
+}
+
+// takeFreeBlock pops a free block for req holding a copy of tokens, counted
+// as a cache miss like any freshly allocated block. Decode blocks are never
+// hashed.
+func (kvc *KVCacheState) takeFreeBlock(req *sim.Request, tokens []sim.TokenID) *KVBlock {
+	blk := kvc.popFreeBlock()
+	if blk == nil {
+		panic(fmt.Sprintf("popFreeBlock returned nil after pre-check passed for req %s: INV-4 violation", req.ID))
+	}
+	if blk.Hash != "" {
+		delete(kvc.HashToBlock, blk.Hash)
+		blk.Hash = ""
+	}
+	blk.Tokens = append([]sim.TokenID{}, tokens...)
+	blk.RefCount = 1
+	blk.InUse = true
+	blk.LastAccess = kvc.clock
+	kvc.CacheMisses++
+	kvc.countsFor(req).Misses++
+	return blk
+}
+
+// releaseSamples drops the block tables of samples 1..n-1 of reqID.
+func (kvc *KVCacheState) releaseSamples(reqID string) {
+	tables, ok := kvc.SampleMap[reqID]
+	if !ok {
+		return
+	}
+	delete(kvc.SampleMap, reqID)
+	for _, ids := range tables {
+		kvc.releaseBlocks(reqID, ids)
+	}
+}
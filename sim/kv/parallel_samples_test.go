@@ -0,0 +1,121 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// TestAllocateKVBlocks_ParallelSamples_PromptBlocksSharedUntilRelease verifies
+// that N samples of one request hold the prompt's full blocks once, take
+// private decode blocks (copying the shared partial block on write), and
+// leave nothing allocated after release.
+func TestAllocateKVBlocks_ParallelSamples_PromptBlocksSharedUntilRelease(t *testing.T) {
+	// GIVEN BlockSize=4 and a 10-token prompt (2 full blocks + 1 partial) with 4 samples of 6 tokens
+	const samples = 4
+	kvc := NewKVCacheState(32, 4)
+	req := &sim.Request{
+		ID:              "r1",
+		InputTokens:     []sim.TokenID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		OutputTokens:    []sim.TokenID{11, 12, 13, 14, 15, 16},
+		ParallelSamples: samples,
+	}
+
+	// WHEN the prompt is prefilled
+	if !kvc.AllocateKVBlocks(req, 0, 10, nil) {
+		t.Fatal("prefill allocation failed")
+	}
+	req.ProgressIndex = 10
+	prompt := append([]int64(nil), kvc.RequestMap["r1"]...)
+
+	// THEN its blocks are allocated once
+	if got := kvc.UsedBlocks(); got != 3 {
+		t.Fatalf("after prefill UsedBlocks = %d, want 3", got)
+	}
+
+	// WHEN all 6 decode tokens are appended to every sample
+	for ; req.ProgressIndex < 16; req.ProgressIndex++ {
+		if !kvc.AllocateKVBlocks(req, req.ProgressIndex, req.ProgressIndex+1, nil) {
+			t.Fatalf("decode allocation failed at PI=%d", req.ProgressIndex)
+		}
+	}
+
+	// THEN the 2 full prompt blocks are shared by all samples and each sample
+	// holds 2 private blocks (16 tokens = 4 blocks per sample): 2 + 4×2 = 10
+	// blocks instead of 4×4 = 16
+	for _, id := range prompt[:2] {
+		if rc := kvc.Blocks[id].RefCount; rc != samples {
+			t.Errorf("prompt block %d RefCount = %d, want %d", id, rc, samples)
+		}
+	}
+	if got := kvc.UsedBlocks(); got != 10 {
+		t.Errorf("after decode UsedBlocks = %d, want 10", got)
+	}
+	if got := kvc.BlocksSavedBySharing(); got != 6 {
+		t.Errorf("BlocksSavedBySharing = %d, want 6", got)
+	}
+	tables := append([][]int64{kvc.RequestMap["r1"]}, kvc.SampleMap["r1"]...)
+	seen := map[int64]int{}
+	for s, table := range tables {
+		if len(table) != 4 {
+			t.Errorf("sample %d holds %d blocks, want 4", s, len(table))
+		}
+		for _, id := range table[2:] {
+			seen[id]++
+		}
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("decode block %d shared by %d samples, want private", id, n)
+		}
+	}
+	assertBlockConservation(t, kvc)
+
+	// WHEN the request completes
+	kvc.ReleaseKVBlocks(req)
+
+	// THEN every block is free again
+	if got := kvc.UsedBlocks(); got != 0 {
+		t.Errorf("after release UsedBlocks = %d, want 0 (leak)", got)
+	}
+	for _, blk := range kvc.Blocks {
+		if blk.RefCount != 0 {
+			t.Errorf("block %d RefCount = %d after release, want 0", blk.ID, blk.RefCount)
+		}
+	}
+	if len(kvc.SampleMap) != 0 {
+		t.Errorf("SampleMap has %d entries after release, want 0", len(kvc.SampleMap))
+	}
+	assertBlockConservation(t, kvc)
+}
+
+// TestAllocateKVBlocks_ParallelSamples_InsufficientBlocks_NoMutation verifies
+// the fork is all-or-nothing: when the samples' copy-on-write blocks do not
+// fit, nothing is allocated.
+func TestAllocateKVBlocks_ParallelSamples_InsufficientBlocks_NoMutation(t *testing.T) {
+	// GIVEN 4 blocks, a 6-token prompt (1 full + 1 partial) and 4 samples needing 3 copies
+	kvc := NewKVCacheState(4, 4)
+	req := &sim.Request{
+		ID:              "r1",
+		InputTokens:     []sim.TokenID{1, 2, 3, 4, 5, 6},
+		OutputTokens:    []sim.TokenID{7, 8},
+		ParallelSamples: 4,
+	}
+	if !kvc.AllocateKVBlocks(req, 0, 6, nil) {
+		t.Fatal("prefill allocation failed")
+	}
+	req.ProgressIndex = 6
+
+	// WHEN the first decode token is allocated with only 2 free blocks
+	ok := kvc.AllocateKVBlocks(req, 6, 7, nil)
+
+	// THEN it fails without forking or allocating
+	if ok {
+		t.Fatal("decode allocation succeeded, want failure (3 copies needed, 2 free)")
+	}
+	if kvc.UsedBlocks() != 2 || len(kvc.SampleMap) != 0 || kvc.BlocksSavedBySharing() != 0 {
+		t.Errorf("state mutated on failure: used=%d forks=%d saved=%d", kvc.UsedBlocks(), len(kvc.SampleMap), kvc.BlocksSavedBySharing())
+	}
+	kvc.ReleaseKVBlocks(req)
+	assertBlockConservation(t, kvc)
+}
@@ -0,0 +1,67 @@
+package kv
+
+import "sort"
+
+// PrefixIndexEntry describes one cached block in a KV cache's prefix index.
+// Because block hashes chain over all preceding blocks, each entry identifies
+// a whole prefix: the first PrefixBlocks blocks of every request that hits it.
+type PrefixIndexEntry struct {
+	Hash           string `json:"hash"`
+	PrefixBlocks   int64  `json:"prefix_blocks"` // blocks in the prefix this hash identifies
+	RefCount       int    `json:"ref_count"`     // requests currently holding the block
+	InUse          bool   `json:"in_use"`        // false = free but still reusable (evictable)
+	LastAccessTick int64  `json:"last_access_tick"`
+}
+
+// PrefixIndexState is a point-in-time dump of a KV cache's prefix index, for
+// debugging cache behavior (--dump-kv-state). Entries are ordered by
+// PrefixBlocks, then Hash, so dumps of identical runs are byte-identical.
+type PrefixIndexState struct {
+	BlockSizeTokens int64              `json:"block_size_tokens"`
+	TotalBlocks     int64              `json:"total_blocks"`
+	UsedBlocks      int64              `json:"used_blocks"`
+	CachedBlocks    int64              `json:"cached_blocks"`               // len(Entries)
+	CPUCachedBlocks int64              `json:"cpu_cached_blocks,omitempty"` // blocks held by the CPU tier (tiered mode only)
+	Entries         []PrefixIndexEntry `json:"entries"`
+}
+
+// PrefixIndexer is implemented by KV stores that can dump their prefix index.
+// Both KVCacheState and TieredKVCache implement it.
+type PrefixIndexer interface {
+	PrefixIndexState() PrefixIndexState
+}
+
+// PrefixIndexState dumps every hash currently findable by GetCachedBlocks.
+func (kvc *KVCacheState) PrefixIndexState() PrefixIndexState {
+	entries := make([]PrefixIndexEntry, 0, len(kvc.HashToBlock))
+	for h, id := range kvc.HashToBlock {
+		blk := kvc.Blocks[id]
+		entries = append(entries, PrefixIndexEntry{
+			Hash:           h,
+			PrefixBlocks:   blk.PrefixDepth,
+			RefCount:       blk.RefCount,
+			InUse:          blk.InUse,
+			LastAccessTick: blk.LastAccess,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].PrefixBlocks != entries[j].PrefixBlocks {
+			return entries[i].PrefixBlocks < entries[j].PrefixBlocks
+		}
+		return entries[i].Hash < entries[j].Hash
+	})
+	return PrefixIndexState{
+		BlockSizeTokens: kvc.BlockSizeTokens,
+		TotalBlocks:     kvc.TotalBlocks,
+		UsedBlocks:      kvc.UsedBlocks(),
+		CachedBlocks:    int64(len(entries)),
+		Entries:         entries,
+	}
+}
+
+// PrefixIndexState dumps the GPU tier's prefix index and the CPU tier's size.
+func (t *TieredKVCache) PrefixIndexState() PrefixIndexState {
+	st := t.gpu.PrefixIndexState()
+	st.CPUCachedBlocks = int64(len(t.cpu.blocks))
+	return st
+}
@@ -22,8 +22,21 @@ func init() {
 // NewKVStore creates a KVStore from KVCacheConfig.
 // Returns *KVCacheState for single-tier (KVCPUBlocks <= 0, the default).
 // Returns *TieredKVCache for tiered mode (KVCPUBlocks > 0).
+// Returns *MultiModelKVCache when ModelKVLayouts is set; it is single-tier
+// only, so combining it with KVCPUBlocks > 0 panics.
 func NewKVStore(cfg sim.KVCacheConfig) sim.KVStore {
+	if len(cfg.ModelKVLayouts) > 0 {
+		if cfg.KVCPUBlocks > 0 {
+			panic(fmt.Sprintf("NewKVStore: ModelKVLayouts requires a single-tier cache, got KVCPUBlocks=%d", cfg.KVCPUBlocks))
+		}
+		mm := NewMultiModelKVCache(cfg.KVCapacityBytes, cfg.ModelKVLayouts)
+		for _, m := range mm.budget.members {
+			m.CheckRefCounts = cfg.CheckRefCounts
+		}
+		return mm
+	}
 	gpu := NewKVCacheState(cfg.TotalKVBlocks, cfg.BlockSizeTokens)
+	gpu.CheckRefCounts = cfg.CheckRefCounts
 	if cfg.KVCPUBlocks <= 0 {
 		return gpu
 	}
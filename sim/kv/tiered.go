@@ -153,10 +153,20 @@ type TieredKVCache struct {
 	// Transfer latency accumulator (query-and-clear)
 	pendingLatency int64
 
+	// host is the shared host-memory bandwidth this cache's transfers draw
+	// from. Nil = un-contended transfers (default). clock is the tick of the
+	// current step, set via SetClock, used to timestamp host transfers.
+	host  *HostBandwidth
+	clock int64
+
 	// Metrics counters
 	cpuHitCount  int64
 	cpuMissCount int64
 	mirrorCount  int64 // total blocks stored to CPU via MirrorToCPU
+
+	// cpuMissByGroup attributes cpuMissCount to the requesting tenant and
+	// SLO class. Lazily allocated.
+	cpuMissByGroup map[sim.CacheGroup]int64
 }
 
 // NewTieredKVCache creates a TieredKVCache.
@@ -198,7 +208,8 @@ func (t *TieredKVCache) AllocateKVBlocks(req *sim.Request, startIndex, endIndex
 		return true
 	}
 	// GPU allocation failed — try targeted CPU reload for this request's prefix.
-	reloaded := t.reloadPrefixFromCPU(req.FullInputTokens())
+	// Cache-bypass requests never consult the prefix cache, CPU tier included.
+	reloaded := !req.NoCache && t.reloadPrefixFromCPU(req.FullInputTokens())
 	if reloaded {
 		// Re-compute cached blocks now that CPU content is back on GPU
 		newCached := t.gpu.GetCachedBlocks(req.FullInputTokens())
@@ -221,11 +232,11 @@ func (t *TieredKVCache) AllocateKVBlocks(req *sim.Request, startIndex, endIndex
 					// request's own partially-filled block).
 					startBlock := (startIndex + t.gpu.BlockSize() - 1) / t.gpu.BlockSize()
 					if startBlock < endBlock {
-						t.gpu.commitCachedBlocks(req.ID, newCached[startBlock:endBlock])
+						t.gpu.commitCachedBlocks(req, newCached[startBlock:endBlock])
 					}
 				} else {
 					// New request: commit all cached blocks from block 0.
-					t.gpu.commitCachedBlocks(req.ID, newCached[:endBlock])
+					t.gpu.commitCachedBlocks(req, newCached[:endBlock])
 				}
 				return true
 			}
@@ -242,11 +253,11 @@ func (t *TieredKVCache) AllocateKVBlocks(req *sim.Request, startIndex, endIndex
 				// same ceiling division as the full-range reload path above.
 				startBlock := (startIndex + t.gpu.BlockSize() - 1) / t.gpu.BlockSize()
 				if startBlock < newStartBlock {
-					t.gpu.commitCachedBlocks(req.ID, newCached[startBlock:newStartBlock])
+					t.gpu.commitCachedBlocks(req, newCached[startBlock:newStartBlock])
 				}
 			} else {
 				// New request: commit all reloaded blocks from block 0.
-				t.gpu.commitCachedBlocks(req.ID, newCached[:newStartBlock])
+				t.gpu.commitCachedBlocks(req, newCached[:newStartBlock])
 			}
 			return t.gpu.AllocateKVBlocks(req, newStart, endIndex, newCached)
 		}
@@ -254,6 +265,10 @@ func (t *TieredKVCache) AllocateKVBlocks(req *sim.Request, startIndex, endIndex
 		return t.gpu.AllocateKVBlocks(req, startIndex, endIndex, cachedBlocks)
 	}
 	t.cpuMissCount++
+	if t.cpuMissByGroup == nil {
+		t.cpuMissByGroup = make(map[sim.CacheGroup]int64)
+	}
+	t.cpuMissByGroup[sim.CacheGroup{TenantID: req.TenantID, SLOClass: req.SLOClass}]++
 	return false
 }
 
@@ -271,6 +286,7 @@ func (t *TieredKVCache) reloadPrefixFromCPU(tokens []sim.TokenID) bool {
 	prevHash := ""
 	reloaded := false
 	reloadCount := int64(0)
+	transferTicks := int64(0)
 	for i := int64(0); i < n; i++ {
 		start := i * t.gpu.BlockSize()
 		end := start + t.gpu.BlockSize()
@@ -311,15 +327,19 @@ func (t *TieredKVCache) reloadPrefixFromCPU(tokens []sim.TokenID) bool {
 
 		gpuBlk.Tokens = append(gpuBlk.Tokens[:0], cpuBlk.tokens...)
 		gpuBlk.Hash = h
+		gpuBlk.PrefixDepth = i + 1
+		gpuBlk.LastAccess = t.clock
 		gpuBlk.RefCount = 0
 		gpuBlk.InUse = false
 		t.gpu.HashToBlock[h] = gpuBlk.ID
 		t.gpu.appendToFreeList(gpuBlk)
 
-		// Accumulate transfer latency
+		// Accumulate transfer latency. The per-block fixed cost is not
+		// bandwidth-bound; the transfer ticks are charged after the loop so a
+		// shared host can stretch them as one transfer.
 		blockSize := float64(t.gpu.BlockSize())
-		transferTicks := int64(math.Ceil(blockSize / t.transferBandwidth))
-		t.pendingLatency += t.baseLatency + transferTicks
+		transferTicks += int64(math.Ceil(blockSize / t.transferBandwidth))
+		t.pendingLatency += t.baseLatency
 
 		// Touch CPU block to refresh LRU recency (block is actively needed)
 		t.cpu.touch(h)
@@ -329,6 +349,10 @@ func (t *TieredKVCache) reloadPrefixFromCPU(tokens []sim.TokenID) bool {
 		reloadCount++
 		prevHash = h
 	}
+	if transferTicks > 0 && t.host != nil {
+		transferTicks = t.host.transfer(t, t.clock, transferTicks)
+	}
+	t.pendingLatency += transferTicks
 	return reloaded
 }
 
@@ -348,9 +372,25 @@ func (t *TieredKVCache) ReleaseKVBlocks(req *sim.Request) {
 	// Hashes are cleared only when popFreeBlock() reuses the slot.
 }
 
+// SeedPrefix seeds the GPU tier only; the CPU tier fills through MirrorToCPU.
+func (t *TieredKVCache) SeedPrefix(tokens []sim.TokenID) int64 { return t.gpu.SeedPrefix(tokens) }
+
+// ExportPrefixes exports the GPU tier's prefix index; CPU-tier blocks are not
+// findable by GetCachedBlocks until reloaded, so they are not exported.
+func (t *TieredKVCache) ExportPrefixes() [][]sim.TokenID { return t.gpu.ExportPrefixes() }
+
 func (t *TieredKVCache) BlockSize() int64    { return t.gpu.BlockSize() }
 func (t *TieredKVCache) UsedBlocks() int64   { return t.gpu.UsedBlocks() }
 func (t *TieredKVCache) TotalCapacity() int64 { return t.gpu.TotalCapacity() }
+func (t *TieredKVCache) BlocksSavedBySharing() int64 { return t.gpu.BlocksSavedBySharing() }
+
+// RoundingWaste reports the GPU tier, where every allocation lands.
+func (t *TieredKVCache) RoundingWaste() (wasteTokens, allocations int64) { return t.gpu.RoundingWaste() }
+
+// CanAllocateAfterRelease checks the GPU tier, where every allocation lands.
+func (t *TieredKVCache) CanAllocateAfterRelease(victims []*sim.Request, req *sim.Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	return t.gpu.CanAllocateAfterRelease(victims, req, startIndex, endIndex, cachedBlocks)
+}
 
 func (t *TieredKVCache) CacheHitRate() float64 {
 	// gpu.CacheHits already includes CPU-reloaded blocks (they appear as GPU
@@ -365,6 +405,24 @@ func (t *TieredKVCache) CacheHitRate() float64 {
 	return float64(totalHits) / float64(total)
 }
 
+// CacheHitCountsByGroup mirrors CacheHitRate per tenant and SLO class: GPU
+// hits and misses plus failed CPU reloads counted as misses.
+func (t *TieredKVCache) CacheHitCountsByGroup() map[sim.CacheGroup]sim.CacheHitCounts {
+	out := t.gpu.CacheHitCountsByGroup()
+	if len(t.cpuMissByGroup) == 0 {
+		return out
+	}
+	if out == nil {
+		out = make(map[sim.CacheGroup]sim.CacheHitCounts, len(t.cpuMissByGroup))
+	}
+	for g, n := range t.cpuMissByGroup {
+		c := out[g]
+		c.Misses += n
+		out[g] = c
+	}
+	return out
+}
+
 // PendingTransferLatency returns the accumulated transfer latency without clearing it.
 // This is a pure query — no side effects. Use ConsumePendingTransferLatency to read and clear.
 func (t *TieredKVCache) PendingTransferLatency() int64 {
@@ -389,8 +447,17 @@ func (t *TieredKVCache) KVThrashingRate() float64 {
 	return float64(t.cpu.evictionCount) / float64(t.mirrorCount)
 }
 
-// SetClock is a no-op in vLLM v1 model (thrashing detection removed).
-func (t *TieredKVCache) SetClock(_ int64) {}
+// SetClock records the current tick on both tiers. Thrashing detection was
+// removed in the vLLM v1 model; the clock only timestamps transfers on a shared
+// HostBandwidth and block accesses reported by PrefixIndexState.
+func (t *TieredKVCache) SetClock(now int64) {
+	t.clock = now
+	t.gpu.SetClock(now)
+}
+
+// SetHostBandwidth attaches the shared host-memory bandwidth resource this
+// cache's CPU↔GPU transfers contend on. Nil restores un-contended transfers.
+func (t *TieredKVCache) SetHostBandwidth(h *HostBandwidth) { t.host = h }
 
 // MirrorToCPU copies newly-completed full blocks from batch requests to CPU tier.
 // For each request in the batch, all full blocks with hashes are processed:
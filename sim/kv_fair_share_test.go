@@ -0,0 +1,107 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// runKVFairShareWorkload runs one giant-context request (700-token prompt,
+// 200-token output ⇒ 57 blocks at completion) followed by 30 small requests (32-token prompt, 32-token output) that
+// all arrive at t=0 against a 64-block KV cache. It returns the worst small
+// request E2E, the giant's E2E, and the completion count.
+func runKVFairShareWorkload(t *testing.T, mode string) (smallMaxE2E, giantE2E float64, completed int) {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(64, 16, 0, 0, 0, 0)
+	cfg.BatchConfig = NewBatchConfig(32, 4096, 0)
+	cfg.KVAllocationMode = mode
+	s := newFixedStepSimulator(t, cfg)
+	giant := uniformRequests(1, 700, 200, 0)[0]
+	giant.ID = "giant"
+	s.InjectArrival(giant)
+	for i, req := range uniformRequests(30, 32, 32, 0) {
+		req.ID = fmt.Sprintf("small_%d", i)
+		s.InjectArrival(req)
+	}
+	s.Run()
+	for id, e2e := range s.Metrics.RequestE2Es {
+		if id == "giant" {
+			giantE2E = e2e
+		} else {
+			smallMaxE2E = max(smallMaxE2E, e2e)
+		}
+	}
+	return smallMaxE2E, giantE2E, s.Metrics.CompletedRequests
+}
+
+// TestKVFairShare_SmallRequestsNotStarved verifies that capping per-request KV
+// at a fair share keeps a giant-context request from monopolizing the cache:
+// the small requests finish sooner while the giant still completes.
+func TestKVFairShare_SmallRequestsNotStarved(t *testing.T) {
+	// GIVEN the workload with greedy allocation
+	greedySmall, greedyGiant, greedyDone := runKVFairShareWorkload(t, KVAllocationGreedy)
+	// AND with fair-share allocation
+	fairSmall, fairGiant, fairDone := runKVFairShareWorkload(t, KVAllocationFairShare)
+
+	// THEN every request completes in both runs (INV-1), including the giant
+	if greedyDone != 31 || fairDone != 31 {
+		t.Fatalf("CompletedRequests: greedy=%d fair-share=%d, want 31 each", greedyDone, fairDone)
+	}
+	if greedyGiant == 0 || fairGiant == 0 {
+		t.Fatalf("giant E2E: greedy=%v fair-share=%v, want completed", greedyGiant, fairGiant)
+	}
+	// AND the slowest small request finishes sooner under fair share
+	if fairSmall >= greedySmall {
+		t.Errorf("max small-request E2E = %.0f with fair share, want < %.0f with greedy", fairSmall, greedySmall)
+	}
+	t.Logf("small max E2E: greedy=%.0f fair=%.0f; giant E2E: greedy=%.0f fair=%.0f", greedySmall, fairSmall, greedyGiant, fairGiant)
+}
+
+// TestKVFairShare_DefaultIsGreedy verifies the empty mode behaves exactly like
+// greedy allocation (INV-6).
+func TestKVFairShare_DefaultIsGreedy(t *testing.T) {
+	defSmall, defGiant, defDone := runKVFairShareWorkload(t, "")
+	greedySmall, greedyGiant, greedyDone := runKVFairShareWorkload(t, KVAllocationGreedy)
+	if defSmall != greedySmall || defGiant != greedyGiant || defDone != greedyDone {
+		t.Errorf("default mode (%v, %v, %d) differs from greedy (%v, %v, %d)",
+			defSmall, defGiant, defDone, greedySmall, greedyGiant, greedyDone)
+	}
+}
+
+func TestKVFairShareTokenCap(t *testing.T) {
+	kv := MustNewKVCacheState(100, 16)
+	tests := []struct {
+		name      string
+		fairShare bool
+		maxBlocks int64
+		active    int
+		want      int64
+	}{
+		{"disabled", false, 0, 4, 1<<63 - 1},
+		{"single request uncapped", true, 0, 1, 1<<63 - 1},
+		{"dynamic share", true, 0, 4, 25 * 16},
+		{"dynamic share floors at one block", true, 0, 200, 16},
+		{"fixed max", true, 10, 4, 10 * 16},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := kvFairShareTokenCap(BatchContext{KVCache: kv, KVFairShare: tc.fairShare, KVFairShareMaxBlocks: tc.maxBlocks}, tc.active)
+			if got != tc.want {
+				t.Errorf("kvFairShareTokenCap = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewSimulator_KVAllocationModeValidation(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.KVAllocationMode = "hog"
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+		t.Error("KVAllocationMode=hog: expected error")
+	}
+	cfg = newTestSimConfig()
+	cfg.KVFairShareMaxBlocks = -1
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+		t.Error("KVFairShareMaxBlocks=-1: expected error")
+	}
+}
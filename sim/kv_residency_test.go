@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// allocTickKVStore wraps a KVStore and records the clock of each request's
+// first successful block allocation.
+type allocTickKVStore struct {
+	KVStore
+	clock      int64
+	firstAlloc map[string]int64
+}
+
+func (s *allocTickKVStore) SetClock(clock int64) {
+	s.clock = clock
+	s.KVStore.SetClock(clock)
+}
+
+func (s *allocTickKVStore) AllocateKVBlocks(req *Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	ok := s.KVStore.AllocateKVBlocks(req, startIndex, endIndex, cachedBlocks)
+	if _, seen := s.firstAlloc[req.ID]; ok && !seen {
+		s.firstAlloc[req.ID] = s.clock
+	}
+	return ok
+}
+
+// TestKVResidency_ReleaseMinusFirstAlloc verifies each completed request's
+// residency is its release (completion) tick minus its first block-allocation
+// tick, and that longer outputs hold their blocks longer.
+func TestKVResidency_ReleaseMinusFirstAlloc(t *testing.T) {
+	// GIVEN requests with equal prompts and growing output lengths, with no
+	// post-step overheads so completion is the end of the final step
+	cfg := newTestSimConfig()
+	store := &allocTickKVStore{KVStore: MustNewKVStoreFromConfig(cfg.KVCacheConfig), firstAlloc: map[string]int64{}}
+	s, err := NewSimulator(cfg, store, &overheadStepModel{perPass: 100})
+	if err != nil {
+		t.Fatalf("NewSimulator: %v", err)
+	}
+	outputs := []int{5, 20, 80}
+	for i, n := range outputs {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("req_%d", i),
+			ArrivalTime:  int64(500 * i),
+			InputTokens:  make([]TokenID, 64),
+			OutputTokens: make([]TokenID, n),
+			MaxOutputLen: n,
+			State:        StateQueued,
+		})
+	}
+
+	// WHEN the simulation runs to completion
+	s.Run()
+	if s.Metrics.CompletedRequests != len(outputs) {
+		t.Fatalf("CompletedRequests = %d, want %d", s.Metrics.CompletedRequests, len(outputs))
+	}
+
+	// THEN residency = release tick − first allocation tick
+	prev := int64(0)
+	for i := range outputs {
+		id := fmt.Sprintf("req_%d", i)
+		release := int64(s.Metrics.RequestCompletionTimes[id])
+		want := release - store.firstAlloc[id]
+		got, ok := s.Metrics.RequestKVResidencies[id]
+		if !ok {
+			t.Fatalf("%s: no KV residency recorded", id)
+		}
+		if got != want {
+			t.Errorf("%s: residency = %d, want release %d − first alloc %d = %d", id, got, release, store.firstAlloc[id], want)
+		}
+		// AND a longer output means a longer residency
+		if got <= prev {
+			t.Errorf("%s (%d output tokens): residency %d not longer than previous %d", id, outputs[i], got, prev)
+		}
+		prev = got
+	}
+
+	// AND the file-only summary reports the mean and P99 in ms
+	mean, p99 := s.Metrics.kvResidencies()
+	if mean <= 0 || p99 < mean {
+		t.Errorf("kvResidencies() = mean %v, p99 %v; want 0 < mean <= p99", mean, p99)
+	}
+}
@@ -0,0 +1,49 @@
+package sim
+
+// recordKVSamplesBefore carries the current KV usage into every sample
+// interval that starts before nextEventTime (and within the horizon): usage
+// cannot change until that event fires, so each of those intervals held at
+// least this many blocks. No-op when sampling is disabled.
+func (sim *Simulator) recordKVSamplesBefore(nextEventTime int64) {
+	interval := sim.Metrics.KVSampleIntervalUs
+	if interval <= 0 {
+		return
+	}
+	used := sim.KVCache.UsedBlocks()
+	if n := len(sim.Metrics.KVUsedSeries); n > 0 {
+		sim.Metrics.KVUsedSeries[n-1] = max(sim.Metrics.KVUsedSeries[n-1], used)
+	}
+	for start := interval * int64(len(sim.Metrics.KVUsedSeries)); start < nextEventTime && start <= sim.Horizon; start += interval {
+		sim.Metrics.KVUsedSeries = append(sim.Metrics.KVUsedSeries, used)
+	}
+}
+
+// recordKVSample raises the current interval's sample to used, the blocks in
+// use at a step (the same measurement as PeakKVBlocksUsed).
+func (sim *Simulator) recordKVSample(used int64) {
+	interval := sim.Metrics.KVSampleIntervalUs
+	if interval <= 0 {
+		return
+	}
+	k := int(sim.Clock / interval)
+	for len(sim.Metrics.KVUsedSeries) <= k {
+		sim.Metrics.KVUsedSeries = append(sim.Metrics.KVUsedSeries, 0)
+	}
+	sim.Metrics.KVUsedSeries[k] = max(sim.Metrics.KVUsedSeries[k], used)
+}
+
+// finishKVSeries sizes the KV usage series to the intervals overlapping
+// [0, SimEndedTime], filling trailing intervals with the final usage.
+func (sim *Simulator) finishKVSeries() {
+	interval := sim.Metrics.KVSampleIntervalUs
+	if interval <= 0 {
+		return
+	}
+	n := int(sim.Metrics.SimEndedTime/interval) + 1
+	if len(sim.Metrics.KVUsedSeries) > n {
+		sim.Metrics.KVUsedSeries = sim.Metrics.KVUsedSeries[:n]
+	}
+	for used := sim.KVCache.UsedBlocks(); len(sim.Metrics.KVUsedSeries) < n; {
+		sim.Metrics.KVUsedSeries = append(sim.Metrics.KVUsedSeries, used)
+	}
+}
@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSimulator_KVSampleInterval_RecordsPeakPerInterval(t *testing.T) {
+	// GIVEN 20 requests with distinct prompts arriving every 20ms, KV usage sampled every 50ms
+	cfg := newTestSimConfig()
+	cfg.KVSampleIntervalUs = 50_000
+	s := mustNewSimulator(t, cfg)
+	injectRequests(s, distinctPrompts(uniformRequests(20, 200, 80, 20_000)))
+
+	// WHEN the simulation runs to completion
+	s.Run()
+
+	// THEN there is one sample per interval overlapping [0, SimEndedTime]
+	m := s.Metrics
+	if m.CompletedRequests != 20 {
+		t.Fatalf("CompletedRequests = %d, want 20", m.CompletedRequests)
+	}
+	wantLen := int(m.SimEndedTime/50_000) + 1
+	if len(m.KVUsedSeries) != wantLen {
+		t.Fatalf("len(KVUsedSeries) = %d, want %d/50000+1 = %d", len(m.KVUsedSeries), m.SimEndedTime, wantLen)
+	}
+
+	// AND the series peaks at PeakKVBlocksUsed
+	if m.PeakKVBlocksUsed == 0 {
+		t.Fatal("PeakKVBlocksUsed = 0, want a loaded cache")
+	}
+	if peak := slices.Max(m.KVUsedSeries); peak != m.PeakKVBlocksUsed {
+		t.Errorf("max(KVUsedSeries) = %d, want PeakKVBlocksUsed %d", peak, m.PeakKVBlocksUsed)
+	}
+
+	// AND every interval while arrivals are still coming in holds blocks
+	for k := 0; int64(k)*50_000 < 19*20_000; k++ {
+		if m.KVUsedSeries[k] == 0 {
+			t.Errorf("KVUsedSeries[%d] = 0 during the arrival stream, want > 0", k)
+		}
+	}
+
+	// AND the series is written to the output
+	out := m.BuildOutput("instance_0", nil)
+	if out.KVUsedSeries == nil || out.KVUsedSeries.IntervalUs != 50_000 || len(out.KVUsedSeries.Used) != wantLen {
+		t.Errorf("BuildOutput KVUsedSeries = %+v, want interval 50000 and %d samples", out.KVUsedSeries, wantLen)
+	}
+}
+
+func TestSimulator_KVSampleIntervalZero_NoSeries(t *testing.T) {
+	// GIVEN KV sampling disabled (the default)
+	s := mustNewSimulator(t, newTestSimConfig())
+	s.InjectArrival(&Request{ID: "request_0", InputTokens: make([]TokenID, 10), OutputTokens: make([]TokenID, 5), State: StateQueued})
+
+	// WHEN the simulation runs
+	s.Run()
+
+	// THEN no series is recorded or emitted (INV-6)
+	if len(s.Metrics.KVUsedSeries) != 0 {
+		t.Errorf("KVUsedSeries = %v, want empty", s.Metrics.KVUsedSeries)
+	}
+	if out := s.Metrics.BuildOutput("instance_0", nil); out.KVUsedSeries != nil {
+		t.Errorf("BuildOutput KVUsedSeries = %+v, want nil", out.KVUsedSeries)
+	}
+}
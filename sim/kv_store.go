@@ -1,20 +1,115 @@
 package sim
 
 // KVStore abstracts KV cache operations for the simulator.
-// kv.KVCacheState (single-tier GPU) and kv.TieredKVCache (GPU+CPU) both implement this.
+// kv.KVCacheState (single-tier GPU), kv.TieredKVCache (GPU+CPU) and
+// kv.MultiModelKVCache (per-model block sizes, see ModelScopedKVStore) implement this.
 type KVStore interface {
 	AllocateKVBlocks(req *Request, startIndex, endIndex int64, cachedBlocks []int64) bool
 	GetCachedBlocks(tokens []TokenID) []int64
 	ReleaseKVBlocks(req *Request)
+	SeedPrefix(tokens []TokenID) int64 // Write uncached full prefix blocks as free, reusable blocks; no hit/miss accounting. Returns blocks written.
+	ExportPrefixes() [][]TokenID       // Tokens of every fully cached prefix, least recently used first; SeedPrefix of each, in order, rebuilds the index. Pure query.
 	BlockSize() int64
 	UsedBlocks() int64
 	TotalCapacity() int64
 	CacheHitRate() float64
+	CacheHitCountsByGroup() map[CacheGroup]CacheHitCounts
 	PendingTransferLatency() int64            // Pure query: returns accumulated transfer latency without clearing.
 	ConsumePendingTransferLatency() int64     // Read and clear: returns accumulated transfer latency and resets to zero.
 	KVThrashingRate() float64
+	BlocksSavedBySharing() int64 // Block allocations avoided by parallel samples sharing prompt blocks (Request.ParallelSamples)
+	RoundingWaste() (wasteTokens, allocations int64) // Unfilled last-block slots summed over successful allocations, and the allocation count
 	SetClock(clock int64)            // Synchronize clock for time-dependent operations. No-op for single-tier.
 	MirrorToCPU(batch []*Request)    // Copy newly-completed full blocks to CPU tier. No-op for single-tier.
+	// Pure query: whether req's prefill allocation would pass its free-block
+	// check once every victim's blocks were released.
+	CanAllocateAfterRelease(victims []*Request, req *Request, startIndex, endIndex int64, cachedBlocks []int64) bool
+}
+
+// ModelScopedKVStore is a KVStore shared by several models with different
+// block sizes (kv.MultiModelKVCache). Its own methods report the shared pool
+// in one model-agnostic unit; ForModel returns the view a request of model
+// must use for block-size-dependent work: prefix lookup, block arithmetic and
+// free-block checks, all in that model's blocks.
+type ModelScopedKVStore interface {
+	KVStore
+	ForModel(model string) KVStore
+}
+
+// kvStoreFor returns the KVStore view req's blocks are counted in: the
+// per-model view of a ModelScopedKVStore, otherwise kvc itself.
+func kvStoreFor(kvc KVStore, req *Request) KVStore {
+	if scoped, ok := kvc.(ModelScopedKVStore); ok {
+		return scoped.ForModel(req.Model)
+	}
+	return kvc
+}
+
+// CacheGroup identifies the requester a prefix-cache lookup is attributed to.
+type CacheGroup struct {
+	TenantID string
+	SLOClass string
+}
+
+// CacheHitCounts counts KV blocks served from the prefix cache (Hits) and
+// blocks allocated fresh (Misses), on the same basis as KVStore.CacheHitRate.
+type CacheHitCounts struct {
+	Hits   int64
+	Misses int64
+}
+
+// Rate returns Hits / (Hits + Misses), or 0 when nothing was counted.
+func (c CacheHitCounts) Rate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+// CacheHitCountsBy pools per-group counts along one dimension, e.g. per
+// tenant with key func(g CacheGroup) string { return g.TenantID }. Returns
+// nil when counts is empty.
+func CacheHitCountsBy(counts map[CacheGroup]CacheHitCounts, key func(CacheGroup) string) map[string]CacheHitCounts {
+	if len(counts) == 0 {
+		return nil
+	}
+	pooled := make(map[string]CacheHitCounts)
+	for g, c := range counts {
+		k := key(g)
+		p := pooled[k]
+		p.Hits += c.Hits
+		p.Misses += c.Misses
+		pooled[k] = p
+	}
+	return pooled
+}
+
+// MergeCacheHitCounts adds src into dst per key, allocating dst if nil, and
+// returns dst.
+func MergeCacheHitCounts(dst, src map[string]CacheHitCounts) map[string]CacheHitCounts {
+	for k, c := range src {
+		if dst == nil {
+			dst = make(map[string]CacheHitCounts, len(src))
+		}
+		d := dst[k]
+		d.Hits += c.Hits
+		d.Misses += c.Misses
+		dst[k] = d
+	}
+	return dst
+}
+
+// CacheHitRates returns the hit rate per key, or nil when counts is empty.
+func CacheHitRates(counts map[string]CacheHitCounts) map[string]float64 {
+	if len(counts) == 0 {
+		return nil
+	}
+	rates := make(map[string]float64, len(counts))
+	for k, c := range counts {
+		rates[k] = c.Rate()
+	}
+	return rates
 }
 
 // NewKVCacheStateFunc is a factory function for creating single-tier KVStore implementations.
@@ -68,11 +68,11 @@ func backends(t *testing.T, ac sim.AdapterCost) map[string]sim.LatencyModel {
 		opts = append(opts, WithAdapterCost(ac))
 	}
 	roofCoeffs := sim.NewLatencyCoeffs(nil, []float64{100, 1, 100})
-	roofHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0)
+	roofHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
 	roof, err := NewLatencyModel(roofCoeffs, roofHW, opts...)
 	require.NoError(t, err, "roofline NewLatencyModel")
 
-	tpHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "trained-physics", 0)
+	tpHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "trained-physics", 0, "")
 	tp, err := NewLatencyModel(*testCoeffs(), tpHW, opts...)
 	require.NoError(t, err, "trained-physics NewLatencyModel")
 
@@ -0,0 +1,86 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// GridPoint is one (batch tokens, context) probe for CompareStepTimes, on the
+// same axes as a step-time table: BatchTokens new tokens scheduled in the step
+// and ContextTokens tokens of context including them.
+type GridPoint struct {
+	BatchTokens   int64
+	ContextTokens int64
+}
+
+// PointDivergence is the comparison of two latency models at one grid point.
+type PointDivergence struct {
+	GridPoint
+	CandidateUs int64   // candidate step time (µs)
+	ReferenceUs int64   // reference step time (µs)
+	RelErr      float64 // |candidate - reference| / reference
+	Flagged     bool    // RelErr > DivergenceReport.Threshold
+}
+
+// DivergenceReport lists per-point relative error of a candidate latency model
+// against a reference, in grid order.
+type DivergenceReport struct {
+	Threshold float64
+	Points    []PointDivergence
+	MaxRelErr float64
+}
+
+// Flagged returns the points whose relative error exceeds the threshold, in grid order.
+func (r DivergenceReport) Flagged() []PointDivergence {
+	var out []PointDivergence
+	for _, p := range r.Points {
+		if p.Flagged {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CompareStepTimes runs candidate and reference on each grid point and reports
+// the relative error of candidate against reference, flagging points where it
+// exceeds threshold. Typical use is sanity-checking roofline model/hardware
+// parameters against a fitted backend (trained-physics coefficients or a
+// measured step-time table) before trusting roofline in a new configuration.
+//
+// Each point is probed as a single prefill chunk: one request with
+// ContextTokens input tokens, BatchTokens of them scheduled in the step.
+// Returns an error for an invalid threshold or grid point, or when the
+// reference predicts a non-positive step time (relative error undefined).
+func CompareStepTimes(candidate, reference sim.LatencyModel, grid []GridPoint, threshold float64) (DivergenceReport, error) {
+	if math.IsNaN(threshold) || math.IsInf(threshold, 0) || threshold <= 0 {
+		return DivergenceReport{}, fmt.Errorf("compare step times: threshold must be a finite value > 0, got %v", threshold)
+	}
+	report := DivergenceReport{Threshold: threshold, Points: make([]PointDivergence, 0, len(grid))}
+	for _, pt := range grid {
+		if pt.BatchTokens <= 0 || pt.ContextTokens < pt.BatchTokens {
+			return DivergenceReport{}, fmt.Errorf("compare step times: grid point %+v must have 0 < BatchTokens <= ContextTokens", pt)
+		}
+		probe := []*sim.Request{{
+			ID:            fmt.Sprintf("probe_%d_%d", pt.BatchTokens, pt.ContextTokens),
+			InputTokens:   make([]sim.TokenID, pt.ContextTokens),
+			ProgressIndex: pt.ContextTokens - pt.BatchTokens,
+			NumNewTokens:  int(pt.BatchTokens),
+		}}
+		cand, ref := candidate.StepTime(probe), reference.StepTime(probe)
+		if ref <= 0 {
+			return DivergenceReport{}, fmt.Errorf("compare step times: reference step time %d at %+v is not positive", ref, pt)
+		}
+		relErr := math.Abs(float64(cand-ref)) / float64(ref)
+		report.Points = append(report.Points, PointDivergence{
+			GridPoint:   pt,
+			CandidateUs: cand,
+			ReferenceUs: ref,
+			RelErr:      relErr,
+			Flagged:     relErr > threshold,
+		})
+		report.MaxRelErr = max(report.MaxRelErr, relErr)
+	}
+	return report, nil
+}
@@ -0,0 +1,82 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompareStepTimes_MismatchedReference_FlagsDivergentPoints verifies that
+// the divergence report flags exactly the grid points where the reference
+// disagrees with roofline beyond the threshold, and reports ~0 error elsewhere.
+func TestCompareStepTimes_MismatchedReference_FlagsDivergentPoints(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "roofline", 0, "")
+	roofline, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), hw)
+	require.NoError(t, err)
+
+	// GIVEN a reference table that reproduces roofline on a 3×2 grid except at
+	// two deliberately mismatched cells (2× and 0.5× roofline)
+	batches, contexts := []int64{64, 256, 1024}, []int64{1024, 8192}
+	mismatch := map[GridPoint]float64{
+		{BatchTokens: 64, ContextTokens: 8192}:   2.0,
+		{BatchTokens: 1024, ContextTokens: 1024}: 0.5,
+	}
+	var grid []GridPoint
+	var csv strings.Builder
+	csv.WriteString("batch_tokens,context_len,step_time_us\n")
+	for _, b := range batches {
+		for _, c := range contexts {
+			pt := GridPoint{BatchTokens: b, ContextTokens: c}
+			grid = append(grid, pt)
+			us := roofline.StepTime([]*sim.Request{{InputTokens: make([]sim.TokenID, c), ProgressIndex: c - b, NumNewTokens: int(b)}})
+			scale := 1.0
+			if s, ok := mismatch[pt]; ok {
+				scale = s
+			}
+			fmt.Fprintf(&csv, "%d,%d,%d\n", b, c, int64(float64(us)*scale))
+		}
+	}
+	table, err := ParseStepTimeTable(strings.NewReader(csv.String()))
+	require.NoError(t, err)
+	reference := &TableLatencyModel{table: table, alphaCoeffs: []float64{100, 1, 100}}
+
+	// WHEN roofline is compared against it with a 10% threshold
+	report, err := CompareStepTimes(roofline, reference, grid, 0.10)
+	require.NoError(t, err)
+
+	// THEN exactly the mismatched points are flagged
+	require.Len(t, report.Points, len(grid))
+	var flagged []GridPoint
+	for _, p := range report.Flagged() {
+		flagged = append(flagged, p.GridPoint)
+	}
+	assert.Equal(t, []GridPoint{{64, 8192}, {1024, 1024}}, flagged)
+	for _, p := range report.Points {
+		if _, ok := mismatch[p.GridPoint]; !ok {
+			assert.InDelta(t, 0, p.RelErr, 1e-9, "matched point %+v", p.GridPoint)
+		}
+	}
+	// Roofline is half the 2× reference (rel err 0.5) and double the 0.5× one (rel err 1.0).
+	assert.InDelta(t, 1.0, report.MaxRelErr, 0.01)
+}
+
+func TestCompareStepTimes_InvalidInput_ReturnsError(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "roofline", 0, "")
+	model, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), hw)
+	require.NoError(t, err)
+	valid := []GridPoint{{BatchTokens: 64, ContextTokens: 1024}}
+
+	for _, threshold := range []float64{0, -0.1, math.NaN()} {
+		_, err := CompareStepTimes(model, model, valid, threshold)
+		assert.Error(t, err, "threshold %v", threshold)
+	}
+	for _, pt := range []GridPoint{{0, 1024}, {2048, 1024}} {
+		_, err := CompareStepTimes(model, model, []GridPoint{pt}, 0.1)
+		assert.Error(t, err, "grid point %+v", pt)
+	}
+}
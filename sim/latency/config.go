@@ -469,6 +469,17 @@ func ValidateRooflineConfig(mc sim.ModelConfig, hc sim.HardwareCalib) error {
 		}
 	}
 
+	// KVBytesPerParam is optional (0 = not set, fall back to BytesPerParam).
+	if mc.KVBytesPerParam != 0 && invalidPositiveFloat(mc.KVBytesPerParam) {
+		problems = append(problems, fmt.Sprintf(
+			"ModelConfig.KVBytesPerParam must be positive when set, got %v", mc.KVBytesPerParam))
+	}
+	if !sim.IsValidComputeDtype(mc.ComputeDtype) {
+		problems = append(problems, fmt.Sprintf(
+			"ModelConfig.ComputeDtype %q is not recognized (valid: %s)",
+			mc.ComputeDtype, strings.Join(sim.ValidComputeDtypeNames(), ", ")))
+	}
+
 	if len(problems) > 0 {
 		return fmt.Errorf("invalid roofline config: %s", strings.Join(problems, "; "))
 	}
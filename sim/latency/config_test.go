@@ -490,7 +490,7 @@ func TestNewLatencyModel_RooflineZeroNumHeads_ReturnsError(t *testing.T) {
 	hw := sim.NewModelHardwareConfig(
 		sim.ModelConfig{NumHeads: 0, NumLayers: 32, HiddenDim: 4096},
 		sim.HardwareCalib{TFlopsPeak: 1000, BwPeakTBs: 3.35, MfuPrefill: 0.5, MfuDecode: 0.3, MemoryGiB: 80.0},
-		"", "", 1, 1, false, "", "roofline", 0,
+		"", "", 1, 1, false, "", "roofline", 0, "",
 	)
 
 	// WHEN NewLatencyModel is called (roofline validation happens here)
@@ -511,7 +511,7 @@ func TestNewLatencyModel_RooflineZeroTP_ReturnsError(t *testing.T) {
 	hw := sim.NewModelHardwareConfig(
 		sim.ModelConfig{NumHeads: 32, NumLayers: 32, HiddenDim: 4096},
 		sim.HardwareCalib{TFlopsPeak: 1000, BwPeakTBs: 3.35, MfuPrefill: 0.5, MfuDecode: 0.3, MemoryGiB: 80.0},
-		"", "", 0, 1, false, "", "roofline", 0,
+		"", "", 0, 1, false, "", "roofline", 0, "",
 	)
 
 	// WHEN NewLatencyModel is called (roofline validation happens here)
@@ -700,6 +700,27 @@ func TestValidateRooflineConfig_NegativeWeightBytesPerParam_ReturnsError(t *test
 	}
 }
 
+func TestValidateRooflineConfig_InvalidDtypeFields_ReturnsError(t *testing.T) {
+	hc := sim.HardwareCalib{TFlopsPeak: 1000, BwPeakTBs: 3.35, MfuPrefill: 0.5, MfuDecode: 0.3}
+	tests := []struct {
+		name  string
+		mc    sim.ModelConfig
+		field string
+	}{
+		{"unknown compute dtype", sim.ModelConfig{NumHeads: 32, NumLayers: 32, HiddenDim: 4096, BytesPerParam: 2, ComputeDtype: "fp4"}, "ComputeDtype"},
+		{"negative KV bytes", sim.ModelConfig{NumHeads: 32, NumLayers: 32, HiddenDim: 4096, BytesPerParam: 2, KVBytesPerParam: -1}, "KVBytesPerParam"},
+		{"NaN KV bytes", sim.ModelConfig{NumHeads: 32, NumLayers: 32, HiddenDim: 4096, BytesPerParam: 2, KVBytesPerParam: math.NaN()}, "KVBytesPerParam"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := latency.ValidateRooflineConfig(tc.mc, hc)
+			if err == nil || !strings.Contains(err.Error(), tc.field) {
+				t.Errorf("ValidateRooflineConfig() = %v, want error mentioning %s", err, tc.field)
+			}
+		})
+	}
+}
+
 func TestValidateRooflineConfig_NaNWeightBytesPerParam_ReturnsError(t *testing.T) {
 	mc := sim.ModelConfig{NumHeads: 32, NumLayers: 32, HiddenDim: 4096, BytesPerParam: 2, WeightBytesPerParam: math.NaN()}
 	hc := sim.HardwareCalib{TFlopsPeak: 1000, BwPeakTBs: 3.35, MfuPrefill: 0.5, MfuDecode: 0.3}
@@ -0,0 +1,152 @@
+package latency
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// FleetGPU is one GPU model's entry in a fleet inventory: the datasheet
+// figures an operator keeps for capacity planning. Zero optional fields fall
+// back to the hardware config entry of the same name.
+type FleetGPU struct {
+	Name          string  `json:"name"`
+	PeakTFlops    float64 `json:"peak_tflops"`               // dense BF16/FP16 peak, required
+	PeakTFlopsFP8 float64 `json:"peak_tflops_fp8,omitempty"` // optional
+	BandwidthTBs  float64 `json:"bandwidth_tbs"`             // HBM bandwidth, required
+	PowerWatts    float64 `json:"power_watts"`               // board power (TDP), required
+	MemoryGiB     float64 `json:"memory_gib,omitempty"`      // optional
+	MfuPrefill    float64 `json:"mfu_prefill,omitempty"`     // optional, (0, 1]
+	MfuDecode     float64 `json:"mfu_decode,omitempty"`      // optional, (0, 1]
+}
+
+// FleetInventory is a JSON fleet inventory: {"gpus": [FleetGPU, ...]}.
+type FleetInventory struct {
+	GPUs []FleetGPU `json:"gpus"`
+}
+
+// LoadFleetInventory reads and validates a fleet inventory file. Unknown
+// fields are rejected (R10), as are duplicate names and any required value
+// that is not positive and finite.
+func LoadFleetInventory(path string) (*FleetInventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fleet inventory %q: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var inv FleetInventory
+	if err := dec.Decode(&inv); err != nil {
+		return nil, fmt.Errorf("parse fleet inventory %q: %w", path, err)
+	}
+	if err := inv.Validate(); err != nil {
+		return nil, fmt.Errorf("fleet inventory %q: %w", path, err)
+	}
+	return &inv, nil
+}
+
+// Validate checks every entry: a non-empty unique name, positive finite peak
+// TFLOPs, bandwidth and power, and positive finite optional values when set
+// (MFUs at most 1).
+func (inv *FleetInventory) Validate() error {
+	if len(inv.GPUs) == 0 {
+		return fmt.Errorf("no GPUs listed")
+	}
+	seen := make(map[string]bool, len(inv.GPUs))
+	for i, g := range inv.GPUs {
+		if g.Name == "" {
+			return fmt.Errorf("gpus[%d]: name is required", i)
+		}
+		if seen[g.Name] {
+			return fmt.Errorf("gpus[%d]: duplicate GPU %q", i, g.Name)
+		}
+		seen[g.Name] = true
+		required := []struct {
+			field string
+			v     float64
+		}{
+			{"peak_tflops", g.PeakTFlops},
+			{"bandwidth_tbs", g.BandwidthTBs},
+			{"power_watts", g.PowerWatts},
+		}
+		for _, f := range required {
+			if !isPositiveFinite(f.v) {
+				return fmt.Errorf("gpus[%d] (%s): %s must be a positive finite number, got %v", i, g.Name, f.field, f.v)
+			}
+		}
+		optional := []struct {
+			field string
+			v     float64
+			max   float64
+		}{
+			{"peak_tflops_fp8", g.PeakTFlopsFP8, math.Inf(1)},
+			{"memory_gib", g.MemoryGiB, math.Inf(1)},
+			{"mfu_prefill", g.MfuPrefill, 1},
+			{"mfu_decode", g.MfuDecode, 1},
+		}
+		for _, f := range optional {
+			if f.v != 0 && (!isPositiveFinite(f.v) || f.v > f.max) {
+				return fmt.Errorf("gpus[%d] (%s): %s must be a positive finite number no greater than %v when set, got %v",
+					i, g.Name, f.field, f.max, f.v)
+			}
+		}
+	}
+	return nil
+}
+
+// GPU returns the entry named name, or an error listing the available GPUs.
+func (inv *FleetInventory) GPU(name string) (FleetGPU, error) {
+	for _, g := range inv.GPUs {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	available := make([]string, 0, len(inv.GPUs))
+	for _, g := range inv.GPUs {
+		available = append(available, g.Name)
+	}
+	sort.Strings(available)
+	return FleetGPU{}, fmt.Errorf("GPU %q not found in fleet inventory (available: %v)", name, available)
+}
+
+// HardwareCalib returns the calibration for GPU name: the inventory's peak
+// TFLOPs and bandwidth, with optional fields the inventory leaves unset taken
+// from base (normally the hardware config entry). The MFUs are
+// calibration, not datasheet figures, so an error is returned when neither
+// the inventory nor base provides them.
+func (inv *FleetInventory) HardwareCalib(name string, base sim.HardwareCalib) (sim.HardwareCalib, error) {
+	g, err := inv.GPU(name)
+	if err != nil {
+		return sim.HardwareCalib{}, err
+	}
+	hc := base
+	hc.TFlopsPeak = g.PeakTFlops
+	hc.BwPeakTBs = g.BandwidthTBs
+	if g.PeakTFlopsFP8 != 0 {
+		hc.TFlopsFP8 = g.PeakTFlopsFP8
+	}
+	if g.MemoryGiB != 0 {
+		hc.MemoryGiB = g.MemoryGiB
+	}
+	if g.MfuPrefill != 0 {
+		hc.MfuPrefill = g.MfuPrefill
+	}
+	if g.MfuDecode != 0 {
+		hc.MfuDecode = g.MfuDecode
+	}
+	if hc.MfuPrefill <= 0 || hc.MfuDecode <= 0 {
+		return sim.HardwareCalib{}, fmt.Errorf(
+			"GPU %q: fleet inventory sets no mfu_prefill/mfu_decode and the hardware config has none to fall back on", name)
+	}
+	return hc, nil
+}
+
+// isPositiveFinite reports whether v is > 0 and neither NaN nor ±Inf.
+func isPositiveFinite(v float64) bool {
+	return v > 0 && !math.IsInf(v, 0) && !math.IsNaN(v)
+}
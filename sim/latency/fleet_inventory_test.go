@@ -0,0 +1,84 @@
+package latency_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/inference-sim/inference-sim/sim/latency"
+)
+
+func TestFleetInventory_NamedGPU_OverridesHardwareConfigPeaks(t *testing.T) {
+	// GIVEN the fixture inventory and a hardware config entry carrying MFUs
+	inv, err := latency.LoadFleetInventory("testdata/fleet_inventory.json")
+	if err != nil {
+		t.Fatalf("LoadFleetInventory: %v", err)
+	}
+	base := sim.HardwareCalib{TFlopsPeak: 1, BwPeakTBs: 1, MfuPrefill: 0.45, MfuDecode: 0.3, MemoryGiB: 1}
+
+	// WHEN resolving H100
+	hc, err := inv.HardwareCalib("H100", base)
+	if err != nil {
+		t.Fatalf("HardwareCalib: %v", err)
+	}
+
+	// THEN peaks and memory come from the inventory, MFUs from the hardware config
+	want := sim.HardwareCalib{TFlopsPeak: 989.5, TFlopsFP8: 1979, BwPeakTBs: 3.35, MfuPrefill: 0.45, MfuDecode: 0.3, MemoryGiB: 80}
+	if hc != want {
+		t.Errorf("HardwareCalib(H100) = %+v, want %+v", hc, want)
+	}
+	g, err := inv.GPU("H100")
+	if err != nil || g.PowerWatts != 700 {
+		t.Errorf("GPU(H100).PowerWatts = %v (err %v), want 700", g.PowerWatts, err)
+	}
+
+	// AND inventory MFUs stand alone when the hardware config has no entry
+	hc, err = inv.HardwareCalib("L40S", sim.HardwareCalib{})
+	if err != nil {
+		t.Fatalf("HardwareCalib(L40S): %v", err)
+	}
+	if hc.MfuPrefill != 0.5 || hc.MfuDecode != 0.2 || hc.TFlopsPeak != 362 {
+		t.Errorf("HardwareCalib(L40S) = %+v, want inventory peaks and MFUs", hc)
+	}
+}
+
+func TestFleetInventory_MissingGPU_ClearError(t *testing.T) {
+	inv, err := latency.LoadFleetInventory("testdata/fleet_inventory.json")
+	if err != nil {
+		t.Fatalf("LoadFleetInventory: %v", err)
+	}
+	_, err = inv.HardwareCalib("A100-80", sim.HardwareCalib{MfuPrefill: 0.5, MfuDecode: 0.3})
+	if err == nil {
+		t.Fatal("expected error for GPU missing from the inventory")
+	}
+	for _, want := range []string{`"A100-80"`, "fleet inventory", "H100", "L40S"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
+
+func TestLoadFleetInventory_InvalidValues_Rejected(t *testing.T) {
+	cases := map[string]string{
+		"zero peak":       `{"gpus":[{"name":"X","peak_tflops":0,"bandwidth_tbs":1,"power_watts":100}]}`,
+		"negative bw":     `{"gpus":[{"name":"X","peak_tflops":1,"bandwidth_tbs":-1,"power_watts":100}]}`,
+		"missing power":   `{"gpus":[{"name":"X","peak_tflops":1,"bandwidth_tbs":1}]}`,
+		"mfu above 1":     `{"gpus":[{"name":"X","peak_tflops":1,"bandwidth_tbs":1,"power_watts":100,"mfu_decode":1.5}]}`,
+		"duplicate name":  `{"gpus":[{"name":"X","peak_tflops":1,"bandwidth_tbs":1,"power_watts":1},{"name":"X","peak_tflops":1,"bandwidth_tbs":1,"power_watts":1}]}`,
+		"unknown field":   `{"gpus":[{"name":"X","peak_tflops":1,"bandwidth_tbs":1,"power_watts":1,"tdp":1}]}`,
+		"empty inventory": `{"gpus":[]}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inv.json")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if _, err := latency.LoadFleetInventory(path); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
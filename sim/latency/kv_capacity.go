@@ -56,10 +56,11 @@ var swiGLUActivations = map[string]bool{
 // model config and tensor parallelism degree. This is used for both KV cache
 // capacity sizing and PD transfer duration estimation.
 //
-// The formula is: NumLayers × 2 (K+V) × headDim × numKVHeads × BytesPerParam / TP
+// The formula is: NumLayers × 2 (K+V) × headDim × numKVHeads × EffectiveKVBytesPerParam / TP
 //
-// Uses BytesPerParam (compute/activation dtype), not WeightBytesPerParam, since
-// KV cache is stored at compute precision regardless of weight quantization.
+// Uses EffectiveKVBytesPerParam (the KV cache dtype, defaulting to the compute
+// dtype BytesPerParam), not WeightBytesPerParam, since KV cache precision is
+// independent of weight quantization.
 //
 // Returns a float64 so callers can choose when to truncate. CalculateKVBlocks
 // multiplies by blockSize before truncating (avoids loss when the per-token
@@ -91,6 +92,9 @@ func KVBytesPerToken(mc sim.ModelConfig, tp int) (float64, error) {
 	if mc.BytesPerParam <= 0 || math.IsNaN(mc.BytesPerParam) || math.IsInf(mc.BytesPerParam, 0) {
 		return 0, fmt.Errorf("KVBytesPerToken: precision (BytesPerParam) must be a valid positive number, got %v", mc.BytesPerParam)
 	}
+	if mc.KVBytesPerParam != 0 && (mc.KVBytesPerParam < 0 || math.IsNaN(mc.KVBytesPerParam) || math.IsInf(mc.KVBytesPerParam, 0)) {
+		return 0, fmt.Errorf("KVBytesPerToken: KV cache precision (KVBytesPerParam) must be positive when set, got %v", mc.KVBytesPerParam)
+	}
 	if mc.HiddenDim%mc.NumHeads != 0 {
 		return 0, fmt.Errorf("KVBytesPerToken: hidden_dim (%d) must be evenly divisible by num_attention_heads (%d)", mc.HiddenDim, mc.NumHeads)
 	}
@@ -108,7 +112,7 @@ func KVBytesPerToken(mc sim.ModelConfig, tp int) (float64, error) {
 	}
 
 	headDim := mc.HiddenDim / mc.NumHeads
-	perTokenKVBytesF := float64(mc.NumLayers) * 2.0 * float64(headDim) * float64(numKVHeads) * mc.BytesPerParam
+	perTokenKVBytesF := float64(mc.NumLayers) * 2.0 * float64(headDim) * float64(numKVHeads) * mc.EffectiveKVBytesPerParam()
 	perTokenKVBytesPerGPUF := perTokenKVBytesF / float64(tp)
 
 	if perTokenKVBytesPerGPUF <= 0 {
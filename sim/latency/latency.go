@@ -1,7 +1,8 @@
 // Package latency provides latency model implementations for the BLIS simulator.
 // The LatencyModel interface is defined in sim/ (parent package).
-// This package provides RooflineLatencyModel (analytical FLOPs/bandwidth) and
-// TrainedPhysicsModel (physics-informed basis functions with architecture-aware MoE scaling).
+// This package provides RooflineLatencyModel (analytical FLOPs/bandwidth),
+// TrainedPhysicsModel (physics-informed basis functions with architecture-aware MoE scaling),
+// and TableLatencyModel (interpolation over measured step times).
 package latency
 
 import (
@@ -32,9 +33,15 @@ func clampToInt64(v float64) int64 {
 // the same options, so an adapter effect applies identically (R23).
 type Option func(*latencyOptions)
 
-// latencyOptions accumulates the applied Options. Zero value ⇒ no adapter effect.
+// latencyOptions accumulates the applied Options. Zero value ⇒ no adapter effect
+// and no scheduling overhead.
 type latencyOptions struct {
-	adapterCost sim.AdapterCost
+	adapterCost                sim.AdapterCost
+	schedulingOverheadUsPerSeq float64
+	schedulingContentionUsSq   float64
+	blockSizeTokens            int64
+	rooflineStats              *sim.RooflineStepStats
+	minStepTimeTicks           int64
 }
 
 // WithAdapterCost supplies the LoRA per-step compute-overhead accessor. A nil
@@ -45,6 +52,65 @@ func WithAdapterCost(ac sim.AdapterCost) Option {
 	return func(o *latencyOptions) { o.adapterCost = ac }
 }
 
+// WithSchedulingOverhead supplies the per-step CPU scheduling cost in
+// microseconds per sequence in the batch (building block tables, sampling
+// metadata, ...). 0 (or no option) leaves StepTime unchanged (INV-6).
+func WithSchedulingOverhead(usPerSeq float64) Option {
+	return func(o *latencyOptions) { o.schedulingOverheadUsPerSeq = usPerSeq }
+}
+
+// WithSchedulingContention supplies a scheduling cost in microseconds per
+// sequence squared, modeling host-side contention that grows with batch size
+// (sequences sharing the scheduler's locks and allocator). Unlike the linear
+// WithSchedulingOverhead term it eventually outgrows the fixed per-step cost,
+// so throughput peaks at an interior batch size. 0 (or no option) leaves
+// StepTime unchanged (INV-6).
+func WithSchedulingContention(usPerSeqSq float64) Option {
+	return func(o *latencyOptions) { o.schedulingContentionUsSq = usPerSeqSq }
+}
+
+// WithBlockSize supplies the KV block size so the roofline backend charges
+// paged-attention block-table reads, which grow with context length. 0 (or no
+// option) leaves them out. Other backends ignore it: their step times are
+// measured or fitted and already include the block-table walk.
+func WithBlockSize(blockSizeTokens int64) Option {
+	return func(o *latencyOptions) { o.blockSizeTokens = blockSizeTokens }
+}
+
+// WithRooflineStats supplies the accumulator the roofline backend records each
+// forward pass's FLOPs and memory bytes into. nil (or no option) records
+// nothing. Observation only: step times are unchanged. Other backends ignore
+// it, as they do not compute FLOPs or bytes.
+func WithRooflineStats(stats *sim.RooflineStepStats) Option {
+	return func(o *latencyOptions) { o.rooflineStats = stats }
+}
+
+// WithMinStepTime supplies a floor on the forward-pass time of every step,
+// modeling the fixed kernel-launch overhead a tiny batch still pays. 0 (or no
+// option) leaves StepTime unchanged (INV-6).
+func WithMinStepTime(ticks int64) Option {
+	return func(o *latencyOptions) { o.minStepTimeTicks = ticks }
+}
+
+// applyStepTimeFloor raises a forward-pass time to floorTicks. It is the single
+// shared application point so every backend behaves identically (R23), and it
+// runs after applyAdapterOverhead and before applySchedulingOverhead: the floor
+// bounds GPU time, and host-side scheduling adds to it.
+func applyStepTimeFloor(base, floorTicks int64) int64 {
+	return max(base, floorTicks)
+}
+
+// applySchedulingOverhead adds usPerSeq * len(batch) + usPerSeqSq * len(batch)²
+// to a step time. It is the single shared application point so both backends
+// behave identically (R23), and it runs after applyAdapterOverhead: scheduling
+// is host-side work that the adapter compute factor does not scale.
+func applySchedulingOverhead(base int64, batch []*sim.Request, usPerSeq, usPerSeqSq float64) int64 {
+	if usPerSeq <= 0 && usPerSeqSq <= 0 {
+		return base
+	}
+	return clampToInt64(float64(base) + math.Round(sim.SchedulingOverheadUs(len(batch), usPerSeq, usPerSeqSq)))
+}
+
 // applyAdapterOverhead multiplies a base step time by the batch's LoRA
 // compute-overhead factor (>= 1.0) from the accessor. It is the single shared
 // application point so both backends behave identically (R23). A nil accessor —
@@ -80,12 +146,28 @@ type RooflineLatencyModel struct {
 	// when the LoRA subsystem is inert, in which case StepTime is byte-identical to
 	// a pre-feature build (INV-6). Set via WithAdapterCost at construction.
 	adapterCost sim.AdapterCost
+	// schedulingOverheadUsPerSeq is the per-sequence scheduling cost added to
+	// every step (0 = disabled). Set via WithSchedulingOverhead at construction.
+	schedulingOverheadUsPerSeq float64
+	// schedulingContentionUsSq is the per-sequence² contention cost added to
+	// every step (0 = disabled). Set via WithSchedulingContention at construction.
+	schedulingContentionUsSq float64
+	// blockSizeTokens enables block-table read traffic (0 = not modeled). Set
+	// via WithBlockSize at construction.
+	blockSizeTokens int64
+	// rooflineStats receives each step's FLOPs and bytes (nil = not recorded).
+	// Set via WithRooflineStats at construction.
+	rooflineStats *sim.RooflineStepStats
+	// minStepTimeTicks floors the forward-pass time (0 = no floor). Set via
+	// WithMinStepTime at construction.
+	minStepTimeTicks int64
 }
 
 func (m *RooflineLatencyModel) StepTime(batch []*sim.Request) int64 {
 	stepConfig := StepConfig{
 		PrefillRequests: make([]PrefillRequestConfig, 0, len(batch)),
 		DecodeRequests:  make([]DecodeRequestConfig, 0, len(batch)),
+		BlockSizeTokens: m.blockSizeTokens,
 	}
 	for _, req := range batch {
 		if req.ProgressIndex < req.InputLen() {
@@ -94,13 +176,21 @@ func (m *RooflineLatencyModel) StepTime(batch []*sim.Request) int64 {
 				NumNewPrefillTokens: req.NumNewTokens,
 			})
 		} else if len(req.OutputTokens) > 0 {
-			stepConfig.DecodeRequests = append(stepConfig.DecodeRequests, DecodeRequestConfig{
-				ProgressIndex:      req.ProgressIndex,
-				NumNewDecodeTokens: req.NumNewTokens,
-			})
+			// Each parallel sample decodes its own sequence over the shared prompt.
+			for range req.Samples() {
+				stepConfig.DecodeRequests = append(stepConfig.DecodeRequests, DecodeRequestConfig{
+					ProgressIndex:      req.ProgressIndex,
+					NumNewDecodeTokens: req.NumNewTokens,
+				})
+			}
 		}
 	}
-	return applyAdapterOverhead(max(1, rooflineStepTime(m.modelConfig, m.hwConfig, stepConfig, m.tp)), batch, m.adapterCost)
+	step := rooflineStepBreakdown(m.modelConfig, m.hwConfig, stepConfig, m.tp)
+	if m.rooflineStats != nil && step.flops+step.bytes > 0 {
+		m.rooflineStats.Record(step.flops, step.bytes, step.seconds(), step.peakFlops, step.computeS >= step.memoryS)
+	}
+	stepTime := applyStepTimeFloor(applyAdapterOverhead(max(1, step.micros()), batch, m.adapterCost), m.minStepTimeTicks)
+	return applySchedulingOverhead(stepTime, batch, m.schedulingOverheadUsPerSeq, m.schedulingContentionUsSq)
 }
 
 func (m *RooflineLatencyModel) QueueingTime(req *sim.Request) int64 {
@@ -134,12 +224,14 @@ func validateCoeffs(name string, coeffs []float64) error {
 
 // NewLatencyModel creates the appropriate LatencyModel based on config.
 // Dispatches on hw.Backend: "" or "roofline" → RooflineLatencyModel,
-// "trained-physics" → TrainedPhysicsModel.
+// "trained-physics" → TrainedPhysicsModel, "table" → TableLatencyModel (loads
+// hw.StepTimeTable).
 // Returns error if coefficient slices are too short, contain NaN/Inf, or config validation fails.
 //
 // Options inject optional dependencies; the same options are applied to whichever
-// backend is selected, so an adapter-overhead accessor (WithAdapterCost) affects
-// both identically (R23). No options ⇒ pre-feature behavior (INV-6).
+// backend is selected, so an adapter-overhead accessor (WithAdapterCost) or a
+// scheduling overhead (WithSchedulingOverhead) affects both identically (R23).
+// No options ⇒ pre-feature behavior (INV-6).
 func NewLatencyModel(coeffs sim.LatencyCoeffs, hw sim.ModelHardwareConfig, opts ...Option) (sim.LatencyModel, error) {
 	var o latencyOptions
 	for _, opt := range opts {
@@ -152,6 +244,18 @@ func NewLatencyModel(coeffs sim.LatencyCoeffs, hw sim.ModelHardwareConfig, opts
 	if err := validateCoeffs("AlphaCoeffs", coeffs.AlphaCoeffs); err != nil {
 		return nil, err
 	}
+	if o.blockSizeTokens < 0 {
+		return nil, fmt.Errorf("latency model: block size must be >= 0, got %d", o.blockSizeTokens)
+	}
+	if o.schedulingOverheadUsPerSeq < 0 || math.IsNaN(o.schedulingOverheadUsPerSeq) || math.IsInf(o.schedulingOverheadUsPerSeq, 0) {
+		return nil, fmt.Errorf("latency model: scheduling overhead must be a finite value >= 0, got %v", o.schedulingOverheadUsPerSeq)
+	}
+	if o.schedulingContentionUsSq < 0 || math.IsNaN(o.schedulingContentionUsSq) || math.IsInf(o.schedulingContentionUsSq, 0) {
+		return nil, fmt.Errorf("latency model: scheduling contention must be a finite value >= 0, got %v", o.schedulingContentionUsSq)
+	}
+	if o.minStepTimeTicks < 0 {
+		return nil, fmt.Errorf("latency model: minimum step time must be >= 0, got %d", o.minStepTimeTicks)
+	}
 	switch hw.Backend {
 	case "", "roofline":
 		if hw.TP <= 0 {
@@ -161,11 +265,16 @@ func NewLatencyModel(coeffs sim.LatencyCoeffs, hw sim.ModelHardwareConfig, opts
 			return nil, fmt.Errorf("latency model: %w", err)
 		}
 		return &RooflineLatencyModel{
-			modelConfig: hw.ModelConfig,
-			hwConfig:    hw.HWConfig,
-			tp:          hw.TP,
-			alphaCoeffs: coeffs.AlphaCoeffs,
-			adapterCost: o.adapterCost,
+			modelConfig:                hw.ModelConfig,
+			hwConfig:                   hw.HWConfig,
+			tp:                         hw.TP,
+			alphaCoeffs:                coeffs.AlphaCoeffs,
+			adapterCost:                o.adapterCost,
+			schedulingOverheadUsPerSeq: o.schedulingOverheadUsPerSeq,
+			schedulingContentionUsSq:   o.schedulingContentionUsSq,
+			blockSizeTokens:            o.blockSizeTokens,
+			rooflineStats:              o.rooflineStats,
+			minStepTimeTicks:           o.minStepTimeTicks,
 		}, nil
 	case "trained-physics":
 		// TrainedPhysicsModel: physics-informed roofline with architecture-aware MoE overhead.
@@ -176,7 +285,26 @@ func NewLatencyModel(coeffs sim.LatencyCoeffs, hw sim.ModelHardwareConfig, opts
 			return nil, err
 		}
 		model.adapterCost = o.adapterCost
+		model.schedulingOverheadUsPerSeq = o.schedulingOverheadUsPerSeq
+		model.schedulingContentionUsSq = o.schedulingContentionUsSq
+		model.minStepTimeTicks = o.minStepTimeTicks
 		return model, nil
+	case "table":
+		if hw.StepTimeTable == "" {
+			return nil, fmt.Errorf("latency model: table backend requires a step-time table path")
+		}
+		table, err := LoadStepTimeTable(hw.StepTimeTable)
+		if err != nil {
+			return nil, fmt.Errorf("latency model: %w", err)
+		}
+		return &TableLatencyModel{
+			table:                      table,
+			alphaCoeffs:                coeffs.AlphaCoeffs,
+			adapterCost:                o.adapterCost,
+			schedulingOverheadUsPerSeq: o.schedulingOverheadUsPerSeq,
+			schedulingContentionUsSq:   o.schedulingContentionUsSq,
+			minStepTimeTicks:           o.minStepTimeTicks,
+		}, nil
 	default:
 		return nil, fmt.Errorf("latency model: unknown backend %q; valid options: %s",
 			hw.Backend, strings.Join(sim.ValidLatencyBackendNames(), ", "))
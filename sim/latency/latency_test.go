@@ -107,7 +107,7 @@ func TestRooflineLatencyModel_QueueingTime_Positive(t *testing.T) {
 func TestNewLatencyModel_RooflineMode(t *testing.T) {
 	cfg := sim.SimConfig{
 		LatencyCoeffs:       sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}),
-		ModelHardwareConfig: sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, ""),
 	}
 
 	model, err := NewLatencyModel(cfg.LatencyCoeffs, cfg.ModelHardwareConfig)
@@ -134,7 +134,7 @@ func TestNewLatencyModel_RooflineMode(t *testing.T) {
 // Library consumers who omit Backend get the same default as CLI users.
 func TestNewLatencyModel_EmptyBackendDefaultsToRoofline(t *testing.T) {
 	coeffs := sim.NewLatencyCoeffs(nil, []float64{100, 1, 100})
-	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "", 0)
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "", 0, "")
 
 	model, err := NewLatencyModel(coeffs, hw)
 	if err != nil {
@@ -159,7 +159,7 @@ func TestNewLatencyModel_EmptyBackendDefaultsToRoofline(t *testing.T) {
 func TestNewLatencyModel_InvalidRoofline(t *testing.T) {
 	cfg := sim.SimConfig{
 		LatencyCoeffs:       sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}),
-		ModelHardwareConfig: sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "roofline", 0, ""),
 	}
 
 	_, err := NewLatencyModel(cfg.LatencyCoeffs, cfg.ModelHardwareConfig)
@@ -181,7 +181,7 @@ func TestNewLatencyModel_ShortAlphaCoeffs(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			coeffs := sim.NewLatencyCoeffs(nil, tc.alpha)
-			hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", tc.backend, 0)
+			hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", tc.backend, 0, "")
 			_, err := NewLatencyModel(coeffs, hw)
 			if err == nil {
 				t.Fatal("expected error for short AlphaCoeffs, got nil")
@@ -193,7 +193,7 @@ func TestNewLatencyModel_ShortAlphaCoeffs(t *testing.T) {
 // TestNewLatencyModel_NaNAlphaCoeffs_ReturnsError verifies BC-4: NaN in alpha rejected.
 func TestNewLatencyModel_NaNAlphaCoeffs_ReturnsError(t *testing.T) {
 	coeffs := sim.NewLatencyCoeffs(nil, []float64{math.NaN(), 1.0, 100.0})
-	_, err := NewLatencyModel(coeffs, sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0))
+	_, err := NewLatencyModel(coeffs, sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, ""))
 	if err == nil {
 		t.Fatal("expected error for NaN AlphaCoeffs, got nil")
 	}
@@ -202,7 +202,7 @@ func TestNewLatencyModel_NaNAlphaCoeffs_ReturnsError(t *testing.T) {
 // TestNewLatencyModel_UnknownBackend_ReturnsError verifies BC-6: unknown backend → error.
 func TestNewLatencyModel_UnknownBackend_ReturnsError(t *testing.T) {
 	coeffs := sim.NewLatencyCoeffs([]float64{1000, 10, 2}, []float64{500, 1, 100})
-	hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "nonexistent", 0)
+	hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "nonexistent", 0, "")
 	_, err := NewLatencyModel(coeffs, hw)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "nonexistent")
@@ -223,7 +223,7 @@ func TestNewLatencyModel_NegativeCoefficients_ReturnsError(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			coeffs := sim.NewLatencyCoeffs(nil, tc.alpha)
-			hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0)
+			hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
 			_, err := NewLatencyModel(coeffs, hw)
 			if err == nil {
 				t.Fatal("expected error for negative coefficient")
@@ -358,7 +358,7 @@ func TestNewLatencyModel_RemovedBackendError(t *testing.T) {
 					TFlopsPeak: 989.0,
 					BwPeakTBs:  3.35,
 				},
-				"", "", 1, 1, false, "", tt.backend, 0,
+				"", "", 1, 1, false, "", tt.backend, 0, "",
 			)
 
 			// WHEN attempting to construct the model
@@ -411,7 +411,7 @@ func TestNewLatencyModel_RemainingBackendsWork(t *testing.T) {
 					MfuDecode:  0.30,
 					MemoryGiB:  80.0,
 				},
-				"", "", 1, 1, false, "", backend, 0,
+				"", "", 1, 1, false, "", backend, 0, "",
 			)
 			coeffs := sim.NewLatencyCoeffs(
 				[]float64{1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0},
@@ -451,7 +451,7 @@ func TestNewLatencyModel_RemainingBackendsWork(t *testing.T) {
 // THEN no deprecation warning MUST be emitted.
 func TestNewLatencyModel_Roofline_NoDeprecationWarning(t *testing.T) {
 	coeffs := sim.NewLatencyCoeffs(nil, []float64{1.0, 2.0, 3.0})
-	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0)
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
 
 	var logBuf bytes.Buffer
 	oldOut := logrus.StandardLogger().Out
@@ -479,7 +479,7 @@ func TestNewLatencyModel_Roofline_NoDeprecationWarning(t *testing.T) {
 // THEN no deprecation warning MUST be emitted.
 func TestNewLatencyModel_TrainedPhysics_NoDeprecationWarning(t *testing.T) {
 	coeffs := sim.NewLatencyCoeffs([]float64{1, 2, 3, 4, 5, 6, 7, 8}, []float64{1.0, 2.0, 3.0})
-	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "trained-physics", 0)
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "trained-physics", 0, "")
 
 	var logBuf bytes.Buffer
 	oldOut := logrus.StandardLogger().Out
@@ -500,3 +500,130 @@ func TestNewLatencyModel_TrainedPhysics_NoDeprecationWarning(t *testing.T) {
 		t.Errorf("expected no deprecation warning for trained-physics, but got: %s", logOutput)
 	}
 }
+
+// TestRooflineLatencyModel_RooflineStats_DecodeMemoryBoundPrefillComputeBound
+// verifies the per-step accounting: decode-heavy steps are memory-bound,
+// large-prefill steps are compute-bound, and recording leaves step time unchanged.
+func TestRooflineLatencyModel_RooflineStats_DecodeMemoryBoundPrefillComputeBound(t *testing.T) {
+	newModel := func(stats *sim.RooflineStepStats) *RooflineLatencyModel {
+		return &RooflineLatencyModel{
+			modelConfig:   testModelConfig(),
+			hwConfig:      testHardwareCalib(),
+			tp:            1,
+			alphaCoeffs:   []float64{100, 1, 100},
+			rooflineStats: stats,
+		}
+	}
+	decodeBatch := func(step int) []*sim.Request {
+		batch := make([]*sim.Request, 16)
+		for i := range batch {
+			batch[i] = &sim.Request{
+				InputTokens:   make([]sim.TokenID, 512),
+				OutputTokens:  make([]sim.TokenID, 256),
+				ProgressIndex: int64(512 + step),
+				NumNewTokens:  1,
+			}
+		}
+		return batch
+	}
+	prefillBatch := func(step int) []*sim.Request {
+		return []*sim.Request{{
+			InputTokens:   make([]sim.TokenID, 32768),
+			ProgressIndex: int64(step * 4096),
+			NumNewTokens:  4096,
+		}}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		batch        func(step int) []*sim.Request
+		computeBound bool
+	}{
+		{"decode-heavy", decodeBatch, false},
+		{"large-prefill", prefillBatch, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// GIVEN a roofline model recording into stats, and one without
+			var stats sim.RooflineStepStats
+			recording, plain := newModel(&stats), newModel(nil)
+
+			// WHEN eight steps run
+			for step := 0; step < 8; step++ {
+				got, want := recording.StepTime(tc.batch(step)), plain.StepTime(tc.batch(step))
+				if got != want {
+					t.Fatalf("step %d: StepTime with stats = %d, without = %d; accounting must not change step time", step, got, want)
+				}
+			}
+
+			// THEN every step is recorded with positive work
+			sum := stats.Summary()
+			if sum == nil || sum.Steps != 8 {
+				t.Fatalf("Summary = %+v, want 8 steps", sum)
+			}
+			if sum.TotalFlops <= 0 || sum.TotalBytes <= 0 {
+				t.Errorf("TotalFlops = %v, TotalBytes = %v, want both > 0", sum.TotalFlops, sum.TotalBytes)
+			}
+			// AND the steps fall on the expected side of the roofline
+			if tc.computeBound && sum.ComputeBoundFraction < 0.9 {
+				t.Errorf("ComputeBoundFraction = %v, want >= 0.9 for large prefill", sum.ComputeBoundFraction)
+			}
+			if !tc.computeBound && sum.MemoryBoundFraction < 0.9 {
+				t.Errorf("MemoryBoundFraction = %v, want >= 0.9 for decode", sum.MemoryBoundFraction)
+			}
+		})
+	}
+}
+
+// TestStepTime_ParallelSamples_DecodeCountsEverySample verifies that a decode
+// step of a request with n parallel samples costs as much as decoding n
+// sequences: more than one sample, and no more than n separate requests
+// (which also pay per-sequence overheads). Holds for every backend (R23).
+func TestStepTime_ParallelSamples_DecodeCountsEverySample(t *testing.T) {
+	for _, backend := range []string{"roofline", "trained-physics", "table"} {
+		t.Run(backend, func(t *testing.T) {
+			// GIVEN a model for the backend
+			hw := sim.NewModelHardwareConfig(
+				sim.ModelConfig{
+					NumLayers:       32,
+					NumHeads:        32,
+					NumKVHeads:      8,
+					HiddenDim:       4096,
+					IntermediateDim: 11008,
+					BytesPerParam:   2.0,
+				},
+				sim.HardwareCalib{TFlopsPeak: 989.0, BwPeakTBs: 3.35, MfuPrefill: 0.55, MfuDecode: 0.30, MemoryGiB: 80.0},
+				"", "", 1, 1, false, "", backend, 0, stepTimeTableFixture,
+			)
+			coeffs := sim.NewLatencyCoeffs(
+				[]float64{1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 1.0},
+				[]float64{1.0, 1.0, 1.0},
+			)
+			model, err := NewLatencyModel(coeffs, hw)
+			if err != nil {
+				t.Fatalf("NewLatencyModel(%q): %v", backend, err)
+			}
+			decoding := func(samples int) *sim.Request {
+				return &sim.Request{
+					InputTokens:     make([]sim.TokenID, 1000),
+					OutputTokens:    make([]sim.TokenID, 1500),
+					ProgressIndex:   2000,
+					NumNewTokens:    1,
+					ParallelSamples: samples,
+				}
+			}
+
+			// WHEN one request decodes 4 samples
+			single := model.StepTime([]*sim.Request{decoding(1)})
+			sampled := model.StepTime([]*sim.Request{decoding(4)})
+			separate := model.StepTime([]*sim.Request{decoding(1), decoding(1), decoding(1), decoding(1)})
+
+			// THEN the step costs more than one sample and at most 4 requests
+			if sampled <= single {
+				t.Errorf("StepTime with 4 samples = %d, want > %d (one sample)", sampled, single)
+			}
+			if sampled > separate {
+				t.Errorf("StepTime with 4 samples = %d, want <= %d (4 separate requests)", sampled, separate)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package latency
+
+import (
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/stretchr/testify/require"
+)
+
+// minStepBackends returns one roofline and one trained-physics model built
+// via the production constructor with the given step-time floor.
+func minStepBackends(t *testing.T, floorTicks int64) map[string]sim.LatencyModel {
+	t.Helper()
+	roofHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+	roof, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), roofHW, WithMinStepTime(floorTicks))
+	require.NoError(t, err, "roofline NewLatencyModel")
+
+	tpHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "trained-physics", 0, "")
+	tp, err := NewLatencyModel(*testCoeffs(), tpHW, WithMinStepTime(floorTicks))
+	require.NoError(t, err, "trained-physics NewLatencyModel")
+
+	return map[string]sim.LatencyModel{"roofline": roof, "trained-physics": tp}
+}
+
+// TestStepTime_MinStepTime_FloorsTinyStepsSparesLargeBatches verifies that
+// single-token decode steps never run faster than the floor, that a large
+// prefill batch is unaffected, and that 0 is a no-op (INV-6).
+func TestStepTime_MinStepTime_FloorsTinyStepsSparesLargeBatches(t *testing.T) {
+	const floorTicks = 15000
+	noOpt := backends(t, nil)
+	largeBatch := make([]*sim.Request, 32)
+	for i := range largeBatch {
+		largeBatch[i] = prefillReq(2048, "")
+	}
+	for name, model := range minStepBackends(t, floorTicks) {
+		// GIVEN single-token decode steps at growing context, which the
+		// unfloored model runs in less than the floor
+		for _, ctx := range []int64{16, 256, 1024} {
+			decode := []*sim.Request{{
+				InputTokens:   make([]sim.TokenID, ctx),
+				OutputTokens:  make([]sim.TokenID, 8),
+				ProgressIndex: ctx,
+				NumNewTokens:  1,
+			}}
+			if base := noOpt[name].StepTime(decode); base >= floorTicks {
+				t.Fatalf("%s: unfloored decode step = %d ticks, want below the %d floor for this test", name, base, floorTicks)
+			}
+			// THEN the floored step takes exactly the floor
+			if got := model.StepTime(decode); got != floorTicks {
+				t.Errorf("%s decode step (context %d) = %d, want floor %d", name, ctx, got, floorTicks)
+			}
+		}
+
+		// AND a large batch, already slower than the floor, is unchanged
+		base := noOpt[name].StepTime(largeBatch)
+		if base <= floorTicks {
+			t.Fatalf("%s: large batch step = %d ticks, want above the %d floor for this test", name, base, floorTicks)
+		}
+		if got := model.StepTime(largeBatch); got != base {
+			t.Errorf("%s large batch step = %d, want unfloored %d", name, got, base)
+		}
+
+		// AND a zero floor is byte-identical to no option
+		zero := minStepBackends(t, 0)[name]
+		if got, want := zero.StepTime(largeBatch), base; got != want {
+			t.Errorf("%s zero floor StepTime = %d, want byte-identical %d", name, got, want)
+		}
+	}
+}
+
+func TestNewLatencyModel_NegativeMinStepTime_ReturnsError(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+	_, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), hw, WithMinStepTime(-1))
+	require.Error(t, err)
+}
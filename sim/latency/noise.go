@@ -0,0 +1,65 @@
+package latency
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// noisyLatencyModel multiplies an inner model's step times by 1 + x, where x
+// follows a zero-mean AR(1) process:
+//
+//	x[t] = correlation·x[t−1] + magnitude·√(1 − correlation²)·ε[t],  ε ~ N(0, 1)
+//
+// The stationary standard deviation of x is magnitude whatever the
+// correlation, so correlation only sets how slowly the jitter wanders: 0 is
+// independent per-step noise, values near 1 give long slow/fast stretches.
+// The mean factor is 1, so mean step time is preserved up to the floor at
+// one tick. Queueing and output-processing times pass through unchanged.
+type noisyLatencyModel struct {
+	sim.LatencyModel
+	magnitude   float64
+	correlation float64
+	innovation  float64 // magnitude·√(1 − correlation²)
+	rng         *rand.Rand
+	x           float64
+}
+
+// NewNoisyLatencyModel wraps inner with AR(1) step-time noise drawn from rng.
+// magnitude must be in [0, 0.5], keeping the factor positive in all but
+// extreme draws (those are floored at one tick); correlation in [0, 1).
+// magnitude 0 returns inner itself, so a disabled wrapper is byte-identical
+// (INV-6).
+func NewNoisyLatencyModel(inner sim.LatencyModel, magnitude, correlation float64, rng *rand.Rand) (sim.LatencyModel, error) {
+	if math.IsNaN(magnitude) || magnitude < 0 || magnitude > 0.5 {
+		return nil, fmt.Errorf("latency noise: magnitude must be in [0, 0.5], got %v", magnitude)
+	}
+	if math.IsNaN(correlation) || correlation < 0 || correlation >= 1 {
+		return nil, fmt.Errorf("latency noise: correlation must be in [0, 1), got %v", correlation)
+	}
+	if magnitude == 0 {
+		return inner, nil
+	}
+	if rng == nil {
+		return nil, fmt.Errorf("latency noise: rng must not be nil")
+	}
+	return &noisyLatencyModel{
+		LatencyModel: inner,
+		magnitude:    magnitude,
+		correlation:  correlation,
+		innovation:   magnitude * math.Sqrt(1-correlation*correlation),
+		rng:          rng,
+		x:            magnitude * rng.NormFloat64(), // start in the stationary distribution
+	}, nil
+}
+
+// StepTime returns the inner step time scaled by the current noise factor,
+// then advances the process by one step.
+func (m *noisyLatencyModel) StepTime(batch []*sim.Request) int64 {
+	base := m.LatencyModel.StepTime(batch)
+	t := max(1, clampToInt64(math.Round(float64(base)*(1+m.x))))
+	m.x = m.correlation*m.x + m.innovation*m.rng.NormFloat64()
+	return t
+}
@@ -0,0 +1,96 @@
+package latency
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	_ "github.com/inference-sim/inference-sim/sim/kv"
+	"github.com/stretchr/testify/require"
+)
+
+// noiseITLs decodes one 800-token request with the roofline model, wrapped in
+// AR(1) noise of the given magnitude seeded with seed, and returns its ITLs.
+func noiseITLs(t *testing.T, magnitude float64, seed int64) []int64 {
+	t.Helper()
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+	coeffs := sim.NewLatencyCoeffs(nil, []float64{100, 1, 100})
+	inner, err := NewLatencyModel(coeffs, hw)
+	require.NoError(t, err)
+	model, err := NewNoisyLatencyModel(inner, magnitude, 0.8, rand.New(rand.NewSource(seed)))
+	require.NoError(t, err)
+
+	cfg := sim.SimConfig{
+		Horizon:             math.MaxInt64,
+		Seed:                seed,
+		KVCacheConfig:       sim.NewKVCacheConfig(10000, 16, 0, 0, 0, 0),
+		BatchConfig:         sim.NewBatchConfig(256, 2048, 0),
+		LatencyCoeffs:       coeffs,
+		ModelHardwareConfig: hw,
+	}
+	s, err := sim.NewSimulator(cfg, sim.MustNewKVStoreFromConfig(cfg.KVCacheConfig), model)
+	require.NoError(t, err)
+	s.InjectArrival(&sim.Request{
+		ID:           "req_0",
+		InputTokens:  make([]sim.TokenID, 128),
+		OutputTokens: make([]sim.TokenID, 800),
+		MaxOutputLen: 800,
+		State:        sim.StateQueued,
+	})
+	s.Run()
+	require.Equal(t, 1, s.Metrics.CompletedRequests)
+	return append([]int64(nil), s.Metrics.AllITLs...)
+}
+
+func meanVariance(xs []int64) (mean, variance float64) {
+	for _, x := range xs {
+		mean += float64(x)
+	}
+	mean /= float64(len(xs))
+	for _, x := range xs {
+		variance += (float64(x) - mean) * (float64(x) - mean)
+	}
+	return mean, variance / float64(len(xs))
+}
+
+// TestNoisyLatencyModel_RaisesITLVariancePreservesMean verifies enabling
+// noise increases ITL variance, keeps the mean ITL within 5%, and is
+// reproducible under a fixed seed.
+func TestNoisyLatencyModel_RaisesITLVariancePreservesMean(t *testing.T) {
+	base := noiseITLs(t, 0, 7)
+	noisy := noiseITLs(t, 0.2, 7)
+	require.Equal(t, len(base), len(noisy))
+
+	baseMean, baseVar := meanVariance(base)
+	noisyMean, noisyVar := meanVariance(noisy)
+	if noisyVar <= 2*baseVar {
+		t.Errorf("ITL variance with noise = %.1f, want well above deterministic %.1f", noisyVar, baseVar)
+	}
+	if rel := math.Abs(noisyMean-baseMean) / baseMean; rel > 0.05 {
+		t.Errorf("mean ITL with noise = %.1f, deterministic %.1f: off by %.1f%%, want <= 5%%", noisyMean, baseMean, 100*rel)
+	}
+
+	require.Equal(t, noisy, noiseITLs(t, 0.2, 7), "same seed must reproduce the same ITLs")
+	require.NotEqual(t, noisy, noiseITLs(t, 0.2, 8), "a different seed should draw different noise")
+}
+
+// TestNewNoisyLatencyModel_ZeroMagnitudeIsInner verifies INV-6: disabled
+// noise returns the inner model unwrapped.
+func TestNewNoisyLatencyModel_ZeroMagnitudeIsInner(t *testing.T) {
+	inner := backends(t, nil)["roofline"]
+	model, err := NewNoisyLatencyModel(inner, 0, 0.5, nil)
+	require.NoError(t, err)
+	require.Same(t, inner, model)
+}
+
+func TestNewNoisyLatencyModel_InvalidParams_ReturnsError(t *testing.T) {
+	inner := backends(t, nil)["roofline"]
+	rng := rand.New(rand.NewSource(1))
+	for _, p := range []struct{ magnitude, correlation float64 }{
+		{-0.1, 0}, {0.6, 0}, {math.NaN(), 0}, {0.1, -0.1}, {0.1, 1}, {0.1, math.NaN()},
+	} {
+		_, err := NewNoisyLatencyModel(inner, p.magnitude, p.correlation, rng)
+		require.Error(t, err, "magnitude %v correlation %v", p.magnitude, p.correlation)
+	}
+}
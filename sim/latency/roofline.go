@@ -21,10 +21,15 @@ type memAccessBytes struct {
 	ModelWeights      float64
 	KVCacheGrowth     float64
 	KVCacheAccess     float64
+	BlockTableAccess  float64
 	ActivationsTokens float64
 	Total             float64
 }
 
+// blockTableBytesPerEntry is the size of one paged-attention block-table entry
+// (an int32 physical block ID, as in vLLM's block_table tensor).
+const blockTableBytesPerEntry = 4
+
 // PrefillRequestConfig describes a single prefill request in a batch step.
 type PrefillRequestConfig struct {
 	ProgressIndex       int64 `json:"progress_index"`
@@ -41,6 +46,9 @@ type DecodeRequestConfig struct {
 type StepConfig struct {
 	PrefillRequests []PrefillRequestConfig `json:"prefill_requests"`
 	DecodeRequests  []DecodeRequestConfig  `json:"decode_requests"`
+	// BlockSizeTokens is the KV block size used to model paged-attention
+	// block-table reads; 0 leaves them out.
+	BlockSizeTokens int64 `json:"block_size_tokens,omitempty"`
 }
 
 // mlpMatrixCount returns the number of MLP weight matrices for bandwidth/FLOPs estimation.
@@ -152,6 +160,9 @@ func calculateTransformerFlops(config sim.ModelConfig, sequenceLength int64, new
 	return flops
 }
 
+// blockSizeTokens > 0 adds paged-attention block-table reads (see BlockTableAccess
+// below); 0 leaves them out.
+//
 // Precondition: same as calculateTransformerFlops — config must pass
 // ValidateRooflineConfig. NumHeads > 0 required (division at dHead).
 // For MoE configs (NumLocalExperts > 1), NumExpertsPerTok must be > 0;
@@ -161,6 +172,7 @@ func calculateMemoryAccessBytes(
 	sequenceLength int64,
 	newTokens int64,
 	includeKVCache bool,
+	blockSizeTokens int64,
 ) memAccessBytes {
 	dModel := float64(config.HiddenDim)
 	nLayers := float64(config.NumLayers)
@@ -246,15 +258,25 @@ func calculateMemoryAccessBytes(
 	mem.ModelWeights = weightsPerLayer * config.EffectiveWeightBytesPerParam()
 
 	if includeKVCache {
-		// KV Growth: Writing new tokens to HBM.
-		kvWritePerNewToken := 2 * nLayers * nKVHeads * dHead * config.BytesPerParam
+		// KV Growth: Writing new tokens to HBM. KV traffic scales with the KV
+		// cache dtype, which may differ from the compute dtype (e.g. FP8 KV).
+		kvWritePerNewToken := 2 * nLayers * nKVHeads * dHead * config.EffectiveKVBytesPerParam()
 		mem.KVCacheGrowth = kvWritePerNewToken * newT
 
 		// KV Access: Only read PAST history.
 		// IMPORTANT: For Prefill (newT > 1), the newT tokens attend to each other in SRAM.
 		// They do NOT generate HBM read traffic for themselves.
-		kvReadPerToken := 2 * nLayers * nKVHeads * dHead * config.BytesPerParam
+		kvReadPerToken := 2 * nLayers * nKVHeads * dHead * config.EffectiveKVBytesPerParam()
 		mem.KVCacheAccess = kvReadPerToken * seq
+
+		// Block-table access: every layer's attention kernel walks the request's
+		// page table, one entry per KV block of context. Small next to the KV
+		// reads themselves, but it grows with context length. Not sharded by TP:
+		// each rank reads the whole table.
+		if blockSizeTokens > 0 {
+			numBlocks := math.Ceil((seq + newT) / float64(blockSizeTokens))
+			mem.BlockTableAccess = numBlocks * blockTableBytesPerEntry * nLayers
+		}
 	}
 
 	// Token activations (linear)
@@ -265,10 +287,43 @@ func calculateMemoryAccessBytes(
 
 	// Sum known fields in declaration order — deterministic by construction,
 	// no sort.Strings + key-slice allocation needed (eliminates antipattern #2).
-	mem.Total = mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.ActivationsTokens
+	mem.Total = mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.BlockTableAccess + mem.ActivationsTokens
 	return mem
 }
 
+// int8PeakFlopsMultiplier is the dense INT8 tensor-core throughput relative to
+// FP16/BF16 on Ampere and Hopper (A100: 624 vs 312 TOPS; H100: 1979 vs 989).
+const int8PeakFlopsMultiplier = 2.0
+
+// peakTFlops returns the GEMM peak throughput (TFLOPS) for the model's compute dtype.
+//
+// An explicit ComputeDtype selects the rate directly: bf16/fp16 use TFlopsPeak,
+// int8 uses TFlopsPeak × int8PeakFlopsMultiplier, and fp8 uses TFlopsFP8 when the
+// GPU has native FP8 tensor cores (falling back to TFlopsPeak otherwise).
+//
+// When ComputeDtype is empty the dtype is inferred from weight precision:
+// FP8 models (exactly 1 byte/param) on GPUs with native FP8 tensor cores use the FP8 rate.
+// Sub-FP8 formats (e.g., W4A16 at 0.5 bytes/param) dequantize to FP16 during GEMM, using FP16 rate.
+// This reflects that H100 has native FP8 tensor cores (~1979 TFLOPS, 2× FP16),
+// while A100/L40S use W8A16 via Marlin kernels (weights dequantized to FP16 during GEMM, preserving the FP16 compute rate).
+func peakTFlops(modelConfig sim.ModelConfig, hwConfig sim.HardwareCalib) float64 {
+	switch modelConfig.ComputeDtype {
+	case sim.ComputeDtypeBF16, sim.ComputeDtypeFP16:
+		return hwConfig.TFlopsPeak
+	case sim.ComputeDtypeINT8:
+		return hwConfig.TFlopsPeak * int8PeakFlopsMultiplier
+	case sim.ComputeDtypeFP8:
+		if hwConfig.TFlopsFP8 > 0 {
+			return hwConfig.TFlopsFP8
+		}
+		return hwConfig.TFlopsPeak
+	}
+	if modelConfig.EffectiveWeightBytesPerParam() == 1.0 && hwConfig.TFlopsFP8 > 0 {
+		return hwConfig.TFlopsFP8
+	}
+	return hwConfig.TFlopsPeak
+}
+
 // rooflineStepTime computes step latency using the roofline model.
 //
 // Models a single forward pass per step (matching vLLM chunked prefill):
@@ -289,26 +344,47 @@ func calculateMemoryAccessBytes(
 // Precondition: ValidateRooflineConfig(modelConfig, hwConfig) must return nil
 // and tp must be > 0. Callers must validate before first call.
 func rooflineStepTime(modelConfig sim.ModelConfig, hwConfig sim.HardwareCalib, stepConfig StepConfig, tp int) int64 {
+	return rooflineStepBreakdown(modelConfig, hwConfig, stepConfig, tp).micros()
+}
+
+// rooflineStep is one forward pass's roofline terms. flops and bytes are per
+// GPU (after TP sharding), the quantities computeS and memoryS are derived from;
+// peakFlops is the per-GPU peak FLOPs/sec they were measured against.
+type rooflineStep struct {
+	computeS  float64
+	memoryS   float64
+	flops     float64
+	bytes     float64
+	peakFlops float64
+}
+
+// seconds is the step latency: the single-crossover max of compute and memory time.
+func (r rooflineStep) seconds() float64 {
+	return math.Max(r.computeS, r.memoryS)
+}
+
+// micros is seconds in ticks.
+func (r rooflineStep) micros() int64 {
+	return clampToInt64(r.seconds() * 1e6)
+}
+
+// rooflineStepBreakdown computes the compute and memory terms behind
+// rooflineStepTime. An empty step returns the zero rooflineStep.
+func rooflineStepBreakdown(modelConfig sim.ModelConfig, hwConfig sim.HardwareCalib, stepConfig StepConfig, tp int) rooflineStep {
 
 	tpFactor := float64(tp)
 
-	// Select compute throughput based on weight precision and hardware capability.
-	// FP8 models (exactly 1 byte/param) on GPUs with native FP8 tensor cores use the FP8 rate.
-	// Sub-FP8 formats (e.g., W4A16 at 0.5 bytes/param) dequantize to FP16 during GEMM, using FP16 rate.
-	// This reflects that H100 has native FP8 tensor cores (~1979 TFLOPS, 2× FP16),
-	// while A100/L40S use W8A16 via Marlin kernels (weights dequantized to FP16 during GEMM, preserving the FP16 compute rate).
-	peakFlops := hwConfig.TFlopsPeak * 1e12
-	if modelConfig.EffectiveWeightBytesPerParam() == 1.0 && hwConfig.TFlopsFP8 > 0 {
-		peakFlops = hwConfig.TFlopsFP8 * 1e12
-	}
+	// Select compute throughput based on compute dtype and hardware capability.
+	peakFlops := peakTFlops(modelConfig, hwConfig) * 1e12
 
 	peakBW := hwConfig.BwPeakTBs * 1e12
 
 	if len(stepConfig.PrefillRequests) == 0 && len(stepConfig.DecodeRequests) == 0 {
-		return 0
+		return rooflineStep{}
 	}
 
 	var totalComputeS float64
+	var totalFlops float64
 	var totalDynamicBytes float64
 
 	// 1. PREFILL FLOPs + dynamic memory (KV cache, activations)
@@ -317,18 +393,24 @@ func rooflineStepTime(modelConfig sim.ModelConfig, hwConfig sim.HardwareCalib, s
 
 		f := calculateTransformerFlops(modelConfig, req.ProgressIndex, numTokens, true, true)
 		totalComputeS += f.Total / tpFactor / (peakFlops * hwConfig.MfuPrefill)
+		totalFlops += f.Total / tpFactor
 
-		m := calculateMemoryAccessBytes(modelConfig, req.ProgressIndex, numTokens, true)
-		totalDynamicBytes += (m.Total - m.ModelWeights) / tpFactor
+		m := calculateMemoryAccessBytes(modelConfig, req.ProgressIndex, numTokens, true, stepConfig.BlockSizeTokens)
+		totalDynamicBytes += (m.Total-m.ModelWeights-m.BlockTableAccess)/tpFactor + m.BlockTableAccess
 	}
 
 	// 2. DECODE FLOPs + dynamic memory (KV cache, activations)
+	// A request decoding several tokens per step (multi-token decode) counts
+	// each of them; unset NumNewDecodeTokens means the usual single token.
 	for _, req := range stepConfig.DecodeRequests {
-		f := calculateTransformerFlops(modelConfig, req.ProgressIndex, 1, true, true)
+		numTokens := max(int64(req.NumNewDecodeTokens), 1)
+
+		f := calculateTransformerFlops(modelConfig, req.ProgressIndex, numTokens, true, true)
 		totalComputeS += f.Total / tpFactor / (peakFlops * hwConfig.MfuDecode)
+		totalFlops += f.Total / tpFactor
 
-		m := calculateMemoryAccessBytes(modelConfig, req.ProgressIndex, 1, true)
-		totalDynamicBytes += (m.Total - m.ModelWeights) / tpFactor
+		m := calculateMemoryAccessBytes(modelConfig, req.ProgressIndex, numTokens, true, stepConfig.BlockSizeTokens)
+		totalDynamicBytes += (m.Total-m.ModelWeights-m.BlockTableAccess)/tpFactor + m.BlockTableAccess
 	}
 
 	// 3. WEIGHTS loaded once per step (single forward pass, per Sarathi-Serve/vLLM V1)
@@ -337,15 +419,21 @@ func rooflineStepTime(modelConfig sim.ModelConfig, hwConfig sim.HardwareCalib, s
 	for _, req := range stepConfig.PrefillRequests {
 		totalNewTokens += int64(req.NumNewPrefillTokens)
 	}
-	totalNewTokens += int64(len(stepConfig.DecodeRequests))
+	for _, req := range stepConfig.DecodeRequests {
+		totalNewTokens += max(int64(req.NumNewDecodeTokens), 1)
+	}
 
-	baseMem := calculateMemoryAccessBytes(modelConfig, 0, totalNewTokens, false)
+	baseMem := calculateMemoryAccessBytes(modelConfig, 0, totalNewTokens, false, 0)
 	weightBytes := baseMem.ModelWeights / tpFactor
 
 	totalMemoryS := (weightBytes + totalDynamicBytes) / peakBW
 
-	// 4. ROOFLINE: single crossover
-	totalMicros := math.Max(totalComputeS, totalMemoryS) * 1e6
-
-	return clampToInt64(totalMicros)
+	// 4. ROOFLINE: single crossover (see micros)
+	return rooflineStep{
+		computeS:  totalComputeS,
+		memoryS:   totalMemoryS,
+		flops:     totalFlops,
+		bytes:     weightBytes + totalDynamicBytes,
+		peakFlops: peakFlops,
+	}
 }
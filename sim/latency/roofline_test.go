@@ -14,7 +14,7 @@ func TestCalculateMemoryAccessBytes_Deterministic(t *testing.T) {
 	// WHEN calculateMemoryAccessBytes is called 100 times
 	var firstTotal float64
 	for i := 0; i < 100; i++ {
-		result := calculateMemoryAccessBytes(config, 1024, 64, true)
+		result := calculateMemoryAccessBytes(config, 1024, 64, true, 0)
 
 		// THEN every call produces the same "total"
 		if i == 0 {
@@ -30,8 +30,8 @@ func TestCalculateMemoryAccessBytes_Deterministic(t *testing.T) {
 	}
 
 	// Verify component-sum conservation: total == sum of all components
-	result := calculateMemoryAccessBytes(config, 1024, 64, true)
-	componentSum := result.ModelWeights + result.KVCacheGrowth + result.KVCacheAccess + result.ActivationsTokens
+	result := calculateMemoryAccessBytes(config, 1024, 64, true, 0)
+	componentSum := result.ModelWeights + result.KVCacheGrowth + result.KVCacheAccess + result.BlockTableAccess + result.ActivationsTokens
 	if result.Total != componentSum {
 		t.Errorf("conservation violation: total=%v but sum of components=%v", result.Total, componentSum)
 	}
@@ -135,8 +135,8 @@ func TestCalculateMemoryAccessBytes_Monotonicity_MoreTokensMoreBytes(t *testing.
 	// BC-2: more newTokens MUST produce higher total bytes
 	mc := testModelConfig()
 
-	small := calculateMemoryAccessBytes(mc, 512, 100, true)
-	large := calculateMemoryAccessBytes(mc, 512, 200, true)
+	small := calculateMemoryAccessBytes(mc, 512, 100, true, 0)
+	large := calculateMemoryAccessBytes(mc, 512, 200, true, 0)
 
 	if large.Total <= small.Total {
 		t.Errorf("200 tokens total bytes (%g) should exceed 100 tokens (%g)",
@@ -148,9 +148,9 @@ func TestCalculateMemoryAccessBytes_Conservation_TotalEqualsSumOfComponents(t *t
 	// BC-9: total MUST equal sum of all non-"total" components
 	mc := testModelConfig()
 
-	mem := calculateMemoryAccessBytes(mc, 512, 64, true)
+	mem := calculateMemoryAccessBytes(mc, 512, 64, true, 0)
 
-	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.ActivationsTokens
+	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.BlockTableAccess + mem.ActivationsTokens
 	if math.Abs(mem.Total-sum) > 1e-6 {
 		t.Errorf("total (%g) != sum of components (%g), delta=%g",
 			mem.Total, sum, mem.Total-sum)
@@ -368,11 +368,11 @@ func TestCalculateMemoryAccessBytes_MoE_EffectiveExperts(t *testing.T) {
 	dense := mc
 	dense.NumLocalExperts = 0
 	dense.NumExpertsPerTok = 0
-	denseMem := calculateMemoryAccessBytes(dense, 512, 1, false)
+	denseMem := calculateMemoryAccessBytes(dense, 512, 1, false, 0)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			moeMem := calculateMemoryAccessBytes(mc, 512, tt.batchSize, false)
+			moeMem := calculateMemoryAccessBytes(mc, 512, tt.batchSize, false, 0)
 
 			// Verify MoE weights exceed dense (attention same, MLP has nEff experts)
 			if moeMem.ModelWeights <= denseMem.ModelWeights {
@@ -448,7 +448,7 @@ func TestCalculateMemoryAccessBytes_MoE_EdgeCases(t *testing.T) {
 			mc.NumLocalExperts = tt.N
 			mc.NumExpertsPerTok = tt.k
 
-			mem := calculateMemoryAccessBytes(mc, 512, tt.batchSize, false)
+			mem := calculateMemoryAccessBytes(mc, 512, tt.batchSize, false, 0)
 
 			// Calculate expected nEff directly from formula
 			N := float64(tt.N)
@@ -467,7 +467,7 @@ func TestCalculateMemoryAccessBytes_MoE_EdgeCases(t *testing.T) {
 			dense := mc
 			dense.NumLocalExperts = 0
 			dense.NumExpertsPerTok = 0
-			denseMem := calculateMemoryAccessBytes(dense, 512, tt.batchSize, false)
+			denseMem := calculateMemoryAccessBytes(dense, 512, tt.batchSize, false, 0)
 
 			if mem.ModelWeights <= denseMem.ModelWeights {
 				t.Errorf("%s: MoE weights (%g) should exceed dense weights (%g)",
@@ -498,12 +498,12 @@ func TestCalculateMemoryAccessBytes_MoE_Monotonicity(t *testing.T) {
 	dense := mc
 	dense.NumLocalExperts = 0
 	dense.NumExpertsPerTok = 0
-	denseMem := calculateMemoryAccessBytes(dense, 512, 1, false)
+	denseMem := calculateMemoryAccessBytes(dense, 512, 1, false, 0)
 	denseWeights := denseMem.ModelWeights
 
 	var prevWeights float64
 	for B := int64(1); B <= 20; B++ {
-		mem := calculateMemoryAccessBytes(mc, 512, B, false)
+		mem := calculateMemoryAccessBytes(mc, 512, B, false, 0)
 		moeWeights := mem.ModelWeights
 
 		// Extract effective expert contribution
@@ -536,9 +536,9 @@ func TestCalculateMemoryAccessBytes_MoE_Monotonicity(t *testing.T) {
 func TestCalculateMemoryAccessBytes_MoE_Conservation(t *testing.T) {
 	// total = sum of components
 	mc := testMixtralConfig()
-	mem := calculateMemoryAccessBytes(mc, 512, 64, true)
+	mem := calculateMemoryAccessBytes(mc, 512, 64, true, 0)
 
-	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.ActivationsTokens
+	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.BlockTableAccess + mem.ActivationsTokens
 	if math.Abs(mem.Total-sum) > 1e-6 {
 		t.Errorf("conservation violation: total (%g) != components (%g)", mem.Total, sum)
 	}
@@ -547,12 +547,12 @@ func TestCalculateMemoryAccessBytes_MoE_Conservation(t *testing.T) {
 func TestCalculateMemoryAccessBytes_Dense_UnchangedAfterMoE(t *testing.T) {
 	// BC-10: dense model memory unchanged (regression anchor)
 	mc := testModelConfig()
-	mem := calculateMemoryAccessBytes(mc, 512, 64, true)
+	mem := calculateMemoryAccessBytes(mc, 512, 64, true, 0)
 
 	if mem.ModelWeights <= 0 {
 		t.Fatal("expected positive model_weights for dense config")
 	}
-	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.ActivationsTokens
+	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.BlockTableAccess + mem.ActivationsTokens
 	if mem.Total != sum {
 		t.Errorf("dense conservation: total (%g) != sum (%g)", mem.Total, sum)
 	}
@@ -645,8 +645,8 @@ func TestRooflineStepTime_MoE_BandwidthReduction(t *testing.T) {
 	}
 
 	// Verify bandwidth reduction using actual batch sizes
-	smallMem := calculateMemoryAccessBytes(mc, 512, int64(len(smallBatch.DecodeRequests)), true)
-	largeMem := calculateMemoryAccessBytes(mc, 512, int64(len(largeBatch.DecodeRequests)), true)
+	smallMem := calculateMemoryAccessBytes(mc, 512, int64(len(smallBatch.DecodeRequests)), true, 0)
+	largeMem := calculateMemoryAccessBytes(mc, 512, int64(len(largeBatch.DecodeRequests)), true, 0)
 	weightReduction := 1.0 - (smallMem.ModelWeights / largeMem.ModelWeights)
 	if weightReduction < 0.20 {
 		t.Errorf("Expected ≥20%% weight bandwidth reduction for small batch, got %.1f%%",
@@ -675,8 +675,8 @@ func TestRooflineStepTime_SingleCrossover_MemoryBoundDecode(t *testing.T) {
 	peakBW := hc.BwPeakTBs * 1e12
 	peakFlops := hc.TFlopsPeak * 1e12
 
-	baseMem := calculateMemoryAccessBytes(mc, 0, 0, false)
-	dynamicMem := calculateMemoryAccessBytes(mc, 512, 1, true)
+	baseMem := calculateMemoryAccessBytes(mc, 0, 0, false, 0)
+	dynamicMem := calculateMemoryAccessBytes(mc, 512, 1, true, 0)
 	totalBytes := baseMem.ModelWeights + (dynamicMem.Total - dynamicMem.ModelWeights)
 
 	flops := calculateTransformerFlops(mc, 512, 1, true, true)
@@ -727,7 +727,7 @@ func TestRooflineStepTime_MixedBatch_WeightsLoadedOnce(t *testing.T) {
 	// Mixed should be >= decode-only (more work), but if weights were loaded
 	// twice, mixed would be roughly 2× decode-only for memory-bound steps.
 	// With single weight load, the increase should be modest (just extra dynamic bytes).
-	baseMem := calculateMemoryAccessBytes(mc, 0, 0, false)
+	baseMem := calculateMemoryAccessBytes(mc, 0, 0, false, 0)
 	weightBytes := baseMem.ModelWeights
 
 	// The mixed step should NOT double the weight bandwidth.
@@ -785,8 +785,8 @@ func TestCalculateMemoryAccessBytes_W4A16_WeightsReduced_KVUnchanged(t *testing.
 	fp16 := testModelConfig()
 	w4a16 := testW4A16Config()
 
-	fp16Mem := calculateMemoryAccessBytes(fp16, 512, 64, true)
-	w4a16Mem := calculateMemoryAccessBytes(w4a16, 512, 64, true)
+	fp16Mem := calculateMemoryAccessBytes(fp16, 512, 64, true, 0)
+	w4a16Mem := calculateMemoryAccessBytes(w4a16, 512, 64, true, 0)
 
 	// model_weights should be 1/4 of FP16 (0.5/2.0)
 	ratio := w4a16Mem.ModelWeights / fp16Mem.ModelWeights
@@ -815,12 +815,12 @@ func TestCalculateMemoryAccessBytes_W4A16_WeightsReduced_KVUnchanged(t *testing.
 func TestCalculateMemoryAccessBytes_NonQuantized_IdenticalToBaseline(t *testing.T) {
 	// BC-8: non-quantized model (WeightBytesPerParam=0) produces identical results
 	baseline := testModelConfig()
-	baselineMem := calculateMemoryAccessBytes(baseline, 512, 64, true)
+	baselineMem := calculateMemoryAccessBytes(baseline, 512, 64, true, 0)
 
 	// WeightBytesPerParam=0 (sentinel) — should fall back to BytesPerParam
 	withSentinel := testModelConfig()
 	withSentinel.WeightBytesPerParam = 0
-	sentinelMem := calculateMemoryAccessBytes(withSentinel, 512, 64, true)
+	sentinelMem := calculateMemoryAccessBytes(withSentinel, 512, 64, true, 0)
 
 	if baselineMem.ModelWeights != sentinelMem.ModelWeights {
 		t.Errorf("non-quantized should be identical: baseline=%g, sentinel=%g",
@@ -835,9 +835,9 @@ func TestCalculateMemoryAccessBytes_NonQuantized_IdenticalToBaseline(t *testing.
 func TestCalculateMemoryAccessBytes_W4A16_Conservation(t *testing.T) {
 	// Conservation: total == sum(components) for quantized model
 	mc := testW4A16Config()
-	mem := calculateMemoryAccessBytes(mc, 512, 64, true)
+	mem := calculateMemoryAccessBytes(mc, 512, 64, true, 0)
 
-	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.ActivationsTokens
+	sum := mem.ModelWeights + mem.KVCacheGrowth + mem.KVCacheAccess + mem.BlockTableAccess + mem.ActivationsTokens
 	if math.Abs(mem.Total-sum) > 1e-6 {
 		t.Errorf("conservation violation: total=%g, sum=%g", mem.Total, sum)
 	}
@@ -1159,7 +1159,7 @@ func TestRooflineStepTime_Scout_InterleavedMoE(t *testing.T) {
 
 	t.Run("Weight_bandwidth_split_correctly_for_interleaved", func(t *testing.T) {
 		// Calculate weight bandwidth for Scout with batch size
-		mem := calculateMemoryAccessBytes(scoutConfig, 0, 588, false)
+		mem := calculateMemoryAccessBytes(scoutConfig, 0, 588, false, 0)
 		weightBytes := mem.ModelWeights
 
 		// Verify weight bytes are positive and finite
@@ -1183,11 +1183,11 @@ func TestRooflineStepTime_Scout_InterleavedMoE(t *testing.T) {
 
 	t.Run("nEff_zero_bug_fixed", func(t *testing.T) {
 		// Calculate with newTokens=0 (the bug scenario)
-		memZero := calculateMemoryAccessBytes(scoutConfig, 0, 0, false)
+		memZero := calculateMemoryAccessBytes(scoutConfig, 0, 0, false, 0)
 		weightBytesZero := memZero.ModelWeights
 
 		// Calculate with newTokens=588 (correct scenario)
-		memBatch := calculateMemoryAccessBytes(scoutConfig, 0, 588, false)
+		memBatch := calculateMemoryAccessBytes(scoutConfig, 0, 588, false, 0)
 		weightBytesBatch := memBatch.ModelWeights
 
 		// With the fix, both should be positive (dense layers contribute regardless)
@@ -1241,6 +1241,151 @@ func TestRooflineStepTime_GQA_ReducesKVBandwidth(t *testing.T) {
 	}
 }
 
+// TestRooflineStepTime_ComputeDtype_FP8FasterOnlyWhenComputeBound verifies that
+// an explicit FP8 compute dtype raises the effective peak FLOPs: compute-bound
+// prefill gets faster, while a memory-bound decode step is unchanged because
+// weight and KV traffic do not depend on the compute dtype.
+func TestRooflineStepTime_ComputeDtype_FP8FasterOnlyWhenComputeBound(t *testing.T) {
+	// GIVEN the same model with bf16 and fp8 compute (identical weight precision)
+	mcBF16 := testModelConfig()
+	mcBF16.ComputeDtype = sim.ComputeDtypeBF16
+	mcFP8 := testModelConfig()
+	mcFP8.ComputeDtype = sim.ComputeDtypeFP8
+
+	// AND an H100 with native FP8 tensor cores
+	hc := testHardwareCalib()
+	hc.TFlopsFP8 = 2 * hc.TFlopsPeak
+
+	// WHEN a compute-bound prefill step is estimated
+	prefill := StepConfig{PrefillRequests: []PrefillRequestConfig{{ProgressIndex: 0, NumNewPrefillTokens: 4096}}}
+	bf16Prefill := rooflineStepTime(mcBF16, hc, prefill, 1)
+	fp8Prefill := rooflineStepTime(mcFP8, hc, prefill, 1)
+
+	// THEN fp8 runs at roughly twice the bf16 rate
+	ratio := float64(fp8Prefill) / float64(bf16Prefill)
+	if ratio < 0.45 || ratio > 0.55 {
+		t.Errorf("fp8/bf16 prefill ratio = %.3f (%d µs / %d µs), want ≈ 0.5", ratio, fp8Prefill, bf16Prefill)
+	}
+
+	// WHEN a memory-bound decode step is estimated
+	decode := StepConfig{DecodeRequests: []DecodeRequestConfig{
+		{ProgressIndex: 1024, NumNewDecodeTokens: 1},
+		{ProgressIndex: 2048, NumNewDecodeTokens: 1},
+	}}
+	bf16Decode := rooflineStepTime(mcBF16, hc, decode, 1)
+	fp8Decode := rooflineStepTime(mcFP8, hc, decode, 1)
+
+	// THEN both dtypes produce the identical memory-bound time
+	if fp8Decode != bf16Decode {
+		t.Errorf("memory-bound decode: fp8 = %d µs, bf16 = %d µs, want identical", fp8Decode, bf16Decode)
+	}
+}
+
+func TestPeakTFlops_ComputeDtype(t *testing.T) {
+	h100 := sim.HardwareCalib{TFlopsPeak: 989.5, TFlopsFP8: 1979.0}
+	a100 := sim.HardwareCalib{TFlopsPeak: 312.0}
+	fp8Weights := testModelConfig()
+	fp8Weights.WeightBytesPerParam = 1.0
+
+	tests := []struct {
+		name  string
+		dtype string
+		mc    sim.ModelConfig
+		hc    sim.HardwareCalib
+		want  float64
+	}{
+		{"inferred bf16", "", testModelConfig(), h100, 989.5},
+		{"inferred fp8 from weights", "", fp8Weights, h100, 1979.0},
+		{"explicit bf16 overrides fp8 weights", sim.ComputeDtypeBF16, fp8Weights, h100, 989.5},
+		{"fp16", sim.ComputeDtypeFP16, testModelConfig(), h100, 989.5},
+		{"fp8 native", sim.ComputeDtypeFP8, testModelConfig(), h100, 1979.0},
+		{"fp8 without native support", sim.ComputeDtypeFP8, testModelConfig(), a100, 312.0},
+		{"int8", sim.ComputeDtypeINT8, testModelConfig(), a100, 624.0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := tc.mc
+			mc.ComputeDtype = tc.dtype
+			if got := peakTFlops(mc, tc.hc); got != tc.want {
+				t.Errorf("peakTFlops = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestCalculateMemoryAccessBytes_KVBytesPerParam_ScalesOnlyKVTraffic verifies
+// that an FP8 KV cache halves KV reads/writes and leaves weight and activation
+// traffic at the compute dtype.
+func TestCalculateMemoryAccessBytes_KVBytesPerParam_ScalesOnlyKVTraffic(t *testing.T) {
+	mcBF16 := testModelConfig()
+	mcFP8KV := testModelConfig()
+	mcFP8KV.KVBytesPerParam = 1.0
+
+	base := calculateMemoryAccessBytes(mcBF16, 2048, 16, true, 0)
+	fp8KV := calculateMemoryAccessBytes(mcFP8KV, 2048, 16, true, 0)
+
+	if fp8KV.KVCacheAccess != base.KVCacheAccess/2 || fp8KV.KVCacheGrowth != base.KVCacheGrowth/2 {
+		t.Errorf("KV traffic with fp8 KV = (%v, %v), want half of (%v, %v)",
+			fp8KV.KVCacheAccess, fp8KV.KVCacheGrowth, base.KVCacheAccess, base.KVCacheGrowth)
+	}
+	if fp8KV.ModelWeights != base.ModelWeights || fp8KV.ActivationsTokens != base.ActivationsTokens {
+		t.Errorf("weights/activations changed with KV dtype: (%v, %v) vs (%v, %v)",
+			fp8KV.ModelWeights, fp8KV.ActivationsTokens, base.ModelWeights, base.ActivationsTokens)
+	}
+}
+
+// TestCalculateMemoryAccessBytes_BlockTable_OneEntryPerBlockPerLayer verifies
+// the block-table term: ceil(context / blockSize) 4-byte entries per layer,
+// included in Total, and absent when blockSize is 0 or KV is excluded.
+func TestCalculateMemoryAccessBytes_BlockTable_OneEntryPerBlockPerLayer(t *testing.T) {
+	mc := testModelConfig()
+	// 1000 past + 1 new token = 1001 tokens of context = 63 blocks of 16.
+	mem := calculateMemoryAccessBytes(mc, 1000, 1, true, 16)
+	if want := 63.0 * 4 * 32; mem.BlockTableAccess != want {
+		t.Errorf("BlockTableAccess = %v, want %v", mem.BlockTableAccess, want)
+	}
+	without := calculateMemoryAccessBytes(mc, 1000, 1, true, 0)
+	if without.BlockTableAccess != 0 || mem.Total-without.Total != mem.BlockTableAccess {
+		t.Errorf("block table not isolated: with=%v without=%v term=%v", mem.Total, without.Total, mem.BlockTableAccess)
+	}
+	if w := calculateMemoryAccessBytes(mc, 0, 64, false, 16); w.BlockTableAccess != 0 {
+		t.Errorf("weights-only call charged block table: %v", w.BlockTableAccess)
+	}
+}
+
+// TestRooflineStepTime_BlockTable_GrowsWithContext verifies that block-table
+// reads raise long-context decode step time more than short-context, and that
+// short contexts are nearly unaffected.
+func TestRooflineStepTime_BlockTable_GrowsWithContext(t *testing.T) {
+	mc := testModelConfig()
+	hc := testHardwareCalib()
+	// GIVEN a full decode batch of 256 requests at a given context length
+	decodeStep := func(context int64, blockSize int64) int64 {
+		reqs := make([]DecodeRequestConfig, 256)
+		for i := range reqs {
+			reqs[i] = DecodeRequestConfig{ProgressIndex: context, NumNewDecodeTokens: 1}
+		}
+		return rooflineStepTime(mc, hc, StepConfig{DecodeRequests: reqs, BlockSizeTokens: blockSize}, 1)
+	}
+
+	// WHEN step time is computed with and without block-table reads
+	shortBase, shortBT := decodeStep(256, 0), decodeStep(256, 16)
+	longBase, longBT := decodeStep(65536, 0), decodeStep(65536, 16)
+	t.Logf("short: %d -> %d µs, long: %d -> %d µs", shortBase, shortBT, longBase, longBT)
+
+	// THEN long context pays strictly more, and more than short context does
+	if longBT <= longBase {
+		t.Errorf("long context: step time %d with block table, want > %d", longBT, longBase)
+	}
+	if longBT-longBase <= shortBT-shortBase {
+		t.Errorf("block-table cost at long context (%d µs) not above short context (%d µs)", longBT-longBase, shortBT-shortBase)
+	}
+	// Short contexts are nearly unaffected (< 0.1%).
+	if rel := float64(shortBT-shortBase) / float64(shortBase); rel > 1e-3 {
+		t.Errorf("short context: relative increase %.5f, want < 0.001", rel)
+	}
+}
+
 func BenchmarkRooflineStepTime_MixedBatch(b *testing.B) {
 	mc := testModelConfig()
 	hc := testHardwareCalib()
@@ -1262,3 +1407,27 @@ func BenchmarkRooflineStepTime_MixedBatch(b *testing.B) {
 		rooflineStepTime(mc, hc, step, 1)
 	}
 }
+
+// TestRooflineStepTime_MultiTokenDecode_ChargesEveryToken verifies that a
+// decode request advancing several tokens per step is charged for all of
+// them, and that an unset NumNewDecodeTokens keeps the single-token cost.
+func TestRooflineStepTime_MultiTokenDecode_ChargesEveryToken(t *testing.T) {
+	mc := testModelConfig()
+	hc := testHardwareCalib()
+	decodeStep := func(tokens int) int64 {
+		reqs := make([]DecodeRequestConfig, 256)
+		for i := range reqs {
+			reqs[i] = DecodeRequestConfig{ProgressIndex: 1024, NumNewDecodeTokens: tokens}
+		}
+		return rooflineStepTime(mc, hc, StepConfig{DecodeRequests: reqs}, 1)
+	}
+
+	unset, one, four := decodeStep(0), decodeStep(1), decodeStep(4)
+	if unset != one {
+		t.Errorf("NumNewDecodeTokens=0: %d µs, want single-token %d µs", unset, one)
+	}
+	// Weights are read once per step, so 4 tokens cost more than 1 but less than 4 steps.
+	if four <= one || four >= 4*one {
+		t.Errorf("4 tokens/step: %d µs, want in (%d, %d)", four, one, 4*one)
+	}
+}
@@ -0,0 +1,81 @@
+package latency
+
+import (
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/stretchr/testify/require"
+)
+
+// schedulingBackends returns one roofline and one trained-physics model built
+// via the production constructor with the given per-sequence overhead.
+func schedulingBackends(t *testing.T, usPerSeq float64) map[string]sim.LatencyModel {
+	t.Helper()
+	roofHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+	roof, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), roofHW, WithSchedulingOverhead(usPerSeq))
+	require.NoError(t, err, "roofline NewLatencyModel")
+
+	tpHW := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "trained-physics", 0, "")
+	tp, err := NewLatencyModel(*testCoeffs(), tpHW, WithSchedulingOverhead(usPerSeq))
+	require.NoError(t, err, "trained-physics NewLatencyModel")
+
+	return map[string]sim.LatencyModel{"roofline": roof, "trained-physics": tp}
+}
+
+// TestStepTime_SchedulingOverhead_AddsPerSequence verifies both backends add
+// exactly usPerSeq * batch size to StepTime, and that 0 is a no-op (INV-6).
+func TestStepTime_SchedulingOverhead_AddsPerSequence(t *testing.T) {
+	const usPerSeq = 37.5
+	base := schedulingBackends(t, 0)
+	noOpt := backends(t, nil)
+	for name, model := range schedulingBackends(t, usPerSeq) {
+		for _, n := range []int{0, 1, 4, 16} {
+			batch := make([]*sim.Request, n)
+			for i := range batch {
+				batch[i] = prefillReq(64, "")
+			}
+			want := base[name].StepTime(batch) + int64(usPerSeq*float64(n)+0.5)
+			if got := model.StepTime(batch); got != want {
+				t.Errorf("%s StepTime(batch=%d) = %d, want %d", name, n, got, want)
+			}
+			if got, want := base[name].StepTime(batch), noOpt[name].StepTime(batch); got != want {
+				t.Errorf("%s zero overhead StepTime(batch=%d) = %d, want byte-identical %d", name, n, got, want)
+			}
+		}
+	}
+}
+
+// TestStepTime_SchedulingContention_AddsPerSequenceSquared verifies both
+// backends add usPerSeqSq * batch size² on top of the linear term.
+func TestStepTime_SchedulingContention_AddsPerSequenceSquared(t *testing.T) {
+	const usPerSeq, usPerSeqSq = 10, 0.5
+	base := schedulingBackends(t, usPerSeq)
+	for name := range base {
+		hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+		coeffs := sim.NewLatencyCoeffs(nil, []float64{100, 1, 100})
+		if name == "trained-physics" {
+			hw = sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 1, 1, false, "", "trained-physics", 0, "")
+			coeffs = *testCoeffs()
+		}
+		model, err := NewLatencyModel(coeffs, hw, WithSchedulingOverhead(usPerSeq), WithSchedulingContention(usPerSeqSq))
+		require.NoError(t, err, name)
+		for _, n := range []int{0, 1, 4, 16} {
+			batch := make([]*sim.Request, n)
+			for i := range batch {
+				batch[i] = prefillReq(64, "")
+			}
+			want := base[name].StepTime(batch) + int64(usPerSeqSq*float64(n*n)+0.5)
+			if got := model.StepTime(batch); got != want {
+				t.Errorf("%s StepTime(batch=%d) = %d, want %d", name, n, got, want)
+			}
+		}
+	}
+}
+
+func TestNewLatencyModel_InvalidSchedulingOverhead_ReturnsError(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(testModelConfig(), testHardwareCalib(), "", "", 2, 1, false, "", "roofline", 0, "")
+	_, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), hw, WithSchedulingOverhead(-1))
+	require.Error(t, err)
+	_, err = NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 100}), hw, WithSchedulingContention(-1))
+	require.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package latency
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// Required CSV columns of a step-time table. Column order is free; extra
+// columns are ignored.
+const (
+	tableColBatchTokens = "batch_tokens"
+	tableColContextLen  = "context_len"
+	tableColStepTimeUs  = "step_time_us"
+)
+
+// StepTimeTable is a grid of measured step times. StepTimeUs[i][j] is the
+// step time observed with BatchTokens[i] new tokens scheduled in the step and
+// ContextTokens[j] tokens of context across the batch. Both axes are strictly
+// ascending and every (batch, context) pair has a measurement.
+type StepTimeTable struct {
+	BatchTokens   []float64
+	ContextTokens []float64
+	StepTimeUs    [][]float64
+}
+
+// LoadStepTimeTable reads a step-time table from a CSV file. See
+// ParseStepTimeTable for the format.
+func LoadStepTimeTable(path string) (*StepTimeTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("step-time table: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	t, err := ParseStepTimeTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("step-time table %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// ParseStepTimeTable parses a CSV with a header row naming the columns
+// batch_tokens, context_len and step_time_us (microseconds). Rows may appear
+// in any order but must cover the full cross product of the distinct
+// batch_tokens and context_len values exactly once. All values must be finite
+// and non-negative (R3).
+func ParseStepTimeTable(r io.Reader) (*StepTimeTable, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("empty table: expected a header row with %s, %s, %s",
+			tableColBatchTokens, tableColContextLen, tableColStepTimeUs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := map[string]int{tableColBatchTokens: -1, tableColContextLen: -1, tableColStepTimeUs: -1}
+	for i, name := range header {
+		if idx, ok := cols[strings.TrimSpace(name)]; ok && idx < 0 {
+			cols[strings.TrimSpace(name)] = i
+		}
+	}
+	for _, name := range []string{tableColBatchTokens, tableColContextLen, tableColStepTimeUs} {
+		if cols[name] < 0 {
+			return nil, fmt.Errorf("header is missing column %q", name)
+		}
+	}
+
+	type point struct{ batch, context float64 }
+	measured := make(map[point]float64)
+	batchSet := make(map[float64]bool)
+	contextSet := make(map[float64]bool)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		var v [3]float64
+		for k, name := range []string{tableColBatchTokens, tableColContextLen, tableColStepTimeUs} {
+			x, err := strconv.ParseFloat(strings.TrimSpace(rec[cols[name]]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", line, name, err)
+			}
+			if math.IsNaN(x) || math.IsInf(x, 0) || x < 0 {
+				return nil, fmt.Errorf("line %d: %s must be a finite value >= 0, got %v", line, name, x)
+			}
+			v[k] = x
+		}
+		p := point{v[0], v[1]}
+		if _, dup := measured[p]; dup {
+			return nil, fmt.Errorf("line %d: duplicate measurement for %s=%v, %s=%v",
+				line, tableColBatchTokens, p.batch, tableColContextLen, p.context)
+		}
+		measured[p] = v[2]
+		batchSet[p.batch] = true
+		contextSet[p.context] = true
+	}
+	if len(measured) == 0 {
+		return nil, fmt.Errorf("table has no measurements")
+	}
+
+	t := &StepTimeTable{BatchTokens: sortedKeys(batchSet), ContextTokens: sortedKeys(contextSet)}
+	t.StepTimeUs = make([][]float64, len(t.BatchTokens))
+	for i, b := range t.BatchTokens {
+		t.StepTimeUs[i] = make([]float64, len(t.ContextTokens))
+		for j, c := range t.ContextTokens {
+			st, ok := measured[point{b, c}]
+			if !ok {
+				return nil, fmt.Errorf("incomplete grid: no measurement for %s=%v, %s=%v",
+					tableColBatchTokens, b, tableColContextLen, c)
+			}
+			t.StepTimeUs[i][j] = st
+		}
+	}
+	return t, nil
+}
+
+func sortedKeys(set map[float64]bool) []float64 {
+	keys := make([]float64, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Float64s(keys)
+	return keys
+}
+
+// Interpolate returns the bilinearly interpolated step time in microseconds at
+// (batchTokens, contextTokens). Queries outside the measured range are clamped
+// to the nearest edge of the grid rather than extrapolated.
+func (t *StepTimeTable) Interpolate(batchTokens, contextTokens float64) float64 {
+	i0, i1, fb := gridSegment(t.BatchTokens, batchTokens)
+	j0, j1, fc := gridSegment(t.ContextTokens, contextTokens)
+	lo := t.StepTimeUs[i0][j0]*(1-fc) + t.StepTimeUs[i0][j1]*fc
+	hi := t.StepTimeUs[i1][j0]*(1-fc) + t.StepTimeUs[i1][j1]*fc
+	return lo*(1-fb) + hi*fb
+}
+
+// gridSegment locates x on an ascending axis, returning the bracketing indices
+// and x's fractional position between them. Out-of-range x clamps to an end.
+func gridSegment(axis []float64, x float64) (lo, hi int, frac float64) {
+	n := len(axis)
+	if x <= axis[0] {
+		return 0, 0, 0
+	}
+	if x >= axis[n-1] {
+		return n - 1, n - 1, 0
+	}
+	hi = sort.SearchFloat64s(axis, x)
+	lo = hi - 1
+	return lo, hi, (x - axis[lo]) / (axis[hi] - axis[lo])
+}
+
+// TableLatencyModel looks step times up in a measured StepTimeTable. A step is
+// keyed by its scheduled token count (sum of NumNewTokens) and its total
+// context (sum over the batch of ProgressIndex + NumNewTokens), with a decoding
+// request counted once per parallel sample. Queueing and output-token
+// overheads use the alpha coefficients, as in roofline.
+type TableLatencyModel struct {
+	table       *StepTimeTable
+	alphaCoeffs []float64
+	// adapterCost supplies the per-step LoRA compute-overhead factor. nil when
+	// the LoRA subsystem is inert. Set via WithAdapterCost at construction.
+	adapterCost sim.AdapterCost
+	// schedulingOverheadUsPerSeq is the per-sequence scheduling cost added to
+	// every step (0 = disabled). Set via WithSchedulingOverhead at construction.
+	schedulingOverheadUsPerSeq float64
+	// schedulingContentionUsSq is the per-sequence² contention cost added to
+	// every step (0 = disabled). Set via WithSchedulingContention at construction.
+	schedulingContentionUsSq float64
+	// minStepTimeTicks floors the forward-pass time (0 = no floor). Set via
+	// WithMinStepTime at construction.
+	minStepTimeTicks int64
+}
+
+func (m *TableLatencyModel) StepTime(batch []*sim.Request) int64 {
+	var batchTokens, contextTokens int64
+	for _, req := range batch {
+		samples := int64(1)
+		if req.ProgressIndex >= req.InputLen() {
+			samples = int64(req.Samples())
+		}
+		batchTokens += samples * int64(req.NumNewTokens)
+		contextTokens += samples * (int64(req.ProgressIndex) + int64(req.NumNewTokens))
+	}
+	base := clampToInt64(math.Round(m.table.Interpolate(float64(batchTokens), float64(contextTokens))))
+	stepTime := applyStepTimeFloor(applyAdapterOverhead(max(1, base), batch, m.adapterCost), m.minStepTimeTicks)
+	return applySchedulingOverhead(stepTime, batch, m.schedulingOverheadUsPerSeq, m.schedulingContentionUsSq)
+}
+
+func (m *TableLatencyModel) QueueingTime(req *sim.Request) int64 {
+	return clampToInt64(m.alphaCoeffs[0] + m.alphaCoeffs[1]*float64(req.InputLen()))
+}
+
+func (m *TableLatencyModel) OutputTokenProcessingTime() int64 {
+	return clampToInt64(m.alphaCoeffs[2])
+}
+
+func (m *TableLatencyModel) PostDecodeFixedOverhead() int64 { return 0 }
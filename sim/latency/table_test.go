@@ -0,0 +1,91 @@
+package latency
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
+	"github.com/stretchr/testify/require"
+)
+
+const stepTimeTableFixture = "testdata/step_time_table.csv"
+
+// lerp is the reference 1-D linear interpolation used to check the grid.
+func lerp(x, x0, x1, y0, y1 float64) float64 {
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// TestStepTimeTable_Interpolate_MatchesLinearInterpolationOffGrid verifies
+// that off-grid lookups equal linear interpolation along each axis (and its
+// composition in the cell interior), that grid points return the measured
+// value, and that queries outside the grid clamp to the edge.
+func TestStepTimeTable_Interpolate_MatchesLinearInterpolationOffGrid(t *testing.T) {
+	table, err := LoadStepTimeTable(stepTimeTableFixture)
+	require.NoError(t, err)
+	require.Equal(t, []float64{64, 256, 1024}, table.BatchTokens)
+	require.Equal(t, []float64{1024, 8192}, table.ContextTokens)
+
+	cases := []struct {
+		name           string
+		batch, context float64
+		want           float64
+	}{
+		{"grid point", 256, 8192, 9000},
+		{"off-grid batch", 640, 1024, lerp(640, 256, 1024, 5000, 12000)},
+		{"off-grid context", 64, 3000, lerp(3000, 1024, 8192, 4000, 6000)},
+		{"cell interior", 100, 5000, lerp(100, 64, 256,
+			lerp(5000, 1024, 8192, 4000, 6000),
+			lerp(5000, 1024, 8192, 5000, 9000))},
+		{"below grid clamps", 1, 1, 4000},
+		{"above grid clamps", 1e6, 1e6, 20000},
+	}
+	for _, tc := range cases {
+		if got := table.Interpolate(tc.batch, tc.context); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: Interpolate(%v, %v) = %v, want %v", tc.name, tc.batch, tc.context, got, tc.want)
+		}
+	}
+}
+
+// TestNewLatencyModel_Table_StepTimeUsesBatchTokensAndContext verifies the
+// backend is reachable through the factory and keys the lookup by scheduled
+// tokens and total batch context.
+func TestNewLatencyModel_Table_StepTimeUsesBatchTokensAndContext(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "table", 0, stepTimeTableFixture)
+	model, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{100, 1, 50}), hw)
+	require.NoError(t, err)
+
+	// Two decode requests at 2000 context each: 2 new tokens, 4002 context.
+	batch := []*sim.Request{
+		{InputTokens: make([]sim.TokenID, 1000), ProgressIndex: 2000, NumNewTokens: 1},
+		{InputTokens: make([]sim.TokenID, 1000), ProgressIndex: 2000, NumNewTokens: 1},
+	}
+	want := int64(math.Round(lerp(4002, 1024, 8192, 4000, 6000)))
+	require.Equal(t, want, model.StepTime(batch))
+	require.Equal(t, int64(4000), model.StepTime(nil), "empty batch clamps to the grid corner")
+	require.Equal(t, int64(100+1000), model.QueueingTime(batch[0]))
+	require.Equal(t, int64(50), model.OutputTokenProcessingTime())
+}
+
+func TestParseStepTimeTable_InvalidInput_ReturnsError(t *testing.T) {
+	cases := map[string]string{
+		"missing column":  "batch_tokens,step_time_us\n1,2\n",
+		"incomplete grid": "batch_tokens,context_len,step_time_us\n1,1,5\n1,2,6\n2,1,7\n",
+		"duplicate point": "batch_tokens,context_len,step_time_us\n1,1,5\n1,1,6\n",
+		"negative value":  "batch_tokens,context_len,step_time_us\n1,1,-5\n",
+		"NaN value":       "batch_tokens,context_len,step_time_us\n1,1,NaN\n",
+		"no rows":         "batch_tokens,context_len,step_time_us\n",
+		"empty":           "",
+	}
+	for name, csv := range cases {
+		if _, err := ParseStepTimeTable(strings.NewReader(csv)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewLatencyModel_TableWithoutPath_ReturnsError(t *testing.T) {
+	hw := sim.NewModelHardwareConfig(sim.ModelConfig{}, sim.HardwareCalib{}, "", "", 0, 1, false, "", "table", 0, "")
+	_, err := NewLatencyModel(sim.NewLatencyCoeffs(nil, []float64{0, 0, 0}), hw)
+	require.Error(t, err)
+}
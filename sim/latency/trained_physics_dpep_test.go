@@ -41,7 +41,7 @@ func dpepMoEModelConfig() *sim.ModelConfig {
 // ep, backend). It uses the 11-coeff defaults so β_EP is active.
 func newDPEPModel(t *testing.T, mc *sim.ModelConfig, tp, dp int, ep bool, backend string) *TrainedPhysicsModel {
 	t.Helper()
-	mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", tp, dp, ep, backend, "trained-physics", 0)
+	mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", tp, dp, ep, backend, "trained-physics", 0, "")
 	m, err := NewTrainedPhysicsModel(*testCoeffs(), mhw)
 	require.NoError(t, err)
 	return m
@@ -178,7 +178,7 @@ func TestStepTime_AttentionComputeScalesWithDP(t *testing.T) {
 	}
 	batch := makePrefillBatch(64, 512) // compute-bound
 	mk := func(tp, dp int) int64 {
-		mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", tp, dp, false, "", "trained-physics", 0)
+		mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", tp, dp, false, "", "trained-physics", 0, "")
 		m, err := NewTrainedPhysicsModel(*coeffs, mhw)
 		require.NoError(t, err)
 		return m.StepTime(batch)
@@ -317,7 +317,7 @@ func TestMoEWorkedExample_TP2DP2(t *testing.T) {
 func TestBetaEP_DefaultsToBeta4(t *testing.T) {
 	mc := trainedPhysicsTestModelConfig()
 	hw := dpepTestHW()
-	mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", 1, 1, false, "", "trained-physics", 0)
+	mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", 1, 1, false, "", "trained-physics", 0, "")
 
 	// 10-coeff caller: β_EP must default to β₄.
 	c10 := &sim.LatencyCoeffs{
@@ -345,7 +345,7 @@ func TestBetaEP_DefaultsToBeta4(t *testing.T) {
 func TestNewTrainedPhysicsModel_RejectsUnknownCommBackend(t *testing.T) {
 	mc := trainedPhysicsTestModelConfig()
 	hw := dpepTestHW()
-	mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", 1, 1, false, "not-a-backend", "trained-physics", 0)
+	mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", 1, 1, false, "not-a-backend", "trained-physics", 0, "")
 	_, err := NewTrainedPhysicsModel(*testCoeffs(), mhw)
 	require.Error(t, err, "unknown MoE comm backend must be rejected")
 	assert.Contains(t, err.Error(), "not-a-backend")
@@ -376,7 +376,7 @@ func TestINVBCDP1_DenseStepTimeByteIdentical(t *testing.T) {
 	}
 
 	for tp, want := range golden {
-		mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, "", "trained-physics", 0)
+		mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, "", "trained-physics", 0, "")
 		m, err := NewTrainedPhysicsModel(*coeffs, mhw)
 		require.NoError(t, err)
 		got := m.StepTime(dpepMixedBatch())
@@ -398,20 +398,20 @@ func TestINVBCDP1_DenseDP1Determinism(t *testing.T) {
 	batch := dpepMixedBatch()
 
 	for _, tp := range []int{1, 2, 4, 8} {
-		base := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, "", "trained-physics", 0)
+		base := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, "", "trained-physics", 0, "")
 		mBase, err := NewTrainedPhysicsModel(*coeffs, base)
 		require.NoError(t, err)
 		want := mBase.StepTime(batch)
 
 		// EP flag is a no-op for dense models.
-		epOn := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, true, "", "trained-physics", 0)
+		epOn := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, true, "", "trained-physics", 0, "")
 		mEP, err := NewTrainedPhysicsModel(*coeffs, epOn)
 		require.NoError(t, err)
 		assert.Equalf(t, want, mEP.StepTime(batch), "dense step time must ignore EP flag (tp=%d)", tp)
 
 		// Comm backend is a no-op for dense models (no MoE dispatch).
 		for _, backend := range ValidMoECommBackends {
-			mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, backend, "trained-physics", 0)
+			mhw := sim.NewModelHardwareConfig(*mc, hw, "m", "H100", tp, 1, false, backend, "trained-physics", 0, "")
 			mB, err := NewTrainedPhysicsModel(*coeffs, mhw)
 			require.NoError(t, err)
 			assert.Equalf(t, want, mB.StepTime(batch),
@@ -431,7 +431,7 @@ func TestINVBCDP1_DenseDP1Determinism(t *testing.T) {
 func TestNewTrainedPhysicsModel_RejectsZeroBytesPerParam(t *testing.T) {
 	mc := dpepMoEModelConfig()
 	mc.BytesPerParam = 0 // unrecognized/missing torch_dtype
-	mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", 1, 1, false, "", "trained-physics", 0)
+	mhw := sim.NewModelHardwareConfig(*mc, dpepTestHW(), "m", "H100", 1, 1, false, "", "trained-physics", 0, "")
 	_, err := NewTrainedPhysicsModel(*testCoeffs(), mhw)
 	require.Error(t, err, "zero BytesPerParam must be rejected at construction")
 	assert.Contains(t, err.Error(), "BytesPerParam")
@@ -173,6 +173,15 @@ type TrainedPhysicsModel struct {
 	// when the LoRA subsystem is inert, in which case StepTime is byte-identical to
 	// a pre-feature build (INV-6/INV-BC-DP1). Set via WithAdapterCost at construction.
 	adapterCost sim.AdapterCost
+	// schedulingOverheadUsPerSeq is the per-sequence scheduling cost added to
+	// every step (0 = disabled). Set via WithSchedulingOverhead at construction.
+	schedulingOverheadUsPerSeq float64
+	// schedulingContentionUsSq is the per-sequence² contention cost added to
+	// every step (0 = disabled). Set via WithSchedulingContention at construction.
+	schedulingContentionUsSq float64
+	// minStepTimeTicks floors the forward-pass time (0 = no floor). Set via
+	// WithMinStepTime at construction.
+	minStepTimeTicks int64
 }
 
 // bytesPerKVElement is 2 bytes (FP16) for KV cache, matching vLLM's default.
@@ -231,9 +240,11 @@ func (m *TrainedPhysicsModel) StepTime(batch []*sim.Request) int64 {
 			totalPrefillTokens += ti
 			prefillAttnFlops += 4 * hPerGPU * ti * (si + ti/2) * dH
 		} else if len(req.OutputTokens) > 0 {
-			// Decode
-			totalDecodeTokens++
-			sumCtx += float64(req.ProgressIndex)
+			// Decode (NumNewTokens > 1 under multi-token decode), once per
+			// parallel sample
+			samples := float64(req.Samples())
+			totalDecodeTokens += samples * float64(max(req.NumNewTokens, 1))
+			sumCtx += samples * float64(req.ProgressIndex)
 		}
 	}
 
@@ -431,7 +442,8 @@ func (m *TrainedPhysicsModel) StepTime(batch []*sim.Request) int64 {
 		m.Beta[6] +
 		m.Beta[7]*moeScaling*float64(m.numMoELayers) // β₈: per-MoE-layer overhead (interleaved archs only)
 
-	return applyAdapterOverhead(max(1, clampToInt64(stepTime)), batch, m.adapterCost)
+	gpuTime := applyStepTimeFloor(applyAdapterOverhead(max(1, clampToInt64(stepTime)), batch, m.adapterCost), m.minStepTimeTicks)
+	return applySchedulingOverhead(gpuTime, batch, m.schedulingOverheadUsPerSeq, m.schedulingContentionUsSq)
 }
 
 // sharedExpertCompute returns the shared-expert FFN compute basis (raw FLOPs) for
@@ -0,0 +1,81 @@
+package sim
+
+import "sort"
+
+// RecentLatencyWindowSize is the number of most recent completions kept in each
+// instance's rolling TTFT window (consumed by the p99-ttft routing scorer).
+const RecentLatencyWindowSize = 100
+
+// latencyWindow is a fixed-capacity ring buffer of the most recent per-request
+// latencies (ticks), in completion order. The P99 is cached and recomputed only
+// after the window changes, so repeated snapshot reads between completions are O(1).
+type latencyWindow struct {
+	values []int64
+	next   int // ring write position once the buffer is full
+	p99    float64
+	dirty  bool
+}
+
+func newLatencyWindow(capacity int) *latencyWindow {
+	return &latencyWindow{values: make([]int64, 0, capacity)}
+}
+
+// add records one latency sample, evicting the oldest when the window is full.
+func (w *latencyWindow) add(v int64) {
+	if len(w.values) < cap(w.values) {
+		w.values = append(w.values, v)
+	} else {
+		w.values[w.next] = v
+		w.next = (w.next + 1) % len(w.values)
+	}
+	w.dirty = true
+}
+
+// P99 returns the 99th percentile of the window in ticks (μs), or 0 when empty.
+func (w *latencyWindow) P99() float64 {
+	if w.dirty {
+		sorted := append([]int64(nil), w.values...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		w.p99 = CalculatePercentile(sorted, 99) * 1e3 // CalculatePercentile returns ms
+		w.dirty = false
+	}
+	return w.p99
+}
+
+// RecentPreemptionWindowUs is the span (μs) of each instance's rolling
+// preemption window (consumed by the preemption-rate routing scorer).
+const RecentPreemptionWindowUs int64 = 1_000_000
+
+// eventWindow holds the timestamps (ticks) of events within the trailing span,
+// in non-decreasing order. Older timestamps are dropped on each add and query.
+type eventWindow struct {
+	times []int64
+	span  int64
+}
+
+func newEventWindow(span int64) *eventWindow {
+	return &eventWindow{span: span}
+}
+
+// add records one event at tick t.
+func (w *eventWindow) add(t int64) {
+	w.times = append(w.times, t)
+	w.prune(t)
+}
+
+// prune drops events at or before now - span.
+func (w *eventWindow) prune(now int64) {
+	i := 0
+	for i < len(w.times) && w.times[i] <= now-w.span {
+		i++
+	}
+	if i > 0 {
+		w.times = append(w.times[:0], w.times[i:]...)
+	}
+}
+
+// RatePerSec returns the number of events in (now - span, now] per second.
+func (w *eventWindow) RatePerSec(now int64) float64 {
+	w.prune(now)
+	return float64(len(w.times)) * 1e6 / float64(w.span)
+}
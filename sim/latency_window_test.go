@@ -0,0 +1,40 @@
+package sim
+
+import "testing"
+
+func TestLatencyWindow_EvictsOldestAndTracksP99(t *testing.T) {
+	w := newLatencyWindow(4)
+	if got := w.P99(); got != 0 {
+		t.Fatalf("empty window P99 = %v, want 0", got)
+	}
+	for _, v := range []int64{100_000, 1000, 1000, 1000} {
+		w.add(v)
+	}
+	if got := w.P99(); got <= 1000 {
+		t.Errorf("P99 = %v with a 100ms outlier in the window, want > 1000", got)
+	}
+	// One more sample evicts the outlier (the oldest entry).
+	w.add(1000)
+	if got := w.P99(); got != 1000 {
+		t.Errorf("P99 = %v after the outlier aged out, want 1000", got)
+	}
+}
+
+func TestEventWindow_RateDropsOnceEventsAgeOut(t *testing.T) {
+	w := newEventWindow(1_000_000)
+	if got := w.RatePerSec(0); got != 0 {
+		t.Fatalf("empty window rate = %v, want 0", got)
+	}
+	w.add(100)
+	w.add(500_000)
+	if got := w.RatePerSec(900_000); got != 2 {
+		t.Errorf("rate = %v with 2 events in the last second, want 2", got)
+	}
+	// The first event leaves the window exactly one span after it happened.
+	if got := w.RatePerSec(1_000_100); got != 1 {
+		t.Errorf("rate = %v after the first event aged out, want 1", got)
+	}
+	if got := w.RatePerSec(2_000_000); got != 0 {
+		t.Errorf("rate = %v after every event aged out, want 0", got)
+	}
+}
@@ -0,0 +1,60 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestMaxOutputTokens_LowCap_RequestsFinishAtCapByLength verifies that a
+// server-side output cap truncates longer sampled outputs: those requests
+// finish with exactly cap output tokens and reason "length" (fewer decode
+// steps), while requests already within the cap finish by EOS untouched.
+func TestMaxOutputTokens_LowCap_RequestsFinishAtCapByLength(t *testing.T) {
+	// GIVEN a cap of 5 output tokens and requests sampling 3, 20, and 40 tokens
+	const maxOut = 5
+	cfg := newTestSimConfig()
+	cfg.MaxOutputTokens = maxOut
+	s := newFixedStepSimulator(t, cfg)
+	outputLens := []int{3, 20, 40}
+	reqs := make([]*Request, len(outputLens))
+	for i, n := range outputLens {
+		reqs[i] = uniformRequests(1, 10, n, 0)[0]
+		reqs[i].ID = fmt.Sprintf("request_%d", i)
+		reqs[i].ArrivalTime = int64(i) * 100
+	}
+
+	// WHEN the simulation runs to completion
+	runToCompletion(t, s, reqs)
+
+	// THEN capped requests emit exactly maxOut tokens
+	wantTotal := 0
+	for i, req := range reqs {
+		rm := s.Metrics.Requests[req.ID]
+		wantLen, wantReason := outputLens[i], CompletionReasonStop
+		if wantLen > maxOut {
+			wantLen, wantReason = maxOut, CompletionReasonLength
+		}
+		wantTotal += wantLen
+		if rm.CompletionReason != wantReason {
+			t.Errorf("%s: CompletionReason = %q, want %q", req.ID, rm.CompletionReason, wantReason)
+		}
+		if rm.NumDecodeTokens != wantLen {
+			t.Errorf("%s: NumDecodeTokens = %d, want %d", req.ID, rm.NumDecodeTokens, wantLen)
+		}
+		// One ITL entry per token after the first.
+		if got := len(req.ITL); got != wantLen-1 {
+			t.Errorf("%s: %d ITL entries, want %d (decode steps bounded by the cap)", req.ID, got, wantLen-1)
+		}
+	}
+	if s.Metrics.TotalOutputTokens != wantTotal {
+		t.Errorf("TotalOutputTokens = %d, want %d", s.Metrics.TotalOutputTokens, wantTotal)
+	}
+}
+
+func TestNewSimulator_MaxOutputTokensValidation(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.MaxOutputTokens = -1
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+		t.Error("MaxOutputTokens=-1: expected error")
+	}
+}
@@ -23,14 +23,38 @@ type Metrics struct {
 	KVBlocksUsed      float64 // Integral of KVBlockUsage over time
 	PeakKVBlocksUsed  int64   // Max number of simultaneously used KV blocks
 	PreemptionCount      int64   // Total preemption events (PR12)
-	KVAllocationFailures int64   // KV allocation failures for the final decode token at completion; non-zero indicates a cache accounting anomaly (#183)
+	DecodePreemptionCount int64  // Subset of PreemptionCount evicted to fit a completing request's final decode token
+	WastedPrefillTokens  int64   // Progress (ProgressIndex) discarded by preemptions; recomputed when the victims are re-prefilled
+	SwapOutCount         int64   // Subset of PreemptionCount swapped out to host memory with progress kept (PreemptionModeSwap)
+	SwapTransferTicks    int64   // Step time spent copying KV out on swap-out and back in on swap-in (PreemptionModeSwap)
+	PriorityHOLBlockingEvents int64 // Preemptions of the running request nearest completion among those less urgent than a waiting request (PreemptedRequest.HOLBlocking)
+	RemotePrefixFetchedBlocks int64 // KV blocks fetched from another instance's cache via the shared prefix index
+	BatchAffinitySwitchSteps int64 // Steps whose batch mixed more than one Request.AffinityKey (each paid SimConfig.BatchAffinitySwitchCostUs per extra key)
+	KVAllocationFailures int64   // Final decode token allocations that failed even after preempting every other evictable running request (#183)
 	CacheHitRate         float64 // Cumulative cache hit rate at finalization (PR12). Intentional observability signal: set by cluster/instance.go Finalize() from KVStore.CacheHitRate(). Read-only statistic — does not feed back into state evolution.
 	KVThrashingRate      float64 // KV thrashing rate at finalization (PR12)
+	KVBlocksSavedBySharing int64 // Block allocations avoided by parallel samples sharing prompt blocks, at finalization
+	KVRoundingWasteTokens  int64 // Unfilled last-block slots summed over successful KV allocations, at finalization (KVStore.RoundingWaste)
+	KVAllocations          int64 // Successful KV allocations, at finalization (see MeanKVRoundingWaste)
 	StillQueued          int     // Requests still in wait queue at sim end
 	StillRunning         int     // Requests still in running batch at sim end
 	DroppedUnservable    int // Requests dropped at enqueue: negative MaxOutputLen (R3), MaxModelLen violation, or input exceeds KV capacity (R19)
+	DroppedUnservableByReason map[string]int // DroppedUnservable split by Unservable* reason; nil until the first drop
 	LengthCappedRequests int // Requests force-completed at MaxModelLen-1 boundary (proactive cap)
 	TimedOutRequests     int // Requests cancelled by client timeout
+	QueueOverflowRejected int // Arrivals rejected because the wait queue was at MaxQueueDepth (reject-new)
+	QueueOverflowDropped  int // Queued requests evicted to make room for a newer arrival (drop-oldest)
+	StarvationPromotions  int // Requests moved to the wait-queue front on reaching MaxQueueWaitTicks
+	EnergyJoules          float64 // Modeled energy of executed steps (SimConfig.PowerPeakWatts > 0)
+	PowerThrottledSteps   int64   // Steps whose compute was stretched by SimConfig.PowerCapWatts
+	Roofline              RooflineStepStats // Per-step FLOPs/bytes accounting (SimConfig.RooflineAccounting)
+	TimeBudget            TimeBudgetBreakdown // Busy time split into queueing, scheduling, compute, output processing and preemption
+	BatchSteps            int64 // Steps that ran a non-empty batch
+	BatchScheduledTokens  int64 // Tokens scheduled over those steps (see MeanTokensPerStep)
+	GPUBusyTicks          int64 // Summed duration of those steps
+	GPUIdleTicks          int64 // Time with an empty running batch: SimEndedTime − GPUBusyTicks, set by Finalize (see GPUIdleFraction)
+	StepBounds            StepBoundCounts // Formed batches by what ended admission (see ClassifyBottleneck)
+	KVTotalBlocks         int64 // KV capacity in blocks, set by Finalize; summed across instances (see MeanKVUtilization)
 
 	TTFTSum int64 // Total time-to-first-token sum (in ticks)
 	ITLSum  int64 // Total ITL sum across requests (in ticks)
@@ -38,6 +62,8 @@ type Metrics struct {
 	RequestTTFTs            map[string]float64 // list of all requests' TTFT
 	RequestITLs             map[string]float64 // list of all requests' ITL
 	RequestSchedulingDelays map[string]int64   // list of all requests' scheduling delays
+	RequestQueueWaits       map[string]int64   // first-schedule tick − arrival; unlike scheduling delay, not reset by preemption
+	RequestKVResidencies    map[string]int64   // completed requests' KV release tick − first block-allocation tick
 	AllITLs                 []int64            // list of all requests' ITL
 	RequestE2Es             map[string]float64 // list of all requests' latencies
 	RequestCompletionTimes  map[string]float64 // list of all requests' completion times in ticks
@@ -60,6 +86,61 @@ type Metrics struct {
 	// adapter-blind run produces no adapter output (INV-6). Surfaced via buildAdapterMetrics.
 	AdapterLoadCounts     map[string]int64
 	AdapterEvictionCounts map[string]int64
+
+	// Prefix-cache hit/miss block counts per tenant and per SLO class,
+	// captured at finalization alongside CacheHitRate. Nil when no lookup
+	// occurred. In cluster mode counts are summed per key across instances,
+	// so rates derived from them are pooled, not the per-instance mean that
+	// CacheHitRate reports.
+	CacheHitCountsByTenant   map[string]CacheHitCounts
+	CacheHitCountsBySLOClass map[string]CacheHitCounts
+
+	// Throughput over time. CompletedSeries[k] is CompletedRequests as of tick
+	// (k+1)*ThroughputSampleIntervalUs; the last sample is taken at or after
+	// SimEndedTime and equals the final CompletedRequests. Empty when
+	// ThroughputSampleIntervalUs is 0. In cluster mode the aggregate carries
+	// the cluster-wide series (PD sub-requests counted once per request),
+	// sampled by the cluster event loop rather than summed across instances.
+	ThroughputSampleIntervalUs int64
+	CompletedSeries            []int
+
+	// KV usage over time. KVUsedSeries[k] is the most KV blocks in use during
+	// ticks [k*KVSampleIntervalUs, (k+1)*KVSampleIntervalUs): the usage
+	// measured at each step (as for PeakKVBlocksUsed), carried across
+	// intervals with no step. Empty when KVSampleIntervalUs is 0. In cluster mode
+	// the aggregate carries each instance's series in KVUsedSeriesByInstance.
+	KVSampleIntervalUs     int64
+	KVUsedSeries           []int64
+	KVUsedSeriesByInstance map[string][]int64
+
+	// PercentileMethod selects how BuildOutput computes latency percentiles.
+	// Empty = PercentileLinear, the pre-existing behavior (INV-6).
+	PercentileMethod PercentileMethod
+}
+
+// Reasons a request is dropped as unservable: the keys of
+// Metrics.DroppedUnservableByReason.
+const (
+	// UnservableNegativeBudget: MaxOutputLen is negative (R3).
+	UnservableNegativeBudget = "negative-budget"
+	// UnservableExceedsModelLen: the input, or input plus output budget,
+	// does not fit in MaxModelLen.
+	UnservableExceedsModelLen = "exceeds-max-model-len"
+	// UnservableTooLarge: ceil(input / block size) exceeds the KV cache's
+	// total blocks, so the input can never be allocated (R19).
+	UnservableTooLarge = "too-large"
+	// UnservableDecodeKV: PD disaggregation found no KV room for the decode
+	// sub-request after its prefill completed.
+	UnservableDecodeKV = "decode-kv-unavailable"
+)
+
+// AddUnservable counts n unservable drops under reason.
+func (m *Metrics) AddUnservable(reason string, n int) {
+	m.DroppedUnservable += n
+	if m.DroppedUnservableByReason == nil {
+		m.DroppedUnservableByReason = make(map[string]int)
+	}
+	m.DroppedUnservableByReason[reason] += n
 }
 
 func NewMetrics() *Metrics {
@@ -71,6 +152,8 @@ func NewMetrics() *Metrics {
 		RequestE2Es:             make(map[string]float64),
 		RequestCompletionTimes:  make(map[string]float64),
 		RequestSchedulingDelays: make(map[string]int64),
+		RequestQueueWaits:       make(map[string]int64),
+		RequestKVResidencies:    make(map[string]int64),
 		NumWaitQRequests:        []int{},
 		NumRunningBatchRequests: []int{},
 		Requests:                make(map[string]RequestMetrics),
@@ -93,15 +176,29 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 		CompletedRequests:    m.CompletedRequests,
 		StillQueued:          m.StillQueued,
 		StillRunning:         m.StillRunning,
-		InjectedRequests:     m.CompletedRequests + m.StillQueued + m.StillRunning + m.DroppedUnservable + m.TimedOutRequests,
+		InjectedRequests:     m.CompletedRequests + m.StillQueued + m.StillRunning + m.DroppedUnservable + m.TimedOutRequests + m.QueueOverflowRejected + m.QueueOverflowDropped,
 		TotalInputTokens:     int(m.TotalInputTokens),
 		TotalOutputTokens:    int(m.TotalOutputTokens),
 		VllmDurationSec:      vllmRuntime,
 		KVAllocationFailures: m.KVAllocationFailures,
 		PreemptionCount:      m.PreemptionCount,
+		DecodePreemptionCount: m.DecodePreemptionCount,
+		WastedPrefillTokens:  m.WastedPrefillTokens,
+		SwapOutCount:         m.SwapOutCount,
+		SwapTransferTicks:    m.SwapTransferTicks,
+		PriorityHOLBlockingEvents: m.PriorityHOLBlockingEvents,
+		BatchAffinitySwitchSteps: m.BatchAffinitySwitchSteps,
+		KVBlocksSavedBySharing: m.KVBlocksSavedBySharing,
 		DroppedUnservable:    m.DroppedUnservable,
+		DroppedUnservableByReason: m.DroppedUnservableByReason,
 		LengthCappedRequests: m.LengthCappedRequests,
 		TimedOutRequests:     m.TimedOutRequests,
+		QueueOverflowRejected: m.QueueOverflowRejected,
+		QueueOverflowDropped:  m.QueueOverflowDropped,
+		StarvationPromotions:  m.StarvationPromotions,
+		EnergyJoules:          m.EnergyJoules,
+		PowerThrottledSteps:   m.PowerThrottledSteps,
+		Roofline:              m.Roofline.Summary(),
 	}
 
 	if m.CompletedRequests > 0 {
@@ -112,9 +209,10 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 		}
 		sort.Float64s(sortedTTFTs)
 		output.TTFTMeanMs = CalculateMean(sortedTTFTs)
-		output.TTFTP90Ms = CalculatePercentile(sortedTTFTs, 90)
-		output.TTFTP95Ms = CalculatePercentile(sortedTTFTs, 95)
-		output.TTFTP99Ms = CalculatePercentile(sortedTTFTs, 99)
+		output.TTFTP50Ms = CalculatePercentileWithMethod(sortedTTFTs, 50, m.PercentileMethod)
+		output.TTFTP90Ms = CalculatePercentileWithMethod(sortedTTFTs, 90, m.PercentileMethod)
+		output.TTFTP95Ms = CalculatePercentileWithMethod(sortedTTFTs, 95, m.PercentileMethod)
+		output.TTFTP99Ms = CalculatePercentileWithMethod(sortedTTFTs, 99, m.PercentileMethod)
 
 		// --- E2E Calculations ---
 		sortedE2Es := make([]float64, 0, len(m.RequestE2Es))
@@ -123,16 +221,17 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 		}
 		sort.Float64s(sortedE2Es)
 		output.E2EMeanMs = CalculateMean(sortedE2Es)
-		output.E2EP90Ms = CalculatePercentile(sortedE2Es, 90)
-		output.E2EP95Ms = CalculatePercentile(sortedE2Es, 95)
-		output.E2EP99Ms = CalculatePercentile(sortedE2Es, 99)
+		output.E2EP50Ms = CalculatePercentileWithMethod(sortedE2Es, 50, m.PercentileMethod)
+		output.E2EP90Ms = CalculatePercentileWithMethod(sortedE2Es, 90, m.PercentileMethod)
+		output.E2EP95Ms = CalculatePercentileWithMethod(sortedE2Es, 95, m.PercentileMethod)
+		output.E2EP99Ms = CalculatePercentileWithMethod(sortedE2Es, 99, m.PercentileMethod)
 
 		// --- ITL Calculations ---
 		slices.Sort(m.AllITLs)
 		output.ITLMeanMs = CalculateMean(m.AllITLs)
-		output.ITLP90Ms = CalculatePercentile(m.AllITLs, 90)
-		output.ITLP95Ms = CalculatePercentile(m.AllITLs, 95)
-		output.ITLP99Ms = CalculatePercentile(m.AllITLs, 99)
+		output.ITLP90Ms = CalculatePercentileWithMethod(m.AllITLs, 90, m.PercentileMethod)
+		output.ITLP95Ms = CalculatePercentileWithMethod(m.AllITLs, 95, m.PercentileMethod)
+		output.ITLP99Ms = CalculatePercentileWithMethod(m.AllITLs, 99, m.PercentileMethod)
 
 		// --- P99 Scheduling Delay ---
 		sortedSchedulingDelays := make([]float64, 0, len(m.RequestSchedulingDelays))
@@ -140,7 +239,7 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 			sortedSchedulingDelays = append(sortedSchedulingDelays, float64(value))
 		}
 		sort.Float64s(sortedSchedulingDelays)
-		output.SchedulingDelayP99Ms = CalculatePercentile(sortedSchedulingDelays, 99)
+		output.SchedulingDelayP99Ms = CalculatePercentileWithMethod(sortedSchedulingDelays, 99, m.PercentileMethod)
 
 		if vllmRuntime > 0 {
 			output.ResponsesPerSec = float64(m.CompletedRequests) / vllmRuntime
@@ -162,13 +261,23 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 		}
 
 		// Calculate total arrivals (Issue #4: needed for rate deficit in batch mode)
-		totalArrivals := m.CompletedRequests + m.StillQueued + m.StillRunning + m.DroppedUnservable + m.TimedOutRequests
+		totalArrivals := m.CompletedRequests + m.StillQueued + m.StillRunning + m.DroppedUnservable + m.TimedOutRequests + m.QueueOverflowRejected + m.QueueOverflowDropped
 
 		// Call Classify with total arrivals (Issues #4, #6: typed interface, rate deficit available)
 		// Note: Sorting by completion time is now handled inside Classify (Issue #5)
 		output.Saturation = saturationDetector.Classify(completedReqs, totalArrivals)
 	}
 
+	// File-only (stdoutView clears them); set here so every BuildOutput caller,
+	// not just the --metrics-path writer, sees them.
+	output.CacheHitRate = m.CacheHitRate
+	output.CacheHitRateByTenant = CacheHitRates(m.CacheHitCountsByTenant)
+	output.CacheHitRateBySLOClass = CacheHitRates(m.CacheHitCountsBySLOClass)
+	if m.TimeBudget.Total() > 0 {
+		budget := m.TimeBudget
+		output.TimeBudget = &budget
+	}
+
 	// Per-adapter aggregate metrics (#1464, US1). Group COMPLETED requests by their
 	// non-empty adapter id; base-model requests (adapter == "") are attributed to no
 	// adapter and excluded. When no request carries an adapter the map stays nil and
@@ -176,6 +285,20 @@ func (m *Metrics) BuildOutput(instanceID string, saturationDetector BatchClassif
 	// the pre-feature build (INV-6).
 	output.Adapters = buildAdapterMetrics(m, vllmRuntime)
 
+	if m.ThroughputSampleIntervalUs > 0 {
+		output.CompletedSeries = &CompletedSeriesOutput{
+			IntervalUs: m.ThroughputSampleIntervalUs,
+			Completed:  append([]int{}, m.CompletedSeries...),
+		}
+	}
+	if m.KVSampleIntervalUs > 0 {
+		output.KVUsedSeries = &KVUsedSeriesOutput{
+			IntervalUs: m.KVSampleIntervalUs,
+			Used:       m.KVUsedSeries,
+			ByInstance: m.KVUsedSeriesByInstance,
+		}
+	}
+
 	return output
 }
 
@@ -234,8 +357,8 @@ func buildAdapterMetrics(m *Metrics, vllmRuntime float64) map[string]AdapterMetr
 		if ttfts, ok := ttftsByAdapter[adapter]; ok {
 			sort.Float64s(ttfts)
 			// CalculatePercentile returns ms (÷1000); ×1000 recovers µs for the _us fields.
-			am.TTFTP50Us = CalculatePercentile(ttfts, 50) * 1000
-			am.TTFTP99Us = CalculatePercentile(ttfts, 99) * 1000
+			am.TTFTP50Us = CalculatePercentileWithMethod(ttfts, 50, m.PercentileMethod) * 1000
+			am.TTFTP99Us = CalculatePercentileWithMethod(ttfts, 99, m.PercentileMethod) * 1000
 			if vllmRuntime > 0 {
 				am.ThroughputTokPerS = float64(outTokensByAdapter[adapter]) / vllmRuntime
 			}
@@ -254,7 +377,7 @@ func (m *Metrics) EmitOutput(output MetricsOutput, outputFilePath string) error
 	// Always emit the metrics section so callers can reliably parse output,
 	// even when CompletedRequests == 0 (e.g., all requests dropped as unservable).
 	fmt.Println("=== Simulation Metrics ===")
-	data, err := json.MarshalIndent(output, "", "  ")
+	data, err := json.MarshalIndent(output.stdoutView(), "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshalling metrics: %w", err)
 	}
@@ -270,12 +393,34 @@ func (m *Metrics) EmitOutput(output MetricsOutput, outputFilePath string) error
 			detail.E2E = m.RequestE2Es[id] / 1e3                                 // zero if not in map
 			detail.ITL = m.RequestITLs[id] / 1e3                                 // ticks → ms (consistent with TTFT, E2E)
 			detail.SchedulingDelay = float64(m.RequestSchedulingDelays[id]) / 1e3 // ticks → ms
+			if e2e, ok := m.RequestE2Es[id]; ok {
+				detail.PrefillTimeMs = detail.TTFT
+				detail.DecodeTimeMs = (e2e - m.RequestTTFTs[id]) / 1e3
+			}
 			output.Requests = append(output.Requests, detail)
 		}
 
 		sort.Slice(output.Requests, func(i, j int) bool {
 			return output.Requests[i].ArrivedAt < output.Requests[j].ArrivedAt
 		})
+		output.PrefillFractionMean, output.PrefillFractionP90 = m.prefillFractions()
+		output.KVResidencyMeanMs, output.KVResidencyP99Ms = m.kvResidencies()
+		if tau, ok := m.ReorderingTau(); ok {
+			output.ReorderingTau = &tau
+		}
+		output.ReorderingTauByInstance = m.ReorderingTauByInstance()
+		hist := m.QueueWaitHistogram()
+		output.QueueWaitHistogram = &hist
+		output.MeanTokensPerStep = m.MeanTokensPerStep()
+		output.KVRoundingWastePerAllocation = m.MeanKVRoundingWaste()
+		output.PreemptedRequests, output.MeanTimeLostToPreemptionMs = m.PreemptionLoss()
+		if b, ok := ClassifyBottleneck(m); ok {
+			output.Bottleneck = &b
+		}
+		if m.GPUIdleTicks+m.GPUBusyTicks > 0 {
+			idle := m.GPUIdleFraction()
+			output.GPUIdleFraction = &idle
+		}
 
 		data, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -291,6 +436,40 @@ func (m *Metrics) EmitOutput(output MetricsOutput, outputFilePath string) error
 	return nil
 }
 
+// BootstrapLatencyCIs computes bootstrap confidence intervals for the P50/P90/P99
+// of TTFT, E2E, and ITL. Resampling draws from the SubsystemBootstrap stream of
+// seed, so the result is a pure function of (m, resamples, seed). Percentiles use
+// m.PercentileMethod, like BuildOutput. Returns nil when resamples <= 0 or no
+// request has completed.
+func (m *Metrics) BootstrapLatencyCIs(resamples int, seed int64) *BootstrapCIs {
+	if resamples <= 0 || m.CompletedRequests == 0 {
+		return nil
+	}
+	// Map iteration order does not matter: each slice is fully sorted before use.
+	ttfts := make([]float64, 0, len(m.RequestTTFTs))
+	for _, value := range m.RequestTTFTs {
+		ttfts = append(ttfts, value)
+	}
+	sort.Float64s(ttfts)
+	e2es := make([]float64, 0, len(m.RequestE2Es))
+	for _, value := range m.RequestE2Es {
+		e2es = append(e2es, value)
+	}
+	sort.Float64s(e2es)
+	itls := slices.Clone(m.AllITLs)
+	slices.Sort(itls)
+
+	rng := NewPartitionedRNG(NewSimulationKey(seed)).ForSubsystem(SubsystemBootstrap)
+	return &BootstrapCIs{
+		Confidence: BootstrapConfidence,
+		Resamples:  resamples,
+		Seed:       seed,
+		TTFT:       bootstrapLatencyCIs(ttfts, resamples, rng, m.PercentileMethod),
+		E2E:        bootstrapLatencyCIs(e2es, resamples, rng, m.PercentileMethod),
+		ITL:        bootstrapLatencyCIs(itls, resamples, rng, m.PercentileMethod),
+	}
+}
+
 // SaveResults computes aggregate metrics and optionally runs post-hoc saturation detection.
 // saturationDetector should be a saturation.Detector or nil to skip saturation analysis.
 // This is a thin wrapper around BuildOutput + EmitOutput; see those methods for the
@@ -304,6 +483,60 @@ func (m *Metrics) SaveResults(instanceID string, horizon int64, totalBlocks int6
 	return m.EmitOutput(output, outputFilePath)
 }
 
+// prefillFractions returns the mean and P90 of TTFT / E2E over completed
+// requests, or zeros when none completed.
+func (m *Metrics) prefillFractions() (mean, p90 float64) {
+	// Fractions are stored ×1e3 so the tick→ms helpers return them unitless.
+	fractions := make([]float64, 0, len(m.RequestE2Es))
+	for id, e2e := range m.RequestE2Es {
+		if e2e > 0 {
+			fractions = append(fractions, 1e3*m.RequestTTFTs[id]/e2e)
+		}
+	}
+	sort.Float64s(fractions)
+	return CalculateMean(fractions), CalculatePercentileWithMethod(fractions, 90, m.PercentileMethod)
+}
+
+// MeanKVRoundingWaste returns the mean unfilled last-block token slots per
+// successful KV allocation (KVRoundingWasteTokens / KVAllocations), or 0 when
+// nothing was allocated. Smaller blocks waste less at the cost of larger
+// block tables.
+func (m *Metrics) MeanKVRoundingWaste() float64 {
+	if m.KVAllocations == 0 {
+		return 0
+	}
+	return float64(m.KVRoundingWasteTokens) / float64(m.KVAllocations)
+}
+
+// kvResidencies returns the mean and P99 KV residency (RequestKVResidencies)
+// of completed requests in ms.
+func (m *Metrics) kvResidencies() (mean, p99 float64) {
+	residencies := make([]float64, 0, len(m.RequestKVResidencies))
+	for _, r := range m.RequestKVResidencies {
+		residencies = append(residencies, float64(r))
+	}
+	sort.Float64s(residencies)
+	return CalculateMean(residencies), CalculatePercentileWithMethod(residencies, 99, m.PercentileMethod)
+}
+
+// QueueWaitHistogramBoundsMs are the inclusive upper bounds of the queue-wait
+// histogram buckets. The 0 bucket isolates requests scheduled on arrival.
+var QueueWaitHistogramBoundsMs = []float64{0, 1, 10, 100, 1000, 10000}
+
+// QueueWaitHistogram buckets the queue wait (RequestQueueWaits) of every
+// completed request, so the counts sum to CompletedRequests.
+func (m *Metrics) QueueWaitHistogram() QueueWaitHistogram {
+	h := QueueWaitHistogram{
+		BoundsMs: QueueWaitHistogramBoundsMs,
+		Counts:   make([]int, len(QueueWaitHistogramBoundsMs)+1),
+	}
+	for id := range m.RequestE2Es {
+		waitMs := float64(m.RequestQueueWaits[id]) / 1e3
+		h.Counts[sort.SearchFloat64s(h.BoundsMs, waitMs)]++
+	}
+	return h
+}
+
 // sortedRequestIDs returns request IDs from the Requests map in sorted order.
 // Ensures deterministic output ordering for JSON serialization.
 func sortedRequestIDs(requests map[string]RequestMetrics) []string {
@@ -426,6 +426,70 @@ func TestSaveResults_AlwaysEmitsHeader_ZeroCompletions(t *testing.T) {
 	assert.Equal(t, 100, out.InjectedRequests)
 }
 
+// TestBuildOutput_CacheHitRates_SetForEveryCallerButFileOnly verifies the
+// cache hit rates come from BuildOutput (so in-process callers such as
+// `blis diff` inputs and replications see them) while the stdout JSON block
+// still omits them (INV-6).
+func TestBuildOutput_CacheHitRates_SetForEveryCallerButFileOnly(t *testing.T) {
+	m := NewMetrics()
+	m.SimEndedTime = 1_000_000
+	m.CacheHitRate = 0.25
+	m.CacheHitCountsByTenant = map[string]CacheHitCounts{"a": {Hits: 3, Misses: 1}}
+	m.CacheHitCountsBySLOClass = map[string]CacheHitCounts{"critical": {Hits: 1, Misses: 1}}
+
+	out := m.BuildOutput("test", nil)
+	assert.Equal(t, 0.25, out.CacheHitRate)
+	assert.Equal(t, map[string]float64{"a": 0.75}, out.CacheHitRateByTenant)
+	assert.Equal(t, map[string]float64{"critical": 0.5}, out.CacheHitRateBySLOClass)
+
+	origStdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	tmpFile := filepath.Join(t.TempDir(), "out.json")
+	emitErr := m.EmitOutput(out, tmpFile)
+	require.NoError(t, w.Close())
+	os.Stdout = origStdout
+	stdout, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, emitErr)
+
+	assert.NotContains(t, string(stdout), "cache_hit_rate")
+	data, err := os.ReadFile(tmpFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cache_hit_rate_by_tenant"`)
+}
+
+// TestBuildOutput_TimeBudget_SetForEveryCallerButFileOnly verifies the time
+// budget comes from BuildOutput while the stdout JSON block omits it (INV-6).
+func TestBuildOutput_TimeBudget_SetForEveryCallerButFileOnly(t *testing.T) {
+	m := NewMetrics()
+	m.SimEndedTime = 1_000_000
+	m.TimeBudget.ComputeTicks = 400
+	m.TimeBudget.QueueingTicks = 100
+
+	out := m.BuildOutput("test", nil)
+	require.NotNil(t, out.TimeBudget)
+	assert.Equal(t, int64(500), out.TimeBudget.Total())
+
+	origStdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	tmpFile := filepath.Join(t.TempDir(), "out.json")
+	emitErr := m.EmitOutput(out, tmpFile)
+	require.NoError(t, w.Close())
+	os.Stdout = origStdout
+	stdout, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, emitErr)
+
+	assert.NotContains(t, string(stdout), "time_budget")
+	data, err := os.ReadFile(tmpFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"time_budget"`)
+}
+
 // BC-3: LengthCappedRequests appears in JSON output
 func TestSaveResults_LengthCappedRequests_InJSON(t *testing.T) {
 	m := NewMetrics()
@@ -452,3 +516,48 @@ func TestSaveResults_LengthCappedRequests_InJSON(t *testing.T) {
 		t.Errorf("LengthCappedRequests in JSON = %d, want 3", output.LengthCappedRequests)
 	}
 }
+
+// TestSaveResults_PrefillDecodeSplit_SumsToE2EAndTracksPromptShare verifies
+// that each completed request's prefill_time_ms + decode_time_ms equals its
+// e2e_ms, and that the aggregate prefill fraction is higher for a prompt-heavy
+// workload than for a decode-heavy one.
+func TestSaveResults_PrefillDecodeSplit_SumsToE2EAndTracksPromptShare(t *testing.T) {
+	run := func(inputLen, outputLen int) MetricsOutput {
+		t.Helper()
+		s := mustNewSimulator(t, newTestSimConfig())
+		for i := 0; i < 8; i++ {
+			s.InjectArrival(&Request{
+				ID:           fmt.Sprintf("request_%d", i),
+				InputTokens:  make([]TokenID, inputLen),
+				OutputTokens: make([]TokenID, outputLen),
+				ArrivalTime:  int64(i) * 20_000,
+				State:        StateQueued,
+			})
+		}
+		s.Run()
+		require.Equal(t, 8, s.Metrics.CompletedRequests)
+
+		outputPath := filepath.Join(t.TempDir(), "results.json")
+		require.NoError(t, s.Metrics.SaveResults("test", s.Horizon, 10000, outputPath, nil))
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		var output MetricsOutput
+		require.NoError(t, json.Unmarshal(data, &output))
+		return output
+	}
+
+	promptHeavy := run(1500, 4)
+	decodeHeavy := run(32, 200)
+
+	for _, out := range []MetricsOutput{promptHeavy, decodeHeavy} {
+		require.Len(t, out.Requests, 8)
+		for _, rm := range out.Requests {
+			assert.InDelta(t, rm.E2E, rm.PrefillTimeMs+rm.DecodeTimeMs, 1e-9, "%s: prefill + decode must equal E2E", rm.ID)
+			assert.Equal(t, rm.TTFT, rm.PrefillTimeMs, "%s: prefill time is TTFT", rm.ID)
+		}
+	}
+	assert.Greater(t, promptHeavy.PrefillFractionMean, decodeHeavy.PrefillFractionMean,
+		"prompt-heavy workload should spend a larger share of E2E in prefill")
+	assert.Greater(t, promptHeavy.PrefillFractionMean, 0.0)
+	assert.LessOrEqual(t, promptHeavy.PrefillFractionMean, 1.0)
+}
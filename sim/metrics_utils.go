@@ -3,6 +3,8 @@ package sim
 
 import (
 	"math"
+	"math/rand"
+	"slices"
 )
 
 type IntOrFloat64 interface {
@@ -19,6 +21,8 @@ type RequestMetrics struct {
 	ITL              float64 `json:"itl_ms"`
 	E2E              float64 `json:"e2e_ms"`
 	SchedulingDelay  float64 `json:"scheduling_delay_ms"`
+	PrefillTimeMs    float64 `json:"prefill_time_ms"` // time to first token (= TTFT); zero until completed
+	DecodeTimeMs     float64 `json:"decode_time_ms"`  // E2E - TTFT; zero until completed
 	SLOClass         string  `json:"slo_class,omitempty"`   // PR10: for per-SLO-class metrics
 	TenantID         string  `json:"tenant_id,omitempty"`  // PR10: for per-tenant fairness
 	HandledBy        string  `json:"handled_by,omitempty"` // #181: instance that processed this request
@@ -28,6 +32,27 @@ type RequestMetrics struct {
 	GatewayQueueDelay float64 `json:"gateway_queue_delay_ms,omitempty"` // #882: time spent in gateway queue (ms)
 	SessionID         string  `json:"session_id,omitempty"`             // #1058: session context for multi-turn metrics
 	RoundIndex        int     `json:"round_index"`                      // #1058: 0 for first round, N for Nth follow-up
+	CompletionReason  string  `json:"completion_reason,omitempty"`      // CompletionReasonStop or CompletionReasonLength; "" until completed
+	PreemptionCount      int     `json:"preemption_count,omitempty"`           // times this request was preempted and re-queued
+	TimeLostToPreemption float64 `json:"time_lost_to_preemption_ms,omitempty"` // per preemption: discarded attempt start to rescheduling (ms)
+}
+
+// Per-request completion reasons for RequestMetrics.CompletionReason, named
+// after vLLM's finish_reason values.
+const (
+	// CompletionReasonStop: the request emitted its full sampled output (EOS).
+	CompletionReasonStop = "stop"
+	// CompletionReasonLength: the request was cut off by a length limit —
+	// SimConfig.MaxOutputTokens or the MaxModelLen boundary.
+	CompletionReasonLength = "length"
+)
+
+// CompletionReasonOf returns why a completed request finished.
+func CompletionReasonOf(req *Request) string {
+	if req.OutputCapped || req.LengthCapped {
+		return CompletionReasonLength
+	}
+	return CompletionReasonStop
 }
 
 // NewRequestMetrics creates a RequestMetrics from a Request and its arrival time.
@@ -82,9 +107,85 @@ type MetricsOutput struct {
 	SchedulingDelayP99Ms     float64          `json:"scheduling_delay_p99_ms"`
 	KVAllocationFailures    int64            `json:"kv_allocation_failures,omitempty"`
 	PreemptionCount         int64            `json:"preemption_count"`
+	DecodePreemptionCount   int64            `json:"decode_preemption_count,omitempty"`
+	WastedPrefillTokens     int64            `json:"wasted_prefill_tokens,omitempty"`
+	SwapOutCount            int64            `json:"swap_out_count,omitempty"`
+	SwapTransferTicks       int64            `json:"swap_transfer_ticks,omitempty"`
+	PriorityHOLBlockingEvents int64          `json:"priority_hol_blocking_events,omitempty"`
+	BatchAffinitySwitchSteps  int64          `json:"batch_affinity_switch_steps,omitempty"`
+	KVBlocksSavedBySharing  int64            `json:"kv_blocks_saved_by_sharing,omitempty"`
 	DroppedUnservable       int              `json:"dropped_unservable"`
+	// DroppedUnservable split by reason (Unservable* constants); omitted when
+	// nothing was dropped (INV-6).
+	DroppedUnservableByReason map[string]int `json:"dropped_unservable_by_reason,omitempty"`
 	LengthCappedRequests    int              `json:"length_capped_requests"`
 	TimedOutRequests        int              `json:"timed_out_requests"`
+	// Bounded wait-queue overflow counts (MaxQueueDepth > 0). omitempty keeps
+	// unbounded-queue output unchanged (INV-6).
+	QueueOverflowRejected int `json:"queue_overflow_rejected,omitempty"`
+	QueueOverflowDropped  int `json:"queue_overflow_dropped,omitempty"`
+	// Anti-starvation promotions (MaxQueueWaitTicks > 0); omitted when zero (INV-6).
+	StarvationPromotions int `json:"starvation_promotions,omitempty"`
+	// Power model (SimConfig.PowerPeakWatts > 0); omitted when zero (INV-6).
+	EnergyJoules        float64 `json:"energy_joules,omitempty"`
+	PowerThrottledSteps int64   `json:"power_throttled_steps,omitempty"`
+	// Roofline compute/memory-bound step summary (SimConfig.RooflineAccounting);
+	// omitted when off (INV-6).
+	Roofline *RooflineSummary `json:"roofline,omitempty"`
+	// Median TTFT and E2E latency (ms). Not serialized, so neither stdout nor the
+	// --metrics-path file changes (INV-6); read in-process by `blis bench-compare`.
+	TTFTP50Ms float64 `json:"-"`
+	E2EP50Ms  float64 `json:"-"`
+	// CacheHitRate is the cluster-mean prefix-cache hit rate. Set by BuildOutput
+	// but written only to the --metrics-path file (like Requests; see
+	// stdoutView) so stdout is unchanged (INV-6).
+	CacheHitRate float64 `json:"cache_hit_rate,omitempty"`
+	// Pooled prefix-cache hit rate per tenant and per SLO class (untagged
+	// requests under ""). File-only, like CacheHitRate.
+	CacheHitRateByTenant   map[string]float64 `json:"cache_hit_rate_by_tenant,omitempty"`
+	CacheHitRateBySLOClass map[string]float64 `json:"cache_hit_rate_by_slo_class,omitempty"`
+	// Share of each completed request's E2E spent before its first token
+	// (TTFT / E2E), mean and P90 over completed requests. File-only, like CacheHitRate.
+	PrefillFractionMean float64 `json:"prefill_fraction_mean,omitempty"`
+	PrefillFractionP90  float64 `json:"prefill_fraction_p90,omitempty"`
+	// How long completed requests held KV blocks, from first block allocation
+	// to release at completion (RequestKVResidencies), mean and P99. File-only,
+	// like CacheHitRate.
+	KVResidencyMeanMs float64 `json:"kv_residency_mean_ms,omitempty"`
+	KVResidencyP99Ms  float64 `json:"kv_residency_p99_ms,omitempty"`
+	// Kendall tau between completed requests' arrival and completion order
+	// (Metrics.ReorderingTau), overall and per instance: 1 = arrival order
+	// kept, lower = more reordering. File-only, like CacheHitRate.
+	ReorderingTau           *float64           `json:"reordering_tau,omitempty"`
+	ReorderingTauByInstance map[string]float64 `json:"reordering_tau_by_instance,omitempty"`
+	// Distribution of completed requests' queue wait (arrival to first
+	// scheduling). File-only, like CacheHitRate.
+	QueueWaitHistogram *QueueWaitHistogram `json:"queue_wait_histogram,omitempty"`
+	// Busy time split by phase (Metrics.TimeBudget). File-only, like CacheHitRate.
+	TimeBudget *TimeBudgetBreakdown `json:"time_budget,omitempty"`
+	// Batch efficiency (Metrics.MeanTokensPerStep, Metrics.GPUIdleFraction):
+	// tokens scheduled per non-empty step and the share of time with an empty
+	// running batch. File-only, like CacheHitRate; the fraction is nil when no
+	// simulated time elapsed.
+	MeanTokensPerStep float64  `json:"mean_tokens_per_step,omitempty"`
+	GPUIdleFraction   *float64 `json:"gpu_idle_fraction,omitempty"`
+	// Block-granularity waste (Metrics.MeanKVRoundingWaste): unfilled
+	// last-block token slots per KV allocation. File-only, like CacheHitRate.
+	KVRoundingWastePerAllocation float64 `json:"kv_rounding_waste_tokens_per_allocation,omitempty"`
+	// Preemption retry cost (Metrics.PreemptionLoss): requests preempted at
+	// least once and their mean TimeLostToPreemption. File-only, like
+	// CacheHitRate.
+	PreemptedRequests          int     `json:"preempted_requests,omitempty"`
+	MeanTimeLostToPreemptionMs float64 `json:"mean_time_lost_to_preemption_ms,omitempty"`
+	// Primary bottleneck label and supporting numbers (ClassifyBottleneck).
+	// File-only, like CacheHitRate.
+	Bottleneck *Bottleneck `json:"bottleneck,omitempty"`
+	// Cluster-level INV-1 buckets (see ClusterConservation). Set by cmd/ for
+	// the cluster output; file-only, like CacheHitRate.
+	ClusterConservation *ClusterConservation `json:"cluster_conservation,omitempty"`
+	// Admission re-attempts by rejected requests under the retry model. Set by
+	// cmd/ for the cluster output; file-only, like CacheHitRate.
+	AdmissionRetries int `json:"admission_retries,omitempty"`
 	Requests                []RequestMetrics `json:"requests,omitempty"`
 	Saturation              interface{}      `json:"saturation,omitempty"` // saturation.Result, using interface{} to avoid import cycle
 	// Goodput fields (issue #1409). Populated by cmd/-side goodput wiring when
@@ -102,6 +203,74 @@ type MetricsOutput struct {
 	// adapter-blind run adds no stdout fields (INV-6, SC-001). encoding/json emits
 	// map string keys in sorted order, giving deterministic output (R2).
 	Adapters map[string]AdapterMetrics `json:"adapters,omitempty"`
+
+	// LatencyCI holds bootstrap 95% confidence intervals for the TTFT/E2E/ITL
+	// percentiles. Populated by cmd/ only when --bootstrap-resamples > 0; nil
+	// otherwise so default output is unchanged (INV-6).
+	LatencyCI *BootstrapCIs `json:"latency_ci,omitempty"`
+
+	// CompletedSeries is the cumulative completed-request count over time.
+	// nil unless --throughput-sample-interval > 0 (INV-6).
+	CompletedSeries *CompletedSeriesOutput `json:"completed_series,omitempty"`
+
+	// KVUsedSeries is the per-interval peak of KV blocks in use.
+	// nil unless --kv-sample-interval > 0 (INV-6).
+	KVUsedSeries *KVUsedSeriesOutput `json:"kv_used_series,omitempty"`
+}
+
+// stdoutView returns o without the file-only fields BuildOutput populates, for
+// the stdout JSON block, which must not change when they are added (INV-6).
+func (o MetricsOutput) stdoutView() MetricsOutput {
+	o.CacheHitRate = 0
+	o.CacheHitRateByTenant = nil
+	o.CacheHitRateBySLOClass = nil
+	o.ClusterConservation = nil
+	o.AdmissionRetries = 0
+	o.TimeBudget = nil
+	return o
+}
+
+// ClusterConservation holds the request counts cluster INV-1 needs beyond the
+// instance buckets of MetricsOutput: Arrived, counted once per request at the
+// cluster before any decision, equals the instance buckets plus every other
+// field here.
+type ClusterConservation struct {
+	Arrived                 int `json:"arrived"`
+	AdmissionRejected       int `json:"admission_rejected"`
+	RoutingRejections       int `json:"routing_rejections"`
+	EncodeRoutingRejections int `json:"encode_routing_rejections"`
+	GatewayQueueDepth       int `json:"gateway_queue_depth"`    // still in the gateway queue at the end
+	GatewayQueueShed        int `json:"gateway_queue_shed"`     // evicted from the gateway queue by a newer request
+	GatewayQueueRejected    int `json:"gateway_queue_rejected"` // could not enter a full gateway queue
+	GatewayEvicted          int `json:"gateway_evicted"`        // evicted in flight from an instance
+	GatewayExpired          int `json:"gateway_expired"`        // timed out in the gateway queue before routing
+	Failed                  int `json:"failed"`                 // lost with a failed instance
+	InPipeline              int `json:"in_pipeline"`            // not yet in a wait queue when the run stopped
+}
+
+// QueueWaitHistogram is a bucketed queue-wait distribution. Counts[i] is the
+// number of requests with wait <= BoundsMs[i] (and above BoundsMs[i-1]); the
+// final entry, Counts[len(BoundsMs)], counts waits above the last bound.
+type QueueWaitHistogram struct {
+	BoundsMs []float64 `json:"bounds_ms"`
+	Counts   []int     `json:"counts"`
+}
+
+// CompletedSeriesOutput is a cumulative completed-request series:
+// Completed[k] is the count as of tick (k+1)*IntervalUs, and the last entry
+// equals completed_requests.
+type CompletedSeriesOutput struct {
+	IntervalUs int64 `json:"interval_us"`
+	Completed  []int `json:"completed"`
+}
+
+// KVUsedSeriesOutput is a KV usage series: Used[k] is the most blocks in use
+// during ticks [k*IntervalUs, (k+1)*IntervalUs). An instance block fills
+// Used; the cluster block fills ByInstance, keyed by instance ID.
+type KVUsedSeriesOutput struct {
+	IntervalUs int64              `json:"interval_us"`
+	Used       []int64            `json:"used_blocks,omitempty"`
+	ByInstance map[string][]int64 `json:"by_instance,omitempty"`
 }
 
 // AdapterMetrics is the per-adapter aggregate section
@@ -144,6 +313,44 @@ func CalculatePercentile[T IntOrFloat64](data []T, p float64) float64 {
 	}
 }
 
+// PercentileMethod selects how a percentile is read off sorted data.
+type PercentileMethod string
+
+const (
+	// PercentileLinear interpolates between the two ranks bracketing p/100·(n-1)
+	// (numpy's default, used by vLLM's benchmark harness). This is the default.
+	PercentileLinear PercentileMethod = "linear"
+	// PercentileNearestRank returns the smallest value with at least p% of the
+	// data at or below it: data[ceil(p/100·n)-1]. Always an observed sample.
+	PercentileNearestRank PercentileMethod = "nearest-rank"
+)
+
+// IsValidPercentileMethod returns true if name is a recognized percentile method
+// ("" = linear).
+func IsValidPercentileMethod(name string) bool {
+	switch PercentileMethod(name) {
+	case "", PercentileLinear, PercentileNearestRank:
+		return true
+	}
+	return false
+}
+
+// CalculatePercentileWithMethod is CalculatePercentile with an explicit method.
+// data must be sorted ascending; return values are in milliseconds.
+// An empty method is PercentileLinear, identical to CalculatePercentile.
+func CalculatePercentileWithMethod[T IntOrFloat64](data []T, p float64, method PercentileMethod) float64 {
+	if method != PercentileNearestRank {
+		return CalculatePercentile(data, p)
+	}
+	n := len(data)
+	if n == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100.0*float64(n))) - 1
+	idx = max(0, min(idx, n-1))
+	return float64(data[idx]) / 1000
+}
+
 // CalculateMean is a util function that calculates the mean of a data list
 // return values are in milliseconds
 func CalculateMean[T IntOrFloat64](numbers []T) float64 {
@@ -159,3 +366,75 @@ func CalculateMean[T IntOrFloat64](numbers []T) float64 {
 	return (sum / float64(len(numbers))) / 1000
 }
 
+
+// PercentileCI is a percentile point estimate with its bootstrap confidence
+// interval. All values are in milliseconds, matching CalculatePercentile.
+type PercentileCI struct {
+	Point float64 `json:"point_ms"`
+	Lower float64 `json:"lower_ms"`
+	Upper float64 `json:"upper_ms"`
+}
+
+// LatencyCIs holds bootstrap confidence intervals for the P50/P90/P99 of one
+// latency distribution.
+type LatencyCIs struct {
+	P50 PercentileCI `json:"p50"`
+	P90 PercentileCI `json:"p90"`
+	P99 PercentileCI `json:"p99"`
+}
+
+// BootstrapCIs is the MetricsOutput section reporting bootstrap confidence
+// intervals for TTFT, E2E, and ITL percentiles. Resamples and Seed are echoed so
+// the intervals can be reproduced.
+type BootstrapCIs struct {
+	Confidence float64    `json:"confidence"`
+	Resamples  int        `json:"resamples"`
+	Seed       int64      `json:"seed"`
+	TTFT       LatencyCIs `json:"ttft"`
+	E2E        LatencyCIs `json:"e2e"`
+	ITL        LatencyCIs `json:"itl"`
+}
+
+// BootstrapConfidence is the coverage of the intervals computed by BootstrapPercentileCI.
+const BootstrapConfidence = 0.95
+
+// BootstrapPercentileCI returns the p-th percentile of data together with a 95%
+// percentile-bootstrap confidence interval: data is resampled with replacement
+// resamples times using rng, and the interval is the 2.5th/97.5th percentile of
+// the resampled estimates. Every percentile uses method, so the point estimate
+// matches the one BuildOutput reports. data must be sorted ascending (as for
+// CalculatePercentile) and is not modified. Returns the zero value for empty
+// data or resamples <= 0.
+func BootstrapPercentileCI[T IntOrFloat64](data []T, p float64, resamples int, rng *rand.Rand, method PercentileMethod) PercentileCI {
+	n := len(data)
+	if n == 0 || resamples <= 0 {
+		return PercentileCI{}
+	}
+	estimates := make([]float64, resamples)
+	sample := make([]T, n)
+	for r := 0; r < resamples; r++ {
+		for i := range sample {
+			sample[i] = data[rng.Intn(n)]
+		}
+		slices.Sort(sample)
+		estimates[r] = CalculatePercentileWithMethod(sample, p, method)
+	}
+	slices.Sort(estimates)
+	alpha := (1 - BootstrapConfidence) / 2 * 100
+	// estimates are already in ms; CalculatePercentile divides by 1000, so scale back up.
+	return PercentileCI{
+		Point: CalculatePercentileWithMethod(data, p, method),
+		Lower: CalculatePercentileWithMethod(estimates, alpha, method) * 1000,
+		Upper: CalculatePercentileWithMethod(estimates, 100-alpha, method) * 1000,
+	}
+}
+
+// bootstrapLatencyCIs computes P50/P90/P99 intervals for one sorted latency slice.
+// The three percentiles share rng so the stream consumed is fixed per call.
+func bootstrapLatencyCIs[T IntOrFloat64](sorted []T, resamples int, rng *rand.Rand, method PercentileMethod) LatencyCIs {
+	return LatencyCIs{
+		P50: BootstrapPercentileCI(sorted, 50, resamples, rng, method),
+		P90: BootstrapPercentileCI(sorted, 90, resamples, rng, method),
+		P99: BootstrapPercentileCI(sorted, 99, resamples, rng, method),
+	}
+}
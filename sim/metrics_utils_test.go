@@ -2,6 +2,10 @@ package sim
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -170,6 +174,63 @@ func TestCalculatePercentile_SingleElement_ReturnsScaled(t *testing.T) {
 	}
 }
 
+// TestCalculatePercentileWithMethod_HandComputed compares both methods on 1..10 ms
+// (stored in ticks) against hand-computed values.
+// Linear: rank = p/100·(n-1), interpolated. Nearest-rank: data[ceil(p/100·n)-1].
+func TestCalculatePercentileWithMethod_HandComputed(t *testing.T) {
+	data := []int64{1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000, 10000}
+	tests := []struct {
+		p           float64
+		linear      float64
+		nearestRank float64
+	}{
+		{0, 1, 1},
+		{25, 3.25, 3},
+		{50, 5.5, 5},
+		{90, 9.1, 9},
+		{95, 9.55, 10},
+		{99, 9.91, 10},
+		{100, 10, 10},
+	}
+	for _, tc := range tests {
+		if got := CalculatePercentileWithMethod(data, tc.p, PercentileLinear); math.Abs(got-tc.linear) > 1e-9 {
+			t.Errorf("linear p%v = %v, want %v", tc.p, got, tc.linear)
+		}
+		if got := CalculatePercentileWithMethod(data, tc.p, PercentileNearestRank); math.Abs(got-tc.nearestRank) > 1e-9 {
+			t.Errorf("nearest-rank p%v = %v, want %v", tc.p, got, tc.nearestRank)
+		}
+		// Empty method is the default and matches CalculatePercentile exactly.
+		if got, want := CalculatePercentileWithMethod(data, tc.p, ""), CalculatePercentile(data, tc.p); got != want {
+			t.Errorf("default p%v = %v, want CalculatePercentile %v", tc.p, got, want)
+		}
+	}
+	if got := CalculatePercentileWithMethod([]float64{}, 99, PercentileNearestRank); got != 0 {
+		t.Errorf("nearest-rank on empty input = %v, want 0", got)
+	}
+}
+
+// TestBuildOutput_PercentileMethod verifies Metrics.PercentileMethod reaches the
+// latency percentiles in MetricsOutput.
+func TestBuildOutput_PercentileMethod(t *testing.T) {
+	m := NewMetrics()
+	for i := 1; i <= 10; i++ {
+		id := fmt.Sprintf("r%d", i)
+		m.CompletedRequests++
+		m.RequestTTFTs[id] = float64(i * 1000)
+		m.RequestE2Es[id] = float64(i * 2000)
+	}
+	m.SimEndedTime = 1e6
+
+	if got := m.BuildOutput("", nil).TTFTP90Ms; math.Abs(got-9.1) > 1e-9 {
+		t.Errorf("default TTFTP90Ms = %v, want 9.1 (linear)", got)
+	}
+	m.PercentileMethod = PercentileNearestRank
+	out := m.BuildOutput("", nil)
+	if out.TTFTP90Ms != 9 || out.E2EP90Ms != 18 {
+		t.Errorf("nearest-rank TTFTP90Ms=%v E2EP90Ms=%v, want 9 and 18", out.TTFTP90Ms, out.E2EP90Ms)
+	}
+}
+
 // TestMetricsOutput_GoodputFields_OmittedWhenZero verifies BC-10: zero-valued
 // goodput fields are absent from the JSON to avoid breaking existing consumers.
 func TestMetricsOutput_GoodputFields_OmittedWhenZero(t *testing.T) {
@@ -206,3 +267,114 @@ func TestMetricsOutput_GoodputFields_PresentWhenSet(t *testing.T) {
 		}
 	}
 }
+
+// newBootstrapTestMetrics returns metrics for 200 completed requests whose
+// TTFT/E2E follow a skewed ramp, so P99 has visibly wider intervals than P50.
+func newBootstrapTestMetrics() *Metrics {
+	m := NewMetrics()
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("request_%d", i)
+		ttft := float64(1000 + (i*i)%5000)
+		m.RequestTTFTs[id] = ttft
+		m.RequestE2Es[id] = ttft + float64(10000+i*37)
+		m.AllITLs = append(m.AllITLs, int64(500+(i*13)%400))
+		m.CompletedRequests++
+	}
+	return m
+}
+
+// TestBootstrapLatencyCIs_BracketsPointEstimate verifies every interval contains
+// its point estimate and that the point estimate matches CalculatePercentile.
+func TestBootstrapLatencyCIs_BracketsPointEstimate(t *testing.T) {
+	m := newBootstrapTestMetrics()
+
+	ci := m.BootstrapLatencyCIs(500, 42)
+	if ci == nil {
+		t.Fatal("BootstrapLatencyCIs returned nil for populated metrics")
+	}
+
+	sortedTTFTs := make([]float64, 0, len(m.RequestTTFTs))
+	for _, v := range m.RequestTTFTs {
+		sortedTTFTs = append(sortedTTFTs, v)
+	}
+	sort.Float64s(sortedTTFTs)
+	if want := CalculatePercentile(sortedTTFTs, 99); ci.TTFT.P99.Point != want {
+		t.Errorf("TTFT P99 point = %v, want %v (CalculatePercentile)", ci.TTFT.P99.Point, want)
+	}
+
+	for name, l := range map[string]LatencyCIs{"ttft": ci.TTFT, "e2e": ci.E2E, "itl": ci.ITL} {
+		for pName, p := range map[string]PercentileCI{"p50": l.P50, "p90": l.P90, "p99": l.P99} {
+			if p.Lower > p.Point || p.Point > p.Upper {
+				t.Errorf("%s %s: interval [%v, %v] does not bracket point %v", name, pName, p.Lower, p.Upper, p.Point)
+			}
+			if p.Lower >= p.Upper {
+				t.Errorf("%s %s: degenerate interval [%v, %v] for non-constant data", name, pName, p.Lower, p.Upper)
+			}
+		}
+	}
+}
+
+// TestBootstrapLatencyCIs_ReproducibleUnderFixedSeed verifies determinism (INV-6):
+// the same seed yields identical intervals, a different seed yields different ones.
+func TestBootstrapLatencyCIs_ReproducibleUnderFixedSeed(t *testing.T) {
+	m := newBootstrapTestMetrics()
+
+	a := m.BootstrapLatencyCIs(300, 7)
+	b := m.BootstrapLatencyCIs(300, 7)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed produced different intervals:\n%+v\n%+v", a, b)
+	}
+
+	c := m.BootstrapLatencyCIs(300, 8)
+	if reflect.DeepEqual(a.TTFT, c.TTFT) {
+		t.Error("different seeds produced identical TTFT intervals")
+	}
+}
+
+// TestBootstrapLatencyCIs_NearestRank_MatchesBuildOutput verifies the
+// intervals honor Metrics.PercentileMethod: each point estimate equals the
+// percentile BuildOutput reports, and every bound is an observed sample.
+func TestBootstrapLatencyCIs_NearestRank_MatchesBuildOutput(t *testing.T) {
+	m := newBootstrapTestMetrics()
+	m.PercentileMethod = PercentileNearestRank
+	m.SimEndedTime = 1_000_000
+
+	ci := m.BootstrapLatencyCIs(300, 42)
+	out := m.BuildOutput("test", nil)
+	if ci.TTFT.P99.Point != out.TTFTP99Ms || ci.E2E.P90.Point != out.E2EP90Ms || ci.ITL.P99.Point != out.ITLP99Ms {
+		t.Errorf("points TTFT P99 %v, E2E P90 %v, ITL P99 %v; BuildOutput reports %v, %v, %v",
+			ci.TTFT.P99.Point, ci.E2E.P90.Point, ci.ITL.P99.Point, out.TTFTP99Ms, out.E2EP90Ms, out.ITLP99Ms)
+	}
+	linear := newBootstrapTestMetrics().BootstrapLatencyCIs(300, 42)
+	if ci.TTFT.P99.Point == linear.TTFT.P99.Point {
+		t.Fatalf("nearest-rank and linear TTFT P99 both %v; test data does not distinguish the methods", ci.TTFT.P99.Point)
+	}
+	observed := make(map[float64]bool, len(m.RequestTTFTs))
+	for _, v := range m.RequestTTFTs {
+		observed[v/1000] = true
+	}
+	for name, p := range map[string]PercentileCI{"p50": ci.TTFT.P50, "p90": ci.TTFT.P90, "p99": ci.TTFT.P99} {
+		if !observed[p.Lower] || !observed[p.Upper] {
+			t.Errorf("TTFT %s interval [%v, %v]: bounds are not observed samples under nearest-rank", name, p.Lower, p.Upper)
+		}
+	}
+}
+
+func TestBootstrapLatencyCIs_DisabledOrEmpty_ReturnsNil(t *testing.T) {
+	if ci := newBootstrapTestMetrics().BootstrapLatencyCIs(0, 42); ci != nil {
+		t.Errorf("resamples=0: got %+v, want nil", ci)
+	}
+	if ci := NewMetrics().BootstrapLatencyCIs(100, 42); ci != nil {
+		t.Errorf("no completed requests: got %+v, want nil", ci)
+	}
+}
+
+func TestMetricsOutput_LatencyCI_OmittedWhenNil(t *testing.T) {
+	data, err := json.Marshal(MetricsOutput{})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "latency_ci") {
+		t.Errorf("latency_ci must be omitted when nil, got %s", data)
+	}
+}
@@ -0,0 +1,65 @@
+package sim
+
+import "testing"
+
+// runLowLoad runs 20 requests arriving every 10ms, each needing 5 steps, with
+// the given min-batch-fill wait.
+func runLowLoad(t *testing.T, minFill int, maxWait int64) *Simulator {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.MinBatchFill = minFill
+	cfg.BatchFillMaxWaitTicks = maxWait
+	s := newFixedStepSimulator(t, cfg)
+	runToCompletion(t, s, uniformRequests(20, 10, 5, 10000))
+	return s
+}
+
+func meanBatchSize(s *Simulator) float64 {
+	var sum int
+	for _, n := range s.Metrics.NumRunningBatchRequests {
+		sum += n
+	}
+	return float64(sum) / float64(len(s.Metrics.NumRunningBatchRequests))
+}
+
+// TestMinBatchFill_LowLoad_LargerBatchesFewerStepsHigherTTFT verifies that at
+// low load the min-batch-fill wait groups arrivals into larger batches, taking
+// fewer steps at the cost of higher TTFT.
+func TestMinBatchFill_LowLoad_LargerBatchesFewerStepsHigherTTFT(t *testing.T) {
+	// GIVEN arrivals spaced wider than a request's 5ms service time
+	plain := runLowLoad(t, 0, 0)
+
+	// WHEN an idle instance waits up to 40ms for 4 queued requests
+	held := runLowLoad(t, 4, 40000)
+
+	// THEN without the wait every request runs alone: 20 × 5 steps
+	if plain.stepCount != 100 || meanBatchSize(plain) != 1 {
+		t.Errorf("plain: steps=%d mean batch=%.2f, want 100 and 1", plain.stepCount, meanBatchSize(plain))
+	}
+	// AND with it requests run four at a time: 5 batches × 5 steps
+	if held.stepCount != 25 || meanBatchSize(held) != 4 {
+		t.Errorf("held: steps=%d mean batch=%.2f, want 25 and 4", held.stepCount, meanBatchSize(held))
+	}
+	// AND TTFT grows by the time spent waiting for the batch to fill
+	if held.Metrics.TTFTSum <= plain.Metrics.TTFTSum {
+		t.Errorf("TTFTSum: held=%d plain=%d, want held > plain", held.Metrics.TTFTSum, plain.Metrics.TTFTSum)
+	}
+}
+
+// TestMinBatchFill_MaxWaitExpires_LaunchesPartialBatch verifies that a hold
+// never outlasts BatchFillMaxWaitTicks: with a fill target that is never
+// reached, each request launches alone once it has waited the max delay.
+func TestMinBatchFill_MaxWaitExpires_LaunchesPartialBatch(t *testing.T) {
+	// GIVEN a fill target of 100 and a 2ms max wait, below the 10ms arrival gap
+	s := runLowLoad(t, 100, 2000)
+
+	// THEN each request's first step starts exactly 2ms after arrival
+	for id, wait := range s.Metrics.RequestQueueWaits {
+		if wait != 2000 {
+			t.Errorf("%s queue wait = %d, want 2000", id, wait)
+		}
+	}
+	if s.stepCount != 100 {
+		t.Errorf("steps = %d, want 100 (requests still run alone)", s.stepCount)
+	}
+}
@@ -19,8 +19,27 @@ type ModelConfig struct {
 	DenseIntermediateDim int    `json:"intermediate_size_mlp"`            // Dense layer FFN dimension; 0 = use IntermediateDim. For models like Scout where dense layers have different FFN size than MoE expert FFN.
 	HiddenAct           string  `json:"hidden_act"`                       // Activation function (e.g. "silu", "gelu", "relu"); used by KV capacity (3-matrix SwiGLU detection), reserved for future roofline per-activation tuning
 	WeightBytesPerParam float64 `json:"weight_bytes_per_param,omitempty"` // Quantized weight precision (bytes/param); 0 = not set, use BytesPerParam. Auto-detected from quantization_config or model name conventions.
+	ComputeDtype        string  `json:"compute_dtype,omitempty"`          // GEMM precision (ComputeDtype* constants); "" = infer from weight precision
+	KVBytesPerParam     float64 `json:"kv_bytes_per_param,omitempty"`     // KV cache precision (bytes/element); 0 = not set, use BytesPerParam
 }
 
+// Compute dtypes for ModelConfig.ComputeDtype: the precision GEMMs execute in,
+// which selects the roofline peak FLOP rate.
+const (
+	ComputeDtypeBF16 = "bf16"
+	ComputeDtypeFP16 = "fp16"
+	ComputeDtypeFP8  = "fp8"
+	ComputeDtypeINT8 = "int8"
+)
+
+var validComputeDtypes = map[string]bool{"": true, ComputeDtypeBF16: true, ComputeDtypeFP16: true, ComputeDtypeFP8: true, ComputeDtypeINT8: true}
+
+// IsValidComputeDtype returns true if name is a recognized compute dtype ("" = infer).
+func IsValidComputeDtype(name string) bool { return validComputeDtypes[name] }
+
+// ValidComputeDtypeNames returns sorted valid compute dtype names (excluding empty).
+func ValidComputeDtypeNames() []string { return validNamesList(validComputeDtypes) }
+
 // EffectiveWeightBytesPerParam returns the bytes-per-parameter to use for
 // weight memory calculations. Returns WeightBytesPerParam when explicitly set
 // (> 0), otherwise falls back to BytesPerParam (the compute/activation dtype).
@@ -33,6 +52,15 @@ func (mc ModelConfig) EffectiveWeightBytesPerParam() float64 {
 	return mc.BytesPerParam
 }
 
+// EffectiveKVBytesPerParam returns the bytes per KV cache element: KVBytesPerParam
+// when explicitly set (> 0, e.g. 1.0 for an FP8 KV cache), otherwise BytesPerParam.
+func (mc ModelConfig) EffectiveKVBytesPerParam() float64 {
+	if mc.KVBytesPerParam > 0 {
+		return mc.KVBytesPerParam
+	}
+	return mc.BytesPerParam
+}
+
 // MoEMinExperts is the minimum NumLocalExperts for a model to be treated as MoE.
 // It is the single source of truth for the MoE-vs-dense boundary across BLIS:
 // the detection predicate (IsMoE), the parse-time expert-count resolver
@@ -0,0 +1,56 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// runPrefixSequence runs requests with an identical 72-token prompt, one at a
+// time, and returns the per-tenant prefix-cache counts. Each request is tagged
+// with its own TenantID so its hits and misses can be read back separately.
+func runPrefixSequence(t *testing.T, noCache []bool) map[string]CacheHitCounts {
+	t.Helper()
+	cfg := newTestSimConfig()
+	s := newFixedStepSimulator(t, cfg)
+	prompt := make([]TokenID, 72) // 4 full blocks + a partial tail
+	for i := range prompt {
+		prompt[i] = TokenID(i + 1)
+	}
+	requests := uniformRequests(len(noCache), 0, 4, 1_000_000) // far apart: each finishes before the next
+	for i, req := range requests {
+		req.TenantID = fmt.Sprintf("tenant_%d", i)
+		req.InputTokens = append([]TokenID{}, prompt...)
+		req.NoCache = noCache[i]
+	}
+	runToCompletion(t, s, requests)
+	return CacheHitCountsBy(s.KVCache.CacheHitCountsByGroup(), func(g CacheGroup) string { return g.TenantID })
+}
+
+// TestNoCache_IdenticalPrefixCached_IncursFullMiss verifies that a cache-bypass
+// request does not reuse a prefix already in the cache, while a normal request
+// after it still does.
+func TestNoCache_IdenticalPrefixCached_IncursFullMiss(t *testing.T) {
+	// GIVEN a normal request warms the prefix, then a NoCache and a normal request repeat it
+	counts := runPrefixSequence(t, []bool{false, true, false})
+
+	// THEN the NoCache request allocates all 5 prompt blocks fresh, like the cold first request
+	if got := counts["tenant_1"]; got != counts["tenant_0"] || got.Hits != 0 {
+		t.Errorf("NoCache request: %+v, want full miss %+v", got, counts["tenant_0"])
+	}
+	// AND the later normal request still hits the warm prefix
+	if got := counts["tenant_2"]; got.Hits == 0 {
+		t.Errorf("normal request after NoCache: %+v, want prefix hits", got)
+	}
+}
+
+// TestNoCache_DoesNotPopulatePrefixIndex verifies that a cache-bypass request
+// leaves nothing behind for later requests with the same prompt.
+func TestNoCache_DoesNotPopulatePrefixIndex(t *testing.T) {
+	// GIVEN a NoCache request runs first on a cold cache
+	counts := runPrefixSequence(t, []bool{true, false})
+
+	// THEN the identical normal request that follows finds no cached prefix
+	if got := counts["tenant_1"]; got.Hits != 0 {
+		t.Errorf("request after NoCache: %d hits, want 0 (NoCache blocks must not be indexed)", got.Hits)
+	}
+}
@@ -0,0 +1,175 @@
+package sim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// OTLP span export: each completed request's lifecycle as one trace in the
+// OTLP/JSON encoding (an ExportTraceServiceRequest), importable into Jaeger
+// and other OpenTelemetry backends. Span times are simulation ticks converted
+// to nanoseconds since a zero epoch, so a run starts at 1970-01-01T00:00:00Z.
+//
+// Each trace is a "request" root span from arrival to completion with three
+// children: "queued" (arrival → first scheduled), "prefill" (first scheduled
+// → first token) and "decode" (first token → completion). Timings come from
+// the per-request maps Metrics already records (RequestQueueWaits,
+// RequestTTFTs, RequestE2Es); IDs are hashes of the request ID, so exports of
+// the same run are byte-identical.
+
+// OTLPServiceName is the service.name resource attribute of exported spans.
+const OTLPServiceName = "blis"
+
+// otlpSpanKindInternal is SPAN_KIND_INTERNAL in the OTLP enum.
+const otlpSpanKindInternal = 1
+
+// OTLPExport is the top-level OTLP/JSON trace document.
+type OTLPExport struct {
+	ResourceSpans []OTLPResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPResourceSpans groups the spans of one resource (the simulator).
+type OTLPResourceSpans struct {
+	Resource   OTLPResource     `json:"resource"`
+	ScopeSpans []OTLPScopeSpans `json:"scopeSpans"`
+}
+
+// OTLPResource describes the span producer.
+type OTLPResource struct {
+	Attributes []OTLPAttribute `json:"attributes"`
+}
+
+// OTLPScopeSpans groups spans by instrumentation scope.
+type OTLPScopeSpans struct {
+	Scope OTLPScope  `json:"scope"`
+	Spans []OTLPSpan `json:"spans"`
+}
+
+// OTLPScope names the instrumentation scope.
+type OTLPScope struct {
+	Name string `json:"name"`
+}
+
+// OTLPSpan is one span. Timestamps are decimal strings of nanoseconds, as the
+// OTLP/JSON encoding requires for 64-bit integers.
+type OTLPSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []OTLPAttribute `json:"attributes,omitempty"`
+}
+
+// OTLPAttribute is a key/value attribute.
+type OTLPAttribute struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+// OTLPAnyValue holds one attribute value; exactly one field is set. Integers
+// are decimal strings per the OTLP/JSON encoding.
+type OTLPAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func otlpString(key, v string) OTLPAttribute {
+	return OTLPAttribute{Key: key, Value: OTLPAnyValue{StringValue: &v}}
+}
+
+func otlpInt(key string, v int64) OTLPAttribute {
+	s := strconv.FormatInt(v, 10)
+	return OTLPAttribute{Key: key, Value: OTLPAnyValue{IntValue: &s}}
+}
+
+// otlpID returns the first n bytes of SHA-256(parts...) as lowercase hex.
+func otlpID(n int, parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:n])
+}
+
+// ticksToUnixNano converts a tick (μs) to an OTLP nanosecond timestamp.
+func ticksToUnixNano(tick int64) string {
+	return strconv.FormatInt(tick*1000, 10)
+}
+
+// BuildOTLPTrace returns the span trees of every completed request in m,
+// ordered by request ID.
+func BuildOTLPTrace(m *Metrics) OTLPExport {
+	spans := make([]OTLPSpan, 0, 4*len(m.RequestE2Es))
+	for _, id := range sortedRequestIDs(m.Requests) {
+		e2e, completed := m.RequestE2Es[id]
+		if !completed || e2e <= 0 {
+			continue
+		}
+		rm := m.Requests[id]
+		arrival := int64(rm.ArrivedAt*1e6 + 0.5) // seconds → ticks
+		scheduled := arrival + m.RequestQueueWaits[id]
+		firstToken := arrival + int64(m.RequestTTFTs[id])
+		done := arrival + int64(e2e)
+
+		traceID := otlpID(16, id)
+		rootID := otlpID(8, id, "request")
+		attrs := []OTLPAttribute{
+			otlpString("request.id", id),
+			otlpInt("request.input_tokens", int64(rm.NumPrefillTokens)),
+			otlpInt("request.output_tokens", int64(rm.NumDecodeTokens)),
+		}
+		if rm.HandledBy != "" {
+			attrs = append(attrs, otlpString("instance.id", rm.HandledBy))
+		}
+		if rm.SLOClass != "" {
+			attrs = append(attrs, otlpString("slo.class", rm.SLOClass))
+		}
+		if rm.TenantID != "" {
+			attrs = append(attrs, otlpString("tenant.id", rm.TenantID))
+		}
+		if rm.Model != "" {
+			attrs = append(attrs, otlpString("model", rm.Model))
+		}
+		spans = append(spans, OTLPSpan{
+			TraceID: traceID, SpanID: rootID, Name: "request", Kind: otlpSpanKindInternal,
+			StartTimeUnixNano: ticksToUnixNano(arrival), EndTimeUnixNano: ticksToUnixNano(done),
+			Attributes: attrs,
+		})
+		phases := []struct {
+			name       string
+			start, end int64
+		}{
+			{"queued", arrival, scheduled},
+			{"prefill", scheduled, firstToken},
+			{"decode", firstToken, done},
+		}
+		for _, p := range phases {
+			spans = append(spans, OTLPSpan{
+				TraceID: traceID, SpanID: otlpID(8, id, p.name), ParentSpanID: rootID,
+				Name: p.name, Kind: otlpSpanKindInternal,
+				StartTimeUnixNano: ticksToUnixNano(p.start), EndTimeUnixNano: ticksToUnixNano(p.end),
+			})
+		}
+	}
+	return OTLPExport{ResourceSpans: []OTLPResourceSpans{{
+		Resource:   OTLPResource{Attributes: []OTLPAttribute{otlpString("service.name", OTLPServiceName)}},
+		ScopeSpans: []OTLPScopeSpans{{Scope: OTLPScope{Name: OTLPServiceName}, Spans: spans}},
+	}}}
+}
+
+// WriteOTLPTrace writes BuildOTLPTrace(m) to w as indented JSON.
+func WriteOTLPTrace(w io.Writer, m *Metrics) error {
+	data, err := json.MarshalIndent(BuildOTLPTrace(m), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
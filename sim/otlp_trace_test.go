@@ -0,0 +1,76 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+// TestBuildOTLPTrace_CompletedRequests_CausalSpanTrees verifies that every
+// completed request exports a root span with queued/prefill/decode children
+// whose timestamps respect causality.
+func TestBuildOTLPTrace_CompletedRequests_CausalSpanTrees(t *testing.T) {
+	// GIVEN 6 requests contending for 2 batch slots, so some queue
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(2, 2048, 0)
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	injectRequests(s, uniformRequests(6, 32, 8, 100))
+	s.Run()
+
+	// WHEN exporting the OTLP trace (through JSON, as a consumer would read it)
+	var buf bytes.Buffer
+	if err := WriteOTLPTrace(&buf, s.Metrics); err != nil {
+		t.Fatalf("WriteOTLPTrace: %v", err)
+	}
+	var exp OTLPExport
+	if err := json.Unmarshal(buf.Bytes(), &exp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	// THEN each completed request has one root and three children in its trace
+	spans := exp.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 4*s.Metrics.CompletedRequests || s.Metrics.CompletedRequests != 6 {
+		t.Fatalf("got %d spans for %d completed requests, want 4 each", len(spans), s.Metrics.CompletedRequests)
+	}
+	ns := func(v string) int64 {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			t.Fatalf("bad timestamp %q: %v", v, err)
+		}
+		return n
+	}
+	queued := 0
+	for i := 0; i < len(spans); i += 4 {
+		root := spans[i]
+		if root.Name != "request" || root.ParentSpanID != "" || len(root.TraceID) != 32 || len(root.SpanID) != 16 {
+			t.Fatalf("span %d: want a request root span with OTLP-sized IDs, got %+v", i, root)
+		}
+		byName := map[string]OTLPSpan{}
+		for _, c := range spans[i+1 : i+4] {
+			if c.TraceID != root.TraceID || c.ParentSpanID != root.SpanID {
+				t.Errorf("child %s not parented to root of trace %s", c.Name, root.TraceID)
+			}
+			byName[c.Name] = c
+		}
+		arrival := ns(root.StartTimeUnixNano)
+		scheduled := ns(byName["queued"].EndTimeUnixNano)
+		firstToken := ns(byName["prefill"].EndTimeUnixNano)
+		done := ns(byName["decode"].EndTimeUnixNano)
+		// AND scheduled ≥ arrival, first-token ≥ scheduled, completion ≥ first-token
+		if !(arrival <= scheduled && scheduled <= firstToken && firstToken <= done) {
+			t.Errorf("trace %s: non-causal arrival=%d scheduled=%d first_token=%d done=%d",
+				root.TraceID, arrival, scheduled, firstToken, done)
+		}
+		if ns(byName["prefill"].StartTimeUnixNano) != scheduled || ns(byName["decode"].StartTimeUnixNano) != firstToken ||
+			ns(byName["queued"].StartTimeUnixNano) != arrival || ns(root.EndTimeUnixNano) != done {
+			t.Errorf("trace %s: child spans do not tile the root span", root.TraceID)
+		}
+		if scheduled > arrival {
+			queued++
+		}
+	}
+	if queued == 0 {
+		t.Error("expected some requests to queue with 2 slots for 6 requests")
+	}
+}
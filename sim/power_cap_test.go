@@ -0,0 +1,70 @@
+package sim
+
+import "testing"
+
+// runPowerWorkload runs 32 simultaneous 512-token prompts, enough to fill the
+// 2048-token budget on prefill steps, under the given power model.
+func runPowerWorkload(t *testing.T, idle, peak, capW float64) *Simulator {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.PowerIdleWatts, cfg.PowerPeakWatts, cfg.PowerCapWatts = idle, peak, capW
+	s := newFixedStepSimulator(t, cfg)
+	runToCompletion(t, s, uniformRequests(32, 512, 8, 0))
+	return s
+}
+
+// TestPowerCap_TightCapSlowsStepsAndThroughput verifies that a power cap below
+// the full-load draw stretches saturated steps and lowers throughput, while the
+// power model with no cap reproduces the baseline timeline exactly.
+func TestPowerCap_TightCapSlowsStepsAndThroughput(t *testing.T) {
+	// GIVEN a 100 W idle / 700 W peak instance
+	baseline := runPowerWorkload(t, 0, 0, 0)
+	uncapped := runPowerWorkload(t, 100, 700, 0)
+
+	// WHEN it is capped at 175 W (a full-budget step then runs at half clock)
+	capped := runPowerWorkload(t, 100, 700, 175)
+
+	// THEN the uncapped power model leaves timing untouched and only accounts energy
+	if uncapped.Metrics.SimEndedTime != baseline.Metrics.SimEndedTime {
+		t.Errorf("uncapped SimEndedTime = %d, want baseline %d", uncapped.Metrics.SimEndedTime, baseline.Metrics.SimEndedTime)
+	}
+	if uncapped.Metrics.PowerThrottledSteps != 0 || uncapped.Metrics.EnergyJoules <= 0 {
+		t.Errorf("uncapped: throttled=%d energy=%v, want 0 and > 0", uncapped.Metrics.PowerThrottledSteps, uncapped.Metrics.EnergyJoules)
+	}
+	if baseline.Metrics.EnergyJoules != 0 {
+		t.Errorf("power model off: EnergyJoules = %v, want 0", baseline.Metrics.EnergyJoules)
+	}
+
+	// AND the tight cap throttles steps, stretching the run and cutting throughput
+	if capped.Metrics.PowerThrottledSteps == 0 {
+		t.Fatal("capped: no throttled steps")
+	}
+	if capped.Metrics.SimEndedTime <= baseline.Metrics.SimEndedTime {
+		t.Errorf("capped SimEndedTime = %d, want > baseline %d", capped.Metrics.SimEndedTime, baseline.Metrics.SimEndedTime)
+	}
+	baseOut, capOut := baseline.Metrics.BuildOutput("base", nil), capped.Metrics.BuildOutput("capped", nil)
+	if capOut.ResponsesPerSec >= baseOut.ResponsesPerSec {
+		t.Errorf("capped ResponsesPerSec = %v, want < baseline %v", capOut.ResponsesPerSec, baseOut.ResponsesPerSec)
+	}
+}
+
+func TestNewSimulator_PowerModelValidation(t *testing.T) {
+	tests := []struct {
+		name             string
+		idle, peak, capW float64
+	}{
+		{"negative idle", -1, 700, 0},
+		{"peak not above idle", 300, 300, 0},
+		{"cap without peak", 0, 0, 400},
+		{"cap at idle", 100, 700, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestSimConfig()
+			cfg.PowerIdleWatts, cfg.PowerPeakWatts, cfg.PowerCapWatts = tt.idle, tt.peak, tt.capW
+			if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package sim
+
+import "sort"
+
+// recordAttemptStart marks now as the start of req's current scheduling
+// attempt. If req is returning from a preemption, the time since its
+// discarded attempt began is charged to its TimeLostToPreemption: that
+// attempt's progress and the re-queue wait are both latency it would not
+// have paid without the preemption.
+func (sim *Simulator) recordAttemptStart(req *Request, now int64) {
+	if start, ok := sim.reqPreemptedAttempt[req.ID]; ok {
+		delete(sim.reqPreemptedAttempt, req.ID)
+		if rm, ok := sim.Metrics.Requests[req.ID]; ok {
+			rm.TimeLostToPreemption += float64(now-start) / 1e3
+			sim.Metrics.Requests[req.ID] = rm
+		}
+	}
+	sim.reqAttemptStart[req.ID] = now
+}
+
+// recordRequestPreemption counts a preemption against req and holds the
+// start of its discarded attempt until recordAttemptStart reschedules it.
+func (sim *Simulator) recordRequestPreemption(req *Request) {
+	if rm, ok := sim.Metrics.Requests[req.ID]; ok {
+		rm.PreemptionCount++
+		sim.Metrics.Requests[req.ID] = rm
+	}
+	if start, ok := sim.reqAttemptStart[req.ID]; ok {
+		sim.reqPreemptedAttempt[req.ID] = start
+		delete(sim.reqAttemptStart, req.ID)
+	}
+}
+
+// forgetPreemptionAttempt drops the attempt bookkeeping of a request that
+// completed or was dropped.
+func (sim *Simulator) forgetPreemptionAttempt(id string) {
+	delete(sim.reqAttemptStart, id)
+	delete(sim.reqPreemptedAttempt, id)
+}
+
+// PreemptionLoss returns the number of requests preempted at least once and
+// their mean TimeLostToPreemption in ms (0 when none was preempted). A
+// preempted request still waiting to be rescheduled at the end of the run
+// counts, but its open attempt adds no lost time.
+func (m *Metrics) PreemptionLoss() (preempted int, meanLostMs float64) {
+	lost := make([]float64, 0)
+	for _, rm := range m.Requests {
+		if rm.PreemptionCount > 0 {
+			lost = append(lost, rm.TimeLostToPreemption)
+		}
+	}
+	if len(lost) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(lost) // R2: map order must not reach the float sum
+	return len(lost), CalculateMean(lost)
+}
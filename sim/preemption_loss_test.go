@@ -0,0 +1,64 @@
+package sim
+
+import (
+	"math"
+	"testing"
+)
+
+// runPreemptionLoss runs four requests (64-token prompts, 100 output tokens)
+// arriving together on a KV cache of kvBlocks 16-token blocks and returns the
+// finalized metrics.
+func runPreemptionLoss(t *testing.T, kvBlocks int64) *Metrics {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(kvBlocks, 16, 0, 0, 0, 0)
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	runToCompletion(t, s, distinctPrompts(uniformRequests(4, 64, 100, 0)))
+	return s.Metrics
+}
+
+// TestPreemptionLoss_AccountsForAddedE2E verifies that on a KV cache too small
+// for the whole batch, preempted requests carry a positive PreemptionCount
+// and their E2E exceeds the unconstrained run by about TimeLostToPreemption.
+func TestPreemptionLoss_AccountsForAddedE2E(t *testing.T) {
+	// Each request peaks at 11 blocks; 30 blocks hold two of them at a time.
+	constrained := runPreemptionLoss(t, 30)
+	baseline := runPreemptionLoss(t, 10000)
+
+	if baseline.PreemptionCount != 0 {
+		t.Fatalf("baseline PreemptionCount = %d, want 0", baseline.PreemptionCount)
+	}
+	if constrained.PreemptionCount == 0 {
+		t.Fatal("constrained run preempted nothing; the test needs preemption")
+	}
+	var counted int64
+	for id, rm := range constrained.Requests {
+		counted += int64(rm.PreemptionCount)
+		if rm.PreemptionCount == 0 {
+			if rm.TimeLostToPreemption != 0 {
+				t.Errorf("%s: TimeLostToPreemption = %.3f ms without a preemption", id, rm.TimeLostToPreemption)
+			}
+			continue
+		}
+		if rm.TimeLostToPreemption <= 0 {
+			t.Errorf("%s: preempted %d times but TimeLostToPreemption = %.3f ms", id, rm.PreemptionCount, rm.TimeLostToPreemption)
+			continue
+		}
+		added := (constrained.RequestE2Es[id] - baseline.RequestE2Es[id]) / 1e3
+		t.Logf("%s: preempted %d times, lost %.3f ms, E2E +%.3f ms", id, rm.PreemptionCount, rm.TimeLostToPreemption, added)
+		if math.Abs(added-rm.TimeLostToPreemption) > 0.1*rm.TimeLostToPreemption {
+			t.Errorf("%s: E2E grew by %.3f ms, want about TimeLostToPreemption %.3f ms", id, added, rm.TimeLostToPreemption)
+		}
+	}
+	if counted != constrained.PreemptionCount {
+		t.Errorf("per-request preemptions sum to %d, want Metrics.PreemptionCount %d", counted, constrained.PreemptionCount)
+	}
+
+	preempted, meanLost := constrained.PreemptionLoss()
+	if preempted == 0 || meanLost <= 0 {
+		t.Errorf("PreemptionLoss() = (%d, %.3f), want a positive count and mean", preempted, meanLost)
+	}
+	if n, mean := baseline.PreemptionLoss(); n != 0 || mean != 0 {
+		t.Errorf("baseline PreemptionLoss() = (%d, %.3f), want (0, 0)", n, mean)
+	}
+}
@@ -0,0 +1,60 @@
+package sim
+
+import "math"
+
+// Priority inheritance (PreemptionPriorityInheritance): a waiting request
+// blocked on KV is freed soonest by the running request nearest completion
+// among those less urgent than it, which will release its blocks. Under plain
+// priority preemption that holder is the first victim whenever a running
+// request needs room, so its progress is discarded, it re-queues behind the
+// waiter's competitors, and the waiter stays blocked. With inheritance the
+// holder is ranked at the waiter's priority for victim selection, so the
+// running requests below the waiter are evicted instead and the holder runs
+// to completion.
+
+// priorityHOLHolder returns the running request blocking the most urgent
+// waiting request and that request's priority: among running requests
+// strictly less urgent than the waiter, the one with the fewest tokens left
+// to process (earliest arrival on ties). Returns nil when no waiting request
+// is more urgent than a running one.
+func priorityHOLHolder(waiting, running []*Request) (*Request, float64) {
+	urgent := math.Inf(1)
+	for _, req := range waiting {
+		urgent = min(urgent, req.Priority)
+	}
+	var holder *Request
+	var holderLeft int64
+	for _, req := range running {
+		if req.Priority <= urgent {
+			continue
+		}
+		left := req.InputLen() + int64(len(req.OutputTokens)) - req.ProgressIndex
+		if holder == nil || left < holderLeft || (left == holderLeft && req.ArrivalTime < holder.ArrivalTime) {
+			holder, holderLeft = req, left
+		}
+	}
+	return holder, urgent
+}
+
+// selectInheritanceVictim is selectPriorityVictim with ctx.PriorityInheritor
+// ranked at ctx.InheritedPriority instead of its own priority.
+func selectInheritanceVictim(ctx BatchContext, requests []*Request) int {
+	priority := func(req *Request) float64 {
+		if req == ctx.PriorityInheritor {
+			return min(req.Priority, ctx.InheritedPriority)
+		}
+		return req.Priority
+	}
+	victimIdx := len(requests) - 1
+	victimPri := priority(requests[victimIdx])
+	victimArrival := requests[victimIdx].ArrivalTime
+	for i := len(requests) - 2; i >= 0; i-- {
+		pri := priority(requests[i])
+		if pri > victimPri || (pri == victimPri && requests[i].ArrivalTime > victimArrival) {
+			victimIdx = i
+			victimPri = pri
+			victimArrival = requests[i].ArrivalTime
+		}
+	}
+	return victimIdx
+}
@@ -0,0 +1,78 @@
+package sim
+
+import "sort"
+
+// Request phases reported in probe snapshots.
+const (
+	ProbePhasePrefill = "prefill"
+	ProbePhaseDecode  = "decode"
+)
+
+// ProbeSnapshot captures queue and batch composition at a registered probe tick,
+// after every event with timestamp <= Clock has executed. All fields are freshly
+// allocated per snapshot and safe to hold after the run (e.g. for a visualizer).
+type ProbeSnapshot struct {
+	Clock        int64
+	Queued       []ProbeQueuedRequest  // wait-queue order, head first
+	Running      []ProbeRunningRequest // running-batch order
+	KVUsedBlocks int64
+}
+
+// ProbeQueuedRequest is a waiting request at a probe tick.
+type ProbeQueuedRequest struct {
+	ID         string
+	WaitTimeUs int64 // Clock - ArrivalTime
+}
+
+// ProbeRunningRequest is a running-batch request at a probe tick.
+type ProbeRunningRequest struct {
+	ID            string
+	Phase         string // ProbePhasePrefill or ProbePhaseDecode
+	ProgressIndex int64  // input tokens processed + output tokens generated
+}
+
+// SetProbeTicks registers ticks at which a ProbeSnapshot is recorded, whether
+// events are driven by Run or, in cluster mode, by ProcessNextEvent and
+// Finalize. Must be called before the first event executes. Ticks are sorted
+// and de-duplicated; ticks beyond the horizon are never recorded. Snapshots are observational only and do not
+// schedule events, so registering probes cannot change simulation results (INV-6).
+func (sim *Simulator) SetProbeTicks(ticks []int64) {
+	sorted := append([]int64(nil), ticks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	sim.probeTicks = sorted[:0]
+	for i, t := range sorted {
+		if i == 0 || t != sorted[i-1] {
+			sim.probeTicks = append(sim.probeTicks, t)
+		}
+	}
+	sim.probeSnapshots = nil
+}
+
+// ProbeSnapshots returns the snapshots recorded so far, in tick order.
+func (sim *Simulator) ProbeSnapshots() []ProbeSnapshot { return sim.probeSnapshots }
+
+// recordProbesBefore records a snapshot for every pending probe tick earlier
+// than nextEventTime: the simulator state cannot change until that event fires.
+func (sim *Simulator) recordProbesBefore(nextEventTime int64) {
+	for len(sim.probeTicks) > 0 && sim.probeTicks[0] < nextEventTime && sim.probeTicks[0] <= sim.Horizon {
+		sim.probeSnapshots = append(sim.probeSnapshots, sim.buildProbeSnapshot(sim.probeTicks[0]))
+		sim.probeTicks = sim.probeTicks[1:]
+	}
+}
+
+func (sim *Simulator) buildProbeSnapshot(clock int64) ProbeSnapshot {
+	snap := ProbeSnapshot{Clock: clock, KVUsedBlocks: sim.KVCache.UsedBlocks()}
+	for _, req := range sim.WaitQ.Items() {
+		snap.Queued = append(snap.Queued, ProbeQueuedRequest{ID: req.ID, WaitTimeUs: clock - req.ArrivalTime})
+	}
+	if sim.RunningBatch != nil {
+		for _, req := range sim.RunningBatch.Requests {
+			phase := ProbePhaseDecode
+			if req.ProgressIndex < req.InputLen() {
+				phase = ProbePhasePrefill
+			}
+			snap.Running = append(snap.Running, ProbeRunningRequest{ID: req.ID, Phase: phase, ProgressIndex: req.ProgressIndex})
+		}
+	}
+	return snap
+}
@@ -0,0 +1,91 @@
+package sim
+
+import "testing"
+
+// newProbeTestSimulator builds a small KV-constrained simulator with 12
+// staggered arrivals, so probe ticks see a mix of queued, prefill, and decode
+// requests.
+func newProbeTestSimulator(t *testing.T) *Simulator {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(40, 16, 0, 0, 0, 0)
+	cfg.BatchConfig = NewBatchConfig(4, 256, 0)
+	s := newFixedStepSimulator(t, cfg)
+	injectRequests(s, uniformRequests(12, 96, 20, 1500))
+	return s
+}
+
+func TestSimulator_ProbeSnapshots_MatchStateAtTick(t *testing.T) {
+	probes := []int64{0, 2500, 7000, 12_345, 20_000, 31_000}
+
+	// GIVEN a run with probe ticks registered (unsorted, with a duplicate)
+	s := newProbeTestSimulator(t)
+	s.SetProbeTicks([]int64{20_000, 0, 7000, 2500, 31_000, 12_345, 7000})
+	s.Run()
+
+	// AND an identical simulator stepped manually to each probe tick
+	ref := newProbeTestSimulator(t)
+
+	snaps := s.ProbeSnapshots()
+	if len(snaps) != len(probes) {
+		t.Fatalf("got %d snapshots, want %d", len(snaps), len(probes))
+	}
+	sawRunning, sawQueued := false, false
+	for i, tick := range probes {
+		for ref.HasPendingEvents() && ref.PeekNextEventTime() <= tick {
+			ref.ProcessNextEvent()
+		}
+		snap := snaps[i]
+		// THEN each snapshot is taken at its probe tick
+		if snap.Clock != tick {
+			t.Errorf("snapshot %d: Clock = %d, want %d", i, snap.Clock, tick)
+		}
+		// AND records the batch, queue, and KV state at that tick
+		if len(snap.Running) != ref.BatchSize() {
+			t.Errorf("tick %d: %d running, want BatchSize() = %d", tick, len(snap.Running), ref.BatchSize())
+		}
+		if len(snap.Queued) != ref.QueueDepth() {
+			t.Errorf("tick %d: %d queued, want QueueDepth() = %d", tick, len(snap.Queued), ref.QueueDepth())
+		}
+		if snap.KVUsedBlocks != ref.KVCache.UsedBlocks() {
+			t.Errorf("tick %d: KVUsedBlocks = %d, want %d", tick, snap.KVUsedBlocks, ref.KVCache.UsedBlocks())
+		}
+		for _, q := range snap.Queued {
+			if q.WaitTimeUs < 0 {
+				t.Errorf("tick %d: %s has negative wait time %d", tick, q.ID, q.WaitTimeUs)
+			}
+		}
+		for _, r := range snap.Running {
+			if r.Phase != ProbePhasePrefill && r.Phase != ProbePhaseDecode {
+				t.Errorf("tick %d: %s has phase %q", tick, r.ID, r.Phase)
+			}
+		}
+		sawRunning = sawRunning || len(snap.Running) > 0
+		sawQueued = sawQueued || len(snap.Queued) > 0
+	}
+	if !sawRunning || !sawQueued {
+		t.Errorf("probes saw running=%v queued=%v; workload does not exercise both", sawRunning, sawQueued)
+	}
+}
+
+// TestSimulator_ProbeSnapshots_AreInert verifies probes do not change results (INV-6).
+func TestSimulator_ProbeSnapshots_AreInert(t *testing.T) {
+	plain := newProbeTestSimulator(t)
+	plain.Run()
+
+	probed := newProbeTestSimulator(t)
+	probed.SetProbeTicks([]int64{1000, 5000, 9000, 100_000_000})
+	probed.Run()
+
+	if probed.Metrics.CompletedRequests != plain.Metrics.CompletedRequests ||
+		probed.Metrics.SimEndedTime != plain.Metrics.SimEndedTime ||
+		probed.Metrics.PreemptionCount != plain.Metrics.PreemptionCount {
+		t.Errorf("probed run (completed=%d ended=%d preempted=%d) differs from plain run (completed=%d ended=%d preempted=%d)",
+			probed.Metrics.CompletedRequests, probed.Metrics.SimEndedTime, probed.Metrics.PreemptionCount,
+			plain.Metrics.CompletedRequests, plain.Metrics.SimEndedTime, plain.Metrics.PreemptionCount)
+	}
+	// A probe tick after the last event still records the final state.
+	if n := len(probed.ProbeSnapshots()); n != 4 {
+		t.Errorf("got %d snapshots, want 4", n)
+	}
+}
@@ -49,7 +49,7 @@ func newTestSimulatorForHook(t *testing.T) *Simulator {
 		KVCacheConfig:       NewKVCacheConfig(100, 4, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 1000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 0.5, 0.5}, []float64{100, 0.1, 50}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0, ""),
 	})
 }
 
@@ -138,7 +138,7 @@ func TestSimulator_ProgressHook_FinalSnapshotClockClamped(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(100, 4, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 1000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 0.5, 0.5}, []float64{100, 0.1, 50}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	})
 	sim.InjectArrival(newTestRequest("req-1", 0, 100, int(math.MaxInt16)))
 
@@ -225,7 +225,7 @@ func TestSimulator_ProgressHook_FreshSlicePerCall(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(100, 4, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 1000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 0.5, 0.5}, []float64{100, 0.1, 50}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "test-model", "", 1, 1, false, "", "roofline", 0, ""),
 	})
 	s.InjectArrival(newTestRequest("req-1", 0, 100, int(math.MaxInt16)))
 
@@ -0,0 +1,138 @@
+package sim
+
+import "testing"
+
+// newOverflowTestSimulator builds a single-slot simulator (MaxRunningReqs=1)
+// with a wait queue bounded at depth 2.
+func newOverflowTestSimulator(t *testing.T, policy string) *Simulator {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(1, 2048, 0)
+	cfg.MaxQueueDepth = 2
+	cfg.QueueOverflowPolicy = policy
+	return newFixedStepSimulator(t, cfg)
+}
+
+// injectOverloadBurst injects request_0 at t=0 (occupies the only batch slot) and
+// request_1..request_5 at t=10..50, all arriving while request_0 is still running.
+// Every request carries a far deadline so any TimeoutEvent left behind by an
+// evicted request would be observable.
+func injectOverloadBurst(s *Simulator) {
+	requests := uniformRequests(6, 10, 4, 10)
+	for _, req := range requests {
+		req.Deadline = 1_000_000_000
+	}
+	injectRequests(s, requests)
+}
+
+// TestQueueOverflow_DropOldest_KeepsNewestAndCountsDropped verifies that under
+// overload a depth-2 queue with drop-oldest keeps the two newest arrivals, counts
+// the three evicted requests separately, and never counts them as timed out.
+func TestQueueOverflow_DropOldest_KeepsNewestAndCountsDropped(t *testing.T) {
+	s := newOverflowTestSimulator(t, QueueOverflowDropOldest)
+	injectOverloadBurst(s)
+	s.Run()
+
+	m := s.Metrics
+	if m.QueueOverflowDropped != 3 {
+		t.Errorf("QueueOverflowDropped = %d, want 3", m.QueueOverflowDropped)
+	}
+	if m.QueueOverflowRejected != 0 {
+		t.Errorf("QueueOverflowRejected = %d, want 0 under drop-oldest", m.QueueOverflowRejected)
+	}
+	for _, id := range []string{"request_0", "request_4", "request_5"} {
+		if _, ok := m.RequestE2Es[id]; !ok {
+			t.Errorf("%s should have completed (running or newest queued)", id)
+		}
+	}
+	for _, id := range []string{"request_1", "request_2", "request_3"} {
+		if _, ok := m.RequestE2Es[id]; ok {
+			t.Errorf("%s is among the oldest queued and should have been dropped", id)
+		}
+		if _, ok := s.reqNumComputedTokens[id]; ok {
+			t.Errorf("%s still has computed-token tracking after being dropped", id)
+		}
+	}
+	// The evicted requests' deadlines must not resurrect them as timeouts.
+	if m.TimedOutRequests != 0 {
+		t.Errorf("TimedOutRequests = %d, want 0 (dropped requests must not also time out)", m.TimedOutRequests)
+	}
+	if s.KVCache.UsedBlocks() != 0 {
+		t.Errorf("UsedBlocks = %d after drain, want 0", s.KVCache.UsedBlocks())
+	}
+
+	// INV-1 conservation: every injected request lands in exactly one bucket.
+	out := m.BuildOutput("test", nil)
+	if out.InjectedRequests != 6 || out.CompletedRequests != 3 {
+		t.Errorf("InjectedRequests=%d CompletedRequests=%d, want 6 and 3", out.InjectedRequests, out.CompletedRequests)
+	}
+	if out.QueueOverflowDropped != 3 {
+		t.Errorf("MetricsOutput.QueueOverflowDropped = %d, want 3", out.QueueOverflowDropped)
+	}
+}
+
+// TestQueueOverflow_RejectNew_KeepsOldest verifies the complementary policy: the
+// first two queued requests are served and the later arrivals are rejected.
+func TestQueueOverflow_RejectNew_KeepsOldest(t *testing.T) {
+	s := newOverflowTestSimulator(t, QueueOverflowRejectNew)
+	injectOverloadBurst(s)
+	s.Run()
+
+	m := s.Metrics
+	if m.QueueOverflowRejected != 3 || m.QueueOverflowDropped != 0 {
+		t.Errorf("rejected=%d dropped=%d, want 3 and 0", m.QueueOverflowRejected, m.QueueOverflowDropped)
+	}
+	for _, id := range []string{"request_0", "request_1", "request_2"} {
+		if _, ok := m.RequestE2Es[id]; !ok {
+			t.Errorf("%s should have completed under reject-new", id)
+		}
+	}
+	if m.TimedOutRequests != 0 {
+		t.Errorf("TimedOutRequests = %d, want 0", m.TimedOutRequests)
+	}
+}
+
+// TestQueueOverflow_Unbounded_IsInert verifies INV-6: MaxQueueDepth=0 admits
+// everything regardless of the configured policy.
+func TestQueueOverflow_Unbounded_IsInert(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(1, 2048, 0)
+	cfg.QueueOverflowPolicy = QueueOverflowDropOldest
+	s := newFixedStepSimulator(t, cfg)
+	injectOverloadBurst(s)
+	s.Run()
+
+	if s.Metrics.CompletedRequests != 6 {
+		t.Errorf("CompletedRequests = %d, want 6 with an unbounded queue", s.Metrics.CompletedRequests)
+	}
+	if s.Metrics.QueueOverflowDropped != 0 || s.Metrics.QueueOverflowRejected != 0 {
+		t.Errorf("overflow counters must stay zero when unbounded, got dropped=%d rejected=%d",
+			s.Metrics.QueueOverflowDropped, s.Metrics.QueueOverflowRejected)
+	}
+}
+
+func TestNewSimulator_QueueOverflowValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		depth   int
+		policy  string
+		wantErr bool
+	}{
+		{"default", 0, "", false},
+		{"reject-new", 4, QueueOverflowRejectNew, false},
+		{"drop-oldest", 4, QueueOverflowDropOldest, false},
+		{"negative depth", -1, "", true},
+		{"unknown policy", 4, "drop-newest", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestSimConfig()
+			cfg.MaxQueueDepth = tc.depth
+			cfg.QueueOverflowPolicy = tc.policy
+			_, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("NewSimulator err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
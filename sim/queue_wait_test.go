@@ -0,0 +1,73 @@
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestQueueWait_GrowsUnderContentionAndHistogramCoversCompleted verifies that
+// queue wait is zero for requests scheduled on arrival, grows for requests
+// that must wait for a batch slot, and that the histogram counts every
+// completed request exactly once.
+func TestQueueWait_GrowsUnderContentionAndHistogramCoversCompleted(t *testing.T) {
+	// GIVEN a batch of at most 2 running requests and 6 simultaneous arrivals
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(2, 2048, 0)
+	s := newFixedStepSimulator(t, cfg)
+	const n = 6
+
+	// WHEN the simulation runs to completion
+	runToCompletion(t, s, uniformRequests(n, 32, 10, 0))
+
+	// THEN the first two requests never queue, and later pairs wait longer
+	waits := make([]int64, n)
+	for i := range waits {
+		w, ok := s.Metrics.RequestQueueWaits[fmt.Sprintf("request_%d", i)]
+		if !ok {
+			t.Fatalf("request_%d has no queue wait", i)
+		}
+		waits[i] = w
+	}
+	if waits[0] != 0 || waits[1] != 0 {
+		t.Errorf("first two waits = %v, want 0 (no contention)", waits[:2])
+	}
+	if !(waits[2] > 0 && waits[4] > waits[2]) {
+		t.Errorf("waits = %v, want later requests to wait longer", waits)
+	}
+
+	// AND the histogram buckets every completed request exactly once
+	h := s.Metrics.QueueWaitHistogram()
+	if len(h.Counts) != len(h.BoundsMs)+1 {
+		t.Fatalf("%d counts for %d bounds, want bounds+1", len(h.Counts), len(h.BoundsMs))
+	}
+	total := 0
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total != s.Metrics.CompletedRequests {
+		t.Errorf("histogram total = %d, want CompletedRequests %d", total, s.Metrics.CompletedRequests)
+	}
+	if h.Counts[0] != 2 {
+		t.Errorf("zero-wait bucket = %d, want 2", h.Counts[0])
+	}
+
+	// AND the --metrics-path file exports it
+	outputPath := filepath.Join(t.TempDir(), "results.json")
+	if err := s.Metrics.SaveResults("test", s.Horizon, 10000, outputPath, nil); err != nil {
+		t.Fatalf("SaveResults: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out MetricsOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.QueueWaitHistogram == nil || fmt.Sprint(out.QueueWaitHistogram.Counts) != fmt.Sprint(h.Counts) {
+		t.Errorf("exported histogram = %+v, want counts %v", out.QueueWaitHistogram, h.Counts)
+	}
+}
@@ -0,0 +1,109 @@
+package sim
+
+import "sort"
+
+// ReorderingTau returns the Kendall tau rank correlation between completed
+// requests' arrival order and completion order: 1 when requests finish in the
+// order they arrived, lower the more the scheduler reorders them, -1 when the
+// order is fully reversed. Pairs that arrived at the same time are excluded;
+// pairs that completed at the same time count as in order. Returns false when
+// fewer than two completed requests have distinct arrival times.
+func (m *Metrics) ReorderingTau() (float64, bool) {
+	return reorderingTau(m.arrivalCompletions(func(RequestMetrics) bool { return true }))
+}
+
+// ReorderingTauByInstance returns ReorderingTau over each instance's completed
+// requests (RequestMetrics.HandledBy), omitting instances where it is
+// undefined. Returns nil when no completed request records its instance.
+func (m *Metrics) ReorderingTauByInstance() map[string]float64 {
+	instances := make(map[string]bool)
+	for id := range m.RequestCompletionTimes {
+		if rm, ok := m.Requests[id]; ok && rm.HandledBy != "" {
+			instances[rm.HandledBy] = true
+		}
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+	taus := make(map[string]float64, len(instances))
+	for inst := range instances {
+		rows := m.arrivalCompletions(func(rm RequestMetrics) bool { return rm.HandledBy == inst })
+		if tau, ok := reorderingTau(rows); ok {
+			taus[inst] = tau
+		}
+	}
+	return taus
+}
+
+// arrivalCompletion pairs a completed request's arrival and completion times.
+type arrivalCompletion struct{ arrival, completion float64 }
+
+// arrivalCompletions returns the completed requests accepted by keep.
+func (m *Metrics) arrivalCompletions(keep func(RequestMetrics) bool) []arrivalCompletion {
+	rows := make([]arrivalCompletion, 0, len(m.RequestCompletionTimes))
+	for id, completion := range m.RequestCompletionTimes {
+		if rm, ok := m.Requests[id]; ok && keep(rm) {
+			rows = append(rows, arrivalCompletion{rm.ArrivedAt, completion})
+		}
+	}
+	return rows
+}
+
+// reorderingTau computes ReorderingTau over rows, counting discordant pairs
+// by merge sort in O(n log n). rows is reordered.
+func reorderingTau(rows []arrivalCompletion) (float64, bool) {
+	// Arrival ties sort by completion, so they never count as discordant.
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].arrival != rows[j].arrival {
+			return rows[i].arrival < rows[j].arrival
+		}
+		return rows[i].completion < rows[j].completion
+	})
+
+	pairs := int64(len(rows)) * int64(len(rows)-1) / 2
+	for i := 0; i < len(rows); {
+		j := i
+		for j < len(rows) && rows[j].arrival == rows[i].arrival {
+			j++
+		}
+		tied := int64(j - i)
+		pairs -= tied * (tied - 1) / 2
+		i = j
+	}
+	if pairs <= 0 {
+		return 0, false
+	}
+
+	completions := make([]float64, len(rows))
+	for i, r := range rows {
+		completions[i] = r.completion
+	}
+	discordant := countInversions(completions, make([]float64, len(completions)))
+	return 1 - 2*float64(discordant)/float64(pairs), true
+}
+
+// countInversions sorts xs ascending and returns the number of pairs i < j
+// with xs[i] > xs[j]. buf is scratch space of the same length.
+func countInversions(xs, buf []float64) int64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	mid := len(xs) / 2
+	n := countInversions(xs[:mid], buf[:mid]) + countInversions(xs[mid:], buf[mid:])
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(xs) {
+		if xs[j] < xs[i] {
+			n += int64(mid - i)
+			buf[k] = xs[j]
+			j++
+		} else {
+			buf[k] = xs[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], xs[i:mid])
+	copy(buf[k:], xs[j:])
+	copy(xs, buf)
+	return n
+}
@@ -0,0 +1,99 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestReorderingTau_KnownOrders verifies the tau value on hand-built
+// completions: kept order, reversed order, one swapped pair, and ties.
+func TestReorderingTau_KnownOrders(t *testing.T) {
+	build := func(arrivals, completions []float64) *Metrics {
+		m := NewMetrics()
+		for i := range arrivals {
+			id := fmt.Sprintf("r%d", i)
+			m.Requests[id] = RequestMetrics{ID: id, ArrivedAt: arrivals[i]}
+			m.RequestCompletionTimes[id] = completions[i]
+		}
+		return m
+	}
+	tests := []struct {
+		name                  string
+		arrivals, completions []float64
+		want                  float64
+		ok                    bool
+	}{
+		{"in order", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, 1, true},
+		{"reversed", []float64{1, 2, 3, 4}, []float64{40, 30, 20, 10}, -1, true},
+		{"one adjacent swap of four", []float64{1, 2, 3, 4}, []float64{10, 30, 20, 40}, 1 - 2.0/6, true},
+		{"arrival ties excluded", []float64{1, 1, 2}, []float64{20, 10, 30}, 1, true},
+		{"completion ties in order", []float64{1, 2, 3}, []float64{10, 10, 30}, 1, true},
+		{"single request", []float64{1}, []float64{10}, 0, false},
+		{"all arrivals tied", []float64{1, 1, 1}, []float64{30, 20, 10}, 0, false},
+	}
+	for _, tc := range tests {
+		got, ok := build(tc.arrivals, tc.completions).ReorderingTau()
+		if ok != tc.ok || math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s: ReorderingTau = (%v, %v), want (%v, %v)", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	// Per instance: each instance's requests are ranked on their own, so two
+	// instances in order stay at 1 even though together they interleave.
+	m := build([]float64{1, 2, 3, 4}, []float64{10, 40, 20, 50})
+	for i, inst := range []string{"instance_0", "instance_1", "instance_0", "instance_1"} {
+		rm := m.Requests[fmt.Sprintf("r%d", i)]
+		rm.HandledBy = inst
+		m.Requests[rm.ID] = rm
+	}
+	byInstance := m.ReorderingTauByInstance()
+	if len(byInstance) != 2 || byInstance["instance_0"] != 1 || byInstance["instance_1"] != 1 {
+		t.Errorf("ReorderingTauByInstance = %v, want 1 for both instances", byInstance)
+	}
+	if overall, _ := m.ReorderingTau(); overall >= 1 {
+		t.Errorf("overall ReorderingTau = %v, want < 1 for the interleaved completions", overall)
+	}
+}
+
+// TestReorderingTau_FCFSKeepsOrderSJFReorders runs a bimodal workload, long
+// and short prompts interleaved, through a queue-building instance: FCFS
+// completes requests close to arrival order while SJF pulls the short ones
+// ahead of earlier long ones.
+func TestReorderingTau_FCFSKeepsOrderSJFReorders(t *testing.T) {
+	run := func(scheduler string) float64 {
+		cfg := newTestSimConfig()
+		cfg.BatchConfig = NewBatchConfig(2, 2048, 0)
+		cfg.PolicyConfig = NewPolicyConfig(scheduler, "fcfs", "")
+		s := mustNewSimulator(t, cfg)
+		for i := 0; i < 60; i++ {
+			input := 32
+			if i%2 == 0 {
+				input = 1024
+			}
+			req := newTestRequest(fmt.Sprintf("request_%d", i), int64(i)*2000, input, 16)
+			for j := range req.InputTokens {
+				req.InputTokens[j] = TokenID(i*2000 + j) // no shared prefixes
+			}
+			s.InjectArrival(req)
+		}
+		s.Run()
+		if s.Metrics.CompletedRequests != 60 {
+			t.Fatalf("%s: CompletedRequests = %d, want 60", scheduler, s.Metrics.CompletedRequests)
+		}
+		tau, ok := s.Metrics.ReorderingTau()
+		if !ok {
+			t.Fatalf("%s: ReorderingTau not defined", scheduler)
+		}
+		return tau
+	}
+
+	fcfs, sjf := run("fcfs"), run("sjf")
+	t.Logf("reordering tau: fcfs %.3f, sjf %.3f", fcfs, sjf)
+	if fcfs < 0.95 {
+		t.Errorf("FCFS tau = %.3f, want >= 0.95 (near arrival order)", fcfs)
+	}
+	if sjf > 0.8 {
+		t.Errorf("SJF tau = %.3f, want <= 0.8 (substantial reordering)", sjf)
+	}
+}
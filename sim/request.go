@@ -54,10 +54,17 @@ type Request struct {
 	FinishedStepIdx  int     // Step index when this request finished (running -> completed)
 	NumNewTokens     int     // Number of new tokens to be generated in the current step
 	LengthCapped     bool    // Set when force-completed by runtime MaxModelLen cap (BC-5)
+	OutputCapped     bool    // Set when OutputTokens was truncated to SimConfig.MaxOutputTokens at enqueue
 	ITL              []int64 // List of inter-token latencies
 	Priority         float64 // Instance-level scheduling priority (vLLM convention: lower = more urgent).
-	// Set once at EnqueueRequest/EnqueueDecodeSubRequest via SLOPriorityMap.InvertForVLLM;
-	// not recomputed per step.
+	// Set once at EnqueueRequest/EnqueueDecodeSubRequest from the SLO class (via
+	// SLOPriorityMap.InvertForVLLM) or, under PriorityPolicy "explicit", from
+	// ExplicitPriority; not recomputed per step.
+
+	// ExplicitPriority is a client-supplied numeric priority (higher = more urgent,
+	// cluster convention), independent of SLOClass. Read only under PriorityPolicy
+	// "explicit"; 0 when the workload sets none.
+	ExplicitPriority int
 
 	// Workload metadata (PR10). All fields are zero-value safe for backward compatibility.
 	TenantID        string  // Client/tenant identifier (empty for legacy workloads)
@@ -70,6 +77,7 @@ type Request struct {
 	VideoTokenCount int     // Video input tokens
 	ReasonRatio     float64 // reason_tokens / total_output_tokens (part of OutputTokens, not additional)
 	ClientID        string  // Client identifier from workload spec (empty for legacy/test workloads)
+	IngressPoint    string  // Cluster ingress point the request entered through (empty = untagged)
 	PrefixGroup     string  // Shared prefix group name (empty for no prefix)
 	PrefixLength    int     // Shared prefix token count; 0 = no prefix. Set during workload generation.
 	Streaming       bool    // Whether client expects streaming response
@@ -78,6 +86,28 @@ type Request struct {
 	// Request is used outside the cluster routing pipeline (e.g., direct sim.Simulator tests).
 	AssignedInstance string // Instance ID this request was routed to
 
+	// RemotePrefixBlocks is the number of leading input blocks cached on another
+	// instance, per the cluster's shared prefix index. Blocks beyond the local
+	// prefix hit are fetched at admission instead of recomputed (0 = none).
+	RemotePrefixBlocks int
+	// RemotePrefixOwner is the instance holding those blocks. Its cache is
+	// re-checked when the fetch is charged, so blocks it evicted after routing
+	// are recomputed rather than fetched.
+	RemotePrefixOwner string
+
+	// ParallelSamples is the number of completions decoded from this prompt
+	// (vLLM's n); <= 1 means one. The samples share the prompt's KV blocks
+	// and each holds its own decode blocks (copy-on-write), all released at
+	// completion. KV and decode compute are per-sample (a decode step emits one
+	// token per sample, see Samples); the request is otherwise scheduled and
+	// counted as one sequence.
+	ParallelSamples int
+
+	// NoCache bypasses prefix caching: the KV store neither looks up cached
+	// blocks for this request nor registers its blocks in the prefix index, so
+	// it pays full prefill and leaves no reusable prefix behind.
+	NoCache bool
+
 	// Model tag for multi-model routing (empty = default model).
 	// Phase 0: carried through the pipeline but not read by any routing policy.
 	Model string
@@ -89,6 +119,13 @@ type Request struct {
 	// generation). Set from the owning client/cohort's Adapter during generation.
 	Adapter string
 
+	// BatchAffinityKey groups requests that batch more cheaply together (e.g.
+	// same model variant). Each extra key in a step's batch costs
+	// SimConfig.BatchAffinitySwitchCostUs; AffinityBatchFormation
+	// (SimConfig.BatchAffinityGrouping) co-batches requests of one key. Empty
+	// ("") falls back to Adapter; see AffinityKey.
+	BatchAffinityKey string
+
 	// adapterPinned tracks whether this request currently holds a pin on its
 	// adapter's resident slot (cold-load gate, #1466). Set once when the request
 	// enters the running batch and cleared once at a terminal state, so a
@@ -97,6 +134,16 @@ type Request struct {
 	// is correct for base-model requests and adapter-blind runs.
 	adapterPinned bool
 
+	// starvationPromoted records that StarvationGuardScheduler has already moved
+	// this request to the queue front, so it is counted in
+	// Metrics.StarvationPromotions once even while it stays overdue.
+	starvationPromoted bool
+
+	// inputTokensCounted records that this request's input tokens were added to
+	// Metrics.TotalInputTokens, so a request resubmitted to another instance
+	// after an instance failure is counted once, at its first admission.
+	inputTokensCounted bool
+
 	// Client timeout: absolute tick by which request must complete (0 = no timeout).
 	// Computed during workload generation as ArrivalTime + timeout.
 	Deadline int64
@@ -113,12 +160,29 @@ type Request struct {
 	// Do NOT skip completion accounting for redirected requests.
 	Redirected bool
 
+	// QueueDropped marks a request evicted from a full wait queue by the
+	// drop-oldest overflow policy. Terminal: a TimeoutEvent that later fires
+	// for it is a no-op so the request is not double-counted.
+	QueueDropped bool
+
 	// IsDecodeSubRequest is true when this request was created by PD disaggregation
 	// after KV transfer from a prefill instance. It enters the decode instance with
 	// ProgressIndex already set to len(InputTokens) and KV blocks pre-allocated.
 	// Set by KVTransferCompletedEvent before the request is routed and enqueued.
 	IsDecodeSubRequest bool
 
+	// Suspended marks a decode request rotated out of the running batch by the
+	// fair-decode quantum (SimConfig.DecodeQuantumSteps). It waits in the queue
+	// keeping its KV blocks and ProgressIndex, and resumes decoding when
+	// re-admitted. Cleared on re-admission.
+	Suspended bool
+
+	// SwappedOut marks a request preempted under PreemptionModeSwap: its KV
+	// was copied to host memory and its GPU blocks released, but it keeps its
+	// ProgressIndex and waits in the queue to be swapped back in. Cleared on
+	// re-admission.
+	SwappedOut bool
+
 	// Flow control timestamps (issue #882). Zero when flow control is disabled.
 	GatewayEnqueueTime  int64 // microseconds: when request entered the gateway queue
 	GatewayDispatchTime int64 // microseconds: when request was dispatched from the gateway queue
@@ -147,6 +211,22 @@ func (req *Request) InputLen() int64 {
 	return int64(len(req.InputTokens))
 }
 
+// RemainingOutputBudget returns the client-declared output budget
+// (MaxOutputLen) less the output tokens generated so far, floored at 0.
+// It is the control plane's view of remaining decode work: the budget, never
+// the oracle output length (INV-9). 0 when no budget is declared.
+func (req *Request) RemainingOutputBudget() int64 {
+	generated := max(0, req.ProgressIndex-req.InputLen())
+	return max(0, int64(req.MaxOutputLen)-generated)
+}
+
+// Samples returns the number of completions decoded in parallel from this
+// request's prompt: ParallelSamples, or 1 when it is unset. Latency models
+// multiply a decode step's tokens (and the context they attend to) by it.
+func (req *Request) Samples() int {
+	return max(req.ParallelSamples, 1)
+}
+
 // FullInputTokens returns the full input-token sequence as a flat slice. The slice
 // is already flat today (a view into a session-scoped shared buffer when produced
 // by multi-turn workloads); the accessor exists as a forward-compatible migration
@@ -183,6 +263,16 @@ func (req *Request) InputTokenSlice(start, end int64) []TokenID {
 	return req.InputTokens[start:end:end]
 }
 
+// AffinityKey returns the key batch affinity groups the request by:
+// BatchAffinityKey when set, else its LoRA Adapter, so same-adapter requests
+// co-batch without explicit keys. Base-model requests without a key share "".
+func (req *Request) AffinityKey() string {
+	if req.BatchAffinityKey != "" {
+		return req.BatchAffinityKey
+	}
+	return req.Adapter
+}
+
 // GenerateRandomTokenIDs creates a slice of random token IDs in [0, MaxTokenID).
 // RNG calls: length × Intn(MaxTokenID).
 func GenerateRandomTokenIDs(rng *rand.Rand, length int) []TokenID {
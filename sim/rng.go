@@ -35,8 +35,24 @@ const (
 	// XOR-derived stream. Existing code in sim/cluster/workload.go continues
 	// using SubsystemWorkload unchanged.
 	SubsystemWorkloadGen = "workload-gen"
+
+	// SubsystemBootstrap is the RNG subsystem for post-hoc bootstrap resampling of
+	// latency percentiles. Isolated so enabling confidence intervals never perturbs
+	// any simulation stream.
+	SubsystemBootstrap = "bootstrap"
+
+	// SubsystemStepNoise prefixes the per-instance RNG subsystems of the
+	// step-time noise process (SimConfig.StepNoiseMagnitude); see
+	// SubsystemStepNoiseFor.
+	SubsystemStepNoise = "step-noise"
 )
 
+// SubsystemStepNoiseFor returns the step-noise subsystem name for the
+// instance with the given ID, so each instance draws an independent stream.
+func SubsystemStepNoiseFor(instanceID string) string {
+	return SubsystemStepNoise + "/" + instanceID
+}
+
 // SubsystemInstance returns the subsystem name for instance N.
 // Provides per-instance RNG isolation in multi-replica cluster simulations.
 func SubsystemInstance(id int) string {
@@ -54,13 +70,26 @@ func SubsystemInstance(id int) string {
 // Thread-safety: NOT thread-safe. Must be called from single goroutine.
 type PartitionedRNG struct {
 	key        SimulationKey
+	source     string // random source name (see NewRandSource); "" = stdlib
 	subsystems map[string]*rand.Rand
 }
 
-// NewPartitionedRNG creates a PartitionedRNG from a SimulationKey.
+// NewPartitionedRNG creates a PartitionedRNG from a SimulationKey, backed by
+// the default (stdlib) random source.
 func NewPartitionedRNG(key SimulationKey) *PartitionedRNG {
+	return NewPartitionedRNGWithSource(key, "")
+}
+
+// NewPartitionedRNGWithSource creates a PartitionedRNG whose subsystem RNGs
+// draw from the named random source. Seed derivation is the same for every
+// source. Panics on an unknown source name.
+func NewPartitionedRNGWithSource(key SimulationKey, source string) *PartitionedRNG {
+	if !IsValidRandSource(source) {
+		panic(fmt.Sprintf("NewPartitionedRNGWithSource: unknown random source %q", source))
+	}
 	return &PartitionedRNG{
 		key:        key,
+		source:     source,
 		subsystems: make(map[string]*rand.Rand),
 	}
 }
@@ -86,7 +115,7 @@ func (p *PartitionedRNG) ForSubsystem(name string) *rand.Rand {
 		derivedSeed = int64(p.key) ^ fnv1a64(name)
 	}
 
-	rng := rand.New(rand.NewSource(derivedSeed))
+	rng := NewRand(p.source, derivedSeed)
 	p.subsystems[name] = rng
 	return rng
 }
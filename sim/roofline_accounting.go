@@ -0,0 +1,96 @@
+package sim
+
+// RooflineStepStats accumulates the roofline backend's per-step work when
+// SimConfig.RooflineAccounting is on: one entry per forward pass, with FLOPs
+// and memory bytes counted per GPU (after tensor-parallel sharding), the same
+// quantities the roofline compares to pick the step time. Zero value means no
+// steps were recorded.
+type RooflineStepStats struct {
+	Steps             int64
+	ComputeBoundSteps int64 // compute time >= memory time
+	MemoryBoundSteps  int64
+	TotalFlops        float64
+	TotalBytes        float64
+	// IntensitySum is the sum of per-step arithmetic intensity (FLOPs/byte);
+	// divided by Steps it gives the mean intensity of a step.
+	IntensitySum float64
+	// BusySeconds sums the roofline forward-pass times (max of compute and
+	// memory time), the busy period effective MFU is measured over.
+	BusySeconds float64
+	// PeakFlopCapacity sums each step's time multiplied by the GPU's peak
+	// FLOPs/sec: the FLOPs the busy period could have done at peak. Kept as a
+	// sum rather than a single peak so instances on different GPUs merge.
+	PeakFlopCapacity float64
+}
+
+// Record adds one forward pass with the given FLOPs and bytes, taking
+// stepSeconds on a GPU with peakFlops FLOPs/sec. computeBound reports whether
+// the pass's compute time was at least its memory time.
+func (s *RooflineStepStats) Record(flops, bytes, stepSeconds, peakFlops float64, computeBound bool) {
+	s.Steps++
+	if computeBound {
+		s.ComputeBoundSteps++
+	} else {
+		s.MemoryBoundSteps++
+	}
+	s.TotalFlops += flops
+	s.TotalBytes += bytes
+	if bytes > 0 {
+		s.IntensitySum += flops / bytes
+	}
+	s.BusySeconds += stepSeconds
+	s.PeakFlopCapacity += stepSeconds * peakFlops
+}
+
+// EffectiveMFU returns achieved FLOPs/sec over the busy period as a fraction
+// of peak, or 0 when nothing was recorded.
+func (s RooflineStepStats) EffectiveMFU() float64 {
+	if s.PeakFlopCapacity <= 0 {
+		return 0
+	}
+	return s.TotalFlops / s.PeakFlopCapacity
+}
+
+// Merge adds o's counts into s (cluster aggregation).
+func (s *RooflineStepStats) Merge(o RooflineStepStats) {
+	s.Steps += o.Steps
+	s.ComputeBoundSteps += o.ComputeBoundSteps
+	s.MemoryBoundSteps += o.MemoryBoundSteps
+	s.TotalFlops += o.TotalFlops
+	s.TotalBytes += o.TotalBytes
+	s.IntensitySum += o.IntensitySum
+	s.BusySeconds += o.BusySeconds
+	s.PeakFlopCapacity += o.PeakFlopCapacity
+}
+
+// RooflineSummary is the exported form of RooflineStepStats.
+type RooflineSummary struct {
+	Steps                   int64   `json:"steps"`
+	ComputeBoundFraction    float64 `json:"compute_bound_fraction"`
+	MemoryBoundFraction     float64 `json:"memory_bound_fraction"`
+	MeanArithmeticIntensity float64 `json:"mean_arithmetic_intensity"`
+	TotalFlops              float64 `json:"total_flops"`
+	TotalBytes              float64 `json:"total_bytes"`
+	BusySeconds             float64 `json:"busy_seconds"`
+	// EffectiveMFU is achieved FLOPs/sec over BusySeconds divided by peak.
+	EffectiveMFU float64 `json:"effective_mfu"`
+}
+
+// Summary returns the step-fraction and intensity summary, or nil when no
+// steps were recorded so the output field is omitted (INV-6).
+func (s RooflineStepStats) Summary() *RooflineSummary {
+	if s.Steps == 0 {
+		return nil
+	}
+	n := float64(s.Steps)
+	return &RooflineSummary{
+		Steps:                   s.Steps,
+		ComputeBoundFraction:    float64(s.ComputeBoundSteps) / n,
+		MemoryBoundFraction:     float64(s.MemoryBoundSteps) / n,
+		MeanArithmeticIntensity: s.IntensitySum / n,
+		TotalFlops:              s.TotalFlops,
+		TotalBytes:              s.TotalBytes,
+		BusySeconds:             s.BusySeconds,
+		EffectiveMFU:            s.EffectiveMFU(),
+	}
+}
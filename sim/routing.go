@@ -2,6 +2,7 @@ package sim
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 )
 
@@ -19,15 +20,19 @@ type RoutingSnapshot struct {
 	FreeKVBlocks          int64
 	CacheHitRate          float64
 	InFlightRequests      int     // Requests dispatched to this instance but not yet completed
+	RemainingDecodeTokens int64   // Declared output budget not yet generated across in-transit, queued, and running requests; refreshed with BatchSize
 	PreemptionCount       int64   // Cumulative preemption events since instance start (monotonically increasing; Immediate by default, Periodic when --snapshot-refresh-interval > 0)
+	RecentPreemptionRate  float64 // preemptions/s over the last sim.RecentPreemptionWindowUs; refreshed with PreemptionCount
 	Model                 string  // Model served by this instance; used by buildRouterState() for per-model filtering
 	GPUType               string  // GPU hardware type (e.g. "A100-80GB"); populated by buildRouterState() from instance config
 	TPDegree              int     // Tensor-parallel degree; populated by buildRouterState() from instance config
+	StepTimeEstimate      float64 // μs; the instance's latency-model time for a reference decode step (see step-time scorer); populated by buildRouterState(); 0 if unknown
 	CostPerHour           float64 // Node pool cost in $/hr; populated by buildRouterState() from NodePool.CostPerHour
 	TotalKvCapacityTokens int64   // Total KV cache capacity in tokens (TotalBlocks × BlockSizeTokens); used by V2SaturationAnalyzer
 	KvTokensInUse         int64   // Current KV cache occupancy in tokens (UsedBlocks × BlockSizeTokens); used by V2SaturationAnalyzer
 	TTFT                  float64 // μs; 0 if not yet available
 	ITL                   float64 // μs; 0 if not yet available
+	TTFTP99               float64 // μs; P99 TTFT over the last sim.RecentLatencyWindowSize completions; 0 if none yet
 	DispatchRate          float64 // req/s completed by this instance; 0 if not yet available
 	AvgInTokens           float64 // average input tokens per completed request; 0 if not yet available
 	AvgOutTokens          float64 // average output tokens per completed request; 0 if not yet available
@@ -59,8 +64,11 @@ func NewRoutingSnapshot(id string) RoutingSnapshot {
 }
 
 // RoutingDecision encapsulates the routing decision for a request.
+// An empty TargetInstance (NewNoRouteDecision) means the policy accepts none
+// of the routable instances; the cluster counts the request as a routing
+// rejection (I13).
 type RoutingDecision struct {
-	TargetInstance string             // Instance ID to route to (must match a snapshot ID)
+	TargetInstance string             // Instance ID to route to (must match a snapshot ID); "" = no route
 	Reason         string             // Human-readable explanation
 	Scores         map[string]float64 // Instance ID → composite score (nil for policies without scoring)
 }
@@ -90,6 +98,12 @@ func NewRoutingDecisionWithScores(target string, reason string, scores map[strin
 	}
 }
 
+// NewNoRouteDecision creates a RoutingDecision that routes the request
+// nowhere, for policies whose constraints exclude every routable instance.
+func NewNoRouteDecision(reason string) RoutingDecision {
+	return RoutingDecision{Reason: reason}
+}
+
 // RoutingPolicy decides which instance should handle a request.
 // Implementations receive request and cluster-wide state via *RouterState.
 type RoutingPolicy interface {
@@ -152,6 +166,48 @@ func (ll *LeastLoaded) Route(req *Request, state *RouterState) RoutingDecision {
 	return NewRoutingDecision(snapshots[idx].ID, fmt.Sprintf("least-loaded (load=%d)", minLoad))
 }
 
+// DecodeLoad routes requests to the instance with the fewest remaining decode
+// tokens (RoutingSnapshot.RemainingDecodeTokens), balancing decode work rather
+// than request counts when output lengths vary widely. Ties are broken by
+// lower EffectiveLoad, then randomly when rng is non-nil or by first
+// occurrence (lowest index) when rng is nil.
+type DecodeLoad struct {
+	rng *rand.Rand
+}
+
+// Route implements RoutingPolicy for DecodeLoad.
+func (dl *DecodeLoad) Route(req *Request, state *RouterState) RoutingDecision {
+	snapshots := state.Snapshots
+	if len(snapshots) == 0 {
+		panic("DecodeLoad.Route: empty snapshots")
+	}
+
+	less := func(a, b RoutingSnapshot) bool {
+		if a.RemainingDecodeTokens != b.RemainingDecodeTokens {
+			return a.RemainingDecodeTokens < b.RemainingDecodeTokens
+		}
+		return a.EffectiveLoad() < b.EffectiveLoad()
+	}
+	best := snapshots[0]
+	for _, snap := range snapshots[1:] {
+		if less(snap, best) {
+			best = snap
+		}
+	}
+	var tied []int
+	for i, snap := range snapshots {
+		if !less(best, snap) {
+			tied = append(tied, i)
+		}
+	}
+
+	idx := tied[0]
+	if len(tied) > 1 && dl.rng != nil {
+		idx = tied[dl.rng.Intn(len(tied))]
+	}
+	return NewRoutingDecision(snapshots[idx].ID, fmt.Sprintf("decode-load (remaining=%d)", best.RemainingDecodeTokens))
+}
+
 // observerFunc is called after each routing decision to update stateful scorer state.
 // Used by scorers like prefix-affinity that track routing history.
 type observerFunc func(req *Request, targetInstance string)
@@ -268,29 +324,193 @@ func (ab *AlwaysBusiest) Route(_ *Request, state *RouterState) RoutingDecision {
 	return NewRoutingDecision(target.ID, fmt.Sprintf("always-busiest (load=%d)", maxLoad))
 }
 
+// StaticWeighted routes each request to an instance drawn at random with
+// probability proportional to a fixed per-instance weight, ignoring load.
+// Instances with weight 0, or absent from the weight map, are never chosen;
+// if no positively weighted instance is routable (e.g. all are draining), the
+// request is not routed (NewNoRouteDecision).
+type StaticWeighted struct {
+	weights map[string]float64 // instance ID -> weight
+	rng     *rand.Rand
+}
+
+// NewStaticWeighted creates a StaticWeighted policy from per-instance-ID
+// weights. Panics on invalid weights (see ValidateStaticRoutingWeights) or a
+// nil rng.
+func NewStaticWeighted(weights map[string]float64, rng *rand.Rand) *StaticWeighted {
+	ws := make([]float64, 0, len(weights))
+	for _, w := range weights {
+		ws = append(ws, w)
+	}
+	if err := ValidateStaticRoutingWeights(ws); err != nil {
+		panic(fmt.Sprintf("NewStaticWeighted: %v", err))
+	}
+	if rng == nil {
+		panic("NewStaticWeighted: rng must not be nil")
+	}
+	return &StaticWeighted{weights: weights, rng: rng}
+}
+
+// ValidateStaticRoutingWeights checks that every weight is finite and >= 0
+// (R3) and that at least one is positive.
+func ValidateStaticRoutingWeights(weights []float64) error {
+	positive := false
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) || w < 0 {
+			return fmt.Errorf("weight %d must be a finite value >= 0, got %v", i, w)
+		}
+		if w > 0 {
+			positive = true
+		}
+	}
+	if !positive {
+		return fmt.Errorf("at least one weight must be > 0")
+	}
+	return nil
+}
+
+// Route implements RoutingPolicy for StaticWeighted. Candidates are walked in
+// snapshot order, so the choice is a pure function of the rng stream (INV-6).
+func (sw *StaticWeighted) Route(_ *Request, state *RouterState) RoutingDecision {
+	snapshots := state.Snapshots
+	if len(snapshots) == 0 {
+		panic("StaticWeighted.Route: empty snapshots")
+	}
+	var total float64
+	for _, snap := range snapshots {
+		total += sw.weights[snap.ID]
+	}
+	if total <= 0 {
+		return NewNoRouteDecision("static-weighted (no weighted instance routable)")
+	}
+	target := sw.rng.Float64() * total
+	chosen := ""
+	var acc float64
+	for _, snap := range snapshots {
+		w := sw.weights[snap.ID]
+		if w <= 0 {
+			continue
+		}
+		chosen = snap.ID // last positive weight absorbs float rounding at target ~ total
+		acc += w
+		if target < acc {
+			break
+		}
+	}
+	return NewRoutingDecision(chosen, fmt.Sprintf("static-weighted (weight=%.3g of %.3g)", sw.weights[chosen], total))
+}
+
+// ReplayRouting routes every request to the instance a routing-decision log
+// assigned it (request ID -> instance ID), typically recorded from an earlier
+// run's decision trace, so scheduling effects can be studied with routing held
+// fixed. Callers that must fail gracefully check Lookup before Route.
+type ReplayRouting struct {
+	assignments map[string]string
+}
+
+// NewReplayRouting creates a ReplayRouting from a request-ID to instance-ID
+// log. Panics on an empty log.
+func NewReplayRouting(assignments map[string]string) *ReplayRouting {
+	if len(assignments) == 0 {
+		panic("NewReplayRouting: routing-decision log must not be empty")
+	}
+	return &ReplayRouting{assignments: assignments}
+}
+
+// Lookup returns the instance the log assigns to requestID and whether the log
+// has an entry for it.
+func (rp *ReplayRouting) Lookup(requestID string) (string, bool) {
+	target, ok := rp.assignments[requestID]
+	return target, ok
+}
+
+// Route implements RoutingPolicy for ReplayRouting. Panics when the log has no
+// entry for the request or its logged instance is not among the candidates:
+// the replay can no longer reproduce the recorded run.
+func (rp *ReplayRouting) Route(req *Request, state *RouterState) RoutingDecision {
+	if req == nil {
+		panic("ReplayRouting.Route: nil request")
+	}
+	target, ok := rp.assignments[req.ID]
+	if !ok {
+		panic(fmt.Sprintf("ReplayRouting.Route: request %q is not in the routing-decision log", req.ID))
+	}
+	for _, snap := range state.Snapshots {
+		if snap.ID == target {
+			return NewRoutingDecision(target, "replay")
+		}
+	}
+	panic(fmt.Sprintf("ReplayRouting.Route: logged instance %q for request %q is not routable", target, req.ID))
+}
+
+// SessionAffinityOverloadSlack is how far (in EffectiveLoad) a session's
+// sticky instance may exceed the least-loaded instance before SessionAffinity
+// gives up on warm KV and falls back to least-loaded routing.
+const SessionAffinityOverloadSlack = 8
+
+// SessionAffinity routes every round of a session to the instance that served
+// the session's previous round, where the session's KV prefix is still warm.
+// A request falls back to least-loaded routing when it has no SessionID, when
+// it opens a session, when the sticky instance is no longer routable, or when
+// the sticky instance is overloaded: its EffectiveLoad exceeds the minimum by
+// more than SessionAffinityOverloadSlack. The fallback target becomes the
+// session's new sticky instance.
+type SessionAffinity struct {
+	sessions map[string]string // SessionID -> instance that served the latest round
+	fallback LeastLoaded
+}
+
+// Route implements RoutingPolicy for SessionAffinity.
+func (sa *SessionAffinity) Route(req *Request, state *RouterState) RoutingDecision {
+	snapshots := state.Snapshots
+	if len(snapshots) == 0 {
+		panic("SessionAffinity.Route: empty snapshots")
+	}
+	if req.SessionID == "" {
+		return sa.fallback.Route(req, state)
+	}
+	if sticky, ok := sa.sessions[req.SessionID]; ok {
+		minLoad := snapshots[0].EffectiveLoad()
+		for _, snap := range snapshots[1:] {
+			minLoad = min(minLoad, snap.EffectiveLoad())
+		}
+		for _, snap := range snapshots {
+			if snap.ID == sticky && snap.EffectiveLoad()-minLoad <= SessionAffinityOverloadSlack {
+				return NewRoutingDecision(sticky, fmt.Sprintf("session-affinity (sticky, load=%d)", snap.EffectiveLoad()))
+			}
+		}
+	}
+	decision := sa.fallback.Route(req, state)
+	sa.sessions[req.SessionID] = decision.TargetInstance
+	decision.Reason = "session-affinity fallback: " + decision.Reason
+	return decision
+}
+
 // NewRoutingPolicy creates a routing policy by name.
 // Valid names are defined in validRoutingPolicies (bundle.go).
 // Empty string defaults to round-robin.
 // For weighted scoring, scorerConfigs configures the scorer pipeline.
 // If scorerConfigs is nil/empty for "weighted", DefaultScorerConfigs() is used.
 // Non-weighted policies ignore scorerConfigs.
-// The rng parameter enables random tie-breaking for least-loaded and weighted policies;
+// The rng parameter enables random tie-breaking for least-loaded, decode-load, and weighted policies;
 // nil preserves positional tie-breaking. Ignored by round-robin and always-busiest.
 // Panics on unrecognized names.
 func NewRoutingPolicy(name string, scorerConfigs []ScorerConfig, blockSize int64, rng *rand.Rand) RoutingPolicy {
-	return newRoutingPolicyInternal(name, scorerConfigs, blockSize, rng, nil)
+	return newRoutingPolicyInternal(name, scorerConfigs, blockSize, 0, rng, nil)
 }
 
 // NewRoutingPolicyWithCache is like NewRoutingPolicy but enables the precise-prefix-cache
 // and no-hit-lru scorers. cacheFn maps instance ID to a function returning the count of
 // consecutive cached prefix blocks for given tokens; pass nil to disable those scorers
-// (equivalent to calling NewRoutingPolicy).
-func NewRoutingPolicyWithCache(name string, scorerConfigs []ScorerConfig, blockSize int64, rng *rand.Rand, cacheFn map[string]func([]TokenID) int) RoutingPolicy {
-	return newRoutingPolicyInternal(name, scorerConfigs, blockSize, rng, cacheQueryFn(cacheFn))
+// (equivalent to calling NewRoutingPolicy). prefixHashSkipTokens is the number of
+// leading input tokens the prefix-affinity scorer leaves out of its block hashes
+// (0 hashes the full prompt, as NewRoutingPolicy does).
+func NewRoutingPolicyWithCache(name string, scorerConfigs []ScorerConfig, blockSize, prefixHashSkipTokens int64, rng *rand.Rand, cacheFn map[string]func([]TokenID) int) RoutingPolicy {
+	return newRoutingPolicyInternal(name, scorerConfigs, blockSize, prefixHashSkipTokens, rng, cacheQueryFn(cacheFn))
 }
 
 // newRoutingPolicyInternal creates a routing policy, shared by both public constructors.
-func newRoutingPolicyInternal(name string, scorerConfigs []ScorerConfig, blockSize int64, rng *rand.Rand, cacheFn cacheQueryFn) RoutingPolicy {
+func newRoutingPolicyInternal(name string, scorerConfigs []ScorerConfig, blockSize, prefixHashSkipTokens int64, rng *rand.Rand, cacheFn cacheQueryFn) RoutingPolicy {
 	if !IsValidRoutingPolicy(name) {
 		panic(fmt.Sprintf("unknown routing policy %q", name))
 	}
@@ -299,6 +519,8 @@ func newRoutingPolicyInternal(name string, scorerConfigs []ScorerConfig, blockSi
 		return &RoundRobin{}
 	case "least-loaded":
 		return &LeastLoaded{rng: rng}
+	case "decode-load":
+		return &DecodeLoad{rng: rng}
 	case "weighted":
 		if len(scorerConfigs) == 0 {
 			scorerConfigs = DefaultScorerConfigs()
@@ -306,7 +528,7 @@ func newRoutingPolicyInternal(name string, scorerConfigs []ScorerConfig, blockSi
 		scorers := make([]scorerFunc, len(scorerConfigs))
 		var observers []observerFunc
 		for i, cfg := range scorerConfigs {
-			scorer, obs := newScorerWithObserver(cfg.Name, int(blockSize), cacheFn)
+			scorer, obs := newScorerWithObserver(cfg.Name, int(blockSize), int(prefixHashSkipTokens), cacheFn)
 			scorers[i] = scorer
 			if obs != nil {
 				observers = append(observers, obs)
@@ -316,6 +538,12 @@ func newRoutingPolicyInternal(name string, scorerConfigs []ScorerConfig, blockSi
 		return &WeightedScoring{scorers: scorers, weights: weights, observers: observers, rng: rng}
 	case "always-busiest":
 		return &AlwaysBusiest{}
+	case "session-affinity":
+		return &SessionAffinity{sessions: make(map[string]string), fallback: LeastLoaded{rng: rng}}
+	case "static-weighted":
+		panic("static-weighted routing requires per-instance weights; construct it with NewStaticWeighted")
+	case "replay":
+		panic("replay routing requires a routing-decision log; construct it with NewReplayRouting")
 	default:
 		panic(fmt.Sprintf("unhandled routing policy %q", name))
 	}
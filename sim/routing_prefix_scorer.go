@@ -16,8 +16,10 @@ const defaultLRUCapacity = 10000
 //	decision, making this scorer effectively synchronous (always fresh).
 //
 // Both the scorer and observer share the same PrefixCacheIndex via closure.
-// The blockSize should match the simulation's KV cache block size.
-func newPrefixAffinityScorer(blockSize int) (scorerFunc, observerFunc) {
+// The blockSize should match the simulation's KV cache block size. The first
+// skipTokens input tokens (a shared system prompt) are left out of the hashes,
+// so affinity follows the user-specific part of the prompt; 0 hashes it all.
+func newPrefixAffinityScorer(blockSize, skipTokens int) (scorerFunc, observerFunc) {
 	idx := NewPrefixCacheIndex(blockSize, defaultLRUCapacity)
 
 	// Shared cache: scorer computes hashes once, observer reuses them.
@@ -33,7 +35,7 @@ func newPrefixAffinityScorer(blockSize int) (scorerFunc, observerFunc) {
 			return scores
 		}
 		// Compute block hashes once and cache for the observer
-		cachedHashes = idx.ComputeBlockHashes(req.FullInputTokens())
+		cachedHashes = idx.ComputeBlockHashes(skipHashPrefix(req.FullInputTokens(), skipTokens))
 		cachedReqID = req.ID
 		totalBlocks := len(cachedHashes)
 		for _, snap := range snapshots {
@@ -56,10 +58,22 @@ func newPrefixAffinityScorer(blockSize int) (scorerFunc, observerFunc) {
 		// cachedReqID is "" and req.ID is also "" (both zero-values).
 		hashes := cachedHashes
 		if req.ID != cachedReqID || cachedHashes == nil {
-			hashes = idx.ComputeBlockHashes(req.FullInputTokens())
+			hashes = idx.ComputeBlockHashes(skipHashPrefix(req.FullInputTokens(), skipTokens))
 		}
 		idx.RecordBlocks(hashes, targetInstance)
 	}
 
 	return scorer, observer
 }
+
+// skipHashPrefix drops the first skip tokens before prefix hashing. A prompt no
+// longer than skip has no hashable suffix and yields nil (no blocks, score 0).
+func skipHashPrefix(tokens []TokenID, skip int) []TokenID {
+	if skip <= 0 {
+		return tokens
+	}
+	if len(tokens) <= skip {
+		return nil
+	}
+	return tokens[skip:]
+}
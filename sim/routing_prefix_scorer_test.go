@@ -316,7 +316,7 @@ func TestPrefixAffinityScorer_INV1_INV2_Conformance(t *testing.T) {
 // observer correctly recomputes block hashes when called for a request whose ID
 // does not match the scorer's cached request ID (the fallback path).
 func TestPrefixAffinityScorer_ObserverFallback_RecomputesHashes(t *testing.T) {
-	scorer, observer := newPrefixAffinityScorer(4)
+	scorer, observer := newPrefixAffinityScorer(4, 0)
 
 	snapshots := []RoutingSnapshot{{ID: "inst_0"}, {ID: "inst_1"}}
 
@@ -354,3 +354,39 @@ func TestPrefixAffinityScorer_NonWeightedPolicies_Unchanged(t *testing.T) {
 		})
 	}
 }
+
+// TestPrefixAffinityScorer_SkipTokens_KeysOnUserSuffix verifies that with
+// prefixHashSkipTokens covering a shared system prompt, requests differing only
+// after it hash apart and are routed apart, while without the skip their
+// system-prompt blocks collide and prefix affinity co-locates them.
+func TestPrefixAffinityScorer_SkipTokens_KeysOnUserSuffix(t *testing.T) {
+	system := []TokenID{101, 102, 103, 104, 105, 106, 107, 108} // two blocks of 4
+	reqA := &Request{ID: "rA", InputTokens: append(append([]TokenID{}, system...), 1, 2, 3, 4)}
+	reqB := &Request{ID: "rB", InputTokens: append(append([]TokenID{}, system...), 5, 6, 7, 8)}
+
+	idx := NewPrefixCacheIndex(4, defaultLRUCapacity)
+	fullA, fullB := idx.ComputeBlockHashes(reqA.FullInputTokens()), idx.ComputeBlockHashes(reqB.FullInputTokens())
+	assert.Equal(t, fullA[:2], fullB[:2], "without skip the system-prompt blocks collide")
+	skipA := idx.ComputeBlockHashes(skipHashPrefix(reqA.FullInputTokens(), len(system)))
+	skipB := idx.ComputeBlockHashes(skipHashPrefix(reqB.FullInputTokens(), len(system)))
+	assert.Len(t, skipA, 1)
+	assert.NotEqual(t, skipA, skipB, "with skip the requests hash on their differing suffixes")
+
+	scorers := []ScorerConfig{{Name: "prefix-affinity", Weight: 3}, {Name: "queue-depth", Weight: 1}}
+	for _, tc := range []struct {
+		skip int64
+		want string
+	}{
+		{skip: 0, want: "inst_0"},                   // 3·(2/3) affinity beats 1·queue
+		{skip: int64(len(system)), want: "inst_1"}, // no affinity left: the emptier queue wins
+	} {
+		policy := NewRoutingPolicyWithCache("weighted", scorers, 4, tc.skip, nil, nil)
+		idle := []RoutingSnapshot{{ID: "inst_0"}, {ID: "inst_1"}}
+		first := policy.Route(reqA, &RouterState{Snapshots: idle})
+		assert.Equal(t, "inst_0", first.TargetInstance, "skip=%d: first request takes the positional tie", tc.skip)
+
+		busy := []RoutingSnapshot{{ID: "inst_0", QueueDepth: 1}, {ID: "inst_1"}}
+		second := policy.Route(reqB, &RouterState{Snapshots: busy})
+		assert.Equal(t, tc.want, second.TargetInstance, "skip=%d: second request", tc.skip)
+	}
+}
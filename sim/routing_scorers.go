@@ -85,6 +85,9 @@ var validScorerNames = map[string]bool{
 	"load-aware":           true,
 	"vllm-dp":              true,
 	"lora-affinity":        true,
+	"p99-ttft":             true,
+	"preemption-rate":      true,
+	"step-time":            true,
 }
 
 // IsValidScorer returns true if name is a recognized scorer.
@@ -157,12 +160,13 @@ func normalizeScorerWeights(configs []ScorerConfig) []float64 {
 
 // newScorerWithObserver creates a scorer function and optional observer for a named scorer.
 // Returns (scorer, observer) where observer is nil for stateless scorers.
-// blockSize is used by stateful scorers (e.g., prefix-affinity) for block hash computation.
+// blockSize is used by stateful scorers (e.g., prefix-affinity) for block hash computation;
+// skipTokens is the number of leading input tokens prefix-affinity leaves out of its hashes.
 // Panics on unknown name (validation should catch this before reaching here).
-func newScorerWithObserver(name string, blockSize int, cacheFn cacheQueryFn) (scorerFunc, observerFunc) {
+func newScorerWithObserver(name string, blockSize, skipTokens int, cacheFn cacheQueryFn) (scorerFunc, observerFunc) {
 	switch name {
 	case "prefix-affinity":
-		return newPrefixAffinityScorer(blockSize)
+		return newPrefixAffinityScorer(blockSize, skipTokens)
 	case "precise-prefix-cache":
 		return newPrecisePrefixCacheScorer(cacheFn)
 	case "no-hit-lru":
@@ -183,6 +187,12 @@ func newScorerWithObserver(name string, blockSize int, cacheFn cacheQueryFn) (sc
 		return scoreVLLMDP, nil
 	case "lora-affinity":
 		return scoreLoRAAffinity, nil
+	case "p99-ttft":
+		return scoreP99TTFT, nil
+	case "preemption-rate":
+		return scorePreemptionRate, nil
+	case "step-time":
+		return scoreStepTime, nil
 	default:
 		panic(fmt.Sprintf("unknown scorer %q", name))
 	}
@@ -308,6 +318,111 @@ func scoreRunningRequests(_ *Request, snapshots []RoutingSnapshot) map[string]fl
 	return scores
 }
 
+// scoreP99TTFT computes per-instance tail-latency scores from each instance's
+// windowed P99 TTFT (last sim.RecentLatencyWindowSize completions) using min-max
+// normalization over the instances that have completed at least one request.
+// Lower P99 → higher score. All-equal P99s → all score 1.0.
+// Instances with no completions yet (TTFTP99 == 0) have no latency evidence and
+// score a neutral 0.5: treating them as fastest would flood a slow instance whose
+// first requests have not finished, and treating them as slowest would starve
+// freshly started instances.
+//
+// Signal freshness (R17, INV-7):
+//
+//	Reads: TTFTP99 (Periodic when interval>0, else Immediate).
+func scoreP99TTFT(_ *Request, snapshots []RoutingSnapshot) map[string]float64 {
+	scores := make(map[string]float64, len(snapshots))
+	minP99, maxP99 := math.MaxFloat64, 0.0
+	for _, snap := range snapshots {
+		if snap.TTFTP99 == 0 {
+			continue
+		}
+		if snap.TTFTP99 < minP99 {
+			minP99 = snap.TTFTP99
+		}
+		if snap.TTFTP99 > maxP99 {
+			maxP99 = snap.TTFTP99
+		}
+	}
+	for _, snap := range snapshots {
+		switch {
+		case snap.TTFTP99 == 0:
+			scores[snap.ID] = 0.5
+		case maxP99 == minP99:
+			scores[snap.ID] = 1.0
+		default:
+			scores[snap.ID] = (maxP99 - snap.TTFTP99) / (maxP99 - minP99)
+		}
+	}
+	return scores
+}
+
+// scorePreemptionRate computes per-instance scores from each instance's recent
+// preemption rate (preemptions/s over the last sim.RecentPreemptionWindowUs) using
+// inverted min-max normalization. An instance thrashing on KV pressure scores 0.0
+// against calm peers; all-equal rates (including none anywhere) → all score 1.0.
+// The windowed rate, unlike the cumulative PreemptionCount, lets an instance that
+// has recovered win traffic back.
+//
+// Signal freshness (R17, INV-7):
+//
+//	Reads: RecentPreemptionRate (Periodic when interval>0, else Immediate).
+func scorePreemptionRate(_ *Request, snapshots []RoutingSnapshot) map[string]float64 {
+	scores := make(map[string]float64, len(snapshots))
+	minRate, maxRate := math.MaxFloat64, 0.0
+	for _, snap := range snapshots {
+		if snap.RecentPreemptionRate < minRate {
+			minRate = snap.RecentPreemptionRate
+		}
+		if snap.RecentPreemptionRate > maxRate {
+			maxRate = snap.RecentPreemptionRate
+		}
+	}
+	for _, snap := range snapshots {
+		if maxRate == minRate {
+			scores[snap.ID] = 1.0
+		} else {
+			scores[snap.ID] = (maxRate - snap.RecentPreemptionRate) / (maxRate - minRate)
+		}
+	}
+	return scores
+}
+
+// scoreStepTime computes per-instance scores from the estimated time to work
+// through the instance's load: StepTimeEstimate × (1 + EffectiveLoad), scored
+// as the cluster minimum over the instance's value. On a heterogeneous cluster a
+// faster instance (smaller StepTimeEstimate) keeps winning until it holds
+// proportionally more load; on a homogeneous one this ranks like load-balance.
+// When any instance has no estimate (0), estimates are ignored and only load
+// counts. Score range: (0, 1].
+//
+// Signal freshness (R17, INV-7):
+//
+//	Reads: StepTimeEstimate (fixed at instance construction),
+//	EffectiveLoad() = QueueDepth + BatchSize + InFlightRequests (synchronous + Periodic composite).
+func scoreStepTime(_ *Request, snapshots []RoutingSnapshot) map[string]float64 {
+	scores := make(map[string]float64, len(snapshots))
+	useEstimates := true
+	for _, snap := range snapshots {
+		if snap.StepTimeEstimate <= 0 {
+			useEstimates = false
+		}
+	}
+	costs := make([]float64, len(snapshots))
+	minCost := math.MaxFloat64
+	for i, snap := range snapshots {
+		costs[i] = 1.0 + float64(snap.EffectiveLoad())
+		if useEstimates {
+			costs[i] *= snap.StepTimeEstimate
+		}
+		minCost = math.Min(minCost, costs[i])
+	}
+	for i, snap := range snapshots {
+		scores[snap.ID] = minCost / costs[i]
+	}
+	return scores
+}
+
 // loadAwareQueueThreshold is the default queue depth threshold for the load-aware scorer.
 // Matches llm-d's QueueThresholdDefault (load_aware.go:42). Queue depths at or above
 // this value score 0.0.
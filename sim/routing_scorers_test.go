@@ -329,6 +329,106 @@ func TestScoreRunningRequests_AllZero_AllScoreOne(t *testing.T) {
 	assert.Equal(t, 1.0, scores["b"])
 }
 
+// === p99-ttft scorer tests ===
+
+func TestScoreP99TTFT_LowerTailScoresHigher(t *testing.T) {
+	snapshots := []RoutingSnapshot{
+		{ID: "fast", TTFTP99: 10_000},
+		{ID: "mid", TTFTP99: 30_000},
+		{ID: "slow", TTFTP99: 50_000},
+	}
+	scores := scoreP99TTFT(nil, snapshots)
+	assert.Equal(t, 1.0, scores["fast"], "lowest P99 should score 1.0")
+	assert.Equal(t, 0.0, scores["slow"], "highest P99 should score 0.0")
+	assert.InDelta(t, 0.5, scores["mid"], 0.001, "mid-point should score ~0.5")
+}
+
+func TestScoreP99TTFT_NoCompletions_Neutral(t *testing.T) {
+	// No instance has latency evidence yet → no differentiation.
+	scores := scoreP99TTFT(nil, []RoutingSnapshot{{ID: "a"}, {ID: "b"}})
+	assert.Equal(t, scores["a"], scores["b"])
+
+	// An instance without completions must not outrank one with a known-good tail,
+	// nor be starved below one with a known-bad tail.
+	scores = scoreP99TTFT(nil, []RoutingSnapshot{
+		{ID: "fast", TTFTP99: 10_000},
+		{ID: "slow", TTFTP99: 50_000},
+		{ID: "new"},
+	})
+	assert.Greater(t, scores["fast"], scores["new"])
+	assert.Greater(t, scores["new"], scores["slow"])
+}
+
+func TestP99TTFT_Registered(t *testing.T) {
+	assert.True(t, IsValidScorer("p99-ttft"))
+	scorer, observer := newScorerWithObserver("p99-ttft", 16, 0, nil)
+	require.NotNil(t, scorer)
+	assert.Nil(t, observer, "p99-ttft is stateless (no observer)")
+}
+
+// === preemption-rate scorer tests ===
+
+func TestScorePreemptionRate_ThrashingScoresLower(t *testing.T) {
+	snapshots := []RoutingSnapshot{
+		{ID: "calm"},
+		{ID: "mild", RecentPreemptionRate: 5},
+		{ID: "thrash", RecentPreemptionRate: 10},
+	}
+	scores := scorePreemptionRate(nil, snapshots)
+	assert.Equal(t, 1.0, scores["calm"], "no recent preemptions should score 1.0")
+	assert.Equal(t, 0.0, scores["thrash"], "highest rate should score 0.0")
+	assert.InDelta(t, 0.5, scores["mild"], 0.001, "mid-point should score ~0.5")
+}
+
+func TestScorePreemptionRate_AllEqual_Neutral(t *testing.T) {
+	scores := scorePreemptionRate(nil, []RoutingSnapshot{{ID: "a"}, {ID: "b"}})
+	assert.Equal(t, 1.0, scores["a"])
+	assert.Equal(t, 1.0, scores["b"])
+}
+
+func TestPreemptionRate_Registered(t *testing.T) {
+	assert.True(t, IsValidScorer("preemption-rate"))
+	scorer, observer := newScorerWithObserver("preemption-rate", 16, 0, nil)
+	require.NotNil(t, scorer)
+	assert.Nil(t, observer, "preemption-rate is stateless (no observer)")
+}
+
+// === step-time scorer tests ===
+
+func TestScoreStepTime_FasterInstanceTakesProportionallyMoreLoad(t *testing.T) {
+	// fast steps in half the time of slow: with twice slow's load it ties.
+	scores := scoreStepTime(nil, []RoutingSnapshot{
+		{ID: "fast", StepTimeEstimate: 1000, QueueDepth: 1},
+		{ID: "slow", StepTimeEstimate: 2000},
+	})
+	assert.Equal(t, 1.0, scores["fast"])
+	assert.Equal(t, 1.0, scores["slow"])
+
+	// One more request on fast tips the balance to slow.
+	scores = scoreStepTime(nil, []RoutingSnapshot{
+		{ID: "fast", StepTimeEstimate: 1000, QueueDepth: 2},
+		{ID: "slow", StepTimeEstimate: 2000},
+	})
+	assert.Equal(t, 1.0, scores["slow"])
+	assert.InDelta(t, 2.0/3.0, scores["fast"], 1e-9)
+}
+
+func TestScoreStepTime_MissingEstimate_LoadOnly(t *testing.T) {
+	scores := scoreStepTime(nil, []RoutingSnapshot{
+		{ID: "a", StepTimeEstimate: 1000, BatchSize: 1},
+		{ID: "b"},
+	})
+	assert.Equal(t, 1.0, scores["b"], "estimates are ignored when any is unknown")
+	assert.Equal(t, 0.5, scores["a"])
+}
+
+func TestStepTime_Registered(t *testing.T) {
+	assert.True(t, IsValidScorer("step-time"))
+	scorer, observer := newScorerWithObserver("step-time", 16, 0, nil)
+	require.NotNil(t, scorer)
+	assert.Nil(t, observer, "step-time is stateless (no observer)")
+}
+
 // === load-aware scorer tests (BC-5, BC-6, BC-7) ===
 
 func TestScoreLoadAware_EmptyQueue_ScoresHalf(t *testing.T) {
@@ -407,6 +507,8 @@ func TestAllScorers_ReturnScoreForEveryInstance(t *testing.T) {
 		{"running-requests", scoreRunningRequests},
 		{"load-aware", scoreLoadAware},
 		{"vllm-dp", scoreVLLMDP},
+		{"p99-ttft", scoreP99TTFT},
+		{"preemption-rate", scorePreemptionRate},
 		{"precise-prefix-cache", precisePrefixScorer},
 		{"no-hit-lru", noHitLRUScorer},
 	}
@@ -430,9 +532,9 @@ func TestAllScorers_ReturnScoreForEveryInstance(t *testing.T) {
 	}
 	// Verify nil-request path for all stateless scorers.
 	// These scorers ignore the request parameter; this confirms they don't panic on nil.
-	// Indices 0-6: queue-depth, kv-utilization, load-balance, active-requests,
-	// running-requests, load-aware, vllm-dp (all use _ *Request).
-	for _, sf := range scorerFns[:7] {
+	// Indices 0-8: queue-depth, kv-utilization, load-balance, active-requests,
+	// running-requests, load-aware, vllm-dp, p99-ttft, preemption-rate (all use _ *Request).
+	for _, sf := range scorerFns[:9] {
 		t.Run(sf.name+"/nil-request", func(t *testing.T) {
 			scores := sf.fn(nil, snapshots)
 			assert.Len(t, scores, len(snapshots))
@@ -449,7 +551,7 @@ func TestNewScorerFactory_PrecisePrefixAndNoHitLRU(t *testing.T) {
 	}
 	policy := NewRoutingPolicyWithCache("weighted", []ScorerConfig{
 		{Name: "precise-prefix-cache", Weight: 1.0},
-	}, 16, nil, cacheQueryFn)
+	}, 16, 0, nil, cacheQueryFn)
 
 	req := &Request{ID: "r1", InputTokens:  []TokenID{1, 2, 3}}
 	state := &RouterState{
@@ -668,7 +770,7 @@ func TestScoreLoRAAffinity_IgnoresOutputTokens(t *testing.T) {
 func TestLoRAAffinity_Registered(t *testing.T) {
 	assert.True(t, IsValidScorer("lora-affinity"), "lora-affinity must be a valid scorer name")
 	assert.Contains(t, ValidScorerNames(), "lora-affinity")
-	scorer, observer := newScorerWithObserver("lora-affinity", 16, nil)
+	scorer, observer := newScorerWithObserver("lora-affinity", 16, 0, nil)
 	require.NotNil(t, scorer, "lora-affinity must resolve to a scorerFunc")
 	assert.Nil(t, observer, "lora-affinity is stateless (no observer)")
 }
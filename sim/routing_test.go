@@ -659,3 +659,188 @@ func TestTieBreaking_NilRNG_Positional(t *testing.T) {
 		})
 	}
 }
+
+// TestStaticWeighted_SplitFollowsWeights routes 1000 requests over weights
+// [3, 1] plus a zero-weight instance: the split is ~75/25, the zero-weight
+// instance is never chosen, and the sequence is identical for a fixed seed.
+func TestStaticWeighted_SplitFollowsWeights(t *testing.T) {
+	weights := map[string]float64{"instance_0": 3, "instance_1": 1, "instance_2": 0}
+	snapshots := []RoutingSnapshot{{ID: "instance_0"}, {ID: "instance_1"}, {ID: "instance_2"}}
+	route := func(seed int64) ([]string, map[string]int) {
+		policy := NewStaticWeighted(weights, rand.New(rand.NewSource(seed)))
+		targets := make([]string, 1000)
+		counts := make(map[string]int)
+		for i := range targets {
+			d := policy.Route(&Request{ID: fmt.Sprintf("r%d", i)}, &RouterState{Snapshots: snapshots, Clock: int64(i)})
+			targets[i] = d.TargetInstance
+			counts[d.TargetInstance]++
+		}
+		return targets, counts
+	}
+
+	targets, counts := route(42)
+	if counts["instance_2"] != 0 {
+		t.Errorf("zero-weight instance_2 received %d requests, want 0", counts["instance_2"])
+	}
+	if share := float64(counts["instance_0"]) / 1000; math.Abs(share-0.75) > 0.04 {
+		t.Errorf("instance_0 share = %.3f, want ~0.75 (counts %v)", share, counts)
+	}
+	if counts["instance_0"]+counts["instance_1"] != 1000 {
+		t.Errorf("counts %v do not sum to 1000", counts)
+	}
+
+	again, _ := route(42)
+	for i := range targets {
+		if targets[i] != again[i] {
+			t.Fatalf("request %d: %s then %s under the same seed", i, targets[i], again[i])
+		}
+	}
+}
+
+// TestStaticWeighted_NoWeightedInstanceRoutable_NoRoute covers snapshots
+// that contain only zero-weight instances (e.g. the weighted ones are down):
+// the request is routed nowhere rather than to a zero-weight instance.
+func TestStaticWeighted_NoWeightedInstanceRoutable_NoRoute(t *testing.T) {
+	policy := NewStaticWeighted(map[string]float64{"instance_0": 1}, rand.New(rand.NewSource(1)))
+	d := policy.Route(&Request{ID: "r"}, &RouterState{Snapshots: []RoutingSnapshot{{ID: "instance_1"}, {ID: "instance_2"}}})
+	if d.TargetInstance != "" {
+		t.Errorf("target = %q, want no route", d.TargetInstance)
+	}
+}
+
+func TestValidateStaticRoutingWeights(t *testing.T) {
+	for _, tc := range []struct {
+		weights []float64
+		wantErr bool
+	}{
+		{[]float64{3, 1}, false},
+		{[]float64{0, 2}, false},
+		{[]float64{0, 0}, true},
+		{nil, true},
+		{[]float64{1, -1}, true},
+		{[]float64{1, math.NaN()}, true},
+		{[]float64{math.Inf(1)}, true},
+	} {
+		if err := ValidateStaticRoutingWeights(tc.weights); (err != nil) != tc.wantErr {
+			t.Errorf("weights %v: err = %v, wantErr %v", tc.weights, err, tc.wantErr)
+		}
+	}
+}
+
+// TestReplayRouting_FollowsLogAndPanicsOnMissingID verifies the logged
+// instance is chosen regardless of load, and an unlogged request panics.
+func TestReplayRouting_FollowsLogAndPanicsOnMissingID(t *testing.T) {
+	policy := NewReplayRouting(map[string]string{"r": "instance_1"})
+	state := &RouterState{Snapshots: []RoutingSnapshot{{ID: "instance_0"}, {ID: "instance_1", QueueDepth: 50}}}
+	if d := policy.Route(&Request{ID: "r"}, state); d.TargetInstance != "instance_1" {
+		t.Errorf("target = %q, want logged instance_1", d.TargetInstance)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a request missing from the log")
+		}
+	}()
+	policy.Route(&Request{ID: "unlogged"}, state)
+}
+
+// === SessionAffinity Tests ===
+
+// TestSessionAffinity_SticksToPreviousInstance verifies that later rounds of a
+// session follow the instance chosen for its first round, even when another
+// instance is now (slightly) less loaded.
+func TestSessionAffinity_SticksToPreviousInstance(t *testing.T) {
+	policy := NewRoutingPolicy("session-affinity", nil, 16, rand.New(rand.NewSource(1)))
+
+	// GIVEN round 0 lands on the idle instance_1
+	first := policy.Route(&Request{ID: "r0", SessionID: "s1"}, &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "instance_0", QueueDepth: 3},
+		{ID: "instance_1"},
+	}})
+	if first.TargetInstance != "instance_1" {
+		t.Fatalf("round 0: target = %q, want least-loaded instance_1", first.TargetInstance)
+	}
+
+	// WHEN round 1 arrives and instance_1 is now busier, but within the slack
+	second := policy.Route(&Request{ID: "r1", SessionID: "s1", RoundIndex: 1}, &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "instance_0"},
+		{ID: "instance_1", BatchSize: SessionAffinityOverloadSlack},
+	}})
+
+	// THEN it still goes to instance_1
+	if second.TargetInstance != "instance_1" {
+		t.Errorf("round 1: target = %q, want sticky instance_1", second.TargetInstance)
+	}
+}
+
+// TestSessionAffinity_OverloadFallsBackAndResticks verifies that an overloaded
+// sticky instance is abandoned for the least-loaded one, which then becomes
+// the session's new sticky instance.
+func TestSessionAffinity_OverloadFallsBackAndResticks(t *testing.T) {
+	policy := NewRoutingPolicy("session-affinity", nil, 16, rand.New(rand.NewSource(1)))
+	policy.Route(&Request{ID: "r0", SessionID: "s1"}, &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "instance_0", QueueDepth: 1},
+		{ID: "instance_1"},
+	}})
+
+	// GIVEN instance_1 exceeds the least-loaded instance by more than the slack
+	overloaded := []RoutingSnapshot{
+		{ID: "instance_0", QueueDepth: 2},
+		{ID: "instance_1", QueueDepth: 2 + SessionAffinityOverloadSlack + 1},
+	}
+
+	// WHEN the next round is routed
+	d := policy.Route(&Request{ID: "r1", SessionID: "s1", RoundIndex: 1}, &RouterState{Snapshots: overloaded})
+
+	// THEN it falls back to instance_0, and the following round sticks there
+	if d.TargetInstance != "instance_0" {
+		t.Fatalf("overloaded round: target = %q, want fallback instance_0", d.TargetInstance)
+	}
+	d = policy.Route(&Request{ID: "r2", SessionID: "s1", RoundIndex: 2}, &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "instance_0", QueueDepth: 4},
+		{ID: "instance_1"},
+	}})
+	if d.TargetInstance != "instance_0" {
+		t.Errorf("round after fallback: target = %q, want re-stuck instance_0", d.TargetInstance)
+	}
+}
+
+// TestSessionAffinity_StickyInstanceGone_FallsBack covers a sticky instance
+// that is no longer routable (e.g. draining or scaled in).
+func TestSessionAffinity_StickyInstanceGone_FallsBack(t *testing.T) {
+	policy := NewRoutingPolicy("session-affinity", nil, 16, rand.New(rand.NewSource(1)))
+	policy.Route(&Request{ID: "r0", SessionID: "s1"}, &RouterState{Snapshots: []RoutingSnapshot{{ID: "instance_0"}}})
+
+	d := policy.Route(&Request{ID: "r1", SessionID: "s1"}, &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "instance_1", QueueDepth: 5},
+		{ID: "instance_2", QueueDepth: 1},
+	}})
+	if d.TargetInstance != "instance_2" {
+		t.Errorf("target = %q, want least-loaded instance_2", d.TargetInstance)
+	}
+}
+
+// TestSessionAffinity_EmptySnapshots_Panics verifies defensive convention.
+func TestSessionAffinity_EmptySnapshots_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic on empty snapshots")
+		}
+	}()
+	policy := NewRoutingPolicy("session-affinity", nil, 16, rand.New(rand.NewSource(1)))
+	policy.Route(&Request{ID: "r1", SessionID: "s1"}, &RouterState{Snapshots: []RoutingSnapshot{}})
+}
+
+// TestDecodeLoad_PicksFewestRemainingDecodeTokens verifies decode-load routes
+// on remaining decode tokens, not request count, and breaks ties by load.
+func TestDecodeLoad_PicksFewestRemainingDecodeTokens(t *testing.T) {
+	policy := NewRoutingPolicy("decode-load", nil, 16, nil)
+	state := &RouterState{Snapshots: []RoutingSnapshot{
+		{ID: "a", QueueDepth: 1, RemainingDecodeTokens: 5000},
+		{ID: "b", QueueDepth: 6, RemainingDecodeTokens: 300},
+		{ID: "c", QueueDepth: 4, RemainingDecodeTokens: 300},
+	}}
+	// b and c tie on decode tokens; c has the lower EffectiveLoad.
+	if got := policy.Route(&Request{ID: "r"}, state).TargetInstance; got != "c" {
+		t.Errorf("TargetInstance = %q, want c", got)
+	}
+}
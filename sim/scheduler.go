@@ -3,6 +3,8 @@ package sim
 import (
 	"fmt"
 	"sort"
+
+	"github.com/inference-sim/inference-sim/sim/internal/hash"
 )
 
 // InstanceScheduler reorders the wait queue before batch formation.
@@ -75,6 +77,171 @@ func (r *ReversePriority) OrderQueue(reqs []*Request, _ int64) {
 	})
 }
 
+// PrefixPackScheduler co-schedules requests that share a prompt prefix to cut
+// cache-miss tokens. Waiting requests are grouped by their first KV block of
+// input tokens. Groups are ordered by the longest prefix any member already has
+// in the instance's KV cache (descending; blocks held by running requests
+// count), then by the group's earliest arrival. Members keep arrival order, so
+// a group lands in the batch back to back and later members hit the blocks the
+// first member allocates in the same step.
+// Requests shorter than one block form singleton groups.
+// Warning: like SJF, requests with no cached or shared prefix can starve under
+// sustained load.
+type PrefixPackScheduler struct {
+	// cachedBlocks returns the number of leading input blocks already in the
+	// instance's KV cache. Wired by NewSimulator; nil disables the cache term
+	// and only the grouping applies.
+	cachedBlocks func(tokens []TokenID) int
+	blockSize    int64
+}
+
+func (p *PrefixPackScheduler) OrderQueue(reqs []*Request, _ int64) {
+	if len(reqs) < 2 {
+		return
+	}
+	type group struct {
+		cached  int
+		arrival int64
+		id      string // ID of the earliest member; distinguishes groups arriving together
+	}
+	groups := make(map[string]*group)
+	member := make(map[*Request]*group, len(reqs))
+	for _, r := range reqs {
+		key := "req:" + r.ID
+		if p.blockSize > 0 && r.InputLen() >= p.blockSize {
+			key = hash.HashBlock("", r.InputTokenSlice(0, p.blockSize))
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{arrival: r.ArrivalTime, id: r.ID}
+			groups[key] = g
+		} else if r.ArrivalTime < g.arrival || (r.ArrivalTime == g.arrival && r.ID < g.id) {
+			g.arrival, g.id = r.ArrivalTime, r.ID
+		}
+		if p.cachedBlocks != nil {
+			g.cached = max(g.cached, p.cachedBlocks(r.FullInputTokens()))
+		}
+		member[r] = g
+	}
+	sort.SliceStable(reqs, func(i, j int) bool {
+		gi, gj := member[reqs[i]], member[reqs[j]]
+		if gi != gj {
+			if gi.cached != gj.cached {
+				return gi.cached > gj.cached
+			}
+			if gi.arrival != gj.arrival {
+				return gi.arrival < gj.arrival
+			}
+			return gi.id < gj.id
+		}
+		if reqs[i].ArrivalTime != reqs[j].ArrivalTime {
+			return reqs[i].ArrivalTime < reqs[j].ArrivalTime
+		}
+		return reqs[i].ID < reqs[j].ID
+	})
+}
+
+// WFQScheduler interleaves tenants with equal weights: each request's virtual
+// start is its position among its own tenant's waiting requests (arrival
+// order), and the queue is sorted by that position, then arrival, then ID.
+// A tenant that floods the queue therefore cannot push another tenant's
+// requests behind its whole backlog — the k-th waiting request of every
+// tenant is considered before any tenant's (k+1)-th. Requests with an empty
+// TenantID form one tenant.
+type WFQScheduler struct{}
+
+func (w *WFQScheduler) OrderQueue(reqs []*Request, _ int64) {
+	if len(reqs) < 2 {
+		return
+	}
+	byArrival := append([]*Request(nil), reqs...)
+	sort.SliceStable(byArrival, func(i, j int) bool {
+		if byArrival[i].ArrivalTime != byArrival[j].ArrivalTime {
+			return byArrival[i].ArrivalTime < byArrival[j].ArrivalTime
+		}
+		return byArrival[i].ID < byArrival[j].ID
+	})
+	perTenant := make(map[string]int)
+	start := make(map[*Request]int, len(reqs))
+	for _, r := range byArrival {
+		start[r] = perTenant[r.TenantID]
+		perTenant[r.TenantID]++
+	}
+	sort.SliceStable(reqs, func(i, j int) bool {
+		si, sj := start[reqs[i]], start[reqs[j]]
+		if si != sj {
+			return si < sj
+		}
+		if reqs[i].ArrivalTime != reqs[j].ArrivalTime {
+			return reqs[i].ArrivalTime < reqs[j].ArrivalTime
+		}
+		return reqs[i].ID < reqs[j].ID
+	})
+}
+
+// StarvationGuardScheduler wraps another InstanceScheduler with an
+// anti-starvation deadline: after the inner policy orders the queue, every
+// request that has waited maxWaitTicks or longer since arrival is moved to
+// the front, oldest first, ahead of the inner order. The rest keep the inner
+// order. Age is checked only here, when a step is formed, so a deadline that
+// falls inside a step is acted on at the next one: promotion lags the
+// deadline by up to one step. A promoted request is admitted as soon as a
+// batch slot and token budget are free, so its wait is bounded by
+// maxWaitTicks plus one step plus the time for capacity to open up.
+// Each request is counted once in the promotion callback, however many steps
+// it stays overdue.
+type StarvationGuardScheduler struct {
+	inner        InstanceScheduler
+	maxWaitTicks int64
+	// onPromote is called the first time a request is moved to the front.
+	// Wired by NewSimulator to count Metrics.StarvationPromotions; nil is allowed.
+	onPromote func(req *Request)
+}
+
+// NewStarvationGuardScheduler wraps inner with a maxWaitTicks deadline.
+// Panics if inner is nil or maxWaitTicks <= 0.
+func NewStarvationGuardScheduler(inner InstanceScheduler, maxWaitTicks int64) *StarvationGuardScheduler {
+	if inner == nil {
+		panic("NewStarvationGuardScheduler: inner scheduler must not be nil")
+	}
+	if maxWaitTicks <= 0 {
+		panic(fmt.Sprintf("NewStarvationGuardScheduler: maxWaitTicks must be > 0, got %d", maxWaitTicks))
+	}
+	return &StarvationGuardScheduler{inner: inner, maxWaitTicks: maxWaitTicks}
+}
+
+func (s *StarvationGuardScheduler) OrderQueue(reqs []*Request, clock int64) {
+	s.inner.OrderQueue(reqs, clock)
+	overdue := make([]*Request, 0)
+	rest := make([]*Request, 0, len(reqs))
+	for _, r := range reqs {
+		if clock-r.ArrivalTime >= s.maxWaitTicks {
+			overdue = append(overdue, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	if len(overdue) == 0 {
+		return
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		if overdue[i].ArrivalTime != overdue[j].ArrivalTime {
+			return overdue[i].ArrivalTime < overdue[j].ArrivalTime
+		}
+		return overdue[i].ID < overdue[j].ID
+	})
+	for _, r := range overdue {
+		if !r.starvationPromoted {
+			r.starvationPromoted = true
+			if s.onPromote != nil {
+				s.onPromote(r)
+			}
+		}
+	}
+	copy(reqs, overdue)
+	copy(reqs[len(overdue):], rest)
+}
+
 // NewScheduler creates an InstanceScheduler by name.
 // Valid names are defined in validSchedulers (bundle.go).
 // Empty string defaults to FCFSScheduler (for CLI flag default compatibility).
@@ -92,6 +259,10 @@ func NewScheduler(name string) InstanceScheduler {
 		return &SJFScheduler{}
 	case "reverse-priority":
 		return &ReversePriority{}
+	case "prefix-pack":
+		return &PrefixPackScheduler{}
+	case "wfq":
+		return &WFQScheduler{}
 	default:
 		panic(fmt.Sprintf("unhandled scheduler %q", name))
 	}
@@ -1,6 +1,8 @@
 package sim
 
 import (
+	"fmt"
+	"sort"
 	"testing"
 )
 
@@ -245,8 +247,8 @@ func TestSimulator_PriorityFCFS_SchedulesHighPriorityFirst(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(1000, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(1, 2048, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
-		PolicyConfig:        NewPolicyConfig("priority-fcfs", ""),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		PolicyConfig:        NewPolicyConfig("priority-fcfs", "", ""),
 	}
 	s := mustNewSimulator(t, cfg)
 
@@ -295,7 +297,7 @@ func TestSimulator_DefaultConfig_MatchesFCFS(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(1000, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(1, 2048, 0), // force sequential: only 1 at a time
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 		// Scheduler left empty (defaults to fcfs)
 	}
 	s := mustNewSimulator(t, cfg)
@@ -365,8 +367,8 @@ func TestSimulator_SJF_SchedulesShortJobFirst(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(1000, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(256, 2048, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{0, 0, 100}), // zero queueing delay so both queue at arrival time
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
-		PolicyConfig:        NewPolicyConfig("sjf", ""),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		PolicyConfig:        NewPolicyConfig("sjf", "", ""),
 	}
 	s := mustNewSimulator(t, cfg)
 
@@ -418,8 +420,8 @@ func TestSimulator_PriorityFCFS_ArrivalTimeTiebreak_OlderFirst(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(1000, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(1, 2048, 0), // only 1 slot: forces sequential scheduling
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{1000, 10, 5}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
-		PolicyConfig:        NewPolicyConfig("priority-fcfs", ""),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		PolicyConfig:        NewPolicyConfig("priority-fcfs", "", ""),
 	}
 	s := mustNewSimulator(t, cfg)
 
@@ -499,3 +501,326 @@ func TestPriorityFCFSScheduler_LowerFirstOrder(t *testing.T) {
 			reqs[0].ID, reqs[1].ID, reqs[2].ID)
 	}
 }
+
+// TestSimulator_ExplicitPriority_SchedulesHigherFirstDespiteArrival verifies
+// that under PriorityPolicy "explicit" the priority-fcfs scheduler orders the
+// wait queue by Request.ExplicitPriority (higher first, arrival time as
+// tiebreak), ignoring both arrival order and SLO class.
+func TestSimulator_ExplicitPriority_SchedulesHigherFirstDespiteArrival(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(1, 2048, 0) // one slot: requests run one at a time
+	cfg.PolicyConfig = NewPolicyConfig("priority-fcfs", "", PriorityPolicyExplicit)
+	s := mustNewSimulator(t, cfg)
+
+	newReq := func(id string, arrival int64, pri int, class string) *Request {
+		return &Request{
+			ID: id, ArrivalTime: arrival, ExplicitPriority: pri, SLOClass: class,
+			InputTokens: make([]TokenID, 20), OutputTokens: make([]TokenID, 4), State: StateQueued,
+		}
+	}
+	// The blocker occupies the only slot while the rest queue up behind it.
+	blocker := newReq("blocker", 0, 0, "")
+	blocker.OutputTokens = make([]TokenID, 50)
+	queued := []*Request{
+		newReq("p1_critical", 10, 1, "critical"),
+		newReq("p5_batch", 20, 5, "batch"),
+		newReq("p3", 30, 3, ""),
+		newReq("p5_later", 40, 5, ""),
+		newReq("p-2", 50, -2, "critical"),
+	}
+	s.InjectArrival(blocker)
+	for _, r := range queued {
+		s.InjectArrival(r)
+	}
+	s.Run()
+
+	if s.Metrics.CompletedRequests != 6 {
+		t.Fatalf("completed: got %d, want 6", s.Metrics.CompletedRequests)
+	}
+	sort.SliceStable(queued, func(i, j int) bool { return queued[i].ScheduledStepIdx < queued[j].ScheduledStepIdx })
+	got := requestIDs(queued)
+	want := []string{"p5_batch", "p5_later", "p3", "p1_critical", "p-2"}
+	if !sliceEqual(got, want) {
+		t.Errorf("scheduling order = %v, want %v", got, want)
+	}
+}
+
+func TestPrefixPackScheduler_GroupsSharedPrefixesCachedFirst(t *testing.T) {
+	prefixed := func(id string, arrival int64, first TokenID) *Request {
+		tokens := make([]TokenID, 8)
+		for i := range tokens {
+			tokens[i] = first + TokenID(i)
+		}
+		return &Request{ID: id, ArrivalTime: arrival, InputTokens: tokens}
+	}
+	reqs := []*Request{
+		prefixed("a1", 10, 100),
+		prefixed("b1", 20, 200),
+		prefixed("short", 25, 300),
+		prefixed("a2", 30, 100),
+		prefixed("c1", 40, 400),
+		prefixed("b2", 50, 200),
+		prefixed("c2", 60, 400),
+	}
+	reqs[2].InputTokens = reqs[2].InputTokens[:2] // shorter than a block: its own group
+	// Only group c's prefix is resident in the KV cache.
+	s := &PrefixPackScheduler{
+		blockSize: 4,
+		cachedBlocks: func(tokens []TokenID) int {
+			if tokens[0] == 400 {
+				return 2
+			}
+			return 0
+		},
+	}
+	s.OrderQueue(reqs, 0)
+	got := requestIDs(reqs)
+	want := []string{"c1", "c2", "a1", "a2", "b1", "b2", "short"}
+	if !sliceEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+// TestPrefixPackScheduler_ClusteredPrefixes_FewerCacheMissesThanFCFS runs the
+// same burst of interleaved prefix groups under fcfs and prefix-pack. The KV
+// cache holds about four prefixes, so under FCFS each group's prefix is
+// evicted by the other groups before the group's next request is scheduled.
+func TestPrefixPackScheduler_ClusteredPrefixes_FewerCacheMissesThanFCFS(t *testing.T) {
+	const (
+		groups     = 8
+		perGroup   = 6
+		prefixLen  = 256
+		suffixLen  = 32
+		outputLen  = 8
+		blockSize  = 16
+		totalBlock = 96
+	)
+	run := func(scheduler string) *Simulator {
+		cfg := newTestSimConfig()
+		cfg.KVCacheConfig = NewKVCacheConfig(totalBlock, blockSize, 0, 0, 0, 0)
+		cfg.BatchConfig = NewBatchConfig(4, 2048, 0)
+		cfg.PolicyConfig = NewPolicyConfig(scheduler, "", "")
+		s := mustNewSimulator(t, cfg)
+		// Interleaved arrivals: a0 b0 c0 ... h0 a1 b1 ...
+		for k := 0; k < perGroup; k++ {
+			for g := 0; g < groups; g++ {
+				input := make([]TokenID, prefixLen+suffixLen)
+				for i := 0; i < prefixLen; i++ {
+					input[i] = TokenID(g*10000 + i)
+				}
+				for i := prefixLen; i < len(input); i++ {
+					input[i] = TokenID(1_000_000 + (k*groups+g)*100 + i)
+				}
+				s.InjectArrival(&Request{
+					ID:           fmt.Sprintf("g%d_r%d", g, k),
+					ArrivalTime:  int64(k*groups + g),
+					InputTokens:  input,
+					OutputTokens: make([]TokenID, outputLen),
+					State:        StateQueued,
+				})
+			}
+		}
+		s.Run()
+		if s.Metrics.CompletedRequests != groups*perGroup {
+			t.Fatalf("%s: completed %d, want %d", scheduler, s.Metrics.CompletedRequests, groups*perGroup)
+		}
+		return s
+	}
+	fcfs := run("fcfs")
+	pack := run("prefix-pack")
+
+	// Hit rate is over blocks and every request looks up the same number of
+	// prompt blocks, so a higher rate means fewer cache-miss tokens computed.
+	fcfsHit, packHit := fcfs.KVCache.CacheHitRate(), pack.KVCache.CacheHitRate()
+	if packHit <= fcfsHit {
+		t.Errorf("cache hit rate: prefix-pack %.3f, fcfs %.3f; want prefix-pack higher", packHit, fcfsHit)
+	}
+	// Same work finishing sooner = higher throughput.
+	if pack.Metrics.SimEndedTime >= fcfs.Metrics.SimEndedTime {
+		t.Errorf("makespan: prefix-pack %d, fcfs %d; want prefix-pack shorter",
+			pack.Metrics.SimEndedTime, fcfs.Metrics.SimEndedTime)
+	}
+	t.Logf("hit rate fcfs=%.3f prefix-pack=%.3f; makespan fcfs=%d prefix-pack=%d",
+		fcfsHit, packHit, fcfs.Metrics.SimEndedTime, pack.Metrics.SimEndedTime)
+}
+
+func TestWFQScheduler_InterleavesTenants(t *testing.T) {
+	// GIVEN alice's three-request burst queued ahead of bob's two and an untenanted one
+	reqs := []*Request{
+		{ID: "a1", TenantID: "alice", ArrivalTime: 0},
+		{ID: "a2", TenantID: "alice", ArrivalTime: 1},
+		{ID: "a3", TenantID: "alice", ArrivalTime: 2},
+		{ID: "b1", TenantID: "bob", ArrivalTime: 3},
+		{ID: "b2", TenantID: "bob", ArrivalTime: 4},
+		{ID: "u1", ArrivalTime: 5},
+	}
+	(&WFQScheduler{}).OrderQueue(reqs, 10)
+
+	// THEN each tenant's k-th request precedes every tenant's (k+1)-th
+	got := requestIDs(reqs)
+	want := []string{"a1", "b1", "u1", "a2", "b2", "a3"}
+	if !sliceEqual(got, want) {
+		t.Errorf("WFQ order: got %v, want %v", got, want)
+	}
+}
+
+func TestStarvationGuardScheduler_PromotesOverdueOldestFirst(t *testing.T) {
+	// GIVEN SJF wrapped with a 100-tick deadline and two long requests past it
+	reqs := []*Request{
+		{ID: "short", InputTokens: make([]TokenID, 10), ArrivalTime: 150},
+		{ID: "long_late", InputTokens: make([]TokenID, 300), ArrivalTime: 50},
+		{ID: "mid", InputTokens: make([]TokenID, 100), ArrivalTime: 120},
+		{ID: "long_early", InputTokens: make([]TokenID, 200), ArrivalTime: 0},
+	}
+	promoted := 0
+	s := NewStarvationGuardScheduler(&SJFScheduler{}, 100)
+	s.onPromote = func(*Request) { promoted++ }
+
+	// WHEN ordered at tick 150 (long_early and long_late have waited >= 100)
+	s.OrderQueue(reqs, 150)
+
+	// THEN overdue requests lead, oldest first; the rest keep SJF order
+	want := []string{"long_early", "long_late", "short", "mid"}
+	if got := requestIDs(reqs); !sliceEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	// AND each promoted request is counted once, even when still overdue next step
+	s.OrderQueue(reqs, 160)
+	if promoted != 2 {
+		t.Errorf("promotions = %d, want 2", promoted)
+	}
+}
+
+// TestSimulator_MaxQueueWait_DeadlineBetweenSteps_PromotedAtNextStep verifies
+// the bound when a deadline falls inside a step: the queue is ordered only
+// when a step is formed, so the request is promoted by the first step after
+// its deadline, up to one step late, then admitted once a slot is free.
+func TestSimulator_MaxQueueWait_DeadlineBetweenSteps_PromotedAtNextStep(t *testing.T) {
+	// GIVEN one batch slot, 1000-tick steps on multiples of 1000, two-step
+	// short requests queued just before the slot frees (SJF always prefers
+	// them), and a long request arriving mid-step at tick 100
+	const maxWait = 2500
+	cfg := newTestSimConfig()
+	cfg.BatchConfig = NewBatchConfig(1, 2048, 0)
+	cfg.PolicyConfig = NewPolicyConfig("sjf", "", "")
+	cfg.MaxQueueWaitTicks = maxWait
+	s := newFixedStepSimulator(t, cfg)
+	for i := 0; i < 4; i++ {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("short_%d", i),
+			InputTokens:  make([]TokenID, 10),
+			OutputTokens: make([]TokenID, 2),
+			ArrivalTime:  max(int64(i)*2000-1, 0),
+			State:        StateQueued,
+		})
+	}
+	s.InjectArrival(&Request{
+		ID:           "long",
+		InputTokens:  make([]TokenID, 200),
+		OutputTokens: make([]TokenID, 2),
+		ArrivalTime:  100,
+		State:        StateQueued,
+	})
+	promotedAt := map[string]int64{}
+	guard := s.scheduler.(*StarvationGuardScheduler)
+	count := guard.onPromote
+	guard.onPromote = func(r *Request) {
+		promotedAt[r.ID] = s.Clock
+		count(r)
+	}
+
+	// WHEN the simulation runs
+	s.Run()
+
+	// THEN the long request, overdue at tick 2600, is promoted by the step at
+	// tick 3000: past its deadline, but within one step of it
+	if got, ok := promotedAt["long"]; !ok || got != 3000 {
+		t.Errorf("long promoted at tick %d (promoted %v), want 3000", got, ok)
+	}
+	// AND admitted when short_1 frees the slot at tick 4000
+	if got, want := s.Metrics.RequestSchedulingDelays["long"], int64(4000-100); got != want {
+		t.Errorf("long waited %d ticks, want %d", got, want)
+	}
+	if s.Metrics.StarvationPromotions != 1 {
+		t.Errorf("StarvationPromotions = %d, want 1", s.Metrics.StarvationPromotions)
+	}
+}
+
+// TestSimulator_MaxQueueWait_HeavyLoad_WaitBoundedByDeadlinePlusSteps
+// verifies the guard's bound under sustained load with deadlines that fall
+// inside steps. The queue is ordered only when a step is formed, so an overdue
+// request is promoted by the first step after its deadline (up to one step
+// late) and admitted when the slot next frees (up to one more step, the
+// remaining half of a two-step long request): no request waits longer than
+// MaxQueueWaitTicks + 2 steps.
+func TestSimulator_MaxQueueWait_HeavyLoad_WaitBoundedByDeadlinePlusSteps(t *testing.T) {
+	// GIVEN one batch slot and 1000-tick steps kept busy by a steady stream of
+	// short requests under SJF, which starves the long requests interleaved
+	// with them, every arrival off the step grid
+	const (
+		maxWait  = 10_000
+		stepTime = 1000
+		bound    = maxWait + 2*stepTime
+	)
+	run := func(maxQueueWait int64) *Simulator {
+		cfg := newTestSimConfig()
+		cfg.BatchConfig = NewBatchConfig(1, 2048, 0)
+		cfg.PolicyConfig = NewPolicyConfig("sjf", "", "")
+		cfg.MaxQueueWaitTicks = maxQueueWait
+		s := newFixedStepSimulator(t, cfg)
+		for i := 0; i < 120; i++ {
+			s.InjectArrival(&Request{
+				ID:           fmt.Sprintf("short_%03d", i),
+				InputTokens:  make([]TokenID, 10),
+				OutputTokens: make([]TokenID, 1),
+				ArrivalTime:  int64(i)*stepTime + 137,
+				State:        StateQueued,
+			})
+		}
+		for i := 0; i < 4; i++ {
+			s.InjectArrival(&Request{
+				ID:           fmt.Sprintf("long_%d", i),
+				InputTokens:  make([]TokenID, 200),
+				OutputTokens: make([]TokenID, 2),
+				ArrivalTime:  int64(i)*3000 + 411 + int64(i)*173,
+				State:        StateQueued,
+			})
+		}
+		s.Run()
+		if s.Metrics.CompletedRequests != 124 {
+			t.Fatalf("CompletedRequests = %d, want 124", s.Metrics.CompletedRequests)
+		}
+		return s
+	}
+	maxDelay := func(s *Simulator) int64 {
+		var worst int64
+		for _, d := range s.Metrics.RequestSchedulingDelays {
+			worst = max(worst, d)
+		}
+		return worst
+	}
+
+	// Baseline: without the guard the long requests wait far past the bound.
+	if base := run(0); maxDelay(base) <= bound || base.Metrics.StarvationPromotions != 0 {
+		t.Fatalf("baseline: max wait %d, promotions %d; want starvation beyond %d and no promotions",
+			maxDelay(base), base.Metrics.StarvationPromotions, bound)
+	}
+
+	// WHEN the same workload runs with MaxQueueWaitTicks set
+	s := run(maxWait)
+
+	// THEN no request waits longer than the deadline plus two steps
+	for id, d := range s.Metrics.RequestSchedulingDelays {
+		if d > bound {
+			t.Errorf("%s waited %d ticks, exceeds MaxQueueWaitTicks %d + 2 steps", id, d, maxWait)
+		}
+	}
+	// AND with deadlines off the step grid, promoted requests do overshoot the
+	// bare deadline: the step slack is needed, not just tolerated
+	if worst := maxDelay(s); worst <= maxWait {
+		t.Errorf("max wait %d <= MaxQueueWaitTicks %d; want off-grid deadlines to be served late", worst, maxWait)
+	}
+	if s.Metrics.StarvationPromotions <= 0 {
+		t.Errorf("StarvationPromotions = %d, want > 0", s.Metrics.StarvationPromotions)
+	}
+}
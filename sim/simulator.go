@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
@@ -66,6 +68,10 @@ type SimConfig struct {
 	// Simulation control (no sub-config — no factory uses only these)
 	Horizon int64
 	Seed    int64
+	// RandSource names the bit generator behind every RNG stream (see
+	// NewRandSource). "" selects the stdlib source, byte-identical to a
+	// pre-feature build (INV-6); RandSourceXoshiro is stable across Go versions.
+	RandSource string
 
 	// Module-scoped sub-configs (R16)
 	KVCacheConfig
@@ -84,8 +90,243 @@ type SimConfig struct {
 	// Shared with admission: same overrides flow from policy bundle slo_priorities.
 	// Set programmatically in cmd/root.go and cmd/replay.go from parsed bundle/CLI overrides — no YAML tag needed.
 	SLOPriorityOverrides map[string]int
+
+	// Cold-start warmup penalty. The first WarmupSteps steps on a fresh instance
+	// have their step time multiplied by a factor that decays linearly from
+	// WarmupFactor (first step) toward 1.0 (steady state). Zero WarmupSteps
+	// disables the penalty and output is byte-identical to a pre-feature build (INV-6).
+	WarmupSteps  int
+	WarmupFactor float64
+
+	// Bounded wait queue. MaxQueueDepth caps the number of requests in the wait
+	// queue (0 = unbounded, the default). When an arrival finds the queue full,
+	// QueueOverflowPolicy decides who is turned away: QueueOverflowRejectNew
+	// ("" is treated the same) or QueueOverflowDropOldest.
+	MaxQueueDepth       int
+	QueueOverflowPolicy string
+
+	// Anti-starvation deadline. When > 0 the configured Scheduler is wrapped in
+	// a StarvationGuardScheduler: a request that has waited MaxQueueWaitTicks
+	// or longer since arrival is moved to the front of the wait queue at the
+	// next step formed (up to one step after the deadline), counted once in
+	// Metrics.StarvationPromotions. 0 disables the guard (INV-6).
+	MaxQueueWaitTicks int64
+
+	// KV memory-pressure admission throttle. When KV utilization exceeds
+	// KVPressureThreshold (a fraction in [0, 1)), the number of running requests
+	// batch formation may admit up to shrinks linearly from MaxRunningReqs at the
+	// threshold toward 1 at full utilization, leaving headroom for running requests'
+	// decode growth. 0 disables the throttle (INV-6).
+	KVPressureThreshold float64
+
+	// CPU detokenization overhead in microseconds per output token. Each completed
+	// request's E2E grows by DetokenizationUsPerToken × its output token count; step
+	// (GPU) time, TTFT, and ITL are unaffected. 0 disables the term (INV-6).
+	DetokenizationUsPerToken float64
+
+	// Server-side output length cap (vLLM max_tokens default). A request whose
+	// sampled output exceeds MaxOutputTokens is truncated to it at enqueue and
+	// completes by length instead of EOS, shortening its decode phase; requests
+	// at or below the cap are untouched. 0 disables the cap (INV-6).
+	MaxOutputTokens int
+
+	// Multi-token decode: every decode step advances a request by up to
+	// TokensPerDecodeStep output tokens in one forward pass (deterministic, no
+	// acceptance sampling — not speculative decoding). The latency backend charges
+	// the step for all of them, and each generated token gets its own ITL entry.
+	// 0 or 1 decodes one token per step (INV-6).
+	TokensPerDecodeStep int
+
+	// Min-batch-fill wait. When MinBatchFill > 0, an idle instance (empty running
+	// batch) with fewer than MinBatchFill queued requests does not launch a step
+	// until enough requests have queued or the oldest of them has waited
+	// BatchFillMaxWaitTicks since arrival, trading TTFT for larger, more
+	// efficient batches at low load. 0 disables the wait (INV-6).
+	MinBatchFill          int
+	BatchFillMaxWaitTicks int64
+
+	// Decode length bucketing. When DecodeLengthBuckets > 0, decode attention is
+	// modeled as padded: every decode request in a forward pass is charged at the
+	// longest context in that pass. With 1 the whole batch is one padded pass;
+	// with N > 1 the decode requests are split into up to N context-length
+	// buckets, each charged as its own padded pass, trading an extra pass per
+	// bucket for less padding waste on heterogeneous contexts. The grouping is
+	// made by DecodeBucketBatchFormation, wrapping the configured formation.
+	// 0 charges the batch unpadded as a single pass (INV-6).
+	DecodeLengthBuckets int
+
+	// Time-sliced fair decode. When DecodeQuantumSteps > 0, every that many
+	// steps the running batch is reordered by least service received (output
+	// tokens decoded so far), and decode requests that have received more
+	// service than the requests at the wait-queue head are suspended to the
+	// back of the queue, keeping their KV blocks and progress, so the waiting
+	// requests take their slots. 0 disables rotation (INV-6).
+	DecodeQuantumSteps int
+
+	// Preemption mode: PreemptionModeRecompute ("" or "recompute", the
+	// default) discards a victim's KV and progress; PreemptionModeSwap copies
+	// its KV to host memory and back instead, charging each copy to step time
+	// at KVCacheConfig.KVTransferBandwidth blocks/tick plus
+	// KVTransferBaseLatency. Victims without progress and parallel-sample
+	// requests are always recomputed (INV-6).
+	PreemptionMode string
+
+	// Host swap space for PreemptionModeSwap, in KV blocks: a victim whose
+	// blocks would take the swapped-out requests past it is recomputed
+	// instead. 0 = unbounded (INV-6).
+	SwapSpaceBlocks int64
+
+	// Critical-class headroom. When CriticalReserveFraction > 0, that fraction
+	// of MaxRunningReqs and of MaxScheduledTokens (each rounded down) is held
+	// back for requests with SLOClass "critical": other requests are admitted,
+	// and their prefill chunks sized, only within the rest, and queued critical
+	// requests are moved ahead of the others to reach the reserved capacity
+	// (CriticalReserveBatchFormation). Must be < 1; 0 disables the reserve (INV-6).
+	CriticalReserveFraction float64
+
+	// Batch affinity. Each step pays BatchAffinitySwitchCostUs per distinct
+	// Request.AffinityKey in its batch beyond the first (the cost of
+	// switching adapters or kernels between groups); 0 charges nothing. When
+	// BatchAffinityGrouping is true, AffinityBatchFormation admits only requests
+	// sharing the running batch's key, one group at a time. Grouping cannot be
+	// combined with CriticalReserveFraction. false/0 disables both (INV-6).
+	BatchAffinityGrouping     bool
+	BatchAffinitySwitchCostUs int64
+
+	// Adaptive chunked prefill. When AdaptivePrefillChunkMin > 0, each step's
+	// prefill chunk size replaces LongPrefillTokenThreshold: MaxScheduledTokens
+	// scaled by the share of the running batch (plus one prospective prefill)
+	// that is not decoding, and never below AdaptivePrefillChunkMin. Chunks
+	// shrink while many decode requests are active, keeping their inter-token
+	// latency steady, and grow to the whole budget when the batch is
+	// prefill-dominated. 0 keeps the fixed threshold (INV-6).
+	AdaptivePrefillChunkMin int64
+
+	// Step ordering. StepOrderingPrefillFirst charges each step's token budget
+	// to prefill chunks and new admissions before running decodes, which get
+	// what is left; StepOrderingDecodeFirst (or "") keeps vLLM's order of
+	// running requests first (INV-6).
+	StepOrdering string
+
+	// Instance power model. A step draws PowerIdleWatts + (PowerPeakWatts -
+	// PowerIdleWatts) × load watts, where load is the step's scheduled tokens as
+	// a fraction of MaxScheduledTokens (at most 1), and its energy (draw × compute
+	// time) is accumulated in Metrics.EnergyJoules. When PowerCapWatts > 0 and
+	// the draw would exceed it, the clock is throttled: dynamic power scales with
+	// the cube of clock frequency, so the step's compute time is stretched by
+	// ((draw - idle) / (cap - idle))^(1/3) and it draws exactly the cap.
+	// PowerPeakWatts = 0 disables the model (INV-6).
+	PowerIdleWatts float64
+	PowerPeakWatts float64
+	PowerCapWatts  float64
+
+	// KV allocation mode. KVAllocationGreedy ("" is treated the same) lets any
+	// request grow until the cache is full. KVAllocationFairShare caps each
+	// request's KV blocks while other requests are running: at
+	// KVFairShareMaxBlocks when > 0, else TotalKVBlocks / running requests.
+	// A request that reaches its cap is chunked down to fit, or preempted when
+	// it cannot grow at all, so one huge context cannot starve the batch.
+	KVAllocationMode     string
+	KVFairShareMaxBlocks int64
+
+	// Remote prefix fetch cost in microseconds per KV block. When a request
+	// carries Request.RemotePrefixBlocks (cluster shared prefix cache), the
+	// fetched blocks skip prefill compute and instead add this cost per block
+	// to the admitting step.
+	RemotePrefixFetchUsPerBlock float64
+
+	// Scheduling overhead in microseconds per sequence in the batch, added to
+	// every step time by the latency model (host-side work such as building
+	// block tables). 0 = disabled. Applied where the latency model is built
+	// with latency.WithSchedulingOverhead (cluster instances).
+	SchedulingOverheadUsPerSeq float64
+	// Scheduling contention in microseconds per sequence squared, added to
+	// every step time alongside SchedulingOverheadUsPerSeq (host-side work that
+	// grows faster than the batch, such as lock and allocator contention). It
+	// makes throughput peak at an interior MaxRunningReqs. 0 = disabled.
+	// Applied with latency.WithSchedulingContention (cluster instances).
+	SchedulingContentionUsPerSeqSq float64
+
+	// Minimum forward-pass time per step in ticks, modeling the fixed kernel
+	// launch overhead that even a single-token step pays. Applied by the
+	// latency model before scheduling overhead; 0 = no floor. Applied where
+	// the latency model is built with latency.WithMinStepTime (cluster instances).
+	MinStepTimeTicks int64
+
+	// Step-time noise: every step time is multiplied by a seeded AR(1) factor
+	// with mean 1, stationary standard deviation StepNoiseMagnitude (in
+	// [0, 0.5]) and lag-1 correlation StepNoiseCorrelation (in [0, 1)).
+	// 0 magnitude = disabled. Applied where the latency model is built with
+	// latency.NewNoisyLatencyModel (cluster instances, one stream each).
+	StepNoiseMagnitude   float64
+	StepNoiseCorrelation float64
+
+	// RooflineBlockTable charges paged-attention block-table reads (one entry
+	// per KV block of context, per layer) in roofline step time. false keeps
+	// roofline byte-identical to its golden dataset. Applied where the latency
+	// model is built with latency.WithBlockSize (cluster instances).
+	RooflineBlockTable bool
+
+	// RooflineAccounting records each roofline forward pass's FLOPs and memory
+	// bytes into Metrics.Roofline, exported as compute/memory-bound step
+	// fractions, mean arithmetic intensity and effective MFU (achieved FLOPs/sec
+	// over the busy period as a fraction of peak). Observation only: step times are
+	// unchanged. Applied where the latency model is built with
+	// latency.WithRooflineStats (cluster instances); other backends ignore it.
+	RooflineAccounting bool
+
+	// Steady-state stopping condition. When > 0 the cluster pulls arrivals
+	// from its RequestSource on demand (so the source may be unbounded) and
+	// halts once this many requests have completed; requests still queued or
+	// running are left unfinished and reported as StillQueued/StillRunning.
+	// Exactly N complete: requests finishing in the same step as the Nth stay
+	// running (StillRunning) rather than completing. 0 = disabled.
+	StopAfterCompleted int64
+
+	// KV prefix seeding. Each sequence in KVPrefixSeeds is written into the
+	// KV cache as free, hashed blocks before any arrival, so the first request
+	// sharing that prefix gets prefix-cache hits. The prefill time of the
+	// seeded blocks is charged once, to the instance's first step. nil
+	// disables seeding (INV-6).
+	KVPrefixSeeds [][]TokenID
+
+	// KV warm start. Each sequence in KVWarmPrefixes (typically a prior run's
+	// KVStore.ExportPrefixes) is written into the KV cache with SeedPrefix,
+	// in order, before KVPrefixSeeds and any arrival. Unlike KVPrefixSeeds no
+	// prefill time is charged: the cache is taken to have survived from the
+	// earlier run. nil starts cold (INV-6).
+	KVWarmPrefixes [][]TokenID
+
+	// Throughput-over-time sampling. When > 0, Metrics.CompletedSeries records
+	// the cumulative completed-request count every ThroughputSampleIntervalUs
+	// ticks. Sampling is observational and schedules no events. 0 disables it
+	// (INV-6).
+	ThroughputSampleIntervalUs int64
+
+	// KV usage sampling. When > 0, Metrics.KVUsedSeries records the most KV
+	// blocks in use during each KVSampleIntervalUs-tick interval. Like
+	// throughput sampling it is observational and schedules no events. 0
+	// disables it (INV-6).
+	KVSampleIntervalUs int64
 }
 
+// Wait-queue overflow policies for SimConfig.QueueOverflowPolicy.
+const (
+	// QueueOverflowRejectNew rejects the arriving request; queued requests are kept.
+	QueueOverflowRejectNew = "reject-new"
+	// QueueOverflowDropOldest evicts the queued request with the earliest arrival
+	// time to make room for the newcomer.
+	QueueOverflowDropOldest = "drop-oldest"
+)
+
+// KV allocation modes for SimConfig.KVAllocationMode.
+const (
+	// KVAllocationGreedy allocates blocks first-come-first-served until the cache is full.
+	KVAllocationGreedy = "greedy"
+	// KVAllocationFairShare caps per-request blocks at a fair share under contention.
+	KVAllocationFairShare = "fair-share"
+)
+
 // Simulator is the core object that holds simulation time, system state, and the event loop.
 type Simulator struct {
 	Clock   int64
@@ -108,14 +349,54 @@ type Simulator struct {
 	longPrefillTokenThreshold int64
 	stepEvent                 Event
 	stepCount                 int
+	warmupSteps               int     // cold-start steps with inflated step time (0 = disabled)
+	warmupFactor              float64 // step-time multiplier on the first step; decays to 1.0
+	maxQueueDepth             int     // wait-queue bound (0 = unbounded)
+	queueOverflowPolicy       string  // QueueOverflowRejectNew or QueueOverflowDropOldest
+	kvPressureThreshold       float64 // KV utilization above which admissions are throttled (0 = disabled)
+	detokenizationUsPerToken  float64 // CPU output-processing cost per output token, added to E2E (0 = disabled)
+	maxOutputTokens           int     // server-side output length cap (0 = disabled)
+	tokensPerDecodeStep       int64   // output tokens per decode step (<= 1 = one)
+	minBatchFill              int64   // queued requests an idle instance waits for (0 = disabled)
+	batchFillMaxWait          int64   // max ticks the oldest queued request is held for minBatchFill
+	batchFillWake             *BatchFillWakeEvent // pending end of the current min-batch-fill hold, or nil
+	inTransit                 map[string]*Request // InjectArrivalAt requests not yet queued (RemainingDecodeTokens)
+	forwardPasses             [][]*Request // this step's forward passes (BatchResult.ForwardPasses); nil = one pass
+	decodeQuantumSteps        int     // steps between fair-decode rotations (0 = disabled)
+	adaptivePrefillChunkMin   int64   // floor of the decode-load-adaptive prefill chunk (0 = fixed threshold)
+	prefillFirst              bool    // StepOrderingPrefillFirst: decodes take the budget prefills leave
+	schedOverheadUsPerSeq     float64 // per-sequence step overhead the latency model adds, for Metrics.TimeBudget attribution
+	schedContentionUsPerSeqSq float64 // per-sequence² step overhead the latency model adds, likewise
+	powerIdleWatts            float64 // power draw of an idle step
+	powerPeakWatts            float64 // power draw at a full token budget (0 = power model off)
+	powerCapWatts             float64 // per-instance power cap (0 = unlimited)
+	kvFairShare               bool    // cap per-request KV blocks under contention (KVAllocationFairShare)
+	kvFairShareMaxBlocks      int64   // fixed per-request cap; 0 = TotalKVBlocks / running requests
+	remoteFetchUsPerBlock     float64 // step-time cost per block fetched from a remote prefix cache
+	affinitySwitchCost        int64   // step-time cost per extra BatchAffinityKey group in a batch
+	pendingRemoteFetchLatency int64   // remote prefix fetch latency for the step being formed
+	swapPreemption            bool    // PreemptionModeSwap: victims swap their KV out instead of recomputing
+	swapBandwidth             float64 // swap transfer rate in blocks/tick
+	swapBaseLatency           int64   // fixed cost per swap transfer in ticks
+	swapSpaceBlocks           int64   // host blocks swapped-out requests may hold; 0 = unbounded
+	pendingSwapLatency        int64   // swap transfer latency for the step being formed
+	pendingPrefixSeedLatency  int64   // one-time prefill cost of KVPrefixSeeds, charged to the first step
+	recentTTFTs               *latencyWindow // TTFTs of the last RecentLatencyWindowSize completions
+	recentPreemptions         *eventWindow   // preemption ticks within the last RecentPreemptionWindowUs
 	// map of request IDs to total num computed tokens (including cached tokens)
 	reqNumComputedTokens map[string]int64
+	reqComputeTicks      map[string]int64 // step compute credited to each running request since admission (Metrics.TimeBudget)
+	reqAttemptStart      map[string]int64 // tick each running request was last scheduled
+	reqPreemptedAttempt  map[string]int64 // start tick of a preempted request's discarded attempt, until rescheduled
 	batchFormation       BatchFormation
 	model                  string
 	gpu                    string
 	maxModelLen            int64 // max total sequence length (0 = unlimited)
 	rng                    *PartitionedRNG // partitioned RNG for deterministic multi-subsystem simulation
 	sloMap *SLOPriorityMap // vLLM-convention priority mapping for instance-level scheduling
+	// explicitPriority selects Request.ExplicitPriority over the SLO class as the
+	// source of instance-level priority (PriorityPolicy "explicit").
+	explicitPriority bool
 	scheduler      InstanceScheduler
 	latencyModel           LatencyModel
 	// residentAdapters tracks this instance's finite resident LoRA adapter slots
@@ -131,6 +412,13 @@ type Simulator struct {
 	// this instance, or "" when none. Loads serialize per instance: the gate starts
 	// a new load only when this is "" (§7 serialization).
 	loadingAdapter string
+	// remotePrefixCached re-checks Request.RemotePrefixBlocks against the owner's
+	// cache when the fetch is charged (SetRemotePrefixLookup). nil ⇒ the
+	// routing-time count is fetched.
+	remotePrefixCached func(*Request) int
+	// completionGate is asked before a finished request completes; false keeps
+	// it running (SetCompletionGate). nil ⇒ every finished request completes.
+	completionGate func(*Request) bool
 	seqCounter             int64 // monotonic counter for event queue seqID (deterministic ordering)
 	// OnRequestDone is an optional callback invoked when a request reaches a terminal
 	// state (completed, length-capped, or timed out). Returns follow-up requests to inject.
@@ -140,6 +428,9 @@ type Simulator struct {
 	progressHook                ProgressHook
 	simClockProgressIntervalUs int64
 	nextSnapshotClockUs        int64
+
+	probeTicks     []int64         // pending probe ticks, ascending (SetProbeTicks)
+	probeSnapshots []ProbeSnapshot // recorded probe snapshots, in tick order
 }
 
 // NewSimulator creates a Simulator from a SimConfig struct and pre-built dependencies.
@@ -177,7 +468,131 @@ func NewSimulator(cfg SimConfig, kvStore KVStore, latencyModel LatencyModel) (*S
 				blocksForMaxLen, cfg.MaxModelLen, cfg.BlockSizeTokens, cfg.TotalKVBlocks)
 		}
 	}
+	if cfg.WarmupSteps < 0 {
+		return nil, fmt.Errorf("NewSimulator: WarmupSteps must be >= 0, got %d", cfg.WarmupSteps)
+	}
+	if cfg.WarmupSteps > 0 && (cfg.WarmupFactor < 1 || math.IsNaN(cfg.WarmupFactor) || math.IsInf(cfg.WarmupFactor, 0)) {
+		return nil, fmt.Errorf("NewSimulator: WarmupFactor must be a finite value >= 1 when WarmupSteps > 0, got %v", cfg.WarmupFactor)
+	}
+	if cfg.MaxQueueDepth < 0 {
+		return nil, fmt.Errorf("NewSimulator: MaxQueueDepth must be >= 0, got %d", cfg.MaxQueueDepth)
+	}
+	if cfg.MaxQueueWaitTicks < 0 {
+		return nil, fmt.Errorf("NewSimulator: MaxQueueWaitTicks must be >= 0, got %d", cfg.MaxQueueWaitTicks)
+	}
+	if !IsValidQueueOverflowPolicy(cfg.QueueOverflowPolicy) {
+		return nil, fmt.Errorf("NewSimulator: unknown QueueOverflowPolicy %q; valid: %s", cfg.QueueOverflowPolicy, strings.Join(ValidQueueOverflowPolicyNames(), ", "))
+	}
+	if cfg.KVPressureThreshold < 0 || cfg.KVPressureThreshold >= 1 || math.IsNaN(cfg.KVPressureThreshold) {
+		return nil, fmt.Errorf("NewSimulator: KVPressureThreshold must be in [0, 1), got %v", cfg.KVPressureThreshold)
+	}
+	if cfg.DetokenizationUsPerToken < 0 || math.IsNaN(cfg.DetokenizationUsPerToken) || math.IsInf(cfg.DetokenizationUsPerToken, 0) {
+		return nil, fmt.Errorf("NewSimulator: DetokenizationUsPerToken must be a finite value >= 0, got %v", cfg.DetokenizationUsPerToken)
+	}
+	if cfg.MaxOutputTokens < 0 {
+		return nil, fmt.Errorf("NewSimulator: MaxOutputTokens must be >= 0, got %d", cfg.MaxOutputTokens)
+	}
+	if cfg.TokensPerDecodeStep < 0 {
+		return nil, fmt.Errorf("NewSimulator: TokensPerDecodeStep must be >= 0, got %d", cfg.TokensPerDecodeStep)
+	}
+	if cfg.MinBatchFill < 0 {
+		return nil, fmt.Errorf("NewSimulator: MinBatchFill must be >= 0, got %d", cfg.MinBatchFill)
+	}
+	if cfg.BatchFillMaxWaitTicks < 0 {
+		return nil, fmt.Errorf("NewSimulator: BatchFillMaxWaitTicks must be >= 0, got %d", cfg.BatchFillMaxWaitTicks)
+	}
+	if !IsValidRandSource(cfg.RandSource) {
+		return nil, fmt.Errorf("NewSimulator: unknown RandSource %q; valid: %v", cfg.RandSource, ValidRandSourceNames())
+	}
+	if cfg.DecodeLengthBuckets < 0 {
+		return nil, fmt.Errorf("NewSimulator: DecodeLengthBuckets must be >= 0, got %d", cfg.DecodeLengthBuckets)
+	}
+	if cfg.DecodeQuantumSteps < 0 {
+		return nil, fmt.Errorf("NewSimulator: DecodeQuantumSteps must be >= 0, got %d", cfg.DecodeQuantumSteps)
+	}
+	if cfg.AdaptivePrefillChunkMin < 0 {
+		return nil, fmt.Errorf("NewSimulator: AdaptivePrefillChunkMin must be >= 0, got %d", cfg.AdaptivePrefillChunkMin)
+	}
+	if !IsValidStepOrdering(cfg.StepOrdering) {
+		return nil, fmt.Errorf("NewSimulator: unknown StepOrdering %q; valid: %v", cfg.StepOrdering, ValidStepOrderingNames())
+	}
+	for _, w := range []struct {
+		name string
+		v    float64
+	}{{"PowerIdleWatts", cfg.PowerIdleWatts}, {"PowerPeakWatts", cfg.PowerPeakWatts}, {"PowerCapWatts", cfg.PowerCapWatts}} {
+		if w.v < 0 || math.IsNaN(w.v) || math.IsInf(w.v, 0) {
+			return nil, fmt.Errorf("NewSimulator: %s must be a finite value >= 0, got %v", w.name, w.v)
+		}
+	}
+	if cfg.PowerPeakWatts > 0 && cfg.PowerPeakWatts <= cfg.PowerIdleWatts {
+		return nil, fmt.Errorf("NewSimulator: PowerPeakWatts (%v) must exceed PowerIdleWatts (%v)", cfg.PowerPeakWatts, cfg.PowerIdleWatts)
+	}
+	if cfg.PowerCapWatts > 0 && (cfg.PowerPeakWatts <= 0 || cfg.PowerCapWatts <= cfg.PowerIdleWatts) {
+		return nil, fmt.Errorf("NewSimulator: PowerCapWatts (%v) requires PowerPeakWatts > 0 and a cap above PowerIdleWatts (%v)", cfg.PowerCapWatts, cfg.PowerIdleWatts)
+	}
+	if cfg.RemotePrefixFetchUsPerBlock < 0 || math.IsNaN(cfg.RemotePrefixFetchUsPerBlock) || math.IsInf(cfg.RemotePrefixFetchUsPerBlock, 0) {
+		return nil, fmt.Errorf("NewSimulator: RemotePrefixFetchUsPerBlock must be a finite value >= 0, got %v", cfg.RemotePrefixFetchUsPerBlock)
+	}
+	if cfg.SchedulingOverheadUsPerSeq < 0 || math.IsNaN(cfg.SchedulingOverheadUsPerSeq) || math.IsInf(cfg.SchedulingOverheadUsPerSeq, 0) {
+		return nil, fmt.Errorf("NewSimulator: SchedulingOverheadUsPerSeq must be a finite value >= 0, got %v", cfg.SchedulingOverheadUsPerSeq)
+	}
+	if cfg.SchedulingContentionUsPerSeqSq < 0 || math.IsNaN(cfg.SchedulingContentionUsPerSeqSq) || math.IsInf(cfg.SchedulingContentionUsPerSeqSq, 0) {
+		return nil, fmt.Errorf("NewSimulator: SchedulingContentionUsPerSeqSq must be a finite value >= 0, got %v", cfg.SchedulingContentionUsPerSeqSq)
+	}
+	if cfg.MinStepTimeTicks < 0 {
+		return nil, fmt.Errorf("NewSimulator: MinStepTimeTicks must be >= 0, got %d", cfg.MinStepTimeTicks)
+	}
+	if cfg.ThroughputSampleIntervalUs < 0 {
+		return nil, fmt.Errorf("NewSimulator: ThroughputSampleIntervalUs must be >= 0, got %d", cfg.ThroughputSampleIntervalUs)
+	}
+	if !IsValidPreemptionMode(cfg.PreemptionMode) {
+		return nil, fmt.Errorf("NewSimulator: unknown PreemptionMode %q (valid: %s, %s)", cfg.PreemptionMode, PreemptionModeRecompute, PreemptionModeSwap)
+	}
+	if cfg.PreemptionMode == PreemptionModeSwap {
+		if bw := cfg.KVTransferBandwidth; bw <= 0 || math.IsNaN(bw) || math.IsInf(bw, 0) {
+			return nil, fmt.Errorf("NewSimulator: KVTransferBandwidth must be finite and > 0 with swap preemption, got %v", bw)
+		}
+		if cfg.KVTransferBaseLatency < 0 {
+			return nil, fmt.Errorf("NewSimulator: KVTransferBaseLatency must be >= 0 with swap preemption, got %d", cfg.KVTransferBaseLatency)
+		}
+	}
+	if cfg.SwapSpaceBlocks < 0 {
+		return nil, fmt.Errorf("NewSimulator: SwapSpaceBlocks must be >= 0, got %d", cfg.SwapSpaceBlocks)
+	}
+	if cfg.KVSampleIntervalUs < 0 {
+		return nil, fmt.Errorf("NewSimulator: KVSampleIntervalUs must be >= 0, got %d", cfg.KVSampleIntervalUs)
+	}
+	if cfg.StopAfterCompleted < 0 {
+		return nil, fmt.Errorf("NewSimulator: StopAfterCompleted must be >= 0, got %d", cfg.StopAfterCompleted)
+	}
+	if !IsValidPriorityPolicy(cfg.PriorityPolicy) {
+		return nil, fmt.Errorf("NewSimulator: unknown PriorityPolicy %q; valid: %s", cfg.PriorityPolicy, strings.Join(ValidPriorityPolicyNames(), ", "))
+	}
+	if !IsValidKVAllocationMode(cfg.KVAllocationMode) {
+		return nil, fmt.Errorf("NewSimulator: unknown KVAllocationMode %q; valid: %s", cfg.KVAllocationMode, strings.Join(ValidKVAllocationModeNames(), ", "))
+	}
+	if cfg.KVFairShareMaxBlocks < 0 {
+		return nil, fmt.Errorf("NewSimulator: KVFairShareMaxBlocks must be >= 0, got %d", cfg.KVFairShareMaxBlocks)
+	}
+	if !(cfg.CriticalReserveFraction >= 0 && cfg.CriticalReserveFraction < 1) {
+		return nil, fmt.Errorf("NewSimulator: CriticalReserveFraction must be in [0, 1), got %v", cfg.CriticalReserveFraction)
+	}
+	if cfg.BatchAffinitySwitchCostUs < 0 {
+		return nil, fmt.Errorf("NewSimulator: BatchAffinitySwitchCostUs must be >= 0, got %d", cfg.BatchAffinitySwitchCostUs)
+	}
+	if cfg.BatchAffinityGrouping && cfg.CriticalReserveFraction > 0 {
+		return nil, fmt.Errorf("NewSimulator: BatchAffinityGrouping cannot be combined with CriticalReserveFraction")
+	}
 	batchFormation := NewBatchFormation(cfg.PreemptionPolicy)
+	if cfg.CriticalReserveFraction > 0 {
+		batchFormation = NewCriticalReserveBatchFormation(cfg.PreemptionPolicy, cfg.CriticalReserveFraction)
+	}
+	if cfg.BatchAffinityGrouping {
+		batchFormation = NewAffinityBatchFormation(cfg.PreemptionPolicy)
+	}
+	if cfg.DecodeLengthBuckets > 0 {
+		batchFormation = NewDecodeBucketBatchFormation(batchFormation, cfg.DecodeLengthBuckets)
+	}
 
 	s := &Simulator{
 		Clock:                     0,
@@ -192,16 +607,63 @@ func NewSimulator(cfg SimConfig, kvStore KVStore, latencyModel LatencyModel) (*S
 		longPrefillTokenThreshold: cfg.LongPrefillTokenThreshold,
 		stepEvent:                 nil,
 		stepCount:                 0,
+		warmupSteps:               cfg.WarmupSteps,
+		warmupFactor:              cfg.WarmupFactor,
+		maxQueueDepth:             cfg.MaxQueueDepth,
+		queueOverflowPolicy:       cfg.QueueOverflowPolicy,
+		kvPressureThreshold:       cfg.KVPressureThreshold,
+		detokenizationUsPerToken:  cfg.DetokenizationUsPerToken,
+		maxOutputTokens:           cfg.MaxOutputTokens,
+		tokensPerDecodeStep:       int64(cfg.TokensPerDecodeStep),
+		minBatchFill:              int64(cfg.MinBatchFill),
+		batchFillMaxWait:          cfg.BatchFillMaxWaitTicks,
+		decodeQuantumSteps:        cfg.DecodeQuantumSteps,
+		adaptivePrefillChunkMin:   cfg.AdaptivePrefillChunkMin,
+		prefillFirst:              cfg.StepOrdering == StepOrderingPrefillFirst,
+		schedOverheadUsPerSeq:     cfg.SchedulingOverheadUsPerSeq,
+		schedContentionUsPerSeqSq: cfg.SchedulingContentionUsPerSeqSq,
+		powerIdleWatts:            cfg.PowerIdleWatts,
+		powerPeakWatts:            cfg.PowerPeakWatts,
+		powerCapWatts:             cfg.PowerCapWatts,
+		kvFairShare:               cfg.KVAllocationMode == KVAllocationFairShare,
+		kvFairShareMaxBlocks:      cfg.KVFairShareMaxBlocks,
+		remoteFetchUsPerBlock:     cfg.RemotePrefixFetchUsPerBlock,
+		swapPreemption:            cfg.PreemptionMode == PreemptionModeSwap,
+		swapBandwidth:             cfg.KVTransferBandwidth,
+		swapBaseLatency:           cfg.KVTransferBaseLatency,
+		swapSpaceBlocks:           cfg.SwapSpaceBlocks,
+		affinitySwitchCost:        cfg.BatchAffinitySwitchCostUs,
+		recentTTFTs:               newLatencyWindow(RecentLatencyWindowSize),
+		recentPreemptions:         newEventWindow(RecentPreemptionWindowUs),
 		reqNumComputedTokens:      make(map[string]int64),
+		reqComputeTicks:           make(map[string]int64),
+		reqAttemptStart:           make(map[string]int64),
+		reqPreemptedAttempt:       make(map[string]int64),
 		batchFormation:            batchFormation,
 		model:                     cfg.Model,
 		gpu:                       cfg.GPU,
 		maxModelLen:               cfg.MaxModelLen,
 		latencyModel:              latencyModel,
 		sloMap:                    NewSLOPriorityMap(cfg.SLOPriorityOverrides),
+		explicitPriority:          cfg.PriorityPolicy == PriorityPolicyExplicit,
+	}
+	for _, prefix := range cfg.KVWarmPrefixes {
+		s.KVCache.SeedPrefix(prefix)
 	}
-	s.rng = NewPartitionedRNG(NewSimulationKey(cfg.Seed))
+	s.seedKVPrefixes(cfg.KVPrefixSeeds)
+	s.Metrics.ThroughputSampleIntervalUs = cfg.ThroughputSampleIntervalUs
+	s.Metrics.KVSampleIntervalUs = cfg.KVSampleIntervalUs
+	s.rng = NewPartitionedRNGWithSource(NewSimulationKey(cfg.Seed), cfg.RandSource)
 	s.scheduler = NewScheduler(cfg.Scheduler)
+	if pp, ok := s.scheduler.(*PrefixPackScheduler); ok {
+		pp.cachedBlocks = func(tokens []TokenID) int { return len(s.KVCache.GetCachedBlocks(tokens)) }
+		pp.blockSize = s.KVCache.BlockSize()
+	}
+	if cfg.MaxQueueWaitTicks > 0 {
+		guard := NewStarvationGuardScheduler(s.scheduler, cfg.MaxQueueWaitTicks)
+		guard.onPromote = func(*Request) { s.Metrics.StarvationPromotions++ }
+		s.scheduler = guard
+	}
 
 	// Defense-in-depth: reject a non-positive adapter capacity here rather than
 	// letting it reach newResidentSet as a panic. cmd/ validates via LoRAConfig.Validate,
@@ -260,6 +722,28 @@ func NewSimulator(cfg SimConfig, kvStore KVStore, latencyModel LatencyModel) (*S
 	return s, nil
 }
 
+// seedKVPrefixes writes each seed's full blocks into the KV cache and
+// accumulates the prefill time of the blocks actually written into
+// pendingPrefixSeedLatency. Blocks already cached (e.g. shared with an
+// earlier seed) cost nothing.
+func (sim *Simulator) seedKVPrefixes(seeds [][]TokenID) {
+	blockSize := sim.KVCache.BlockSize()
+	for i, seed := range seeds {
+		written := sim.KVCache.SeedPrefix(seed)
+		if written == 0 {
+			continue
+		}
+		full := util.Len64(seed) / blockSize * blockSize
+		warm := &Request{
+			ID:            fmt.Sprintf("kv-prefix-seed-%d", i),
+			InputTokens:   seed[:full],
+			ProgressIndex: full - written*blockSize,
+			NumNewTokens:  int(written * blockSize),
+		}
+		sim.pendingPrefixSeedLatency += sim.latencyModel.StepTime([]*Request{warm})
+	}
+}
+
 // WorkloadRNG returns the RNG for workload generation.
 // This maintains backward compatibility with the original single-RNG implementation.
 func (sim *Simulator) WorkloadRNG() *rand.Rand {
@@ -304,10 +788,13 @@ func (sim *Simulator) ProcessNextEvent() Event {
 	// timer when the response arrives. Skip it before advancing the clock so
 	// Finalize() captures SimEndedTime from the last real-work event, not from
 	// an orphaned no-op timeout 300s in the future.
-	if te, ok := ev.(*TimeoutEvent); ok && te.Request.State == StateCompleted {
+	if te, ok := ev.(*TimeoutEvent); ok && (te.Request.State == StateCompleted || te.Request.QueueDropped) {
 		return ev
 	}
 
+	sim.recordThroughputSamplesBefore(ev.Timestamp())
+	sim.recordKVSamplesBefore(ev.Timestamp())
+	sim.recordProbesBefore(ev.Timestamp())
 	sim.Clock = ev.Timestamp()
 	logrus.Debugf("[tick %07d] Executing %T", sim.Clock, ev)
 	ev.Execute(sim)
@@ -326,6 +813,12 @@ func (sim *Simulator) Finalize() {
 		sim.Metrics.StillRunning = len(sim.RunningBatch.Requests)
 	}
 	sim.Metrics.SimEndedTime = min(sim.Clock, sim.Horizon)
+	sim.Metrics.GPUIdleTicks = max(0, sim.Metrics.SimEndedTime-sim.Metrics.GPUBusyTicks)
+	sim.Metrics.KVTotalBlocks = sim.KVCache.TotalCapacity()
+	sim.Metrics.CompletedSeries = FinishCompletedSeries(sim.Metrics.CompletedSeries,
+		sim.Metrics.ThroughputSampleIntervalUs, sim.Metrics.SimEndedTime, sim.Metrics.CompletedRequests)
+	sim.finishKVSeries()
+	sim.recordProbesBefore(math.MaxInt64)
 	logrus.Infof("[tick %07d] Simulation ended", sim.Clock)
 }
 
@@ -345,6 +838,10 @@ func (sim *Simulator) InjectArrival(req *Request) {
 // Used by cluster-mode online routing where event time differs from original arrival.
 func (sim *Simulator) InjectArrivalAt(req *Request, eventTime int64) {
 	sim.Schedule(&ArrivalEvent{time: eventTime, Request: req})
+	if sim.inTransit == nil {
+		sim.inTransit = make(map[string]*Request)
+	}
+	sim.inTransit[req.ID] = req
 	sim.Metrics.Requests[req.ID] = NewRequestMetrics(req, float64(req.ArrivalTime)/1e6)
 }
 
@@ -374,6 +871,23 @@ func (sim *Simulator) SetProgressHook(hook ProgressHook, simClockIntervalUs int6
 	}
 }
 
+// SetRemotePrefixLookup installs the shared prefix cache check applied when a
+// request's remote prefix fetch is charged: fn returns how many of the request's
+// leading input blocks its RemotePrefixOwner still holds. Set by ClusterSimulator
+// when SharedPrefixCache is enabled; nil keeps the routing-time count.
+func (sim *Simulator) SetRemotePrefixLookup(fn func(*Request) int) {
+	sim.remotePrefixCached = fn
+}
+
+// SetCompletionGate installs a check asked before each finished request
+// completes in processCompletions. When fn returns false the request is not
+// completed: it stays in the running batch and is reported as StillRunning if
+// the run stops. Set by ClusterSimulator to cap completions at
+// StopAfterCompleted exactly; nil completes every finished request.
+func (sim *Simulator) SetCompletionGate(fn func(*Request) bool) {
+	sim.completionGate = fn
+}
+
 func (sim *Simulator) maybeDeliverProgressSnapshot(isFinal bool) {
 	if sim.progressHook == nil {
 		return
@@ -439,13 +953,107 @@ func (sim *Simulator) ResidentAdapterIDs() []string {
 
 // DrainWaitQueue removes and returns all requests currently in the wait queue.
 // Used by DrainRedirect policy to re-inject queued requests into the cluster router.
-// After this call, WaitQ.Len() == 0.
+// After this call, WaitQ.Len() == 0. A decode request suspended by the
+// fair-decode rotation gives up its KV blocks and progress, as on preemption,
+// and a swapped-out request its progress, so either restarts from prefill
+// wherever it is re-injected.
 func (sim *Simulator) DrainWaitQueue() []*Request {
 	items := sim.WaitQ.Items()
 	sim.WaitQ = &WaitQueue{}
+	for _, req := range items {
+		if req.Suspended || req.SwappedOut {
+			sim.releaseAdapterPin(req)
+			sim.KVCache.ReleaseKVBlocks(req)
+			delete(sim.reqNumComputedTokens, req.ID)
+			delete(sim.Metrics.RequestTTFTs, req.ID)
+			req.Suspended = false
+			req.SwappedOut = false
+			req.ProgressIndex = 0
+			req.ITL = nil
+			req.TTFTSet = false
+			req.FirstTokenTime = 0
+		}
+	}
 	return items
 }
 
+// Fail models an abrupt instance failure. Every request the instance holds —
+// running, queued, or still in its arrival pipeline (ArrivalEvent/QueuedEvent
+// not yet fired) — is removed and returned in that order, and all pending events
+// are discarded so the instance does no further work. Running requests release
+// their KV blocks and adapter pins. Returned requests are unregistered from
+// Metrics and reset to StateQueued with no progress, so callers may either
+// count them as lost or resubmit them to another instance.
+func (sim *Simulator) Fail() []*Request {
+	var failed []*Request
+	if sim.RunningBatch != nil {
+		for _, req := range sim.RunningBatch.Requests {
+			sim.releaseAdapterPin(req)
+			sim.KVCache.ReleaseKVBlocks(req)
+			failed = append(failed, req)
+		}
+		sim.RunningBatch = nil
+	}
+	for _, req := range sim.DrainWaitQueue() {
+		sim.KVCache.ReleaseKVBlocks(req) // no-op unless preempted while holding blocks
+		failed = append(failed, req)
+	}
+	for len(sim.eventQueue) > 0 {
+		switch ev := heap.Pop(&sim.eventQueue).(eventEntry).event.(type) {
+		case *ArrivalEvent:
+			failed = append(failed, ev.Request)
+		case *QueuedEvent:
+			failed = append(failed, ev.Request)
+		}
+	}
+	sim.stepEvent = nil
+
+	for _, req := range failed {
+		delete(sim.inTransit, req.ID)
+		delete(sim.reqNumComputedTokens, req.ID)
+		delete(sim.reqComputeTicks, req.ID)
+		sim.forgetPreemptionAttempt(req.ID)
+		delete(sim.Metrics.Requests, req.ID)
+		delete(sim.Metrics.RequestTTFTs, req.ID)
+		delete(sim.Metrics.RequestSchedulingDelays, req.ID)
+		delete(sim.Metrics.RequestQueueWaits, req.ID)
+		req.State = StateQueued
+		req.ProgressIndex = 0
+		req.NumNewTokens = 0
+		req.ITL = nil
+		req.TTFTSet = false
+		req.FirstTokenTime = 0
+	}
+	return failed
+}
+
+// RemainingDecodeTokens estimates the decode work this instance still owes:
+// declared output budget not yet generated (Request.RemainingOutputBudget),
+// summed over running and queued requests and requests injected with
+// InjectArrivalAt that have not reached the wait queue. It feeds routing, so
+// it reads budgets, not oracle output lengths (INV-9).
+func (sim *Simulator) RemainingDecodeTokens() int64 {
+	var total int64
+	for _, req := range sim.inTransit {
+		total += req.RemainingOutputBudget()
+	}
+	for _, req := range sim.WaitQ.Items() {
+		total += req.RemainingOutputBudget()
+	}
+	if sim.RunningBatch != nil {
+		for _, req := range sim.RunningBatch.Requests {
+			total += req.RemainingOutputBudget()
+		}
+	}
+	return total
+}
+
+// InTransitRequests returns the number of requests injected with
+// InjectArrivalAt that have not reached the wait queue.
+func (sim *Simulator) InTransitRequests() int {
+	return len(sim.inTransit)
+}
+
 // BatchSize returns the number of requests in the running batch, or 0 if nil.
 func (sim *Simulator) BatchSize() int {
 	if sim.RunningBatch == nil {
@@ -454,6 +1062,16 @@ func (sim *Simulator) BatchSize() int {
 	return len(sim.RunningBatch.Requests)
 }
 
+// RecentTTFTP99 returns the P99 TTFT (μs) over the last RecentLatencyWindowSize
+// completed requests, or 0 before any request has completed.
+func (sim *Simulator) RecentTTFTP99() float64 { return sim.recentTTFTs.P99() }
+
+// RecentPreemptionRate returns preemptions per second over the last
+// RecentPreemptionWindowUs of simulated time, or 0 when none occurred.
+func (sim *Simulator) RecentPreemptionRate() float64 {
+	return sim.recentPreemptions.RatePerSec(sim.Clock)
+}
+
 // CurrentClock returns the current simulation clock (in ticks).
 func (sim *Simulator) CurrentClock() int64 { return sim.Clock }
 
@@ -479,13 +1097,43 @@ func (sim *Simulator) PostDecodeFixedOverhead() int64 {
 	return sim.latencyModel.PostDecodeFixedOverhead()
 }
 
+// DetokenizationTime returns the CPU output-processing (detokenization) cost in
+// microseconds for a completed request, proportional to the output tokens it
+// actually emitted. Returns 0 when SimConfig.DetokenizationUsPerToken is unset.
+func (sim *Simulator) DetokenizationTime(req *Request) int64 {
+	if sim.detokenizationUsPerToken == 0 {
+		return 0
+	}
+	n := emittedOutputTokens(req)
+	if n <= 0 {
+		return 0
+	}
+	return int64(math.Round(sim.detokenizationUsPerToken * float64(n)))
+}
+
+// emittedOutputTokens returns the output tokens a finished request produced.
+// PI - InputLen counts decode-step increments (= OutputLen - 1 for normal completion).
+// Add 1 for the prefill-generated first token (#1097) when the count falls short
+// of OutputLen. PD 1-output decode sub-requests are the exception: their PI_final
+// lands at InputLen+1 (one step past the InputLen threshold), so the count already
+// equals OutputLen — the guard prevents double-counting in that case.
+func emittedOutputTokens(req *Request) int {
+	n := int(req.ProgressIndex) - int(req.InputLen())
+	if n < len(req.OutputTokens) {
+		n++ // prefill-generated first token (vLLM parity)
+	}
+	return n
+}
+
 // EnqueueRequest adds a newly arrived request to the waiting queue.
 //
 // Preprocessing: auto-fills MaxOutputLen when the client doesn't set a budget
 // (MaxOutputLen == 0) and maxModelLen > 0. Sets MaxOutputLen = maxModelLen - len(InputTokens),
 // mirroring vLLM's input_processor.py:554 (max_tokens = max_model_len - seq_len).
 // Workload generators normally set MaxOutputLen = len(OutputTokens) (tight budget);
-// this auto-fill is a safety net for requests that bypass generators.
+// this auto-fill is a safety net for requests that bypass generators. When
+// SimConfig.MaxOutputTokens is set, the budget is then clamped to it and longer
+// outputs are truncated (see applyOutputCap).
 //
 // Three guards then prevent unservable requests from entering the queue:
 //  0. MaxOutputLen validation (R3): drops requests with negative MaxOutputLen.
@@ -496,6 +1144,8 @@ func (sim *Simulator) PostDecodeFixedOverhead() int64 {
 //  2. KV capacity guard (defense-in-depth, always active): drops requests whose input
 //     tokens alone require more KV blocks than total cache capacity (R19: livelock protection).
 //
+// Each drop is counted under its reason in Metrics.DroppedUnservableByReason.
+//
 // All guards mirror real vLLM behavior where oversized requests are rejected
 // before entering the engine. The control plane never peeks at len(OutputTokens) —
 // respecting the oracle knowledge boundary (INV-9, #567).
@@ -513,18 +1163,15 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 		r.MaxOutputLen = int(sim.maxModelLen) - int(r.InputLen())
 	}
 
+	// Server-side output cap: applied after the auto-fill so the MaxModelLen
+	// guard below checks the enforced budget.
+	sim.applyOutputCap(r)
+
 	// Guard 0: Negative MaxOutputLen check (R3)
 	if r.MaxOutputLen < 0 {
 		logrus.Warnf("dropping request %s: MaxOutputLen %d is negative",
 			r.ID, r.MaxOutputLen)
-		sim.Metrics.DroppedUnservable++
-		delete(sim.Metrics.Requests, r.ID)
-		// Callback for dropped requests (R1: don't silently discard, BC-17)
-		if sim.OnRequestDone != nil {
-			for _, next := range sim.OnRequestDone(r, sim.Clock) {
-				sim.InjectArrival(next)
-			}
-		}
+		sim.dropUnservable(r, UnservableNegativeBudget)
 		return
 	}
 
@@ -533,13 +1180,7 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 		if r.InputLen() >= sim.maxModelLen {
 			logrus.Warnf("dropping request %s: input length %d >= MaxModelLen %d (no room for output)",
 				r.ID, r.InputLen(), sim.maxModelLen)
-			sim.Metrics.DroppedUnservable++
-			delete(sim.Metrics.Requests, r.ID)
-			if sim.OnRequestDone != nil {
-				for _, next := range sim.OnRequestDone(r, sim.Clock) {
-					sim.InjectArrival(next)
-				}
-			}
+			sim.dropUnservable(r, UnservableExceedsModelLen)
 			return
 		}
 		if r.MaxOutputLen > 0 {
@@ -547,24 +1188,27 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 			if totalSeqLen > sim.maxModelLen {
 				logrus.Warnf("dropping request %s: total sequence length %d (input=%d + budget=%d) exceeds MaxModelLen %d",
 					r.ID, totalSeqLen, r.InputLen(), r.MaxOutputLen, sim.maxModelLen)
-				sim.Metrics.DroppedUnservable++
-				delete(sim.Metrics.Requests, r.ID)
-				if sim.OnRequestDone != nil {
-					for _, next := range sim.OnRequestDone(r, sim.Clock) {
-						sim.InjectArrival(next)
-					}
-				}
+				sim.dropUnservable(r, UnservableExceedsModelLen)
 				return
 			}
 		}
 	}
 
 	// Guard 2: KV capacity check (defense-in-depth, always active)
-	blocksNeeded := (r.InputLen() + sim.KVCache.BlockSize() - 1) / sim.KVCache.BlockSize()
-	if blocksNeeded > sim.KVCache.TotalCapacity() {
+	kvc := kvStoreFor(sim.KVCache, r)
+	blocksNeeded := (r.InputLen() + kvc.BlockSize() - 1) / kvc.BlockSize()
+	if blocksNeeded > kvc.TotalCapacity() {
 		logrus.Warnf("dropping request %s: input requires %d KV blocks but cache has only %d total",
-			r.ID, blocksNeeded, sim.KVCache.TotalCapacity())
-		sim.Metrics.DroppedUnservable++
+			r.ID, blocksNeeded, kvc.TotalCapacity())
+		sim.dropUnservable(r, UnservableTooLarge)
+		return
+	}
+
+	// Guard 3: bounded wait queue. A rejected newcomer never reaches the engine,
+	// so — like the unservable drops above — its input tokens are not counted.
+	if sim.maxQueueDepth > 0 && sim.WaitQ.Len() >= sim.maxQueueDepth && !sim.makeRoomInWaitQ() {
+		logrus.Debugf("[tick %07d] rejecting request %s: wait queue full (%d)", sim.Clock, r.ID, sim.maxQueueDepth)
+		sim.Metrics.QueueOverflowRejected++
 		delete(sim.Metrics.Requests, r.ID)
 		if sim.OnRequestDone != nil {
 			for _, next := range sim.OnRequestDone(r, sim.Clock) {
@@ -574,8 +1218,12 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 		return
 	}
 
-	// Input tokens counted BEFORE past-due check (request was received)
-	sim.Metrics.TotalInputTokens += int(r.InputLen())
+	// Input tokens counted BEFORE past-due check (request was received), once
+	// per request even if it is resubmitted after an instance failure.
+	if !r.inputTokensCounted {
+		sim.Metrics.TotalInputTokens += int(r.InputLen())
+		r.inputTokensCounted = true
+	}
 
 	// Past-due guard (EC-2): check BEFORE enqueue to avoid enqueue-then-remove.
 	// Request is counted as timed_out, not dropped_unservable.
@@ -590,7 +1238,7 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 		return
 	}
 
-	// Pre-processor: convert cluster-convention SLO priority to vLLM instance convention.
+	// Pre-processor: convert cluster-convention priority to vLLM instance convention.
 	// Mirrors the llm-d → vLLM dispatch boundary in production (lower = more urgent).
 	// Overwrites any routing-hint Priority (which was always transient; see routing.go:59).
 	if sim.sloMap == nil {
@@ -598,7 +1246,7 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 		logrus.Warnf("Simulator.sloMap not initialized — using DefaultSLOPriorityMap; prefer NewSimulator()")
 		sim.sloMap = DefaultSLOPriorityMap()
 	}
-	r.Priority = float64(sim.sloMap.InvertForVLLM(r.SLOClass))
+	r.Priority = sim.instancePriority(r)
 
 	sim.WaitQ.Enqueue(r)
 
@@ -609,6 +1257,71 @@ func (sim *Simulator) EnqueueRequest(r *Request) {
 	}
 }
 
+// dropUnservable counts r as unservable under reason and releases it: r
+// leaves Metrics.Requests and OnRequestDone fires so follow-ups and cluster
+// trackers see the terminal state (R1: don't silently discard, BC-17).
+func (sim *Simulator) dropUnservable(r *Request, reason string) {
+	sim.Metrics.AddUnservable(reason, 1)
+	delete(sim.Metrics.Requests, r.ID)
+	if sim.OnRequestDone != nil {
+		for _, next := range sim.OnRequestDone(r, sim.Clock) {
+			sim.InjectArrival(next)
+		}
+	}
+}
+
+// makeRoomInWaitQ applies the drop-oldest overflow policy: it evicts the queued
+// request with the earliest ArrivalTime (first in queue order on ties) and returns
+// true. Returns false — leaving the queue untouched — under reject-new, or when no
+// request is eligible. PD decode sub-requests are never evicted: their KV transfer
+// and completion accounting are owned by the cluster.
+//
+// The victim releases everything a timed-out queued request would (adapter pin,
+// KV blocks held from a prior running phase, computed-token tracking), is marked
+// QueueDropped so its pending TimeoutEvent becomes a no-op, and is reported via
+// OnRequestDone with State still StateQueued (the drop signal sessions rely on).
+func (sim *Simulator) makeRoomInWaitQ() bool {
+	if sim.queueOverflowPolicy != QueueOverflowDropOldest {
+		return false
+	}
+	var victim *Request
+	for _, q := range sim.WaitQ.Items() {
+		if q.IsDecodeSubRequest {
+			continue
+		}
+		if victim == nil || q.ArrivalTime < victim.ArrivalTime {
+			victim = q
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	sim.WaitQ.Remove(victim)
+	victim.QueueDropped = true
+	sim.releaseAdapterPin(victim)
+	sim.KVCache.ReleaseKVBlocks(victim)
+	delete(sim.reqNumComputedTokens, victim.ID)
+	delete(sim.Metrics.Requests, victim.ID)
+	sim.Metrics.QueueOverflowDropped++
+	logrus.Debugf("[tick %07d] dropping oldest queued request %s: wait queue full (%d)", sim.Clock, victim.ID, sim.maxQueueDepth)
+	if sim.OnRequestDone != nil {
+		for _, next := range sim.OnRequestDone(victim, sim.Clock) {
+			sim.InjectArrival(next)
+		}
+	}
+	return true
+}
+
+// instancePriority returns r's vLLM-convention priority (lower = more urgent):
+// the inverted SLO-class priority by default, or the negated ExplicitPriority
+// under PriorityPolicy "explicit", so higher explicit priorities run first.
+func (sim *Simulator) instancePriority(r *Request) float64 {
+	if sim.explicitPriority {
+		return -float64(r.ExplicitPriority)
+	}
+	return float64(sim.sloMap.InvertForVLLM(r.SLOClass))
+}
+
 // EnqueueDecodeSubRequest enqueues a decode sub-request that already has KV blocks
 // pre-allocated (via PD disaggregation transfer). Bypasses the oversized-request guard
 // (blocks already allocated, guard would leak them) and does NOT increment TotalInputTokens
@@ -625,7 +1338,8 @@ func (sim *Simulator) EnqueueDecodeSubRequest(r *Request, clusterTime int64) {
 		logrus.Warnf("Simulator.sloMap not initialized — using DefaultSLOPriorityMap; prefer NewSimulator()")
 		sim.sloMap = DefaultSLOPriorityMap()
 	}
-	r.Priority = float64(sim.sloMap.InvertForVLLM(r.SLOClass))
+	r.Priority = sim.instancePriority(r)
+	sim.applyOutputCap(r)
 
 	sim.WaitQ.Enqueue(r)
 	// Do NOT add len(r.InputTokens) to TotalInputTokens — already counted by prefill sub-request.
@@ -649,6 +1363,29 @@ func (sim *Simulator) EnqueueDecodeSubRequest(r *Request, clusterTime int64) {
 	}
 }
 
+// applyOutputCap enforces SimConfig.MaxOutputTokens on r: the client
+// budget is clamped to the cap, and a sampled output longer than the cap is
+// truncated to it and marked OutputCapped so the request completes by length.
+// Metrics.Requests was built at arrival from the sampled length, so its decode
+// token count is refreshed. No-op when the cap is disabled (INV-6).
+func (sim *Simulator) applyOutputCap(r *Request) {
+	if sim.maxOutputTokens <= 0 {
+		return
+	}
+	if r.MaxOutputLen > sim.maxOutputTokens {
+		r.MaxOutputLen = sim.maxOutputTokens
+	}
+	if len(r.OutputTokens) <= sim.maxOutputTokens {
+		return
+	}
+	r.OutputTokens = r.OutputTokens[:sim.maxOutputTokens]
+	r.OutputCapped = true
+	if rm, ok := sim.Metrics.Requests[r.ID]; ok {
+		rm.NumDecodeTokens = sim.maxOutputTokens
+		sim.Metrics.Requests[r.ID] = rm
+	}
+}
+
 // recordQueueSnapshots records the wait queue and running batch sizes at this step.
 // Called after batch formation, before execution.
 func (sim *Simulator) recordQueueSnapshots() {
@@ -663,6 +1400,7 @@ func (sim *Simulator) recordKVUsageMetrics(stepDuration int64) {
 	if used > sim.Metrics.PeakKVBlocksUsed {
 		sim.Metrics.PeakKVBlocksUsed = used
 	}
+	sim.recordKVSample(used)
 	sim.Metrics.KVBlocksUsed += float64(used) * float64(stepDuration)
 }
 
@@ -670,8 +1408,8 @@ func (sim *Simulator) recordKVUsageMetrics(stepDuration int64) {
 // Called after state transitions (req.State, req.ITL, req.FinishedStepIdx)
 // and KV cleanup are done.
 //
-// NOTE: E2E (lat) includes PostDecodeFixedOverhead and OutputTokenProcessingTime, both of
-// which model non-blocking CPU overhead (concurrent with GPU execution). These inflate
+// NOTE: E2E (lat) includes PostDecodeFixedOverhead, OutputTokenProcessingTime, and
+// DetokenizationTime, all of which model non-blocking CPU overhead (concurrent with GPU execution). These inflate
 // E2E and RequestCompletionTimes beyond the RequestLeftEvent timestamp by the overhead
 // amount. This is architecturally intentional: real vLLM's post-processing (detokenization,
 // response serialization) is non-blocking but still contributes to client-perceived latency.
@@ -690,18 +1428,15 @@ func (sim *Simulator) recordRequestCompletion(req *Request) {
 	// would cause the request to vanish from conservation accounting entirely.
 	sim.Metrics.CompletedRequests++
 	sim.Metrics.TTFTSum += req.FirstTokenTime
+	if rm, ok := sim.Metrics.Requests[req.ID]; ok {
+		rm.CompletionReason = CompletionReasonOf(req)
+		sim.Metrics.Requests[req.ID] = rm
+	}
+	sim.recentTTFTs.add(req.FirstTokenTime)
 
 	// Count output tokens at completion time (not inline per step) to avoid
 	// double-counting under preemption (ProgressIndex reset to 0 on eviction).
-	// PI - InputLen counts decode-step increments (= OutputLen - 1 for normal completion).
-	// Add 1 for the prefill-generated first token (#1097) when decodeTokens falls short
-	// of OutputLen. PD 1-output decode sub-requests are the exception: their PI_final
-	// lands at InputLen+1 (one step past the InputLen threshold), so decodeTokens==OutputLen
-	// already — the guard prevents double-counting in that case.
-	decodeTokens := int(req.ProgressIndex) - int(req.InputLen())
-	if decodeTokens < len(req.OutputTokens) {
-		decodeTokens++ // prefill-generated first token (vLLM parity)
-	}
+	decodeTokens := emittedOutputTokens(req)
 	if decodeTokens > 0 {
 		sim.Metrics.TotalOutputTokens += decodeTokens
 	}
@@ -717,7 +1452,12 @@ func (sim *Simulator) recordRequestCompletion(req *Request) {
 	if len(req.OutputTokens) > 0 {
 		postDecodeOverhead = sim.latencyModel.PostDecodeFixedOverhead()
 	}
-	lat := req.FirstTokenTime + itlSum + postDecodeOverhead
+	// Detokenization: CPU output processing proportional to the tokens actually
+	// emitted. Runs off the GPU, so it lengthens E2E without touching step time.
+	lat := req.FirstTokenTime + itlSum + postDecodeOverhead + sim.DetokenizationTime(req)
+	sim.Metrics.TimeBudget.OutputProcessingTicks += postDecodeOverhead + sim.DetokenizationTime(req)
+	delete(sim.reqComputeTicks, req.ID)
+	sim.forgetPreemptionAttempt(req.ID)
 	sim.Metrics.RequestE2Es[req.ID] = float64(lat)
 	logrus.Debugf("Finished req: ID: %s at time: %d", req.ID, lat+req.ArrivalTime)
 	if len(req.OutputTokens) > 0 {
@@ -754,7 +1494,9 @@ func (sim *Simulator) Step(now int64) {
 		sim.stepEvent = nil
 		return
 	}
-	sim.scheduleBatch(now)
+	if !sim.scheduleBatch(now) {
+		return
+	}
 	currStepAdvance := sim.executeBatchStep(now)
 	// Mirror in-use blocks to CPU tier (no-op for single-tier KVCacheState).
 	// Runs after execution (new full blocks exist) and before completions
@@ -765,8 +1507,10 @@ func (sim *Simulator) Step(now int64) {
 }
 
 // scheduleBatch handles Phase 1: priority assignment, queue reordering, batch formation,
-// and event scheduling for preemptions and newly scheduled requests.
-func (sim *Simulator) scheduleBatch(now int64) {
+// and event scheduling for preemptions and newly scheduled requests. Returns
+// false when batch formation held the idle instance for MinBatchFill, in which
+// case no step is launched.
+func (sim *Simulator) scheduleBatch(now int64) bool {
 	sim.stepCount += 1
 
 	// Synchronize KV cache clock for thrashing detection (no-op for single-tier KVCacheState)
@@ -790,31 +1534,51 @@ func (sim *Simulator) scheduleBatch(now int64) {
 	// Delegate batch composition to the pluggable BatchFormation strategy.
 	// Event scheduling and metrics recording happen after FormBatch returns (kernel concerns).
 	batchCtx := BatchContext{
-		RunningBatch:          sim.RunningBatch,
-		WaitQ:                 sim.WaitQ,
-		KVCache:               sim.KVCache,
-		MaxScheduledTokens:    sim.maxScheduledTokens,
-		MaxRunningReqs:        sim.maxRunningReqs,
-		PrefillTokenThreshold: sim.longPrefillTokenThreshold,
-		MaxModelLen:           sim.maxModelLen,
-		Now:                   now,
-		StepCount:             sim.stepCount,
-		ComputedTokens:        sim.reqNumComputedTokens,
+		RunningBatch:            sim.RunningBatch,
+		WaitQ:                   sim.WaitQ,
+		KVCache:                 sim.KVCache,
+		MaxScheduledTokens:      sim.maxScheduledTokens,
+		MaxRunningReqs:          sim.maxRunningReqs,
+		PrefillTokenThreshold:   sim.longPrefillTokenThreshold,
+		MaxModelLen:             sim.maxModelLen,
+		KVPressureThreshold:     sim.kvPressureThreshold,
+		KVFairShare:             sim.kvFairShare,
+		KVFairShareMaxBlocks:    sim.kvFairShareMaxBlocks,
+		TokensPerDecodeStep:     sim.tokensPerDecodeStep,
+		MinBatchFill:            sim.minBatchFill,
+		BatchFillMaxWait:        sim.batchFillMaxWait,
+		DecodeQuantumSteps:      sim.decodeQuantumSteps,
+		AdaptivePrefillChunkMin: sim.adaptivePrefillChunkMin,
+		PrefillFirst:            sim.prefillFirst,
+		SwapPreemption:          sim.swapPreemption,
+		SwapSpaceBlocks:         sim.swapSpaceBlocks,
+		Now:                     now,
+		StepCount:               sim.stepCount,
+		ComputedTokens:          sim.reqNumComputedTokens,
+		RemotePrefixCached:      sim.remotePrefixCached,
 	}
 	if sim.residentAdapters != nil {
 		batchCtx.AdapterResident = sim.residentAdapters.IsResident
 	}
 	batchResult := sim.batchFormation.FormBatch(batchCtx)
+	if batchResult.HoldUntil > 0 {
+		sim.stepCount-- // nothing launched
+		sim.holdForBatchFill(batchResult.HoldUntil)
+		return false
+	}
 
 	// Apply result: update running batch
 	sim.RunningBatch = batchResult.RunningBatch
+	sim.forwardPasses = batchResult.ForwardPasses
+	sim.recordStepBound(batchCtx, batchResult)
 
-	// Record preemption metrics and emit debug log for each preempted request
-	for _, p := range batchResult.Preempted {
-		logrus.Debugf("<< Preemption: %s at %d ticks", p.Request.ID, now)
-		sim.Metrics.PreemptionCount++
+	if n := batchResult.RemotePrefixFetchedBlocks; n > 0 {
+		sim.Metrics.RemotePrefixFetchedBlocks += n
+		sim.pendingRemoteFetchLatency += int64(math.Round(float64(n) * sim.remoteFetchUsPerBlock))
 	}
 
+	sim.recordPreemptions(batchResult.Preempted, now)
+
 	// Schedule events for newly scheduled requests and record scheduling metrics
 	for _, s := range batchResult.NewlyScheduled {
 		sim.Schedule(&ScheduledEvent{
@@ -822,11 +1586,55 @@ func (sim *Simulator) scheduleBatch(now int64) {
 			Request: s.Request,
 		})
 		sim.Metrics.RequestSchedulingDelays[s.Request.ID] = now - s.Request.ArrivalTime
+		if _, ok := sim.Metrics.RequestQueueWaits[s.Request.ID]; !ok {
+			sim.Metrics.RequestQueueWaits[s.Request.ID] = now - s.Request.ArrivalTime
+		}
 		sim.recordAdapterResidency(s.Request)
+		sim.recordAttemptStart(s.Request, now)
+		if s.SwappedInBlocks > 0 {
+			sim.recordSwap(s.SwappedInBlocks)
+		}
 	}
 
 	// Record queue depth observations after batch formation
 	sim.recordQueueSnapshots()
+	return true
+}
+
+// recordPreemptions records the metrics of requests preempted at tick now,
+// by FormBatch or preemptForFinalToken, and emits a debug log for each.
+func (sim *Simulator) recordPreemptions(preempted []PreemptedRequest, now int64) {
+	for _, p := range preempted {
+		logrus.Debugf("<< Preemption: %s at %d ticks (%d tokens of progress lost)", p.Request.ID, now, p.ProgressLost)
+		sim.Metrics.PreemptionCount++
+		sim.recentPreemptions.add(now)
+		sim.Metrics.WastedPrefillTokens += p.ProgressLost
+		if p.HOLBlocking {
+			sim.Metrics.PriorityHOLBlockingEvents++
+		}
+		if p.SwappedBlocks > 0 {
+			sim.recordSwap(p.SwappedBlocks)
+			sim.recordRequestSwapOut(p.Request, now)
+			continue
+		}
+		sim.recordPreemptionTimeBudget(p.Request)
+		sim.recordRequestPreemption(p.Request)
+	}
+}
+
+// holdForBatchFill idles the instance until tick until under the
+// min-batch-fill wait. sim.stepEvent is cleared so the next arrival re-forms
+// the batch (launching early once MinBatchFill requests are queued), and a
+// BatchFillWakeEvent re-forms it at the deadline. A wake scheduled for an
+// earlier hold with a different deadline is superseded and fires as a no-op.
+func (sim *Simulator) holdForBatchFill(until int64) {
+	sim.stepEvent = nil
+	if sim.batchFillWake != nil && sim.batchFillWake.time == until {
+		return
+	}
+	wake := &BatchFillWakeEvent{time: until}
+	sim.Schedule(wake)
+	sim.batchFillWake = wake
 }
 
 // recordAdapterResidency updates the per-instance resident-adapter set when a
@@ -849,6 +1657,18 @@ func (sim *Simulator) recordAdapterResidency(req *Request) {
 	}
 }
 
+// recordKVResidency records how long a completing request held KV blocks:
+// from its first allocation, made when it was first scheduled (the tick
+// RequestQueueWaits is measured to), to releaseTick, the end of the step it
+// completes in.
+func (sim *Simulator) recordKVResidency(req *Request, releaseTick int64) {
+	wait, ok := sim.Metrics.RequestQueueWaits[req.ID]
+	if !ok {
+		return
+	}
+	sim.Metrics.RequestKVResidencies[req.ID] = releaseTick - (req.ArrivalTime + wait)
+}
+
 // ReleaseAdapterPin releases req's adapter pin if it holds one. Exported for the
 // cluster layer's gateway-eviction / drain paths (InstanceSimulator.EvictRequest),
 // which remove a running request from this instance outside the normal
@@ -934,6 +1754,41 @@ func (sim *Simulator) completeAdapterLoad(now int64, adapter string) {
 	sim.ScheduleStepIfIdle(now)
 }
 
+// warmupMultiplier returns the cold-start step-time multiplier for the current step.
+// Step k (1-based, as counted by scheduleBatch) of warmupSteps gets
+// 1 + (warmupFactor-1)·(warmupSteps-k+1)/warmupSteps, so the penalty starts at
+// warmupFactor and decays linearly; every step after the window returns 1.0.
+func (sim *Simulator) warmupMultiplier() float64 {
+	if sim.warmupSteps <= 0 || sim.stepCount > sim.warmupSteps {
+		return 1.0
+	}
+	remaining := float64(sim.warmupSteps-sim.stepCount+1) / float64(sim.warmupSteps)
+	return 1.0 + (sim.warmupFactor-1.0)*remaining
+}
+
+// applyPowerModel returns the step's compute time after power-cap throttling
+// and accounts its energy (see SimConfig.PowerPeakWatts). computeUs is returned
+// unchanged when the power model is off.
+func (sim *Simulator) applyPowerModel(scheduled []*Request, computeUs int64) int64 {
+	if sim.powerPeakWatts <= 0 {
+		return computeUs
+	}
+	var tokens int64
+	for _, req := range scheduled {
+		tokens += int64(req.NumNewTokens)
+	}
+	load := min(float64(tokens)/float64(sim.maxScheduledTokens), 1)
+	draw := sim.powerIdleWatts + (sim.powerPeakWatts-sim.powerIdleWatts)*load
+	if sim.powerCapWatts > 0 && draw > sim.powerCapWatts {
+		slowdown := math.Cbrt((draw - sim.powerIdleWatts) / (sim.powerCapWatts - sim.powerIdleWatts))
+		computeUs = int64(math.Round(float64(computeUs) * slowdown))
+		draw = sim.powerCapWatts
+		sim.Metrics.PowerThrottledSteps++
+	}
+	sim.Metrics.EnergyJoules += draw * float64(computeUs) / 1e6
+	return computeUs
+}
+
 // executeBatchStep handles Phase 2: model execution (prefill + decode) for all requests
 // in the running batch. Returns the step time advance in ticks.
 func (sim *Simulator) executeBatchStep(now int64) int64 {
@@ -952,15 +1807,53 @@ func (sim *Simulator) executeBatchStep(now int64) int64 {
 			scheduled = append(scheduled, req)
 		}
 	}
-	currStepAdvance := sim.latencyModel.StepTime(scheduled)
+	var currStepAdvance int64
+	if sim.forwardPasses != nil {
+		for _, pass := range sim.forwardPasses {
+			currStepAdvance += sim.latencyModel.StepTime(pass)
+		}
+		sim.forwardPasses = nil
+	} else {
+		currStepAdvance = sim.latencyModel.StepTime(scheduled)
+	}
+
+	// Cold-start penalty: inflate compute time on a fresh instance's first steps
+	// (CUDA graph capture, allocator warmup). Transfer latency is not inflated.
+	if factor := sim.warmupMultiplier(); factor > 1 {
+		currStepAdvance = int64(math.Round(float64(currStepAdvance) * factor))
+	}
+
+	// Power cap: throttle compute when the step's draw would exceed it. Transfer
+	// and fetch latencies added below are not compute and are not throttled.
+	currStepAdvance = sim.applyPowerModel(scheduled, currStepAdvance)
 
 	// Add transfer latency from CPU→GPU reloads (0 for single-tier)
 	currStepAdvance += sim.KVCache.ConsumePendingTransferLatency()
 
+	// Add fetch latency for prefix blocks pulled from another instance's cache (0 unless shared prefix cache)
+	currStepAdvance += sim.pendingRemoteFetchLatency
+	sim.pendingRemoteFetchLatency = 0
+
+	// Add KV swap-out and swap-in transfers (0 unless PreemptionModeSwap)
+	currStepAdvance += sim.pendingSwapLatency
+	sim.pendingSwapLatency = 0
+
+	// Add the one-time KV prefix seeding cost (0 after the first step or without seeds)
+	currStepAdvance += sim.pendingPrefixSeedLatency
+	sim.pendingPrefixSeedLatency = 0
+
+	// Add the switch cost between affinity-key groups sharing the step
+	if groups := affinityGroupCount(scheduled); groups > 1 {
+		currStepAdvance += int64(groups-1) * sim.affinitySwitchCost
+		sim.Metrics.BatchAffinitySwitchSteps++
+	}
+
 	// INV-3 defense-in-depth: guarantee clock advancement regardless of backend.
 	// All LatencyModel implementations must return >= 1 per interface contract;
 	// this floor catches violations that would cause infinite livelock.
 	currStepAdvance = max(1, currStepAdvance)
+	sim.recordStepTimeBudget(scheduled, currStepAdvance)
+	sim.recordBatchEfficiency(scheduled, currStepAdvance)
 
 	// Subprocess: Model Execution - this could be prefill or decode depending on the request.
 	// similar to vLLM's execute_model()
@@ -983,8 +1876,19 @@ func (sim *Simulator) executeBatchStep(now int64) int64 {
 			// from proactive cap) would get a phantom ProgressIndex increment.
 			// Also prevents phantom tokens from token budget exhaustion (pre-existing edge case).
 			if req.NumNewTokens > 0 {
-				req.ProgressIndex++
-				req.ITL = append(req.ITL, currStepAdvance+sim.latencyModel.OutputTokenProcessingTime())
+				// A multi-token step emits NumNewTokens tokens at once. ITL stays
+				// per generated token: each gets an equal share of the step (the
+				// remainder going to the earliest), so the entries sum to it.
+				n := int64(req.NumNewTokens)
+				req.ProgressIndex += n
+				for i := int64(0); i < n; i++ {
+					share := currStepAdvance / n
+					if i < currStepAdvance%n {
+						share++
+					}
+					req.ITL = append(req.ITL, share+sim.latencyModel.OutputTokenProcessingTime())
+				}
+				sim.Metrics.TimeBudget.OutputProcessingTicks += n * sim.latencyModel.OutputTokenProcessingTime()
 			}
 		}
 		// !req.TTFTSet guard: fires once per prefill completion (including re-prefill after
@@ -997,6 +1901,7 @@ func (sim *Simulator) executeBatchStep(now int64) int64 {
 			req.TTFTSet = true
 			req.FirstTokenTime = now + currStepAdvance + sim.latencyModel.OutputTokenProcessingTime() - req.ArrivalTime
 			sim.Metrics.RequestTTFTs[req.ID] = float64(req.FirstTokenTime)
+			sim.Metrics.TimeBudget.OutputProcessingTicks += sim.latencyModel.OutputTokenProcessingTime()
 		}
 	}
 
@@ -1018,7 +1923,17 @@ func (sim *Simulator) executeBatchStep(now int64) int64 {
 // in the same step.
 func (sim *Simulator) processCompletions(now, currStepAdvance int64) []*Request {
 	remaining := []*Request{}
+	preempted := false
 	for _, req := range sim.RunningBatch.Requests {
+		// Evicted this pass by preemptForFinalToken; already back in WaitQ.
+		if req.State == StateQueued {
+			preempted = true
+			continue
+		}
+		if sim.completionGate != nil && sim.completesThisStep(req) && !sim.completionGate(req) {
+			remaining = append(remaining, req)
+			continue
+		}
 		// in cases where there are 0 output tokens, set it to 1 manually to avoid errors
 		if req.ProgressIndex >= req.InputLen()+max(util.Len64(req.OutputTokens), 1)-1 {
 			// State transitions
@@ -1041,7 +1956,12 @@ func (sim *Simulator) processCompletions(now, currStepAdvance int64) []*Request
 			if len(req.OutputTokens) > 0 && req.ProgressIndex < req.InputLen()+util.Len64(req.OutputTokens) {
 				ok := sim.KVCache.AllocateKVBlocks(req, req.ProgressIndex, req.ProgressIndex+1, []int64{})
 				if !ok {
-					logrus.Errorf("[tick %07d] KV allocation failed for completing request %s (request will still complete) — this indicates a cache accounting bug", now, req.ID)
+					// Decode growth outran KV capacity: evict other running
+					// requests (back-pressure) rather than over-committing.
+					ok = sim.preemptForFinalToken(req, now)
+				}
+				if !ok {
+					logrus.Errorf("[tick %07d] KV allocation failed for completing request %s with no request left to preempt (request will still complete)", now, req.ID)
 					sim.Metrics.KVAllocationFailures++
 				}
 			}
@@ -1050,6 +1970,7 @@ func (sim *Simulator) processCompletions(now, currStepAdvance int64) []*Request
 			// pattern, matching vLLM kv_cache_manager.py:334-336), so RequestMap is
 			// preserved and Release frees all blocks from prior successful allocations.
 			sim.KVCache.ReleaseKVBlocks(req)
+			sim.recordKVResidency(req, now+currStepAdvance)
 			req.FinishedStepIdx = sim.stepCount
 			sim.Schedule(&RequestLeftEvent{
 				time:    now + currStepAdvance,
@@ -1093,6 +2014,7 @@ func (sim *Simulator) processCompletions(now, currStepAdvance int64) []*Request
 			}
 			req.State = StateCompleted
 			sim.KVCache.ReleaseKVBlocks(req)
+			sim.recordKVResidency(req, now+currStepAdvance)
 			req.FinishedStepIdx = sim.stepCount
 			sim.Schedule(&RequestLeftEvent{
 				time:    now + currStepAdvance,
@@ -1110,9 +2032,78 @@ func (sim *Simulator) processCompletions(now, currStepAdvance int64) []*Request
 			remaining = append(remaining, req)
 		}
 	}
+	if preempted {
+		// A victim visited before its eviction was already kept in remaining.
+		kept := remaining[:0]
+		for _, req := range remaining {
+			if req.State != StateQueued {
+				kept = append(kept, req)
+			}
+		}
+		remaining = kept
+	}
 	return remaining
 }
 
+// preemptForFinalToken makes KV room for the final-token allocation of the
+// completing request req by evicting other running requests, least urgent
+// first (highest Request.Priority, latest ArrivalTime on ties), until the
+// allocation succeeds. Requests also completing this step are never evicted:
+// they release their blocks anyway. Victims go through preemptRunningRequest
+// and recordPreemptions exactly as FormBatch preemptions do (swapped out under
+// swap preemption), and stay in the running batch slice with StateQueued
+// until processCompletions filters them out.
+// Returns false when no evictable request is left.
+func (sim *Simulator) preemptForFinalToken(req *Request, now int64) bool {
+	// preemptRunningRequest removes each victim from the batch it is given;
+	// hand it a copy so the caller's loop over RunningBatch is undisturbed.
+	result := &BatchResult{RunningBatch: &Batch{Requests: slices.Clone(sim.RunningBatch.Requests)}}
+	ctx := BatchContext{
+		WaitQ:           sim.WaitQ,
+		KVCache:         sim.KVCache,
+		SwapPreemption:  sim.swapPreemption,
+		SwapSpaceBlocks: sim.swapSpaceBlocks,
+		Now:             now,
+		ComputedTokens:  sim.reqNumComputedTokens,
+	}
+	var tokenBudget int64 // the step has executed; there is no budget to restore
+	for {
+		victimIdx := -1
+		for i, r := range result.RunningBatch.Requests {
+			if r == req || r.State != StateRunning || sim.completesThisStep(r) {
+				continue
+			}
+			if victimIdx < 0 {
+				victimIdx = i
+				continue
+			}
+			victim := result.RunningBatch.Requests[victimIdx]
+			if r.Priority > victim.Priority || (r.Priority == victim.Priority && r.ArrivalTime > victim.ArrivalTime) {
+				victimIdx = i
+			}
+		}
+		if victimIdx < 0 {
+			return false
+		}
+		logrus.Debugf("[tick %07d] preemption: making room for the final token of %s", now, req.ID)
+		preemptRunningRequest(victimIdx, result, ctx, &tokenBudget)
+		sim.recordPreemptions(result.Preempted, now)
+		result.Preempted = nil
+		sim.Metrics.DecodePreemptionCount++
+
+		if sim.KVCache.AllocateKVBlocks(req, req.ProgressIndex, req.ProgressIndex+1, []int64{}) {
+			return true
+		}
+	}
+}
+
+// completesThisStep reports whether processCompletions retires req in the
+// current step, either normally or length-capped.
+func (sim *Simulator) completesThisStep(req *Request) bool {
+	return req.ProgressIndex >= req.InputLen()+max(util.Len64(req.OutputTokens), 1)-1 ||
+		(sim.maxModelLen > 0 && req.ProgressIndex >= sim.maxModelLen-1)
+}
+
 // scheduleNextStep handles Phase 4: schedules the next step event based on
 // remaining requests, or starts a new batch if only WaitQ has pending work
 // (work-conserving property, INV-8).
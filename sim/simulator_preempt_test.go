@@ -1,7 +1,14 @@
 package sim
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"testing"
+
+	"github.com/sirupsen/logrus"
 )
 
 // TestPreempt_EmptyBatch_ReturnsFalse verifies BC-6 (#293):
@@ -13,7 +20,7 @@ func TestPreempt_EmptyBatch_ReturnsFalse(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(2, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(config.TotalKVBlocks, config.BlockSizeTokens)
@@ -68,7 +75,7 @@ func TestPreempt_InsufficientBlocks_EvictsAllThenReturnsFalse(t *testing.T) {
 		KVCacheConfig:       NewKVCacheConfig(2, 16, 0, 0, 0, 0),
 		BatchConfig:         NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:       NewLatencyCoeffs([]float64{100, 1, 1}, []float64{100, 1, 100}),
-		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
+		ModelHardwareConfig: NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
 	}
 	bf := NewBatchFormation("")
 	kvCache := MustNewKVCacheState(config.TotalKVBlocks, config.BlockSizeTokens)
@@ -143,8 +150,8 @@ func TestNewSimulator_CustomSLOPriorityMap_AffectsPreemption(t *testing.T) {
 		KVCacheConfig:        NewKVCacheConfig(10, 16, 0, 0.0, 0.0, 0.0),
 		BatchConfig:          NewBatchConfig(10, 10000, 0),
 		LatencyCoeffs:        NewLatencyCoeffs([]float64{0, 0, 0}, []float64{100, 1, 0}),
-		ModelHardwareConfig:  NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0),
-		PolicyConfig:         NewPolicyConfig("fcfs", "priority"),
+		ModelHardwareConfig:  NewModelHardwareConfig(rooflineModelConfig(), rooflineHWCalib(), "", "", 1, 1, false, "", "roofline", 0, ""),
+		PolicyConfig:         NewPolicyConfig("fcfs", "priority", ""),
 		SLOPriorityOverrides: map[string]int{"background": 10}, // promote background above batch(-1)
 	}
 	s := mustNewSimulator(t, cfg)
@@ -216,3 +223,134 @@ func TestNewSimulator_CustomSLOPriorityMap_AffectsPreemption(t *testing.T) {
 		t.Error("bg-req should still be running (background=10 with override, more urgent than batch=-1)")
 	}
 }
+
+// boundsCheckingKVStore wraps a real KVStore and records the used-block range
+// observed after every allocation and release.
+type boundsCheckingKVStore struct {
+	KVStore
+	minUsed, maxUsed int64
+}
+
+func (b *boundsCheckingKVStore) observe() {
+	used := b.KVStore.UsedBlocks()
+	b.minUsed = min(b.minUsed, used)
+	b.maxUsed = max(b.maxUsed, used)
+}
+
+func (b *boundsCheckingKVStore) AllocateKVBlocks(req *Request, startIndex, endIndex int64, cachedBlocks []int64) bool {
+	ok := b.KVStore.AllocateKVBlocks(req, startIndex, endIndex, cachedBlocks)
+	b.observe()
+	return ok
+}
+
+func (b *boundsCheckingKVStore) ReleaseKVBlocks(req *Request) {
+	b.KVStore.ReleaseKVBlocks(req)
+	b.observe()
+}
+
+// TestStep_DecodeGrowthExceedsKV_PreemptsAndCompletesAll verifies decode-time
+// back-pressure: when a batch of long-output requests outgrows a tiny KV cache
+// mid-decode, the final-token allocation preempts other running requests
+// instead of failing, block accounting stays within [0, capacity], and every
+// request eventually completes under an infinite horizon.
+func TestStep_DecodeGrowthExceedsKV_PreemptsAndCompletesAll(t *testing.T) {
+	// GIVEN a 12-block cache (192 tokens) and 8 requests that each grow to
+	// 5 blocks (16 input + 49 output tokens; the final token opens the fifth block)
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(12, 16, 0, 0, 0, 0)
+	sim := mustNewSimulator(t, cfg)
+	kv := &boundsCheckingKVStore{KVStore: sim.KVCache}
+	sim.KVCache = kv
+
+	const n = 8
+	for i := 0; i < n; i++ {
+		sim.InjectArrival(&Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i),
+			InputTokens:  make([]TokenID, 16),
+			OutputTokens: make([]TokenID, 49),
+			State:        StateQueued,
+		})
+	}
+
+	// WHEN the simulation runs to completion
+	sim.Run()
+
+	// THEN decode-phase preemptions occurred and no final-token allocation failed
+	if sim.Metrics.DecodePreemptionCount == 0 {
+		t.Error("DecodePreemptionCount = 0, want > 0 (decode growth should preempt)")
+	}
+	if sim.Metrics.PreemptionCount < sim.Metrics.DecodePreemptionCount {
+		t.Errorf("PreemptionCount %d < DecodePreemptionCount %d, want decode preemptions counted in the total",
+			sim.Metrics.PreemptionCount, sim.Metrics.DecodePreemptionCount)
+	}
+	if sim.Metrics.KVAllocationFailures != 0 {
+		t.Errorf("KVAllocationFailures = %d, want 0", sim.Metrics.KVAllocationFailures)
+	}
+
+	// AND used blocks never left [0, capacity]
+	if kv.minUsed < 0 || kv.maxUsed > kv.TotalCapacity() {
+		t.Errorf("used blocks ranged over [%d, %d], want within [0, %d]", kv.minUsed, kv.maxUsed, kv.TotalCapacity())
+	}
+
+	// AND every request completed with its blocks released (INV-1, INV-4)
+	if sim.Metrics.CompletedRequests != n {
+		t.Errorf("CompletedRequests = %d, want %d", sim.Metrics.CompletedRequests, n)
+	}
+	if used := kv.UsedBlocks(); used != 0 {
+		t.Errorf("UsedBlocks after run = %d, want 0", used)
+	}
+}
+
+// TestStep_ConstrainedKV_WastedPrefillTokensMatchesPreemptionTrace verifies
+// that WastedPrefillTokens is the sum of the progress each preemption event
+// discarded, as reported by the preemption warnings.
+func TestStep_ConstrainedKV_WastedPrefillTokensMatchesPreemptionTrace(t *testing.T) {
+	// GIVEN the decode-growth workload on a 12-block cache
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(12, 16, 0, 0, 0, 0)
+	sim := mustNewSimulator(t, cfg)
+	for i := 0; i < 8; i++ {
+		sim.InjectArrival(&Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i),
+			InputTokens:  make([]TokenID, 16),
+			OutputTokens: make([]TokenID, 49),
+			State:        StateQueued,
+		})
+	}
+
+	// AND preemption warnings are captured
+	var buf bytes.Buffer
+	logrus.SetOutput(&buf)
+	logrus.SetLevel(logrus.WarnLevel)
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetLevel(logrus.InfoLevel)
+	}()
+
+	// WHEN the simulation runs to completion
+	sim.Run()
+
+	// THEN progress was wasted
+	if sim.Metrics.WastedPrefillTokens <= 0 {
+		t.Fatalf("WastedPrefillTokens = %d, want > 0 (preemptions discard progress)", sim.Metrics.WastedPrefillTokens)
+	}
+
+	// AND it equals the progress lost summed over every preemption event
+	lost := regexp.MustCompile(`preemption: evicting .* \((\d+) tokens of progress lost\)`).FindAllStringSubmatch(buf.String(), -1)
+	if int64(len(lost)) != sim.Metrics.PreemptionCount {
+		t.Fatalf("traced %d preemptions, want PreemptionCount %d", len(lost), sim.Metrics.PreemptionCount)
+	}
+	var want int64
+	for _, m := range lost {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want += n
+	}
+	if sim.Metrics.WastedPrefillTokens != want {
+		t.Errorf("WastedPrefillTokens = %d, want %d (sum of traced progress lost)", sim.Metrics.WastedPrefillTokens, want)
+	}
+}
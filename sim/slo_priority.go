@@ -2,6 +2,13 @@ package sim
 
 import "fmt"
 
+// Priority policies (PolicyConfig.PriorityPolicy) select where a request's
+// instance-level Priority comes from at enqueue.
+const (
+	PriorityPolicySLOClass = "slo-class" // SLOPriorityMap.InvertForVLLM(SLOClass); "" is treated the same
+	PriorityPolicyExplicit = "explicit"  // Request.ExplicitPriority, higher = more urgent
+)
+
 // SLOPriorityMap maps SLOClass strings to integer priorities.
 // Higher = more important. Negative = sheddable (matches GAIE's IsSheddable contract).
 // Unexported map field prevents external mutation (R8).
@@ -0,0 +1,31 @@
+package sim
+
+// Step ordering names accepted by SimConfig.StepOrdering. They choose which
+// work the per-step token budget is charged to first when it is tight.
+const (
+	// StepOrderingDecodeFirst advances every running request (prefill chunks
+	// and decodes) before admitting new prefills from the wait queue: vLLM's
+	// order, favoring inter-token latency of in-flight requests. The default
+	// ("" maps to it).
+	StepOrderingDecodeFirst = "decode-first"
+
+	// StepOrderingPrefillFirst charges running prefill chunks and new
+	// admissions first, and gives running decodes only the budget left over,
+	// favoring time to first token of new requests.
+	StepOrderingPrefillFirst = "prefill-first"
+)
+
+// IsValidStepOrdering reports whether name selects a known step ordering.
+// The empty string selects the default (StepOrderingDecodeFirst).
+func IsValidStepOrdering(name string) bool {
+	switch name {
+	case "", StepOrderingDecodeFirst, StepOrderingPrefillFirst:
+		return true
+	}
+	return false
+}
+
+// ValidStepOrderingNames returns the accepted step ordering names.
+func ValidStepOrderingNames() []string {
+	return []string{StepOrderingDecodeFirst, StepOrderingPrefillFirst}
+}
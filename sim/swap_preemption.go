@@ -0,0 +1,122 @@
+package sim
+
+import (
+	"math"
+
+	"github.com/inference-sim/inference-sim/sim/internal/util"
+)
+
+// Preemption modes for SimConfig.PreemptionMode: what happens to a victim's
+// KV cache when it is evicted from the running batch.
+const (
+	// PreemptionModeRecompute discards the victim's KV and progress; it is
+	// re-prefilled from scratch when rescheduled (vLLM's default).
+	PreemptionModeRecompute = "recompute"
+	// PreemptionModeSwap copies the victim's KV out to host memory and back in
+	// when it is rescheduled, keeping its progress: each direction costs
+	// KVTransferBaseLatency + blocks / KVTransferBandwidth ticks of step time.
+	// Host memory holds SimConfig.SwapSpaceBlocks blocks (0 = unbounded).
+	PreemptionModeSwap = "swap"
+)
+
+var validPreemptionModes = map[string]bool{"": true, PreemptionModeRecompute: true, PreemptionModeSwap: true}
+
+// IsValidPreemptionMode returns true if name is a recognized preemption mode
+// ("" = recompute).
+func IsValidPreemptionMode(name string) bool { return validPreemptionModes[name] }
+
+// canSwap reports whether a preemption victim is swapped rather than
+// recomputed: it must have progress to keep, and parallel samples (whose
+// decode blocks are tracked per sample) are always recomputed.
+func canSwap(req *Request) bool {
+	return req.ProgressIndex > 0 && req.ParallelSamples <= 1
+}
+
+// swappedBlocks is the number of KV blocks holding req's progress, the
+// blocks a swap copies in each direction.
+func swappedBlocks(req *Request, blockSize int64) int64 {
+	return (req.ProgressIndex + blockSize - 1) / blockSize
+}
+
+// swapSpaceFits reports whether host swap space can take req's blocks on top
+// of those of the swapped-out requests waiting in ctx.WaitQ. Always true when
+// ctx.SwapSpaceBlocks is 0 (unbounded).
+func swapSpaceFits(req *Request, ctx BatchContext) bool {
+	if ctx.SwapSpaceBlocks == 0 {
+		return true
+	}
+	blockSize := ctx.KVCache.BlockSize()
+	used := swappedBlocks(req, blockSize)
+	for _, w := range ctx.WaitQ.Items() {
+		if w.SwappedOut {
+			used += swappedBlocks(w, blockSize)
+		}
+	}
+	return used <= ctx.SwapSpaceBlocks
+}
+
+// swapOutRequest evicts req to host memory: its GPU blocks are released but
+// its progress, ITL and first-token state are kept, and Request.SwappedOut
+// marks it for swapInKVBlocks on re-admission. The caller requeues it.
+func swapOutRequest(req *Request, kvc KVStore) {
+	req.State = StateQueued
+	req.NumNewTokens = 0
+	req.SwappedOut = true
+	kvc.ReleaseKVBlocks(req)
+}
+
+// swapInKVBlocks re-allocates GPU blocks for the swapped-out tokens
+// [0, ProgressIndex) of req: the prompt in one allocation, claiming its blocks
+// still in the prefix cache, then each generated token as decode does.
+// Returns the number of prompt blocks claimed from the cache, which need no
+// copy, or false, holding nothing, when the blocks do not fit.
+func swapInKVBlocks(kvc KVStore, req *Request) (int64, bool) {
+	progress := req.ProgressIndex
+	defer func() { req.ProgressIndex = progress }()
+	req.ProgressIndex = 0
+	promptEnd := min(progress, req.InputLen())
+	var cachedBlocks []int64
+	if !req.NoCache {
+		cachedBlocks = kvc.GetCachedBlocks(req.FullInputTokens())
+		// Leave at least one prompt token to allocate: the allocation is what
+		// claims the cached blocks.
+		cachedBlocks = cachedBlocks[:min(util.Len64(cachedBlocks), (promptEnd-1)/kvc.BlockSize())]
+	}
+	if !kvc.AllocateKVBlocks(req, util.Len64(cachedBlocks)*kvc.BlockSize(), promptEnd, cachedBlocks) {
+		return 0, false
+	}
+	for i := req.InputLen(); i < progress; i++ {
+		req.ProgressIndex = i
+		if !kvc.AllocateKVBlocks(req, i, i+1, nil) {
+			kvc.ReleaseKVBlocks(req)
+			return 0, false
+		}
+	}
+	return util.Len64(cachedBlocks), true
+}
+
+// swapTransferTicks is the step time a swap of blocks KV blocks in one
+// direction adds.
+func (sim *Simulator) swapTransferTicks(blocks int64) int64 {
+	return sim.swapBaseLatency + int64(math.Ceil(float64(blocks)/sim.swapBandwidth))
+}
+
+// recordSwap charges a swap of blocks KV blocks to the step being formed.
+func (sim *Simulator) recordSwap(blocks int64) {
+	ticks := sim.swapTransferTicks(blocks)
+	sim.pendingSwapLatency += ticks
+	sim.Metrics.SwapTransferTicks += ticks
+}
+
+// recordRequestSwapOut counts a swap-out preemption against req. Its compute
+// so far is kept, so unlike recordRequestPreemption only the time from now
+// until it is swapped back in is lost to the preemption.
+func (sim *Simulator) recordRequestSwapOut(req *Request, now int64) {
+	sim.Metrics.SwapOutCount++
+	if rm, ok := sim.Metrics.Requests[req.ID]; ok {
+		rm.PreemptionCount++
+		sim.Metrics.Requests[req.ID] = rm
+	}
+	delete(sim.reqAttemptStart, req.ID)
+	sim.reqPreemptedAttempt[req.ID] = now
+}
@@ -0,0 +1,135 @@
+package sim
+
+import "testing"
+
+// runSwapComparison runs eight requests (64-token prompts, 100 output tokens)
+// arriving together on a 30-block KV cache under the given preemption mode,
+// swapping at 0.01 blocks/tick (100 ticks per block) into swapSpaceBlocks of
+// host memory (0 = unbounded), and returns the finalized metrics.
+func runSwapComparison(t *testing.T, mode string, swapSpaceBlocks int64) *Metrics {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(30, 16, 0, 0, 0.01, 0)
+	cfg.PreemptionMode = mode
+	cfg.SwapSpaceBlocks = swapSpaceBlocks
+	s := newSimulatorWithModel(t, cfg, &contextStepModel{perPass: 500})
+	runToCompletion(t, s, distinctPrompts(uniformRequests(8, 64, 100, 0)))
+	return s.Metrics
+}
+
+// TestPreemptionModeSwap_KeepsProgressAndChargesTransfers verifies that on a
+// KV cache too small for the batch, swap preemption discards no progress
+// (WastedPrefillTokens 0, unlike recompute) but charges swap transfers to
+// step time, and every request still emits all its tokens.
+func TestPreemptionModeSwap_KeepsProgressAndChargesTransfers(t *testing.T) {
+	recompute := runSwapComparison(t, PreemptionModeRecompute, 0)
+	swap := runSwapComparison(t, PreemptionModeSwap, 0)
+
+	// Recompute: preemptions discard progress, no transfers.
+	if recompute.PreemptionCount == 0 || recompute.WastedPrefillTokens == 0 {
+		t.Fatalf("recompute: PreemptionCount = %d, WastedPrefillTokens = %d; the test needs wasteful preemption",
+			recompute.PreemptionCount, recompute.WastedPrefillTokens)
+	}
+	if recompute.SwapOutCount != 0 || recompute.SwapTransferTicks != 0 {
+		t.Errorf("recompute: SwapOutCount = %d, SwapTransferTicks = %d, want 0", recompute.SwapOutCount, recompute.SwapTransferTicks)
+	}
+
+	// Swap: every preemption is a swap-out, nothing is recomputed, and both
+	// directions pay transfer time.
+	if swap.PreemptionCount == 0 {
+		t.Fatal("swap: PreemptionCount = 0; the test needs preemption")
+	}
+	if swap.SwapOutCount != swap.PreemptionCount {
+		t.Errorf("swap: SwapOutCount = %d, want every preemption (%d)", swap.SwapOutCount, swap.PreemptionCount)
+	}
+	if swap.WastedPrefillTokens != 0 {
+		t.Errorf("swap: WastedPrefillTokens = %d, want 0", swap.WastedPrefillTokens)
+	}
+	// A victim holds at least its 4 prompt blocks, 400 ticks each way.
+	if minTicks := swap.SwapOutCount * 2 * 400; swap.SwapTransferTicks < minTicks {
+		t.Errorf("swap: SwapTransferTicks = %d, want >= %d for %d swap-outs and swap-ins", swap.SwapTransferTicks, minTicks, swap.SwapOutCount)
+	}
+	t.Logf("recompute: %d preemptions, %d wasted tokens, ended at %d; swap: %d swap-outs, %d transfer ticks, ended at %d",
+		recompute.PreemptionCount, recompute.WastedPrefillTokens, recompute.SimEndedTime,
+		swap.SwapOutCount, swap.SwapTransferTicks, swap.SimEndedTime)
+
+	// Swapped requests resume mid-stream with their output intact.
+	if swap.TotalOutputTokens != recompute.TotalOutputTokens {
+		t.Errorf("swap: TotalOutputTokens = %d, want %d", swap.TotalOutputTokens, recompute.TotalOutputTokens)
+	}
+}
+
+// TestPreemptionModeSwap_SwapSpaceFull_Recomputes verifies that with host
+// swap space for only one victim (each holds at least 5 blocks) further
+// victims are recomputed instead, and every request still completes.
+func TestPreemptionModeSwap_SwapSpaceFull_Recomputes(t *testing.T) {
+	m := runSwapComparison(t, PreemptionModeSwap, 8)
+
+	if m.SwapOutCount == 0 {
+		t.Fatal("SwapOutCount = 0, want swap-outs while host space is free")
+	}
+	if m.SwapOutCount >= m.PreemptionCount || m.WastedPrefillTokens == 0 {
+		t.Errorf("SwapOutCount = %d of %d preemptions, WastedPrefillTokens = %d; want recomputed victims once swap space is full",
+			m.SwapOutCount, m.PreemptionCount, m.WastedPrefillTokens)
+	}
+}
+
+// TestSwapIn_PromptBlocksInPrefixCache_NotTransferred verifies a swap-in
+// claims the prompt blocks still in the prefix cache and charges only the
+// rest as a transfer.
+func TestSwapIn_PromptBlocksInPrefixCache_NotTransferred(t *testing.T) {
+	// GIVEN a request with a 60-token prompt and 10 decoded tokens (5 blocks,
+	// 3 of them full prompt blocks) swapped out of an otherwise idle cache
+	kvc := MustNewKVStoreFromConfig(NewKVCacheConfig(30, 16, 0, 0, 0, 0))
+	req := distinctPrompts(uniformRequests(1, 60, 20, 0))[0]
+	if !kvc.AllocateKVBlocks(req, 0, 60, nil) {
+		t.Fatal("prompt allocation failed")
+	}
+	for i := int64(60); i < 70; i++ {
+		req.ProgressIndex = i
+		if !kvc.AllocateKVBlocks(req, i, i+1, nil) {
+			t.Fatalf("decode allocation %d failed", i)
+		}
+	}
+	req.ProgressIndex = 70
+	swapOutRequest(req, kvc)
+	wq := &WaitQueue{}
+	wq.Enqueue(req)
+
+	// WHEN it is swapped back in
+	result := BatchResult{RunningBatch: &Batch{}}
+	ctx := BatchContext{WaitQ: wq, KVCache: kvc, ComputedTokens: map[string]int64{}}
+	budget := int64(2048)
+	if !swapIn(req, &result, ctx, &budget) {
+		t.Fatal("swapIn failed on an idle cache")
+	}
+
+	// THEN the 3 cached prompt blocks are reused and only 2 are copied in
+	if got := result.NewlyScheduled[0].SwappedInBlocks; got != 2 {
+		t.Errorf("SwappedInBlocks = %d, want 2 (5 blocks less 3 cached)", got)
+	}
+	if got := kvc.UsedBlocks(); got != 5 {
+		t.Errorf("UsedBlocks = %d, want 5", got)
+	}
+	if req.ProgressIndex != 70 || req.SwappedOut {
+		t.Errorf("ProgressIndex = %d, SwappedOut = %v; want 70, false", req.ProgressIndex, req.SwappedOut)
+	}
+}
+
+func TestNewSimulator_SwapPreemptionWithoutBandwidth_Errors(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.PreemptionMode = PreemptionModeSwap // KVTransferBandwidth is 0
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &contextStepModel{perPass: 500}); err == nil {
+		t.Error("NewSimulator accepted swap preemption with KVTransferBandwidth 0")
+	}
+	cfg.KVCacheConfig = NewKVCacheConfig(10000, 16, 0, 0, 0.01, 0)
+	cfg.SwapSpaceBlocks = -1
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &contextStepModel{perPass: 500}); err == nil {
+		t.Error("NewSimulator accepted SwapSpaceBlocks -1")
+	}
+	cfg.SwapSpaceBlocks = 0
+	cfg.PreemptionMode = "spill"
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &contextStepModel{perPass: 500}); err == nil {
+		t.Error(`NewSimulator accepted PreemptionMode "spill"`)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"testing"
 )
 
 // testGenerateRequests replicates the exact algorithm from the old
@@ -72,3 +73,60 @@ func injectRequests(sim *Simulator, requests []*Request) {
 		sim.InjectArrival(req)
 	}
 }
+
+// newFixedStepSimulator builds a simulator from cfg on a fixed 1000-tick step
+// model, so every step advances the clock by exactly 1ms.
+func newFixedStepSimulator(t *testing.T, cfg SimConfig) *Simulator {
+	t.Helper()
+	return newSimulatorWithModel(t, cfg, &fixedStepModel{stepTime: 1000})
+}
+
+// newSimulatorWithModel builds a simulator from cfg on the given latency model.
+func newSimulatorWithModel(t *testing.T, cfg SimConfig, model LatencyModel) *Simulator {
+	t.Helper()
+	s, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), model)
+	if err != nil {
+		t.Fatalf("NewSimulator: %v", err)
+	}
+	return s
+}
+
+// uniformRequests returns n queued requests request_0..request_{n-1}, each
+// with an inputLen-token prompt and outputLen output tokens (MaxOutputLen
+// outputLen), the i-th arriving at i × gap.
+func uniformRequests(n, inputLen, outputLen int, gap int64) []*Request {
+	requests := make([]*Request, n)
+	for i := range requests {
+		requests[i] = &Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * gap,
+			InputTokens:  make([]TokenID, inputLen),
+			OutputTokens: make([]TokenID, outputLen),
+			MaxOutputLen: outputLen,
+			State:        StateQueued,
+		}
+	}
+	return requests
+}
+
+// runToCompletion injects requests into s, runs it, and fails the test unless
+// every request completed.
+func runToCompletion(t *testing.T, s *Simulator, requests []*Request) {
+	t.Helper()
+	injectRequests(s, requests)
+	s.Run()
+	if s.Metrics.CompletedRequests != len(requests) {
+		t.Fatalf("CompletedRequests = %d, want %d", s.Metrics.CompletedRequests, len(requests))
+	}
+}
+
+// distinctPrompts gives each request prompt tokens no other request shares,
+// so no prefix is served from cache.
+func distinctPrompts(requests []*Request) []*Request {
+	for i, req := range requests {
+		for j := range req.InputTokens {
+			req.InputTokens[j] = TokenID(i*1000 + j)
+		}
+	}
+	return requests
+}
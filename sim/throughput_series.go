@@ -0,0 +1,38 @@
+package sim
+
+// recordThroughputSamplesBefore appends Metrics.CompletedRequests to
+// Metrics.CompletedSeries for every pending sample tick earlier than
+// nextEventTime: the count cannot change until that event fires. Ticks past
+// the horizon are left to Finalize. No-op when sampling is disabled.
+func (sim *Simulator) recordThroughputSamplesBefore(nextEventTime int64) {
+	interval := sim.Metrics.ThroughputSampleIntervalUs
+	if interval <= 0 {
+		return
+	}
+	for next := interval * int64(len(sim.Metrics.CompletedSeries)+1); next < nextEventTime && next <= sim.Horizon; next += interval {
+		sim.Metrics.CompletedSeries = append(sim.Metrics.CompletedSeries, sim.Metrics.CompletedRequests)
+	}
+}
+
+// FinishCompletedSeries closes a cumulative completed-request series sampled
+// every intervalUs ticks (sample k is taken at tick k*intervalUs) for a run
+// that ended at endTime with finalCompleted requests done. The series is
+// extended or truncated to ceil(endTime/intervalUs) samples, the last of
+// which is finalCompleted, so the series always ends at the run's
+// CompletedRequests. Returns series unchanged when intervalUs <= 0.
+func FinishCompletedSeries(series []int, intervalUs, endTime int64, finalCompleted int) []int {
+	if intervalUs <= 0 {
+		return series
+	}
+	n := int((endTime + intervalUs - 1) / intervalUs)
+	if len(series) > n {
+		series = series[:n]
+	}
+	for len(series) < n {
+		series = append(series, finalCompleted)
+	}
+	if n > 0 {
+		series[n-1] = finalCompleted
+	}
+	return series
+}
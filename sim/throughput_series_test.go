@@ -0,0 +1,102 @@
+package sim
+
+import (
+	"fmt"
+	"testing"
+)
+
+// assertCompletedSeries checks the series invariants: one sample per interval
+// up to SimEndedTime, non-decreasing, ending at CompletedRequests.
+func assertCompletedSeries(t *testing.T, name string, m *Metrics) {
+	t.Helper()
+	interval := m.ThroughputSampleIntervalUs
+	wantLen := int((m.SimEndedTime + interval - 1) / interval)
+	if len(m.CompletedSeries) != wantLen {
+		t.Fatalf("%s: len(CompletedSeries) = %d, want ceil(%d/%d) = %d", name, len(m.CompletedSeries), m.SimEndedTime, interval, wantLen)
+	}
+	for k := 1; k < len(m.CompletedSeries); k++ {
+		if m.CompletedSeries[k] < m.CompletedSeries[k-1] {
+			t.Errorf("%s: CompletedSeries[%d] = %d < CompletedSeries[%d] = %d, want non-decreasing",
+				name, k, m.CompletedSeries[k], k-1, m.CompletedSeries[k-1])
+		}
+	}
+	if last := m.CompletedSeries[len(m.CompletedSeries)-1]; last != m.CompletedRequests {
+		t.Errorf("%s: final CompletedSeries = %d, want CompletedRequests %d", name, last, m.CompletedRequests)
+	}
+}
+
+func TestSimulator_ThroughputSampleInterval_CumulativeCompletedSeries(t *testing.T) {
+	// GIVEN a steady workload of 40 requests arriving every 50ms, sampled every 100ms
+	cfg := newTestSimConfig()
+	cfg.ThroughputSampleIntervalUs = 100_000
+	s := mustNewSimulator(t, cfg)
+	for i := 0; i < 40; i++ {
+		s.InjectArrival(&Request{
+			ID:           fmt.Sprintf("request_%d", i),
+			ArrivalTime:  int64(i) * 50_000,
+			InputTokens:  make([]TokenID, 100),
+			OutputTokens: make([]TokenID, 50),
+			State:        StateQueued,
+		})
+	}
+
+	// WHEN the simulation runs to completion
+	s.Run()
+
+	// THEN the series is cumulative and ends at CompletedRequests
+	if s.Metrics.CompletedRequests != 40 {
+		t.Fatalf("CompletedRequests = %d, want 40", s.Metrics.CompletedRequests)
+	}
+	assertCompletedSeries(t, "instance", s.Metrics)
+
+	// AND it ramps: completions are spread over the run, not all in the last sample
+	if first := s.Metrics.CompletedSeries[0]; first >= s.Metrics.CompletedRequests {
+		t.Errorf("CompletedSeries[0] = %d, want < %d for a steady arrival stream", first, s.Metrics.CompletedRequests)
+	}
+
+	// AND the series is written to the output
+	out := s.Metrics.BuildOutput("instance_0", nil)
+	if out.CompletedSeries == nil || out.CompletedSeries.IntervalUs != 100_000 ||
+		len(out.CompletedSeries.Completed) != len(s.Metrics.CompletedSeries) {
+		t.Errorf("BuildOutput CompletedSeries = %+v, want interval 100000 and %d samples", out.CompletedSeries, len(s.Metrics.CompletedSeries))
+	}
+}
+
+func TestSimulator_ThroughputSampleIntervalZero_NoSeries(t *testing.T) {
+	// GIVEN sampling disabled (the default)
+	s := mustNewSimulator(t, newTestSimConfig())
+	s.InjectArrival(&Request{ID: "request_0", InputTokens: make([]TokenID, 10), OutputTokens: make([]TokenID, 5), State: StateQueued})
+
+	// WHEN the simulation runs
+	s.Run()
+
+	// THEN no series is recorded or emitted (INV-6)
+	if len(s.Metrics.CompletedSeries) != 0 {
+		t.Errorf("CompletedSeries = %v, want empty", s.Metrics.CompletedSeries)
+	}
+	if out := s.Metrics.BuildOutput("instance_0", nil); out.CompletedSeries != nil {
+		t.Errorf("BuildOutput CompletedSeries = %+v, want nil", out.CompletedSeries)
+	}
+}
+
+func TestFinishCompletedSeries_ExtendsAndTruncates(t *testing.T) {
+	tests := []struct {
+		name    string
+		series  []int
+		endTime int64
+		want    []int
+	}{
+		{"extends to the sample covering endTime", []int{1}, 250, []int{1, 4, 4}},
+		{"truncates samples past endTime", []int{1, 2, 3, 3, 3}, 200, []int{1, 4}},
+		{"aligned endTime ends on that sample", []int{1, 2}, 200, []int{1, 4}},
+		{"zero endTime yields no samples", nil, 0, []int{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FinishCompletedSeries(tc.series, 100, tc.endTime, 4)
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("FinishCompletedSeries = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sim
+
+import "math"
+
+// TimeBudgetBreakdown attributes an instance's simulated busy time to the
+// phase that spent it. Every step's time is split between SchedulingTicks and
+// ComputeTicks, with the compute share of work a preemption later discards
+// moved to PreemptionTicks; the per-request CPU overheads the latency model
+// adds outside steps are QueueingTicks and OutputProcessingTicks. Total() is
+// the busy time: the sum of all step times plus those overheads.
+type TimeBudgetBreakdown struct {
+	// QueueingTicks sums LatencyModel.QueueingTime over every arrival.
+	QueueingTicks int64 `json:"queueing_ticks"`
+	// SchedulingTicks is the scheduling overhead within step times
+	// (SimConfig.SchedulingOverheadUsPerSeq and SchedulingContentionUsPerSeqSq).
+	SchedulingTicks int64 `json:"scheduling_ticks"`
+	// ComputeTicks is the rest of every step: forward passes plus KV
+	// transfer, remote-fetch and prefix-seeding latency.
+	ComputeTicks int64 `json:"compute_ticks"`
+	// OutputProcessingTicks sums OutputTokenProcessingTime per emitted token
+	// and PostDecodeFixedOverhead plus detokenization per completed request.
+	OutputProcessingTicks int64 `json:"output_processing_ticks"`
+	// PreemptionTicks is step compute spent on progress preemptions
+	// discarded, split across each step's requests by scheduled tokens.
+	PreemptionTicks int64 `json:"preemption_ticks"`
+}
+
+// Total returns the busy time the breakdown accounts for.
+func (b TimeBudgetBreakdown) Total() int64 {
+	return b.QueueingTicks + b.SchedulingTicks + b.ComputeTicks + b.OutputProcessingTicks + b.PreemptionTicks
+}
+
+// Merge adds o into b (cluster aggregation).
+func (b *TimeBudgetBreakdown) Merge(o TimeBudgetBreakdown) {
+	b.QueueingTicks += o.QueueingTicks
+	b.SchedulingTicks += o.SchedulingTicks
+	b.ComputeTicks += o.ComputeTicks
+	b.OutputProcessingTicks += o.OutputProcessingTicks
+	b.PreemptionTicks += o.PreemptionTicks
+}
+
+// SchedulingOverheadUs returns the host-side scheduling cost in microseconds of
+// a step over n sequences: usPerSeq·n + usPerSeqSq·n². The latency backends add
+// it to step times and recordStepTimeBudget attributes it, so both agree.
+func SchedulingOverheadUs(n int, usPerSeq, usPerSeqSq float64) float64 {
+	b := float64(n)
+	return usPerSeq*b + usPerSeqSq*b*b
+}
+
+// recordStepTimeBudget attributes one step of stepTicks over the scheduled
+// requests. The scheduling share is what the latency model's scheduling
+// overhead added (never more than the step); the compute remainder is
+// credited to each request by its scheduled tokens so a later preemption can
+// move its share to PreemptionTicks.
+func (sim *Simulator) recordStepTimeBudget(scheduled []*Request, stepTicks int64) {
+	var scheduling int64
+	if sim.schedOverheadUsPerSeq > 0 || sim.schedContentionUsPerSeqSq > 0 {
+		overhead := SchedulingOverheadUs(len(scheduled), sim.schedOverheadUsPerSeq, sim.schedContentionUsPerSeqSq)
+		scheduling = min(int64(math.Round(overhead)), stepTicks)
+	}
+	compute := stepTicks - scheduling
+	sim.Metrics.TimeBudget.SchedulingTicks += scheduling
+	sim.Metrics.TimeBudget.ComputeTicks += compute
+
+	var tokens int64
+	for _, req := range scheduled {
+		tokens += int64(req.NumNewTokens)
+	}
+	if tokens == 0 {
+		return
+	}
+	for _, req := range scheduled {
+		sim.reqComputeTicks[req.ID] += compute * int64(req.NumNewTokens) / tokens
+	}
+}
+
+// recordPreemptionTimeBudget moves the compute credited to a preempted
+// request since it was last admitted from ComputeTicks to PreemptionTicks.
+func (sim *Simulator) recordPreemptionTimeBudget(req *Request) {
+	lost := sim.reqComputeTicks[req.ID]
+	delete(sim.reqComputeTicks, req.ID)
+	sim.Metrics.TimeBudget.ComputeTicks -= lost
+	sim.Metrics.TimeBudget.PreemptionTicks += lost
+}
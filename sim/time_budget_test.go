@@ -0,0 +1,107 @@
+package sim
+
+import "testing"
+
+// overheadStepModel is a latency model with fixed per-request overheads. Its
+// step time is perPass plus one tick per scheduled token plus schedPerSeq per
+// sequence, mirroring latency.WithSchedulingOverhead. It records the sum of
+// the step times it returned.
+type overheadStepModel struct {
+	perPass, schedPerSeq              int64
+	queueing, outputToken, postDecode int64
+	stepSum                           int64
+}
+
+func (m *overheadStepModel) StepTime(batch []*Request) int64 {
+	t := m.perPass + m.schedPerSeq*int64(len(batch))
+	for _, req := range batch {
+		t += int64(req.NumNewTokens)
+	}
+	m.stepSum += t
+	return t
+}
+func (m *overheadStepModel) QueueingTime(req *Request) int64  { return m.queueing }
+func (m *overheadStepModel) OutputTokenProcessingTime() int64 { return m.outputToken }
+func (m *overheadStepModel) PostDecodeFixedOverhead() int64   { return m.postDecode }
+
+// runTimeBudget runs n requests (200 input, 50 output tokens) through model
+// and returns the simulator.
+func runTimeBudget(t *testing.T, cfg SimConfig, model *overheadStepModel, n int) *Simulator {
+	t.Helper()
+	s := newSimulatorWithModel(t, cfg, model)
+	runToCompletion(t, s, uniformRequests(n, 200, 50, 1000))
+	return s
+}
+
+// TestTimeBudget_ComponentsSumToBusyTime verifies each component against the
+// overheads the latency model charged, and that together they account for
+// every step tick plus the per-request overheads.
+func TestTimeBudget_ComponentsSumToBusyTime(t *testing.T) {
+	// GIVEN a model charging queueing, scheduling, output and post-decode overheads
+	const n = 10
+	cfg := newTestSimConfig()
+	cfg.SchedulingOverheadUsPerSeq = 20
+	model := &overheadStepModel{perPass: 300, schedPerSeq: 20, queueing: 7, outputToken: 3, postDecode: 11}
+
+	// WHEN requests run to completion
+	s := runTimeBudget(t, cfg, model, n)
+	b := s.Metrics.TimeBudget
+
+	// THEN each component matches what was charged
+	if want := int64(n * 7); b.QueueingTicks != want {
+		t.Errorf("QueueingTicks = %d, want %d", b.QueueingTicks, want)
+	}
+	if want := int64(n * (50*3 + 11)); b.OutputProcessingTicks != want {
+		t.Errorf("OutputProcessingTicks = %d, want %d (one OutputTokenProcessingTime per token, one PostDecodeFixedOverhead per request)", b.OutputProcessingTicks, want)
+	}
+	if b.SchedulingTicks <= 0 || b.SchedulingTicks%20 != 0 {
+		t.Errorf("SchedulingTicks = %d, want a positive multiple of 20", b.SchedulingTicks)
+	}
+	if b.PreemptionTicks != 0 {
+		t.Errorf("PreemptionTicks = %d, want 0 without preemption", b.PreemptionTicks)
+	}
+	// AND the step share sums to the step times, the total to busy time
+	if got := b.SchedulingTicks + b.ComputeTicks + b.PreemptionTicks; got != model.stepSum {
+		t.Errorf("scheduling + compute + preemption = %d, want step time sum %d", got, model.stepSum)
+	}
+	if want := model.stepSum + b.QueueingTicks + b.OutputProcessingTicks; b.Total() != want {
+		t.Errorf("Total() = %d, want %d", b.Total(), want)
+	}
+}
+
+// TestTimeBudget_ZeroOverheads_OnlyCompute verifies that with zero overhead
+// coefficients every busy tick is attributed to compute.
+func TestTimeBudget_ZeroOverheads_OnlyCompute(t *testing.T) {
+	model := &overheadStepModel{perPass: 300}
+	s := runTimeBudget(t, newTestSimConfig(), model, 10)
+	want := TimeBudgetBreakdown{ComputeTicks: model.stepSum}
+	if got := s.Metrics.TimeBudget; got != want || got.ComputeTicks == 0 {
+		t.Errorf("TimeBudget = %+v, want %+v", got, want)
+	}
+}
+
+// TestTimeBudget_Preemption_MovesLostComputeAndKeepsTotal verifies that
+// compute spent on preempted progress is reported as preemption time without
+// changing the step-time total.
+func TestTimeBudget_Preemption_MovesLostComputeAndKeepsTotal(t *testing.T) {
+	// GIVEN KV capacity too small for all requests' decode growth at once
+	cfg := newTestSimConfig()
+	cfg.KVCacheConfig = NewKVCacheConfig(40, 16, 0, 0, 0, 0)
+	model := &overheadStepModel{perPass: 300}
+
+	// WHEN requests run to completion
+	s := runTimeBudget(t, cfg, model, 10)
+
+	// THEN preemptions occurred, their lost compute is attributed to them
+	if s.Metrics.PreemptionCount == 0 {
+		t.Fatal("PreemptionCount = 0; test does not exercise preemption")
+	}
+	b := s.Metrics.TimeBudget
+	if b.PreemptionTicks <= 0 {
+		t.Errorf("PreemptionTicks = %d, want > 0", b.PreemptionTicks)
+	}
+	// AND the step share still sums to the step times
+	if got := b.ComputeTicks + b.PreemptionTicks; got != model.stepSum {
+		t.Errorf("compute + preemption = %d, want step time sum %d", got, model.stepSum)
+	}
+}
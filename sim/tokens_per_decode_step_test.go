@@ -0,0 +1,66 @@
+package sim
+
+import "testing"
+
+// runDecodeSteps runs one request with 10 input and outputLen output tokens at
+// the given TokensPerDecodeStep and returns it with the simulator.
+func runDecodeSteps(t *testing.T, tokensPerStep, outputLen int) (*Request, *Simulator) {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.TokensPerDecodeStep = tokensPerStep
+	s := newFixedStepSimulator(t, cfg)
+	req := uniformRequests(1, 10, outputLen, 0)[0]
+	runToCompletion(t, s, []*Request{req})
+	return req, s
+}
+
+// TestTokensPerDecodeStep_FewerStepsPerTokenITL verifies that multi-token
+// decode finishes the same output in fewer steps while still reporting one ITL
+// entry per generated token, the entries summing to the decode time.
+func TestTokensPerDecodeStep_FewerStepsPerTokenITL(t *testing.T) {
+	// GIVEN a request with 13 output tokens: the first comes from prefill, the
+	// other 12 need 12 single-token decode steps or ceil(12/5) = 3 at 5 per step
+	const outputLen = 13
+	single, singleSim := runDecodeSteps(t, 0, outputLen)
+
+	// WHEN it decodes 5 tokens per step
+	multi, multiSim := runDecodeSteps(t, 5, outputLen)
+
+	// THEN decode takes 3 steps instead of 12 (plus one prefill step each)
+	if singleSim.stepCount != 13 || multiSim.stepCount != 4 {
+		t.Errorf("steps: single=%d multi=%d, want 13 and 4", singleSim.stepCount, multiSim.stepCount)
+	}
+	// AND ITL has one entry per generated token after the first in both modes
+	if len(single.ITL) != outputLen-1 || len(multi.ITL) != outputLen-1 {
+		t.Fatalf("ITL entries: single=%d multi=%d, want %d each", len(single.ITL), len(multi.ITL), outputLen-1)
+	}
+	// AND the per-token entries split each 1000µs step: 5+5+2 tokens → 200×10 + 500×2
+	var sum int64
+	for i, itl := range multi.ITL {
+		want := int64(200)
+		if i >= 10 {
+			want = 500
+		}
+		if itl != want {
+			t.Errorf("multi ITL[%d] = %d, want %d", i, itl, want)
+		}
+		sum += itl
+	}
+	if sum != 3000 {
+		t.Errorf("multi ITL sum = %d, want 3000 (3 decode steps)", sum)
+	}
+	if got := multiSim.Metrics.Requests[multi.ID].NumDecodeTokens; got != outputLen {
+		t.Errorf("NumDecodeTokens = %d, want %d", got, outputLen)
+	}
+	if multiSim.Metrics.TotalOutputTokens != outputLen {
+		t.Errorf("TotalOutputTokens = %d, want %d", multiSim.Metrics.TotalOutputTokens, outputLen)
+	}
+}
+
+func TestNewSimulator_TokensPerDecodeStepValidation(t *testing.T) {
+	cfg := newTestSimConfig()
+	cfg.TokensPerDecodeStep = -1
+	if _, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000}); err == nil {
+		t.Error("TokensPerDecodeStep=-1: expected error")
+	}
+}
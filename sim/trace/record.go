@@ -27,9 +27,12 @@ type RoutingRecord struct {
 	Clock          int64
 	ChosenInstance string
 	Reason         string
-	Scores         map[string]float64 // from RoutingDecision.Scores (may be nil)
-	Candidates     []CandidateScore   // top-k candidates sorted by score desc (nil if k=0)
-	Regret         float64            // max(alternative scores) - score(chosen); 0 if chosen is best
+	// Scores maps every candidate instance ID → composite routing score, so a
+	// decision can be explained against all alternatives, not just the top-k.
+	// Recorded regardless of CounterfactualK. Nil when the policy does not score.
+	Scores     map[string]float64 // from RoutingDecision.Scores (may be nil)
+	Candidates []CandidateScore   // top-k candidates sorted by score desc (nil if k=0)
+	Regret     float64            // max(alternative scores) - score(chosen); 0 if chosen is best
 }
 
 // DisaggregationRecord captures a PD disaggregation decision.
@@ -88,3 +88,15 @@ func (st *SimulationTrace) RecordKVTransfer(record KVTransferRecord) {
 func (st *SimulationTrace) RecordEncodeRouting(record EncodeRoutingRecord) {
 	st.EncodeRoutings = append(st.EncodeRoutings, record)
 }
+
+// RoutingLog returns the routing-decision log of the trace: each routed
+// request's ID mapped to its chosen instance, for replay routing
+// (sim.NewReplayRouting). A request routed more than once keeps its last
+// decision.
+func (st *SimulationTrace) RoutingLog() map[string]string {
+	log := make(map[string]string, len(st.Routings))
+	for _, r := range st.Routings {
+		log[r.RequestID] = r.ChosenInstance
+	}
+	return log
+}
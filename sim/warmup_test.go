@@ -0,0 +1,92 @@
+package sim
+
+import (
+	"math"
+	"testing"
+)
+
+// runWarmupRequest runs one 10-token-prompt, 8-token-output request on a
+// fresh instance with the given warmup and returns it with the simulator.
+func runWarmupRequest(t *testing.T, warmupSteps int, warmupFactor float64) (*Simulator, *Request) {
+	t.Helper()
+	cfg := newTestSimConfig()
+	cfg.WarmupSteps = warmupSteps
+	cfg.WarmupFactor = warmupFactor
+	s := newFixedStepSimulator(t, cfg)
+	req := uniformRequests(1, 10, 8, 0)[0]
+	runToCompletion(t, s, []*Request{req})
+	return s, req
+}
+
+// TestWarmup_EarlyStepsElevatedThenDecayToSteadyState verifies that the first
+// WarmupSteps steps on a fresh instance are inflated by a linearly decaying factor
+// and that every later step matches the no-warmup baseline.
+func TestWarmup_EarlyStepsElevatedThenDecayToSteadyState(t *testing.T) {
+	// GIVEN a fresh instance with a 4-step warmup starting at 3x
+	warm, warmReq := runWarmupRequest(t, 4, 3.0)
+
+	// AND a baseline instance without warmup
+	base, baseReq := runWarmupRequest(t, 0, 0)
+
+	// THEN the prefill step (step 1) carries the full 3x penalty
+	warmTTFT := warm.Metrics.RequestTTFTs[warmReq.ID]
+	baseTTFT := base.Metrics.RequestTTFTs[baseReq.ID]
+	if warmTTFT <= baseTTFT {
+		t.Errorf("TTFT with warmup = %v, want > baseline %v", warmTTFT, baseTTFT)
+	}
+
+	// AND decode steps 2..4 decay (2.5x, 2.0x, 1.5x) before reaching steady state
+	want := []int64{2500, 2000, 1500}
+	if len(warmReq.ITL) < len(want)+1 {
+		t.Fatalf("len(ITL) = %d, want at least %d", len(warmReq.ITL), len(want)+1)
+	}
+	for i, w := range want {
+		if warmReq.ITL[i] != w {
+			t.Errorf("ITL[%d] = %d, want %d", i, warmReq.ITL[i], w)
+		}
+	}
+	for i := len(want); i < len(warmReq.ITL); i++ {
+		if warmReq.ITL[i] != baseReq.ITL[i] {
+			t.Errorf("ITL[%d] = %d after warmup window, want steady-state %d", i, warmReq.ITL[i], baseReq.ITL[i])
+		}
+	}
+}
+
+// TestWarmup_ZeroStepsIsInert verifies INV-6: WarmupSteps=0 leaves timing
+// unchanged even when WarmupFactor is set.
+func TestWarmup_ZeroStepsIsInert(t *testing.T) {
+	withFactor, _ := runWarmupRequest(t, 0, 5.0)
+	base, _ := runWarmupRequest(t, 0, 0)
+
+	if withFactor.Clock != base.Clock {
+		t.Errorf("Clock = %d, want %d (warmup must be inert when WarmupSteps=0)", withFactor.Clock, base.Clock)
+	}
+}
+
+func TestNewSimulator_WarmupValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   int
+		factor  float64
+		wantErr bool
+	}{
+		{"disabled", 0, 0, false},
+		{"valid", 10, 2.0, false},
+		{"factor one is a no-op", 10, 1.0, false},
+		{"negative steps", -1, 2.0, true},
+		{"factor below one", 10, 0.5, true},
+		{"NaN factor", 10, math.NaN(), true},
+		{"Inf factor", 10, math.Inf(1), true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestSimConfig()
+			cfg.WarmupSteps = tc.steps
+			cfg.WarmupFactor = tc.factor
+			_, err := NewSimulator(cfg, MustNewKVStoreFromConfig(cfg.KVCacheConfig), &fixedStepModel{stepTime: 1000})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("NewSimulator err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -281,12 +281,62 @@ func NewArrivalSampler(spec ArrivalSpec, ratePerMicrosecond float64) ArrivalSamp
 		scale := mean / math.Gamma(1.0+1.0/k)
 		return &WeibullSampler{shape: k, scale: scale}
 
+	case "mixture":
+		var total float64
+		for _, comp := range spec.Mixture {
+			total += comp.Weight
+		}
+		if total <= 0 {
+			// Validated before reaching here; defensive fallback
+			return &PoissonSampler{rateMicros: ratePerMicrosecond}
+		}
+		components := make([]ArrivalSampler, len(spec.Mixture))
+		for i, comp := range spec.Mixture {
+			components[i] = NewArrivalSampler(comp.ArrivalSpec, ratePerMicrosecond*comp.Weight/total)
+		}
+		return &MixtureArrivalSampler{components: components}
+
 	default:
 		// Validated before reaching here; defensive fallback
 		return &PoissonSampler{rateMicros: ratePerMicrosecond}
 	}
 }
 
+// MixtureArrivalSampler superposes independent arrival processes: the
+// client's arrivals are the union of each component's arrival times, so the
+// rates add up and each component keeps its own burstiness (e.g. a smooth
+// Poisson baseline plus bursty Gamma spikes). Components are stateless
+// samplers built by NewArrivalSampler, so none ever signals exhaustion.
+type MixtureArrivalSampler struct {
+	components []ArrivalSampler
+	next       []int64 // next arrival time of each component, in µs since the first sample
+	now        int64   // time of the last returned arrival
+}
+
+// SampleIAT returns the gap to the earliest pending component arrival
+// (lowest index on ties), floored at 1 µs like the other samplers.
+func (s *MixtureArrivalSampler) SampleIAT(rng *rand.Rand) int64 {
+	if s.next == nil {
+		s.next = make([]int64, len(s.components))
+		for i, comp := range s.components {
+			s.next[i] = comp.SampleIAT(rng)
+		}
+	}
+	first := 0
+	for i := range s.next {
+		if s.next[i] < s.next[first] {
+			first = i
+		}
+	}
+	iat := s.next[first] - s.now
+	if iat < 1 {
+		iat = 1
+	}
+	s.now += iat
+	s.next[first] += s.components[first].SampleIAT(rng)
+	return iat
+}
+
 // weibullShapeFromCV finds Weibull shape parameter k such that
 // CV² = Γ(1+2/k)/Γ(1+1/k)² - 1, using bisection.
 // Range: k ∈ [0.1, 100], tolerance: |CV_computed - CV_target| < 0.001.
@@ -619,3 +619,56 @@ func TestNormalizedExponentialSampler_EdgeCases(t *testing.T) {
 func ptrFloat64(v float64) *float64 {
 	return &v
 }
+
+func TestMixtureArrivalSampler_RateAndBurstiness(t *testing.T) {
+	// GIVEN a 10 req/s mixture of 80% Poisson and 20% Gamma with CV=4
+	rng := rand.New(rand.NewSource(42))
+	cv := 4.0
+	spec := ArrivalSpec{Process: "mixture", Mixture: []ArrivalComponent{
+		{Weight: 0.8, ArrivalSpec: ArrivalSpec{Process: "poisson"}},
+		{Weight: 0.2, ArrivalSpec: ArrivalSpec{Process: "gamma", CV: &cv}},
+	}}
+	sampler := NewArrivalSampler(spec, 10.0/1e6)
+
+	// WHEN 50000 IATs are sampled
+	n := 50000
+	iats := make([]float64, n)
+	short := 0
+	for i := range iats {
+		iat := sampler.SampleIAT(rng)
+		if iat < 1 {
+			t.Fatalf("IAT %d = %d, want >= 1", i, iat)
+		}
+		iats[i] = float64(iat)
+		if iat < 1000 { // 1% of the mean IAT
+			short++
+		}
+	}
+	mean, _ := meanAndVariance(iats)
+	mixCV := coefficientOfVariation(iats)
+	shortFrac := float64(short) / float64(n)
+	t.Logf("mean IAT %.0f µs, CV %.2f, short-gap fraction %.3f", mean, mixCV, shortFrac)
+
+	// THEN the rates add up to the client target: mean IAT ≈ 100000 µs (within 5%)
+	if math.Abs(mean-1e5)/1e5 > 0.05 {
+		t.Errorf("mean IAT = %.0f µs, want ≈ 100000 µs (within 5%%)", mean)
+	}
+	// AND the gamma bursts show: IATs vary more than Poisson (CV 1), and gaps
+	// under 1% of the mean are far more common than Poisson's ~1%
+	if mixCV < 1.1 {
+		t.Errorf("mixture CV = %.2f, want > 1.1 (bursty component)", mixCV)
+	}
+	if shortFrac < 0.05 {
+		t.Errorf("short-gap fraction = %.3f, want >= 0.05 (bursty component)", shortFrac)
+	}
+	// AND the Poisson baseline keeps arrivals flowing between bursts: the
+	// mixture is far smoother than the bursty component alone
+	burstOnly := NewArrivalSampler(ArrivalSpec{Process: "gamma", CV: &cv}, 10.0/1e6)
+	burstIATs := make([]float64, n)
+	for i := range burstIATs {
+		burstIATs[i] = float64(burstOnly.SampleIAT(rng))
+	}
+	if burstCV := coefficientOfVariation(burstIATs); mixCV > burstCV/2 {
+		t.Errorf("mixture CV = %.2f, want well below the bursty component's %.2f (smooth component)", mixCV, burstCV)
+	}
+}
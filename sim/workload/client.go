@@ -1,7 +1,9 @@
 package workload
 
 import (
+	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/inference-sim/inference-sim/sim"
 )
@@ -94,6 +96,56 @@ func windowsOverlap(a, b *LifecycleSpec) bool {
 	return false
 }
 
+// PrefixGroupTokens returns the shared prefix token sequence of every prefix
+// group in spec, ordered by group name (R2). The sequences are re-derived from
+// spec.Seed exactly as GenerateRequests draws them, so they match the prefixes
+// of the generated requests. Used to seed instance KV caches before arrivals
+// (SimConfig.KVPrefixSeeds).
+func PrefixGroupTokens(spec *WorkloadSpec) ([][]sim.TokenID, error) {
+	if err := validateAndExpandSpec(spec); err != nil {
+		return nil, err
+	}
+	allClients := append([]ClientSpec{}, spec.Clients...)
+	if len(spec.Cohorts) > 0 {
+		allClients = append(allClients, expandCohorts(spec.Cohorts, spec.Seed, spec.RandSource)...)
+	}
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
+	prefixes := generatePrefixTokens(allClients, rng.ForSubsystem(sim.SubsystemWorkloadGen))
+	groups := make([]string, 0, len(prefixes))
+	for g := range prefixes {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	seeds := make([][]sim.TokenID, 0, len(groups))
+	for _, g := range groups {
+		seeds = append(seeds, prefixes[g])
+	}
+	return seeds, nil
+}
+
+// newPrefixLengthSampler returns the sampler for client's prefix_length_distribution,
+// or nil when the client uses its group's whole prefix.
+func newPrefixLengthSampler(client *ClientSpec) (LengthSampler, error) {
+	if client.PrefixLengthDist == nil {
+		return nil, nil
+	}
+	sampler, err := NewLengthSampler(*client.PrefixLengthDist)
+	if err != nil {
+		return nil, fmt.Errorf("client %q prefix length distribution: %w", client.ID, err)
+	}
+	return sampler, nil
+}
+
+// samplePrefix returns the group prefix a request carries: all of prefix, or
+// with a prefix length sampler, its first min(sample, len(prefix)) tokens.
+// Draws from rng only when sampler is non-nil.
+func samplePrefix(prefix []sim.TokenID, sampler LengthSampler, rng *rand.Rand) []sim.TokenID {
+	if sampler == nil || len(prefix) == 0 {
+		return prefix
+	}
+	return prefix[:min(max(sampler.Sample(rng), 0), len(prefix))]
+}
+
 // generatePrefixTokens creates shared prefix token sequences per prefix group.
 // Clients in the same group get the same prefix tokens. The length is determined
 // by the first client in the group that specifies prefix_length; others in the
@@ -11,6 +11,12 @@ import (
 // Each cohort derives an independent RNG sub-stream from seed and its index.
 // Note: reordering cohorts in the input changes their index-derived seeds.
 func ExpandCohorts(cohorts []CohortSpec, seed int64) []ClientSpec {
+	return expandCohorts(cohorts, seed, "")
+}
+
+// expandCohorts is ExpandCohorts with the per-cohort RNGs drawn from the named
+// random source (WorkloadSpec.RandSource).
+func expandCohorts(cohorts []CohortSpec, seed int64, randSource string) []ClientSpec {
 	var expanded []ClientSpec
 	for i, cohort := range cohorts {
 		if cohort.Population <= 0 {
@@ -21,7 +27,7 @@ func ExpandCohorts(cohorts []CohortSpec, seed int64) []ClientSpec {
 		// Avoids XOR collision (seed=1^2 == seed=2^1) and zero-seed
 		// (seed==i+1 → XOR=0). Knuth multiplicative hash spreads entropy.
 		cohortSeed := seed*2654435761 + int64(i)
-		cohortRNG := rand.New(rand.NewSource(cohortSeed))
+		cohortRNG := newRandFromSeed(randSource, cohortSeed)
 
 		perMemberFraction := cohort.RateFraction / float64(cohort.Population)
 
@@ -41,24 +47,28 @@ func ExpandCohorts(cohorts []CohortSpec, seed int64) []ClientSpec {
 			}
 
 			client := ClientSpec{
-				ID:           clientID,
-				TenantID:     cohort.TenantID,
-				SLOClass:     cohort.SLOClass,
-				Model:        cohort.Model,
-				Adapter:      cohort.Adapter,
-				RateFraction: perMemberFraction,
-				Arrival:      cohort.Arrival,
-				InputDist:    cohort.InputDist,
-				OutputDist:   cohort.OutputDist,
-				PrefixGroup:  prefixGroup,
-				Streaming:    cohort.Streaming,
-				PrefixLength: cohort.PrefixLength,
+				ID:               clientID,
+				TenantID:         cohort.TenantID,
+				SLOClass:         cohort.SLOClass,
+				Model:            cohort.Model,
+				Adapter:          cohort.Adapter,
+				IngressPoint:     cohort.IngressPoint,
+				ParallelSamples:  cohort.ParallelSamples,
+				RateFraction:     perMemberFraction,
+				Arrival:          cohort.Arrival,
+				InputDist:        cohort.InputDist,
+				OutputDist:       cohort.OutputDist,
+				PrefixGroup:      prefixGroup,
+				Streaming:        cohort.Streaming,
+				PrefixLength:     cohort.PrefixLength,
+				PrefixLengthDist: cohort.PrefixLengthDist,
 				// Pointer fields shared across all expanded clients.
 				// Safe: GenerateRequests reads but never mutates these fields.
 				Reasoning:   cohort.Reasoning,
 				ClosedLoop:  cohort.ClosedLoop,
 				Timeout:     cohort.Timeout,
 				SLOTargetUs: cohort.SLOTargetUs,
+				Priority:    cohort.Priority,
 				Network:     cohort.Network,
 				Multimodal:  cohort.Multimodal,
 			}
@@ -95,6 +95,102 @@ func ConvertPreset(name string, rate float64, numRequests int, preset PresetConf
 	return spec, nil
 }
 
+// TargetLoad describes a workload by its aggregate characteristics for
+// GenerateSpecFromTarget (blis workload-gen).
+type TargetLoad struct {
+	RPS            float64 // aggregate request rate, req/s
+	InputMean      float64 // mean total input tokens per request (shared prefix included)
+	InputStdDev    float64
+	OutputMean     float64 // mean output tokens per request
+	OutputStdDev   float64
+	Tenants        int     // number of tenants sharing the load equally
+	PrefixFraction float64 // share of requests that start with their tenant's shared prefix, in [0, 1]
+	PrefixLength   int     // shared prefix tokens; required when PrefixFraction > 0, below InputMean
+	NumRequests    int64   // request cap; 0 = bounded by the horizon only
+	Seed           int64
+}
+
+// GenerateSpecFromTarget builds a v2 WorkloadSpec with Poisson arrivals at
+// target.RPS split equally across target.Tenants. Each tenant gets a client
+// for its prefix-sharing requests (rate share PrefixFraction, prefix group
+// "<tenant>-prefix") and one for the rest, omitting either when its share is
+// zero. Token lengths are gaussian with the given mean and standard
+// deviation, truncated to [1, mean + 4 std dev]; prefix clients draw the
+// suffix so the total input keeps InputMean.
+func GenerateSpecFromTarget(target TargetLoad) (*WorkloadSpec, error) {
+	if math.IsNaN(target.RPS) || math.IsInf(target.RPS, 0) || target.RPS <= 0 {
+		return nil, fmt.Errorf("rps must be a finite positive number, got %v", target.RPS)
+	}
+	for _, f := range []struct {
+		name      string
+		mean, std float64
+	}{
+		{"input", target.InputMean, target.InputStdDev},
+		{"output", target.OutputMean, target.OutputStdDev},
+	} {
+		if math.IsNaN(f.mean) || math.IsInf(f.mean, 0) || f.mean < 1 {
+			return nil, fmt.Errorf("%s mean must be a finite number >= 1, got %v", f.name, f.mean)
+		}
+		if math.IsNaN(f.std) || math.IsInf(f.std, 0) || f.std < 0 {
+			return nil, fmt.Errorf("%s std dev must be a finite number >= 0, got %v", f.name, f.std)
+		}
+	}
+	if target.Tenants < 1 {
+		return nil, fmt.Errorf("tenants must be >= 1, got %d", target.Tenants)
+	}
+	if math.IsNaN(target.PrefixFraction) || target.PrefixFraction < 0 || target.PrefixFraction > 1 {
+		return nil, fmt.Errorf("prefix fraction must be in [0, 1], got %v", target.PrefixFraction)
+	}
+	if target.PrefixFraction > 0 && (target.PrefixLength < 1 || float64(target.PrefixLength) >= target.InputMean) {
+		return nil, fmt.Errorf("prefix length must be in [1, input mean %v) when prefix fraction > 0, got %d",
+			target.InputMean, target.PrefixLength)
+	}
+	if target.NumRequests < 0 {
+		return nil, fmt.Errorf("num_requests must be >= 0, got %d", target.NumRequests)
+	}
+
+	gaussian := func(mean, std float64) DistSpec {
+		return DistSpec{Type: "gaussian", Params: map[string]float64{
+			"mean": mean, "std_dev": std, "min": 1, "max": math.Ceil(mean + 4*std),
+		}}
+	}
+	spec := &WorkloadSpec{
+		Version:       "2",
+		Seed:          target.Seed,
+		Category:      "language",
+		AggregateRate: target.RPS,
+		NumRequests:   target.NumRequests,
+	}
+	perTenant := 1 / float64(target.Tenants)
+	for i := 0; i < target.Tenants; i++ {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		if share := target.PrefixFraction * perTenant; share > 0 {
+			suffixMean := target.InputMean - float64(target.PrefixLength)
+			spec.Clients = append(spec.Clients, ClientSpec{
+				ID:           tenant + "-prefix",
+				TenantID:     tenant,
+				RateFraction: share,
+				Arrival:      ArrivalSpec{Process: "poisson"},
+				InputDist:    gaussian(suffixMean, math.Min(target.InputStdDev, suffixMean)),
+				OutputDist:   gaussian(target.OutputMean, target.OutputStdDev),
+				PrefixGroup:  tenant + "-prefix",
+				PrefixLength: target.PrefixLength,
+			})
+		}
+		if share := (1 - target.PrefixFraction) * perTenant; share > 0 {
+			spec.Clients = append(spec.Clients, ClientSpec{
+				ID:           tenant,
+				TenantID:     tenant,
+				RateFraction: share,
+				Arrival:      ArrivalSpec{Process: "poisson"},
+				InputDist:    gaussian(target.InputMean, target.InputStdDev),
+				OutputDist:   gaussian(target.OutputMean, target.OutputStdDev),
+			})
+		}
+	}
+	return spec, nil
+}
+
 // ConvertInferencePerf converts an inference-perf YAML spec file into a v2 WorkloadSpec.
 // Wraps existing ExpandInferencePerfSpec with file loading.
 func ConvertInferencePerf(path string) (*WorkloadSpec, error) {
@@ -1,7 +1,11 @@
 package workload
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestConvertPreset_ValidParams_ProducesV2Spec(t *testing.T) {
@@ -60,7 +64,7 @@ func TestComposeSpecs_TwoSpecs_MergesClients(t *testing.T) {
 		AggregateRate: 10.0,
 		Clients: []ClientSpec{
 			{ID: "a", RateFraction: 1.0, Arrival: ArrivalSpec{Process: "poisson"},
-				InputDist: DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 100, "std_dev": 10, "min": 1, "max": 200}},
+				InputDist:  DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 100, "std_dev": 10, "min": 1, "max": 200}},
 				OutputDist: DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 50, "std_dev": 5, "min": 1, "max": 100}}},
 		},
 	}
@@ -69,7 +73,7 @@ func TestComposeSpecs_TwoSpecs_MergesClients(t *testing.T) {
 		AggregateRate: 5.0,
 		Clients: []ClientSpec{
 			{ID: "b", RateFraction: 1.0, Arrival: ArrivalSpec{Process: "constant"},
-				InputDist: DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 200, "std_dev": 20, "min": 1, "max": 400}},
+				InputDist:  DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 200, "std_dev": 20, "min": 1, "max": 400}},
 				OutputDist: DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 100, "std_dev": 10, "min": 1, "max": 200}}},
 		},
 	}
@@ -166,3 +170,100 @@ func TestConvertInferencePerf_EmptyPath_ReturnsError(t *testing.T) {
 		t.Fatal("expected error for empty path")
 	}
 }
+
+// TestGenerateSpecFromTarget_RoundTripsAndHitsTargetRPS verifies the
+// generated spec survives YAML marshal + strict load, validates, and that
+// GenerateRequests on it yields roughly the requested rate, tenant split, and
+// prefix-sharing fraction.
+func TestGenerateSpecFromTarget_RoundTripsAndHitsTargetRPS(t *testing.T) {
+	// GIVEN a 40 req/s target across 4 tenants with 30% prefix sharing
+	target := TargetLoad{
+		RPS: 40, InputMean: 512, InputStdDev: 128, OutputMean: 128, OutputStdDev: 32,
+		Tenants: 4, PrefixFraction: 0.3, PrefixLength: 256, Seed: 7,
+	}
+	generated, err := GenerateSpecFromTarget(target)
+	if err != nil {
+		t.Fatalf("GenerateSpecFromTarget: %v", err)
+	}
+
+	// WHEN it is written as YAML and loaded back
+	data, err := yaml.Marshal(generated)
+	if err != nil {
+		t.Fatalf("yaml.Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "workload.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadWorkloadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadWorkloadSpec: %v", err)
+	}
+
+	// THEN it validates
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(spec.Clients) != 2*target.Tenants {
+		t.Errorf("clients = %d, want %d (prefix + plain per tenant)", len(spec.Clients), 2*target.Tenants)
+	}
+
+	// AND generating 60 s of requests achieves the target rate within 10%
+	const horizonSec = 60
+	reqs, err := GenerateRequests(spec, horizonSec*1_000_000, 0)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	if rps := float64(len(reqs)) / horizonSec; rps < 0.9*target.RPS || rps > 1.1*target.RPS {
+		t.Errorf("achieved %.1f req/s, want %v ± 10%%", rps, target.RPS)
+	}
+
+	// AND every tenant gets traffic, with ~30% of requests in a prefix group
+	tenants := map[string]int{}
+	prefixed := 0
+	for _, r := range reqs {
+		tenants[r.TenantID]++
+		if r.PrefixGroup != "" {
+			prefixed++
+		}
+	}
+	if len(tenants) != target.Tenants {
+		t.Errorf("requests span %d tenants, want %d: %v", len(tenants), target.Tenants, tenants)
+	}
+	if frac := float64(prefixed) / float64(len(reqs)); frac < 0.25 || frac > 0.35 {
+		t.Errorf("prefix-sharing fraction = %.3f, want ~%v", frac, target.PrefixFraction)
+	}
+}
+
+func TestGenerateSpecFromTarget_ZeroPrefixFraction_OneClientPerTenant(t *testing.T) {
+	spec, err := GenerateSpecFromTarget(TargetLoad{RPS: 5, InputMean: 100, OutputMean: 50, Tenants: 3})
+	if err != nil {
+		t.Fatalf("GenerateSpecFromTarget: %v", err)
+	}
+	if len(spec.Clients) != 3 {
+		t.Fatalf("clients = %d, want 3", len(spec.Clients))
+	}
+	for _, c := range spec.Clients {
+		if c.PrefixGroup != "" || c.PrefixLength != 0 {
+			t.Errorf("client %s: unexpected prefix group %q / length %d", c.ID, c.PrefixGroup, c.PrefixLength)
+		}
+	}
+}
+
+func TestGenerateSpecFromTarget_InvalidTarget_ReturnsError(t *testing.T) {
+	valid := TargetLoad{RPS: 10, InputMean: 512, OutputMean: 128, Tenants: 1, PrefixFraction: 0.5, PrefixLength: 128}
+	for name, mutate := range map[string]func(*TargetLoad){
+		"zero rps":                 func(tl *TargetLoad) { tl.RPS = 0 },
+		"zero input mean":          func(tl *TargetLoad) { tl.InputMean = 0 },
+		"negative output std":      func(tl *TargetLoad) { tl.OutputStdDev = -1 },
+		"zero tenants":             func(tl *TargetLoad) { tl.Tenants = 0 },
+		"prefix fraction above 1":  func(tl *TargetLoad) { tl.PrefixFraction = 1.5 },
+		"prefix longer than input": func(tl *TargetLoad) { tl.PrefixLength = 512 },
+	} {
+		target := valid
+		mutate(&target)
+		if _, err := GenerateSpecFromTarget(target); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
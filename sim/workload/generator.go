@@ -29,12 +29,12 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 	// Build working client list without mutating spec.Clients (idempotency, INV-6).
 	allClients := append([]ClientSpec{}, spec.Clients...)
 	if len(spec.Cohorts) > 0 {
-		expanded := ExpandCohorts(spec.Cohorts, spec.Seed)
+		expanded := expandCohorts(spec.Cohorts, spec.Seed, spec.RandSource)
 		allClients = append(allClients, expanded...)
 	}
 
 	// Create partitioned RNG for deterministic generation
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(spec.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
 	workloadRNG := rng.ForSubsystem(sim.SubsystemWorkloadGen)
 
 	// Normalize rate fractions
@@ -72,7 +72,7 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 
 		// Create per-client RNG (derived from main RNG for isolation)
 		clientSeed := workloadRNG.Int63()
-		clientRNG := newRandFromSeed(clientSeed)
+		clientRNG := newRandFromSeed(spec.RandSource, clientSeed)
 
 		// Create samplers.
 		// When CustomSamplerFactory is set, clientRate is only used for the
@@ -83,7 +83,7 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 			// This isolates the sampler's N-draw RNG consumption (for N pre-generated intervals)
 			// from downstream content sampling, keeping input/output distributions stable.
 			subSeed := clientRNG.Int63()
-			subRNG := newRandFromSeed(subSeed)
+			subRNG := newRandFromSeed(spec.RandSource, subSeed)
 			arrivalSampler = client.CustomSamplerFactory(subRNG)
 		} else {
 			arrivalSampler = NewArrivalSampler(client.Arrival, clientRate)
@@ -96,6 +96,10 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 		if err != nil {
 			return nil, fmt.Errorf("client %q output distribution: %w", client.ID, err)
 		}
+		prefixSampler, err := newPrefixLengthSampler(client)
+		if err != nil {
+			return nil, err
+		}
 
 		// Get prefix for this client's group
 		var prefix []sim.TokenID
@@ -143,10 +147,13 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 				if err != nil {
 					return nil, fmt.Errorf("client %q reasoning: %w", client.ID, err)
 				}
-				// Set Deadline and SLOTargetUs on all reasoning requests (not set in reasoning.go)
+				// Set Deadline, SLOTargetUs, and ExplicitPriority on all reasoning requests (not set in reasoning.go)
 				for _, req := range reasoningReqs {
 					req.Deadline = computeDeadline(req.ArrivalTime, client.Timeout, true)
 					req.SLOTargetUs = derefInt64(client.SLOTargetUs)
+					client.Priority.assign(req)
+					req.IngressPoint = client.IngressPoint
+					req.ParallelSamples = client.ParallelSamples
 				}
 				for _, req := range reasoningReqs {
 					if req.ArrivalTime >= horizon {
@@ -196,10 +203,13 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 					return nil, fmt.Errorf("client %q reasoning: %w", client.ID, err)
 				}
 				// Prefix is seeded into the shared buffer inside reasoning.go (#1445).
-				// Set Deadline and SLOTargetUs on all reasoning requests (not set in reasoning.go)
+				// Set Deadline, SLOTargetUs, and ExplicitPriority on all reasoning requests (not set in reasoning.go)
 				for _, req := range reasoningReqs {
 					req.Deadline = computeDeadline(req.ArrivalTime, client.Timeout, true)
 					req.SLOTargetUs = derefInt64(client.SLOTargetUs)
+					client.Priority.assign(req)
+					req.IngressPoint = client.IngressPoint
+					req.ParallelSamples = client.ParallelSamples
 				}
 				// Count all generated rounds for perClientCap safety (R19)
 				clientReqCount += int64(len(reasoningReqs))
@@ -269,9 +279,9 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 			}
 
 			var prefixLength int
-			if len(prefix) > 0 {
-				inputTokens = append(append([]sim.TokenID{}, prefix...), inputTokens...)
-				prefixLength = len(prefix)
+			if reqPrefix := samplePrefix(prefix, prefixSampler, clientRNG); len(reqPrefix) > 0 {
+				inputTokens = append(append([]sim.TokenID{}, reqPrefix...), inputTokens...)
+				prefixLength = len(reqPrefix)
 			}
 
 			req := &sim.Request{
@@ -298,6 +308,9 @@ func GenerateRequests(spec *WorkloadSpec, horizon int64, maxRequests int64) ([]*
 				PrefixLength:     prefixLength,
 				Streaming:        client.Streaming,
 			}
+			client.Priority.assign(req)
+			req.IngressPoint = client.IngressPoint
+			req.ParallelSamples = client.ParallelSamples
 			allRequests = append(allRequests, req)
 			clientReqCount++
 		}
@@ -354,7 +367,7 @@ func GenerateWorkload(spec *WorkloadSpec, horizon int64, maxRequests int64) (*Ge
 	hasConcurrency := false
 	allClients := append([]ClientSpec{}, spec.Clients...)
 	if len(spec.Cohorts) > 0 {
-		allClients = append(allClients, ExpandCohorts(spec.Cohorts, spec.Seed)...)
+		allClients = append(allClients, expandCohorts(spec.Cohorts, spec.Seed, spec.RandSource)...)
 	}
 	for i := range allClients {
 		if isClosedLoop(&allClients[i]) {
@@ -372,7 +385,7 @@ func GenerateWorkload(spec *WorkloadSpec, horizon int64, maxRequests int64) (*Ge
 	// Blueprint RNG uses a fixed offset from spec seed to avoid colliding with
 	// GenerateRequests' internal RNG draws. The offset (spec.Seed + 7919) is a
 	// prime shift that produces an independent stream.
-	blueprintRNG := rand.New(rand.NewSource(spec.Seed + 7919))
+	blueprintRNG := newRandFromSeed(spec.RandSource, spec.Seed+7919)
 
 	var sessions []SessionBlueprint
 	round0Only := make([]*sim.Request, 0, len(reqs))
@@ -388,6 +401,10 @@ func GenerateWorkload(spec *WorkloadSpec, horizon int64, maxRequests int64) (*Ge
 			continue
 		}
 		mt := client.Reasoning.MultiTurn
+		thinkTimeSampler, err := mt.thinkTimeSampler()
+		if err != nil {
+			return nil, fmt.Errorf("client %q: %w", client.ID, err)
+		}
 
 		// Create samplers for the blueprint
 		inputSampler, err := NewLengthSampler(client.InputDist)
@@ -454,22 +471,23 @@ func GenerateWorkload(spec *WorkloadSpec, horizon int64, maxRequests int64) (*Ge
 		for _, sessID := range sortedSessionIDs {
 			sessSeed := blueprintRNG.Int63()
 			sessions = append(sessions, SessionBlueprint{
-				SessionID:     sessID,
-				ClientID:      client.ID,
-				MaxRounds:     mt.MaxRounds,
-				ContextGrowth: mt.ContextGrowth,
-				ThinkTimeUs:   mt.ThinkTimeUs,
-				Timeout:       client.Timeout,
-				Horizon:       horizon,
-				InputSampler:  inputSampler,
-				OutputSampler: outputSampler,
-				RNG:           rand.New(rand.NewSource(sessSeed)),
-				Prefix:        prefixTokens,
-				TenantID:      client.TenantID,
-				SLOClass:      client.SLOClass,
-				Model:         client.Model,
-				Adapter:       client.Adapter,
-				SLOTargetUs:   derefInt64(client.SLOTargetUs),
+				SessionID:        sessID,
+				ClientID:         client.ID,
+				MaxRounds:        mt.MaxRounds,
+				ContextGrowth:    mt.ContextGrowth,
+				ThinkTimeUs:      mt.ThinkTimeUs,
+				ThinkTimeSampler: thinkTimeSampler, // stateless: safe to share across sessions
+				Timeout:          client.Timeout,
+				Horizon:          horizon,
+				InputSampler:     inputSampler,
+				OutputSampler:    outputSampler,
+				RNG:              newRandFromSeed(spec.RandSource, sessSeed),
+				Prefix:           prefixTokens,
+				TenantID:         client.TenantID,
+				SLOClass:         client.SLOClass,
+				Model:            client.Model,
+				Adapter:          client.Adapter,
+				SLOTargetUs:      derefInt64(client.SLOTargetUs),
 			})
 		}
 	}
@@ -495,12 +513,12 @@ func GenerateWorkload(spec *WorkloadSpec, horizon int64, maxRequests int64) (*Ge
 	//
 	// Re-derive prefix tokens by initializing a fresh RNG from spec.Seed —
 	// same seed produces same prefix tokens as GenerateRequests produced.
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(spec.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
 	workloadRNG := rng.ForSubsystem(sim.SubsystemWorkloadGen)
 	prefixes := generatePrefixTokens(allClients, workloadRNG)
 
 	concurrencySeeds, concurrencyBlueprints, totalConcurrencyUsers, err :=
-		generateConcurrencySeedsAndBlueprints(allClients, prefixes, spec.Seed, horizon, maxRequests, int64(len(round0Only)))
+		generateConcurrencySeedsAndBlueprints(allClients, prefixes, spec.Seed, spec.RandSource, horizon, maxRequests, int64(len(round0Only)))
 	if err != nil {
 		return nil, err
 	}
@@ -552,6 +570,7 @@ func generateConcurrencySeedsAndBlueprints(
 	allClients []ClientSpec,
 	prefixes map[string][]sim.TokenID,
 	specSeed int64,
+	randSource string,
 	horizon int64,
 	maxRequests int64,
 	alreadyKept int64,
@@ -561,7 +580,7 @@ func generateConcurrencySeedsAndBlueprints(
 	// specSeed + 7919, so the two streams do not produce identical sequences for
 	// the same spec seed. If new per-client RNG streams are added here, choose an
 	// offset not already in use and document it with the same pattern.
-	concurrencyRNG := rand.New(rand.NewSource(specSeed + 10007))
+	concurrencyRNG := newRandFromSeed(randSource, specSeed+10007)
 
 	for i := range allClients {
 		client := &allClients[i]
@@ -577,6 +596,10 @@ func generateConcurrencySeedsAndBlueprints(
 		if sErr != nil {
 			return nil, nil, 0, fmt.Errorf("client %q output distribution: %w", client.ID, sErr)
 		}
+		prefixSampler, sErr := newPrefixLengthSampler(client)
+		if sErr != nil {
+			return nil, nil, 0, sErr
+		}
 
 		var prefix []sim.TokenID
 		if client.PrefixGroup != "" {
@@ -590,7 +613,7 @@ func generateConcurrencySeedsAndBlueprints(
 				break
 			}
 			userSeed := concurrencyRNG.Int63()
-			userRNG := rand.New(rand.NewSource(userSeed))
+			userRNG := newRandFromSeed(randSource, userSeed)
 
 			sessionID := fmt.Sprintf("concurrency_%s_user_%d", client.ID, u)
 
@@ -607,9 +630,9 @@ func generateConcurrencySeedsAndBlueprints(
 			outputTokens := sim.GenerateRandomTokenIDs(userRNG, outputLen)
 
 			var prefixLength int
-			if len(prefix) > 0 {
-				inputTokens = append(append([]sim.TokenID{}, prefix...), inputTokens...)
-				prefixLength = len(prefix)
+			if reqPrefix := samplePrefix(prefix, prefixSampler, userRNG); len(reqPrefix) > 0 {
+				inputTokens = append(append([]sim.TokenID{}, reqPrefix...), inputTokens...)
+				prefixLength = len(reqPrefix)
 			}
 
 			seed := &sim.Request{
@@ -632,6 +655,9 @@ func generateConcurrencySeedsAndBlueprints(
 				SessionID:    sessionID,
 				RoundIndex:   0,
 			}
+			client.Priority.assign(seed)
+			seed.IngressPoint = client.IngressPoint
+			seed.ParallelSamples = client.ParallelSamples
 			seeds = append(seeds, seed)
 
 			// Create blueprint for this virtual user's session
@@ -646,7 +672,7 @@ func generateConcurrencySeedsAndBlueprints(
 				Horizon:         horizon,
 				InputSampler:    inputSampler,
 				OutputSampler:   outputSampler,
-				RNG:             rand.New(rand.NewSource(bpSeed)),
+				RNG:             newRandFromSeed(randSource, bpSeed),
 				Prefix:          prefix,
 				TenantID:        client.TenantID,
 				SLOClass:        client.SLOClass,
@@ -697,15 +723,16 @@ func lastWindowEndUs(lifecycle *LifecycleSpec) int64 {
 	return maxEnd
 }
 
-// newRandFromSeed creates a new *rand.Rand from a seed (avoids importing math/rand in callers).
-func newRandFromSeed(seed int64) *rand.Rand {
-	return rand.New(rand.NewSource(seed))
+// newRandFromSeed creates a new *rand.Rand over the named random source
+// (WorkloadSpec.RandSource; "" = stdlib) from a seed.
+func newRandFromSeed(source string, seed int64) *rand.Rand {
+	return sim.NewRand(source, seed)
 }
 
 // validateAndExpandSpec performs the spec-mutating prelude shared by
 // GenerateRequests and GenerateWorkloadLazy: mutual-exclusion check across
 // primary workload sources, inference-perf expansion, ServeGen data load,
-// v1→v2 upgrade, and final Validate.
+// v1→v2 upgrade, field-level ValidateFields, and final Validate.
 //
 // Mutates spec in place: spec.Clients may be populated by InferencePerf /
 // ServeGen expansion; spec.AggregateRate may be overridden by the
@@ -731,6 +758,11 @@ func validateAndExpandSpec(spec *WorkloadSpec) error {
 		return err
 	}
 	UpgradeV1ToV2(spec)
+	// Field-level checks first so a hand-written spec reports every malformed
+	// field at once instead of one error per run.
+	if errs := spec.ValidateFields(); len(errs) > 0 {
+		return fmt.Errorf("invalid workload spec:\n%w", errs)
+	}
 	if err := spec.Validate(); err != nil {
 		return fmt.Errorf("invalid workload spec: %w", err)
 	}
@@ -895,7 +927,7 @@ func generateTimeVaryingRequests(
 
 		// Create per-client RNG for determinism (isolates client entropy).
 		clientSeed := rng.Int63()
-		clientRNG := newRandFromSeed(clientSeed)
+		clientRNG := newRandFromSeed(spec.RandSource, clientSeed)
 
 		// Generate requests for each window.
 		for _, window := range client.Lifecycle.Windows {
@@ -1035,9 +1067,12 @@ func generateRequestsForWindow(
 			}
 
 			// BC-2: prefix is seeded into the shared buffer inside reasoning.go (#1445).
-			// BC-3: Set Deadline on all reasoning requests
+			// BC-3: Set Deadline and ExplicitPriority on all reasoning requests
 			for _, req := range reasoningReqs {
 				req.Deadline = computeDeadline(req.ArrivalTime, client.Timeout, true)
+				client.Priority.assign(req)
+				req.IngressPoint = client.IngressPoint
+				req.ParallelSamples = client.ParallelSamples
 			}
 
 			// BC-5: Filter rounds outside window boundary
@@ -1127,6 +1162,9 @@ func generateRequestsForWindow(
 			Deadline:     0, // Set by caller if needed.
 			SLOTargetUs:  derefInt64(client.SLOTargetUs),
 		}
+		client.Priority.assign(req)
+		req.IngressPoint = client.IngressPoint
+		req.ParallelSamples = client.ParallelSamples
 		requests = append(requests, req)
 	}
 
@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -266,6 +267,61 @@ func TestGenerateRequests_PrefixGroup_SharedPrefix(t *testing.T) {
 	}
 }
 
+func TestGenerateRequests_PrefixLengthDistribution_SharesShortestPrefix(t *testing.T) {
+	// GIVEN a prefix group of up to 200 tokens whose per-request length is sampled
+	spec := &WorkloadSpec{
+		Version: "1", Seed: 42, Category: "language", AggregateRate: 20.0,
+		Clients: []ClientSpec{
+			{ID: "a", TenantID: "a", RateFraction: 1.0, PrefixGroup: "shared", PrefixLength: 200,
+				PrefixLengthDist: &DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 120, "std_dev": 40, "min": 40, "max": 300}},
+				Arrival:          ArrivalSpec{Process: "poisson"},
+				InputDist:        DistSpec{Type: "constant", Params: map[string]float64{"value": 20}},
+				OutputDist:       DistSpec{Type: "constant", Params: map[string]float64{"value": 5}}},
+		},
+	}
+
+	// WHEN requests are generated
+	requests, err := GenerateRequests(spec, 1e6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) < 5 {
+		t.Fatalf("need at least 5 requests, got %d", len(requests))
+	}
+
+	// THEN prefix lengths vary, stay within [min, prefix_length], and add to the input
+	lengths := make(map[int]bool)
+	shortest := requests[0]
+	for _, req := range requests {
+		if req.PrefixLength < 40 || req.PrefixLength > 200 {
+			t.Errorf("PrefixLength = %d, want within [40, 200]", req.PrefixLength)
+		}
+		if got := req.InputLen(); got != int64(req.PrefixLength)+20 {
+			t.Errorf("InputLen = %d, want PrefixLength %d + 20", got, req.PrefixLength)
+		}
+		lengths[req.PrefixLength] = true
+		if req.PrefixLength < shortest.PrefixLength {
+			shortest = req
+		}
+	}
+	if len(lengths) < 2 {
+		t.Errorf("all %d requests have prefix length %v, want varying lengths", len(requests), lengths)
+	}
+
+	// AND every request shares the group prefix up to the shortest member's length
+	n := int64(shortest.PrefixLength)
+	want := shortest.InputTokenSlice(0, n)
+	for i, req := range requests {
+		got := req.InputTokenSlice(0, n)
+		for j := range want {
+			if got[j] != want[j] {
+				t.Errorf("request %d: prefix token %d = %d, want %d", i, j, got[j], want[j])
+				break
+			}
+		}
+	}
+}
+
 func TestGenerateRequests_MaxRequests_CapsOutput(t *testing.T) {
 	// BC-1, BC-6: maxRequests caps total output even with long horizon
 	spec := &WorkloadSpec{
@@ -894,6 +950,60 @@ func TestGenerateRequests_ReasoningClient_RespectsLifecycleWindows(t *testing.T)
 	}
 }
 
+// TestPrefixGroupTokens_MatchesGeneratedPrefixes verifies that the seeds
+// returned for KV prefix seeding are, in group-name order, exactly the prefixes
+// GenerateRequests prepends to each group's requests.
+func TestPrefixGroupTokens_MatchesGeneratedPrefixes(t *testing.T) {
+	newSpec := func() *WorkloadSpec {
+		return &WorkloadSpec{
+			Version: "2", Seed: 7, AggregateRate: 20.0,
+			Clients: []ClientSpec{
+				{ID: "z", TenantID: "z", RateFraction: 0.5, PrefixGroup: "zeta", PrefixLength: 32,
+					Arrival:    ArrivalSpec{Process: "poisson"},
+					InputDist:  DistSpec{Type: "constant", Params: map[string]float64{"value": 40}},
+					OutputDist: DistSpec{Type: "constant", Params: map[string]float64{"value": 10}}},
+				{ID: "a", TenantID: "a", RateFraction: 0.5, PrefixGroup: "alpha", PrefixLength: 16,
+					Arrival:    ArrivalSpec{Process: "poisson"},
+					InputDist:  DistSpec{Type: "constant", Params: map[string]float64{"value": 40}},
+					OutputDist: DistSpec{Type: "constant", Params: map[string]float64{"value": 10}}},
+			},
+		}
+	}
+	seeds, err := PrefixGroupTokens(newSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeds) != 2 || len(seeds[0]) != 16 || len(seeds[1]) != 32 {
+		t.Fatalf("seeds lengths = %v, want [alpha:16 zeta:32]", seedLens(seeds))
+	}
+
+	requests, err := GenerateRequests(newSpec(), 1e6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byGroup := map[string][]sim.TokenID{"alpha": seeds[0], "zeta": seeds[1]}
+	for _, req := range requests {
+		want := byGroup[req.PrefixGroup]
+		got := req.InputTokens[:req.PrefixLength]
+		if len(got) != len(want) {
+			t.Fatalf("request %s: prefix length %d, want %d", req.ID, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("request %s: prefix token %d = %d, want %d", req.ID, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func seedLens(seeds [][]sim.TokenID) []int {
+	lens := make([]int, len(seeds))
+	for i, s := range seeds {
+		lens[i] = len(s)
+	}
+	return lens
+}
+
 func TestGenerateRequests_ReasoningClient_PrependsPrefixTokens(t *testing.T) {
 	// BC-1/BC-2: Reasoning paths must prepend shared prefix tokens, just like
 	// the standard request path. Both SingleSession and multi-session must work.
@@ -3492,3 +3602,49 @@ func TestGenerateWorkload_TimeVaryingClosedLoopReasoning(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateRequests_XoshiroSource_IdenticalTokenSequences(t *testing.T) {
+	// GIVEN a spec on the vendored xoshiro256** source
+	newSpec := func(source string) *WorkloadSpec {
+		return &WorkloadSpec{
+			Version: "2", Seed: 7, Category: "language", AggregateRate: 10.0, RandSource: source,
+			Clients: []ClientSpec{{
+				ID: "c1", RateFraction: 1.0, PrefixGroup: "sys", PrefixLength: 16,
+				Arrival:    ArrivalSpec{Process: "poisson"},
+				InputDist:  DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 100, "std_dev": 20, "min": 10, "max": 500}},
+				OutputDist: DistSpec{Type: "exponential", Params: map[string]float64{"mean": 50}},
+			}},
+		}
+	}
+
+	// WHEN it is generated twice
+	r1, err := GenerateRequests(newSpec(sim.RandSourceXoshiro), 1e6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := GenerateRequests(newSpec(sim.RandSourceXoshiro), 1e6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN both runs produce identical arrivals and token sequences
+	if len(r1) == 0 || len(r1) != len(r2) {
+		t.Fatalf("request counts: %d vs %d", len(r1), len(r2))
+	}
+	for i := range r1 {
+		if r1[i].ArrivalTime != r2[i].ArrivalTime ||
+			!reflect.DeepEqual(r1[i].InputTokens, r2[i].InputTokens) ||
+			!reflect.DeepEqual(r1[i].OutputTokens, r2[i].OutputTokens) {
+			t.Fatalf("request %d differs between runs", i)
+		}
+	}
+
+	// AND the source is actually in use: the stdlib stream differs
+	std, err := GenerateRequests(newSpec(""), 1e6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(std[0].InputTokens, r1[0].InputTokens) {
+		t.Error("xoshiro256ss and stdlib produced the same first token sequence")
+	}
+}
@@ -0,0 +1,30 @@
+package workload
+
+import "testing"
+
+// TestGenerateRequests_ThreadsIngressPoint verifies each client's
+// ingress_point is stamped on every request it generates.
+func TestGenerateRequests_ThreadsIngressPoint(t *testing.T) {
+	spec := adapterTestSpec("", "")
+	east := spec.Clients[0]
+	east.ID, east.IngressPoint, east.RateFraction = "east", "us-east", 0.5
+	west := east
+	west.ID, west.IngressPoint = "west", "eu-west"
+	spec.Clients = []ClientSpec{east, west}
+
+	reqs, err := GenerateRequests(spec, int64(5e6), 60)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	want := map[string]string{"east": "us-east", "west": "eu-west"}
+	seen := map[string]int{}
+	for _, r := range reqs {
+		if r.IngressPoint != want[r.ClientID] {
+			t.Fatalf("request %s (client %s): IngressPoint = %q, want %q", r.ID, r.ClientID, r.IngressPoint, want[r.ClientID])
+		}
+		seen[r.IngressPoint]++
+	}
+	if seen["us-east"] == 0 || seen["eu-west"] == 0 {
+		t.Errorf("expected requests from both ingress points, got %v", seen)
+	}
+}
@@ -0,0 +1,39 @@
+package workload
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateRequests_ThreadsParallelSamples verifies each client's
+// parallel_samples is stamped on every request it generates, and that a
+// negative count is rejected.
+func TestGenerateRequests_ThreadsParallelSamples(t *testing.T) {
+	spec := adapterTestSpec("", "")
+	sampled := spec.Clients[0]
+	sampled.ID, sampled.ParallelSamples, sampled.RateFraction = "sampled", 4, 0.5
+	plain := sampled
+	plain.ID, plain.ParallelSamples = "plain", 0
+	spec.Clients = []ClientSpec{sampled, plain}
+
+	reqs, err := GenerateRequests(spec, int64(5e6), 60)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	want := map[string]int{"sampled": 4, "plain": 0}
+	seen := map[string]int{}
+	for _, r := range reqs {
+		if r.ParallelSamples != want[r.ClientID] {
+			t.Fatalf("request %s (client %s): ParallelSamples = %d, want %d", r.ID, r.ClientID, r.ParallelSamples, want[r.ClientID])
+		}
+		seen[r.ClientID]++
+	}
+	if seen["sampled"] == 0 || seen["plain"] == 0 {
+		t.Errorf("expected requests from both clients, got %v", seen)
+	}
+
+	spec.Clients[0].ParallelSamples = -1
+	if err := spec.Validate(); err == nil || !strings.Contains(err.Error(), "parallel_samples") {
+		t.Errorf("Validate with parallel_samples -1 = %v, want a parallel_samples error", err)
+	}
+}
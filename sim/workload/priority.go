@@ -0,0 +1,110 @@
+package workload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/inference-sim/inference-sim/sim"
+)
+
+// PrioritySpec sets the explicit numeric priority (sim.Request.ExplicitPriority)
+// of a client's requests, independent of slo_class. Higher = more urgent; only
+// read by the simulator under priority policy "explicit".
+//
+// Either Value (every request gets it) or Weights (a discrete distribution
+// over priorities, keyed by priority with relative weights) may be set. A
+// multi-turn session draws once and all its rounds share the result.
+type PrioritySpec struct {
+	Value   int             `yaml:"value,omitempty"`
+	Weights map[int]float64 `yaml:"weights,omitempty"`
+}
+
+// validate checks that Value and Weights are not both set and that Weights
+// are finite, non-negative, and not all zero (R3).
+func (p *PrioritySpec) validate() error {
+	if len(p.Weights) == 0 {
+		return nil
+	}
+	if p.Value != 0 {
+		return fmt.Errorf("value and weights are mutually exclusive")
+	}
+	var total float64
+	for _, pri := range sortedPriorities(p.Weights) {
+		w := p.Weights[pri]
+		if math.IsNaN(w) || math.IsInf(w, 0) || w < 0 {
+			return fmt.Errorf("weights[%d] must be a finite value >= 0, got %v", pri, w)
+		}
+		total += w
+	}
+	if total <= 0 {
+		return fmt.Errorf("weights must sum to a positive value")
+	}
+	return nil
+}
+
+// assign sets req.ExplicitPriority. A nil spec leaves it at 0.
+//
+// Weighted draws are a pure function of the request rather than an RNG
+// stream, so enabling priorities perturbs no other sampling (INV-6) and the
+// eager and lazy generators, which build the same requests in different
+// orders, agree. The draw is keyed by SessionID when set (one priority per
+// session), otherwise by client, arrival time, and input length.
+func (p *PrioritySpec) assign(req *sim.Request) {
+	if p == nil {
+		return
+	}
+	if len(p.Weights) == 0 {
+		req.ExplicitPriority = p.Value
+		return
+	}
+	h := fnv.New64a()
+	if req.SessionID != "" {
+		_, _ = h.Write([]byte(req.SessionID))
+	} else {
+		var buf [16]byte
+		binary.LittleEndian.PutUint64(buf[:8], uint64(req.ArrivalTime))
+		binary.LittleEndian.PutUint64(buf[8:], uint64(len(req.InputTokens)))
+		_, _ = h.Write([]byte(req.ClientID))
+		_, _ = h.Write(buf[:])
+	}
+	u := float64(h.Sum64()>>11) / (1 << 53) // uniform in [0, 1)
+	req.ExplicitPriority = p.pick(u)
+}
+
+// pick maps u in [0, 1) to a priority by cumulative weight, walking
+// priorities in ascending order (R2: deterministic map iteration).
+func (p *PrioritySpec) pick(u float64) int {
+	pris := sortedPriorities(p.Weights)
+	var total float64
+	for _, pri := range pris {
+		total += p.Weights[pri]
+	}
+	target := u * total
+	var acc float64
+	for _, pri := range pris {
+		acc += p.Weights[pri]
+		if target < acc {
+			return pri
+		}
+	}
+	// Float rounding can leave target == total; fall back to the last
+	// priority with positive weight.
+	for i := len(pris) - 1; i >= 0; i-- {
+		if p.Weights[pris[i]] > 0 {
+			return pris[i]
+		}
+	}
+	return 0
+}
+
+func sortedPriorities(weights map[int]float64) []int {
+	pris := make([]int, 0, len(weights))
+	for pri := range weights {
+		pris = append(pris, pri)
+	}
+	sort.Ints(pris)
+	return pris
+}
@@ -0,0 +1,99 @@
+package workload
+
+import (
+	"math"
+	"testing"
+)
+
+func priorityTestSpec(pri *PrioritySpec, multiTurn bool) *WorkloadSpec {
+	client := ClientSpec{
+		ID: "c", TenantID: "t", SLOClass: "standard", RateFraction: 1,
+		Arrival:    ArrivalSpec{Process: "poisson"},
+		InputDist:  DistSpec{Type: "exponential", Params: map[string]float64{"mean": 64}},
+		OutputDist: DistSpec{Type: "constant", Params: map[string]float64{"value": 8}},
+		Priority:   pri,
+	}
+	if multiTurn {
+		closed := false
+		client.ClosedLoop = &closed
+		client.Reasoning = &ReasoningSpec{MultiTurn: &MultiTurnSpec{MaxRounds: 3, ThinkTimeUs: 1000, ContextGrowth: "accumulate"}}
+	}
+	return &WorkloadSpec{Version: "2", Seed: 7, AggregateRate: 50, Clients: []ClientSpec{client}}
+}
+
+// TestGenerateRequests_PriorityWeights_SamplesPerRequestAndEagerMatchesLazy
+// verifies that a weighted priority spec draws every declared priority in
+// roughly its weight's share, and that the lazy generator assigns the same
+// priority to every request as the eager one.
+func TestGenerateRequests_PriorityWeights_SamplesPerRequestAndEagerMatchesLazy(t *testing.T) {
+	const n = 400
+	spec := priorityTestSpec(&PrioritySpec{Weights: map[int]float64{-1: 1, 10: 3}}, false)
+	eager, err := GenerateRequests(spec, math.MaxInt64, n)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	counts := make(map[int]int)
+	for _, r := range eager {
+		counts[r.ExplicitPriority]++
+	}
+	if len(counts) != 2 || counts[-1]+counts[10] != n {
+		t.Fatalf("priorities drawn = %v, want only -1 and 10", counts)
+	}
+	if frac := float64(counts[10]) / n; frac < 0.65 || frac > 0.85 {
+		t.Errorf("share of priority 10 = %.2f, want ~0.75", frac)
+	}
+
+	src, _, _, err := GenerateWorkloadLazy(priorityTestSpec(spec.Clients[0].Priority, false), math.MaxInt64, n)
+	if err != nil {
+		t.Fatalf("GenerateWorkloadLazy: %v", err)
+	}
+	assertRequestStreamsEqual(t, eager, drainLazy(t, src))
+}
+
+// TestGenerateRequests_PriorityWeights_SessionRoundsSharePriority verifies
+// that all rounds of a multi-turn session carry the priority drawn for it.
+func TestGenerateRequests_PriorityWeights_SessionRoundsSharePriority(t *testing.T) {
+	spec := priorityTestSpec(&PrioritySpec{Weights: map[int]float64{1: 1, 2: 1}}, true)
+	reqs, err := GenerateRequests(spec, math.MaxInt64, 300)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	bySession := make(map[string]int)
+	distinct := make(map[int]bool)
+	for _, r := range reqs {
+		if pri, ok := bySession[r.SessionID]; ok && pri != r.ExplicitPriority {
+			t.Fatalf("session %s: round %d priority %d, earlier round %d", r.SessionID, r.RoundIndex, r.ExplicitPriority, pri)
+		}
+		bySession[r.SessionID] = r.ExplicitPriority
+		distinct[r.ExplicitPriority] = true
+	}
+	if len(distinct) != 2 {
+		t.Errorf("sessions drew priorities %v, want both 1 and 2", distinct)
+	}
+}
+
+func TestWorkloadSpec_Validate_InvalidPriority_ReturnsError(t *testing.T) {
+	for name, pri := range map[string]*PrioritySpec{
+		"value and weights": {Value: 3, Weights: map[int]float64{1: 1}},
+		"negative weight":   {Weights: map[int]float64{1: -1}},
+		"NaN weight":        {Weights: map[int]float64{1: math.NaN()}},
+		"all zero":          {Weights: map[int]float64{1: 0, 2: 0}},
+	} {
+		if err := priorityTestSpec(pri, false).Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	spec := priorityTestSpec(&PrioritySpec{Value: -4}, false)
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("constant priority: unexpected error %v", err)
+	}
+	reqs, err := GenerateRequests(spec, math.MaxInt64, 10)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+	for _, r := range reqs {
+		if r.ExplicitPriority != -4 {
+			t.Fatalf("request %s: ExplicitPriority = %d, want -4", r.ID, r.ExplicitPriority)
+		}
+	}
+}
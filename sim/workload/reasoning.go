@@ -44,6 +44,11 @@ func GenerateReasoningRequests(
 		}
 	}
 
+	thinkTimeSampler, err := mt.thinkTimeSampler()
+	if err != nil {
+		return nil, err
+	}
+
 	sessionID := fmt.Sprintf("sess_%d", rng.Int63())
 	var requests []*sim.Request
 	currentTime := startTime
@@ -141,9 +146,26 @@ func GenerateReasoningRequests(
 		}
 
 		// Next round arrives after think time
-		currentTime += mt.ThinkTimeUs
+		if thinkTimeSampler != nil {
+			currentTime += int64(thinkTimeSampler.Sample(rng))
+		} else {
+			currentTime += mt.ThinkTimeUs
+		}
 		// Add estimated completion time (simple heuristic: 1µs per output token)
 		currentTime += int64(outputLen)
 	}
 	return requests, nil
 }
+
+// thinkTimeSampler returns the sampler for ThinkTimeDist, or nil when unset
+// (rounds are then spaced by the constant ThinkTimeUs).
+func (mt *MultiTurnSpec) thinkTimeSampler() (LengthSampler, error) {
+	if mt.ThinkTimeDist == "" {
+		return nil, nil
+	}
+	s, err := ParseThinkTimeDist(mt.ThinkTimeDist)
+	if err != nil {
+		return nil, fmt.Errorf("multi_turn think_time_dist: %w", err)
+	}
+	return s, nil
+}
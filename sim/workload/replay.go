@@ -3,7 +3,6 @@ package workload
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"sort"
 
 	"github.com/inference-sim/inference-sim/sim"
@@ -48,15 +47,132 @@ func injectionTime(rec TraceRecord) int64 {
 	return rec.ArrivalTimeUs
 }
 
+// ScaleTraceV2ArrivalRate rescales the offered load of a trace in place by
+// dividing every inter-arrival gap by rateScale (2.0 = double the rate, 0.5 =
+// half). Arrival and send times go through the same mapping relative to the
+// earliest arrival, which stays fixed, so injection gaps scale whether a record
+// is injected at its send time or its arrival. Each absolute deadline moves
+// with its arrival and each recorded chunk time with its injection time, so
+// client timeouts and recorded TTFT/E2E are unchanged. Token counts are
+// preserved. Returns an error if rateScale is not a finite value > 0.
+func ScaleTraceV2ArrivalRate(trace *TraceV2, rateScale float64) error {
+	if rateScale <= 0 || math.IsNaN(rateScale) || math.IsInf(rateScale, 0) {
+		return fmt.Errorf("rate scale must be a finite value > 0, got %v", rateScale)
+	}
+	if trace == nil || len(trace.Records) == 0 {
+		return nil
+	}
+	origin := trace.Records[0].ArrivalTimeUs
+	for _, rec := range trace.Records {
+		origin = min(origin, rec.ArrivalTimeUs)
+	}
+	scale := func(t int64) int64 {
+		return origin + int64(math.Round(float64(t-origin)/rateScale))
+	}
+	for i := range trace.Records {
+		rec := &trace.Records[i]
+		arrival := scale(rec.ArrivalTimeUs)
+		if rec.DeadlineUs > 0 {
+			rec.DeadlineUs = arrival + (rec.DeadlineUs - rec.ArrivalTimeUs)
+		}
+		injected := injectionTime(*rec)
+		shift := scale(injected) - injected
+		if rec.SendTimeUs > 0 {
+			rec.SendTimeUs += shift
+		}
+		if rec.FirstChunkTimeUs > 0 {
+			rec.FirstChunkTimeUs += shift
+		}
+		if rec.LastChunkTimeUs > 0 {
+			rec.LastChunkTimeUs += shift
+		}
+		rec.ArrivalTimeUs = arrival
+	}
+	return nil
+}
+
+// BurstClientID is the client_id of records added by OverlayTraceV2Burst.
+const BurstClientID = "burst"
+
+// TraceBurst describes a synthetic burst overlaid on a replayed trace:
+// Requests extra requests arriving evenly spaced over [StartUs, StartUs+WindowUs).
+type TraceBurst struct {
+	Requests int
+	StartUs  int64
+	WindowUs int64
+}
+
+// OverlayTraceV2Burst adds burst.Requests synthetic records to the trace in
+// place and re-sorts the records by injection time (stable, so recorded
+// arrivals keep their order on ties). Each burst record copies the token
+// counts and request metadata of a trace record drawn with seed from the
+// randSource generator (sim.NewRand), is single-turn, carries client_id
+// BurstClientID and a request_id above every recorded one, and keeps its
+// template's client timeout; response timings are left unset. A zero-request burst is a no-op. Returns an error for
+// negative fields or a burst on an empty trace.
+func OverlayTraceV2Burst(trace *TraceV2, burst TraceBurst, seed int64, randSource string) error {
+	if burst.Requests < 0 || burst.StartUs < 0 || burst.WindowUs < 0 {
+		return fmt.Errorf("burst requests, start and window must be >= 0, got %d, %d, %d",
+			burst.Requests, burst.StartUs, burst.WindowUs)
+	}
+	if burst.Requests == 0 {
+		return nil
+	}
+	if trace == nil || len(trace.Records) == 0 {
+		return fmt.Errorf("burst needs a non-empty trace to draw request shapes from")
+	}
+	rng := newRandFromSeed(randSource, seed)
+	nextID := trace.Records[0].RequestID
+	for _, rec := range trace.Records {
+		nextID = max(nextID, rec.RequestID)
+	}
+	n := int64(burst.Requests)
+	for i := int64(0); i < n; i++ {
+		tmpl := trace.Records[rng.Intn(len(trace.Records))]
+		arrival := burst.StartUs + i*burst.WindowUs/n
+		rec := TraceRecord{
+			RequestID:         nextID + 1 + int(i),
+			ClientID:          BurstClientID,
+			TenantID:          tmpl.TenantID,
+			SLOClass:          tmpl.SLOClass,
+			VLLMPriority:      tmpl.VLLMPriority,
+			PrefixGroup:       tmpl.PrefixGroup,
+			PrefixLength:      tmpl.PrefixLength,
+			Streaming:         tmpl.Streaming,
+			InputTokens:       tmpl.InputTokens,
+			OutputTokens:      tmpl.OutputTokens,
+			TextTokens:        tmpl.TextTokens,
+			ImageTokens:       tmpl.ImageTokens,
+			AudioTokens:       tmpl.AudioTokens,
+			VideoTokens:       tmpl.VideoTokens,
+			ReasonRatio:       tmpl.ReasonRatio,
+			Model:             tmpl.Model,
+			SLOTargetUs:       tmpl.SLOTargetUs,
+			ServerInputTokens: tmpl.ServerInputTokens,
+			ArrivalTimeUs:     arrival,
+			Adapter:           tmpl.Adapter,
+		}
+		if tmpl.DeadlineUs > 0 {
+			rec.DeadlineUs = arrival + (tmpl.DeadlineUs - tmpl.ArrivalTimeUs)
+		}
+		trace.Records = append(trace.Records, rec)
+	}
+	sort.SliceStable(trace.Records, func(i, j int) bool {
+		return injectionTime(trace.Records[i]) < injectionTime(trace.Records[j])
+	})
+	return nil
+}
+
 // LoadTraceV2Requests converts trace v2 records into sim.Request objects
 // with synthetic token IDs for simulation replay. Requests in the same
-// prefix_group share identical prefix token sequences.
-func LoadTraceV2Requests(trace *TraceV2, seed int64) ([]*sim.Request, error) {
+// prefix_group share identical prefix token sequences. Token IDs are drawn
+// from the named random source (sim.NewRand; "" = stdlib) seeded with seed.
+func LoadTraceV2Requests(trace *TraceV2, seed int64, randSource string) ([]*sim.Request, error) {
 	if trace == nil || len(trace.Records) == 0 {
 		return nil, fmt.Errorf("empty trace")
 	}
 
-	rng := rand.New(rand.NewSource(seed))
+	rng := newRandFromSeed(randSource, seed)
 
 	// Generate shared prefix tokens per prefix group using trace-specified length
 	prefixTokens := make(map[string][]sim.TokenID)
@@ -129,7 +245,10 @@ func LoadTraceV2Requests(trace *TraceV2, seed int64) ([]*sim.Request, error) {
 //	an observe-generated trace with accurate inter-round spacing.
 //
 // horizon <= 0: defaults to math.MaxInt64.
-func LoadTraceV2SessionBlueprints(trace *TraceV2, seed int64, thinkTimeSampler LengthSampler, horizon int64) ([]*sim.Request, []SessionBlueprint, error) {
+//
+// Token IDs are drawn from the named random source (sim.NewRand; "" =
+// stdlib), as in LoadTraceV2Requests.
+func LoadTraceV2SessionBlueprints(trace *TraceV2, seed int64, randSource string, thinkTimeSampler LengthSampler, horizon int64) ([]*sim.Request, []SessionBlueprint, error) {
 	if trace == nil || len(trace.Records) == 0 {
 		return nil, nil, fmt.Errorf("empty trace")
 	}
@@ -137,7 +256,7 @@ func LoadTraceV2SessionBlueprints(trace *TraceV2, seed int64, thinkTimeSampler L
 		horizon = math.MaxInt64
 	}
 
-	rng := rand.New(rand.NewSource(seed))
+	rng := newRandFromSeed(randSource, seed)
 
 	// Generate shared prefix tokens per prefix group (same as LoadTraceV2Requests)
 	prefixTokens := make(map[string][]sim.TokenID)
@@ -221,7 +340,7 @@ func LoadTraceV2SessionBlueprints(trace *TraceV2, seed int64, thinkTimeSampler L
 		}
 
 		// Per-session RNG for deterministic token ID generation (INV-6)
-		sessionRNG := rand.New(rand.NewSource(rng.Int63()))
+		sessionRNG := newRandFromSeed(randSource, rng.Int63())
 
 		// Build round-0 request, preferring server-reported count when available.
 		r0 := rounds[0]
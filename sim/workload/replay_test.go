@@ -1,8 +1,12 @@
 package workload
 
 import (
+	"math"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/inference-sim/inference-sim/sim"
 )
 
 func TestLoadTraceV2Requests_CorrectTokenCounts(t *testing.T) {
@@ -27,7 +31,7 @@ func TestLoadTraceV2Requests_CorrectTokenCounts(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,7 +84,7 @@ func TestLoadTraceV2Requests_PrefixGroup_SharedTokens(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -112,6 +116,47 @@ func TestLoadTraceV2Requests_PrefixGroup_SharedTokens(t *testing.T) {
 
 // --- LoadTraceV2SessionBlueprints tests (BC-5, BC-6) ---
 
+func TestLoadTraceV2_RandSource_SelectsTokenStream(t *testing.T) {
+	trace := &TraceV2{
+		Records: []TraceRecord{
+			{RequestID: 1, InputTokens: 64, OutputTokens: 8, ArrivalTimeUs: 0},
+			{RequestID: 2, SessionID: "A", RoundIndex: 0, InputTokens: 64, OutputTokens: 8, ArrivalTimeUs: 100},
+		},
+	}
+	tokens := func(source string) (flat, session []sim.TokenID) {
+		reqs, err := LoadTraceV2Requests(trace, 42, source)
+		if err != nil {
+			t.Fatalf("LoadTraceV2Requests(%q): %v", source, err)
+		}
+		r0, _, err := LoadTraceV2SessionBlueprints(trace, 42, source, nil, 0)
+		if err != nil {
+			t.Fatalf("LoadTraceV2SessionBlueprints(%q): %v", source, err)
+		}
+		for _, r := range r0 {
+			if r.SessionID == "A" {
+				session = r.InputTokens
+			}
+		}
+		return reqs[0].InputTokens, session
+	}
+
+	// The empty name is the stdlib source.
+	stdFlat, stdSession := tokens("")
+	gotFlat, gotSession := tokens(sim.RandSourceStdlib)
+	if !slices.Equal(stdFlat, gotFlat) || !slices.Equal(stdSession, gotSession) {
+		t.Error(`"" and stdlib sources drew different tokens`)
+	}
+	// xoshiro draws its own, reproducible stream, per-session RNGs included.
+	xFlat, xSession := tokens(sim.RandSourceXoshiro)
+	if slices.Equal(xFlat, stdFlat) || slices.Equal(xSession, stdSession) {
+		t.Error("xoshiro256ss drew the stdlib token stream")
+	}
+	againFlat, againSession := tokens(sim.RandSourceXoshiro)
+	if !slices.Equal(xFlat, againFlat) || !slices.Equal(xSession, againSession) {
+		t.Error("xoshiro256ss tokens differ across runs with the same seed")
+	}
+}
+
 func TestLoadTraceV2SessionBlueprints_GroupsBySession(t *testing.T) {
 	trace := &TraceV2{
 		Records: []TraceRecord{
@@ -121,7 +166,7 @@ func TestLoadTraceV2SessionBlueprints_GroupsBySession(t *testing.T) {
 		},
 	}
 
-	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -164,7 +209,7 @@ func TestLoadTraceV2SessionBlueprints_NonSessionPassThrough(t *testing.T) {
 		},
 	}
 
-	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -187,7 +232,7 @@ func TestLoadTraceV2SessionBlueprints_ThinkTimeFromTrace(t *testing.T) {
 		},
 	}
 
-	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -211,7 +256,7 @@ func TestLoadTraceV2SessionBlueprints_SingleRoundSession(t *testing.T) {
 		},
 	}
 
-	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -239,7 +284,7 @@ func TestLoadTraceV2SessionBlueprints_OverrideThinkTime(t *testing.T) {
 	}
 
 	sampler := &ConstantSampler{value: 500_000}
-	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, sampler, 0)
+	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", sampler, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -265,7 +310,7 @@ func TestLoadTraceV2SessionBlueprints_NonMonotoneGapClamped(t *testing.T) {
 		},
 	}
 
-	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -290,7 +335,7 @@ func TestLoadTraceV2SessionBlueprints_NonConsecutiveRoundIndex_Error(t *testing.
 		},
 	}
 
-	_, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	_, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err == nil {
 		t.Fatal("expected error for non-consecutive round indices, got nil")
 	}
@@ -341,7 +386,7 @@ func TestLoadTraceV2Requests_ServerInputTokens_UsedWhenPresent(t *testing.T) {
 				OutputTokens: 64, ArrivalTimeUs: 0, Status: "ok"},
 		},
 	}
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -359,7 +404,7 @@ func TestLoadTraceV2Requests_ServerInputTokens_Zero_FallsBackToInputTokens(t *te
 				OutputTokens: 32, ArrivalTimeUs: 0, Status: "ok"},
 		},
 	}
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -381,7 +426,7 @@ func TestLoadTraceV2Requests_ServerInputTokens_PrefixGroup_FallsBackToInputToken
 				OutputTokens: 32, ArrivalTimeUs: 0, Status: "ok"},
 		},
 	}
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -406,7 +451,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_Round0(t *testing.T) {
 				OutputTokens: 32, ArrivalTimeUs: 5000},
 		},
 	}
-	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -431,7 +476,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_Sampler(t *testing.T) {
 				InputTokens: 128, ServerInputTokens: 0, OutputTokens: 16, ArrivalTimeUs: 10000},
 		},
 	}
-	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -457,7 +502,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_NonSessionRecord(t *test
 				OutputTokens: 64, ArrivalTimeUs: 0},
 		},
 	}
-	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -487,7 +532,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_Round0_PrefixGroup_Falls
 				ServerInputTokens: 196, OutputTokens: 16, ArrivalTimeUs: 5000},
 		},
 	}
-	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -514,7 +559,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_Sampler_PrefixGroup_Fall
 				OutputTokens: 16, ArrivalTimeUs: 5000},
 		},
 	}
-	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	_, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -535,7 +580,7 @@ func TestLoadTraceV2SessionBlueprints_ServerInputTokens_NonSession_PrefixGroup_F
 				ServerInputTokens: 246, OutputTokens: 32, ArrivalTimeUs: 0},
 		},
 	}
-	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -588,7 +633,7 @@ func TestLoadTraceV2Requests_ModelAndDeadline(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -674,7 +719,7 @@ func TestLoadTraceV2Requests_ConcurrencyModeUseSendTime(t *testing.T) {
 					},
 				},
 			}
-			reqs, err := LoadTraceV2Requests(trace, 42)
+			reqs, err := LoadTraceV2Requests(trace, 42, "")
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -710,7 +755,7 @@ func TestLoadTraceV2SessionBlueprints_ConcurrencyModeInjection(t *testing.T) {
 		},
 	}
 
-	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -782,7 +827,7 @@ func TestLoadTraceV2SessionBlueprints_NegativeSendTime(t *testing.T) {
 		},
 	}
 
-	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, _, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -807,3 +852,187 @@ func TestLoadTraceV2SessionBlueprints_NegativeSendTime(t *testing.T) {
 		t.Errorf("non-session: ArrivalTime = %d, want 20000 (negative send_time must fall back)", nonSessionArrival)
 	}
 }
+
+func TestScaleTraceV2ArrivalRate_HalfRate_DoublesGaps(t *testing.T) {
+	// GIVEN a trace fixture with uneven gaps, a send delay, and a client timeout
+	header := &TraceHeader{Version: 2, TimeUnit: "microseconds", Mode: "generated"}
+	records := []TraceRecord{
+		{RequestID: 0, InputTokens: 100, OutputTokens: 50, ArrivalTimeUs: 1000, Status: "ok"},
+		{RequestID: 1, InputTokens: 200, OutputTokens: 75, ArrivalTimeUs: 4000, SendTimeUs: 4500,
+			FirstChunkTimeUs: 4900, LastChunkTimeUs: 6000, Status: "ok"},
+		{RequestID: 2, InputTokens: 300, OutputTokens: 25, ArrivalTimeUs: 4250, DeadlineUs: 9250, Status: "ok"},
+		{RequestID: 3, InputTokens: 50, OutputTokens: 10, ArrivalTimeUs: 11000, Status: "ok"},
+	}
+	dir := t.TempDir()
+	headerPath := filepath.Join(dir, "header.yaml")
+	dataPath := filepath.Join(dir, "data.csv")
+	if err := ExportTraceV2(header, records, headerPath, dataPath); err != nil {
+		t.Fatal(err)
+	}
+	trace, err := LoadTraceV2(headerPath, dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := LoadTraceV2Requests(trace, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN the trace is replayed at half rate
+	if err := ScaleTraceV2ArrivalRate(trace, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	scaled, err := LoadTraceV2Requests(trace, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN every inter-arrival gap is exactly doubled from the first arrival
+	if scaled[0].ArrivalTime != original[0].ArrivalTime {
+		t.Errorf("first arrival = %d, want %d (origin fixed)", scaled[0].ArrivalTime, original[0].ArrivalTime)
+	}
+	for i := 1; i < len(scaled); i++ {
+		gotGap := scaled[i].ArrivalTime - scaled[i-1].ArrivalTime
+		wantGap := 2 * (original[i].ArrivalTime - original[i-1].ArrivalTime)
+		if gotGap != wantGap {
+			t.Errorf("gap %d→%d = %d, want %d", i-1, i, gotGap, wantGap)
+		}
+	}
+	// AND token counts and the client timeout duration are preserved
+	for i := range scaled {
+		if len(scaled[i].InputTokens) != len(original[i].InputTokens) || len(scaled[i].OutputTokens) != len(original[i].OutputTokens) {
+			t.Errorf("request %d tokens changed: %d/%d, want %d/%d", i,
+				len(scaled[i].InputTokens), len(scaled[i].OutputTokens), len(original[i].InputTokens), len(original[i].OutputTokens))
+		}
+	}
+	if got := scaled[2].Deadline - scaled[2].ArrivalTime; got != 5000 {
+		t.Errorf("request 2 timeout = %d µs after arrival, want 5000", got)
+	}
+	// AND the send time is scaled like the arrival, keeping recorded TTFT/E2E
+	rec := trace.Records[1]
+	if rec.SendTimeUs != 8000 || rec.ArrivalTimeUs != 7000 {
+		t.Errorf("request 1 send/arrival = %d/%d, want 8000/7000", rec.SendTimeUs, rec.ArrivalTimeUs)
+	}
+	if ttft, e2e := rec.FirstChunkTimeUs-rec.SendTimeUs, rec.LastChunkTimeUs-rec.SendTimeUs; ttft != 400 || e2e != 1500 {
+		t.Errorf("request 1 recorded TTFT/E2E = %d/%d µs, want 400/1500", ttft, e2e)
+	}
+}
+
+func TestScaleTraceV2ArrivalRate_InvalidScale_Error(t *testing.T) {
+	trace := &TraceV2{Records: []TraceRecord{{RequestID: 0, ArrivalTimeUs: 10}}}
+	for _, scale := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if err := ScaleTraceV2ArrivalRate(trace, scale); err == nil {
+			t.Errorf("scale %v: expected error", scale)
+		}
+	}
+}
+
+func TestOverlayTraceV2Burst_AddsRequestsInArrivalOrder(t *testing.T) {
+	// GIVEN a trace fixture and a 50-request burst over [5000, 15000) µs
+	header := &TraceHeader{Version: 2, TimeUnit: "microseconds", Mode: "generated"}
+	records := []TraceRecord{
+		{RequestID: 0, InputTokens: 100, OutputTokens: 50, ArrivalTimeUs: 1000, Status: "ok"},
+		{RequestID: 1, InputTokens: 200, OutputTokens: 75, ArrivalTimeUs: 4000, SendTimeUs: 4500,
+			FirstChunkTimeUs: 4900, LastChunkTimeUs: 6000, Status: "ok"},
+		{RequestID: 2, InputTokens: 300, OutputTokens: 25, ArrivalTimeUs: 8000, DeadlineUs: 13000, Status: "ok"},
+		{RequestID: 3, InputTokens: 50, OutputTokens: 10, ArrivalTimeUs: 20000, Status: "ok"},
+	}
+	dir := t.TempDir()
+	headerPath := filepath.Join(dir, "header.yaml")
+	dataPath := filepath.Join(dir, "data.csv")
+	if err := ExportTraceV2(header, records, headerPath, dataPath); err != nil {
+		t.Fatal(err)
+	}
+	trace, err := LoadTraceV2(headerPath, dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := LoadTraceV2Requests(trace, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN the burst is overlaid and the trace replayed
+	if err := OverlayTraceV2Burst(trace, TraceBurst{Requests: 50, StartUs: 5000, WindowUs: 10000}, 42, ""); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := LoadTraceV2Requests(trace, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// THEN the request count grows by exactly 50
+	if got, want := len(replayed), len(original)+50; got != want {
+		t.Fatalf("replayed %d requests, want %d", got, want)
+	}
+	// AND arrivals remain sorted
+	for i := 1; i < len(replayed); i++ {
+		if replayed[i].ArrivalTime < replayed[i-1].ArrivalTime {
+			t.Fatalf("arrival %d (%d) precedes arrival %d (%d)", i, replayed[i].ArrivalTime, i-1, replayed[i-1].ArrivalTime)
+		}
+	}
+	// AND burst requests fall in the window with unique IDs, keeping their template's timeout
+	ids := make(map[string]bool, len(replayed))
+	burst := 0
+	for _, req := range replayed {
+		if ids[req.ID] {
+			t.Errorf("duplicate request ID %s", req.ID)
+		}
+		ids[req.ID] = true
+		if req.ClientID != BurstClientID {
+			continue
+		}
+		burst++
+		if req.ArrivalTime < 5000 || req.ArrivalTime >= 15000 {
+			t.Errorf("burst request %s arrives at %d, outside [5000, 15000)", req.ID, req.ArrivalTime)
+		}
+		if req.Deadline != 0 && req.Deadline-req.ArrivalTime != 5000 {
+			t.Errorf("burst request %s timeout = %d µs, want 5000 (template's)", req.ID, req.Deadline-req.ArrivalTime)
+		}
+	}
+	if burst != 50 {
+		t.Errorf("%d burst requests, want 50", burst)
+	}
+}
+
+func TestOverlayTraceV2Burst_RandSource_SelectsTemplateDraws(t *testing.T) {
+	for _, source := range []string{sim.RandSourceStdlib, sim.RandSourceXoshiro} {
+		// GIVEN a trace of 8 records with distinct input lengths
+		trace := &TraceV2{}
+		for i := range 8 {
+			trace.Records = append(trace.Records, TraceRecord{RequestID: i, InputTokens: 10 * (i + 1), ArrivalTimeUs: int64(i)})
+		}
+		templates := slices.Clone(trace.Records)
+
+		// WHEN a burst is overlaid after the recorded arrivals
+		if err := OverlayTraceV2Burst(trace, TraceBurst{Requests: 20, StartUs: 100, WindowUs: 2000}, 7, source); err != nil {
+			t.Fatal(err)
+		}
+
+		// THEN burst templates are drawn from the selected generator (each
+		// draw also sees the burst records added before it)
+		rng := sim.NewRand(source, 7)
+		for i, rec := range trace.Records[8:] {
+			want := templates[rng.Intn(len(templates))].InputTokens
+			if rec.InputTokens != want {
+				t.Fatalf("%s: burst record %d input tokens = %d, want %d", source, i, rec.InputTokens, want)
+			}
+			templates = append(templates, rec)
+		}
+	}
+}
+
+func TestOverlayTraceV2Burst_InvalidOrEmpty(t *testing.T) {
+	trace := &TraceV2{Records: []TraceRecord{{RequestID: 0, ArrivalTimeUs: 10}}}
+	for _, b := range []TraceBurst{{Requests: -1}, {Requests: 1, StartUs: -1}, {Requests: 1, WindowUs: -1}} {
+		if err := OverlayTraceV2Burst(trace, b, 1, ""); err == nil {
+			t.Errorf("burst %+v: expected error", b)
+		}
+	}
+	if err := OverlayTraceV2Burst(&TraceV2{}, TraceBurst{Requests: 1}, 1, ""); err == nil {
+		t.Error("burst on empty trace: expected error")
+	}
+	if err := OverlayTraceV2Burst(trace, TraceBurst{}, 1, ""); err != nil || len(trace.Records) != 1 {
+		t.Errorf("zero burst: err=%v, %d records, want no-op", err, len(trace.Records))
+	}
+}
@@ -284,7 +284,7 @@ func loadServeGenData(spec *WorkloadSpec) error {
 	}
 
 	// BC-7: Deterministic RNG from spec.Seed
-	rng := rand.New(rand.NewSource(spec.Seed))
+	rng := newRandFromSeed(spec.RandSource, spec.Seed)
 
 	// Define three time periods (ServeGen Day 1 spans 0-86400s; each period is 30 minutes)
 	// startUs values create gaps between periods for drain timeout
@@ -139,6 +139,8 @@ func (sm *SessionManager) OnComplete(req *sim.Request, tick int64) []*sim.Reques
 	//   3. EnqueueRequest guard drops (req.State == StateQueued) — handled here
 	//   4. detectDecodeCompletions (cluster.go) — req.State set to StateCompleted
 	//      before invocation; not a drop path (issue #884)
+	//   5. cluster admission drop of an input too large for any instance's KV
+	//      cache (sim/cluster/unservable.go) — a copy with req.State = StateQueued
 	// A legitimately queued request never triggers this callback.
 	// If a future code path invokes OnRequestDone for a queued request that is
 	// NOT dropped, this detection would incorrectly cancel the session. Review
@@ -288,6 +290,10 @@ func (sm *SessionManager) OnComplete(req *sim.Request, tick int64) []*sim.Reques
 		ClientID:     bp.ClientID,
 		SessionID:    bp.SessionID,
 		RoundIndex:   sess.currentRound,
+		// A session keeps the priority drawn for its first round.
+		ExplicitPriority: req.ExplicitPriority,
+		IngressPoint:     req.IngressPoint,
+		ParallelSamples:  req.ParallelSamples,
 	}
 	if sm.budgetEnabled {
 		sm.followUpCount++
@@ -9,6 +9,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
+
+	"github.com/inference-sim/inference-sim/sim"
 )
 
 // v1ToV2SLOClasses maps deprecated v1 SLO class names to v2 equivalents.
@@ -40,8 +42,12 @@ func UpgradeV1ToV2(spec *WorkloadSpec) {
 // WorkloadSpec is the top-level workload configuration.
 // Loaded from YAML via LoadWorkloadSpec(path).
 type WorkloadSpec struct {
-	Version       string             `yaml:"version"`
-	Seed          int64              `yaml:"seed"`
+	Version string `yaml:"version"`
+	Seed    int64  `yaml:"seed"`
+	// RandSource names the bit generator for all generation RNGs (see
+	// sim.NewRandSource): "" or "stdlib" (default, byte-identical to earlier
+	// builds) or "xoshiro256ss" (stable across Go versions and platforms).
+	RandSource    string             `yaml:"rand_source,omitempty"`
 	Category      string             `yaml:"category"`
 	Clients       []ClientSpec       `yaml:"clients"`
 	Cohorts       []CohortSpec       `yaml:"cohorts,omitempty"`
@@ -63,29 +69,33 @@ type WorkloadSpec struct {
 // ExpandCohorts) and Lifecycle (synthesized from Diurnal/Spike/Drain — exposing
 // Lifecycle directly would create two conflicting paths to the same effect).
 type CohortSpec struct {
-	ID            string          `yaml:"id"`
-	Population    int             `yaml:"population"`
-	TenantID      string          `yaml:"tenant_id,omitempty"`
-	SLOClass      string          `yaml:"slo_class,omitempty"`
-	Model         string          `yaml:"model,omitempty"`
-	Adapter       string          `yaml:"adapter,omitempty"` // LoRA adapter id (registry key; #1464). omitempty => base-model-only (no-op).
-	Arrival       ArrivalSpec     `yaml:"arrival"`
-	InputDist     DistSpec        `yaml:"input_distribution"`
-	OutputDist    DistSpec        `yaml:"output_distribution"`
-	PrefixGroup   string          `yaml:"prefix_group,omitempty"`
-	PrefixSharing string          `yaml:"prefix_sharing,omitempty"` // "shared" (default) or "per_member"
-	Streaming     bool            `yaml:"streaming,omitempty"`
-	RateFraction  float64         `yaml:"rate_fraction"`
-	Diurnal       *DiurnalSpec    `yaml:"diurnal,omitempty"`
-	Spike         *SpikeSpec      `yaml:"spike,omitempty"`
-	Drain         *DrainSpec      `yaml:"drain,omitempty"`
-	PrefixLength  int             `yaml:"prefix_length,omitempty"`
-	Reasoning     *ReasoningSpec  `yaml:"reasoning,omitempty"`
-	ClosedLoop    *bool           `yaml:"closed_loop,omitempty"`
-	Timeout       *int64          `yaml:"timeout,omitempty"`
-	SLOTargetUs   *int64          `yaml:"slo_target_us,omitempty"` // Per-request SLO TTFT target in µs. nil/0 = no target. (R9: pointer)
-	Network       *NetworkSpec    `yaml:"network,omitempty"`
-	Multimodal    *MultimodalSpec `yaml:"multimodal,omitempty"`
+	ID               string          `yaml:"id"`
+	Population       int             `yaml:"population"`
+	TenantID         string          `yaml:"tenant_id,omitempty"`
+	SLOClass         string          `yaml:"slo_class,omitempty"`
+	Model            string          `yaml:"model,omitempty"`
+	Adapter          string          `yaml:"adapter,omitempty"`          // LoRA adapter id (registry key; #1464). omitempty => base-model-only (no-op).
+	IngressPoint     string          `yaml:"ingress_point,omitempty"`    // cluster ingress point tag (Request.IngressPoint); "" = untagged
+	ParallelSamples  int             `yaml:"parallel_samples,omitempty"` // completions per request (Request.ParallelSamples); 0 or 1 = one
+	Arrival          ArrivalSpec     `yaml:"arrival"`
+	InputDist        DistSpec        `yaml:"input_distribution"`
+	OutputDist       DistSpec        `yaml:"output_distribution"`
+	PrefixGroup      string          `yaml:"prefix_group,omitempty"`
+	PrefixSharing    string          `yaml:"prefix_sharing,omitempty"` // "shared" (default) or "per_member"
+	Streaming        bool            `yaml:"streaming,omitempty"`
+	RateFraction     float64         `yaml:"rate_fraction"`
+	Diurnal          *DiurnalSpec    `yaml:"diurnal,omitempty"`
+	Spike            *SpikeSpec      `yaml:"spike,omitempty"`
+	Drain            *DrainSpec      `yaml:"drain,omitempty"`
+	PrefixLength     int             `yaml:"prefix_length,omitempty"`
+	PrefixLengthDist *DistSpec       `yaml:"prefix_length_distribution,omitempty"`
+	Reasoning        *ReasoningSpec  `yaml:"reasoning,omitempty"`
+	ClosedLoop       *bool           `yaml:"closed_loop,omitempty"`
+	Timeout          *int64          `yaml:"timeout,omitempty"`
+	SLOTargetUs      *int64          `yaml:"slo_target_us,omitempty"` // Per-request SLO TTFT target in µs. nil/0 = no target. (R9: pointer)
+	Priority         *PrioritySpec   `yaml:"priority,omitempty"`      // Explicit numeric priority (value or weights). nil = 0.
+	Network          *NetworkSpec    `yaml:"network,omitempty"`
+	Multimodal       *MultimodalSpec `yaml:"multimodal,omitempty"`
 }
 
 // DiurnalSpec configures sinusoidal rate modulation over a 24-hour cycle.
@@ -111,27 +121,36 @@ type DrainSpec struct {
 
 // ClientSpec defines a single client's workload behavior.
 type ClientSpec struct {
-	ID           string          `yaml:"id"`
-	TenantID     string          `yaml:"tenant_id"`
-	SLOClass     string          `yaml:"slo_class"`
-	Model        string          `yaml:"model,omitempty"`
-	Adapter      string          `yaml:"adapter,omitempty"` // LoRA adapter id (registry key; #1464). omitempty => base-model-only (no-op).
-	RateFraction float64         `yaml:"rate_fraction"`
-	Concurrency  int             `yaml:"concurrency,omitempty"`
-	ThinkTimeUs  int64           `yaml:"think_time_us,omitempty"`
-	Arrival      ArrivalSpec     `yaml:"arrival"`
-	InputDist    DistSpec        `yaml:"input_distribution"`
-	OutputDist   DistSpec        `yaml:"output_distribution"`
-	PrefixGroup  string          `yaml:"prefix_group,omitempty"`
-	PrefixLength int             `yaml:"prefix_length,omitempty"` // shared prefix token count (default 50)
-	Streaming    bool            `yaml:"streaming"`
-	Network      *NetworkSpec    `yaml:"network,omitempty"`
-	Lifecycle    *LifecycleSpec  `yaml:"lifecycle,omitempty"`
-	Multimodal   *MultimodalSpec `yaml:"multimodal,omitempty"`
-	Reasoning    *ReasoningSpec  `yaml:"reasoning,omitempty"`
-	Timeout      *int64          `yaml:"timeout,omitempty"`       // Per-request timeout in µs. nil = default (300s). 0 = no timeout. (R9: pointer for zero-value)
-	SLOTargetUs  *int64          `yaml:"slo_target_us,omitempty"` // Per-request SLO TTFT target in µs. nil/0 = no target. (R9: pointer)
-	ClosedLoop   *bool           `yaml:"closed_loop,omitempty"`   // nil = default (true for reasoning/multi-turn). false = open-loop (all rounds pre-generated).
+	ID           string `yaml:"id"`
+	TenantID     string `yaml:"tenant_id"`
+	SLOClass     string `yaml:"slo_class"`
+	Model        string `yaml:"model,omitempty"`
+	Adapter      string `yaml:"adapter,omitempty"`       // LoRA adapter id (registry key; #1464). omitempty => base-model-only (no-op).
+	IngressPoint string `yaml:"ingress_point,omitempty"` // cluster ingress point tag (Request.IngressPoint); "" = untagged
+	// ParallelSamples is the number of completions decoded per request
+	// (vLLM's n; Request.ParallelSamples). 0 or 1 = one.
+	ParallelSamples int         `yaml:"parallel_samples,omitempty"`
+	RateFraction    float64     `yaml:"rate_fraction"`
+	Concurrency     int         `yaml:"concurrency,omitempty"`
+	ThinkTimeUs     int64       `yaml:"think_time_us,omitempty"`
+	Arrival         ArrivalSpec `yaml:"arrival"`
+	InputDist       DistSpec    `yaml:"input_distribution"`
+	OutputDist      DistSpec    `yaml:"output_distribution"`
+	PrefixGroup     string      `yaml:"prefix_group,omitempty"`
+	PrefixLength    int         `yaml:"prefix_length,omitempty"` // shared prefix token count (default 50)
+	// PrefixLengthDist, when set, samples each request's prefix length: the
+	// request carries the first min(sample, group prefix length) tokens of the
+	// group prefix, so members share content up to the shortest sampled length.
+	PrefixLengthDist *DistSpec       `yaml:"prefix_length_distribution,omitempty"`
+	Streaming        bool            `yaml:"streaming"`
+	Network          *NetworkSpec    `yaml:"network,omitempty"`
+	Lifecycle        *LifecycleSpec  `yaml:"lifecycle,omitempty"`
+	Multimodal       *MultimodalSpec `yaml:"multimodal,omitempty"`
+	Reasoning        *ReasoningSpec  `yaml:"reasoning,omitempty"`
+	Timeout          *int64          `yaml:"timeout,omitempty"`       // Per-request timeout in µs. nil = default (300s). 0 = no timeout. (R9: pointer for zero-value)
+	SLOTargetUs      *int64          `yaml:"slo_target_us,omitempty"` // Per-request SLO TTFT target in µs. nil/0 = no target. (R9: pointer)
+	Priority         *PrioritySpec   `yaml:"priority,omitempty"`      // Explicit numeric priority (value or weights). nil = 0.
+	ClosedLoop       *bool           `yaml:"closed_loop,omitempty"`   // nil = default (true for reasoning/multi-turn). false = open-loop (all rounds pre-generated).
 	// CustomSamplerFactory allows programmatic injection of arrival sampler factories,
 	// bypassing the factory-based construction from Arrival.Process.
 	//
@@ -160,6 +179,19 @@ type ArrivalSpec struct {
 	// Populated by `blis convert servegen` (trace columns 5-6) or set directly in YAML for manual calibration.
 	Shape *float64 `yaml:"shape,omitempty"` // Gamma α or Weibull k
 	Scale *float64 `yaml:"scale,omitempty"` // Gamma θ or Weibull λ (in microseconds)
+
+	// Mixture lists the sub-processes of a "mixture" arrival: independent
+	// processes superposed, each at its weight's share of the client rate
+	// (e.g. 80% poisson + 20% bursty gamma). Required for, and only valid
+	// with, process "mixture"; components cannot nest.
+	Mixture []ArrivalComponent `yaml:"mixture,omitempty"`
+}
+
+// ArrivalComponent is one weighted sub-process of a mixture ArrivalSpec.
+// Weights are relative: they are normalized by their sum.
+type ArrivalComponent struct {
+	Weight      float64 `yaml:"weight"`
+	ArrivalSpec `yaml:",inline"`
 }
 
 // DistSpec parameterizes a token length distribution.
@@ -217,6 +249,7 @@ type ReasoningSpec struct {
 type MultiTurnSpec struct {
 	MaxRounds     int    `yaml:"max_rounds"`
 	ThinkTimeUs   int64  `yaml:"think_time_us"`
+	ThinkTimeDist string `yaml:"think_time_dist,omitempty"` // optional: sampled inter-round delay (ParseThinkTimeDist format); overrides ThinkTimeUs
 	ContextGrowth string `yaml:"context_growth"`
 	SingleSession bool   `yaml:"single_session,omitempty"`
 }
@@ -235,7 +268,7 @@ type ServeGenDataSpec struct {
 // Valid value registries.
 var (
 	validArrivalProcesses = map[string]bool{
-		"poisson": true, "gamma": true, "weibull": true, "constant": true,
+		"poisson": true, "gamma": true, "weibull": true, "constant": true, "mixture": true,
 	}
 	validDistTypes = map[string]bool{
 		"gaussian": true, "exponential": true, "pareto_lognormal": true, "lognormal": true, "empirical": true, "constant": true,
@@ -270,6 +303,9 @@ func (s *WorkloadSpec) Validate() error {
 	if !validCategories[s.Category] {
 		return fmt.Errorf("unknown category %q; valid: language, multimodal, reasoning", s.Category)
 	}
+	if !sim.IsValidRandSource(s.RandSource) {
+		return fmt.Errorf("unknown rand_source %q; valid: %v", s.RandSource, sim.ValidRandSourceNames())
+	}
 	// Only require aggregate_rate > 0 when at least one client is rate-based
 	// (Concurrency == 0). All-concurrency workloads don't need aggregate_rate.
 	hasRateBasedClient := false
@@ -427,6 +463,9 @@ func validateClient(c *ClientSpec, idx int) error {
 	if c.ThinkTimeUs < 0 {
 		return fmt.Errorf("%s: think_time_us must be non-negative, got %d", prefix, c.ThinkTimeUs)
 	}
+	if c.ParallelSamples < 0 {
+		return fmt.Errorf("%s: parallel_samples must be non-negative, got %d", prefix, c.ParallelSamples)
+	}
 	// Mutual exclusion: concurrency and rate_fraction cannot both be set
 	if c.Concurrency > 0 && c.RateFraction > 0 {
 		return fmt.Errorf("%s: concurrency and rate_fraction are mutually exclusive", prefix)
@@ -442,7 +481,10 @@ func validateClient(c *ClientSpec, idx int) error {
 	// CustomSamplerFactory also bypasses arrival process validation (programmatic injection).
 	if c.Concurrency == 0 && c.CustomSamplerFactory == nil {
 		if !validArrivalProcesses[c.Arrival.Process] {
-			return fmt.Errorf("%s: unknown arrival process %q; valid: poisson, gamma, weibull, constant", prefix, c.Arrival.Process)
+			return fmt.Errorf("%s: unknown arrival process %q; valid: poisson, gamma, weibull, constant, mixture", prefix, c.Arrival.Process)
+		}
+		if err := validateArrivalMixture(prefix, c.Arrival); err != nil {
+			return err
 		}
 		if c.Arrival.Process == "weibull" && c.Arrival.CV != nil {
 			// Skip CV bounds check when explicit MLE-fitted shape/scale are
@@ -477,6 +519,14 @@ func validateClient(c *ClientSpec, idx int) error {
 	if c.PrefixLength < 0 {
 		return fmt.Errorf("%s: prefix_length must be non-negative, got %d", prefix, c.PrefixLength)
 	}
+	if c.PrefixLengthDist != nil {
+		if c.PrefixGroup == "" {
+			return fmt.Errorf("%s: prefix_length_distribution requires prefix_group to be set", prefix)
+		}
+		if err := validateDistSpec(prefix+".prefix_length_distribution", c.PrefixLengthDist); err != nil {
+			return err
+		}
+	}
 	if err := validateDistSpec(prefix+".input_distribution", &c.InputDist); err != nil {
 		return err
 	}
@@ -490,10 +540,20 @@ func validateClient(c *ClientSpec, idx int) error {
 	if c.SLOTargetUs != nil && *c.SLOTargetUs < 0 {
 		return fmt.Errorf("%s: slo_target_us must be non-negative, got %d", prefix, *c.SLOTargetUs)
 	}
+	if c.Priority != nil {
+		if err := c.Priority.validate(); err != nil {
+			return fmt.Errorf("%s: priority: %w", prefix, err)
+		}
+	}
 	// Validate MaxRounds for reasoning/multi-turn (prevents panic in NewSessionManager)
 	if c.Reasoning != nil && c.Reasoning.MultiTurn != nil && c.Reasoning.MultiTurn.MaxRounds < 1 {
 		return fmt.Errorf("%s: reasoning.multi_turn.max_rounds must be >= 1, got %d", prefix, c.Reasoning.MultiTurn.MaxRounds)
 	}
+	if c.Reasoning != nil && c.Reasoning.MultiTurn != nil && c.Reasoning.MultiTurn.ThinkTimeDist != "" {
+		if _, err := ParseThinkTimeDist(c.Reasoning.MultiTurn.ThinkTimeDist); err != nil {
+			return fmt.Errorf("%s: reasoning.multi_turn.think_time_dist: %w", prefix, err)
+		}
+	}
 	// Validate lifecycle windows (#1131): empty or degenerate windows would cause
 	// the generator to loop indefinitely against a MaxInt64 horizon.
 	if c.Lifecycle != nil {
@@ -568,11 +628,17 @@ func validateCohort(c *CohortSpec, idx int) error {
 	if err := validateFinitePositive(prefix+".rate_fraction", c.RateFraction); err != nil {
 		return err
 	}
+	if c.ParallelSamples < 0 {
+		return fmt.Errorf("%s: parallel_samples must be non-negative, got %d", prefix, c.ParallelSamples)
+	}
 	if !validSLOClasses[c.SLOClass] {
 		return fmt.Errorf("%s: unknown slo_class %q; valid: critical, standard, sheddable, batch, background, or empty", prefix, c.SLOClass)
 	}
 	if !validArrivalProcesses[c.Arrival.Process] {
-		return fmt.Errorf("%s: unknown arrival process %q; valid: poisson, gamma, weibull, constant", prefix, c.Arrival.Process)
+		return fmt.Errorf("%s: unknown arrival process %q; valid: poisson, gamma, weibull, constant, mixture", prefix, c.Arrival.Process)
+	}
+	if err := validateArrivalMixture(prefix, c.Arrival); err != nil {
+		return err
 	}
 	if c.Arrival.Process == "weibull" && c.Arrival.CV != nil {
 		// Skip CV bounds check when explicit MLE-fitted shape/scale are
@@ -635,6 +701,14 @@ func validateCohort(c *CohortSpec, idx int) error {
 	if c.PrefixLength < 0 {
 		return fmt.Errorf("%s: prefix_length must be non-negative, got %d", prefix, c.PrefixLength)
 	}
+	if c.PrefixLengthDist != nil {
+		if c.PrefixGroup == "" {
+			return fmt.Errorf("%s: prefix_length_distribution requires prefix_group to be set", prefix)
+		}
+		if err := validateDistSpec(prefix+".prefix_length_distribution", c.PrefixLengthDist); err != nil {
+			return err
+		}
+	}
 	if c.PrefixSharing != "" && c.PrefixSharing != "shared" && c.PrefixSharing != "per_member" {
 		return fmt.Errorf("%s: prefix_sharing must be \"shared\" or \"per_member\", got %q", prefix, c.PrefixSharing)
 	}
@@ -647,9 +721,19 @@ func validateCohort(c *CohortSpec, idx int) error {
 	if c.SLOTargetUs != nil && *c.SLOTargetUs < 0 {
 		return fmt.Errorf("%s: slo_target_us must be non-negative, got %d", prefix, *c.SLOTargetUs)
 	}
+	if c.Priority != nil {
+		if err := c.Priority.validate(); err != nil {
+			return fmt.Errorf("%s: priority: %w", prefix, err)
+		}
+	}
 	if c.Reasoning != nil && c.Reasoning.MultiTurn != nil && c.Reasoning.MultiTurn.MaxRounds < 1 {
 		return fmt.Errorf("%s: reasoning.multi_turn.max_rounds must be >= 1, got %d", prefix, c.Reasoning.MultiTurn.MaxRounds)
 	}
+	if c.Reasoning != nil && c.Reasoning.MultiTurn != nil && c.Reasoning.MultiTurn.ThinkTimeDist != "" {
+		if _, err := ParseThinkTimeDist(c.Reasoning.MultiTurn.ThinkTimeDist); err != nil {
+			return fmt.Errorf("%s: reasoning.multi_turn.think_time_dist: %w", prefix, err)
+		}
+	}
 	return nil
 }
 
@@ -659,6 +743,43 @@ func IsValidSLOClass(name string) bool {
 	return validSLOClasses[name]
 }
 
+// validateArrivalMixture checks that mixture components are present exactly
+// when the process is "mixture", and that each has a positive weight and a
+// valid, non-mixture process with a positive CV (weibull CV within the
+// bisection range).
+func validateArrivalMixture(prefix string, a ArrivalSpec) error {
+	if a.Process != "mixture" {
+		if len(a.Mixture) > 0 {
+			return fmt.Errorf("%s: arrival.mixture requires process \"mixture\", got %q", prefix, a.Process)
+		}
+		return nil
+	}
+	if len(a.Mixture) == 0 {
+		return fmt.Errorf("%s: arrival process \"mixture\" requires at least one arrival.mixture component", prefix)
+	}
+	for i, comp := range a.Mixture {
+		name := fmt.Sprintf("%s.arrival.mixture[%d]", prefix, i)
+		if err := validateFinitePositive(name+".weight", comp.Weight); err != nil {
+			return err
+		}
+		if comp.Process == "mixture" || len(comp.Mixture) > 0 {
+			return fmt.Errorf("%s: mixture components cannot nest", name)
+		}
+		if !validArrivalProcesses[comp.Process] {
+			return fmt.Errorf("%s: unknown arrival process %q; valid: poisson, gamma, weibull, constant", name, comp.Process)
+		}
+		if comp.CV != nil {
+			if err := validateFinitePositive(name+".cv", *comp.CV); err != nil {
+				return err
+			}
+			if comp.Process == "weibull" && (*comp.CV < 0.01 || *comp.CV > 10.4) {
+				return fmt.Errorf("%s: weibull CV must be in [0.01, 10.4], got %f", name, *comp.CV)
+			}
+		}
+	}
+	return nil
+}
+
 func validateFinitePositive(name string, val float64) error {
 	if math.IsNaN(val) || math.IsInf(val, 0) {
 		return fmt.Errorf("%s must be a finite number, got %f", name, val)
@@ -0,0 +1,208 @@
+package workload
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FieldError is a single field-level problem in a WorkloadSpec. Path uses the
+// YAML key names (e.g. "clients[0].input_distribution.params.std_dev") so users
+// can locate the offending line in a hand-written spec.
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string { return e.Path + ": " + e.Message }
+
+// FieldErrors is an ordered list of FieldError values. Non-empty FieldErrors
+// implements error so it can be wrapped and returned directly.
+type FieldErrors []FieldError
+
+// Error joins all field errors, one per line.
+func (fe FieldErrors) Error() string {
+	msgs := make([]string, len(fe))
+	for i, e := range fe {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ValidateFields checks the documented per-field constraints of a parsed spec
+// and returns every violation found, in field order, rather than stopping at
+// the first one. Covered constraints: rate_fraction present for rate-based
+// clients and cohorts, known arrival processes, distribution types with the
+// parameters each type requires, recognized slo_class values, and well-formed
+// reasoning and priority specs. Cross-field rules (absolute rate mode, mixed slo_class,
+// source mutual exclusion) remain in Validate. Returns nil for a valid spec.
+func (s *WorkloadSpec) ValidateFields() FieldErrors {
+	var errs FieldErrors
+	add := func(path, format string, args ...any) {
+		errs = append(errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	for i := range s.Clients {
+		c := &s.Clients[i]
+		prefix := fmt.Sprintf("clients[%d]", i)
+		rateBased := c.Concurrency == 0
+		if rateBased && c.RateFraction == 0 {
+			add(prefix+".rate_fraction", "required for rate-based clients (concurrency is 0)")
+		} else if rateBased {
+			checkFinitePositive(add, prefix+".rate_fraction", c.RateFraction)
+		}
+		if rateBased && c.CustomSamplerFactory == nil && !validArrivalProcesses[c.Arrival.Process] {
+			add(prefix+".arrival.process", "unknown arrival process %q; valid: %s", c.Arrival.Process, registryNames(validArrivalProcesses))
+		}
+		if rateBased && c.CustomSamplerFactory == nil {
+			checkArrivalMixture(add, prefix+".arrival", c.Arrival)
+		}
+		checkSLOClass(add, prefix+".slo_class", c.SLOClass)
+		checkDist(add, prefix+".input_distribution", c.InputDist)
+		checkDist(add, prefix+".output_distribution", c.OutputDist)
+		if c.PrefixLengthDist != nil {
+			checkDist(add, prefix+".prefix_length_distribution", *c.PrefixLengthDist)
+		}
+		checkReasoning(add, prefix+".reasoning", c.Reasoning)
+		checkPriority(add, prefix+".priority", c.Priority)
+	}
+	for i := range s.Cohorts {
+		c := &s.Cohorts[i]
+		prefix := fmt.Sprintf("cohorts[%d]", i)
+		if c.RateFraction == 0 {
+			add(prefix+".rate_fraction", "required (cohorts are always rate-based)")
+		} else {
+			checkFinitePositive(add, prefix+".rate_fraction", c.RateFraction)
+		}
+		if !validArrivalProcesses[c.Arrival.Process] {
+			add(prefix+".arrival.process", "unknown arrival process %q; valid: %s", c.Arrival.Process, registryNames(validArrivalProcesses))
+		}
+		checkArrivalMixture(add, prefix+".arrival", c.Arrival)
+		checkSLOClass(add, prefix+".slo_class", c.SLOClass)
+		checkDist(add, prefix+".input_distribution", c.InputDist)
+		checkDist(add, prefix+".output_distribution", c.OutputDist)
+		if c.PrefixLengthDist != nil {
+			checkDist(add, prefix+".prefix_length_distribution", *c.PrefixLengthDist)
+		}
+		checkReasoning(add, prefix+".reasoning", c.Reasoning)
+		checkPriority(add, prefix+".priority", c.Priority)
+	}
+	return errs
+}
+
+// fieldErrorFn records one field-level violation.
+type fieldErrorFn func(path, format string, args ...any)
+
+func checkFinitePositive(add fieldErrorFn, path string, val float64) {
+	if math.IsNaN(val) || math.IsInf(val, 0) || val <= 0 {
+		add(path, "must be a finite positive number, got %v", val)
+	}
+}
+
+// checkArrivalMixture validates the components of a "mixture" arrival and
+// rejects components on any other process.
+func checkArrivalMixture(add fieldErrorFn, path string, a ArrivalSpec) {
+	if a.Process != "mixture" {
+		if len(a.Mixture) > 0 {
+			add(path+".mixture", "requires process \"mixture\", got %q", a.Process)
+		}
+		return
+	}
+	if len(a.Mixture) == 0 {
+		add(path+".mixture", "required for process \"mixture\"")
+	}
+	for i, comp := range a.Mixture {
+		compPath := fmt.Sprintf("%s.mixture[%d]", path, i)
+		checkFinitePositive(add, compPath+".weight", comp.Weight)
+		if comp.Process == "mixture" || len(comp.Mixture) > 0 {
+			add(compPath+".process", "mixture components cannot nest")
+		} else if !validArrivalProcesses[comp.Process] {
+			add(compPath+".process", "unknown arrival process %q; valid: %s", comp.Process, registryNames(validArrivalProcesses))
+		}
+	}
+}
+
+func checkSLOClass(add fieldErrorFn, path, class string) {
+	if !validSLOClasses[class] {
+		add(path, "unknown slo_class %q; valid: critical, standard, sheddable, batch, background, or empty", class)
+	}
+}
+
+// checkDist validates a DistSpec's type and parameters. Non-finite params are
+// reported per parameter; everything else NewLengthSampler would reject
+// (missing required params, min > max, malformed empirical bins) is reported
+// against the distribution itself. File-backed empirical distributions are not
+// opened here.
+func checkDist(add fieldErrorFn, path string, d DistSpec) {
+	if !validDistTypes[d.Type] {
+		add(path+".type", "unknown distribution type %q; valid: %s", d.Type, registryNames(validDistTypes))
+		return
+	}
+	finite := true
+	for _, name := range sortedParamNames(d.Params) {
+		if val := d.Params[name]; math.IsNaN(val) || math.IsInf(val, 0) {
+			add(path+".params."+name, "must be a finite number, got %v", val)
+			finite = false
+		}
+	}
+	if !finite || (d.Type == "empirical" && d.File != "") {
+		return
+	}
+	if _, err := NewLengthSampler(d); err != nil {
+		add(path, "%v", err)
+	}
+}
+
+func checkReasoning(add fieldErrorFn, path string, r *ReasoningSpec) {
+	if r == nil {
+		return
+	}
+	if r.ReasonRatioDist.Type != "" {
+		checkDist(add, path+".reason_ratio_distribution", r.ReasonRatioDist)
+	}
+	mt := r.MultiTurn
+	if mt == nil {
+		return
+	}
+	if mt.MaxRounds < 1 {
+		add(path+".multi_turn.max_rounds", "must be >= 1, got %d", mt.MaxRounds)
+	}
+	if mt.ThinkTimeUs < 0 {
+		add(path+".multi_turn.think_time_us", "must be non-negative, got %d", mt.ThinkTimeUs)
+	}
+	if mt.ThinkTimeDist != "" {
+		if _, err := ParseThinkTimeDist(mt.ThinkTimeDist); err != nil {
+			add(path+".multi_turn.think_time_dist", "%v", err)
+		}
+	}
+}
+
+func checkPriority(add fieldErrorFn, path string, p *PrioritySpec) {
+	if p == nil {
+		return
+	}
+	if err := p.validate(); err != nil {
+		add(path, "%v", err)
+	}
+}
+
+func sortedParamNames(params map[string]float64) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func registryNames(registry map[string]bool) string {
+	var names []string
+	for name := range registry {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,153 @@
+package workload
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func fieldsValidSpec() *WorkloadSpec {
+	return &WorkloadSpec{
+		Version:       "2",
+		AggregateRate: 10,
+		Clients: []ClientSpec{{
+			ID:           "c0",
+			SLOClass:     "standard",
+			RateFraction: 1.0,
+			Arrival:      ArrivalSpec{Process: "poisson"},
+			InputDist:    DistSpec{Type: "gaussian", Params: map[string]float64{"mean": 100, "std_dev": 10, "min": 10, "max": 200}},
+			OutputDist:   DistSpec{Type: "exponential", Params: map[string]float64{"mean": 50}},
+			Reasoning: &ReasoningSpec{
+				ReasonRatioDist: DistSpec{Type: "constant", Params: map[string]float64{"value": 50}},
+				MultiTurn:       &MultiTurnSpec{MaxRounds: 3, ThinkTimeUs: 1000, ContextGrowth: "accumulate"},
+			},
+		}},
+	}
+}
+
+func TestValidateFields_ValidSpec_NoErrors(t *testing.T) {
+	if errs := fieldsValidSpec().ValidateFields(); len(errs) != 0 {
+		t.Errorf("ValidateFields on a valid spec = %v, want none", errs)
+	}
+}
+
+// TestValidateFields_MalformedSpecs_ReportFieldPaths feeds one defect at a time
+// and asserts the error names the offending field.
+func TestValidateFields_MalformedSpecs_ReportFieldPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(s *WorkloadSpec)
+		wantPath string
+		wantMsg  string
+	}{
+		{
+			name:     "missing rate_fraction",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].RateFraction = 0 },
+			wantPath: "clients[0].rate_fraction",
+			wantMsg:  "required",
+		},
+		{
+			name:     "NaN rate_fraction",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].RateFraction = math.NaN() },
+			wantPath: "clients[0].rate_fraction",
+			wantMsg:  "finite positive",
+		},
+		{
+			name:     "gaussian missing std_dev",
+			mutate:   func(s *WorkloadSpec) { delete(s.Clients[0].InputDist.Params, "std_dev") },
+			wantPath: "clients[0].input_distribution",
+			wantMsg:  `"std_dev"`,
+		},
+		{
+			name:     "unknown distribution type",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].OutputDist.Type = "zipf" },
+			wantPath: "clients[0].output_distribution.type",
+			wantMsg:  `"zipf"`,
+		},
+		{
+			name:     "Inf distribution param",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].OutputDist.Params["mean"] = math.Inf(1) },
+			wantPath: "clients[0].output_distribution.params.mean",
+			wantMsg:  "finite",
+		},
+		{
+			name:     "unknown slo_class",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].SLOClass = "gold" },
+			wantPath: "clients[0].slo_class",
+			wantMsg:  `"gold"`,
+		},
+		{
+			name:     "unknown arrival process",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].Arrival.Process = "bursty" },
+			wantPath: "clients[0].arrival.process",
+			wantMsg:  `"bursty"`,
+		},
+		{
+			name: "reasoning ratio lognormal missing mu",
+			mutate: func(s *WorkloadSpec) {
+				s.Clients[0].Reasoning.ReasonRatioDist = DistSpec{Type: "lognormal", Params: map[string]float64{"sigma": 0.5}}
+			},
+			wantPath: "clients[0].reasoning.reason_ratio_distribution",
+			wantMsg:  `"mu"`,
+		},
+		{
+			name:     "reasoning zero max_rounds",
+			mutate:   func(s *WorkloadSpec) { s.Clients[0].Reasoning.MultiTurn.MaxRounds = 0 },
+			wantPath: "clients[0].reasoning.multi_turn.max_rounds",
+			wantMsg:  ">= 1",
+		},
+		{
+			name: "cohort missing rate_fraction",
+			mutate: func(s *WorkloadSpec) {
+				s.Cohorts = []CohortSpec{{
+					ID: "co", Population: 2, SLOClass: "batch",
+					Arrival:    ArrivalSpec{Process: "poisson"},
+					InputDist:  DistSpec{Type: "constant", Params: map[string]float64{"value": 10}},
+					OutputDist: DistSpec{Type: "constant", Params: map[string]float64{"value": 10}},
+				}}
+			},
+			wantPath: "cohorts[0].rate_fraction",
+			wantMsg:  "required",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := fieldsValidSpec()
+			tc.mutate(spec)
+			errs := spec.ValidateFields()
+			if len(errs) != 1 {
+				t.Fatalf("got %d errors %v, want exactly 1", len(errs), errs)
+			}
+			if errs[0].Path != tc.wantPath {
+				t.Errorf("Path = %q, want %q", errs[0].Path, tc.wantPath)
+			}
+			if !strings.Contains(errs[0].Message, tc.wantMsg) {
+				t.Errorf("Message = %q, want substring %q", errs[0].Message, tc.wantMsg)
+			}
+		})
+	}
+}
+
+// TestValidateFields_ReportsAllErrors verifies errors are collected rather than
+// stopping at the first, and that generation surfaces them before running.
+func TestValidateFields_ReportsAllErrors(t *testing.T) {
+	spec := fieldsValidSpec()
+	spec.Clients[0].SLOClass = "gold"
+	spec.Clients[0].OutputDist.Type = "zipf"
+	spec.Clients[0].Reasoning.MultiTurn.MaxRounds = 0
+
+	errs := spec.ValidateFields()
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors %v, want 3", len(errs), errs)
+	}
+
+	_, err := GenerateRequests(spec, 1_000_000, 10)
+	if err == nil {
+		t.Fatal("GenerateRequests accepted a malformed spec")
+	}
+	for _, e := range errs {
+		if !strings.Contains(err.Error(), e.Path) {
+			t.Errorf("generation error %q does not mention %s", err, e.Path)
+		}
+	}
+}
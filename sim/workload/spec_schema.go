@@ -0,0 +1,113 @@
+package workload
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaEnums maps "<struct>.<yaml key>" to the registry of accepted values
+// for that field, so the exported schema enumerates them.
+var schemaEnums = map[string]map[string]bool{
+	"WorkloadSpec.category": validCategories,
+	"ClientSpec.slo_class":  validSLOClasses,
+	"CohortSpec.slo_class":  validSLOClasses,
+	"ArrivalSpec.process":   validArrivalProcesses,
+	"DistSpec.type":         validDistTypes,
+}
+
+// WorkloadSpecJSONSchema returns a JSON Schema (draft 2020-12) for the YAML
+// WorkloadSpec format. It is derived from the spec structs' yaml tags, so it
+// tracks what LoadWorkloadSpec accepts: unknown keys are disallowed and the
+// registered arrival processes, distribution types, categories and slo_class
+// values are enums. Cross-field and per-type parameter rules are not
+// expressed; those remain with ValidateFields and Validate.
+func WorkloadSpecJSONSchema() map[string]any {
+	b := &schemaBuilder{defs: map[string]any{}}
+	root := b.objectSchema(reflect.TypeOf(WorkloadSpec{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "BLIS WorkloadSpec"
+	root["$defs"] = b.defs
+	return root
+}
+
+// schemaBuilder emits nested struct types once under $defs and references
+// them by name.
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		s := map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+		if t.Key().Kind() != reflect.String {
+			s["propertyNames"] = map[string]any{"pattern": "^-?[0-9]+$"}
+		}
+		return s
+	case reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.defs[t.Name()] = nil // reserve before recursing
+			b.defs[t.Name()] = b.objectSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	panic(fmt.Sprintf("WorkloadSpecJSONSchema: unsupported field kind %s", t.Kind()))
+}
+
+// objectSchema describes struct t as a closed object keyed by its yaml names.
+func (b *schemaBuilder) objectSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	b.addFields(props, t, t.Name())
+	return map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+}
+
+// addFields adds t's yaml-visible fields to props, flattening inline
+// embedded structs the way yaml.v3 decodes them. Enum lookups use owner,
+// the struct the fields were declared in.
+func (b *schemaBuilder) addFields(props map[string]any, t reflect.Type, owner string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("yaml")
+		if !f.IsExported() || tag == "-" || f.Type.Kind() == reflect.Func {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			b.addFields(props, f.Type, f.Type.Name())
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		s := b.typeSchema(f.Type)
+		if values, ok := schemaEnums[owner+"."+name]; ok {
+			s["enum"] = registryValues(values)
+		}
+		props[name] = s
+	}
+}
+
+// registryValues returns the keys of a value registry in sorted order (R2).
+func registryValues(registry map[string]bool) []string {
+	values := make([]string, 0, len(registry))
+	for v := range registry {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,114 @@
+package workload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaCheck walks a decoded YAML document against schema s and returns the
+// first key or enum value the schema does not admit. It follows $ref, object
+// properties, array items and map values; that is all WorkloadSpecJSONSchema emits.
+func schemaCheck(root, s map[string]any, path string, doc any) error {
+	if ref, ok := s["$ref"].(string); ok {
+		return schemaCheck(root, root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any), path, doc)
+	}
+	if enum, ok := s["enum"].([]string); ok && !slices.Contains(enum, fmt.Sprint(doc)) {
+		return fmt.Errorf("%s: %v not in enum %v", path, doc, enum)
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		props, _ := s["properties"].(map[string]any)
+		for k, val := range v {
+			child, ok := props[k].(map[string]any)
+			if !ok {
+				if child, ok = s["additionalProperties"].(map[string]any); !ok {
+					return fmt.Errorf("%s.%s: key not in schema", path, k)
+				}
+			}
+			if err := schemaCheck(root, child, path+"."+k, val); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, val := range v {
+			if err := schemaCheck(root, s["items"].(map[string]any), fmt.Sprintf("%s[%d]", path, i), val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TestWorkloadSpecJSONSchema_AdmitsExampleSpecs verifies every key and enum
+// value in the example workload specs is described by the exported schema.
+func TestWorkloadSpecJSONSchema_AdmitsExampleSpecs(t *testing.T) {
+	schema := WorkloadSpecJSONSchema()
+	files, err := filepath.Glob("../../examples/*.yaml")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	checked := 0
+	for _, path := range files {
+		if _, err := LoadWorkloadSpec(path); err != nil {
+			continue // policy configs and other non-spec YAML
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("unmarshal %s: %v", path, err)
+		}
+		if err := schemaCheck(schema, schema, filepath.Base(path), doc); err != nil {
+			t.Errorf("schema rejects %v", err)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("no example workload specs found — check relative path from sim/workload/")
+	}
+}
+
+// TestWorkloadSpecJSONSchema_RejectsUnknownKeysAndValues verifies the schema
+// is closed like LoadWorkloadSpec's strict parsing and enumerates registries.
+func TestWorkloadSpecJSONSchema_RejectsUnknownKeysAndValues(t *testing.T) {
+	schema := WorkloadSpecJSONSchema()
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"typo key", "version: \"2\"\nclients:\n  - id: c0\n    rate_fracton: 1\n"},
+		{"unknown slo_class", "clients:\n  - slo_class: gold\n"},
+		{"unknown process", "clients:\n  - arrival: {process: bursty}\n"},
+		{"unknown mixture process", "clients:\n  - arrival: {process: mixture, mixture: [{weight: 1, process: bursty}]}\n"},
+		{"unknown dist type", "cohorts:\n  - input_distribution: {type: zipf}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc map[string]any
+			if err := yaml.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := schemaCheck(schema, schema, "spec", doc); err == nil {
+				t.Error("schema admitted the document, want rejection")
+			}
+		})
+	}
+
+	// The schema is plain JSON and names the registries as enums.
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	dist := schema["$defs"].(map[string]any)["DistSpec"].(map[string]any)["properties"].(map[string]any)
+	if got, want := dist["type"].(map[string]any)["enum"], registryValues(validDistTypes); !slices.Equal(got.([]string), want) {
+		t.Errorf("DistSpec.type enum = %v, want %v", got, want)
+	}
+}
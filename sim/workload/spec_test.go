@@ -142,6 +142,31 @@ func TestWorkloadSpec_Validate_InvalidDistType_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestWorkloadSpec_Validate_InvalidThinkTimeDist_ReturnsError(t *testing.T) {
+	spec := &WorkloadSpec{
+		Version:       "1",
+		AggregateRate: 100.0,
+		Clients: []ClientSpec{{
+			ID:           "c1",
+			RateFraction: 1.0,
+			Arrival:      ArrivalSpec{Process: "poisson"},
+			InputDist:    DistSpec{Type: "exponential", Params: map[string]float64{"mean": 100}},
+			OutputDist:   DistSpec{Type: "exponential", Params: map[string]float64{"mean": 50}},
+			Reasoning: &ReasoningSpec{MultiTurn: &MultiTurnSpec{
+				MaxRounds:     3,
+				ThinkTimeDist: "lognormal:mu=1.0",
+			}},
+		}},
+	}
+	err := spec.Validate()
+	if err == nil {
+		t.Fatal("expected error for think_time_dist missing sigma")
+	}
+	if !strings.Contains(err.Error(), "think_time_dist") {
+		t.Errorf("error should name think_time_dist, got: %v", err)
+	}
+}
+
 func TestWorkloadSpec_Validate_InvalidCategory_ReturnsError(t *testing.T) {
 	spec := &WorkloadSpec{
 		Version:       "1",
@@ -1251,3 +1276,89 @@ func TestExampleWorkloadFiles_CanonicalSLOClasses(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadWorkloadSpec_MixtureArrival_GeneratesAtClientRate(t *testing.T) {
+	// GIVEN a client whose 10 req/s arrivals are 80% Poisson + 20% bursty Gamma
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	yamlContent := `
+version: "2"
+seed: 7
+aggregate_rate: 10.0
+clients:
+  - id: "mixed"
+    rate_fraction: 1.0
+    arrival:
+      process: mixture
+      mixture:
+        - weight: 0.8
+          process: poisson
+        - weight: 0.2
+          process: gamma
+          cv: 4.0
+    input_distribution:
+      type: constant
+      params:
+        value: 100
+    output_distribution:
+      type: constant
+      params:
+        value: 10
+`
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadWorkloadSpec(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	mix := spec.Clients[0].Arrival.Mixture
+	if len(mix) != 2 || mix[1].Process != "gamma" || mix[1].CV == nil || *mix[1].CV != 4.0 {
+		t.Fatalf("mixture = %+v, want poisson + gamma(cv=4)", mix)
+	}
+
+	// WHEN 200 seconds of requests are generated
+	requests, err := GenerateRequests(spec, 200_000_000, 0)
+	if err != nil {
+		t.Fatalf("GenerateRequests: %v", err)
+	}
+
+	// THEN the empirical rate matches the client's 10 req/s (within 10%)
+	if rate := float64(len(requests)) / 200; math.Abs(rate-10) > 1 {
+		t.Errorf("empirical rate = %.2f req/s (%d requests), want ≈ 10", rate, len(requests))
+	}
+}
+
+func TestWorkloadSpec_Validate_MixtureArrival_RejectsMalformed(t *testing.T) {
+	cases := map[string]ArrivalSpec{
+		"no components":   {Process: "mixture"},
+		"zero weight":     {Process: "mixture", Mixture: []ArrivalComponent{{Weight: 0, ArrivalSpec: ArrivalSpec{Process: "poisson"}}}},
+		"nested":          {Process: "mixture", Mixture: []ArrivalComponent{{Weight: 1, ArrivalSpec: ArrivalSpec{Process: "mixture"}}}},
+		"unknown process": {Process: "mixture", Mixture: []ArrivalComponent{{Weight: 1, ArrivalSpec: ArrivalSpec{Process: "bursty"}}}},
+		"not a mixture":   {Process: "poisson", Mixture: []ArrivalComponent{{Weight: 1, ArrivalSpec: ArrivalSpec{Process: "poisson"}}}},
+	}
+	for name, arrival := range cases {
+		t.Run(name, func(t *testing.T) {
+			spec := &WorkloadSpec{
+				Version:       "2",
+				AggregateRate: 100.0,
+				Clients: []ClientSpec{{
+					ID:           "c1",
+					RateFraction: 1.0,
+					Arrival:      arrival,
+					InputDist:    DistSpec{Type: "exponential", Params: map[string]float64{"mean": 100}},
+					OutputDist:   DistSpec{Type: "exponential", Params: map[string]float64{"mean": 50}},
+				}},
+			}
+			if err := spec.Validate(); err == nil {
+				t.Error("Validate: expected an error")
+			}
+			if errs := spec.ValidateFields(); len(errs) == 0 {
+				t.Error("ValidateFields: expected an error")
+			}
+		})
+	}
+}
@@ -248,6 +248,9 @@ func (s *clientStreamState) produceNextSingleShot() (*sim.Request, int64, bool)
 			PrefixLength:     prefixLength,
 			Streaming:        s.client.Streaming,
 		}
+		s.client.Priority.assign(req)
+		req.IngressPoint = s.client.IngressPoint
+		req.ParallelSamples = s.client.ParallelSamples
 		s.perClientSeq++
 		return req, s.currentTime, true
 	}
@@ -570,7 +573,7 @@ func (s *clientStreamState) buildNextTimeVaryingWindow() bool {
 }
 
 // buildSession generates one reasoning session at startTime and sets the
-// per-round Deadline/SLOTargetUs (mirrors GenerateRequests' reasoning path).
+// per-round Deadline/SLOTargetUs/ExplicitPriority (mirrors GenerateRequests' reasoning path).
 // GenerateReasoningRequests seeds/prepends the prefix internally (#1445).
 // On error it records the terminal error on the state and returns it; callers
 // stop producing so lazyRequestSource.Err() surfaces it to cmd for a Fatalf
@@ -593,6 +596,9 @@ func (s *clientStreamState) buildSession(startTime int64) ([]*sim.Request, error
 	for _, req := range reasoningReqs {
 		req.Deadline = computeDeadline(req.ArrivalTime, s.client.Timeout, true)
 		req.SLOTargetUs = derefInt64(s.client.SLOTargetUs)
+		s.client.Priority.assign(req)
+		req.IngressPoint = s.client.IngressPoint
+		req.ParallelSamples = s.client.ParallelSamples
 	}
 	return reasoningReqs, nil
 }
@@ -839,6 +845,7 @@ type clientPrep struct {
 	idx        int
 	client     *ClientSpec
 	clientSeed int64
+	randSource string // WorkloadSpec.RandSource for the client's RNGs
 	rate       float64
 	prefix     []sim.TokenID
 
@@ -914,7 +921,7 @@ func GenerateWorkloadLazy(spec *WorkloadSpec, horizon int64, maxRequests int64)
 	// expanded clients).
 	allClients := append([]ClientSpec{}, spec.Clients...)
 	if len(spec.Cohorts) > 0 {
-		allClients = append(allClients, ExpandCohorts(spec.Cohorts, spec.Seed)...)
+		allClients = append(allClients, expandCohorts(spec.Cohorts, spec.Seed, spec.RandSource)...)
 	}
 
 	// Time-varying dispatch. Clients with per-window parameter overrides
@@ -927,7 +934,7 @@ func GenerateWorkloadLazy(spec *WorkloadSpec, horizon int64, maxRequests int64)
 		return generateTimeVaryingWorkloadLazy(spec, horizon, maxRequests, allClients)
 	}
 
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(spec.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
 	workloadRNG := rng.ForSubsystem(sim.SubsystemWorkloadGen)
 	clientRates := normalizeRateFractions(allClients, spec.AggregateRate)
 	prefixes := generatePrefixTokens(allClients, workloadRNG)
@@ -946,6 +953,7 @@ func GenerateWorkloadLazy(spec *WorkloadSpec, horizon int64, maxRequests int64)
 			idx:        i,
 			client:     &allClients[i],
 			clientSeed: clientSeed,
+			randSource: spec.RandSource,
 			rate:       clientRates[i],
 			prefix:     prefixes[allClients[i].PrefixGroup],
 		})
@@ -954,7 +962,7 @@ func GenerateWorkloadLazy(spec *WorkloadSpec, horizon int64, maxRequests int64)
 	// Phases 2–4 (blueprint pre-pass, streaming states, concurrency seeds) are
 	// identical for the time-varying and non-time-varying paths — they operate
 	// purely on preps/prefixes/allClients — so they live in one shared helper.
-	return assembleLazySourceFromPreps(preps, prefixes, allClients, spec.Seed, horizon, maxRequests)
+	return assembleLazySourceFromPreps(preps, prefixes, allClients, spec.Seed, spec.RandSource, horizon, maxRequests)
 }
 
 // assembleLazySourceFromPreps runs the shared Phases 2–4 of lazy workload
@@ -969,6 +977,7 @@ func assembleLazySourceFromPreps(
 	prefixes map[string][]sim.TokenID,
 	allClients []ClientSpec,
 	specSeed int64,
+	randSource string,
 	horizon int64,
 	maxRequests int64,
 ) (*lazyRequestSource, []SessionBlueprint, int64, error) {
@@ -992,11 +1001,14 @@ func assembleLazySourceFromPreps(
 	// subsequent blueprint RNG seed — breaking INV-6 byte-identity and
 	// INV-13 run/replay parity for closed-loop reasoning under a tight
 	// cap. (Bug found in PR #1453 self-review.)
+	if horizon == math.MaxInt64 && maxRequests <= 0 && hasClosedLoopSessionPrep(preps) {
+		return nil, nil, 0, fmt.Errorf("closed-loop multi-turn clients require a finite horizon or num_requests")
+	}
 	survivingPerClient, keptOpen, err := enumerateSurvivingSessionsPerClient(preps, prefixes, horizon, maxRequests)
 	if err != nil {
 		return nil, nil, 0, err
 	}
-	blueprintRNG := rand.New(rand.NewSource(specSeed + 7919))
+	blueprintRNG := newRandFromSeed(randSource, specSeed+7919)
 	var sessions []SessionBlueprint
 	for _, p := range preps {
 		if !isClosedLoop(p.client) || p.client.Reasoning == nil || p.client.Reasoning.MultiTurn == nil {
@@ -1027,25 +1039,30 @@ func assembleLazySourceFromPreps(
 			prefixTokens = prefixes[p.client.PrefixGroup]
 		}
 		mt := p.client.Reasoning.MultiTurn
+		thinkTimeSampler, err := mt.thinkTimeSampler()
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("client %q: %w", p.client.ID, err)
+		}
 		for _, sessID := range sessIDs {
 			sessSeed := blueprintRNG.Int63()
 			sessions = append(sessions, SessionBlueprint{
-				SessionID:     sessID,
-				ClientID:      p.client.ID,
-				MaxRounds:     mt.MaxRounds,
-				ContextGrowth: mt.ContextGrowth,
-				ThinkTimeUs:   mt.ThinkTimeUs,
-				Timeout:       p.client.Timeout,
-				Horizon:       horizon,
-				InputSampler:  inputSampler,
-				OutputSampler: outputSampler,
-				RNG:           rand.New(rand.NewSource(sessSeed)),
-				Prefix:        prefixTokens,
-				TenantID:      p.client.TenantID,
-				SLOClass:      p.client.SLOClass,
-				Model:         p.client.Model,
-				Adapter:       p.client.Adapter,
-				SLOTargetUs:   derefInt64(p.client.SLOTargetUs),
+				SessionID:        sessID,
+				ClientID:         p.client.ID,
+				MaxRounds:        mt.MaxRounds,
+				ContextGrowth:    mt.ContextGrowth,
+				ThinkTimeUs:      mt.ThinkTimeUs,
+				ThinkTimeSampler: thinkTimeSampler, // stateless: safe to share across sessions
+				Timeout:          p.client.Timeout,
+				Horizon:          horizon,
+				InputSampler:     inputSampler,
+				OutputSampler:    outputSampler,
+				RNG:              newRandFromSeed(randSource, sessSeed),
+				Prefix:           prefixTokens,
+				TenantID:         p.client.TenantID,
+				SLOClass:         p.client.SLOClass,
+				Model:            p.client.Model,
+				Adapter:          p.client.Adapter,
+				SLOTargetUs:      derefInt64(p.client.SLOTargetUs),
 			})
 		}
 	}
@@ -1053,7 +1070,7 @@ func assembleLazySourceFromPreps(
 	// Phase 3: build the streaming states with fresh RNGs seeded from the
 	// same clientSeeds. The pre-pass RNGs ran to completion and are
 	// discarded; Phase 3's RNGs start anew but, because
-	// `rand.New(rand.NewSource(s))` is deterministic, both produce the
+	// `newRandFromSeed(source, s)` is deterministic, both produce the
 	// SAME starting sequence — giving the streaming pass byte-identical
 	// emissions to what the pre-pass simulated.
 	h := &heapByArrival{}
@@ -1083,7 +1100,7 @@ func assembleLazySourceFromPreps(
 	// Phase 2 dry-run. Push the seeds as individual heap entries (see the shared
 	// helper) so the global merge reproduces eager's stable-sort-by-arrival.
 	sessions, followUpBudget, err := appendConcurrencySeedsToHeap(
-		h, allClients, prefixes, specSeed, horizon, maxRequests, keptOpen, sessions)
+		h, allClients, prefixes, specSeed, randSource, horizon, maxRequests, keptOpen, sessions)
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -1108,7 +1125,7 @@ func assembleLazySourceFromPreps(
 func generateTimeVaryingWorkloadLazy(
 	spec *WorkloadSpec, horizon int64, maxRequests int64, allClients []ClientSpec,
 ) (*lazyRequestSource, []SessionBlueprint, int64, error) {
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(spec.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
 	workloadRNG := rng.ForSubsystem(sim.SubsystemWorkloadGen)
 	// generatePrefixTokens draws first — same as eager's TV path (generator.go),
 	// which calls it inside generateTimeVaryingRequests before the per-client loop.
@@ -1134,6 +1151,7 @@ func generateTimeVaryingWorkloadLazy(
 			idx:           i,
 			client:        client,
 			clientSeed:    clientSeed,
+			randSource:    spec.RandSource,
 			prefix:        prefixes[client.PrefixGroup],
 			isTimeVarying: true,
 			allClients:    allClients,
@@ -1141,7 +1159,7 @@ func generateTimeVaryingWorkloadLazy(
 		})
 	}
 
-	return assembleLazySourceFromPreps(preps, prefixes, allClients, spec.Seed, horizon, maxRequests)
+	return assembleLazySourceFromPreps(preps, prefixes, allClients, spec.Seed, spec.RandSource, horizon, maxRequests)
 }
 
 // exhaustedSentinelState is a single shared always-exhausted clientStreamState
@@ -1176,13 +1194,14 @@ func appendConcurrencySeedsToHeap(
 	allClients []ClientSpec,
 	prefixes map[string][]sim.TokenID,
 	specSeed int64,
+	randSource string,
 	horizon int64,
 	maxRequests int64,
 	keptOpen int64,
 	sessions []SessionBlueprint,
 ) ([]SessionBlueprint, int64, error) {
 	seeds, blueprints, totalUsers, err :=
-		generateConcurrencySeedsAndBlueprints(allClients, prefixes, specSeed, horizon, maxRequests, keptOpen)
+		generateConcurrencySeedsAndBlueprints(allClients, prefixes, specSeed, randSource, horizon, maxRequests, keptOpen)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1213,16 +1232,16 @@ func generateConcurrencyOnlyLazyAtZeroHorizon(spec *WorkloadSpec, horizon int64,
 	*lazyRequestSource, []SessionBlueprint, int64, error) {
 	allClients := append([]ClientSpec{}, spec.Clients...)
 	if len(spec.Cohorts) > 0 {
-		allClients = append(allClients, ExpandCohorts(spec.Cohorts, spec.Seed)...)
+		allClients = append(allClients, expandCohorts(spec.Cohorts, spec.Seed, spec.RandSource)...)
 	}
-	rng := sim.NewPartitionedRNG(sim.NewSimulationKey(spec.Seed))
+	rng := sim.NewPartitionedRNGWithSource(sim.NewSimulationKey(spec.Seed), spec.RandSource)
 	workloadRNG := rng.ForSubsystem(sim.SubsystemWorkloadGen)
 	prefixes := generatePrefixTokens(allClients, workloadRNG)
 
 	h := &heapByArrival{}
 	heap.Init(h)
 	sessions, followUpBudget, err := appendConcurrencySeedsToHeap(
-		h, allClients, prefixes, spec.Seed, horizon, maxRequests, 0, nil)
+		h, allClients, prefixes, spec.Seed, spec.RandSource, horizon, maxRequests, 0, nil)
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -1255,7 +1274,7 @@ func specHasConcurrencyClient(spec *WorkloadSpec) bool {
 // but multi-session build order != arrival order, so the per-client cap must
 // bound building exactly as eager does to keep byte-identity (#1458).
 func buildClientStreamState(p clientPrep, horizon int64, maxRequests int64) (*clientStreamState, error) {
-	clientRNG := newRandFromSeed(p.clientSeed)
+	clientRNG := newRandFromSeed(p.randSource, p.clientSeed)
 
 	// Time-varying branch (#1460): the eager generateTimeVaryingRequests draws
 	// clientSeed then passes clientRNG STRAIGHT to generateRequestsForWindow with
@@ -1288,7 +1307,7 @@ func buildClientStreamState(p clientPrep, horizon int64, maxRequests int64) (*cl
 	var arrivalSampler ArrivalSampler
 	if p.client.CustomSamplerFactory != nil {
 		subSeed := clientRNG.Int63()
-		subRNG := newRandFromSeed(subSeed)
+		subRNG := newRandFromSeed(p.randSource, subSeed)
 		arrivalSampler = p.client.CustomSamplerFactory(subRNG)
 	} else {
 		arrivalSampler = NewArrivalSampler(p.client.Arrival, p.rate)
@@ -1331,6 +1350,17 @@ func buildClientStreamState(p clientPrep, horizon int64, maxRequests int64) (*cl
 	return state, nil
 }
 
+// hasClosedLoopSessionPrep reports whether any prep is a closed-loop
+// multi-turn client, the only kind whose sessions need blueprints.
+func hasClosedLoopSessionPrep(preps []clientPrep) bool {
+	for _, p := range preps {
+		if isClosedLoop(p.client) && p.client.Reasoning != nil && p.client.Reasoning.MultiTurn != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // enumerateSurvivingSessionsPerClient simulates the streaming source's
 // global heap-pop order up to maxRequests pops and returns (1) for each
 // closed-loop reasoning client (keyed by allClients index), the set of
@@ -1372,6 +1402,13 @@ func enumerateSurvivingSessionsPerClient(
 	horizon int64,
 	maxRequests int64,
 ) (map[int][]string, int64, error) {
+	// Nothing to enumerate without closed-loop sessions, and keptOpen only
+	// feeds the concurrency seed cap, which is inactive when maxRequests <= 0.
+	// Skipping the dry run here also lets an unbounded stream (no horizon, no
+	// cap; see SimConfig.StopAfterCompleted) be built without iterating forever.
+	if maxRequests <= 0 && !hasClosedLoopSessionPrep(preps) {
+		return nil, 0, nil
+	}
 	// Clone per-client states (fresh RNGs from the same clientSeed).
 	// dryRun=true so sampler-error paths don't user-log twice (the Phase 3
 	// pass runs the same samplers and is authoritative for user feedback).
@@ -73,6 +73,9 @@ func assertRequestStreamsEqual(t *testing.T, eager, lazy []*sim.Request) {
 		if e.ClientID != l.ClientID {
 			t.Fatalf("request %d: ClientID eager=%q lazy=%q", i, e.ClientID, l.ClientID)
 		}
+		if e.ExplicitPriority != l.ExplicitPriority {
+			t.Fatalf("request %d: ExplicitPriority eager=%d lazy=%d", i, e.ExplicitPriority, l.ExplicitPriority)
+		}
 		if e.PrefixGroup != l.PrefixGroup {
 			t.Fatalf("request %d: PrefixGroup eager=%q lazy=%q", i, e.PrefixGroup, l.PrefixGroup)
 		}
@@ -1022,7 +1022,7 @@ func TestTraceV2_Adapter_RoundTrip(t *testing.T) {
 		}
 
 		// The replay reconstruction path must restore Adapter onto the request.
-		replayed, err := LoadTraceV2Requests(loaded, 42)
+		replayed, err := LoadTraceV2Requests(loaded, 42, "")
 		if err != nil {
 			t.Fatalf("LoadTraceV2Requests: %v", err)
 		}
@@ -1057,7 +1057,7 @@ func TestTraceV2_Adapter_RoundTrip(t *testing.T) {
 			t.Fatalf("LoadTraceV2: %v", err)
 		}
 
-		round0, blueprints, err := LoadTraceV2SessionBlueprints(loaded, 42, nil, 0)
+		round0, blueprints, err := LoadTraceV2SessionBlueprints(loaded, 42, "", nil, 0)
 		if err != nil {
 			t.Fatalf("LoadTraceV2SessionBlueprints: %v", err)
 		}
@@ -1358,7 +1358,7 @@ func TestLoadTraceV2Requests_IgnoresVLLMPriority_SimulationIsolation(t *testing.
 		},
 	}
 
-	requests, err := LoadTraceV2Requests(trace, 42)
+	requests, err := LoadTraceV2Requests(trace, 42, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1399,7 +1399,7 @@ func TestLoadTraceV2SessionBlueprints_IgnoresVLLMPriority_SimulationIsolation(t
 		},
 	}
 
-	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, nil, 0)
+	requests, blueprints, err := LoadTraceV2SessionBlueprints(trace, 42, "", nil, 0)
 	if err != nil {
 		t.Fatal(err)
 	}